@@ -49,4 +49,7 @@ const (
 
 	// Deploy is the string value for the deploy action when logging.
 	Deploy = "deploy"
+
+	// Connect is the string value for the connect action when logging.
+	Connect = "connect"
 )
@@ -147,6 +147,15 @@
 		}
 
 
+	Recording
+
+	A logger created with NewRecordingLogger also passes every entry to a Recorder as an Event, so that the audit trail can be stored
+	and queried.  The "user", "action", "type" and "id" fields and the message are copied to the event, and any other fields are kept in
+	its Fields map.  The master records events in the datastore and removes them once they are older than the retention period.
+
+		auditLogger = audit.NewRecordingLogger(recorder)
+
+
 	Common Patterns
 
 	Here is an example using the "SucceededIf" and "Failed" pattern to add audit logging to a method that adds resource pools.
@@ -0,0 +1,69 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// Event is a single entry in the audit trail.
+type Event struct {
+	Time     time.Time
+	User     string
+	Action   string
+	Type     string
+	EntityID string
+	Message  string
+	Success  bool
+	Fields   map[string]string `json:",omitempty"`
+}
+
+// Recorder receives every event written by an audit logger, so that the
+// audit trail can be kept somewhere more durable than the log file.
+// Implementations must not block the caller.
+type Recorder interface {
+	Record(event Event)
+}
+
+// newEvent builds an event from the fields of a log entry.
+func newEvent(data logrus.Fields, message string, success bool, now time.Time) Event {
+	event := Event{
+		Time:    now.UTC(),
+		Message: message,
+		Success: success,
+	}
+	for name, value := range data {
+		s := fmt.Sprint(value)
+		switch name {
+		case "user":
+			event.User = s
+		case "action":
+			event.Action = s
+		case "type":
+			event.Type = s
+		case "id":
+			event.EntityID = s
+		case "success":
+		default:
+			if event.Fields == nil {
+				event.Fields = make(map[string]string)
+			}
+			event.Fields[name] = s
+		}
+	}
+	return event
+}
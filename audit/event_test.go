@@ -0,0 +1,80 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	. "gopkg.in/check.v1"
+)
+
+func TestAudit(t *testing.T) { TestingT(t) }
+
+type AuditSuite struct{}
+
+var _ = Suite(&AuditSuite{})
+
+type testRecorder struct {
+	events []Event
+}
+
+func (r *testRecorder) Record(event Event) {
+	r.events = append(r.events, event)
+}
+
+func (s *AuditSuite) TestNewEvent(c *C) {
+	now := time.Date(2019, 3, 5, 10, 0, 0, 0, time.FixedZone("EST", -5*3600))
+	data := logrus.Fields{
+		"user":        "admin",
+		"action":      Stop,
+		"type":        "service",
+		"id":          "svc1",
+		"success":     "false",
+		"servicename": "Zope",
+	}
+	event := newEvent(data, "Stop Service", false, now)
+	c.Assert(event, DeepEquals, Event{
+		Time:     now.UTC(),
+		User:     "admin",
+		Action:   Stop,
+		Type:     "service",
+		EntityID: "svc1",
+		Message:  "Stop Service",
+		Success:  false,
+		Fields:   map[string]string{"servicename": "Zope"},
+	})
+}
+
+func (s *AuditSuite) TestRecordingLogger(c *C) {
+	r := &testRecorder{}
+	l := NewRecordingLogger(r)
+	alog := l.Action(Add).UserMessage("host1", "Adding Pool").Type("resourcepool").ID("pool1")
+	alog.Succeeded()
+	alog.WithField("reason", "exists").Failed()
+
+	c.Assert(r.events, HasLen, 2)
+	c.Check(r.events[0].User, Equals, "host1")
+	c.Check(r.events[0].Action, Equals, Add)
+	c.Check(r.events[0].Type, Equals, "resourcepool")
+	c.Check(r.events[0].EntityID, Equals, "pool1")
+	c.Check(r.events[0].Message, Equals, "Adding Pool")
+	c.Check(r.events[0].Success, Equals, true)
+	c.Check(r.events[0].Fields, IsNil)
+	c.Check(r.events[1].Success, Equals, false)
+	c.Check(r.events[1].Fields, DeepEquals, map[string]string{"reason": "exists"})
+}
@@ -15,6 +15,7 @@ package audit
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/datastore"
@@ -34,6 +35,10 @@ type Logger interface {
 	// Set the message that we are writing to the audit log.
 	Message(ctx datastore.Context, message string) Logger
 
+	// Set the message that we are writing to the audit log on behalf of a
+	// user that does not come from a datastore context, such as a host.
+	UserMessage(user string, message string) Logger
+
 	// Set the type of entity being modified.
 	Type(theType string) Logger
 
@@ -70,10 +75,18 @@ func NewLogger() Logger {
 	return &logger{loggeri: l}
 }
 
+// NewRecordingLogger returns an audit logger that writes to the log like
+// NewLogger, and also passes every event to the recorder.
+func NewRecordingLogger(recorder Recorder) Logger {
+	l := logri.GetLogger("audit")
+	return &logger{loggeri: l, recorder: recorder}
+}
+
 type logger struct {
-	entry   *logrus.Entry
-	message string
-	loggeri *logri.Logger
+	entry    *logrus.Entry
+	message  string
+	loggeri  *logri.Logger
+	recorder Recorder
 }
 
 func (l *logger) Action(action string) Logger {
@@ -86,6 +99,12 @@ func (l *logger) Message(ctx datastore.Context, message string) Logger {
 	return result
 }
 
+func (l *logger) UserMessage(user string, message string) Logger {
+	result := l.newLoggerWith("user", user)
+	result.message = message
+	return result
+}
+
 func (l *logger) Type(theType string) Logger {
 	return l.newLoggerWith("type", theType)
 }
@@ -111,9 +130,10 @@ func (l *logger) newLoggerWith(name string, value string) *logger {
 
 func (l *logger) newLoggerWithFields(fields logrus.Fields) *logger {
 	result := &logger{
-		entry:   l.entry,
-		message: l.message,
-		loggeri: l.loggeri,
+		entry:    l.entry,
+		message:  l.message,
+		loggeri:  l.loggeri,
+		recorder: l.recorder,
 	}
 	result.addFields(fields)
 	return result
@@ -166,4 +186,7 @@ func (l *logger) log(success bool) {
 	} else {
 		entry.Warn(l.message)
 	}
+	if l.recorder != nil {
+		l.recorder.Record(newEvent(entry.Data, l.message, success, time.Now()))
+	}
 }
@@ -0,0 +1,392 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/utils"
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+var (
+	// ErrOIDCDiscovery is thrown when the provider's discovery document cannot be used
+	ErrOIDCDiscovery = errors.New("unable to discover the OIDC provider configuration")
+	// ErrOIDCTokenBadIssuer is thrown when the issuer claim of an OIDC token does not match the provider
+	ErrOIDCTokenBadIssuer = errors.New("OIDC token issuer does not match the provider")
+	// ErrOIDCTokenBadAudience is thrown when an OIDC token was not issued for this client
+	ErrOIDCTokenBadAudience = errors.New("OIDC token audience does not match the client")
+	// ErrOIDCTokenBadNonce is thrown when the nonce of an OIDC ID token does not match the login request
+	ErrOIDCTokenBadNonce = errors.New("OIDC token nonce does not match the login request")
+	// ErrOIDCTokenExpired is thrown when an OIDC token is expired or has no expiration
+	ErrOIDCTokenExpired = errors.New("OIDC token expired")
+	// ErrOIDCUnknownKey is thrown when an OIDC token is signed by a key the provider does not publish
+	ErrOIDCUnknownKey = errors.New("OIDC token signing key is unknown")
+)
+
+// oidcKeyRefreshInterval limits how often the provider's keys are fetched
+// when a token names a key we do not know
+const oidcKeyRefreshInterval = time.Minute
+
+// OIDCConfig describes the OpenID Connect identity provider and this
+// client's registration with it
+type OIDCConfig struct {
+	Issuer        string   // Issuer URL; the discovery document is read from <Issuer>/.well-known/openid-configuration
+	ClientID      string   // Client ID registered with the provider
+	ClientSecret  string   // Client secret registered with the provider
+	Scopes        []string // Scopes requested at login; openid is always requested
+	Audience      string   // Audience required of bearer tokens; defaults to the client ID
+	UsernameClaim string   // Claim holding the user name; defaults to preferred_username, then sub
+	GroupsClaim   string   // Claim holding the user's groups; defaults to groups
+}
+
+// OIDCIdentity is the verified identity carried by an OIDC token
+type OIDCIdentity struct {
+	Subject   string
+	User      string
+	Groups    []string
+	ExpiresAt int64
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcJWKS struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+// OIDCProvider performs the authorization code flow against an OpenID
+// Connect provider and verifies the tokens it issues
+type OIDCProvider struct {
+	config      OIDCConfig
+	client      *http.Client
+	mu          sync.Mutex
+	discovery   *oidcDiscovery
+	keys        map[string]*rsa.PublicKey
+	keysFetched time.Time
+}
+
+// NewOIDCProvider creates a provider; the discovery document and keys are
+// fetched when first needed
+func NewOIDCProvider(cfg OIDCConfig, client *http.Client) *OIDCProvider {
+	cfg.Issuer = strings.TrimRight(cfg.Issuer, "/")
+	if cfg.Audience == "" {
+		cfg.Audience = cfg.ClientID
+	}
+	if cfg.UsernameClaim == "" {
+		cfg.UsernameClaim = "preferred_username"
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+	if !utils.StringInSlice("openid", cfg.Scopes) {
+		cfg.Scopes = append([]string{"openid"}, cfg.Scopes...)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &OIDCProvider{config: cfg, client: client}
+}
+
+// getJSON decodes the JSON document at the url
+func (p *OIDCProvider) getJSON(u string, v interface{}) error {
+	resp, err := p.client.Get(u)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", u, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// discover returns the provider's endpoints, fetching them on first use
+func (p *OIDCProvider) discover() (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+	doc := &oidcDiscovery{}
+	if err := p.getJSON(p.config.Issuer+"/.well-known/openid-configuration", doc); err != nil {
+		log.WithError(err).WithField("issuer", p.config.Issuer).Warn("Could not read OIDC discovery document")
+		return nil, ErrOIDCDiscovery
+	}
+	if strings.TrimRight(doc.Issuer, "/") != p.config.Issuer || doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		log.WithField("issuer", p.config.Issuer).Warn("OIDC discovery document is incomplete or names another issuer")
+		return nil, ErrOIDCDiscovery
+	}
+	p.discovery = doc
+	return doc, nil
+}
+
+// AuthCodeURL returns the url of the provider's login page
+func (p *OIDCProvider) AuthCodeURL(state, nonce, redirectURI string) (string, error) {
+	doc, err := p.discover()
+	if err != nil {
+		return "", err
+	}
+	params := url.Values{
+		"response_type": {"code"},
+		"client_id":     {p.config.ClientID},
+		"redirect_uri":  {redirectURI},
+		"scope":         {strings.Join(p.config.Scopes, " ")},
+		"state":         {state},
+		"nonce":         {nonce},
+	}
+	sep := "?"
+	if strings.Contains(doc.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return doc.AuthorizationEndpoint + sep + params.Encode(), nil
+}
+
+// Exchange redeems an authorization code and returns the verified identity
+// of the ID token
+func (p *OIDCProvider) Exchange(code, redirectURI, nonce string) (*OIDCIdentity, error) {
+	doc, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {redirectURI},
+	}
+	req, err := http.NewRequest("POST", doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var result struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("could not decode the OIDC token response: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK || result.Error != "" {
+		return nil, fmt.Errorf("OIDC token request failed: %s %s", result.Error, result.ErrorDescription)
+	}
+	if result.IDToken == "" {
+		return nil, errors.New("OIDC token response has no ID token")
+	}
+	return p.verify(result.IDToken, p.config.ClientID, nonce)
+}
+
+// VerifyToken verifies a bearer token issued by the provider for this
+// client's audience
+func (p *OIDCProvider) VerifyToken(token string) (*OIDCIdentity, error) {
+	return p.verify(token, p.config.Audience, "")
+}
+
+// IssuedToken returns true if the token claims to have been issued by the
+// provider.  The token is not verified.
+func (p *OIDCProvider) IssuedToken(token string) bool {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	data, err := jwt.DecodeSegment(parts[1])
+	if err != nil {
+		return false
+	}
+	var claims struct {
+		Issuer string `json:"iss"`
+	}
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return false
+	}
+	return strings.TrimRight(claims.Issuer, "/") == p.config.Issuer
+}
+
+func (p *OIDCProvider) verify(token, audience, nonce string) (*OIDCIdentity, error) {
+	doc, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+	parser := &jwt.Parser{ValidMethods: []string{"RS256", "RS384", "RS512"}}
+	claims := jwt.MapClaims{}
+	if _, err := parser.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(doc, kid)
+	}); err != nil {
+		if verr, ok := err.(*jwt.ValidationError); ok {
+			if verr.Errors&jwt.ValidationErrorExpired != 0 {
+				return nil, ErrOIDCTokenExpired
+			}
+			if verr.Inner != nil {
+				return nil, verr.Inner
+			}
+		}
+		return nil, err
+	}
+
+	if iss, _ := claims["iss"].(string); iss != doc.Issuer {
+		return nil, ErrOIDCTokenBadIssuer
+	}
+	if !utils.StringInSlice(audience, claimStrings(claims["aud"])) {
+		return nil, ErrOIDCTokenBadAudience
+	}
+	if !claims.VerifyExpiresAt(jwt.TimeFunc().Unix(), true) {
+		return nil, ErrOIDCTokenExpired
+	}
+	if nonce != "" {
+		if value, _ := claims["nonce"].(string); value != nonce {
+			return nil, ErrOIDCTokenBadNonce
+		}
+	}
+
+	identity := &OIDCIdentity{Groups: claimStrings(claims[p.config.GroupsClaim])}
+	identity.Subject, _ = claims["sub"].(string)
+	if identity.User, _ = claims[p.config.UsernameClaim].(string); identity.User == "" {
+		identity.User = identity.Subject
+	}
+	if exp, ok := claims["exp"].(float64); ok {
+		identity.ExpiresAt = int64(exp)
+	}
+	return identity, nil
+}
+
+// key returns the provider's signing key with the id, fetching the key set
+// again if the key is unknown
+func (p *OIDCProvider) key(doc *oidcDiscovery, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key := p.findKey(kid); key != nil {
+		return key, nil
+	}
+	if p.keys != nil && time.Since(p.keysFetched) < oidcKeyRefreshInterval {
+		return nil, ErrOIDCUnknownKey
+	}
+	jwks := oidcJWKS{}
+	if err := p.getJSON(doc.JWKSURI, &jwks); err != nil {
+		log.WithError(err).WithField("jwksuri", doc.JWKSURI).Warn("Could not read OIDC signing keys")
+		return nil, ErrOIDCUnknownKey
+	}
+	p.keys = make(map[string]*rsa.PublicKey)
+	p.keysFetched = time.Now()
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(k.N, "="))
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(k.E, "="))
+		if err != nil {
+			continue
+		}
+		p.keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if key := p.findKey(kid); key != nil {
+		return key, nil
+	}
+	return nil, ErrOIDCUnknownKey
+}
+
+// findKey looks up a key by id; a token without a key id may use the
+// provider's only key
+func (p *OIDCProvider) findKey(kid string) *rsa.PublicKey {
+	if key, ok := p.keys[kid]; ok {
+		return key
+	}
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key
+		}
+	}
+	return nil
+}
+
+// claimStrings reads a claim that may be a string or a list of strings
+func claimStrings(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		result, _ := utils.InterfaceArrayToStringArray(value)
+		return result
+	}
+	return nil
+}
+
+var (
+	oidcMu       sync.Mutex
+	oidcConfig   OIDCConfig
+	oidcProvider *OIDCProvider
+)
+
+// OIDCIsConfigured returns true if serviced is configured to log in users
+// with an OpenID Connect provider
+func OIDCIsConfigured() bool {
+	opts := config.GetOptions()
+	return len(opts.OIDCIssuer) > 0 && len(opts.OIDCClientID) > 0
+}
+
+// GetOIDCProvider returns the provider described by the serviced options
+func GetOIDCProvider() *OIDCProvider {
+	opts := config.GetOptions()
+	cfg := OIDCConfig{
+		Issuer:        opts.OIDCIssuer,
+		ClientID:      opts.OIDCClientID,
+		ClientSecret:  opts.OIDCClientSecret,
+		Scopes:        opts.OIDCScopes,
+		Audience:      opts.OIDCAudience,
+		UsernameClaim: opts.OIDCUsernameClaim,
+		GroupsClaim:   opts.OIDCGroupsClaim,
+	}
+	oidcMu.Lock()
+	defer oidcMu.Unlock()
+	if oidcProvider == nil || !reflect.DeepEqual(cfg, oidcConfig) {
+		oidcConfig = cfg
+		oidcProvider = NewOIDCProvider(cfg, nil)
+	}
+	return oidcProvider
+}
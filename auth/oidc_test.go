@@ -0,0 +1,217 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"github.com/control-center/serviced/auth"
+	jwt "github.com/dgrijalva/jwt-go"
+	. "gopkg.in/check.v1"
+)
+
+// TestOIDCSuite runs the OIDC client against a stub identity provider
+type TestOIDCSuite struct {
+	key      *rsa.PrivateKey
+	server   *httptest.Server
+	idToken  string
+	form     url.Values
+	provider *auth.OIDCProvider
+}
+
+var _ = Suite(&TestOIDCSuite{})
+
+func (s *TestOIDCSuite) SetUpSuite(c *C) {
+	var err error
+	s.key, err = rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(err, IsNil)
+}
+
+func (s *TestOIDCSuite) SetUpTest(c *C) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 s.server.URL,
+			"authorization_endpoint": s.server.URL + "/authorize",
+			"token_endpoint":         s.server.URL + "/token",
+			"jwks_uri":               s.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key1",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if id, secret, ok := r.BasicAuth(); !ok || id != "serviced" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+		r.ParseForm()
+		s.form = r.PostForm
+		json.NewEncoder(w).Encode(map[string]string{"id_token": s.idToken})
+	})
+	s.server = httptest.NewServer(mux)
+	s.form = nil
+	s.provider = auth.NewOIDCProvider(auth.OIDCConfig{
+		Issuer:       s.server.URL + "/",
+		ClientID:     "serviced",
+		ClientSecret: "secret",
+		Scopes:       []string{"profile", "email"},
+	}, nil)
+}
+
+func (s *TestOIDCSuite) TearDownTest(c *C) {
+	s.server.Close()
+}
+
+func (s *TestOIDCSuite) claims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":                s.server.URL,
+		"aud":                "serviced",
+		"sub":                "1234",
+		"preferred_username": "jdoe",
+		"groups":             []string{"ops", "dev"},
+		"nonce":              "nonce1",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func (s *TestOIDCSuite) sign(c *C, claims jwt.MapClaims, kid string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(s.key)
+	c.Assert(err, IsNil)
+	return signed
+}
+
+func (s *TestOIDCSuite) TestOIDCAuthCodeURL(c *C) {
+	loginURL, err := s.provider.AuthCodeURL("state1", "nonce1", "https://cc/auth/oidc/callback")
+	c.Assert(err, IsNil)
+	u, err := url.Parse(loginURL)
+	c.Assert(err, IsNil)
+	c.Assert(u.Path, Equals, "/authorize")
+	q := u.Query()
+	c.Assert(q.Get("response_type"), Equals, "code")
+	c.Assert(q.Get("client_id"), Equals, "serviced")
+	c.Assert(q.Get("redirect_uri"), Equals, "https://cc/auth/oidc/callback")
+	c.Assert(q.Get("scope"), Equals, "openid profile email")
+	c.Assert(q.Get("state"), Equals, "state1")
+	c.Assert(q.Get("nonce"), Equals, "nonce1")
+}
+
+func (s *TestOIDCSuite) TestOIDCExchange(c *C) {
+	s.idToken = s.sign(c, s.claims(), "key1")
+	identity, err := s.provider.Exchange("code1", "https://cc/auth/oidc/callback", "nonce1")
+	c.Assert(err, IsNil)
+	c.Assert(identity.Subject, Equals, "1234")
+	c.Assert(identity.User, Equals, "jdoe")
+	c.Assert(identity.Groups, DeepEquals, []string{"ops", "dev"})
+	c.Assert(s.form.Get("grant_type"), Equals, "authorization_code")
+	c.Assert(s.form.Get("code"), Equals, "code1")
+	c.Assert(s.form.Get("redirect_uri"), Equals, "https://cc/auth/oidc/callback")
+}
+
+func (s *TestOIDCSuite) TestOIDCExchangeBadNonce(c *C) {
+	s.idToken = s.sign(c, s.claims(), "key1")
+	_, err := s.provider.Exchange("code1", "https://cc/auth/oidc/callback", "nonce2")
+	c.Assert(err, Equals, auth.ErrOIDCTokenBadNonce)
+}
+
+func (s *TestOIDCSuite) TestOIDCExchangeBadClient(c *C) {
+	provider := auth.NewOIDCProvider(auth.OIDCConfig{
+		Issuer:       s.server.URL,
+		ClientID:     "serviced",
+		ClientSecret: "wrong",
+	}, nil)
+	_, err := provider.Exchange("code1", "https://cc/auth/oidc/callback", "nonce1")
+	c.Assert(err, ErrorMatches, "OIDC token request failed: invalid_client.*")
+}
+
+func (s *TestOIDCSuite) TestOIDCVerifyToken(c *C) {
+	claims := s.claims()
+	claims["aud"] = []string{"other", "serviced"}
+	delete(claims, "preferred_username")
+	identity, err := s.provider.VerifyToken(s.sign(c, claims, "key1"))
+	c.Assert(err, IsNil)
+	c.Assert(identity.User, Equals, "1234")
+	c.Assert(identity.Groups, DeepEquals, []string{"ops", "dev"})
+}
+
+func (s *TestOIDCSuite) TestOIDCVerifyTokenBadAudience(c *C) {
+	claims := s.claims()
+	claims["aud"] = "other"
+	_, err := s.provider.VerifyToken(s.sign(c, claims, "key1"))
+	c.Assert(err, Equals, auth.ErrOIDCTokenBadAudience)
+}
+
+func (s *TestOIDCSuite) TestOIDCVerifyTokenBadIssuer(c *C) {
+	claims := s.claims()
+	claims["iss"] = "https://elsewhere"
+	_, err := s.provider.VerifyToken(s.sign(c, claims, "key1"))
+	c.Assert(err, Equals, auth.ErrOIDCTokenBadIssuer)
+}
+
+func (s *TestOIDCSuite) TestOIDCVerifyTokenExpired(c *C) {
+	claims := s.claims()
+	claims["exp"] = time.Now().Add(-time.Minute).Unix()
+	_, err := s.provider.VerifyToken(s.sign(c, claims, "key1"))
+	c.Assert(err, Equals, auth.ErrOIDCTokenExpired)
+
+	delete(claims, "exp")
+	_, err = s.provider.VerifyToken(s.sign(c, claims, "key1"))
+	c.Assert(err, Equals, auth.ErrOIDCTokenExpired)
+}
+
+func (s *TestOIDCSuite) TestOIDCVerifyTokenUnknownKey(c *C) {
+	_, err := s.provider.VerifyToken(s.sign(c, s.claims(), "key2"))
+	c.Assert(err, Equals, auth.ErrOIDCUnknownKey)
+}
+
+func (s *TestOIDCSuite) TestOIDCVerifyTokenBadSignature(c *C) {
+	other, err := rsa.GenerateKey(rand.Reader, 1024)
+	c.Assert(err, IsNil)
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, s.claims())
+	token.Header["kid"] = "key1"
+	signed, err := token.SignedString(other)
+	c.Assert(err, IsNil)
+	_, err = s.provider.VerifyToken(signed)
+	c.Assert(err, NotNil)
+}
+
+func (s *TestOIDCSuite) TestOIDCIssuedToken(c *C) {
+	c.Assert(s.provider.IssuedToken(s.sign(c, s.claims(), "key1")), Equals, true)
+
+	claims := s.claims()
+	claims["iss"] = "https://elsewhere"
+	c.Assert(s.provider.IssuedToken(s.sign(c, claims, "key1")), Equals, false)
+	c.Assert(s.provider.IssuedToken("not-a-token"), Equals, false)
+}
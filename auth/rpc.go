@@ -96,7 +96,13 @@ func (r *RPCHeaderHandler) WriteHeader(w io.Writer, req []byte, writeAuth bool)
 	)
 	binary.Write(w, byteOrder, RPCMagicNumber)
 	if writeAuth {
-		binary.Write(w, byteOrder, uint8(1))
+		// send the user token, if any, inside the signed payload
+		if ut := UserToken(); ut != "" {
+			binary.Write(w, byteOrder, uint8(2))
+			req = userPayload(ut, req)
+		} else {
+			binary.Write(w, byteOrder, uint8(1))
+		}
 		// get current host token
 		var signer Signer = &delegateKeys
 		token, err = AuthTokenNonBlocking()
@@ -145,6 +151,24 @@ func (r *RPCHeaderHandler) ReadHeader(reader io.Reader) (Identity, []byte, error
 		if err != nil {
 			return nil, nil, err
 		}
+	} else if hasAuth[0] == 2 {
+		// the payload carries a user token ahead of the request
+		var token string
+		sender, _, payload, err = ReadAuthHeader(reader)
+		if err != nil {
+			if e, ok := err.(*AuthHeaderError); ok {
+				if _, req, serr := splitUserPayload(e.Payload); serr == nil {
+					e.Payload = req
+				}
+			}
+			return nil, nil, err
+		}
+		if token, payload, err = splitUserPayload(payload); err != nil {
+			return nil, nil, err
+		}
+		if sender, err = withUser(sender, token); err != nil {
+			return nil, nil, &AuthHeaderError{err, payload}
+		}
 	} else {
 		payload, err = ReadLengthAndBytes(reader)
 		if err != nil {
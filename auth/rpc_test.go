@@ -18,6 +18,7 @@ package auth_test
 import (
 	"bytes"
 	"encoding/binary"
+	"time"
 
 	"github.com/control-center/serviced/auth"
 	. "gopkg.in/check.v1"
@@ -70,3 +71,52 @@ func (s *TestAuthSuite) TestBadRPCMagicNumber(c *C) {
 	c.Assert(sender, IsNil)
 	c.Assert(payload, IsNil)
 }
+
+func (s *TestAuthSuite) TestAuthenticatedUser(c *C) {
+	var conn bytes.Buffer
+	request := []byte("request body")
+
+	// the test process authenticates with the master token, which has no host
+	token, _, err := auth.CreateUserToken("alice", "", time.Minute)
+	c.Assert(err, IsNil)
+	auth.SetUserToken(token)
+	defer auth.SetUserToken("")
+
+	err = rpcHeaderHandler.WriteHeader(&conn, request, true)
+	c.Assert(err, IsNil)
+
+	ident, body, err := rpcHeaderHandler.ReadHeader(&conn)
+	c.Assert(err, IsNil)
+	c.Assert(body, DeepEquals, request)
+	c.Assert(auth.UserOf(ident), Equals, "alice")
+}
+
+func (s *TestAuthSuite) TestAuthenticatedUserOtherHost(c *C) {
+	var conn bytes.Buffer
+	request := []byte("request body")
+
+	token, _, err := auth.CreateUserToken("alice", "OtherHost", time.Minute)
+	c.Assert(err, IsNil)
+	auth.SetUserToken(token)
+	defer auth.SetUserToken("")
+
+	err = rpcHeaderHandler.WriteHeader(&conn, request, true)
+	c.Assert(err, IsNil)
+
+	ident, _, err := rpcHeaderHandler.ReadHeader(&conn)
+	c.Assert(ident, IsNil)
+	herr, ok := err.(*auth.AuthHeaderError)
+	c.Assert(ok, Equals, true)
+	c.Assert(herr.Err, Equals, auth.ErrUserTokenHost)
+	c.Assert(herr.Payload, DeepEquals, request)
+}
+
+func (s *TestAuthSuite) TestUserOfHost(c *C) {
+	var conn bytes.Buffer
+	err := rpcHeaderHandler.WriteHeader(&conn, []byte("request body"), true)
+	c.Assert(err, IsNil)
+
+	ident, _, err := rpcHeaderHandler.ReadHeader(&conn)
+	c.Assert(err, IsNil)
+	c.Assert(auth.UserOf(ident), Equals, "")
+}
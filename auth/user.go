@@ -0,0 +1,176 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+var (
+	// ErrUserTokenHost is thrown when a user token is presented by a host
+	// other than the one it was issued to
+	ErrUserTokenHost = errors.New("User token was not issued to this host")
+
+	userTokenLock sync.RWMutex
+	userToken     string
+)
+
+// userClaims are the claims of a token issued to a user who has logged in
+// to the master from a particular host.
+type userClaims struct {
+	User      string `json:"usr,omitempty"`
+	Host      string `json:"hid,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+}
+
+func (c *userClaims) Valid() error {
+	now := jwt.TimeFunc().UTC().Unix()
+	if now >= (c.ExpiresAt + int64(ClockDriftDelta.Seconds())) {
+		return ErrIdentityTokenExpired
+	}
+	if now < (c.IssuedAt - int64(ClockDriftDelta.Seconds())) {
+		return ErrIdentityTokenNotValidYet
+	}
+	if c.User == "" {
+		return ErrInvalidIdentityTokenClaims
+	}
+	return nil
+}
+
+// CreateUserToken returns a token, signed by the master, that identifies a
+// user logged in from the given host.
+func CreateUserToken(userName, hostID string, expiration time.Duration) (string, int64, error) {
+	now := jwt.TimeFunc().UTC()
+	claims := &userClaims{
+		User:      userName,
+		Host:      hostID,
+		ExpiresAt: now.Add(expiration).Unix(),
+		IssuedAt:  now.Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodPS256, claims)
+	masterPrivKey, err := getMasterPrivateKey()
+	if err != nil {
+		return "", 0, err
+	}
+	signed, err := token.SignedString(masterPrivKey)
+	return signed, claims.ExpiresAt, err
+}
+
+// ParseUserToken verifies that a user token was signed by the master and
+// returns the user and host it was issued to.
+func ParseUserToken(token string) (userName, hostID string, err error) {
+	claims := &userClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSAPSS); !ok {
+			return nil, ErrInvalidSigningMethod
+		}
+		return GetMasterPublicKey()
+	})
+	if err != nil {
+		if verr, ok := err.(*jwt.ValidationError); ok {
+			if verr.Errors&(jwt.ValidationErrorSignatureInvalid|jwt.ValidationErrorUnverifiable) != 0 {
+				return "", "", ErrIdentityTokenBadSig
+			}
+			if verr.Inner != nil {
+				return "", "", verr.Inner
+			}
+		}
+		return "", "", err
+	}
+	if !parsed.Valid {
+		return "", "", ErrIdentityTokenInvalid
+	}
+	return claims.User, claims.Host, nil
+}
+
+// SetUserToken sets the user token that is sent along with authenticated
+// RPC requests from this process.  An empty token sends requests as the
+// host alone.
+func SetUserToken(token string) {
+	userTokenLock.Lock()
+	defer userTokenLock.Unlock()
+	userToken = token
+}
+
+// UserToken returns the user token that is sent along with authenticated RPC
+// requests from this process.
+func UserToken() string {
+	userTokenLock.RLock()
+	defer userTokenLock.RUnlock()
+	return userToken
+}
+
+// UserIdentity is the identity of a host acting on behalf of a logged-in
+// user.
+type UserIdentity interface {
+	Identity
+	User() string
+}
+
+// userIdentity is the UserIdentity of a host that sent a user token
+type userIdentity struct {
+	Identity
+	user string
+}
+
+func (u *userIdentity) User() string {
+	return u.user
+}
+
+// UserOf returns the name of the user on whose behalf a request was made, or
+// an empty string if the request was made by the host alone.
+func UserOf(ident Identity) string {
+	if u, ok := ident.(UserIdentity); ok {
+		return u.User()
+	}
+	return ""
+}
+
+// userPayload prefixes an RPC request with the user token so that both are
+// covered by the signature of the host.
+func userPayload(token string, req []byte) []byte {
+	var buf bytes.Buffer
+	WriteLengthAndBytes([]byte(token), &buf)
+	buf.Write(req)
+	return buf.Bytes()
+}
+
+// splitUserPayload is the inverse of userPayload.
+func splitUserPayload(payload []byte) (string, []byte, error) {
+	r := bytes.NewReader(payload)
+	token, err := ReadLengthAndBytes(r)
+	if err != nil {
+		return "", nil, ErrBadToken
+	}
+	return string(token), payload[len(payload)-r.Len():], nil
+}
+
+// withUser verifies the user token sent by a host and returns an identity
+// carrying the user.
+func withUser(sender Identity, token string) (Identity, error) {
+	userName, hostID, err := ParseUserToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if hostID != sender.HostID() {
+		return nil, ErrUserTokenHost
+	}
+	return &userIdentity{Identity: sender, user: userName}, nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/control-center/serviced/domain/alert"
+)
+
+// Returns the active alerts, or every alert if all is set
+func (a *api) GetAlerts(all bool) ([]alert.Alert, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetAlerts(all)
+}
+
+// Acknowledges an active alert.  The alert is acknowledged by the logged-in
+// user or, without one, by the user running the command.
+func (a *api) AcknowledgeAlert(alertID string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.AcknowledgeAlert(alertID, currentUserName())
+}
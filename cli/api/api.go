@@ -223,6 +223,7 @@ func (a *api) authenticateHost() error {
 	if _, err := auth.RefreshToken(getToken, tokenFile); err != nil {
 		return err
 	}
+	loadUserLogin()
 
 	hostAuthenticated = true
 	return nil
@@ -1,19 +1,27 @@
 package mocks
 
 import api "github.com/control-center/serviced/cli/api"
+import alert "github.com/control-center/serviced/domain/alert"
 import applicationendpoint "github.com/control-center/serviced/domain/applicationendpoint"
+import auditlog "github.com/control-center/serviced/domain/auditlog"
+import session "github.com/control-center/serviced/domain/session"
+import certificate "github.com/control-center/serviced/domain/certificate"
 import dao "github.com/control-center/serviced/dao"
+import dfs "github.com/control-center/serviced/dfs"
 import host "github.com/control-center/serviced/domain/host"
 import io "io"
 import isvcs "github.com/control-center/serviced/isvcs"
 import metrics "github.com/control-center/serviced/metrics"
 import mock "github.com/stretchr/testify/mock"
+import schedule "github.com/control-center/serviced/domain/schedule"
 import pool "github.com/control-center/serviced/domain/pool"
 import script "github.com/control-center/serviced/script"
 import "github.com/control-center/serviced/utils"
 import service "github.com/control-center/serviced/domain/service"
 import servicedefinition "github.com/control-center/serviced/domain/servicedefinition"
 import servicetemplate "github.com/control-center/serviced/domain/servicetemplate"
+import simulation "github.com/control-center/serviced/scheduler/simulation"
+import user "github.com/control-center/serviced/domain/user"
 import volume "github.com/control-center/serviced/volume"
 
 // API is an autogenerated mock type for the API type
@@ -21,6 +29,20 @@ type API struct {
 	mock.Mock
 }
 
+// AcknowledgeAlert provides a mock function with given fields: alertID
+func (_m *API) AcknowledgeAlert(alertID string) error {
+	ret := _m.Called(alertID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(alertID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddHost provides a mock function with given fields: _a0
 func (_m *API) AddHost(_a0 api.HostConfig) (*host.Host, []byte, error) {
 	ret := _m.Called(_a0)
@@ -221,6 +243,20 @@ func (_m *API) AddSnapshot(_a0 api.SnapshotConfig) (string, error) {
 	return r0, r1
 }
 
+// AddUser provides a mock function with given fields: name, password
+func (_m *API) AddUser(name string, password string) error {
+	ret := _m.Called(name, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(name, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddVirtualIP provides a mock function with given fields: _a0
 func (_m *API) AddVirtualIP(_a0 pool.VirtualIP) error {
 	ret := _m.Called(_a0)
@@ -249,6 +285,290 @@ func (_m *API) AssignIP(_a0 api.IPConfig) error {
 	return r0
 }
 
+// GetAlerts provides a mock function with given fields: all
+func (_m *API) GetAlerts(all bool) ([]alert.Alert, error) {
+	ret := _m.Called(all)
+
+	var r0 []alert.Alert
+	if rf, ok := ret.Get(0).(func(bool) []alert.Alert); ok {
+		r0 = rf(all)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]alert.Alert)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(bool) error); ok {
+		r1 = rf(all)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSchedules provides a mock function with given fields:
+func (_m *API) GetSchedules() ([]schedule.Schedule, error) {
+	ret := _m.Called()
+
+	var r0 []schedule.Schedule
+	if rf, ok := ret.Get(0).(func() []schedule.Schedule); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]schedule.Schedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSchedule provides a mock function with given fields: scheduleID
+func (_m *API) GetSchedule(scheduleID string) (*schedule.Schedule, error) {
+	ret := _m.Called(scheduleID)
+
+	var r0 *schedule.Schedule
+	if rf, ok := ret.Get(0).(func(string) *schedule.Schedule); ok {
+		r0 = rf(scheduleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schedule.Schedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(scheduleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddSchedule provides a mock function with given fields: s
+func (_m *API) AddSchedule(s schedule.Schedule) (string, error) {
+	ret := _m.Called(s)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(schedule.Schedule) string); ok {
+		r0 = rf(s)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(schedule.Schedule) error); ok {
+		r1 = rf(s)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateSchedule provides a mock function with given fields: s
+func (_m *API) UpdateSchedule(s schedule.Schedule) error {
+	ret := _m.Called(s)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(schedule.Schedule) error); ok {
+		r0 = rf(s)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveSchedule provides a mock function with given fields: scheduleID
+func (_m *API) RemoveSchedule(scheduleID string) error {
+	ret := _m.Called(scheduleID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(scheduleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetCertificates provides a mock function with given fields:
+func (_m *API) GetCertificates() ([]certificate.Certificate, error) {
+	ret := _m.Called()
+
+	var r0 []certificate.Certificate
+	if rf, ok := ret.Get(0).(func() []certificate.Certificate); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]certificate.Certificate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddCertificate provides a mock function with given fields: cert
+func (_m *API) AddCertificate(cert certificate.Certificate) error {
+	ret := _m.Called(cert)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(certificate.Certificate) error); ok {
+		r0 = rf(cert)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveCertificate provides a mock function with given fields: certificateID
+func (_m *API) RemoveCertificate(certificateID string) error {
+	ret := _m.Called(certificateID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(certificateID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetSessions provides a mock function with given fields: filter
+func (_m *API) GetSessions(filter session.Filter) ([]session.Session, error) {
+	ret := _m.Called(filter)
+
+	var r0 []session.Session
+	if rf, ok := ret.Get(0).(func(session.Filter) []session.Session); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]session.Session)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(session.Filter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSessionRecording provides a mock function with given fields: sessionID
+func (_m *API) GetSessionRecording(sessionID string) (*session.Session, []byte, error) {
+	ret := _m.Called(sessionID)
+
+	var r0 *session.Session
+	if rf, ok := ret.Get(0).(func(string) *session.Session); ok {
+		r0 = rf(sessionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*session.Session)
+		}
+	}
+
+	var r1 []byte
+	if rf, ok := ret.Get(1).(func(string) []byte); ok {
+		r1 = rf(sessionID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]byte)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(sessionID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetAuditEvents provides a mock function with given fields: filter
+func (_m *API) GetAuditEvents(filter auditlog.Filter) ([]auditlog.Event, error) {
+	ret := _m.Called(filter)
+
+	var r0 []auditlog.Event
+	if rf, ok := ret.Get(0).(func(auditlog.Filter) []auditlog.Event); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]auditlog.Event)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(auditlog.Filter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUsers provides a mock function with given fields:
+func (_m *API) GetUsers() ([]user.User, error) {
+	ret := _m.Called()
+
+	var r0 []user.User
+	if rf, ok := ret.Get(0).(func() []user.User); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]user.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GrantRole provides a mock function with given fields: name, binding
+func (_m *API) GrantRole(name string, binding user.RoleBinding) error {
+	ret := _m.Called(name, binding)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, user.RoleBinding) error); ok {
+		r0 = rf(name, binding)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RemoveIP provides a mock function with given fields: args
 func (_m *API) RemoveIP(args []string) error {
 	ret := _m.Called(args)
@@ -263,6 +583,34 @@ func (_m *API) RemoveIP(args []string) error {
 	return r0
 }
 
+// RemoveUser provides a mock function with given fields: name
+func (_m *API) RemoveUser(name string) error {
+	ret := _m.Called(name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeRole provides a mock function with given fields: name, binding
+func (_m *API) RevokeRole(name string, binding user.RoleBinding) error {
+	ret := _m.Called(name, binding)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, user.RoleBinding) error); ok {
+		r0 = rf(name, binding)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SetIP provides a mock function with given fields: _a0
 func (_m *API) SetIP(_a0 api.IPConfig) error {
 	ret := _m.Called(_a0)
@@ -277,13 +625,13 @@ func (_m *API) SetIP(_a0 api.IPConfig) error {
 	return r0
 }
 
-// AttachServiceInstance provides a mock function with given fields: serviceID, instanceID, command, args
-func (_m *API) AttachServiceInstance(serviceID string, instanceID int, command string, args []string) error {
-	ret := _m.Called(serviceID, instanceID, command, args)
+// AttachServiceInstance provides a mock function with given fields: serviceID, instanceID, command, args, record
+func (_m *API) AttachServiceInstance(serviceID string, instanceID int, command string, args []string, record bool) error {
+	ret := _m.Called(serviceID, instanceID, command, args, record)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, int, string, []string) error); ok {
-		r0 = rf(serviceID, instanceID, command, args)
+	if rf, ok := ret.Get(0).(func(string, int, string, []string, bool) error); ok {
+		r0 = rf(serviceID, instanceID, command, args, record)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -320,19 +668,19 @@ func (_m *API) AuthenticateHost(_a0 string) (string, int64, error) {
 }
 
 // Backup provides a mock function with given fields: _a0, _a1
-func (_m *API) Backup(_a0 string, _a1 []string, _a2 bool) (string, error) {
-	ret := _m.Called(_a0, _a1, _a2)
+func (_m *API) Backup(_a0 string, _a1 []string, _a2 bool, _a3 string) (string, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(string, []string, bool) string); ok {
-		r0 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(0).(func(string, []string, bool, string) string); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, []string, bool) error); ok {
-		r1 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(1).(func(string, []string, bool, string) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -428,6 +776,36 @@ func (_m *API) DebugEnableMetrics() (string, error) {
 	return r0, r1
 }
 
+// ExportServiceTemplate provides a mock function with given fields: _a0
+func (_m *API) ExportServiceTemplate(_a0 api.ExportTemplateConfig) (string, []string, error) {
+	ret := _m.Called(_a0)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(api.ExportTemplateConfig) string); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 []string
+	if rf, ok := ret.Get(1).(func(api.ExportTemplateConfig) []string); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]string)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(api.ExportTemplateConfig) error); ok {
+		r2 = rf(_a0)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // DeployServiceTemplate provides a mock function with given fields: _a0
 func (_m *API) DeployServiceTemplate(_a0 api.DeployTemplateConfig) ([]service.ServiceDetails, error) {
 	ret := _m.Called(_a0)
@@ -451,6 +829,52 @@ func (_m *API) DeployServiceTemplate(_a0 api.DeployTemplateConfig) ([]service.Se
 	return r0, r1
 }
 
+// TemplateDiff provides a mock function with given fields: tenantID, templateID
+func (_m *API) TemplateDiff(tenantID string, templateID string) (*servicetemplate.TemplateDiff, error) {
+	ret := _m.Called(tenantID, templateID)
+
+	var r0 *servicetemplate.TemplateDiff
+	if rf, ok := ret.Get(0).(func(string, string) *servicetemplate.TemplateDiff); ok {
+		r0 = rf(tenantID, templateID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*servicetemplate.TemplateDiff)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(tenantID, templateID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpgradeTemplate provides a mock function with given fields: tenantID, templateID
+func (_m *API) UpgradeTemplate(tenantID string, templateID string) (*servicetemplate.TemplateDiff, error) {
+	ret := _m.Called(tenantID, templateID)
+
+	var r0 *servicetemplate.TemplateDiff
+	if rf, ok := ret.Get(0).(func(string, string) *servicetemplate.TemplateDiff); ok {
+		r0 = rf(tenantID, templateID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*servicetemplate.TemplateDiff)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(tenantID, templateID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DockerOverride provides a mock function with given fields: newImage, oldImage
 func (_m *API) DockerOverride(newImage string, oldImage string) error {
 	ret := _m.Called(newImage, oldImage)
@@ -493,6 +917,34 @@ func (_m *API) EnablePublicEndpointVHost(serviceid string, endpointName string,
 	return r0
 }
 
+// SetPublicEndpointPortAccess provides a mock function with given fields: serviceid, endpointName, portAddr, access
+func (_m *API) SetPublicEndpointPortAccess(serviceid string, endpointName string, portAddr string, access *servicedefinition.AccessPolicy) error {
+	ret := _m.Called(serviceid, endpointName, portAddr, access)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, *servicedefinition.AccessPolicy) error); ok {
+		r0 = rf(serviceid, endpointName, portAddr, access)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPublicEndpointVHostAccess provides a mock function with given fields: serviceid, endpointName, vhost, access
+func (_m *API) SetPublicEndpointVHostAccess(serviceid string, endpointName string, vhost string, access *servicedefinition.AccessPolicy) error {
+	ret := _m.Called(serviceid, endpointName, vhost, access)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, *servicedefinition.AccessPolicy) error); ok {
+		r0 = rf(serviceid, endpointName, vhost, access)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ExportLogs provides a mock function with given fields: config
 func (_m *API) ExportLogs(config api.ExportLogsConfig) error {
 	ret := _m.Called(config)
@@ -760,6 +1212,52 @@ func (_m *API) GetPoolIPs(_a0 string) (*pool.PoolIPs, error) {
 	return r0, r1
 }
 
+// SimulatePool provides a mock function with given fields: poolID, changes, template
+func (_m *API) SimulatePool(poolID string, changes io.Reader, template io.Reader) (*simulation.Plan, error) {
+	ret := _m.Called(poolID, changes, template)
+
+	var r0 *simulation.Plan
+	if rf, ok := ret.Get(0).(func(string, io.Reader, io.Reader) *simulation.Plan); ok {
+		r0 = rf(poolID, changes, template)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*simulation.Plan)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, io.Reader, io.Reader) error); ok {
+		r1 = rf(poolID, changes, template)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RebalancePool provides a mock function with given fields: poolID, dryRun
+func (_m *API) RebalancePool(poolID string, dryRun bool) (*simulation.Plan, error) {
+	ret := _m.Called(poolID, dryRun)
+
+	var r0 *simulation.Plan
+	if rf, ok := ret.Get(0).(func(string, bool) *simulation.Plan); ok {
+		r0 = rf(poolID, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*simulation.Plan)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, bool) error); ok {
+		r1 = rf(poolID, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetResourcePool provides a mock function with given fields: _a0
 func (_m *API) GetResourcePool(_a0 string) (*pool.ResourcePool, error) {
 	ret := _m.Called(_a0)
@@ -1034,6 +1532,34 @@ func (_m *API) GetVolumeStatus() (*volume.Statuses, error) {
 	return r0, r1
 }
 
+// LoginUser provides a mock function with given fields: name, password
+func (_m *API) LoginUser(name string, password string) error {
+	ret := _m.Called(name, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(name, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LogoutUser provides a mock function with given fields:
+func (_m *API) LogoutUser() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // LogsForServiceInstance provides a mock function with given fields: serviceID, instanceID, command, args
 func (_m *API) LogsForServiceInstance(serviceID string, instanceID int, command string, args []string) error {
 	ret := _m.Called(serviceID, instanceID, command, args)
@@ -1048,6 +1574,27 @@ func (_m *API) LogsForServiceInstance(serviceID string, instanceID int, command
 	return r0
 }
 
+// MigrateDatastore provides a mock function with given fields: from, to
+func (_m *API) MigrateDatastore(from string, to string) (int, error) {
+	ret := _m.Called(from, to)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string, string) int); ok {
+		r0 = rf(from, to)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PostMetric provides a mock function with given fields: metricName, metricValue
 func (_m *API) PostMetric(metricName string, metricValue string) (string, error) {
 	ret := _m.Called(metricName, metricValue)
@@ -1395,6 +1942,29 @@ func (_m *API) RunShell(_a0 api.ShellConfig, _a1 chan struct{}) (int, error) {
 	return r0, r1
 }
 
+// ScriptDryRun provides a mock function with given fields: fileName, config
+func (_m *API) ScriptDryRun(fileName string, config *script.Config) (*script.Plan, error) {
+	ret := _m.Called(fileName, config)
+
+	var r0 *script.Plan
+	if rf, ok := ret.Get(0).(func(string, *script.Config) *script.Plan); ok {
+		r0 = rf(fileName, config)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*script.Plan)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *script.Config) error); ok {
+		r1 = rf(fileName, config)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ScriptParse provides a mock function with given fields: fileName, config
 func (_m *API) ScriptParse(fileName string, config *script.Config) error {
 	ret := _m.Called(fileName, config)
@@ -1474,6 +2044,20 @@ func (_m *API) SetHostMemory(_a0 api.HostUpdateConfig) error {
 	return r0
 }
 
+// SetHostLabels provides a mock function with given fields: _a0
+func (_m *API) SetHostLabels(_a0 api.HostLabelConfig) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(api.HostLabelConfig) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // StartServer provides a mock function with given fields:
 func (_m *API) StartServer() error {
 	ret := _m.Called()
@@ -1544,6 +2128,29 @@ func (_m *API) StopService(_a0 api.SchedulerConfig) (int, error) {
 	return r0, r1
 }
 
+// VerifyBackup provides a mock function with given fields: _a0
+func (_m *API) VerifyBackup(_a0 string) (*dfs.BackupVerification, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *dfs.BackupVerification
+	if rf, ok := ret.Get(0).(func(string) *dfs.BackupVerification); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dfs.BackupVerification)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // pauseService provides a mock function with given fields: _a0
 func (_m *API) PauseService(_a0 api.SchedulerConfig) (int, error) {
 	ret := _m.Called(_a0)
@@ -19,13 +19,17 @@ import (
 
 	"github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/dfs/target"
 	"errors"
 )
 
 // Dump all templates and services to a tgz file.
 // This includes a snapshot of all shared file systems
 // and exports all docker images the services depend on.
-func (a *api) Backup(dirpath string, excludes []string, force bool) (string, error) {
+// If incrementalFrom names a previous backup, only the
+// changes since that backup are exported.
+func (a *api) Backup(dirpath string, excludes []string, force bool, incrementalFrom string) (string, error) {
 	client, err := a.connectDAO()
 	if err != nil {
 		return "", err
@@ -36,6 +40,7 @@ func (a *api) Backup(dirpath string, excludes []string, force bool) (string, err
 		SnapshotSpacePercent: config.GetOptions().SnapshotSpacePercent,
 		Excludes:             excludes,
 		Force:                force,
+		IncrementalFrom:      incrementalFrom,
 	}
 
 	est := dao.BackupEstimate{}
@@ -57,14 +62,42 @@ func (a *api) Restore(path string) error {
 		return err
 	}
 
-	fp, err := filepath.Abs(path)
+	fp, err := backupFilePath(path)
 	if err != nil {
-		return fmt.Errorf("could not convert '%s' to an absolute file path: %v", path, err)
+		return err
 	}
 
-	return client.Restore(dao.RestoreRequest{Filename: filepath.Clean(fp)}, &unusedInt)
+	return client.Restore(dao.RestoreRequest{Filename: fp}, &unusedInt)
 }
 
+// Checks a backup file on the master against the checksums recorded in the
+// backup, without restoring it.
+func (a *api) VerifyBackup(path string) (*dfs.BackupVerification, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	fp, err := backupFilePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.VerifyBackup(fp)
+}
+
+// backupFilePath returns the absolute path of a backup file on the master.
+// Backups on remote targets are addressed by their url.
+func backupFilePath(path string) (string, error) {
+	if target.IsRemote(path) {
+		return path, nil
+	}
+	fp, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("could not convert '%s' to an absolute file path: %v", path, err)
+	}
+	return filepath.Clean(fp), nil
+}
 
 func (a *api) GetBackupEstimate(dirpath string, excludes []string) (*dao.BackupEstimate, error) {
 	client, err := a.connectDAO()
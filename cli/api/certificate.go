@@ -0,0 +1,48 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/control-center/serviced/domain/certificate"
+)
+
+// Returns the certificates of the virtual hosts and public ports
+func (a *api) GetCertificates() ([]certificate.Certificate, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetCertificates()
+}
+
+// Adds or replaces the certificate of a hostname or port address
+func (a *api) AddCertificate(cert certificate.Certificate) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.AddCertificate(cert)
+}
+
+// Removes a certificate
+func (a *api) RemoveCertificate(certificateID string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.RemoveCertificate(certificateID)
+}
@@ -27,25 +27,23 @@ import (
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/dao/elasticsearch"
 	"github.com/control-center/serviced/datastore"
-	"github.com/control-center/serviced/datastore/elastic"
 	"github.com/control-center/serviced/dfs"
 	"github.com/control-center/serviced/dfs/docker"
 	"github.com/control-center/serviced/dfs/nfs"
 	"github.com/control-center/serviced/dfs/registry"
-	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/dfs/target"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/properties"
 	"github.com/control-center/serviced/domain/service"
-	"github.com/control-center/serviced/domain/serviceconfigfile"
 	"github.com/control-center/serviced/domain/servicetemplate"
-	"github.com/control-center/serviced/domain/user"
 	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/isvcs"
 	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/metrics"
 	"github.com/control-center/serviced/node"
+	"github.com/control-center/serviced/notify"
 	"github.com/control-center/serviced/proxy"
 	"github.com/control-center/serviced/rpc/agent"
 	"github.com/control-center/serviced/rpc/master"
@@ -96,6 +94,14 @@ var (
 
 const (
 	localhost = "127.0.0.1"
+
+	// hostMonitorInterval is how often the master checks for hosts that
+	// have become inactive
+	hostMonitorInterval = 30 * time.Second
+
+	// auditPruneInterval is how often the master removes audit events that
+	// are older than the retention period
+	auditPruneInterval = 24 * time.Hour
 )
 
 type daemon struct {
@@ -114,13 +120,16 @@ type daemon struct {
 	rpcServer        *rpc.Server
 	tokenExpiration  time.Duration
 
-	facade *facade.Facade
-	ssm    servicestatemanager.ServiceStateManager
-	hcache *health.HealthStatusCache
-	docker docker.Docker
-	reg    *registry.RegistryListener
-	disk   volume.Driver
-	net    storage.StorageDriver
+	facade   *facade.Facade
+	notifier notify.Notifier
+	ssm      servicestatemanager.ServiceStateManager
+	hcache   *health.HealthStatusCache
+	docker   docker.Docker
+	reg      *registry.RegistryListener
+	disk     volume.Driver
+	net      storage.StorageDriver
+
+	backupKey []byte
 }
 
 func init() {
@@ -136,6 +145,7 @@ func newDaemon(servicedEndpoint string, staticIPs []string, masterPoolID string,
 		waitGroup:        &sync.WaitGroup{},
 		rpcServer:        rpc.NewServer(),
 		tokenExpiration:  tokenExpiration,
+		notifier:         notify.Discard,
 	}
 	return d, nil
 }
@@ -173,11 +183,13 @@ func (d *daemon) startISVCS() {
 	bigtable := options.BigTableMetrics
 	isvcs.Init(options.ESStartupTimeout, options.DockerLogDriver, convertStringSliceToMap(options.DockerLogConfigList), d.docker, startZK, bigtable)
 	isvcs.Mgr.SetVolumesDir(options.IsvcsPath)
-	servicedClusterName := d.getEsClusterName("elasticsearch-serviced")
-	if err := isvcs.Mgr.SetConfigurationOption("elasticsearch-serviced", "cluster", servicedClusterName); err != nil {
-		log.WithFields(logrus.Fields{
-			"clustername": servicedClusterName,
-		}).WithError(err).Fatal("Could not set Elastic configuration")
+	if options.DatastoreDriver != config.DatastoreLocal {
+		servicedClusterName := d.getEsClusterName("elasticsearch-serviced")
+		if err := isvcs.Mgr.SetConfigurationOption("elasticsearch-serviced", "cluster", servicedClusterName); err != nil {
+			log.WithFields(logrus.Fields{
+				"clustername": servicedClusterName,
+			}).WithError(err).Fatal("Could not set Elastic configuration")
+		}
 	}
 	logstashClusterName := d.getEsClusterName("elasticsearch-logstash")
 	if err := isvcs.Mgr.SetConfigurationOption("elasticsearch-logstash", "cluster", logstashClusterName); err != nil {
@@ -395,11 +407,11 @@ func (d *daemon) run() (err error) {
 }
 
 func (d *daemon) initContext() datastore.Context {
-	log.Debug("Acquiring application context from Elastic")
+	log.Debug("Acquiring application context from the datastore")
 	datastore.Register(d.dsDriver)
 	ctx := datastore.Get()
 	if ctx == nil {
-		log.Fatal("Unable to acquire application context from Elastic")
+		log.Fatal("Unable to acquire application context from the datastore")
 	}
 	return ctx
 }
@@ -1052,6 +1064,20 @@ func (d *daemon) startAgent() error {
 					log.Info("Stopping stats reporting")
 				}()
 			}
+
+			// rejected clients of the public endpoints served by the master
+			if options.Master {
+				publicEndpointStatsReporter, err := stats.NewPublicEndpointStatsReporter(statsdest, statsduration, web.GetAccessRejections)
+				if err != nil {
+					log.WithError(err).Error("Unable to start reporting public endpoint stats")
+				} else {
+					go func() {
+						defer publicEndpointStatsReporter.Close()
+						<-d.shutdown
+						log.Info("Stopping public endpoint stats reporting")
+					}()
+				}
+			}
 		}
 
 		// storage stats (thinpool, etc)
@@ -1167,6 +1193,9 @@ func (d *daemon) registerMasterRPC() error {
 	options := config.GetOptions()
 
 	server := master.NewServer(d.facade, d.tokenExpiration)
+	authorizer := master.NewAuthorizer(d.facade, d.hostID)
+	rpcutils.RegisterAuthorizer("Master", authorizer)
+	rpcutils.RegisterAuthorizer("ControlCenter", authorizer)
 	disableLocal := os.Getenv("DISABLE_RPC_BYPASS")
 	if disableLocal == "" {
 		rpcutils.RegisterLocalAddress(options.Endpoint, fmt.Sprintf("localhost:%s", options.RPCPort),
@@ -1192,25 +1221,12 @@ func (d *daemon) registerMasterRPC() error {
 }
 
 func (d *daemon) initDriver() datastore.Driver {
-	log := log.WithFields(logrus.Fields{
-		"address": "localhost:9200",
-		"index":   "controlplane",
-	})
 	options := config.GetOptions()
-	log.Debug("Establishing connection with Elastic")
-	eDriver := elastic.New("localhost", 9200, "controlplane", time.Duration(options.ESRequestTimeout))
-	eDriver.AddMapping(host.MAPPING)
-	eDriver.AddMapping(pool.MAPPING)
-	eDriver.AddMapping(servicetemplate.MAPPING)
-	eDriver.AddMapping(service.MAPPING)
-	eDriver.AddMapping(addressassignment.MAPPING)
-	eDriver.AddMapping(serviceconfigfile.MAPPING)
-	eDriver.AddMapping(user.MAPPING)
-	err := eDriver.Initialize(10 * time.Second)
+	driver, _, err := openDatastore(options.DatastoreDriver)
 	if err != nil {
-		log.WithError(err).Fatal("Unable to establish connection to Elastic database")
+		log.WithField("driver", options.DatastoreDriver).WithError(err).Fatal("Unable to establish connection to the datastore")
 	}
-	return eDriver
+	return driver
 }
 
 func initMetricsClient() *metrics.Client {
@@ -1227,19 +1243,68 @@ func initMetricsClient() *metrics.Client {
 	return client
 }
 
+func initNotifier() notify.Notifier {
+	filename := config.GetOptions().NotifyConfig
+	if filename == "" {
+		return notify.Discard
+	}
+	log := log.WithField("notifyconfig", filename)
+	cfg, err := notify.LoadConfig(filename)
+	if err != nil {
+		log.WithError(err).Fatal("Unable to load notification config")
+	}
+	dispatcher, err := notify.NewDispatcher(cfg)
+	if err != nil {
+		log.WithError(err).Fatal("Unable to configure notifications")
+	}
+	log.WithFields(logrus.Fields{
+		"sinks":  len(cfg.Sinks),
+		"routes": len(cfg.Routes),
+	}).Info("Configured notifications")
+	return dispatcher
+}
+
 func (d *daemon) initFacade() *facade.Facade {
 	options := config.GetOptions()
 	f := facade.New()
+	f.StartAuditTrail(d.dsContext, d.shutdown)
+	go d.startAuditPruner(f, 10*time.Minute, auditPruneInterval)
 	index := registry.NewRegistryIndexClient(f)
+	if options.BackupKeyFile != "" {
+		key, err := dfs.LoadBackupKey(options.BackupKeyFile)
+		if err != nil {
+			log.WithError(err).WithField("keyfile", options.BackupKeyFile).Fatal("Unable to load backup key")
+		}
+		d.backupKey = key
+	}
+	backupLocation := options.BackupTarget
+	if backupLocation == "" {
+		backupLocation = options.BackupsPath
+	}
+	backupTarget, err := target.New(backupLocation)
+	if err != nil {
+		log.WithError(err).WithField("target", backupLocation).Fatal("Unable to set up backup target")
+	}
+	f.SetBackupTarget(backupTarget)
 	dfs := dfs.NewDistributedFilesystem(d.docker, index, d.reg, d.disk, d.net, time.Duration(options.MaxDFSTimeout)*time.Second)
 	dfs.SetTmp(os.Getenv("TMP"))
+	dfs.SetBackupKey(d.backupKey)
 	f.SetDFS(dfs)
 	f.SetIsvcsPath(options.IsvcsPath)
+	f.SetSessionsPath(options.SessionsPath)
 	d.hcache = health.New()
 	d.hcache.SetPurgeFrequency(5 * time.Second)
 	f.SetHealthCache(d.hcache)
 	client := initMetricsClient()
 	f.SetMetricsClient(client)
+	if client != nil {
+		go d.startThresholdMonitor(f)
+	}
+	d.notifier = initNotifier()
+	f.SetNotifier(d.notifier)
+	if d.notifier != notify.Discard {
+		go d.startHostMonitor(f)
+	}
 	if err := f.CreateSystemUser(d.dsContext); err != nil {
 		log.WithError(err).Fatal("Unable to create system user")
 	}
@@ -1269,6 +1334,86 @@ func (d *daemon) startLogstashPurger(initialStart, cycleTime time.Duration) {
 	}
 }
 
+// startHostMonitor sends a notification whenever a host that was active is
+// no longer active.  It is started before d.facade is set, so it uses the
+// facade it is given.
+func (d *daemon) startHostMonitor(f *facade.Facade) {
+	defer log.Info("Stopped monitoring active hosts")
+	active := map[string]struct{}{}
+	for {
+		if hostIDs, err := f.GetActiveHostIDs(d.dsContext); err != nil {
+			log.WithError(err).Warn("Unable to get active hosts")
+		} else {
+			current := make(map[string]struct{}, len(hostIDs))
+			for _, hostID := range hostIDs {
+				current[hostID] = struct{}{}
+			}
+			for hostID := range active {
+				if _, ok := current[hostID]; !ok {
+					d.notifier.Notify(notify.Event{
+						Kind:    notify.KindHostInactive,
+						HostID:  hostID,
+						Summary: fmt.Sprintf("Host %s is no longer active", hostID),
+					})
+				}
+			}
+			active = current
+		}
+		select {
+		case <-d.shutdown:
+			return
+		case <-time.After(hostMonitorInterval):
+		}
+	}
+}
+
+// startThresholdMonitor periodically evaluates the monitoring profile
+// thresholds of all services and raises or resolves alerts, using the facade
+// it is given
+func (d *daemon) startThresholdMonitor(f *facade.Facade) {
+	options := config.GetOptions()
+	interval := time.Duration(options.ThresholdEvalInterval) * time.Second
+	if interval <= 0 {
+		log.Info("Threshold evaluation is disabled")
+		return
+	}
+	defer log.Info("Stopped evaluating monitoring thresholds")
+	for {
+		select {
+		case <-d.shutdown:
+			return
+		case <-time.After(interval):
+		}
+		if err := f.EvaluateThresholds(d.dsContext); err != nil {
+			log.WithError(err).Warn("Unable to evaluate monitoring thresholds")
+		}
+	}
+}
+
+// startAuditPruner periodically removes the audit events that are older than
+// the retention period
+func (d *daemon) startAuditPruner(f *facade.Facade, initialStart, cycleTime time.Duration) {
+	options := config.GetOptions()
+	retention := time.Duration(options.AuditRetentionDays) * 24 * time.Hour
+	if retention <= 0 {
+		log.Info("Audit events are kept forever")
+		return
+	}
+	defer log.Info("Stopped pruning the audit trail")
+	wait := initialStart
+	for {
+		select {
+		case <-d.shutdown:
+			return
+		case <-time.After(wait):
+		}
+		if _, err := f.PruneAuditEvents(d.dsContext, retention); err != nil {
+			log.WithError(err).Warn("Unable to prune the audit trail")
+		}
+		wait = cycleTime
+	}
+}
+
 func (d *daemon) startStorageMonitor() {
 	options := config.GetOptions()
 	defer log.Info("Stopped monitoring application storage availability")
@@ -1289,17 +1434,20 @@ func (d *daemon) startStorageMonitor() {
 			}
 			tenants := []string{}
 		CheckMetrics:
-			for k, v := range avail {
+			for k, forecast := range avail {
+				v := forecast.Value
 				log.WithFields(logrus.Fields{
 					"lookahead": lookahead,
 					"minfree":   options.StorageMinimumFreeSpace,
 					"window":    time.Duration(options.StorageMetricMonitorWindow) * time.Second,
+					"model":     forecast.Model,
 				}).Debug("Predicting future availability of storage")
 				switch k {
 				case metrics.PoolMetadataAvailableName:
 					if v < float64(minfree)*0.02 {
 						log.WithFields(logrus.Fields{
 							"prediction": v,
+							"model":      forecast.Model,
 							"minfree":    float64(minfree) * 0.02,
 							"period":     lookahead,
 						}).Error("Pool metadata volume will be exhausted within the configured period, so all running applications should be stopped")
@@ -1324,6 +1472,7 @@ func (d *daemon) startStorageMonitor() {
 					if v < float64(minfree) {
 						log.WithFields(logrus.Fields{
 							"prediction": v,
+							"model":      forecast.Model,
 							"minfree":    float64(minfree),
 							"period":     lookahead,
 						}).Error("Pool data volume will be exhausted within the configured period, so all running applications should be stopped")
@@ -1363,7 +1512,8 @@ func (d *daemon) startStorageMonitor() {
 					continue
 				}
 				log.WithFields(logrus.Fields{
-					"prediction": avail[tenant],
+					"prediction": avail[tenant].Value,
+					"model":      avail[tenant].Model,
 					"period":     lookahead,
 				}).Error("Application storage is predicted to be full within the configured period")
 				if n, err := d.facade.EmergencyStopService(d.dsContext, dao.ScheduleServiceRequest{
@@ -1374,6 +1524,12 @@ func (d *daemon) startStorageMonitor() {
 					log.WithError(err).Error("Unable to perform emergency stop of application")
 				} else {
 					log.WithField("numservices", n).Info("Emergency stop initiated")
+					d.notifier.Notify(notify.Event{
+						Kind:      notify.KindEmergencyShutdown,
+						TenantID:  tenant,
+						ServiceID: tenant,
+						Summary:   fmt.Sprintf("Application %s was stopped because its storage is predicted to be full within %s", tenant, lookahead),
+					})
 				}
 			}
 		}
@@ -1404,6 +1560,7 @@ func (d *daemon) initDAO() dao.ControlPlane {
 	if err != nil {
 		log.WithError(err).Fatal("Unable to initialize DAO layer")
 	}
+	cp.SetBackupKey(d.backupKey)
 	return cp
 }
 
@@ -1528,7 +1685,7 @@ func (d *daemon) runScheduler() {
 	options := config.GetOptions()
 	// Run the first time after 10 minutes
 	for {
-		sched, err := scheduler.NewScheduler(d.masterPoolID, d.hostID, d.storageHandler, d.cpDao, d.facade, d.reg, options.SnapshotTTL)
+		sched, err := scheduler.NewScheduler(d.masterPoolID, d.hostID, d.storageHandler, d.cpDao, d.facade, d.reg, options.SnapshotTTL, options.SnapshotSpacePercent)
 		if err != nil {
 			log.WithError(err).Fatal("Unable to start service scheduler")
 			return
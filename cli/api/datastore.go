@@ -0,0 +1,120 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
+	"github.com/control-center/serviced/datastore/local"
+	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/alert"
+	"github.com/control-center/serviced/domain/auditlog"
+	"github.com/control-center/serviced/domain/certificate"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/schedule"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/serviceconfigfile"
+	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/session"
+	"github.com/control-center/serviced/domain/user"
+)
+
+// validateDatastoreDriver verifies that the datastore driver is supported
+func validateDatastoreDriver(name string) error {
+	switch name {
+	case config.DatastoreElastic, config.DatastoreLocal:
+		return nil
+	default:
+		return fmt.Errorf("invalid datastore driver %q; must be %s or %s", name, config.DatastoreElastic, config.DatastoreLocal)
+	}
+}
+
+// openDatastore initializes the named datastore driver.  The returned
+// function releases the driver once it is no longer needed.
+func openDatastore(name string) (datastore.Driver, func() error, error) {
+	options := config.GetOptions()
+	switch name {
+	case config.DatastoreElastic:
+		log := log.WithFields(logrus.Fields{
+			"address": "localhost:9200",
+			"index":   "controlplane",
+		})
+		log.Debug("Establishing connection with Elastic")
+		eDriver := elastic.New("localhost", 9200, "controlplane", time.Duration(options.ESRequestTimeout))
+		eDriver.AddMapping(host.MAPPING)
+		eDriver.AddMapping(pool.MAPPING)
+		eDriver.AddMapping(servicetemplate.MAPPING)
+		eDriver.AddMapping(service.MAPPING)
+		eDriver.AddMapping(addressassignment.MAPPING)
+		eDriver.AddMapping(serviceconfigfile.MAPPING)
+		eDriver.AddMapping(user.MAPPING)
+		eDriver.AddMapping(alert.MAPPING)
+		eDriver.AddMapping(schedule.MAPPING)
+		eDriver.AddMapping(certificate.MAPPING)
+		eDriver.AddMapping(auditlog.MAPPING)
+		eDriver.AddMapping(session.MAPPING)
+		if err := eDriver.Initialize(10 * time.Second); err != nil {
+			return nil, nil, err
+		}
+		return eDriver, func() error { return nil }, nil
+	case config.DatastoreLocal:
+		lDriver := local.New(options.DatastorePath)
+		if err := lDriver.Initialize(); err != nil {
+			return nil, nil, err
+		}
+		return lDriver, lDriver.Close, nil
+	default:
+		return nil, nil, validateDatastoreDriver(name)
+	}
+}
+
+// MigrateDatastore copies every entity from one datastore driver to another
+// and returns the number of entities copied.
+func (a *api) MigrateDatastore(from, to string) (int, error) {
+	for _, name := range []string{from, to} {
+		if err := validateDatastoreDriver(name); err != nil {
+			return 0, err
+		}
+	}
+	if from == to {
+		return 0, fmt.Errorf("cannot migrate the %s datastore onto itself", from)
+	}
+
+	src, closeSrc, err := openDatastore(from)
+	if err != nil {
+		return 0, fmt.Errorf("could not open the %s datastore: %s", from, err)
+	}
+	defer closeSrc()
+	dst, closeDst, err := openDatastore(to)
+	if err != nil {
+		return 0, fmt.Errorf("could not open the %s datastore: %s", to, err)
+	}
+	defer closeDst()
+
+	srcConn, err := src.GetConnection()
+	if err != nil {
+		return 0, err
+	}
+	dstConn, err := dst.GetConnection()
+	if err != nil {
+		return 0, err
+	}
+	return datastore.Copy(srcConn, dstConn)
+}
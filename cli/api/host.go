@@ -39,6 +39,13 @@ type HostUpdateConfig struct {
 	Memory string
 }
 
+// HostLabelConfig describes the labels to set on and remove from a host
+type HostLabelConfig struct {
+	HostID string
+	Set    map[string]string
+	Unset  []string
+}
+
 type AuthHost struct {
 	host.Host
 	Authenticated bool
@@ -246,6 +253,28 @@ func (a *api) SetHostMemory(config HostUpdateConfig) error {
 	return client.UpdateHost(*h)
 }
 
+// SetHostLabels sets and removes labels of an existing host
+func (a *api) SetHostLabels(config HostLabelConfig) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+	h, err := client.GetHost(config.HostID)
+	if err != nil {
+		return err
+	}
+	if h.Labels == nil {
+		h.Labels = make(map[string]string)
+	}
+	for _, key := range config.Unset {
+		delete(h.Labels, key)
+	}
+	for key, value := range config.Set {
+		h.Labels[key] = value
+	}
+	return client.UpdateHost(*h)
+}
+
 func (a *api) AuthenticateHost(hostID string) (string, int64, error) {
 	client, err := a.connectMaster()
 	if err != nil {
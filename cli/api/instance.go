@@ -15,11 +15,13 @@ package api
 
 import (
 	"os"
+	"os/exec"
 	"syscall"
 
 	dockerclient "github.com/control-center/serviced/commons/docker"
 	"github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/session"
 	"github.com/control-center/serviced/utils"
 )
 
@@ -43,8 +45,10 @@ func (a *api) StopServiceInstance(serviceID string, instanceID int) error {
 	return client.StopServiceInstance(serviceID, instanceID)
 }
 
-// AttachServiceInstance locates and attaches to a running instance of a service
-func (a *api) AttachServiceInstance(serviceID string, instanceID int, command string, args []string) error {
+// AttachServiceInstance locates and attaches to a running instance of a
+// service.  The session is recorded if record is set or the tenant of the
+// service requires it.
+func (a *api) AttachServiceInstance(serviceID string, instanceID int, command string, args []string, record bool) error {
 	var (
 		targetHost      string
 		targetContainer string
@@ -73,6 +77,11 @@ func (a *api) AttachServiceInstance(serviceID string, instanceID int, command st
 		command = "/bin/bash"
 	}
 
+	recording, err := a.newSessionRecording(record, session.KindAttach, serviceID, instanceID, append([]string{command}, args...))
+	if err != nil {
+		return err
+	}
+
 	// attach to the container
 	if targetHost != hostID {
 		cmd, err := a.getSSHCommand(location)
@@ -83,10 +92,20 @@ func (a *api) AttachServiceInstance(serviceID string, instanceID int, command st
 
 		cmd = append(cmd, command)
 		cmd = append(cmd, args...)
+		if recording != nil {
+			return recording.run(exec.Command(cmd[0], cmd[1:]...))
+		}
 		return syscall.Exec(cmd[0], cmd[0:], os.Environ())
 	} else {
 		cmd := []string{command}
 		cmd = append(cmd, args...)
+		if recording != nil {
+			execCmd, err := utils.DockerExecCommand(targetContainer, cmd)
+			if err != nil {
+				return err
+			}
+			return recording.run(exec.Command(execCmd[0], execCmd[1:]...))
+		}
 		return utils.AttachAndExec(targetContainer, cmd)
 	}
 }
@@ -17,14 +17,22 @@ import (
 	"io"
 
 	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/alert"
 	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/domain/auditlog"
+	"github.com/control-center/serviced/domain/certificate"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/schedule"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	template "github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/session"
+	"github.com/control-center/serviced/domain/user"
 	"github.com/control-center/serviced/isvcs"
 	"github.com/control-center/serviced/metrics"
+	"github.com/control-center/serviced/scheduler/simulation"
 	"github.com/control-center/serviced/script"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/volume"
@@ -46,6 +54,7 @@ type API interface {
 	RemoveHost(string) error
 	GetHostMemory(string) (*metrics.MemoryUsageStats, error)
 	SetHostMemory(HostUpdateConfig) error
+	SetHostLabels(HostLabelConfig) error
 	GetHostPublicKey(string) ([]byte, error)
 	RegisterHost([]byte) error
 	RegisterRemoteHost(*host.Host, utils.URL, []byte, bool) error
@@ -62,6 +71,8 @@ type API interface {
 	RemoveResourcePool(string) error
 	UpdateResourcePool(pool pool.ResourcePool) error
 	GetPoolIPs(string) (*pool.PoolIPs, error)
+	SimulatePool(poolID string, changes io.Reader, template io.Reader) (*simulation.Plan, error)
+	RebalancePool(poolID string, dryRun bool) (*simulation.Plan, error)
 	AddVirtualIP(pool.VirtualIP) error
 	RemoveVirtualIP(pool.VirtualIP) error
 
@@ -107,12 +118,19 @@ type API interface {
 	AddServiceTemplate(io.Reader) (*template.ServiceTemplate, error)
 	RemoveServiceTemplate(string) error
 	CompileServiceTemplate(CompileTemplateConfig) (*template.ServiceTemplate, error)
+	ExportServiceTemplate(ExportTemplateConfig) (string, []string, error)
 	DeployServiceTemplate(DeployTemplateConfig) ([]service.ServiceDetails, error)
+	TemplateDiff(tenantID, templateID string) (*template.TemplateDiff, error)
+	UpgradeTemplate(tenantID, templateID string) (*template.TemplateDiff, error)
 
 	// Backup & Restore
 	GetBackupEstimate(string, []string) (*dao.BackupEstimate, error)
-	Backup(string, []string, bool) (string, error)
+	Backup(string, []string, bool, string) (string, error)
 	Restore(string) error
+	VerifyBackup(string) (*dfs.BackupVerification, error)
+
+	// Datastore
+	MigrateDatastore(from, to string) (int, error)
 
 	// Docker
 	ResetRegistry() error
@@ -129,6 +147,7 @@ type API interface {
 	// Scripts
 	ScriptRun(fileName string, config *script.Config, stopChan chan struct{}) error
 	ScriptParse(fileName string, config *script.Config) error
+	ScriptDryRun(fileName string, config *script.Config) (*script.Plan, error)
 
 	// Volumes
 	GetVolumeStatus() (*volume.Statuses, error)
@@ -140,15 +159,49 @@ type API interface {
 	AddPublicEndpointVHost(serviceid, endpointName, vhost string, isEnabled, restart bool) (*servicedefinition.VHost, error)
 	RemovePublicEndpointVHost(serviceid, endpointName, vhost string) error
 	EnablePublicEndpointVHost(serviceid, endpointName, vhost string, isEnabled bool) error
+	SetPublicEndpointPortAccess(serviceid, endpointName, portAddr string, access *servicedefinition.AccessPolicy) error
+	SetPublicEndpointVHostAccess(serviceid, endpointName, vhost string, access *servicedefinition.AccessPolicy) error
 	GetAllPublicEndpoints() ([]service.PublicEndpoint, error)
 
 	// Service Instances
 	GetServiceInstances(serviceID string) ([]service.Instance, error)
 	StopServiceInstance(serviceID string, instanceID int) error
-	AttachServiceInstance(serviceID string, instanceID int, command string, args []string) error
+	AttachServiceInstance(serviceID string, instanceID int, command string, args []string, record bool) error
 	LogsForServiceInstance(serviceID string, instanceID int, command string, args []string) error
 	SendDockerAction(serviceID string, instanceID int, action string, args []string) error
 
+	// Users
+	GetUsers() ([]user.User, error)
+	AddUser(name, password string) error
+	RemoveUser(name string) error
+	GrantRole(name string, binding user.RoleBinding) error
+	RevokeRole(name string, binding user.RoleBinding) error
+	LoginUser(name, password string) error
+	LogoutUser() error
+
+	// Alerts
+	GetAlerts(all bool) ([]alert.Alert, error)
+	AcknowledgeAlert(alertID string) error
+
+	// Schedules
+	GetSchedules() ([]schedule.Schedule, error)
+	GetSchedule(scheduleID string) (*schedule.Schedule, error)
+	AddSchedule(s schedule.Schedule) (string, error)
+	UpdateSchedule(s schedule.Schedule) error
+	RemoveSchedule(scheduleID string) error
+
+	// Certificates
+	GetCertificates() ([]certificate.Certificate, error)
+	AddCertificate(cert certificate.Certificate) error
+	RemoveCertificate(certificateID string) error
+
+	// Audit trail
+	GetAuditEvents(filter auditlog.Filter) ([]auditlog.Event, error)
+
+	// Shell sessions
+	GetSessions(filter session.Filter) ([]session.Session, error)
+	GetSessionRecording(sessionID string) (*session.Session, []byte, error)
+
 	// Debug Management
 	DebugEnableMetrics() (string, error)
 	DebugDisableMetrics() (string, error)
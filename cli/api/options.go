@@ -20,8 +20,10 @@ import (
 	"time"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/commons/statistics"
 	"github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/isvcs"
 	"github.com/control-center/serviced/node"
 	"github.com/control-center/serviced/rpc/rpcutils"
@@ -68,6 +70,14 @@ func ValidateServerOptions(options *config.Options) error {
 		return err
 	}
 
+	if err := validateDatastoreDriver(options.DatastoreDriver); err != nil {
+		return err
+	}
+
+	if _, err := facade.ParseStorageForecastModels(options.StorageForecastModel); err != nil {
+		return err
+	}
+
 	// Make sure we have an endpoint to work with
 	if len(options.Endpoint) == 0 {
 		if options.Master {
@@ -146,6 +156,7 @@ func GetDefaultOptions(cfg utils.ConfigReader) config.Options {
 		FSType:                     volume.DriverType(cfg.StringVal("FS_TYPE", "devicemapper")),
 		ESStartupTimeout:           getDefaultESStartupTimeout(cfg.IntVal("ES_STARTUP_TIMEOUT", isvcs.DEFAULT_ES_STARTUP_TIMEOUT_SECONDS)),
 		ESRequestTimeout:           cfg.IntVal("ES_REQUEST_TIMEOUT", 0),
+		DatastoreDriver:            cfg.StringVal("DATASTORE_DRIVER", config.DatastoreElastic),
 		HostAliases:                cfg.StringSlice("VHOST_ALIASES", []string{}),
 		Verbosity:                  cfg.IntVal("LOG_LEVEL", 0),
 		StaticIPs:                  cfg.StringSlice("STATIC_IPS", []string{}),
@@ -197,14 +208,32 @@ func GetDefaultOptions(cfg utils.ConfigReader) config.Options {
 		StorageMetricMonitorWindow: cfg.IntVal("STORAGE_METRIC_MONITOR_WINDOW", 300),
 		StorageLookaheadPeriod:     cfg.IntVal("STORAGE_LOOKAHEAD_PERIOD", 360),
 		StorageMinimumFreeSpace:    cfg.StringVal("STORAGE_MIN_FREE", "3G"),
+		StorageForecastModel:       cfg.StringVal("STORAGE_FORECAST_MODEL", statistics.LeastSquaresModel),
+		StorageForecastSeason:      cfg.IntVal("STORAGE_FORECAST_SEASON", 86400),
+		ThresholdEvalInterval:      cfg.IntVal("THRESHOLD_EVAL_INTERVAL", 60),
+		NotifyConfig:               cfg.StringVal("NOTIFY_CONFIG", ""),
+		AuditRetentionDays:         cfg.IntVal("AUDIT_RETENTION_DAYS", 90),
 		BackupEstimatedCompression: cfg.Float64Val("BACKUP_ESTIMATED_COMPRESSION", 1.0),
 		BackupMinOverhead:          cfg.StringVal("BACKUP_MIN_OVERHEAD", "0G"),
+		BackupKeyFile:              cfg.StringVal("BACKUP_KEY_FILE", ""),
+		BackupTarget:               cfg.StringVal("BACKUP_TARGET", ""),
 		// Auth0 configuration parameters. Default to empty strings - must edit in serviced.conf to configure for auth0.
 		Auth0Domain:   cfg.StringVal("AUTH0_DOMAIN", ""),
 		Auth0Audience: cfg.StringVal("AUTH0_AUDIENCE", ""),
 		Auth0Group:    cfg.StringSlice("AUTH0_GROUP", []string{}),
 		Auth0ClientID: cfg.StringVal("AUTH0_CLIENT_ID", ""),
 		Auth0Scope:    cfg.StringVal("AUTH0_SCOPE", ""),
+		// OpenID Connect login. Disabled unless the issuer and client id are set.
+		OIDCIssuer:        cfg.StringVal("OIDC_ISSUER", ""),
+		OIDCClientID:      cfg.StringVal("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret:  cfg.StringVal("OIDC_CLIENT_SECRET", ""),
+		OIDCScopes:        cfg.StringSlice("OIDC_SCOPES", []string{"openid", "profile", "email"}),
+		OIDCRedirectURL:   cfg.StringVal("OIDC_REDIRECT_URL", ""),
+		OIDCAudience:      cfg.StringVal("OIDC_AUDIENCE", ""),
+		OIDCUsernameClaim: cfg.StringVal("OIDC_USERNAME_CLAIM", "preferred_username"),
+		OIDCGroupsClaim:   cfg.StringVal("OIDC_GROUPS_CLAIM", "groups"),
+		OIDCAdminGroups:   cfg.StringSlice("OIDC_ADMIN_GROUPS", []string{}),
+		OIDCGroupRoles:    cfg.StringSlice("OIDC_GROUP_ROLES", []string{}),
 		// Parameters for api-key-proxy isvc configuration
 		KeyProxyJsonServer: cfg.StringVal("KEYPROXY_JSON_SERVER", ""),
 		KeyProxyListenPort: cfg.StringVal("KEYPROXY_LISTEN_PORT", ":6443"),
@@ -228,6 +257,8 @@ func GetDefaultOptions(cfg utils.ConfigReader) config.Options {
 	options.LogPath = cfg.StringVal("LOG_PATH", "/var/log/serviced")
 	options.VolumesPath = cfg.StringVal("VOLUMES_PATH", filepath.Join(varpath, "volumes"))
 	options.BackupsPath = cfg.StringVal("BACKUPS_PATH", filepath.Join(varpath, "backups"))
+	options.DatastorePath = cfg.StringVal("DATASTORE_PATH", filepath.Join(varpath, "datastore", "controlplane.db"))
+	options.SessionsPath = cfg.StringVal("SESSIONS_PATH", filepath.Join(varpath, "sessions"))
 	options.EtcPath = cfg.StringVal("ETC_PATH", filepath.Join(options.HomePath, "etc"))
 	options.StorageArgs = getDefaultStorageOptions(options.FSType, cfg)
 
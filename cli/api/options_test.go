@@ -99,6 +99,37 @@ func (s *TestAPISuite) TestValidateServerOptionsFailsIfStorageInvalid(c *C) {
 	s.assertErrorContent(c, err, "Use of devicemapper loop back device is not allowed")
 }
 
+func (s *TestAPISuite) TestValidateServerOptionsFailsIfDatastoreDriverInvalid(c *C) {
+	configReader := utils.TestConfigReader(map[string]string{})
+	testOptions := GetDefaultOptions(configReader)
+	testOptions.Master = true
+	testOptions.FSType = volume.DriverTypeBtrFS
+	testOptions.DatastoreDriver = "mongo"
+	config.LoadOptions(testOptions)
+
+	err := ValidateServerOptions(&testOptions)
+
+	s.assertErrorContent(c, err, `invalid datastore driver "mongo"`)
+}
+
+func (s *TestAPISuite) TestValidateServerOptionsFailsIfStorageForecastModelInvalid(c *C) {
+	configReader := utils.TestConfigReader(map[string]string{})
+	testOptions := GetDefaultOptions(configReader)
+	testOptions.Master = true
+	testOptions.FSType = volume.DriverTypeBtrFS
+	testOptions.StorageForecastModel = "theilsen,thinpool-data=arima"
+	config.LoadOptions(testOptions)
+
+	err := ValidateServerOptions(&testOptions)
+
+	s.assertErrorContent(c, err, `unknown storage forecast model "arima"`)
+}
+
+func (s *TestAPISuite) TestMigrateDatastoreRejectsSameDriver(c *C) {
+	_, err := New().MigrateDatastore(config.DatastoreLocal, config.DatastoreLocal)
+	s.assertErrorContent(c, err, "cannot migrate the local datastore onto itself")
+}
+
 func (s *TestAPISuite) TestValidateServerOptionsFailsIfAgentMissingEndpoint(c *C) {
 	configReader := utils.TestConfigReader(map[string]string{})
 	testOptions := GetDefaultOptions(configReader)
@@ -14,7 +14,14 @@
 package api
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/rpc/master"
+	"github.com/control-center/serviced/scheduler/simulation"
 )
 
 const ()
@@ -121,3 +128,38 @@ func (a *api) RemoveVirtualIP(requestVirtualIP pool.VirtualIP) error {
 
 	return client.RemoveVirtualIP(requestVirtualIP)
 }
+
+// SimulatePool returns where the scheduler would place instances in a pool
+// after the changes are made and the template is deployed.  Either reader may
+// be nil.
+func (a *api) SimulatePool(poolID string, changes io.Reader, template io.Reader) (*simulation.Plan, error) {
+	request := master.SimulatePoolRequest{PoolID: poolID}
+	if changes != nil {
+		if err := json.NewDecoder(changes).Decode(&request.Changes); err != nil {
+			return nil, fmt.Errorf("could not unmarshal service changes: %s", err)
+		}
+	}
+	if template != nil {
+		request.Template = &servicetemplate.ServiceTemplate{}
+		if err := json.NewDecoder(template).Decode(request.Template); err != nil {
+			return nil, fmt.Errorf("could not unmarshal template: %s", err)
+		}
+	}
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.SimulatePool(request)
+}
+
+// RebalancePool returns the instances that would be moved to rebalance a
+// pool and, unless it is a dry run, asks the pool leader to move them
+func (a *api) RebalancePool(poolID string, dryRun bool) (*simulation.Plan, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.RebalancePool(master.RebalancePoolRequest{PoolID: poolID, DryRun: dryRun})
+}
@@ -76,6 +76,26 @@ func (a *api) EnablePublicEndpointVHost(serviceid, endpointName, vhost string, i
 	return client.EnablePublicEndpointVHost(serviceid, endpointName, vhost, isEnabled)
 }
 
+// Set the access policy of a port public endpoint.
+func (a *api) SetPublicEndpointPortAccess(serviceid, endpointName, portAddr string, access *servicedefinition.AccessPolicy) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.SetPublicEndpointPortAccess(serviceid, endpointName, portAddr, access)
+}
+
+// Set the access policy of a vhost public endpoint.
+func (a *api) SetPublicEndpointVHostAccess(serviceid, endpointName, vhost string, access *servicedefinition.AccessPolicy) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.SetPublicEndpointVHostAccess(serviceid, endpointName, vhost, access)
+}
+
 func (a *api) GetAllPublicEndpoints() ([]service.PublicEndpoint, error) {
 	client, err := a.connectMaster()
 	if err != nil {
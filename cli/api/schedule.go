@@ -0,0 +1,68 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/control-center/serviced/domain/schedule"
+)
+
+// Returns the snapshot and backup schedules
+func (a *api) GetSchedules() ([]schedule.Schedule, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetSchedules()
+}
+
+// Returns a schedule by its id
+func (a *api) GetSchedule(scheduleID string) (*schedule.Schedule, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetSchedule(scheduleID)
+}
+
+// Adds a snapshot or backup schedule and returns its id
+func (a *api) AddSchedule(s schedule.Schedule) (string, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return "", err
+	}
+
+	return client.AddSchedule(s)
+}
+
+// Updates a schedule, keeping its status
+func (a *api) UpdateSchedule(s schedule.Schedule) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.UpdateSchedule(s)
+}
+
+// Removes a schedule.  The snapshots and backups it took are kept.
+func (a *api) RemoveSchedule(scheduleID string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.RemoveSchedule(scheduleID)
+}
@@ -20,6 +20,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/script"
 )
@@ -41,6 +42,13 @@ func (a *api) ScriptParse(fileName string, config *script.Config) error {
 	return err
 }
 
+// ScriptDryRun returns the changes the script would make without making them
+func (a *api) ScriptDryRun(fileName string, config *script.Config) (*script.Plan, error) {
+	initConfig(config, a)
+	config.LatestSnapshot = cliLatestSnapshot(a)
+	return script.DryRunFromFile(fileName, config)
+}
+
 func initConfig(config *script.Config, a *api) {
 	config.Snapshot = func(serviceID, message string, tag string) (string, error) {
 		return a.AddSnapshot(SnapshotConfig{ServiceID: serviceID, Message: message, Tag: tag})
@@ -56,6 +64,32 @@ func initConfig(config *script.Config, a *api) {
 		return a.AddSnapshot(SnapshotConfig{DockerID: containerID})
 	}
 	config.SvcUse = cliServiceUse(a)
+	config.SvcImage = func(serviceID string) (string, error) {
+		svc, err := a.GetService(serviceID)
+		if err != nil {
+			return "", err
+		}
+		return svc.ImageID, nil
+	}
+}
+
+func cliLatestSnapshot(a *api) script.SnapshotLookup {
+	return func(tenantID string) (string, error) {
+		snapshots, err := a.GetSnapshotsByServiceID(tenantID)
+		if err != nil {
+			return "", err
+		}
+		var latest *dao.SnapshotInfo
+		for i, snapshot := range snapshots {
+			if !snapshot.Invalid && (latest == nil || snapshot.Created.After(latest.Created)) {
+				latest = &snapshots[i]
+			}
+		}
+		if latest == nil {
+			return "", nil
+		}
+		return latest.SnapshotID, nil
+	}
 }
 
 func cliServiceUse(a *api) script.ServiceUse {
@@ -152,7 +186,7 @@ func cliServiceIDFromPath(a *api) script.ServiceIDFromPath {
 		}
 		svcID, found := pathmap[strings.ToLower(svcPath)]
 		if !found {
-			return "", fmt.Errorf("did not find service %s", svcPath)
+			return "", script.ServiceNotFoundError{Path: svcPath}
 		}
 		return svcID, nil
 	}
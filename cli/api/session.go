@@ -0,0 +1,137 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/domain/session"
+	"github.com/control-center/serviced/shell"
+	"github.com/control-center/serviced/utils"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// Returns the recorded shell sessions selected by the filter, most recently
+// started first
+func (a *api) GetSessions(filter session.Filter) ([]session.Session, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetSessions(filter)
+}
+
+// Returns a recorded shell session and its asciicast recording
+func (a *api) GetSessionRecording(sessionID string) (*session.Session, []byte, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sess, err := client.GetSession(sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	recording, err := client.GetSessionRecording(sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sess, recording, nil
+}
+
+// sessionRecording records a shell session and saves it on the master when
+// it ends
+type sessionRecording struct {
+	a       *api
+	session session.Session
+	rec     *shell.SessionRecording
+}
+
+// newSessionRecording returns a recording for a session on a service if the
+// user asked for one or the tenant of the service requires it, and nil
+// otherwise
+func (a *api) newSessionRecording(record bool, kind session.Kind, serviceID string, instanceID int, command []string) (*sessionRecording, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	required, err := client.SessionRecordingRequired(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if !record && !required {
+		return nil, nil
+	}
+	if required {
+		fmt.Fprintln(os.Stderr, "This session is being recorded.")
+	}
+
+	hostID, _ := utils.HostID()
+	return &sessionRecording{
+		a: a,
+		session: session.Session{
+			Kind:       kind,
+			User:       currentUserName(),
+			HostID:     hostID,
+			ServiceID:  serviceID,
+			InstanceID: instanceID,
+			Command:    strings.Join(command, " "),
+		},
+	}, nil
+}
+
+// start starts the command with its terminal session recorded
+func (r *sessionRecording) start(cmd *exec.Cmd) (*shell.RecordedCmd, error) {
+	width, height, err := terminal.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		width, height = 80, 24
+	}
+	if r.rec, err = shell.NewSessionRecording(r.session, width, height); err != nil {
+		return nil, err
+	}
+	return shell.StartRecorded(cmd, r.rec.Recorder)
+}
+
+// run runs the command with its terminal session recorded and saves the
+// recording
+func (r *sessionRecording) run(cmd *exec.Cmd) error {
+	c, err := r.start(cmd)
+	if err != nil {
+		return err
+	}
+	err = c.Wait()
+	r.save(err)
+	return err
+}
+
+// save saves the recording on the master, given the result of the command
+func (r *sessionRecording) save(cmdErr error) {
+	logger := log.WithFields(logrus.Fields{
+		"serviceid": r.session.ServiceID,
+	})
+	client, err := r.a.connectMaster()
+	if err == nil {
+		var sessionID string
+		if sessionID, err = r.rec.Save(client, cmdErr); err == nil {
+			logger.WithField("sessionid", sessionID).Debug("Saved session recording")
+			return
+		}
+	}
+	logger.WithError(err).Error("Unable to save session recording")
+}
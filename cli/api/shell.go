@@ -23,6 +23,7 @@ import (
 	ccconfig "github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/session"
 	"github.com/control-center/serviced/node"
 	"github.com/control-center/serviced/shell"
 	"github.com/control-center/serviced/utils"
@@ -40,6 +41,7 @@ type ShellConfig struct {
 	Mounts           []string
 	ServicedEndpoint string
 	LogToStderr      bool
+	Record           bool
 	LogStash         struct {
 		Enable        bool
 		SettleTime    string
@@ -112,6 +114,11 @@ func (a *api) StartShell(config ShellConfig) error {
 
 	command := append([]string{config.Command}, config.Args...)
 
+	recording, err := a.newSessionRecording(config.Record, session.KindShell, config.ServiceID, -1, command)
+	if err != nil {
+		return err
+	}
+
 	cfg := shell.ProcessConfig{
 		ServiceID: config.ServiceID,
 		IsTTY:     config.IsTTY,
@@ -126,6 +133,9 @@ func (a *api) StartShell(config ShellConfig) error {
 		return fmt.Errorf("failed to connect to service: %s", err)
 	}
 
+	if recording != nil {
+		return recording.run(cmd)
+	}
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -171,6 +181,11 @@ func (a *api) RunShell(config ShellConfig, stopChan chan struct{}) (int, error)
 	quotedArgs := utils.ShellQuoteArgs(config.Args)
 	command := strings.Join([]string{run.Command, quotedArgs}, " ")
 
+	recording, err := a.newSessionRecording(config.Record, session.KindRun, config.ServiceID, -1, append([]string{config.Command}, config.Args...))
+	if err != nil {
+		return 1, err
+	}
+
 	asUser := "su - root -c "
 	if config.Username != "" && config.Username != "root" {
 		asUser = fmt.Sprintf("su - %s -c ", config.Username)
@@ -197,10 +212,6 @@ func (a *api) RunShell(config ShellConfig, stopChan chan struct{}) (int, error)
 		return 1, fmt.Errorf("failed to connect to service: %s", err)
 	}
 
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
 	dockercli, err := a.connectDocker()
 	if err != nil {
 		log.WithError(err).Fatal("Unable to connect to Docker")
@@ -210,13 +221,28 @@ func (a *api) RunShell(config ShellConfig, stopChan chan struct{}) (int, error)
 		"containername": cfg.SaveAs,
 	})
 
-	err = cmd.Start()
+	wait := cmd.Wait
+	if recording != nil {
+		var c *shell.RecordedCmd
+		if c, err = recording.start(cmd); err == nil {
+			wait = func() error {
+				err := c.Wait()
+				recording.save(err)
+				return err
+			}
+		}
+	} else {
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		err = cmd.Start()
+	}
 	if err != nil {
 		log.WithError(err).Fatal("Unable to start container")
 	}
-	cmdChan := make(chan error)
+	cmdChan := make(chan error, 1)
 	go func() {
-		cmdChan <- cmd.Wait()
+		cmdChan <- wait()
 	}()
 	log.WithFields(logrus.Fields{
 		"command": cmd,
@@ -231,6 +257,13 @@ func (a *api) RunShell(config ShellConfig, stopChan chan struct{}) (int, error)
 			log.WithError(err).Error("Unable to kill container")
 		}
 		log.Info("Killed container")
+		if recording != nil {
+			// give the recording a chance to be saved
+			select {
+			case <-cmdChan:
+			case <-time.After(10 * time.Second):
+			}
+		}
 		return 1, err
 	case err = <-cmdChan:
 		if _, ok := utils.GetExitStatus(err); !ok {
@@ -17,10 +17,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 
+	"github.com/control-center/serviced/config"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	template "github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/rpc/master"
 )
 
 // DeployTemplateConfig is the configuration object to deploy a template
@@ -37,6 +41,14 @@ type CompileTemplateConfig struct {
 	Map ImageMap
 }
 
+// ExportTemplateConfig is the configuration object to export an application
+// as a template directory
+type ExportTemplateConfig struct {
+	TenantID string
+	Dir      string // directory to write the application's template directory into
+	Map      ImageMap
+}
+
 // Gets all available service templates
 func (a *api) GetServiceTemplates() ([]template.ServiceTemplate, error) {
 	client, err := a.connectMaster()
@@ -168,3 +180,125 @@ func (a *api) DeployServiceTemplate(config DeployTemplateConfig) ([]service.Serv
 
 	return svcs, nil
 }
+
+// TemplateDiff returns the changes upgrading a deployed application to a
+// template would make
+func (a *api) TemplateDiff(tenantID, templateID string) (*template.TemplateDiff, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.TemplateDiff(master.TemplateUpgradeRequest{TenantID: tenantID, TemplateID: templateID})
+}
+
+// UpgradeTemplate upgrades a deployed application to a template
+func (a *api) UpgradeTemplate(tenantID, templateID string) (*template.TemplateDiff, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+	return client.UpgradeTemplate(master.TemplateUpgradeRequest{
+		TenantID:             tenantID,
+		TemplateID:           templateID,
+		SnapshotSpacePercent: config.GetOptions().SnapshotSpacePercent,
+	})
+}
+
+// ExportServiceTemplate writes a deployed application out as a template
+// directory that CompileServiceTemplate can build, keeping its edited config
+// files, context, endpoints and instance counts.  Returns the path of the
+// directory and a warning for each service whose image could only be found in
+// this deployment's registry.
+func (a *api) ExportServiceTemplate(config ExportTemplateConfig) (string, []string, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return "", nil, err
+	}
+
+	svcs, err := client.GetServiceList(config.TenantID)
+	if err != nil {
+		return "", nil, err
+	}
+	var tenant *service.Service
+	children := make(map[string][]*service.Service)
+	for i := range svcs {
+		if svc := &svcs[i]; svc.ID == config.TenantID {
+			tenant = svc
+		} else {
+			children[svc.ParentServiceID] = append(children[svc.ParentServiceID], svc)
+		}
+	}
+	if tenant == nil {
+		return "", nil, fmt.Errorf("service %s not found", config.TenantID)
+	} else if tenant.ParentServiceID != "" {
+		return "", nil, fmt.Errorf("service %s is not an application", config.TenantID)
+	}
+	dir := filepath.Join(config.Dir, tenant.Name)
+	if _, err := os.Stat(dir); err == nil {
+		return "", nil, fmt.Errorf("%s already exists", dir)
+	} else if !os.IsNotExist(err) {
+		return "", nil, err
+	}
+
+	// The template the application was deployed from has the upstream names
+	// of its images and its log filters.
+	var source *servicedefinition.ServiceDefinition
+	if tenant.TemplateID != "" {
+		templates, err := client.GetServiceTemplates()
+		if err != nil {
+			return "", nil, err
+		}
+		if t, ok := templates[tenant.TemplateID]; ok {
+			source = findServiceDefinition(t.Services, tenant.Name)
+		}
+	}
+
+	var warnings []string
+	var export func(svc *service.Service, path string, source *servicedefinition.ServiceDefinition) (servicedefinition.ServiceDefinition, error)
+	export = func(svc *service.Service, path string, source *servicedefinition.ServiceDefinition) (servicedefinition.ServiceDefinition, error) {
+		sd := service.BuildServiceDefinition(*svc)
+		if imageID, ok := config.Map[svc.ImageID]; ok {
+			sd.ImageID = imageID
+		} else if source != nil && source.ImageID != "" {
+			sd.ImageID = source.ImageID
+		} else if sd.ImageID != "" {
+			warnings = append(warnings, fmt.Sprintf("%s: image %s is local to this deployment", path, sd.ImageID))
+		}
+		if source != nil {
+			sd.LogFilters = source.LogFilters
+		}
+		for _, child := range children[svc.ID] {
+			var childSource *servicedefinition.ServiceDefinition
+			if source != nil {
+				childSource = findServiceDefinition(source.Services, child.Name)
+			}
+			childDef, err := export(child, path+"/"+child.Name, childSource)
+			if err != nil {
+				return sd, err
+			}
+			sd.Services = append(sd.Services, childDef)
+		}
+		return sd, nil
+	}
+	sd, err := export(tenant, tenant.Name, source)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := sd.ValidEntity(); err != nil {
+		return "", nil, err
+	}
+	if err := servicedefinition.WriteToPath(&sd, config.Dir); err != nil {
+		return "", nil, err
+	}
+	return dir, warnings, nil
+}
+
+// findServiceDefinition returns the service definition with the given name
+func findServiceDefinition(sds []servicedefinition.ServiceDefinition, name string) *servicedefinition.ServiceDefinition {
+	for i := range sds {
+		if sds[i].Name == name {
+			return &sds[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	osuser "os/user"
+	"path/filepath"
+
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/domain/user"
+)
+
+// UserLoginFileName is the file, in the home directory of the operating system
+// user, in which the login of a control center user is kept
+const UserLoginFileName = ".serviced.login"
+
+// Returns a list of all users
+func (a *api) GetUsers() ([]user.User, error) {
+	client, err := a.connectMaster()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetUsers()
+}
+
+// Adds a new user without any roles
+func (a *api) AddUser(name, password string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.AddUser(user.User{Name: name, Password: password})
+}
+
+// Removes an existing user
+func (a *api) RemoveUser(name string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	return client.RemoveUser(name)
+}
+
+// Grants a role to an existing user
+func (a *api) GrantRole(name string, binding user.RoleBinding) error {
+	return a.updateRoles(name, func(roles *user.RoleBindings) error {
+		if !roles.Add(binding) {
+			return fmt.Errorf("user %s already has role %s", name, binding)
+		}
+		return nil
+	})
+}
+
+// Revokes a role from an existing user
+func (a *api) RevokeRole(name string, binding user.RoleBinding) error {
+	return a.updateRoles(name, func(roles *user.RoleBindings) error {
+		if !roles.Remove(binding) {
+			return fmt.Errorf("user %s does not have role %s", name, binding)
+		}
+		return nil
+	})
+}
+
+func (a *api) updateRoles(name string, update func(*user.RoleBindings) error) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	users, err := client.GetUsers()
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		if u.Name == name {
+			if err := update(&u.Roles); err != nil {
+				return err
+			}
+			return client.SetUserRoles(name, u.Roles)
+		}
+	}
+	return fmt.Errorf("user %s not found", name)
+}
+
+// userLogin is the login of a control center user, kept in the home
+// directory of the operating system user who logged in
+type userLogin struct {
+	Name  string
+	Token string
+}
+
+// userLoginFile returns the path of the file that holds the current login
+func userLoginFile() (string, error) {
+	u, err := osuser.Current()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(u.HomeDir, UserLoginFileName), nil
+}
+
+// readUserLogin returns the current login, if there is one
+func readUserLogin() (*userLogin, error) {
+	filename, err := userLoginFile()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	login := &userLogin{}
+	if err := json.Unmarshal(data, login); err != nil {
+		return nil, err
+	}
+	return login, nil
+}
+
+// loadUserLogin sends the token of the current login, if any, with requests
+// to the master
+func loadUserLogin() {
+	login, err := readUserLogin()
+	if err != nil {
+		log.WithError(err).Debug("Unable to read user login")
+		return
+	}
+	if login != nil {
+		auth.SetUserToken(login.Token)
+	}
+}
+
+// currentUserName returns the name of the logged-in user, or of the
+// operating system user if no one has logged in
+func currentUserName() string {
+	if login, err := readUserLogin(); err == nil && login != nil {
+		return login.Name
+	}
+	if u, err := osuser.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+// Logs a user in to the master, so that commands from this host act with
+// the roles of the user
+func (a *api) LoginUser(name, password string) error {
+	client, err := a.connectMaster()
+	if err != nil {
+		return err
+	}
+
+	// don't send a previous login along with the new one
+	auth.SetUserToken("")
+	token, err := client.LoginUser(name, password)
+	if err != nil {
+		return err
+	}
+
+	filename, err := userLoginFile()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(userLogin{Name: name, Token: token})
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filename, data, 0600); err != nil {
+		return err
+	}
+	auth.SetUserToken(token)
+	return nil
+}
+
+// Logs the current user out
+func (a *api) LogoutUser() error {
+	auth.SetUserToken("")
+	filename, err := userLoginFile()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
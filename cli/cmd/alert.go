@@ -0,0 +1,138 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/codegangsta/cli"
+)
+
+// Initializer for serviced alert subcommands
+func (c *ServicedCli) initAlert() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "alert",
+		Usage:       "Lists and acknowledges monitoring alerts",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:         "list",
+				Usage:        "Lists the active alerts",
+				Description:  "serviced alert list [--all]",
+				BashComplete: nil,
+				Action:       c.cmdAlertList,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "all, a",
+						Usage: "Include resolved alerts",
+					},
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+					cli.StringFlag{
+						Name:  "show-fields",
+						Value: "ID,ServiceID,Threshold,DataPoint,State,Opened,Message",
+						Usage: "Comma-delimited list describing which fields to display",
+					},
+				},
+			}, {
+				Name:         "ack",
+				Usage:        "Acknowledges active alerts",
+				Description:  "serviced alert ack ALERTID ...",
+				BashComplete: c.printActiveAlerts,
+				Action:       c.cmdAlertAck,
+			},
+		},
+	})
+}
+
+// Bash-completion command that prints the ids of the active alerts
+func (c *ServicedCli) printActiveAlerts(ctx *cli.Context) {
+	alerts, err := c.driver.GetAlerts(false)
+	if err != nil {
+		return
+	}
+	for _, a := range alerts {
+		fmt.Println(a.ID)
+	}
+}
+
+// formatAlertTime formats the time an alert changed state, if it has
+func formatAlertTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// serviced alert list [--all]
+func (c *ServicedCli) cmdAlertList(ctx *cli.Context) {
+	alerts, err := c.driver.GetAlerts(ctx.Bool("all"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if alerts == nil || len(alerts) == 0 {
+		fmt.Fprintln(os.Stderr, "no alerts found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonAlerts, err := json.MarshalIndent(alerts, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal alert list: %s", err)
+		} else {
+			fmt.Println(string(jsonAlerts))
+		}
+	} else {
+		t := NewTable(ctx.String("show-fields"))
+		for _, a := range alerts {
+			t.AddRow(map[string]interface{}{
+				"ID":             a.ID,
+				"ServiceID":      a.ServiceID,
+				"TenantID":       a.TenantID,
+				"Threshold":      a.ThresholdName,
+				"DataPoint":      a.DataPoint,
+				"Value":          a.Value,
+				"State":          a.State,
+				"Opened":         formatAlertTime(a.Opened),
+				"Acknowledged":   formatAlertTime(a.Acknowledged),
+				"AcknowledgedBy": a.AcknowledgedBy,
+				"Resolved":       formatAlertTime(a.Resolved),
+				"Message":        a.Message,
+			})
+		}
+		t.Print()
+	}
+}
+
+// serviced alert ack ALERTID ...
+func (c *ServicedCli) cmdAlertAck(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "ack")
+		return
+	}
+
+	for _, alertID := range args {
+		if err := c.driver.AcknowledgeAlert(alertID); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", alertID, err)
+		} else {
+			fmt.Println(alertID)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/alert"
+)
+
+var ErrNoAlertFound = errors.New("no alert found")
+
+type AlertAPITest struct {
+	api.API
+	alerts []alert.Alert
+}
+
+func DefaultAlertAPI() AlertAPITest {
+	return AlertAPITest{
+		alerts: []alert.Alert{
+			{ID: "alert1", ServiceID: "svc1", ThresholdName: "Memory", State: alert.StateOpen},
+			{ID: "alert2", ServiceID: "svc2", ThresholdName: "CPU", State: alert.StateResolved},
+		},
+	}
+}
+
+func (t AlertAPITest) GetAlerts(all bool) ([]alert.Alert, error) {
+	alerts := []alert.Alert{}
+	for _, a := range t.alerts {
+		if all || a.Active() {
+			alerts = append(alerts, a)
+		}
+	}
+	return alerts, nil
+}
+
+func (t AlertAPITest) AcknowledgeAlert(alertID string) error {
+	for _, a := range t.alerts {
+		if a.ID == alertID {
+			if !a.Active() {
+				return alert.ErrAlertResolved
+			}
+			return nil
+		}
+	}
+	return ErrNoAlertFound
+}
+
+func ExampleServicedCLI_CmdAlertList() {
+	RunCmd(DefaultAlertAPI(), "serviced", "alert", "list", "--show-fields", "ID,Threshold,State")
+
+	// Output:
+	// ID     Threshold State
+	// alert1 Memory    open
+}
+
+func ExampleServicedCLI_CmdAlertList_all() {
+	RunCmd(DefaultAlertAPI(), "serviced", "alert", "list", "--all", "--show-fields", "ID")
+
+	// Output:
+	// ID
+	// alert1
+	// alert2
+}
+
+func ExampleServicedCLI_CmdAlertAck() {
+	RunCmd(DefaultAlertAPI(), "serviced", "alert", "ack", "alert1")
+
+	// Output:
+	// alert1
+}
+
+func ExampleServicedCLI_CmdAlertAck_err() {
+	pipeStderr(func() { RunCmd(DefaultAlertAPI(), "serviced", "alert", "ack", "alert2", "alert3") })
+
+	// Output:
+	// alert2: alert is already resolved
+	// alert3: no alert found
+}
@@ -0,0 +1,172 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/auditlog"
+)
+
+// auditTimeLayouts are the layouts accepted by the --since and --until
+// options, in local time unless the layout has a zone
+var auditTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// Initializer for serviced audit subcommands
+func (c *ServicedCli) initAudit() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "audit",
+		Usage:       "Queries the audit trail",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:         "list",
+				Usage:        "Lists audit events, newest first",
+				Description:  "serviced audit list [--user USER] [--type TYPE] [--id ID] [--action ACTION] [--since TIME] [--until TIME] [--success|--failed]",
+				BashComplete: nil,
+				Action:       c.cmdAuditList,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "user",
+						Usage: "Only events by this user",
+					},
+					cli.StringFlag{
+						Name:  "type",
+						Usage: "Only events on this type of entity (e.g. service, host, resourcepool)",
+					},
+					cli.StringFlag{
+						Name:  "id",
+						Usage: "Only events on the entity with this id",
+					},
+					cli.StringFlag{
+						Name:  "action",
+						Usage: "Only events for this action (e.g. add, update, remove, start, stop)",
+					},
+					cli.StringFlag{
+						Name:  "since",
+						Usage: "Only events at or after this time, as YYYY-MM-DD [HH:MM[:SS]], RFC 3339, or a duration ago such as 24h",
+					},
+					cli.StringFlag{
+						Name:  "until",
+						Usage: "Only events at or before this time, in the same formats as --since",
+					},
+					cli.BoolFlag{
+						Name:  "success",
+						Usage: "Only successful actions",
+					},
+					cli.BoolFlag{
+						Name:  "failed",
+						Usage: "Only failed actions",
+					},
+					cli.IntFlag{
+						Name:  "limit, n",
+						Value: 100,
+						Usage: "Maximum number of events to show, or 0 for all",
+					},
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+					cli.StringFlag{
+						Name:  "show-fields",
+						Value: "Time,User,Action,Type,ID,Success,Message",
+						Usage: "Comma-delimited list describing which fields to display",
+					},
+				},
+			},
+		},
+	})
+}
+
+// parseAuditTime parses the value of the --since or --until option
+func parseAuditTime(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range auditTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", value)
+}
+
+// serviced audit list [--user USER] [--type TYPE] [--id ID] [--action ACTION] [--since TIME] [--until TIME] [--success|--failed]
+func (c *ServicedCli) cmdAuditList(ctx *cli.Context) {
+	filter := auditlog.Filter{
+		User:     ctx.String("user"),
+		Type:     ctx.String("type"),
+		EntityID: ctx.String("id"),
+		Action:   ctx.String("action"),
+		Limit:    ctx.Int("limit"),
+	}
+	now := time.Now()
+	for name, t := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if value := ctx.String(name); value != "" {
+			parsed, err := parseAuditTime(value, now)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "--%s: %s\n", name, err)
+				return
+			}
+			*t = parsed
+		}
+	}
+	if ctx.Bool("success") && ctx.Bool("failed") {
+		fmt.Fprintln(os.Stderr, "--success and --failed cannot be used together")
+		return
+	} else if ctx.Bool("success") || ctx.Bool("failed") {
+		success := ctx.Bool("success")
+		filter.Success = &success
+	}
+
+	events, err := c.driver.GetAuditEvents(filter)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(events) == 0 {
+		fmt.Fprintln(os.Stderr, "no audit events found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonEvents, err := json.MarshalIndent(events, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal audit events: %s", err)
+		} else {
+			fmt.Println(string(jsonEvents))
+		}
+	} else {
+		t := NewTable(ctx.String("show-fields"))
+		for _, e := range events {
+			t.AddRow(map[string]interface{}{
+				"Time":    e.Time.Local().Format("2006-01-02 15:04:05"),
+				"User":    e.User,
+				"Action":  e.Action,
+				"Type":    e.Type,
+				"ID":      e.EntityID,
+				"Success": e.Success,
+				"Message": e.Message,
+			})
+		}
+		t.Print()
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/auditlog"
+)
+
+type AuditAPITest struct {
+	api.API
+	events []auditlog.Event
+}
+
+func DefaultAuditAPI() AuditAPITest {
+	t0 := time.Date(2019, 3, 5, 10, 0, 0, 0, time.UTC)
+	return AuditAPITest{
+		events: []auditlog.Event{
+			{ID: "e2", Event: audit.Event{Time: t0.Add(time.Hour), User: "jdoe", Action: audit.Stop, Type: "service", EntityID: "svc1", Message: "Stop Service", Success: false}},
+			{ID: "e1", Event: audit.Event{Time: t0, User: "admin", Action: audit.Add, Type: "resourcepool", EntityID: "pool1", Message: "Adding Pool", Success: true}},
+		},
+	}
+}
+
+func (t AuditAPITest) GetAuditEvents(filter auditlog.Filter) ([]auditlog.Event, error) {
+	events := []auditlog.Event{}
+	for _, e := range t.events {
+		if filter.User != "" && filter.User != e.User {
+			continue
+		}
+		if filter.Success != nil && *filter.Success != e.Success {
+			continue
+		}
+		if !filter.Since.IsZero() && e.Time.Before(filter.Since) {
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+func ExampleServicedCLI_CmdAuditList() {
+	RunCmd(DefaultAuditAPI(), "serviced", "audit", "list", "--show-fields", "User,Action,Type,ID,Success")
+
+	// Output:
+	// User  Action Type         ID    Success
+	// jdoe  stop   service      svc1  false
+	// admin add    resourcepool pool1 true
+}
+
+func ExampleServicedCLI_CmdAuditList_filter() {
+	RunCmd(DefaultAuditAPI(), "serviced", "audit", "list", "--failed", "--show-fields", "ID,Message")
+	RunCmd(DefaultAuditAPI(), "serviced", "audit", "list", "--user", "admin", "--show-fields", "ID,Message")
+	RunCmd(DefaultAuditAPI(), "serviced", "audit", "list", "--since", "2019-03-05T10:30:00Z", "--show-fields", "ID")
+
+	// Output:
+	// ID   Message
+	// svc1 Stop Service
+	// ID    Message
+	// pool1 Adding Pool
+	// ID
+	// svc1
+}
+
+func ExampleServicedCLI_CmdAuditList_err() {
+	pipeStderr(func() {
+		RunCmd(DefaultAuditAPI(), "serviced", "audit", "list", "--success", "--failed")
+		RunCmd(DefaultAuditAPI(), "serviced", "audit", "list", "--since", "last tuesday")
+		RunCmd(DefaultAuditAPI(), "serviced", "audit", "list", "--user", "nobody")
+	})
+
+	// Output:
+	// --success and --failed cannot be used together
+	// --since: invalid time "last tuesday"
+	// no audit events found
+}
+
+func Example_parseAuditTime() {
+	now := time.Date(2019, 3, 5, 10, 0, 0, 0, time.UTC)
+	t, _ := parseAuditTime("36h", now)
+	fmt.Println(t.UTC().Format(time.RFC3339))
+	t, _ = parseAuditTime("2019-03-01T08:00:00Z", now)
+	fmt.Println(t.UTC().Format(time.RFC3339))
+	t, _ = parseAuditTime("2019-03-01", now)
+	fmt.Println(t.Format("2006-01-02 15:04"))
+
+	// Output:
+	// 2019-03-03T22:00:00Z
+	// 2019-03-01T08:00:00Z
+	// 2019-03-01 00:00
+}
@@ -16,6 +16,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/codegangsta/cli"
 )
@@ -27,7 +28,7 @@ func (c *ServicedCli) initBackup() {
 		cli.Command{
 			Name:        "backup",
 			Usage:       "Dump all templates and services to a tgz file",
-			Description: "serviced backup DIRPATH",
+			Description: "serviced backup DIRPATH | serviced backup verify FILEPATH",
 			Action:      c.cmdBackup,
 			Flags: []cli.Flag{
 				cli.StringSliceFlag{
@@ -43,6 +44,10 @@ func (c *ServicedCli) initBackup() {
 					Name: "force",
 					Usage: "attempt backup even if space check fails",
 				},
+				cli.StringFlag{
+					Name:  "incremental-from",
+					Usage: "only back up the changes since this backup",
+				},
 			},
 		},
 		cli.Command{
@@ -57,12 +62,16 @@ func (c *ServicedCli) initBackup() {
 // serviced backup DIRPATH
 func (c *ServicedCli) cmdBackup(ctx *cli.Context)  {
 	args := ctx.Args()
-	if len(args) < 1 {
+	if len(args) < 1 || (args[0] == "verify" && len(args) < 2) {
 		fmt.Printf("Incorrect Usage.\n\n")
 		cli.ShowCommandHelp(ctx, "backup")
 		c.exit(1)
 		return
 	}
+	if args[0] == "verify" {
+		c.cmdBackupVerify(args[1])
+		return
+	}
 	if ctx.Bool("check") {
 		fmt.Printf("Checking for space...\n")
 		if backupSpace, err := c.driver.GetBackupEstimate(args[0], ctx.StringSlice("exclude")); err != nil {
@@ -81,7 +90,7 @@ func (c *ServicedCli) cmdBackup(ctx *cli.Context)  {
 		return
 	}
 	// do backup
-	if path, err := c.driver.Backup(args[0], ctx.StringSlice("exclude"), ctx.Bool("force")); err != nil {
+	if path, err := c.driver.Backup(args[0], ctx.StringSlice("exclude"), ctx.Bool("force"), ctx.String("incremental-from")); err != nil {
 		fmt.Fprintln(os.Stdout, err)
 		c.exit(1)
 		return
@@ -94,6 +103,26 @@ func (c *ServicedCli) cmdBackup(ctx *cli.Context)  {
 	}
 }
 
+// serviced backup verify FILEPATH
+func (c *ServicedCli) cmdBackupVerify(path string) {
+	result, err := c.driver.VerifyBackup(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", path, err)
+		c.exit(1)
+		return
+	}
+	details := []string{fmt.Sprintf("%d entries", result.Entries)}
+	if result.Signed {
+		details = append(details, "signed")
+	} else {
+		details = append(details, "unsigned")
+	}
+	if result.Encrypted {
+		details = append(details, "encrypted")
+	}
+	fmt.Printf("%s: OK (%s)\n", path, strings.Join(details, ", "))
+}
+
 // serviced restore FILEPATH
 func (c *ServicedCli) cmdRestore(ctx *cli.Context) {
 	args := ctx.Args()
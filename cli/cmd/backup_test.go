@@ -23,6 +23,7 @@ import (
 
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/dfs"
 	"github.com/control-center/serviced/utils"
 )
 
@@ -54,7 +55,10 @@ func InitBackupAPITestNoExit(args ...string) {
 	c.Run(args)
 }
 
-func (t BackupAPITest) Backup(dirpath string, excludes []string, force bool) (string, error) {
+func (t BackupAPITest) Backup(dirpath string, excludes []string, force bool, incrementalFrom string) (string, error) {
+	if incrementalFrom != "" {
+		return fmt.Sprintf("%s-from-%s", path.Base(dirpath), incrementalFrom), nil
+	}
 	switch dirpath {
 	case PathNotFound:
 		return "", ErrBackupFailed
@@ -80,6 +84,17 @@ func (t BackupAPITest) Restore(path string) error {
 	}
 }
 
+func (t BackupAPITest) VerifyBackup(path string) (*dfs.BackupVerification, error) {
+	switch path {
+	case PathNotFound:
+		return nil, dfs.ErrBackupTampered
+	case TooSmallPath:
+		return &dfs.BackupVerification{Entries: 3}, nil
+	default:
+		return &dfs.BackupVerification{Entries: 12, Signed: true, Encrypted: true}, nil
+	}
+}
+
 func (t BackupAPITest) GetBackupEstimate(path string, _ []string) (*dao.BackupEstimate, error) {
 	switch path{
 	case TooSmallPath:
@@ -142,12 +157,13 @@ func ExampleServicedCLI_CmdBackup_usage() {
 	//    command backup [command options] [arguments...]
 	//
 	// DESCRIPTION:
-	//    serviced backup DIRPATH
+	//    serviced backup DIRPATH | serviced backup verify FILEPATH
 	//
 	// OPTIONS:
 	//    --exclude '--exclude option --exclude option'	Subdirectory of the tenant volume to exclude from backup
 	//    --check						check space, but do not do backup
 	//    --force						attempt backup even if space check fails
+	//    --incremental-from 					only back up the changes since this backup
 }
 
 func ExampleServicedCLI_CmdBackup_noforce() {
@@ -167,6 +183,14 @@ func ExampleServicedCLI_CmdBackup_force() {
 	// TooSmallPath.tgz
 }
 
+func ExampleServicedCLI_CmdBackup_incremental() {
+	// Backup called with a parent backup
+	InitBackupAPITest("serviced", "backup", "path/to/dir", "--incremental-from", "backup-2019-01-01-000000.tgz")
+
+	// Output:
+	// dir-from-backup-2019-01-01-000000.tgz
+}
+
 func ExampleServicedCLI_CmdBackup_check() {
 	// Backup called with check-only flag
 	InitBackupAPITestNoExit("serviced", "backup", "path/to/dir", "--check")
@@ -202,3 +226,18 @@ func ExampleServicedCLI_CmdRestore_usage() {
 	// OPTIONS:
 }
 
+func ExampleServicedCLI_CmdBackup_verify() {
+	InitBackupAPITest("serviced", "backup", "verify", "backup.tgz")
+	InitBackupAPITest("serviced", "backup", "verify", TooSmallPath)
+
+	// Output:
+	// backup.tgz: OK (12 entries, signed, encrypted)
+	// TooSmallPath: OK (3 entries, unsigned)
+}
+
+func ExampleServicedCLI_CmdBackup_verifyTampered() {
+	pipeStderr(func() { InitBackupAPITestNoExit("serviced", "backup", "verify", PathNotFound) })
+
+	// Output:
+	// PathNotFound: backup does not match its signed checksums
+}
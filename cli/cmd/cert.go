@@ -0,0 +1,174 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/certificate"
+)
+
+// Initializer for serviced cert subcommands
+func (c *ServicedCli) initCert() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "cert",
+		Usage:       "Administers the certificates of virtual hosts and public ports",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:         "list",
+				Usage:        "Lists the certificates",
+				Description:  "serviced cert list",
+				BashComplete: nil,
+				Action:       c.cmdCertList,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+					cli.StringFlag{
+						Name:  "show-fields",
+						Value: "Name,Subject,NotAfter,Status",
+						Usage: "Comma-delimited list describing which fields to display",
+					},
+				},
+			}, {
+				Name:         "add",
+				Usage:        "Adds or replaces the certificate of a virtual host, hostname or public port",
+				Description:  "serviced cert add NAME CERTFILE KEYFILE",
+				BashComplete: nil,
+				Action:       c.cmdCertAdd,
+			}, {
+				Name:         "remove",
+				ShortName:    "rm",
+				Usage:        "Removes certificates",
+				Description:  "serviced cert remove NAME ...",
+				BashComplete: c.printCerts,
+				Action:       c.cmdCertRemove,
+			},
+		},
+	})
+}
+
+// Bash-completion command that prints the names of the certificates
+func (c *ServicedCli) printCerts(ctx *cli.Context) {
+	certs, err := c.driver.GetCertificates()
+	if err != nil {
+		return
+	}
+	for _, cert := range certs {
+		fmt.Println(cert.ID)
+	}
+}
+
+// certStatus describes whether a certificate is current at a time
+func certStatus(cert certificate.Certificate, now time.Time) string {
+	switch {
+	case !now.Before(cert.NotAfter):
+		return "expired"
+	case cert.Warning(now) != "":
+		return "expiring"
+	default:
+		return "ok"
+	}
+}
+
+// serviced cert list
+func (c *ServicedCli) cmdCertList(ctx *cli.Context) {
+	certs, err := c.driver.GetCertificates()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if certs == nil || len(certs) == 0 {
+		fmt.Fprintln(os.Stderr, "no certificates found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonCerts, err := json.MarshalIndent(certs, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal certificate list: %s", err)
+		} else {
+			fmt.Println(string(jsonCerts))
+		}
+	} else {
+		now := time.Now()
+		t := NewTable(ctx.String("show-fields"))
+		for _, cert := range certs {
+			t.AddRow(map[string]interface{}{
+				"Name":      cert.ID,
+				"Subject":   cert.Subject,
+				"DNSNames":  strings.Join(cert.DNSNames, ","),
+				"NotBefore": cert.NotBefore.UTC().Format("2006-01-02"),
+				"NotAfter":  cert.NotAfter.UTC().Format("2006-01-02"),
+				"Status":    certStatus(cert, now),
+			})
+		}
+		t.Print()
+	}
+}
+
+// serviced cert add NAME CERTFILE KEYFILE
+func (c *ServicedCli) cmdCertAdd(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 3 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "add")
+		return
+	}
+
+	certPEM, err := ioutil.ReadFile(args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	keyPEM, err := ioutil.ReadFile(args[2])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+
+	cert := certificate.Certificate{ID: args[0], CertPEM: string(certPEM), KeyPEM: string(keyPEM)}
+	if err := c.driver.AddCertificate(cert); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+	} else {
+		fmt.Println(certificate.Normalize(args[0]))
+	}
+}
+
+// serviced cert remove NAME ...
+func (c *ServicedCli) cmdCertRemove(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "remove")
+		return
+	}
+
+	for _, name := range args {
+		if err := c.driver.RemoveCertificate(name); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", name, err)
+		} else {
+			fmt.Println(name)
+		}
+	}
+}
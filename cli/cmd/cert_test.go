@@ -0,0 +1,106 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/certificate"
+)
+
+var ErrNoCertificateFound = errors.New("no certificate found")
+
+type CertAPITest struct {
+	api.API
+	certs []certificate.Certificate
+}
+
+func DefaultCertAPI() CertAPITest {
+	return CertAPITest{
+		certs: []certificate.Certificate{
+			{
+				ID: "app", Subject: "app.example.com", DNSNames: []string{"app.example.com"},
+				NotBefore: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+				NotAfter:  time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC),
+			}, {
+				ID: ":9443", Subject: "ports.example.com",
+				NotBefore: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC),
+				NotAfter:  time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+}
+
+func (t CertAPITest) GetCertificates() ([]certificate.Certificate, error) {
+	return t.certs, nil
+}
+
+func (t CertAPITest) AddCertificate(cert certificate.Certificate) error {
+	fmt.Printf("%s %s ", cert.CertPEM, cert.KeyPEM)
+	return nil
+}
+
+func (t CertAPITest) RemoveCertificate(certificateID string) error {
+	for _, cert := range t.certs {
+		if cert.ID == certificateID {
+			return nil
+		}
+	}
+	return ErrNoCertificateFound
+}
+
+// tempPEM writes PEM data to a temporary file and returns its name
+func tempPEM(data string) string {
+	f, err := ioutil.TempFile("", "cert-test-")
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	f.WriteString(data)
+	return f.Name()
+}
+
+func ExampleServicedCLI_CmdCertList() {
+	RunCmd(DefaultCertAPI(), "serviced", "cert", "list")
+
+	// Output:
+	// Name  Subject           NotAfter   Status
+	// app   app.example.com   2099-01-01 ok
+	// :9443 ports.example.com 2019-01-01 expired
+}
+
+func ExampleServicedCLI_CmdCertAdd() {
+	certFile, keyFile := tempPEM("CERT"), tempPEM("KEY")
+	defer os.Remove(certFile)
+	defer os.Remove(keyFile)
+	RunCmd(DefaultCertAPI(), "serviced", "cert", "add", "App.Example.com", certFile, keyFile)
+
+	// Output:
+	// CERT KEY app.example.com
+}
+
+func ExampleServicedCLI_CmdCertRemove() {
+	pipeStderr(func() { RunCmd(DefaultCertAPI(), "serviced", "cert", "remove", "app", "other") })
+
+	// Output:
+	// app
+	// other: no certificate found
+}
@@ -92,6 +92,9 @@ func New(driver api.API, config utils.ConfigReader, logControl logging.LogContro
 		cli.StringSliceFlag{"alias", convertToStringSlice(defaultOps.HostAliases), "list of aliases for this host, e.g., localhost"},
 		cli.IntFlag{"es-startup-timeout", defaultOps.ESStartupTimeout, "time (in seconds) to wait on elasticsearch startup before bailing"},
 		cli.IntFlag{"es-request-timeout", defaultOps.ESRequestTimeout, "elasticsearch client connection timeout in seconds"},
+		cli.StringFlag{"datastore-driver", defaultOps.DatastoreDriver, "driver that stores the master's data (elastic, local)"},
+		cli.StringFlag{"datastore-path", defaultOps.DatastorePath, "path to the file used by the local datastore driver"},
+		cli.StringFlag{"sessions-path", defaultOps.SessionsPath, "path where the master keeps recordings of shell sessions"},
 		cli.IntFlag{"max-container-age", defaultOps.MaxContainerAge, "maximum age (seconds) of a stopped container before removing"},
 		cli.IntFlag{"max-dfs-timeout", defaultOps.MaxDFSTimeout, "max timeout to perform a dfs snapshot"},
 		cli.StringFlag{"virtual-address-subnet", defaultOps.VirtualAddressSubnet, "/16 subnet for virtual addresses"},
@@ -148,6 +151,11 @@ func New(driver api.API, config utils.ConfigReader, logControl logging.LogContro
 		cli.IntFlag{"storage-metric-monitor-window", defaultOps.StorageMetricMonitorWindow, "the amount of time in seconds for which serviced will consider storage availability metrics in order to predict future availability"},
 		cli.IntFlag{"storage-lookahead-period", defaultOps.StorageLookaheadPeriod, "the amount of time in the future in seconds serviced should predict storage availability for the purposes of emergency shutdown"},
 		cli.StringFlag{"storage-min-free", string(defaultOps.StorageMinimumFreeSpace), "the amount of space the emergency shutdown algorithm should reserve when deciding to shut down"},
+		cli.StringFlag{"storage-forecast-model", defaultOps.StorageForecastModel, "the model used to predict storage availability (leastsquares, theilsen or holtwinters), optionally per volume as NAME=MODEL"},
+		cli.IntFlag{"storage-forecast-season", defaultOps.StorageForecastSeason, "the length in seconds of the storage usage cycle followed by the holtwinters model"},
+		cli.IntFlag{"threshold-eval-interval", defaultOps.ThresholdEvalInterval, "the time in seconds between evaluations of monitoring profile thresholds, or 0 to disable alerting"},
+		cli.StringFlag{"notify-config", defaultOps.NotifyConfig, "path to the JSON file that configures notification sinks and routes"},
+		cli.IntFlag{"audit-retention-days", defaultOps.AuditRetentionDays, "the number of days audit events are kept, or 0 to keep them forever"},
 
 		cli.IntFlag{"logstash-cycle-time", defaultOps.LogstashCycleTime, "logstash purging cycle time in hours"},
 		cli.IntFlag{"v", defaultOps.Verbosity, "log level for V logs"},
@@ -158,11 +166,23 @@ func New(driver api.API, config utils.ConfigReader, logControl logging.LogContro
 		cli.StringFlag{"allow-loop-back", defaultOps.AllowLoopBack, "allow loop-back device with devicemapper"},
 		cli.StringFlag{"backup-min-overhead", defaultOps.BackupMinOverhead, "Minimum free space to allow when calculating backup estimates"},
 		cli.Float64Flag{"backup-estimated-compression", defaultOps.BackupEstimatedCompression, "Estimate of compression rate to use when calculating backup estimates"},
+		cli.StringFlag{"backup-key-file", defaultOps.BackupKeyFile, "path to the file with the passphrase that encrypts and signs backups"},
+		cli.StringFlag{"backup-target", defaultOps.BackupTarget, "directory or url (s3://, sftp://) where backups are written by default"},
 		cli.StringFlag{"auth0-domain", defaultOps.Auth0Domain, "Domain configured for tenant in Auth0. Ref: https://auth0.com/docs/getting-started/the-basics#domain"},
 		cli.StringFlag{"auth0-audience", defaultOps.Auth0Audience, "Audience configured for application (?) in Auth0."},
 		cli.StringSliceFlag{"auth0-group", convertToStringSlice(defaultOps.Auth0Group), "Group(s) configured for application in Auth0. A comma-separated list."},
 		cli.StringFlag{"auth0-client-id", defaultOps.Auth0ClientID, "Client ID of Auth0 application"},
 		cli.StringFlag{"auth0-scope", defaultOps.Auth0Scope, "Scope to request in Auth0"},
+		cli.StringFlag{"oidc-issuer", defaultOps.OIDCIssuer, "Issuer URL of the OpenID Connect provider used to log in users"},
+		cli.StringFlag{"oidc-client-id", defaultOps.OIDCClientID, "Client ID registered with the OpenID Connect provider"},
+		cli.StringFlag{"oidc-client-secret", defaultOps.OIDCClientSecret, "Client secret registered with the OpenID Connect provider"},
+		cli.StringSliceFlag{"oidc-scopes", convertToStringSlice(defaultOps.OIDCScopes), "Scopes to request from the OpenID Connect provider. A comma-separated list."},
+		cli.StringFlag{"oidc-redirect-url", defaultOps.OIDCRedirectURL, "URL the OpenID Connect provider returns to after login (https://HOST/auth/oidc/callback)"},
+		cli.StringFlag{"oidc-audience", defaultOps.OIDCAudience, "Audience required of OpenID Connect bearer tokens (defaults to the client ID)"},
+		cli.StringFlag{"oidc-username-claim", defaultOps.OIDCUsernameClaim, "Claim holding the user name in OpenID Connect tokens"},
+		cli.StringFlag{"oidc-groups-claim", defaultOps.OIDCGroupsClaim, "Claim holding the groups in OpenID Connect tokens"},
+		cli.StringSliceFlag{"oidc-admin-groups", convertToStringSlice(defaultOps.OIDCAdminGroups), "OpenID Connect groups whose members are cluster administrators. A comma-separated list."},
+		cli.StringSliceFlag{"oidc-group-roles", convertToStringSlice(defaultOps.OIDCGroupRoles), "Roles granted to OpenID Connect groups, as GROUP=ROLE[@tenant:ID|@pool:ID]. A comma-separated list."},
 		cli.StringFlag{"keyproxy-json-server", defaultOps.KeyProxyJsonServer, "URL for API key server (cc auth token endpoint)"},
 		cli.StringFlag{"keyproxy-listen-port", defaultOps.KeyProxyListenPort, "Port for API key proxy to listen on"},
 		cli.BoolFlag{"no-prefix-match", "Make matches on SERVICEID by name strictly 'ends-with' rather than 'contains'"},
@@ -179,12 +199,19 @@ func New(driver api.API, config utils.ConfigReader, logControl logging.LogContro
 	c.initLog()
 	c.initBackup()
 	c.initMetric()
+	c.initDatastore()
 	c.initDocker()
 	c.initScript()
 	c.initServer()
 	c.initVolume()
 	c.initKey()
 	c.initDebug()
+	c.initUser()
+	c.initAlert()
+	c.initSchedule()
+	c.initCert()
+	c.initAudit()
+	c.initSession()
 
 	return c
 }
@@ -260,6 +287,9 @@ func getRuntimeOptions(cfg utils.ConfigReader, ctx *cli.Context) config.Options
 		Mount:                      ctx.GlobalStringSlice("mount"),
 		HostAliases:                ctx.GlobalStringSlice("alias"),
 		ESStartupTimeout:           ctx.GlobalInt("es-startup-timeout"),
+		DatastoreDriver:            ctx.GlobalString("datastore-driver"),
+		DatastorePath:              ctx.GlobalString("datastore-path"),
+		SessionsPath:               ctx.GlobalString("sessions-path"),
 		ReportStats:                ctx.GlobalBool("report-stats"),
 		HostStats:                  ctx.GlobalString("host-stats"),
 		StatsPeriod:                ctx.GlobalInt("stats-period"),
@@ -315,13 +345,30 @@ func getRuntimeOptions(cfg utils.ConfigReader, ctx *cli.Context) config.Options
 		StorageMetricMonitorWindow: ctx.GlobalInt("storage-metric-monitor-window"),
 		StorageLookaheadPeriod:     ctx.GlobalInt("storage-lookahead-period"),
 		StorageMinimumFreeSpace:    ctx.GlobalString("storage-min-free"),
+		StorageForecastModel:       ctx.GlobalString("storage-forecast-model"),
+		StorageForecastSeason:      ctx.GlobalInt("storage-forecast-season"),
+		ThresholdEvalInterval:      ctx.GlobalInt("threshold-eval-interval"),
+		NotifyConfig:               ctx.GlobalString("notify-config"),
+		AuditRetentionDays:         ctx.GlobalInt("audit-retention-days"),
 		BackupEstimatedCompression: ctx.Float64("backup-estimated-compression"),
 		BackupMinOverhead:          ctx.String("backup-min-overhead"),
+		BackupKeyFile:              ctx.GlobalString("backup-key-file"),
+		BackupTarget:               ctx.GlobalString("backup-target"),
 		Auth0Domain:                ctx.String("auth0-domain"),
 		Auth0Audience:              ctx.String("auth0-audience"),
 		Auth0Group:                 ctx.GlobalStringSlice("auth0-group"),
 		Auth0ClientID:              ctx.String("auth0-client-id"),
 		Auth0Scope:                 ctx.String("auth0-scope"),
+		OIDCIssuer:                 ctx.String("oidc-issuer"),
+		OIDCClientID:               ctx.String("oidc-client-id"),
+		OIDCClientSecret:           ctx.String("oidc-client-secret"),
+		OIDCScopes:                 ctx.GlobalStringSlice("oidc-scopes"),
+		OIDCRedirectURL:            ctx.String("oidc-redirect-url"),
+		OIDCAudience:               ctx.String("oidc-audience"),
+		OIDCUsernameClaim:          ctx.String("oidc-username-claim"),
+		OIDCGroupsClaim:            ctx.String("oidc-groups-claim"),
+		OIDCAdminGroups:            ctx.GlobalStringSlice("oidc-admin-groups"),
+		OIDCGroupRoles:             ctx.GlobalStringSlice("oidc-group-roles"),
 		KeyProxyJsonServer:         ctx.String("keyproxy-json-server"),
 		KeyProxyListenPort:         ctx.String("keyproxy-listen-port"),
 	}
@@ -0,0 +1,69 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/config"
+)
+
+// Initializer for serviced datastore subcommands
+func (c *ServicedCli) initDatastore() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "datastore",
+		Usage:       "Administers the master's datastore",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:        "migrate",
+				Usage:       "Copies all data from one datastore driver to another",
+				Description: "serviced datastore migrate [--from DRIVER] [--to DRIVER]",
+				Action:      c.cmdDatastoreMigrate,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "from",
+						Value: config.DatastoreElastic,
+						Usage: "datastore driver to copy from (elastic, local)",
+					},
+					cli.StringFlag{
+						Name:  "to",
+						Value: config.DatastoreLocal,
+						Usage: "datastore driver to copy to (elastic, local)",
+					},
+				},
+			},
+		},
+	})
+}
+
+// serviced datastore migrate [--from DRIVER] [--to DRIVER]
+func (c *ServicedCli) cmdDatastoreMigrate(ctx *cli.Context) {
+	if len(ctx.Args()) > 0 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "migrate")
+		return
+	}
+
+	from, to := ctx.String("from"), ctx.String("to")
+	count, err := c.driver.MigrateDatastore(from, to)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Printf("Copied %d entities from the %s datastore to the %s datastore\n", count, from, to)
+	fmt.Printf("Set SERVICED_DATASTORE_DRIVER=%s and restart serviced to use it\n", to)
+}
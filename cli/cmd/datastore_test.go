@@ -0,0 +1,54 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *mySuite) Test_cmdDatastoreMigrate(c *C) {
+	s.api.On("MigrateDatastore", "elastic", "local").Return(42, nil)
+	output := captureStdout(func() {
+		s.cli.Run(strings.Split("serviced datastore migrate", " "))
+	})
+	s.api.AssertExpectations(c)
+	c.Assert(string(output), Equals, "Copied 42 entities from the elastic datastore to the local datastore\n"+
+		"Set SERVICED_DATASTORE_DRIVER=local and restart serviced to use it\n")
+}
+
+func (s *mySuite) Test_cmdDatastoreMigrate_reverse(c *C) {
+	s.api.On("MigrateDatastore", "local", "elastic").Return(3, nil)
+	captureStdout(func() {
+		s.cli.Run(strings.Split("serviced datastore migrate --from local --to elastic", " "))
+	})
+	s.api.AssertExpectations(c)
+}
+
+func (s *mySuite) Test_cmdDatastoreMigrate_error(c *C) {
+	s.api.On("MigrateDatastore", "elastic", "local").Return(0, errors.New("datastore is in use"))
+	var output []byte
+	errout := captureStderr(func() {
+		output = captureStdout(func() {
+			s.cli.Run(strings.Split("serviced datastore migrate", " "))
+		})
+	})
+	s.api.AssertExpectations(c)
+	c.Assert(string(output), Equals, "")
+	c.Assert(string(errout), Equals, "datastore is in use\n")
+}
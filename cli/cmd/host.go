@@ -19,6 +19,7 @@ import (
 	"io/ioutil"
 	"net"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/codegangsta/cli"
@@ -115,6 +116,12 @@ func (c *ServicedCli) initHost() {
 				Description:  "serviced host set-memory HOSTID ALLOCATION",
 				BashComplete: c.printHostsAll,
 				Action:       c.cmdHostSetMemory,
+			}, {
+				Name:         "set-labels",
+				Usage:        "Set or remove labels of a specific host",
+				Description:  "serviced host set-labels HOSTID KEY=VALUE|KEY- ...",
+				BashComplete: c.printHostsFirst,
+				Action:       c.cmdHostSetLabels,
 			},
 		},
 	})
@@ -238,6 +245,7 @@ func (c *ServicedCli) cmdHostList(ctx *cli.Context) {
 				"Cur/Max/Avg": usage,
 				"Network":     h.PrivateNetwork,
 				"Release":     h.ServiceD.Release,
+				"Labels":      formatLabels(h.Labels),
 			})
 		}
 		t.Padding = 6
@@ -245,6 +253,16 @@ func (c *ServicedCli) cmdHostList(ctx *cli.Context) {
 	}
 }
 
+// formatLabels returns the labels as KEY=VALUE pairs, sorted by key
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
 // serviced host add HOST:PORT POOLID [--memory SIZE|%] [--nat-address HOST:PORT]
 func (c *ServicedCli) cmdHostAdd(ctx *cli.Context) {
 	args := ctx.Args()
@@ -419,6 +437,36 @@ func (c *ServicedCli) cmdHostSetMemory(ctx *cli.Context) {
 	}
 }
 
+// serviced host set-labels HOSTID KEY=VALUE|KEY- ...
+func (c *ServicedCli) cmdHostSetLabels(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set-labels")
+		return
+	}
+
+	config := api.HostLabelConfig{HostID: args[0], Set: make(map[string]string)}
+	for _, arg := range args[1:] {
+		if strings.HasSuffix(arg, "-") && !strings.Contains(arg, "=") {
+			config.Unset = append(config.Unset, strings.TrimSuffix(arg, "-"))
+			continue
+		}
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 {
+			fmt.Fprintf(os.Stderr, "invalid label %s; use KEY=VALUE to set a label or KEY- to remove it\n", arg)
+			c.exit(1)
+			return
+		}
+		config.Set[parts[0]] = parts[1]
+	}
+
+	if err := c.driver.SetHostLabels(config); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+	}
+}
+
 // serviced host register (KEYSFILE | -)
 func (c *ServicedCli) cmdHostRegister(ctx *cli.Context) {
 	args := ctx.Args()
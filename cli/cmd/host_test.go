@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"testing"
 
 	"github.com/control-center/serviced/cli/api"
@@ -129,6 +130,26 @@ func (t HostAPITest) RemoveHost(id string) error {
 	return nil
 }
 
+func (t HostAPITest) SetHostLabels(config api.HostLabelConfig) error {
+	if h, err := t.GetHost(config.HostID); err != nil {
+		return err
+	} else if h == nil {
+		return ErrNoHostFound
+	}
+	keys := []string{}
+	for key := range config.Set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Printf("set %s=%s\n", key, config.Set[key])
+	}
+	for _, key := range config.Unset {
+		fmt.Printf("unset %s\n", key)
+	}
+	return nil
+}
+
 func (t HostAPITest) RegisterRemoteHost(h *host.Host, nat utils.URL, data []byte, prompt bool) error {
 	if t.registerFail {
 		return errors.New("Forcing RemoteRegisterHost to fail for testing")
@@ -418,6 +439,33 @@ func ExampleServicedCLI_CmdHostRemove_complete() {
 	// test-host-id-3
 }
 
+func ExampleServicedCLI_CmdHostSetLabels() {
+	InitHostAPITest("serviced", "host", "set-labels", "test-host-id-1", "ssd=true", "zone=", "rack-")
+
+	// Output:
+	// set ssd=true
+	// set zone=
+	// unset rack
+}
+
+func ExampleServicedCLI_CmdHostSetLabels_usage() {
+	InitHostAPITest("serviced", "host", "set-labels", "test-host-id-1")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    set-labels - Set or remove labels of a specific host
+	//
+	// USAGE:
+	//    command set-labels [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced host set-labels HOSTID KEY=VALUE|KEY- ...
+	//
+	// OPTIONS:
+}
+
 func ExampleServicedCLI_CmdHostRegister_usage() {
 	InitHostAPITest("serviced", "host", "register")
 
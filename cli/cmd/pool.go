@@ -16,6 +16,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -23,6 +24,8 @@ import (
 	"github.com/codegangsta/cli"
 	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/scheduler/simulation"
+	"github.com/pivotal-golang/bytefmt"
 )
 
 // Initializer for serviced pool subcommands
@@ -108,6 +111,57 @@ func (c *ServicedCli) initPool() {
 				Description:  "serviced pool set-conn-timeout POOLID TIMEOUT",
 				BashComplete: c.printPoolsFirst,
 				Action:       c.cmdSetConnTimeout,
+			}, {
+				Name:         "simulate",
+				Usage:        "Show where the scheduler would place instances after hypothetical changes to a pool",
+				Description:  "serviced pool simulate POOLID [--template FILE | --service-changes FILE]",
+				BashComplete: c.printPoolsFirst,
+				Action:       c.cmdPoolSimulate,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "template",
+						Value: "",
+						Usage: "Template file to deploy and start in the pool",
+					},
+					cli.StringFlag{
+						Name:  "service-changes",
+						Value: "",
+						Usage: "JSON file of hosts to add or remove and service settings to change",
+					},
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+				},
+			}, {
+				Name:         "rebalance",
+				Usage:        "Move running instances to the hosts the scheduler would choose for them now",
+				Description:  "serviced pool rebalance POOLID [--dry-run]",
+				BashComplete: c.printPoolsFirst,
+				Action:       c.cmdPoolRebalance,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Show the instances that would be moved without moving them",
+					},
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+				},
+			}, {
+				Name:         "set-rebalance",
+				Usage:        "Set whether the pool leader rebalances a pool on its own",
+				Description:  "serviced pool set-rebalance POOLID automatic|manual [--disruption-budget N]",
+				BashComplete: c.printPoolsFirst,
+				Action:       c.cmdSetRebalance,
+				Flags: []cli.Flag{
+					cli.IntFlag{
+						Name:  "disruption-budget",
+						Value: 0,
+						Usage: "Most instances of a service that may be unavailable while rebalancing (0 means 1)",
+					},
+				},
 			}, {
 				Name:         "set-permission",
 				Usage:        "Set permission flags for hosts in a pool",
@@ -444,3 +498,203 @@ func (c *ServicedCli) cmdSetPermission(ctx *cli.Context) {
 		return
 	}
 }
+
+// serviced pool simulate POOLID [--template FILE | --service-changes FILE]
+func (c *ServicedCli) cmdPoolSimulate(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "simulate")
+		return
+	}
+
+	var changes, template io.Reader
+	if filename := ctx.String("service-changes"); filename != "" {
+		file, err := os.Open(filename)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		defer file.Close()
+		changes = file
+	}
+	if filename := ctx.String("template"); filename != "" {
+		file, err := os.Open(filename)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		defer file.Close()
+		template = file
+	}
+
+	plan, err := c.driver.SimulatePool(args[0], changes, template)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonPlan, err := json.MarshalIndent(plan, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal simulation: %s", err)
+		} else {
+			fmt.Println(string(jsonPlan))
+		}
+		return
+	}
+
+	hostNames := make(map[string]string)
+	for _, h := range plan.Hosts {
+		hostNames[h.HostID] = h.Name
+	}
+
+	if len(plan.Placements) == 0 {
+		fmt.Println("No instances need to be placed")
+	} else {
+		fmt.Println("Proposed placement:")
+		t := NewTable("Service,ServiceID,Instance,Host,HostName,Oversubscribed")
+		for _, p := range plan.Placements {
+			t.AddRow(map[string]interface{}{
+				"Service":        p.ServiceName,
+				"ServiceID":      p.ServiceID,
+				"Instance":       p.InstanceID,
+				"Host":           p.HostID,
+				"HostName":       hostNames[p.HostID],
+				"Oversubscribed": p.Oversubscribed,
+			})
+		}
+		t.Padding = 6
+		t.Print()
+	}
+
+	fmt.Println()
+	printHostCommitments(plan)
+
+	if len(plan.Unplaced) > 0 {
+		fmt.Println()
+		fmt.Println("Instances that could not be placed:")
+		t := NewTable("Service,ServiceID,Instance,Reason")
+		for _, u := range plan.Unplaced {
+			t.AddRow(map[string]interface{}{
+				"Service":   u.ServiceName,
+				"ServiceID": u.ServiceID,
+				"Instance":  u.InstanceID,
+				"Reason":    u.Reason,
+			})
+		}
+		t.Padding = 6
+		t.Print()
+	}
+}
+
+// printHostCommitments prints the load of each host at the end of a
+// simulation
+func printHostCommitments(plan *simulation.Plan) {
+	fmt.Println("Host commitments:")
+	t := NewTable("Host,Name,Cores,RAM,Instances")
+	for _, h := range plan.Hosts {
+		t.AddRow(map[string]interface{}{
+			"Host":      h.HostID,
+			"Name":      h.Name,
+			"Cores":     fmt.Sprintf("%d / %d", h.CPUCommitment, h.Cores),
+			"RAM":       fmt.Sprintf("%s / %s", bytefmt.ByteSize(h.RAMCommitment), bytefmt.ByteSize(h.Memory)),
+			"Instances": h.Instances,
+		})
+	}
+	t.Padding = 6
+	t.Print()
+}
+
+// serviced pool rebalance POOLID [--dry-run]
+func (c *ServicedCli) cmdPoolRebalance(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "rebalance")
+		return
+	}
+
+	dryRun := ctx.Bool("dry-run")
+	plan, err := c.driver.RebalancePool(args[0], dryRun)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonPlan, err := json.MarshalIndent(plan, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal rebalance: %s", err)
+		} else {
+			fmt.Println(string(jsonPlan))
+		}
+		return
+	}
+
+	if len(plan.Moves) == 0 {
+		fmt.Println("No instances need to be moved")
+	} else {
+		hostNames := make(map[string]string)
+		for _, h := range plan.Hosts {
+			hostNames[h.HostID] = h.Name
+		}
+		fmt.Println("Proposed moves:")
+		t := NewTable("Service,ServiceID,Instance,From,To")
+		for _, m := range plan.Moves {
+			t.AddRow(map[string]interface{}{
+				"Service":   m.ServiceName,
+				"ServiceID": m.ServiceID,
+				"Instance":  m.InstanceID,
+				"From":      hostNames[m.FromHostID],
+				"To":        hostNames[m.ToHostID],
+			})
+		}
+		t.Padding = 6
+		t.Print()
+		fmt.Println()
+		printHostCommitments(plan)
+	}
+
+	if !dryRun {
+		fmt.Println()
+		fmt.Printf("Requested a rebalance of pool %s; instances are moved one at a time\n", args[0])
+	}
+}
+
+// serviced pool set-rebalance POOLID automatic|manual [--disruption-budget N]
+func (c *ServicedCli) cmdSetRebalance(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "set-rebalance")
+		return
+	}
+
+	var automatic bool
+	switch args[1] {
+	case "automatic":
+		automatic = true
+	case "manual":
+		automatic = false
+	default:
+		fmt.Fprintln(os.Stderr, "rebalance mode must be automatic or manual")
+		return
+	}
+
+	pool, err := c.driver.GetResourcePool(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if pool == nil {
+		fmt.Fprintln(os.Stderr, "pool not found")
+		return
+	}
+
+	pool.Rebalance.Automatic = automatic
+	if ctx.IsSet("disruption-budget") {
+		pool.Rebalance.DisruptionBudget = ctx.Int("disruption-budget")
+	}
+	if err := c.driver.UpdateResourcePool(*pool); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+}
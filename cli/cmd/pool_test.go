@@ -19,12 +19,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
 	"testing"
 
 	"github.com/control-center/serviced/cli/api"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/scheduler/simulation"
 	"github.com/control-center/serviced/utils"
 )
 
@@ -171,6 +173,54 @@ func (t PoolAPITest) UpdateResourcePool(pool pool.ResourcePool) error {
 	return ErrInvalidPool
 }
 
+func (t PoolAPITest) SimulatePool(id string, changes, template io.Reader) (*simulation.Plan, error) {
+	if t.fail {
+		return nil, ErrInvalidPool
+	}
+	p, err := t.GetResourcePool(id)
+	if err != nil {
+		return nil, err
+	} else if p == nil {
+		return nil, ErrNoPoolFound
+	}
+	return &simulation.Plan{
+		PoolID: p.ID,
+		Placements: []simulation.Placement{
+			{ServiceID: "svc-id-1", ServiceName: "web", InstanceID: 1, HostID: "test-host-id-1"},
+			{ServiceID: "svc-id-1", ServiceName: "web", InstanceID: 2, HostID: "test-host-id-2", Oversubscribed: true},
+		},
+		Unplaced: []simulation.Unplaced{
+			{ServiceID: "svc-id-2", ServiceName: "db", InstanceID: 0, Reason: "no host is available under the REQUIRE_SEPARATE strategy"},
+		},
+		Hosts: []simulation.HostCommitment{
+			{HostID: "test-host-id-1", Name: "one", Cores: 4, Memory: 8 * 1024 * 1024 * 1024, CPUCommitment: 2, RAMCommitment: 2 * 1024 * 1024 * 1024, Instances: 2},
+			{HostID: "test-host-id-2", Name: "two", Cores: 2, Memory: 1024 * 1024 * 1024, CPUCommitment: 1, RAMCommitment: 2 * 1024 * 1024 * 1024, Instances: 1},
+		},
+	}, nil
+}
+
+func (t PoolAPITest) RebalancePool(id string, dryRun bool) (*simulation.Plan, error) {
+	p, err := t.GetResourcePool(id)
+	if err != nil {
+		return nil, err
+	} else if p == nil {
+		return nil, ErrNoPoolFound
+	}
+	plan := &simulation.Plan{
+		PoolID: p.ID,
+		Hosts: []simulation.HostCommitment{
+			{HostID: "test-host-id-1", Name: "one", Cores: 4, Memory: 8 * 1024 * 1024 * 1024, CPUCommitment: 1, RAMCommitment: 1024 * 1024 * 1024, Instances: 1},
+			{HostID: "test-host-id-2", Name: "two", Cores: 4, Memory: 8 * 1024 * 1024 * 1024, CPUCommitment: 1, RAMCommitment: 1024 * 1024 * 1024, Instances: 1},
+		},
+	}
+	if p.ID == "test-pool-id-1" {
+		plan.Moves = []simulation.Move{
+			{ServiceID: "svc-id-1", ServiceName: "web", InstanceID: 1, FromHostID: "test-host-id-1", ToHostID: "test-host-id-2"},
+		}
+	}
+	return plan, nil
+}
+
 func TestServicedCLI_CmdPoolList_one(t *testing.T) {
 	poolID := "test-pool-id-1"
 
@@ -415,6 +465,107 @@ func ExampleServicedCLI_CmdPoolListIPs_err() {
 	// no resource pool IPs found
 }
 
+func ExampleServicedCLI_CmdPoolSimulate() {
+	RunCmd(DefaultPoolAPI(), "serviced", "pool", "simulate", "test-pool-id-1")
+
+	// Output:
+	// Proposed placement:
+	// Service      ServiceID      Instance      Host                HostName      Oversubscribed
+	// web          svc-id-1       1             test-host-id-1      one           false
+	// web          svc-id-1       2             test-host-id-2      two           true
+	//
+	// Host commitments:
+	// Host                Name      Cores      RAM          Instances
+	// test-host-id-1      one       2 / 4      2G / 8G      2
+	// test-host-id-2      two       1 / 2      2G / 1G      1
+	//
+	// Instances that could not be placed:
+	// Service      ServiceID      Instance      Reason
+	// db           svc-id-2       0             no host is available under the REQUIRE_SEPARATE strategy
+}
+
+func ExampleServicedCLI_CmdPoolSimulate_usage() {
+	RunCmd(DefaultPoolAPI(), "serviced", "pool", "simulate")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    simulate - Show where the scheduler would place instances after hypothetical changes to a pool
+	//
+	// USAGE:
+	//    command simulate [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced pool simulate POOLID [--template FILE | --service-changes FILE]
+	//
+	// OPTIONS:
+	//    --template 		Template file to deploy and start in the pool
+	//    --service-changes 	JSON file of hosts to add or remove and service settings to change
+	//    --verbose, -v	Show JSON format
+}
+
+func ExampleServicedCLI_CmdPoolSimulate_fail() {
+	pipeStderr(func() { RunCmd(DefaultPoolAPI(), "serviced", "pool", "simulate", "test-pool-id-0") })
+
+	// Output:
+	// no pool found
+}
+
+func ExampleServicedCLI_CmdPoolRebalance_dryRun() {
+	RunCmd(DefaultPoolAPI(), "serviced", "pool", "rebalance", "--dry-run", "test-pool-id-1")
+
+	// Output:
+	// Proposed moves:
+	// Service      ServiceID      Instance      From      To
+	// web          svc-id-1       1             one       two
+	//
+	// Host commitments:
+	// Host                Name      Cores      RAM          Instances
+	// test-host-id-1      one       1 / 4      1G / 8G      1
+	// test-host-id-2      two       1 / 4      1G / 8G      1
+}
+
+func ExampleServicedCLI_CmdPoolRebalance() {
+	RunCmd(DefaultPoolAPI(), "serviced", "pool", "rebalance", "test-pool-id-2")
+
+	// Output:
+	// No instances need to be moved
+	//
+	// Requested a rebalance of pool test-pool-id-2; instances are moved one at a time
+}
+
+func ExampleServicedCLI_CmdPoolRebalance_fail() {
+	pipeStderr(func() { RunCmd(DefaultPoolAPI(), "serviced", "pool", "rebalance", "test-pool-id-0") })
+
+	// Output:
+	// no pool found
+}
+
+func TestServicedCLI_CmdPoolSetRebalance(t *testing.T) {
+	test := DefaultPoolAPI()
+
+	RunCmd(test, "serviced", "pool", "set-rebalance", "--disruption-budget", "2", "test-pool-id-1", "automatic")
+	p, _ := test.GetResourcePool("test-pool-id-1")
+	if !p.Rebalance.Automatic || p.Rebalance.DisruptionBudget != 2 {
+		t.Fatalf("unexpected rebalance policy: %+v", p.Rebalance)
+	}
+
+	// the budget is kept unless it is set
+	RunCmd(test, "serviced", "pool", "set-rebalance", "test-pool-id-1", "manual")
+	p, _ = test.GetResourcePool("test-pool-id-1")
+	if p.Rebalance.Automatic || p.Rebalance.DisruptionBudget != 2 {
+		t.Fatalf("unexpected rebalance policy: %+v", p.Rebalance)
+	}
+}
+
+func ExampleServicedCLI_CmdPoolSetRebalance_badMode() {
+	pipeStderr(func() { RunCmd(DefaultPoolAPI(), "serviced", "pool", "set-rebalance", "test-pool-id-1", "sometimes") })
+
+	// Output:
+	// rebalance mode must be automatic or manual
+}
+
 func TestServicedCLI_CmdPoolSetPermission(t *testing.T) {
 	test := EmptyPoolAPI()
 	assertPerm := func(poolID string, expected pool.Permission) {
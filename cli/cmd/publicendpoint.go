@@ -21,6 +21,7 @@ import (
 
 	"github.com/codegangsta/cli"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
 )
 
 // The vhost and port public endpoint structures are different, so we'll
@@ -467,3 +468,128 @@ func (c *ServicedCli) cmdPublicEndpointsVHostEnable(ctx *cli.Context) {
 	}
 	return
 }
+
+// Show or set the access policy of a port public endpoint
+// serviced service public-endpoints port access <SERVICEID> <ENDPOINTNAME> <PORTADDR>
+func (c *ServicedCli) cmdPublicEndpointsPortAccess(ctx *cli.Context) {
+	// Make sure we have each argument.
+	if len(ctx.Args()) != 3 {
+		cli.ShowCommandHelp(ctx, "access")
+		return
+	}
+
+	serviceid := ctx.Args()[0]
+	endpointName := ctx.Args()[1]
+	portAddr := service.ScrubPortString(ctx.Args()[2])
+
+	svc, ok := c.getPublicEndpointService(ctx, serviceid)
+	if !ok {
+		return
+	}
+	port := svc.GetPort(endpointName, portAddr)
+	if port == nil {
+		fmt.Fprintf(os.Stderr, "port %s not found\n", portAddr)
+		return
+	}
+
+	access, changed := accessPolicyFromFlags(ctx, port.Access)
+	if !changed {
+		printAccessPolicy(port.Access)
+		return
+	}
+
+	if err := c.driver.SetPublicEndpointPortAccess(svc.ID, endpointName, portAddr, access); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+	} else {
+		fmt.Printf("%s\n", portAddr)
+	}
+}
+
+// Show or set the access policy of a vhost public endpoint
+// serviced service public-endpoints vhost access <SERVICEID> <ENDPOINTNAME> <VHOST>
+func (c *ServicedCli) cmdPublicEndpointsVHostAccess(ctx *cli.Context) {
+	// Make sure we have each argument.
+	if len(ctx.Args()) != 3 {
+		cli.ShowCommandHelp(ctx, "access")
+		return
+	}
+
+	serviceid := ctx.Args()[0]
+	endpointName := ctx.Args()[1]
+	vhostName := ctx.Args()[2]
+
+	svc, ok := c.getPublicEndpointService(ctx, serviceid)
+	if !ok {
+		return
+	}
+	vhost := svc.GetVirtualHost(endpointName, vhostName)
+	if vhost == nil {
+		fmt.Fprintf(os.Stderr, "vhost %s not found\n", vhostName)
+		return
+	}
+
+	access, changed := accessPolicyFromFlags(ctx, vhost.Access)
+	if !changed {
+		printAccessPolicy(vhost.Access)
+		return
+	}
+
+	if err := c.driver.SetPublicEndpointVHostAccess(svc.ID, endpointName, vhost.Name, access); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+	} else {
+		fmt.Printf("%s\n", vhost.Name)
+	}
+}
+
+// getPublicEndpointService looks up the service, with its endpoints, by id or
+// name
+func (c *ServicedCli) getPublicEndpointService(ctx *cli.Context, serviceid string) (*service.Service, bool) {
+	details, _, err := c.searchForService(serviceid, ctx.Bool("no-prefix-match"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return nil, false
+	}
+	svc, err := c.driver.GetService(details.ID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return nil, false
+	} else if svc == nil {
+		fmt.Fprintln(os.Stderr, "service not found")
+		return nil, false
+	}
+	return svc, true
+}
+
+// accessPolicyFromFlags applies the access flags to a copy of the current
+// policy and returns false if no flags were set
+func accessPolicyFromFlags(ctx *cli.Context, current *servicedefinition.AccessPolicy) (*servicedefinition.AccessPolicy, bool) {
+	access := &servicedefinition.AccessPolicy{}
+	if current != nil && !ctx.Bool("clear") {
+		*access = *current
+	}
+	changed := ctx.Bool("clear")
+	if ctx.IsSet("allow") {
+		access.Allow = ctx.StringSlice("allow")
+		changed = true
+	}
+	if ctx.IsSet("deny") {
+		access.Deny = ctx.StringSlice("deny")
+		changed = true
+	}
+	if ctx.IsSet("rate-limit") {
+		access.RateLimit = ctx.Int("rate-limit")
+		changed = true
+	}
+	return access, changed
+}
+
+func printAccessPolicy(access *servicedefinition.AccessPolicy) {
+	if access == nil {
+		access = &servicedefinition.AccessPolicy{}
+	}
+	if jsonAccess, err := json.MarshalIndent(access, " ", "  "); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to marshal access policy: %s", err)
+	} else {
+		fmt.Println(string(jsonAccess))
+	}
+}
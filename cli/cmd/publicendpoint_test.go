@@ -96,6 +96,21 @@ func (t ServiceAPITest) EnablePublicEndpointVHost(serviceID, endpointName, vhost
 	return nil
 }
 
+func (t ServiceAPITest) SetPublicEndpointPortAccess(serviceID, endpointName, portAddr string, access *servicedefinition.AccessPolicy) error {
+	if t.errs["SetPublicEndpointPortAccess"] != nil {
+		return t.errs["SetPublicEndpointPortAccess"]
+	}
+	fmt.Printf("allow=%v deny=%v rate-limit=%d\n", access.Allow, access.Deny, access.RateLimit)
+	return nil
+}
+
+func (t ServiceAPITest) SetPublicEndpointVHostAccess(serviceID, endpointName, vhost string, access *servicedefinition.AccessPolicy) error {
+	if t.errs["SetPublicEndpointVHostAccess"] != nil {
+		return t.errs["SetPublicEndpointVHostAccess"]
+	}
+	return nil
+}
+
 func InitPublicEndpointPortTest(args ...string) {
 	c := New(DefaultServiceAPITest, utils.TestConfigReader(make(map[string]string)), MockLogControl{})
 	c.exitDisabled = true
@@ -460,3 +475,36 @@ func ExampleServicedCLI_CmdPublicEndpointsVHostEnable_ValidFlags() {
 	// zproxy
 	// zproxy
 }
+
+func ExampleServicedCLI_CmdPublicEndpointsPortAccess_Show() {
+	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "port", "access", "Zenoss", "zproxy", "22222")
+
+	// Output:
+	// {}
+}
+
+func ExampleServicedCLI_CmdPublicEndpointsPortAccess_Set() {
+	InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "port", "access", "--allow", "10.0.0.0/8", "--allow", "192.168.1.1", "--rate-limit", "120", "Zenoss", "zproxy", ":22222")
+
+	// Output:
+	// allow=[10.0.0.0/8 192.168.1.1] deny=[] rate-limit=120
+	// :22222
+}
+
+func ExampleServicedCLI_CmdPublicEndpointsPortAccess_InvalidPort() {
+	pipeStderr(func() {
+		InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "port", "access", "--clear", "Zenoss", "zproxy", ":33333")
+	})
+
+	// Output:
+	// port :33333 not found
+}
+
+func ExampleServicedCLI_CmdPublicEndpointsVHostAccess_InvalidVHost() {
+	pipeStderr(func() {
+		InitPublicEndpointPortTest("serviced", "service", "public-endpoints", "vhost", "access", "Zenoss", "zproxy", "missing")
+	})
+
+	// Output:
+	// vhost missing not found
+}
@@ -0,0 +1,292 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/schedule"
+)
+
+// Initializer for serviced schedule subcommands
+func (c *ServicedCli) initSchedule() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "schedule",
+		Usage:       "Administers scheduled snapshots and backups",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:         "list",
+				Usage:        "Lists the snapshot and backup schedules",
+				Description:  "serviced schedule list",
+				BashComplete: nil,
+				Action:       c.cmdScheduleList,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+					cli.StringFlag{
+						Name:  "show-fields",
+						Value: "ID,Kind,TenantID,Cron,Retention,Enabled,LastRun,LastOutcome,NextRun",
+						Usage: "Comma-delimited list describing which fields to display",
+					},
+				},
+			}, {
+				Name:         "add",
+				Usage:        "Adds a snapshot or backup schedule",
+				Description:  "serviced schedule add snapshot TENANTID CRON | serviced schedule add backup [TENANTID] CRON",
+				BashComplete: nil,
+				Action:       c.cmdScheduleAdd,
+				Flags: []cli.Flag{
+					cli.IntFlag{
+						Name:  "keep-hourly",
+						Usage: "Number of hours to keep the newest snapshot or backup of",
+					},
+					cli.IntFlag{
+						Name:  "keep-daily",
+						Usage: "Number of days to keep the newest snapshot or backup of",
+					},
+					cli.IntFlag{
+						Name:  "keep-weekly",
+						Usage: "Number of weeks to keep the newest snapshot or backup of",
+					},
+					cli.IntFlag{
+						Name:  "keep-monthly",
+						Usage: "Number of months to keep the newest snapshot or backup of",
+					},
+					cli.IntFlag{
+						Name:  "keep-yearly",
+						Usage: "Number of years to keep the newest snapshot or backup of",
+					},
+					cli.StringFlag{
+						Name:  "target",
+						Value: "",
+						Usage: "Directory or url to write backups to, instead of the backup target of the master",
+					},
+					cli.BoolFlag{
+						Name:  "disabled",
+						Usage: "Add the schedule without enabling it",
+					},
+				},
+			}, {
+				Name:         "status",
+				Usage:        "Shows the runs of a schedule",
+				Description:  "serviced schedule status SCHEDULEID",
+				BashComplete: c.printSchedules,
+				Action:       c.cmdScheduleStatus,
+			}, {
+				Name:         "enable",
+				Usage:        "Enables schedules",
+				Description:  "serviced schedule enable SCHEDULEID ...",
+				BashComplete: c.printSchedules,
+				Action:       c.cmdScheduleEnable,
+			}, {
+				Name:         "disable",
+				Usage:        "Disables schedules",
+				Description:  "serviced schedule disable SCHEDULEID ...",
+				BashComplete: c.printSchedules,
+				Action:       c.cmdScheduleDisable,
+			}, {
+				Name:         "remove",
+				ShortName:    "rm",
+				Usage:        "Removes schedules, keeping the snapshots and backups they took",
+				Description:  "serviced schedule remove SCHEDULEID ...",
+				BashComplete: c.printSchedules,
+				Action:       c.cmdScheduleRemove,
+			},
+		},
+	})
+}
+
+// Bash-completion command that prints the ids of the schedules
+func (c *ServicedCli) printSchedules(ctx *cli.Context) {
+	schedules, err := c.driver.GetSchedules()
+	if err != nil {
+		return
+	}
+	for _, s := range schedules {
+		fmt.Println(s.ID)
+	}
+}
+
+// formatScheduleTime formats the time of a run, if there is one
+func formatScheduleTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// serviced schedule list
+func (c *ServicedCli) cmdScheduleList(ctx *cli.Context) {
+	schedules, err := c.driver.GetSchedules()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if schedules == nil || len(schedules) == 0 {
+		fmt.Fprintln(os.Stderr, "no schedules found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonSchedules, err := json.MarshalIndent(schedules, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal schedule list: %s", err)
+		} else {
+			fmt.Println(string(jsonSchedules))
+		}
+	} else {
+		t := NewTable(ctx.String("show-fields"))
+		for _, s := range schedules {
+			t.AddRow(map[string]interface{}{
+				"ID":          s.ID,
+				"Kind":        s.Kind,
+				"TenantID":    s.TenantID,
+				"Cron":        s.Cron,
+				"Retention":   s.Retention.String(),
+				"Target":      s.Target,
+				"Enabled":     !s.Disabled,
+				"LastRun":     formatScheduleTime(s.Status.LastRun),
+				"LastOutcome": s.Status.LastOutcome,
+				"LastError":   s.Status.LastError,
+				"NextRun":     formatScheduleTime(s.Status.NextRun),
+				"Kept":        len(s.Status.Artifacts),
+			})
+		}
+		t.Print()
+	}
+}
+
+// serviced schedule add snapshot TENANTID CRON | serviced schedule add backup [TENANTID] CRON
+func (c *ServicedCli) cmdScheduleAdd(ctx *cli.Context) {
+	args := ctx.Args()
+	s := schedule.Schedule{
+		Kind: schedule.Kind(args.First()),
+		Retention: schedule.Retention{
+			Hourly:  ctx.Int("keep-hourly"),
+			Daily:   ctx.Int("keep-daily"),
+			Weekly:  ctx.Int("keep-weekly"),
+			Monthly: ctx.Int("keep-monthly"),
+			Yearly:  ctx.Int("keep-yearly"),
+		},
+		Target:   ctx.String("target"),
+		Disabled: ctx.Bool("disabled"),
+	}
+	switch {
+	case s.Kind == schedule.KindSnapshot && len(args) == 3:
+		s.TenantID, s.Cron = args[1], args[2]
+	case s.Kind == schedule.KindBackup && len(args) == 2:
+		s.Cron = args[1]
+	case s.Kind == schedule.KindBackup && len(args) == 3:
+		s.TenantID, s.Cron = args[1], args[2]
+	default:
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "add")
+		return
+	}
+
+	if scheduleID, err := c.driver.AddSchedule(s); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+	} else {
+		fmt.Println(scheduleID)
+	}
+}
+
+// serviced schedule status SCHEDULEID
+func (c *ServicedCli) cmdScheduleStatus(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "status")
+		return
+	}
+
+	s, err := c.driver.GetSchedule(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	fmt.Printf("Schedule:  %s %s (%s)\n", s.Kind, s.Cron, s.Retention)
+	fmt.Printf("Enabled:   %t\n", !s.Disabled)
+	if s.Status.LastRun.IsZero() {
+		fmt.Println("Last run:  never")
+	} else {
+		fmt.Printf("Last run:  %s %s\n", formatScheduleTime(s.Status.LastRun), s.Status.LastOutcome)
+	}
+	if s.Status.LastError != "" {
+		fmt.Printf("Error:     %s\n", s.Status.LastError)
+	}
+	if !s.Disabled {
+		fmt.Printf("Next run:  %s\n", formatScheduleTime(s.Status.NextRun))
+	}
+	for _, a := range s.Status.Artifacts {
+		fmt.Printf("Kept:      %s %s\n", formatScheduleTime(a.Created), a.ID)
+	}
+}
+
+// serviced schedule enable SCHEDULEID ...
+func (c *ServicedCli) cmdScheduleEnable(ctx *cli.Context) {
+	c.setSchedulesDisabled(ctx, "enable", false)
+}
+
+// serviced schedule disable SCHEDULEID ...
+func (c *ServicedCli) cmdScheduleDisable(ctx *cli.Context) {
+	c.setSchedulesDisabled(ctx, "disable", true)
+}
+
+func (c *ServicedCli) setSchedulesDisabled(ctx *cli.Context, command string, disabled bool) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, command)
+		return
+	}
+
+	for _, scheduleID := range args {
+		s, err := c.driver.GetSchedule(scheduleID)
+		if err == nil {
+			s.Disabled = disabled
+			err = c.driver.UpdateSchedule(*s)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", scheduleID, err)
+		} else {
+			fmt.Println(scheduleID)
+		}
+	}
+}
+
+// serviced schedule remove SCHEDULEID ...
+func (c *ServicedCli) cmdScheduleRemove(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "remove")
+		return
+	}
+
+	for _, scheduleID := range args {
+		if err := c.driver.RemoveSchedule(scheduleID); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", scheduleID, err)
+		} else {
+			fmt.Println(scheduleID)
+		}
+	}
+}
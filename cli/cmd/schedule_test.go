@@ -0,0 +1,142 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/schedule"
+	"github.com/control-center/serviced/utils"
+)
+
+var ErrNoScheduleFound = errors.New("no schedule found")
+
+type ScheduleAPITest struct {
+	api.API
+	schedules []schedule.Schedule
+}
+
+func DefaultScheduleAPI() ScheduleAPITest {
+	lastRun := time.Date(2019, 3, 1, 2, 0, 0, 0, time.Local)
+	return ScheduleAPITest{
+		schedules: []schedule.Schedule{
+			{
+				ID: "sched1", Kind: schedule.KindSnapshot, TenantID: "tenant1", Cron: "0 2 * * *",
+				Retention: schedule.Retention{Daily: 7, Weekly: 4},
+				Status: schedule.Status{
+					LastRun:     lastRun,
+					LastOutcome: schedule.OutcomeSucceeded,
+					NextRun:     lastRun.AddDate(0, 0, 1),
+					Artifacts:   []schedule.Artifact{{ID: "tenant1_snap", Created: lastRun}},
+				},
+			}, {
+				ID: "sched2", Kind: schedule.KindBackup, Cron: "@weekly", Disabled: true,
+				Status: schedule.Status{LastRun: lastRun, LastOutcome: schedule.OutcomeFailed, LastError: "not enough space"},
+			},
+		},
+	}
+}
+
+func (t ScheduleAPITest) GetSchedules() ([]schedule.Schedule, error) {
+	return t.schedules, nil
+}
+
+func (t ScheduleAPITest) GetSchedule(scheduleID string) (*schedule.Schedule, error) {
+	for _, s := range t.schedules {
+		if s.ID == scheduleID {
+			return &s, nil
+		}
+	}
+	return nil, ErrNoScheduleFound
+}
+
+func (t ScheduleAPITest) AddSchedule(s schedule.Schedule) (string, error) {
+	if err := s.UpdateNextRun(time.Now()); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%s/%s/%s", s.Kind, s.TenantID, s.Cron, s.Retention), nil
+}
+
+func (t ScheduleAPITest) UpdateSchedule(s schedule.Schedule) error {
+	fmt.Printf("disabled=%t ", s.Disabled)
+	return nil
+}
+
+func (t ScheduleAPITest) RemoveSchedule(scheduleID string) error {
+	_, err := t.GetSchedule(scheduleID)
+	return err
+}
+
+func InitScheduleAPITestNoExit(args ...string) {
+	c := New(DefaultScheduleAPI(), utils.TestConfigReader{}, MockLogControl{})
+	c.exitDisabled = true
+	c.Run(args)
+}
+
+func ExampleServicedCLI_CmdScheduleList() {
+	RunCmd(DefaultScheduleAPI(), "serviced", "schedule", "list", "--show-fields", "ID,Kind,Cron,Retention,Enabled,LastOutcome")
+
+	// Output:
+	// ID     Kind     Cron      Retention         Enabled LastOutcome
+	// sched1 snapshot 0 2 * * * 7 daily, 4 weekly true    succeeded
+	// sched2 backup   @weekly   keep all          false   failed
+}
+
+func ExampleServicedCLI_CmdScheduleAdd() {
+	RunCmd(DefaultScheduleAPI(), "serviced", "schedule", "add", "--keep-daily", "7", "--keep-monthly", "6", "snapshot", "tenant1", "0 2 * * *")
+	RunCmd(DefaultScheduleAPI(), "serviced", "schedule", "add", "backup", "@daily")
+	RunCmd(DefaultScheduleAPI(), "serviced", "schedule", "add", "backup", "tenant1", "@daily")
+
+	// Output:
+	// snapshot/tenant1/0 2 * * */7 daily, 6 monthly
+	// backup//@daily/keep all
+	// backup/tenant1/@daily/keep all
+}
+
+func ExampleServicedCLI_CmdScheduleAdd_invalid() {
+	pipeStderr(func() { InitScheduleAPITestNoExit("serviced", "schedule", "add", "backup", "sometimes") })
+
+	// Output:
+	// cron expression "sometimes" must have 5 fields
+}
+
+func ExampleServicedCLI_CmdScheduleStatus() {
+	RunCmd(DefaultScheduleAPI(), "serviced", "schedule", "status", "sched2")
+
+	// Output:
+	// Schedule:  backup @weekly (keep all)
+	// Enabled:   false
+	// Last run:  2019-03-01 02:00:00 failed
+	// Error:     not enough space
+}
+
+func ExampleServicedCLI_CmdScheduleEnable() {
+	RunCmd(DefaultScheduleAPI(), "serviced", "schedule", "enable", "sched2")
+
+	// Output:
+	// disabled=false sched2
+}
+
+func ExampleServicedCLI_CmdScheduleRemove() {
+	pipeStderr(func() { RunCmd(DefaultScheduleAPI(), "serviced", "schedule", "remove", "sched1", "sched3") })
+
+	// Output:
+	// sched1
+	// sched3: no schedule found
+}
@@ -46,7 +46,7 @@ func (c *ServicedCli) initScript() {
 			{
 				Name:        "run",
 				Usage:       "Run a script",
-				Description: "serviced script run FILE [--service SERVICEID] [-n]",
+				Description: "serviced script run FILE [--service SERVICEID] [-n] [--dry-run]",
 				Action:      c.cmdScriptRun,
 				Flags: []cli.Flag{
 					cli.StringFlag{
@@ -58,6 +58,10 @@ func (c *ServicedCli) initScript() {
 						Name:  "no-op, n",
 						Usage: "Run through script without modifying system",
 					},
+					cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Check the services and images the script uses and print the changes it would make",
+					},
 				},
 			},
 		},
@@ -99,6 +103,20 @@ func (c *ServicedCli) cmdScriptRun(ctx *cli.Context) {
 		config.ServiceID = svc.ID
 	}
 
+	if ctx.Bool("dry-run") {
+		plan, err := c.driver.ScriptDryRun(fileName, config)
+		if plan != nil {
+			printScriptPlan(plan)
+		}
+		if err != nil {
+			printScriptError(err)
+			c.exit(1)
+		} else if len(plan.Problems) > 0 {
+			c.exit(1)
+		}
+		return
+	}
+
 	// exec unix script command to log output
 	if isWithin := os.Getenv("IS_WITHIN_UNIX_SCRIPT"); isWithin != "TRUE" {
 		os.Setenv("IS_WITHIN_UNIX_SCRIPT", "TRUE") // prevent inception problem
@@ -140,11 +158,44 @@ func (c *ServicedCli) cmdScriptParse(ctx *cli.Context) {
 	config := script.Config{}
 	err := c.driver.ScriptParse(fileName, &config)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		printScriptError(err)
 	}
 	return
 }
 
+// printScriptPlan prints the changes found by a dry run
+func printScriptPlan(plan *script.Plan) {
+	if plan.TenantID != "" {
+		fmt.Printf("Tenant: %s\n", plan.TenantID)
+		if plan.Snapshot != "" {
+			fmt.Printf("Latest snapshot: %s\n", plan.Snapshot)
+		} else {
+			fmt.Println("Latest snapshot: none")
+		}
+	}
+	fmt.Println("Actions:")
+	for i, action := range plan.Actions {
+		fmt.Printf("%3d. line %-4d %s%s\n", i+1, action.Line, strings.Repeat("  ", action.Depth), action.Description)
+	}
+	if len(plan.Problems) > 0 {
+		fmt.Println("Problems:")
+		for _, problem := range plan.Problems {
+			fmt.Printf("  %s\n", problem)
+		}
+	}
+}
+
+// printScriptError prints the error, listing each problem found if the
+// script could not be parsed
+func printScriptError(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	if perr, ok := err.(*script.ParseError); ok {
+		for _, e := range perr.Errors {
+			fmt.Fprintf(os.Stderr, "  %s\n", e)
+		}
+	}
+}
+
 func runScript(c *ServicedCli, ctx *cli.Context, fileName string, config *script.Config) {
 	stopChan := make(chan struct{})
 	signalHandlerChan := make(chan os.Signal)
@@ -162,7 +213,7 @@ func runScript(c *ServicedCli, ctx *cli.Context, fileName string, config *script
 	config.NoOp = ctx.Bool("no-op")
 	err := c.driver.ScriptRun(fileName, config, stopChan)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		printScriptError(err)
 		c.exit(1)
 		return
 	}
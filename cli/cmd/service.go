@@ -282,6 +282,10 @@ func (c *ServicedCli) initService() {
 						Value: &cli.StringSlice{},
 						Usage: "bind mount: HOST_PATH[,CONTAINER_PATH]",
 					},
+					cli.BoolFlag{
+						Name:  "record",
+						Usage: "record the session for later review with 'serviced session play'",
+					},
 					cli.BoolFlag{
 						Name:  "no-prefix-match, np",
 						Usage: "Make SERVICEID matches on name strict 'ends with' matches",
@@ -321,6 +325,10 @@ func (c *ServicedCli) initService() {
 						Value: "",
 						Usage: "container username used to run command",
 					},
+					cli.BoolFlag{
+						Name:  "record",
+						Usage: "record the session for later review with 'serviced session play'",
+					},
 					cli.BoolFlag{
 						Name:  "no-prefix-match, np",
 						Usage: "Make SERVICEID matches on name strict 'ends with' matches",
@@ -333,6 +341,10 @@ func (c *ServicedCli) initService() {
 				BashComplete: c.printServicesFirst,
 				Before:       c.cmdServiceAttach,
 				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "record",
+						Usage: "record the session for later review with 'serviced session play'",
+					},
 					cli.BoolFlag{
 						Name:  "no-prefix-match, np",
 						Usage: "Make SERVICEID matches on name strict 'ends with' matches",
@@ -533,6 +545,37 @@ func (c *ServicedCli) initService() {
 									},
 								},
 							},
+							{
+								Name:        "access",
+								Usage:       "Shows or sets the client access policy of a port public endpoint",
+								Description: "serviced service public-endpoints port access <SERVICEID> <ENDPOINTNAME> <PORTADDR>",
+								Action:      c.cmdPublicEndpointsPortAccess,
+								Flags: []cli.Flag{
+									cli.StringSliceFlag{
+										Name:  "allow",
+										Value: &cli.StringSlice{},
+										Usage: "Network (CIDR) or address that may connect; replaces the allow list",
+									},
+									cli.StringSliceFlag{
+										Name:  "deny",
+										Value: &cli.StringSlice{},
+										Usage: "Network (CIDR) or address that may not connect; replaces the deny list",
+									},
+									cli.IntFlag{
+										Name:  "rate-limit",
+										Value: 0,
+										Usage: "Requests or connections allowed per minute for each client address (0 is unlimited)",
+									},
+									cli.BoolFlag{
+										Name:  "clear",
+										Usage: "Remove the access policy before applying any other options",
+									},
+									cli.BoolFlag{
+										Name:  "no-prefix-match, np",
+										Usage: "Make SERVICEID matches on name strict 'ends with' matches",
+									},
+								},
+							},
 						},
 					},
 					{
@@ -602,6 +645,37 @@ func (c *ServicedCli) initService() {
 									},
 								},
 							},
+							{
+								Name:        "access",
+								Usage:       "Shows or sets the client access policy of a vhost public endpoint",
+								Description: "serviced service public-endpoints vhost access <SERVICEID> <ENDPOINTNAME> <VHOST>",
+								Action:      c.cmdPublicEndpointsVHostAccess,
+								Flags: []cli.Flag{
+									cli.StringSliceFlag{
+										Name:  "allow",
+										Value: &cli.StringSlice{},
+										Usage: "Network (CIDR) or address that may connect; replaces the allow list",
+									},
+									cli.StringSliceFlag{
+										Name:  "deny",
+										Value: &cli.StringSlice{},
+										Usage: "Network (CIDR) or address that may not connect; replaces the deny list",
+									},
+									cli.IntFlag{
+										Name:  "rate-limit",
+										Value: 0,
+										Usage: "Requests or connections allowed per minute for each client address (0 is unlimited)",
+									},
+									cli.BoolFlag{
+										Name:  "clear",
+										Usage: "Remove the access policy before applying any other options",
+									},
+									cli.BoolFlag{
+										Name:  "no-prefix-match, np",
+										Usage: "Make SERVICEID matches on name strict 'ends with' matches",
+									},
+								},
+							},
 						},
 					},
 				},
@@ -1996,6 +2070,7 @@ func (c *ServicedCli) cmdServiceShell(ctx *cli.Context) error {
 		IsTTY:            isTTY,
 		Mounts:           ctx.GlobalStringSlice("mount"),
 		ServicedEndpoint: fmt.Sprintf("localhost:%s", api.GetOptionsRPCPort()),
+		Record:           ctx.Bool("record"),
 	}
 
 	if err := c.driver.StartShell(config); err != nil {
@@ -2097,6 +2172,7 @@ func (c *ServicedCli) cmdServiceRun(ctx *cli.Context) error {
 		Mounts:           ctx.GlobalStringSlice("mount"),
 		ServicedEndpoint: fmt.Sprintf("localhost:%s", api.GetOptionsRPCPort()),
 		LogToStderr:      ctx.GlobalBool("logtostderr"),
+		Record:           ctx.Bool("record"),
 	}
 
 	config.LogStash.Enable = ctx.GlobalBool("logstash")
@@ -2147,7 +2223,7 @@ func (c *ServicedCli) cmdServiceAttach(ctx *cli.Context) error {
 		argv = args[2:]
 	}
 
-	if err := c.driver.AttachServiceInstance(svc.ID, instanceID, command, argv); err != nil {
+	if err := c.driver.AttachServiceInstance(svc.ID, instanceID, command, argv, ctx.Bool("record")); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		c.exit(1)
 		return err
@@ -0,0 +1,165 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/session"
+	"github.com/control-center/serviced/shell"
+)
+
+// Initializer for serviced session subcommands
+func (c *ServicedCli) initSession() {
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "session",
+		Usage:       "Reviews recorded shell sessions",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:         "list",
+				Usage:        "Lists recorded shell sessions, most recent first",
+				Description:  "serviced session list [--service SERVICEID] [--tenant TENANTID] [--user USER]",
+				BashComplete: nil,
+				Action:       c.cmdSessionList,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "service",
+						Usage: "Only sessions on the service with this id",
+					},
+					cli.StringFlag{
+						Name:  "tenant",
+						Usage: "Only sessions on services of the tenant with this id",
+					},
+					cli.StringFlag{
+						Name:  "user",
+						Usage: "Only sessions by this user",
+					},
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+					cli.StringFlag{
+						Name:  "show-fields",
+						Value: "ID,Started,Duration,User,Kind,Service,Instance,Host,ExitCode",
+						Usage: "Comma-delimited list describing which fields to display",
+					},
+				},
+			}, {
+				Name:         "play",
+				Usage:        "Replays a recorded shell session",
+				Description:  "serviced session play SESSIONID",
+				BashComplete: nil,
+				Action:       c.cmdSessionPlay,
+				Flags: []cli.Flag{
+					cli.Float64Flag{
+						Name:  "speed",
+						Value: 1,
+						Usage: "Playback speed, where 2 plays the session twice as fast",
+					},
+					cli.StringFlag{
+						Name:  "idle-limit",
+						Value: "2s",
+						Usage: "Longest pause during playback, or 0 to keep the recorded pauses",
+					},
+					cli.BoolFlag{
+						Name:  "raw",
+						Usage: "Write the asciicast recording instead of replaying it",
+					},
+				},
+			},
+		},
+	})
+}
+
+// serviced session list [--service SERVICEID] [--tenant TENANTID] [--user USER]
+func (c *ServicedCli) cmdSessionList(ctx *cli.Context) {
+	filter := session.Filter{
+		ServiceID: ctx.String("service"),
+		TenantID:  ctx.String("tenant"),
+		User:      ctx.String("user"),
+	}
+	sessions, err := c.driver.GetSessions(filter)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if len(sessions) == 0 {
+		fmt.Fprintln(os.Stderr, "no sessions found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonSessions, err := json.MarshalIndent(sessions, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal sessions: %s", err)
+		} else {
+			fmt.Println(string(jsonSessions))
+		}
+	} else {
+		t := NewTable(ctx.String("show-fields"))
+		for _, s := range sessions {
+			instance := "new"
+			if s.InstanceID >= 0 {
+				instance = fmt.Sprintf("%d", s.InstanceID)
+			}
+			t.AddRow(map[string]interface{}{
+				"ID":       s.ID,
+				"Started":  s.Started.Local().Format("2006-01-02 15:04:05"),
+				"Duration": s.Duration().Round(time.Second),
+				"User":     s.User,
+				"Kind":     s.Kind,
+				"Service":  s.ServiceID,
+				"Tenant":   s.TenantID,
+				"Instance": instance,
+				"Host":     s.HostID,
+				"Command":  s.Command,
+				"ExitCode": s.ExitCode,
+				"Size":     s.Size,
+			})
+		}
+		t.Print()
+	}
+}
+
+// serviced session play SESSIONID
+func (c *ServicedCli) cmdSessionPlay(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "play")
+		return
+	}
+	idleLimit, err := time.ParseDuration(ctx.String("idle-limit"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "--idle-limit: invalid duration %q\n", ctx.String("idle-limit"))
+		return
+	}
+
+	_, recording, err := c.driver.GetSessionRecording(args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	if ctx.Bool("raw") {
+		os.Stdout.Write(recording)
+		return
+	}
+	if _, err := shell.Play(bytes.NewReader(recording), os.Stdout, ctx.Float64("speed"), idleLimit); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/session"
+)
+
+type SessionAPITest struct {
+	api.API
+	sessions []session.Session
+}
+
+func DefaultSessionAPI() SessionAPITest {
+	t0 := time.Date(2019, 3, 5, 10, 0, 0, 0, time.UTC)
+	return SessionAPITest{
+		sessions: []session.Session{
+			{ID: "s2", Kind: session.KindAttach, User: "jdoe", HostID: "host1", ServiceID: "svc1", TenantID: "tenant1", InstanceID: 0, Command: "/bin/bash", Started: t0.Add(time.Hour), Ended: t0.Add(time.Hour + 90*time.Second)},
+			{ID: "s1", Kind: session.KindShell, User: "admin", HostID: "host2", ServiceID: "svc2", TenantID: "tenant1", InstanceID: -1, Command: "/bin/bash", Started: t0, Ended: t0.Add(5 * time.Minute), ExitCode: 1},
+		},
+	}
+}
+
+func (t SessionAPITest) GetSessions(filter session.Filter) ([]session.Session, error) {
+	sessions := []session.Session{}
+	for _, s := range t.sessions {
+		if filter.User != "" && filter.User != s.User {
+			continue
+		}
+		if filter.ServiceID != "" && filter.ServiceID != s.ServiceID {
+			continue
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, nil
+}
+
+func (t SessionAPITest) GetSessionRecording(sessionID string) (*session.Session, []byte, error) {
+	for _, s := range t.sessions {
+		if s.ID == sessionID {
+			recording := "{\"version\":2,\"width\":80,\"height\":24}\n" +
+				"[0.001,\"o\",\"$ \"]\n" +
+				"[0.002,\"i\",\"exit\\r\"]\n" +
+				"[0.003,\"o\",\"exit\\r\\n\"]\n"
+			return &s, []byte(recording), nil
+		}
+	}
+	return nil, nil, errors.New("no such session")
+}
+
+func ExampleServicedCLI_CmdSessionList() {
+	RunCmd(DefaultSessionAPI(), "serviced", "session", "list", "--show-fields", "ID,Duration,User,Kind,Service,Instance,Host,ExitCode")
+
+	// Output:
+	// ID Duration User  Kind   Service Instance Host  ExitCode
+	// s2 1m30s    jdoe  attach svc1    0        host1 0
+	// s1 5m0s     admin shell  svc2    new      host2 1
+}
+
+func ExampleServicedCLI_CmdSessionList_filter() {
+	RunCmd(DefaultSessionAPI(), "serviced", "session", "list", "--user", "admin", "--show-fields", "ID,User")
+	RunCmd(DefaultSessionAPI(), "serviced", "session", "list", "--service", "svc1", "--show-fields", "ID,Service")
+
+	// Output:
+	// ID User
+	// s1 admin
+	// ID Service
+	// s2 svc1
+}
+
+func ExampleServicedCLI_CmdSessionList_err() {
+	pipeStderr(func() {
+		RunCmd(DefaultSessionAPI(), "serviced", "session", "list", "--user", "nobody")
+	})
+
+	// Output:
+	// no sessions found
+}
+
+func ExampleServicedCLI_CmdSessionPlay() {
+	RunCmd(DefaultSessionAPI(), "serviced", "session", "play", "s1")
+
+	// Output:
+	// $ exit
+}
+
+func ExampleServicedCLI_CmdSessionPlay_err() {
+	pipeStderr(func() {
+		RunCmd(DefaultSessionAPI(), "serviced", "session", "play", "s3")
+		RunCmd(DefaultSessionAPI(), "serviced", "session", "play", "--idle-limit", "forever", "s1")
+	})
+
+	// Output:
+	// no such session
+	// --idle-limit: invalid duration "forever"
+}
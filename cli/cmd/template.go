@@ -74,6 +74,37 @@ func (c *ServicedCli) initTemplate() {
 						Usage: "Manually assign IP addresses",
 					},
 				},
+			}, {
+				Name:         "diff",
+				Usage:        "Shows how upgrading an application to a template changes its services",
+				Description:  "serviced template diff TENANTID TEMPLATEID",
+				BashComplete: c.printTemplateUpgrade,
+				Action:       c.cmdTemplateDiff,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+				},
+			}, {
+				Name:         "upgrade",
+				Usage:        "Upgrades an application to a template",
+				Description:  "serviced template upgrade TENANTID TEMPLATEID",
+				BashComplete: c.printTemplateUpgrade,
+				Action:       c.cmdTemplateUpgrade,
+			}, {
+				Name:         "export",
+				Usage:        "Writes a deployed application out as a template directory",
+				Description:  "serviced template export TENANTID [DIR]",
+				BashComplete: c.printServicesFirst,
+				Action:       c.cmdTemplateExport,
+				Flags: []cli.Flag{
+					cli.GenericFlag{
+						Name:  "map",
+						Value: &api.ImageMap{},
+						Usage: "Map a given image name to another (e.g. -map localhost:5000/tenantid/core:latest,zenoss/core:5.1)",
+					},
+				},
 			}, {
 				Name:        "compile",
 				Usage:       "Convert a directory of service definitions into a template",
@@ -135,6 +166,23 @@ func (c *ServicedCli) printTemplateDeploy(ctx *cli.Context) {
 	}
 }
 
+// Bash-completion command that prints the command options for
+// serviced template diff and serviced template upgrade
+func (c *ServicedCli) printTemplateUpgrade(ctx *cli.Context) {
+	var output []string
+
+	switch len(ctx.Args()) {
+	case 0:
+		output = c.services()
+	case 1:
+		output = c.templates()
+	}
+
+	for _, o := range output {
+		fmt.Println(o)
+	}
+}
+
 // Bash-completion command that prints the list of templates as all arguments
 func (c *ServicedCli) printTemplatesAll(ctx *cli.Context) {
 	args := ctx.Args()
@@ -265,12 +313,126 @@ func (c *ServicedCli) cmdTemplateDeploy(ctx *cli.Context) {
 	}
 }
 
+// serviced template diff TENANTID TEMPLATEID [--verbose, -v]
+func (c *ServicedCli) cmdTemplateDiff(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "diff")
+		return
+	}
+
+	diff, err := c.driver.TemplateDiff(args[0], args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	if ctx.Bool("verbose") {
+		if jsonDiff, err := json.MarshalIndent(diff, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal template diff: %s\n", err)
+			c.exit(1)
+		} else {
+			fmt.Println(string(jsonDiff))
+		}
+		return
+	}
+	printTemplateDiff(diff)
+}
+
+// serviced template upgrade TENANTID TEMPLATEID
+func (c *ServicedCli) cmdTemplateUpgrade(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "upgrade")
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, "Upgrading application - please wait...")
+	diff, err := c.driver.UpgradeTemplate(args[0], args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	printTemplateDiff(diff)
+	fmt.Printf("Snapshot before upgrade: %s\n", diff.Snapshot)
+}
+
+// printTemplateDiff prints the changes of a template upgrade, one per line
+func printTemplateDiff(diff *template.TemplateDiff) {
+	from := "deployed services"
+	if diff.FromTemplateID != "" {
+		from = fmt.Sprintf("template %s version %s", diff.FromTemplateID, diff.FromVersion)
+	}
+	fmt.Printf("Tenant %s: %s -> template %s version %s\n", diff.TenantID, from, diff.TemplateID, diff.Version)
+	if len(diff.Changes) == 0 {
+		fmt.Println("No changes")
+		return
+	}
+	for _, change := range diff.Changes {
+		line := fmt.Sprintf("%s: %s", change.Path, change.Kind)
+		if change.Name != "" {
+			line += " " + change.Name
+		}
+		switch {
+		case change.Kind == template.ChangeServiceRemoved:
+			line += " (left in place)"
+		case change.Kind == template.ChangeServiceAdded:
+			if change.New != "" {
+				line += fmt.Sprintf(" (image %s)", change.New)
+			}
+		case change.Old == "":
+			line += fmt.Sprintf(": added %s", change.New)
+		case change.New == "":
+			line += fmt.Sprintf(": removed %s", change.Old)
+		default:
+			line += fmt.Sprintf(": %s -> %s", change.Old, change.New)
+		}
+		if change.Preserved {
+			line += " (edited; local version kept)"
+		}
+		fmt.Println(line)
+	}
+}
+
 type metaTemplate struct {
 	template.ServiceTemplate
 	ServicedVersion servicedversion.ServicedVersion
 	TemplateVersion map[string]string
 }
 
+// serviced template export TENANTID [DIR] [[--map IMAGE,IMAGE] ...]
+func (c *ServicedCli) cmdTemplateExport(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "export")
+		return
+	}
+
+	cfg := api.ExportTemplateConfig{
+		TenantID: args[0],
+		Dir:      ".",
+		Map:      *ctx.Generic("map").(*api.ImageMap),
+	}
+	if len(args) > 1 {
+		cfg.Dir = args[1]
+	}
+
+	dir, warnings, err := c.driver.ExportServiceTemplate(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		c.exit(1)
+		return
+	}
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s; use --map to set its upstream name\n", warning)
+	}
+	fmt.Println(dir)
+}
+
 // serviced template compile DIR [[--map IMAGE,IMAGE] ...]
 func (c *ServicedCli) cmdTemplateCompile(ctx *cli.Context) {
 	args := ctx.Args()
@@ -132,6 +132,47 @@ func (t TemplateAPITest) DeployServiceTemplate(cfg api.DeployTemplateConfig) ([]
 	return []service.ServiceDetails{s}, nil
 }
 
+func (t TemplateAPITest) TemplateDiff(tenantID, templateID string) (*template.TemplateDiff, error) {
+	if t.fail {
+		return nil, ErrInvalidTemplate
+	}
+	return &template.TemplateDiff{
+		TenantID:       tenantID,
+		TemplateID:     templateID,
+		Version:        "2.0",
+		FromTemplateID: "test-template-1",
+		FromVersion:    "1.0",
+		Changes: []template.Change{
+			{Path: "app", Kind: template.ChangeImage, Old: "zenoss/app:1.0", New: "zenoss/app:2.0"},
+			{Path: "app", Kind: template.ChangeConfigFile, Name: "/etc/app.conf", Old: "root:root 0644, 1 bytes", New: "root:root 0644, 2 bytes", Preserved: true},
+			{Path: "app", Kind: template.ChangeVolume, Name: "/data", Old: "data dfs root:root 0755"},
+			{Path: "app/reports", Kind: template.ChangeServiceAdded, New: "zenoss/reports:1.0"},
+			{Path: "app/worker", Kind: template.ChangeServiceRemoved},
+		},
+	}, nil
+}
+
+func (t TemplateAPITest) UpgradeTemplate(tenantID, templateID string) (*template.TemplateDiff, error) {
+	diff, err := t.TemplateDiff(tenantID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	diff.Changes = nil
+	diff.Snapshot = tenantID + "_upgrade"
+	return diff, nil
+}
+
+func (t TemplateAPITest) ExportServiceTemplate(cfg api.ExportTemplateConfig) (string, []string, error) {
+	if t.fail {
+		return "", nil, ErrInvalidTemplate
+	}
+	var warnings []string
+	if _, ok := cfg.Map["localhost:5000/tenant-id/app:latest"]; !ok {
+		warnings = append(warnings, "app: image localhost:5000/tenant-id/app:latest is local to this deployment")
+	}
+	return cfg.Dir + "/app", warnings, nil
+}
+
 func TestServicedCLI_CmdTemplateList_one(t *testing.T) {
 	templateID := "test-template-1"
 
@@ -319,6 +360,61 @@ func TestServicedCLI_CmdTemplateCompile(t *testing.T) {
 	}
 }
 
+func ExampleServicedCLI_CmdTemplateDiff() {
+	InitTemplateAPITest("serviced", "template", "diff", "tenant-id", "test-template-2")
+
+	// Output:
+	// Tenant tenant-id: template test-template-1 version 1.0 -> template test-template-2 version 2.0
+	// app: image: zenoss/app:1.0 -> zenoss/app:2.0
+	// app: config file /etc/app.conf: root:root 0644, 1 bytes -> root:root 0644, 2 bytes (edited; local version kept)
+	// app: volume /data: removed data dfs root:root 0755
+	// app/reports: service added (image zenoss/reports:1.0)
+	// app/worker: service removed (left in place)
+}
+
+func ExampleServicedCLI_CmdTemplateDiff_usage() {
+	InitTemplateAPITest("serviced", "template", "diff", "tenant-id")
+
+	// Output:
+	// Incorrect Usage.
+	//
+	// NAME:
+	//    diff - Shows how upgrading an application to a template changes its services
+	//
+	// USAGE:
+	//    command diff [command options] [arguments...]
+	//
+	// DESCRIPTION:
+	//    serviced template diff TENANTID TEMPLATEID
+	//
+	// OPTIONS:
+	//    --verbose, -v	Show JSON format
+}
+
+func ExampleServicedCLI_CmdTemplateUpgrade() {
+	InitTemplateAPITest("serviced", "template", "upgrade", "tenant-id", "test-template-2")
+
+	// Output:
+	// Tenant tenant-id: template test-template-1 version 1.0 -> template test-template-2 version 2.0
+	// No changes
+	// Snapshot before upgrade: tenant-id_upgrade
+}
+
+func ExampleServicedCLI_CmdTemplateExport() {
+	InitTemplateAPITest("serviced", "template", "export", "--map", "localhost:5000/tenant-id/app:latest,zenoss/app:1.0", "tenant-id", "/tmp/export")
+
+	// Output:
+	// /tmp/export/app
+}
+
+func ExampleServicedCLI_CmdTemplateExport_warnings() {
+	pipeStderr(func() { InitTemplateAPITest("serviced", "template", "export", "tenant-id") })
+
+	// Output:
+	// ./app
+	// warning: app: image localhost:5000/tenant-id/app:latest is local to this deployment; use --map to set its upstream name
+}
+
 func ExampleServicedCLI_CmdTemplateCompile_usage() {
 	InitTemplateAPITest("serviced", "template", "compile")
 
@@ -0,0 +1,292 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/codegangsta/cli"
+	"github.com/control-center/serviced/domain/user"
+)
+
+// Initializer for serviced user subcommands
+func (c *ServicedCli) initUser() {
+	roleFlags := []cli.Flag{
+		cli.StringFlag{
+			Name:  "tenant",
+			Usage: "Restrict the role to the tenant with this service ID",
+		},
+		cli.StringFlag{
+			Name:  "pool",
+			Usage: "Restrict the role to the resource pool with this ID",
+		},
+	}
+
+	c.app.Commands = append(c.app.Commands, cli.Command{
+		Name:        "user",
+		Usage:       "Administers users and their roles",
+		Description: "",
+		Subcommands: []cli.Command{
+			{
+				Name:         "list",
+				Usage:        "Lists all users and their roles",
+				Description:  "serviced user list",
+				BashComplete: nil,
+				Action:       c.cmdUserList,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "verbose, v",
+						Usage: "Show JSON format",
+					},
+					cli.StringFlag{
+						Name:  "show-fields",
+						Value: "Name,Roles",
+						Usage: "Comma-delimited list describing which fields to display",
+					},
+				},
+			}, {
+				Name:         "add",
+				Usage:        "Adds a new user",
+				Description:  "serviced user add USER PASSWORD",
+				BashComplete: nil,
+				Action:       c.cmdUserAdd,
+			}, {
+				Name:         "remove",
+				ShortName:    "rm",
+				Usage:        "Removes an existing user",
+				Description:  "serviced user remove USER ...",
+				BashComplete: c.printUsersAll,
+				Action:       c.cmdUserRemove,
+			}, {
+				Name:         "grant",
+				Usage:        "Grants a role to a user",
+				Description:  fmt.Sprintf("serviced user grant [--tenant TENANTID|--pool POOLID] USER ROLE\n\n   ROLE is one of: %s", roleNames()),
+				BashComplete: c.printUsersFirst,
+				Action:       c.cmdUserGrant,
+				Flags:        roleFlags,
+			}, {
+				Name:         "revoke",
+				Usage:        "Revokes a role from a user",
+				Description:  "serviced user revoke [--tenant TENANTID|--pool POOLID] USER ROLE",
+				BashComplete: c.printUsersFirst,
+				Action:       c.cmdUserRevoke,
+				Flags:        roleFlags,
+			}, {
+				Name:         "login",
+				Usage:        "Logs in as a user, so that commands from this host act with the user's roles",
+				Description:  "serviced user login USER PASSWORD",
+				BashComplete: nil,
+				Action:       c.cmdUserLogin,
+			}, {
+				Name:         "logout",
+				Usage:        "Logs out the current user",
+				Description:  "serviced user logout",
+				BashComplete: nil,
+				Action:       c.cmdUserLogout,
+			},
+		},
+	})
+}
+
+// roleNames returns the names of all valid roles, separated by commas
+func roleNames() string {
+	names := make([]string, len(user.Roles))
+	for i, r := range user.Roles {
+		names[i] = string(r)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Returns a list of all available users
+func (c *ServicedCli) users() (data []string) {
+	users, err := c.driver.GetUsers()
+	if err != nil || users == nil || len(users) == 0 {
+		return
+	}
+
+	data = make([]string, len(users))
+	for i, u := range users {
+		data[i] = u.Name
+	}
+
+	return
+}
+
+// Bash-completion command that prints the list of users as the first
+// argument
+func (c *ServicedCli) printUsersFirst(ctx *cli.Context) {
+	if len(ctx.Args()) > 0 {
+		return
+	}
+	fmt.Println(strings.Join(c.users(), "\n"))
+}
+
+// Bash-completion command that prints the list of users as all arguments
+func (c *ServicedCli) printUsersAll(ctx *cli.Context) {
+	args := ctx.Args()
+	users := c.users()
+
+	for _, u := range users {
+		for _, a := range args {
+			if u == a {
+				goto next
+			}
+		}
+		fmt.Println(u)
+	next:
+	}
+}
+
+// serviced user list
+func (c *ServicedCli) cmdUserList(ctx *cli.Context) {
+	users, err := c.driver.GetUsers()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	} else if users == nil || len(users) == 0 {
+		fmt.Fprintln(os.Stderr, "no users found")
+		return
+	}
+
+	if ctx.Bool("verbose") {
+		if jsonUsers, err := json.MarshalIndent(users, " ", "  "); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to marshal user list: %s", err)
+		} else {
+			fmt.Println(string(jsonUsers))
+		}
+	} else {
+		t := NewTable(ctx.String("show-fields"))
+		for _, u := range users {
+			roles := make([]string, len(u.Roles))
+			for i, r := range u.Roles {
+				roles[i] = r.String()
+			}
+			t.AddRow(map[string]interface{}{
+				"Name":  u.Name,
+				"Roles": strings.Join(roles, ","),
+			})
+		}
+		t.Print()
+	}
+}
+
+// serviced user add USER PASSWORD
+func (c *ServicedCli) cmdUserAdd(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "add")
+		return
+	}
+
+	if err := c.driver.AddUser(args[0], args[1]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else {
+		fmt.Println(args[0])
+	}
+}
+
+// serviced user remove USER ...
+func (c *ServicedCli) cmdUserRemove(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 1 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "remove")
+		return
+	}
+
+	for _, name := range args {
+		if err := c.driver.RemoveUser(name); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", name, err)
+		} else {
+			fmt.Println(name)
+		}
+	}
+}
+
+// serviced user grant [--tenant TENANTID|--pool POOLID] USER ROLE
+func (c *ServicedCli) cmdUserGrant(ctx *cli.Context) {
+	name, binding, ok := parseRoleBinding(ctx, "grant")
+	if !ok {
+		return
+	}
+
+	if err := c.driver.GrantRole(name, binding); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else {
+		fmt.Printf("%s: %s\n", name, binding)
+	}
+}
+
+// serviced user revoke [--tenant TENANTID|--pool POOLID] USER ROLE
+func (c *ServicedCli) cmdUserRevoke(ctx *cli.Context) {
+	name, binding, ok := parseRoleBinding(ctx, "revoke")
+	if !ok {
+		return
+	}
+
+	if err := c.driver.RevokeRole(name, binding); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else {
+		fmt.Printf("%s: %s\n", name, binding)
+	}
+}
+
+// serviced user login USER PASSWORD
+func (c *ServicedCli) cmdUserLogin(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) < 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, "login")
+		return
+	}
+
+	if err := c.driver.LoginUser(args[0], args[1]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else {
+		fmt.Println(args[0])
+	}
+}
+
+// serviced user logout
+func (c *ServicedCli) cmdUserLogout(ctx *cli.Context) {
+	if err := c.driver.LogoutUser(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// parseRoleBinding reads the user and role binding from the arguments and
+// flags of the grant and revoke subcommands
+func parseRoleBinding(ctx *cli.Context, command string) (string, user.RoleBinding, bool) {
+	args := ctx.Args()
+	if len(args) < 2 {
+		fmt.Printf("Incorrect Usage.\n\n")
+		cli.ShowCommandHelp(ctx, command)
+		return "", user.RoleBinding{}, false
+	}
+
+	binding := user.RoleBinding{
+		Role:     user.Role(args[1]),
+		TenantID: ctx.String("tenant"),
+		PoolID:   ctx.String("pool"),
+	}
+	if err := binding.ValidEntity(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return "", user.RoleBinding{}, false
+	}
+	return args[0], binding, true
+}
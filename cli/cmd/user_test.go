@@ -0,0 +1,161 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/control-center/serviced/cli/api"
+	"github.com/control-center/serviced/domain/user"
+)
+
+var (
+	ErrNoUserFound    = errors.New("no user found")
+	ErrBadCredentials = errors.New("invalid user name or password")
+)
+
+type UserAPITest struct {
+	api.API
+	users    map[string]*user.User
+	loggedIn *string
+}
+
+func DefaultUserAPI() UserAPITest {
+	return UserAPITest{
+		users: map[string]*user.User{
+			"alice": {Name: "alice", Password: "secret", Roles: user.RoleBindings{{Role: user.RoleOperator, TenantID: "tenant1"}}},
+		},
+		loggedIn: new(string),
+	}
+}
+
+func (t UserAPITest) GetUsers() ([]user.User, error) {
+	users := []user.User{}
+	for _, u := range t.users {
+		users = append(users, *u)
+	}
+	return users, nil
+}
+
+func (t UserAPITest) AddUser(name, password string) error {
+	t.users[name] = &user.User{Name: name, Password: password}
+	return nil
+}
+
+func (t UserAPITest) RemoveUser(name string) error {
+	if _, ok := t.users[name]; !ok {
+		return ErrNoUserFound
+	}
+	delete(t.users, name)
+	return nil
+}
+
+func (t UserAPITest) GrantRole(name string, binding user.RoleBinding) error {
+	u, ok := t.users[name]
+	if !ok {
+		return ErrNoUserFound
+	}
+	u.Roles.Add(binding)
+	return nil
+}
+
+func (t UserAPITest) RevokeRole(name string, binding user.RoleBinding) error {
+	u, ok := t.users[name]
+	if !ok {
+		return ErrNoUserFound
+	}
+	u.Roles.Remove(binding)
+	return nil
+}
+
+func (t UserAPITest) LoginUser(name, password string) error {
+	if u, ok := t.users[name]; !ok || u.Password != password {
+		return ErrBadCredentials
+	}
+	*t.loggedIn = name
+	return nil
+}
+
+func (t UserAPITest) LogoutUser() error {
+	*t.loggedIn = ""
+	return nil
+}
+
+func ExampleServicedCLI_CmdUserList() {
+	RunCmd(DefaultUserAPI(), "serviced", "user", "list", "--show-fields", "Name")
+
+	// Output:
+	// Name
+	// alice
+}
+
+func ExampleServicedCLI_CmdUserAdd() {
+	RunCmd(DefaultUserAPI(), "serviced", "user", "add", "bob", "secret")
+
+	// Output:
+	// bob
+}
+
+func ExampleServicedCLI_CmdUserRemove_err() {
+	pipeStderr(func() { RunCmd(DefaultUserAPI(), "serviced", "user", "remove", "bob") })
+
+	// Output:
+	// bob: no user found
+}
+
+func ExampleServicedCLI_CmdUserGrant() {
+	RunCmd(DefaultUserAPI(), "serviced", "user", "grant", "--pool", "pool1", "alice", "viewer")
+
+	// Output:
+	// alice: viewer@pool:pool1
+}
+
+func ExampleServicedCLI_CmdUserGrant_err() {
+	pipeStderr(func() { RunCmd(DefaultUserAPI(), "serviced", "user", "grant", "alice", "superuser") })
+	pipeStderr(func() { RunCmd(DefaultUserAPI(), "serviced", "user", "grant", "--tenant", "tenant1", "alice", "cluster-admin") })
+
+	// Output:
+	// unknown role "superuser"
+	// role cluster-admin cannot be bound to a tenant or pool
+}
+
+func TestServicedCLI_CmdUserRevoke(t *testing.T) {
+	test := DefaultUserAPI()
+	RunCmd(test, "serviced", "user", "revoke", "--tenant", "tenant1", "alice", "operator")
+	if roles := test.users["alice"].Roles; len(roles) != 0 {
+		t.Fatalf("Expected no roles, got %s", fmt.Sprint(roles))
+	}
+}
+
+func ExampleServicedCLI_CmdUserLogin() {
+	RunCmd(DefaultUserAPI(), "serviced", "user", "login", "alice", "secret")
+	pipeStderr(func() { RunCmd(DefaultUserAPI(), "serviced", "user", "login", "alice", "wrong") })
+
+	// Output:
+	// alice
+	// invalid user name or password
+}
+
+func TestServicedCLI_CmdUserLogout(t *testing.T) {
+	test := DefaultUserAPI()
+	RunCmd(test, "serviced", "user", "login", "alice", "secret")
+	RunCmd(test, "serviced", "user", "logout")
+	if *test.loggedIn != "" {
+		t.Fatalf("Expected no user to be logged in, got %s", *test.loggedIn)
+	}
+}
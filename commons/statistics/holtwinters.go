@@ -0,0 +1,212 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// smoothingGrid is the set of values tried for each of the Holt-Winters
+// smoothing parameters when fitting a series
+var smoothingGrid = []float64{0.05, 0.2, 0.4, 0.6, 0.8, 0.95}
+
+// NewHoltWintersPredictor returns a predictor that uses additive triple
+// exponential smoothing with the given season.  It needs at least two seasons
+// of history.
+func NewHoltWintersPredictor(season time.Duration) Predictor {
+	return &holtWintersPredictor{season: season}
+}
+
+type holtWintersPredictor struct {
+	season time.Duration
+}
+
+// Season implements Seasonal
+func (p *holtWintersPredictor) Season() time.Duration {
+	return p.season
+}
+
+func (p *holtWintersPredictor) Predict(period time.Duration, timestamps, values []float64) (float64, error) {
+	forecast, err := p.Forecast(period, timestamps, values)
+	return forecast.Value, err
+}
+
+func (p *holtWintersPredictor) Forecast(period time.Duration, timestamps, values []float64) (Forecast, error) {
+	then := float64(time.Now().UTC().Add(period).Unix())
+
+	xs, ys, step, err := resample(timestamps, values)
+	if err != nil {
+		return Forecast{}, err
+	}
+
+	// Smoothing needs two full seasons: one to initialize the seasonal
+	// components and one to compare against
+	m := int(math.Floor(p.season.Seconds()/step + 0.5))
+	if m < 2 || len(ys) < 2*m {
+		return Forecast{}, ErrInsufficientData
+	}
+
+	// Pick the smoothing parameters that best predict the series one step
+	// ahead
+	var best *HoltWinters
+	for _, alpha := range smoothingGrid {
+		for _, beta := range smoothingGrid {
+			for _, gamma := range smoothingGrid {
+				hw, err := FitHoltWinters(ys, m, alpha, beta, gamma)
+				if err != nil {
+					return Forecast{}, err
+				}
+				if best == nil || hw.SSE < best.SSE {
+					best = hw
+				}
+			}
+		}
+	}
+
+	// Predict whole steps past the end of the series
+	h := int(math.Ceil((then - xs[len(xs)-1]) / step))
+	if h < 1 {
+		h = 1
+	}
+	value := best.Forecast(h)
+
+	// The variance of the prediction grows with each step ahead by the
+	// weight the smoothing gives to the errors along the way
+	sigma2 := best.SSE / float64(best.N)
+	spread := 1.0
+	for j := 1; j < h; j++ {
+		c := best.Alpha * (1 + float64(j)*best.Beta)
+		if j%m == 0 {
+			c += best.Gamma
+		}
+		spread += c * c
+	}
+	half := confidenceZ * math.Sqrt(sigma2*spread)
+	return Forecast{Model: HoltWintersModel, Value: value, Lower: value - half, Upper: value + half}, nil
+}
+
+// HoltWinters is the state of additive triple exponential smoothing at the
+// end of a series
+type HoltWinters struct {
+	Alpha    float64   // smoothing factor of the level
+	Beta     float64   // smoothing factor of the trend
+	Gamma    float64   // smoothing factor of the seasonal components
+	Level    float64   // level at the end of the series
+	Trend    float64   // trend per step at the end of the series
+	Seasonal []float64 // seasonal components, indexed by step modulo the season
+	SSE      float64   // sum of the squared errors of the one step ahead predictions
+	N        int       // number of one step ahead predictions
+	length   int
+}
+
+// FitHoltWinters smooths evenly spaced values with a season of m steps,
+// recording the squared errors of its one step ahead predictions.  It needs at
+// least two seasons of values.
+func FitHoltWinters(ys []float64, m int, alpha, beta, gamma float64) (*HoltWinters, error) {
+	if m < 1 || len(ys) < 2*m {
+		return nil, ErrInsufficientData
+	}
+
+	// Initialize the level and trend from the means of the first two seasons
+	// and the seasonal components from the first season's deviation from
+	// that trend
+	var first, second float64
+	for i := 0; i < m; i++ {
+		first += ys[i]
+		second += ys[m+i]
+	}
+	first /= float64(m)
+	second /= float64(m)
+	hw := &HoltWinters{
+		Alpha:    alpha,
+		Beta:     beta,
+		Gamma:    gamma,
+		Trend:    (second - first) / float64(m),
+		Seasonal: make([]float64, m),
+		length:   len(ys),
+	}
+	middle := float64(m-1) / 2
+	hw.Level = first + hw.Trend*middle
+	for i := 0; i < m; i++ {
+		hw.Seasonal[i] = ys[i] - (first + hw.Trend*(float64(i)-middle))
+	}
+
+	for t := m; t < len(ys); t++ {
+		s := hw.Seasonal[t%m]
+		e := ys[t] - (hw.Level + hw.Trend + s)
+		hw.SSE += e * e
+		hw.N++
+
+		level := alpha*(ys[t]-s) + (1-alpha)*(hw.Level+hw.Trend)
+		hw.Trend = beta*(level-hw.Level) + (1-beta)*hw.Trend
+		hw.Seasonal[t%m] = gamma*(ys[t]-level) + (1-gamma)*s
+		hw.Level = level
+	}
+	return hw, nil
+}
+
+// Forecast returns the value predicted h steps past the end of the series
+func (hw *HoltWinters) Forecast(h int) float64 {
+	m := len(hw.Seasonal)
+	return hw.Level + float64(h)*hw.Trend + hw.Seasonal[(hw.length-1+h)%m]
+}
+
+// resample interpolates a series onto evenly spaced timestamps, using the
+// median interval between the original timestamps as the step
+func resample(timestamps, values []float64) (xs, ys []float64, step float64, err error) {
+	n := len(timestamps)
+	if n != len(values) {
+		return nil, nil, 0, ErrUnequalArrays
+	}
+
+	points := make([]int, n)
+	for i := range points {
+		points[i] = i
+	}
+	sort.Slice(points, func(i, j int) bool { return timestamps[points[i]] < timestamps[points[j]] })
+	px, py := make([]float64, n), make([]float64, n)
+	for i, p := range points {
+		px[i], py[i] = timestamps[p], values[p]
+	}
+
+	intervals := []float64{}
+	for i := 1; i < n; i++ {
+		if d := px[i] - px[i-1]; d > 0 {
+			intervals = append(intervals, d)
+		}
+	}
+	if len(intervals) == 0 {
+		return nil, nil, 0, ErrInsufficientData
+	}
+	step = Median(intervals)
+
+	count := int(math.Floor((px[n-1]-px[0])/step)) + 1
+	xs, ys = make([]float64, count), make([]float64, count)
+	j := 0
+	for i := range xs {
+		x := px[0] + float64(i)*step
+		for j < n-2 && px[j+1] < x {
+			j++
+		}
+		xs[i] = x
+		if dx := px[j+1] - px[j]; dx > 0 {
+			ys[i] = py[j] + (py[j+1]-py[j])*(x-px[j])/dx
+		} else {
+			ys[i] = py[j]
+		}
+	}
+	return xs, ys, step, nil
+}
@@ -53,3 +53,30 @@ func (s *StatisticsSuite) TestLeastSquares(c *C) {
 	c.Assert(b, RoughlyEquals, -0.95)
 	c.Assert(err, IsNil)
 }
+
+func (s *StatisticsSuite) TestTheilSen(c *C) {
+	// Test empty series produces error
+	m, b, err := TheilSen([]float64{}, []float64{})
+	c.Assert(m, Equals, zeroFloat)
+	c.Assert(b, Equals, zeroFloat)
+	c.Assert(err, Equals, ErrInsufficientData)
+
+	// Test unequal arrays produce error
+	_, _, err = TheilSen([]float64{1, 2}, []float64{1})
+	c.Assert(err, Equals, ErrUnequalArrays)
+
+	// Test fit against a straight line with an outlier.  It should still
+	// match the original line.
+	xs := []float64{-1, 0, 1, 2, 3, 4, 5}
+	ys := ycoords(xs, 0.2, 4.0)
+	ys[3] = 100
+	m, b, err = TheilSen(xs, ys)
+	c.Assert(m, RoughlyEquals, 0.2)
+	c.Assert(b, RoughlyEquals, 4.0)
+	c.Assert(err, IsNil)
+}
+
+func (s *StatisticsSuite) TestMedian(c *C) {
+	c.Assert(Median([]float64{3, 1, 2}), Equals, 2.0)
+	c.Assert(Median([]float64{4, 1, 3, 2}), Equals, 2.5)
+}
@@ -13,12 +13,33 @@
 
 package statistics
 
-import "time"
+import (
+	"math"
+	"time"
+)
+
+// Names of the models that can be used to make predictions
+const (
+	LeastSquaresModel = "leastsquares"
+	TheilSenModel     = "theilsen"
+	HoltWintersModel  = "holtwinters"
+)
+
+// confidenceZ is the standard score of a two-sided 95% interval
+const confidenceZ = 1.96
 
 var (
 	// LeastSquaresPredictor uses the ordinary least squares method of
 	// estimation to predict future values
 	LeastSquaresPredictor = &olsPredictor{}
+
+	// TheilSenPredictor fits a line through the median of the pairwise
+	// slopes, so a few outliers do not pull the prediction off course
+	TheilSenPredictor = &theilSenPredictor{}
+
+	// HoltWintersPredictor uses additive triple exponential smoothing with a
+	// daily season to predict future values
+	HoltWintersPredictor = NewHoltWintersPredictor(24 * time.Hour)
 )
 
 // Predictor represents a strategy for predicting a future value based on
@@ -27,20 +48,72 @@ type Predictor interface {
 	// Predict uses the timestamp/value pairs passed in to predict the value at
 	// time now+period
 	Predict(period time.Duration, timestamps, values []float64) (float64, error)
+
+	// Forecast is like Predict, but also returns the 95% prediction interval
+	// and the name of the model
+	Forecast(period time.Duration, timestamps, values []float64) (Forecast, error)
+}
+
+// Seasonal is implemented by predictors that need at least two full seasons
+// of history to make a prediction
+type Seasonal interface {
+	Season() time.Duration
+}
+
+// Forecast is a predicted value and the interval the actual value is
+// expected to fall within
+type Forecast struct {
+	Model string
+	Value float64
+	Lower float64
+	Upper float64
 }
 
 type olsPredictor struct{}
 
 func (p *olsPredictor) Predict(period time.Duration, timestamps, values []float64) (float64, error) {
+	forecast, err := p.Forecast(period, timestamps, values)
+	return forecast.Value, err
+}
+
+func (p *olsPredictor) Forecast(period time.Duration, timestamps, values []float64) (Forecast, error) {
 	// Get the timestamp for which we're going to predict the value
 	then := float64(time.Now().UTC().Add(period).Unix())
 
 	// Use least squares to find the line of best fit
 	m, b, err := LeastSquares(timestamps, values)
 	if err != nil {
-		return 0, err
+		return Forecast{}, err
 	}
 
-	// Get the predicted future value using the line
-	return then*m + b, nil
+	// The residual standard error sets the width of the interval
+	var sse float64
+	for i, x := range timestamps {
+		r := values[i] - (x*m + b)
+		sse += r * r
+	}
+	var s float64
+	if n := len(timestamps); n > 2 {
+		s = math.Sqrt(sse / float64(n-2))
+	}
+	return lineForecast(LeastSquaresModel, timestamps, m, b, then, s), nil
+}
+
+// lineForecast returns the value of the line at x and its prediction
+// interval, given the scale of the residuals around the line.  The interval
+// widens the further x is from the data.
+func lineForecast(model string, xs []float64, m, b, x, scale float64) Forecast {
+	meanx := Mean(xs)
+	var sumxx float64
+	for _, xi := range xs {
+		sumxx += (xi - meanx) * (xi - meanx)
+	}
+	n := float64(len(xs))
+	spread := 1 + 1/n
+	if sumxx > 0 {
+		spread += (x - meanx) * (x - meanx) / sumxx
+	}
+	value := x*m + b
+	half := confidenceZ * scale * math.Sqrt(spread)
+	return Forecast{Model: model, Value: value, Lower: value - half, Upper: value + half}
 }
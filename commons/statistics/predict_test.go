@@ -1,6 +1,7 @@
 package statistics_test
 
 import (
+	"math"
 	"time"
 
 	. "github.com/control-center/serviced/commons/statistics"
@@ -31,3 +32,107 @@ func (s *StatisticsSuite) TestLeastSquaresPredictor(c *C) {
 	c.Assert(val, Equals, zeroFloat)
 	c.Assert(err, Equals, ErrInsufficientData)
 }
+
+func (s *StatisticsSuite) TestLeastSquaresForecast(c *C) {
+	now := float64(time.Now().UTC().Unix())
+	ts := []float64{now - 10, now - 8, now - 6, now - 4, now - 2}
+
+	// A perfect fit has no uncertainty
+	f, err := LeastSquaresPredictor.Forecast(time.Minute, ts, ycoords(ts, 1, 0))
+	c.Assert(err, IsNil)
+	c.Assert(f.Model, Equals, LeastSquaresModel)
+	c.Assert(f.Value, RoughlyEquals, now+60)
+	c.Assert(f.Lower, RoughlyEquals, f.Value)
+	c.Assert(f.Upper, RoughlyEquals, f.Value)
+
+	// Noise widens the interval, and more so further out
+	ys := []float64{4, 6, 3, 5, 4}
+	near, err := LeastSquaresPredictor.Forecast(time.Second, ts, ys)
+	c.Assert(err, IsNil)
+	far, err := LeastSquaresPredictor.Forecast(time.Hour, ts, ys)
+	c.Assert(err, IsNil)
+	c.Assert(near.Lower < near.Value && near.Value < near.Upper, Equals, true)
+	c.Assert(far.Upper-far.Lower > near.Upper-near.Lower, Equals, true)
+}
+
+func (s *StatisticsSuite) TestTheilSenPredictor(c *C) {
+	now := float64(time.Now().UTC().Unix())
+	ts := []float64{}
+	for i := 10; i > 0; i-- {
+		ts = append(ts, now-float64(i))
+	}
+
+	// A single outlier, like a backup that was cleaned up, doesn't move the
+	// line
+	ys := ycoords(ts, -2, 3*now)
+	ys[8] -= 1000
+	f, err := TheilSenPredictor.Forecast(time.Minute, ts, ys)
+	c.Assert(err, IsNil)
+	c.Assert(f.Model, Equals, TheilSenModel)
+	c.Assert(f.Value, RoughlyEquals, now-120)
+	c.Assert(f.Lower, RoughlyEquals, f.Value)
+	c.Assert(f.Upper, RoughlyEquals, f.Value)
+
+	// Insufficient data
+	val, err := TheilSenPredictor.Predict(time.Minute, ts[:1], ys[:1])
+	c.Assert(val, Equals, zeroFloat)
+	c.Assert(err, Equals, ErrInsufficientData)
+}
+
+func (s *StatisticsSuite) TestHoltWintersPredictor(c *C) {
+	predictor := NewHoltWintersPredictor(time.Hour)
+	c.Assert(predictor.(Seasonal).Season(), Equals, time.Hour)
+
+	// Three hours of a slowly falling series that dips every hour
+	now := float64(time.Now().UTC().Unix())
+	value := func(t float64) float64 {
+		return 1e6 - (t-now)/10 - 500*math.Cos(2*math.Pi*t/3600)
+	}
+	var ts, ys []float64
+	for t := now - 3*3600; t < now; t += 60 {
+		ts = append(ts, t)
+		ys = append(ys, value(t))
+	}
+
+	f, err := predictor.Forecast(20*time.Minute, ts, ys)
+	c.Assert(err, IsNil)
+	c.Assert(f.Model, Equals, HoltWintersModel)
+	expected := value(now + 1200)
+	c.Assert(math.Abs(f.Value-expected) < 25, Equals, true, Commentf("got %f, expected %f", f.Value, expected))
+	c.Assert(f.Lower <= f.Value && f.Value <= f.Upper, Equals, true)
+
+	// A straight line through the same window misses the dip
+	ols, err := LeastSquaresPredictor.Predict(20*time.Minute, ts, ys)
+	c.Assert(err, IsNil)
+	c.Assert(math.Abs(ols-expected) > 100, Equals, true)
+
+	// Less than two seasons of data
+	val, err := predictor.Predict(20*time.Minute, ts[:100], ys[:100])
+	c.Assert(val, Equals, zeroFloat)
+	c.Assert(err, Equals, ErrInsufficientData)
+}
+
+func (s *StatisticsSuite) TestFitHoltWinters(c *C) {
+	// A rising series with a season of four steps
+	ys := []float64{}
+	for i := 0; i < 12; i++ {
+		ys = append(ys, float64(2*i)+[]float64{0, 10, 5, -5}[i%4])
+	}
+	hw, err := FitHoltWinters(ys, 4, 0.5, 0.1, 0.3)
+	c.Assert(err, IsNil)
+	c.Assert(hw.N, Equals, 8)
+	c.Assert(hw.SSE < 1e-9, Equals, true, Commentf("sse %f", hw.SSE))
+	c.Assert(math.Abs(hw.Forecast(1)-(24+0)) < 1e-9, Equals, true, Commentf("got %f", hw.Forecast(1)))
+	c.Assert(math.Abs(hw.Forecast(2)-(26+10)) < 1e-9, Equals, true, Commentf("got %f", hw.Forecast(2)))
+
+	// The seasonal factor is applied separately from the level's
+	flat, err := FitHoltWinters(append(ys[:11:11], 100), 4, 0.5, 0.1, 0)
+	c.Assert(err, IsNil)
+	adapted, err := FitHoltWinters(append(ys[:11:11], 100), 4, 0.5, 0.1, 0.9)
+	c.Assert(err, IsNil)
+	c.Assert(flat.Seasonal[3], Equals, hw.Seasonal[3])
+	c.Assert(adapted.Seasonal[3] > flat.Seasonal[3], Equals, true)
+
+	_, err = FitHoltWinters(ys[:7], 4, 0.5, 0.1, 0.3)
+	c.Assert(err, Equals, ErrInsufficientData)
+}
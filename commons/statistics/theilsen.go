@@ -0,0 +1,127 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// madScale converts the median absolute deviation into an estimate of the
+// standard deviation of normally distributed data
+const madScale = 1.4826
+
+// maxTheilSenPoints bounds the number of pairs the Theil-Sen estimator has
+// to compare
+const maxTheilSenPoints = 1000
+
+// Median calculates the median of an array of floats
+func Median(series []float64) float64 {
+	n := len(series)
+	if n == 0 {
+		return math.NaN()
+	}
+	sorted := make([]float64, n)
+	copy(sorted, series)
+	sort.Float64s(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// TheilSen calculates the slope and y-intercept of the line through the
+// series of points using the Theil-Sen estimator.  The slope is the median of
+// the slopes between every pair of points and the intercept is the median of
+// the intercepts through each point, so up to about 29% of the points can be
+// outliers without skewing the line.  Longer series are thinned to
+// maxTheilSenPoints evenly spaced points.
+func TheilSen(xs, ys []float64) (m, b float64, err error) {
+	lx, ly := len(xs), len(ys)
+
+	// If the arrays are not of equal length, we can't do anything
+	if lx != ly {
+		err = ErrUnequalArrays
+		return
+	}
+
+	if lx > maxTheilSenPoints {
+		xs, ys = thin(xs, ys, maxTheilSenPoints)
+		lx = len(xs)
+	}
+
+	slopes := make([]float64, 0, lx*(lx-1)/2)
+	for i := 0; i < lx; i++ {
+		for j := i + 1; j < lx; j++ {
+			if dx := xs[j] - xs[i]; dx != 0 {
+				slopes = append(slopes, (ys[j]-ys[i])/dx)
+			}
+		}
+	}
+
+	// If we don't have at least two distinct x-values, we can't do anything
+	if len(slopes) == 0 {
+		err = ErrInsufficientData
+		return
+	}
+	m = Median(slopes)
+
+	intercepts := make([]float64, lx)
+	for i, x := range xs {
+		intercepts[i] = ys[i] - m*x
+	}
+	b = Median(intercepts)
+	return
+}
+
+type theilSenPredictor struct{}
+
+func (p *theilSenPredictor) Predict(period time.Duration, timestamps, values []float64) (float64, error) {
+	forecast, err := p.Forecast(period, timestamps, values)
+	return forecast.Value, err
+}
+
+func (p *theilSenPredictor) Forecast(period time.Duration, timestamps, values []float64) (Forecast, error) {
+	then := float64(time.Now().UTC().Add(period).Unix())
+
+	m, b, err := TheilSen(timestamps, values)
+	if err != nil {
+		return Forecast{}, err
+	}
+
+	// Use the median absolute deviation of the residuals for the width of
+	// the interval, so the outliers ignored by the fit don't widen it either
+	residuals := make([]float64, len(timestamps))
+	for i, x := range timestamps {
+		residuals[i] = values[i] - (x*m + b)
+	}
+	center := Median(residuals)
+	for i, r := range residuals {
+		residuals[i] = math.Abs(r - center)
+	}
+	scale := madScale * Median(residuals)
+	return lineForecast(TheilSenModel, timestamps, m, b, then, scale), nil
+}
+
+// thin returns n points spread evenly across the series
+func thin(xs, ys []float64, n int) ([]float64, []float64) {
+	tx, ty := make([]float64, n), make([]float64, n)
+	last := float64(len(xs) - 1)
+	for i := 0; i < n; i++ {
+		j := int(float64(i) * last / float64(n-1))
+		tx[i], ty[i] = xs[j], ys[j]
+	}
+	return tx, ty
+}
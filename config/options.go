@@ -29,6 +29,12 @@ import (
 
 const (
 	minTimeout = 30
+
+	// DatastoreElastic keeps the master's data in the elasticsearch-serviced
+	// internal service
+	DatastoreElastic = "elastic"
+	// DatastoreLocal keeps the master's data in a file on the master's disk
+	DatastoreLocal = "local"
 )
 
 var (
@@ -128,8 +134,15 @@ type Options struct {
 	StorageMetricMonitorWindow int               // The amount of time in seconds for which serviced will consider storage availability metrics in order to predict future availability
 	StorageLookaheadPeriod     int               // The amount of time in the future in seconds serviced should predict storage availability for the purposes of emergency shutdown
 	StorageMinimumFreeSpace    string            // The amount of space the emergency shutdown algorithm should reserve when deciding to shut down
+	StorageForecastModel       string            // The model used to predict storage availability, optionally per thin pool device or tenant volume
+	StorageForecastSeason      int               // The length in seconds of the usage cycle followed by the holtwinters model
+	ThresholdEvalInterval      int               // The time in seconds between evaluations of monitoring profile thresholds; 0 disables alerting
+	NotifyConfig               string            // Path to the JSON file that configures notification sinks and routes
+	AuditRetentionDays         int               // The number of days audit events are kept in the datastore; 0 keeps them forever
 	BackupEstimatedCompression float64           // Best guess for tgz compression ratio (uncompressed size / compressed size) used to determine whether sufficient disk space is available for taking a backup
 	BackupMinOverhead          string            // Warn user if estimated backup size would leave less than this amount of space free
+	BackupKeyFile              string            // Path to the file with the passphrase that encrypts and signs backups
+	BackupTarget               string            // Directory or url of remote storage where backups are written by default
 	StartZK                    bool              // Should ZooKeeper ISVC be started
 	StartAPIKeyProxy           bool              // Should API Key Proxy ISVC be started
 	BigTableMetrics            bool              // Should serviced metrics be stored in gcp bigtable
@@ -138,9 +151,22 @@ type Options struct {
 	Auth0Group                 []string          // Group membership(s) required in Auth0 token for login, comma separated list
 	Auth0ClientID              string            // ClientID of Auth0 Application
 	Auth0Scope                 string            // Auth0 Scope for request.
+	OIDCIssuer                 string            // Issuer URL of the OpenID Connect provider used to log in users
+	OIDCClientID               string            // Client ID registered with the OpenID Connect provider
+	OIDCClientSecret           string            // Client secret registered with the OpenID Connect provider
+	OIDCScopes                 []string          // Scopes requested from the OpenID Connect provider
+	OIDCRedirectURL            string            // URL the OpenID Connect provider returns to after login; derived from the request if empty
+	OIDCAudience               string            // Audience required of OpenID Connect bearer tokens; defaults to the client ID
+	OIDCUsernameClaim          string            // Claim holding the user name in OpenID Connect tokens
+	OIDCGroupsClaim            string            // Claim holding the groups in OpenID Connect tokens
+	OIDCAdminGroups            []string          // Groups whose members are cluster administrators
+	OIDCGroupRoles             []string          // GROUP=ROLE[@tenant:ID|@pool:ID] role bindings granted to members of a group
 	KeyProxyJsonServer         string            // Address of api-key-server endpoint for getting CC Access tokens
 	KeyProxyListenPort         string            // Port where api-key-proxy will listen
 	ESRequestTimeout           int               // The http request connect timeout, in seconds, for an elasticsearch client connection.
+	DatastoreDriver            string            // Which driver stores the master's data: elastic or local
+	DatastorePath              string            // Path to the file used by the local datastore driver
+	SessionsPath               string            // Path where the master keeps recordings of shell sessions
 }
 
 // GetOptions returns a COPY of the global options struct
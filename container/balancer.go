@@ -0,0 +1,226 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+)
+
+const (
+	// ejectInterval is how long a backend is skipped after its first failed
+	// dial; the interval doubles with each consecutive failure.
+	ejectInterval = 5 * time.Second
+	// maxEjectInterval is the longest a backend will be skipped
+	maxEjectInterval = time.Minute
+)
+
+// backend is a remote address behind an imported endpoint along with the
+// bookkeeping the balancer needs to choose between addresses.
+type backend struct {
+	address      addressTuple
+	active       int       // number of open connections to the backend
+	failures     int       // number of consecutive failed dials
+	ejectedUntil time.Time // backend is skipped until this time
+}
+
+// balancer chooses the backend that receives each new connection to an
+// imported endpoint.
+type balancer struct {
+	mu       sync.Mutex
+	policy   servicedefinition.LoadBalancing
+	healthy  func(serviceID string, instanceID int) bool
+	backends []*backend
+	next     int
+	now      func() time.Time
+}
+
+func newBalancer() *balancer {
+	return &balancer{now: time.Now}
+}
+
+// SetPolicy updates the load balancing policy and the function used to
+// check the health of an instance for the health aware policy.
+func (lb *balancer) SetPolicy(policy servicedefinition.LoadBalancing, healthy func(serviceID string, instanceID int) bool) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	lb.policy = policy
+	lb.healthy = healthy
+}
+
+// SetAddresses replaces the set of backends, keeping the connection counts
+// and failure history of addresses that are still present.
+func (lb *balancer) SetAddresses(addresses []addressTuple) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	current := make(map[addressTuple]*backend)
+	for _, b := range lb.backends {
+		current[b.address] = b
+	}
+
+	backends := make([]*backend, len(addresses))
+	for i, address := range addresses {
+		if b, ok := current[address]; ok {
+			backends[i] = b
+		} else {
+			backends[i] = &backend{address: address}
+		}
+	}
+	lb.backends = backends
+}
+
+// Len returns the number of backends
+func (lb *balancer) Len() int {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	return len(lb.backends)
+}
+
+// Pick chooses a backend that is not in the exclude set for a connection
+// from the client address and counts the connection against it.  Returns nil
+// if there are no backends left to choose from.
+func (lb *balancer) Pick(clientIP string, exclude map[*backend]bool) *backend {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	now := lb.now()
+	var available, ejected []*backend
+	for _, b := range lb.backends {
+		if exclude[b] {
+			continue
+		}
+		if now.Before(b.ejectedUntil) {
+			ejected = append(ejected, b)
+		} else {
+			available = append(available, b)
+		}
+	}
+
+	// if every backend has been ejected, try them anyway rather than
+	// refusing the connection
+	if len(available) == 0 {
+		available = ejected
+	}
+	if len(available) == 0 {
+		return nil
+	}
+
+	var b *backend
+	switch lb.policy {
+	case servicedefinition.LeastConnections:
+		b = lb.leastConnections(available)
+	case servicedefinition.ClientIP:
+		b = lb.clientIP(clientIP, available)
+	case servicedefinition.HealthAware:
+		b = lb.roundRobin(lb.filterHealthy(available))
+	default:
+		b = lb.roundRobin(available)
+	}
+	b.active++
+	return b
+}
+
+// Release uncounts a connection that was handed to the backend
+func (lb *balancer) Release(b *backend) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	if b.active > 0 {
+		b.active--
+	}
+}
+
+// Fail records a failed dial and ejects the backend
+func (lb *balancer) Fail(b *backend) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	interval := ejectInterval
+	for i := 0; i < b.failures && interval < maxEjectInterval; i++ {
+		interval *= 2
+	}
+	if interval > maxEjectInterval {
+		interval = maxEjectInterval
+	}
+	b.failures++
+	b.ejectedUntil = lb.now().Add(interval)
+}
+
+// Succeed records a successful dial and returns the backend to service
+func (lb *balancer) Succeed(b *backend) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	b.failures = 0
+	b.ejectedUntil = time.Time{}
+}
+
+func (lb *balancer) roundRobin(backends []*backend) *backend {
+	b := backends[lb.next%len(backends)]
+	lb.next++
+	return b
+}
+
+// leastConnections returns the backend with the fewest open connections,
+// taking turns between backends that are tied.
+func (lb *balancer) leastConnections(backends []*backend) *backend {
+	offset := lb.next % len(backends)
+	lb.next++
+	var least *backend
+	for i := range backends {
+		b := backends[(offset+i)%len(backends)]
+		if least == nil || b.active < least.active {
+			least = b
+		}
+	}
+	return least
+}
+
+// clientIP uses rendezvous hashing so that a client keeps landing on the
+// same backend and only the clients of a backend that goes away move.
+func (lb *balancer) clientIP(clientIP string, backends []*backend) *backend {
+	var (
+		picked *backend
+		max    uint64
+	)
+	for _, b := range backends {
+		h := fnv.New64a()
+		h.Write([]byte(clientIP))
+		h.Write([]byte(b.address.host))
+		h.Write([]byte(b.address.containerAddr))
+		if sum := h.Sum64(); picked == nil || sum > max {
+			picked, max = b, sum
+		}
+	}
+	return picked
+}
+
+// filterHealthy returns the backends whose instances are passing their
+// health checks.  If none are, all of the backends are returned.
+func (lb *balancer) filterHealthy(backends []*backend) []*backend {
+	if lb.healthy == nil {
+		return backends
+	}
+	var healthy []*backend
+	for _, b := range backends {
+		if lb.healthy(b.address.serviceID, b.address.instanceID) {
+			healthy = append(healthy, b)
+		}
+	}
+	if len(healthy) == 0 {
+		return backends
+	}
+	return healthy
+}
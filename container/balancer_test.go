@@ -0,0 +1,183 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package container
+
+import (
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+)
+
+func testAddresses(n int) []addressTuple {
+	addresses := make([]addressTuple, n)
+	for i := range addresses {
+		addresses[i] = addressTuple{
+			host:          "10.0.0.1",
+			containerAddr: "172.17.0." + string('1'+byte(i)) + ":8080",
+			serviceID:     "svc",
+			instanceID:    i,
+		}
+	}
+	return addresses
+}
+
+func TestBalancerRoundRobin(t *testing.T) {
+	lb := newBalancer()
+	lb.SetAddresses(testAddresses(3))
+
+	seen := make(map[int]int)
+	for i := 0; i < 6; i++ {
+		b := lb.Pick("", nil)
+		seen[b.address.instanceID]++
+	}
+	for i := 0; i < 3; i++ {
+		if seen[i] != 2 {
+			t.Errorf("Expected instance %d to be picked twice, got %d", i, seen[i])
+		}
+	}
+}
+
+func TestBalancerLeastConnections(t *testing.T) {
+	lb := newBalancer()
+	lb.SetPolicy(servicedefinition.LeastConnections, nil)
+	lb.SetAddresses(testAddresses(3))
+
+	picked := make(map[*backend]bool)
+	for i := 0; i < 3; i++ {
+		picked[lb.Pick("", nil)] = true
+	}
+	if len(picked) != 3 {
+		t.Fatalf("Expected each backend to get a connection, got %d backends", len(picked))
+	}
+
+	busy := lb.Pick("", nil)
+	for i := 0; i < 2; i++ {
+		if b := lb.Pick("", nil); b == busy {
+			t.Errorf("Expected a backend with fewer connections than %v", busy.address)
+		}
+	}
+	for _, b := range lb.backends {
+		if b.active != 2 {
+			t.Errorf("Expected 2 connections to %v, got %d", b.address, b.active)
+		}
+	}
+
+	lb.Release(busy)
+	if b := lb.Pick("", nil); b != busy {
+		t.Errorf("Expected %v, got %v", busy.address, b.address)
+	}
+}
+
+func TestBalancerClientIP(t *testing.T) {
+	lb := newBalancer()
+	lb.SetPolicy(servicedefinition.ClientIP, nil)
+	lb.SetAddresses(testAddresses(4))
+
+	first := lb.Pick("192.168.1.10", nil)
+	for i := 0; i < 5; i++ {
+		if b := lb.Pick("192.168.1.10", nil); b != first {
+			t.Fatalf("Expected client to stick to %v, got %v", first.address, b.address)
+		}
+	}
+
+	// removing a different backend does not move the client
+	var addresses []addressTuple
+	for _, b := range lb.backends {
+		if b != first && len(addresses) < 2 {
+			continue
+		}
+		addresses = append(addresses, b.address)
+	}
+	lb.SetAddresses(addresses)
+	if b := lb.Pick("192.168.1.10", nil); b != first {
+		t.Errorf("Expected client to stick to %v, got %v", first.address, b.address)
+	}
+}
+
+func TestBalancerHealthAware(t *testing.T) {
+	unhealthy := map[int]bool{0: true, 2: true}
+	lb := newBalancer()
+	lb.SetPolicy(servicedefinition.HealthAware, func(serviceID string, instanceID int) bool {
+		return !unhealthy[instanceID]
+	})
+	lb.SetAddresses(testAddresses(3))
+
+	for i := 0; i < 4; i++ {
+		if b := lb.Pick("", nil); b.address.instanceID != 1 {
+			t.Errorf("Expected the healthy instance, got %d", b.address.instanceID)
+		}
+	}
+
+	// fail open if nothing is healthy
+	unhealthy[1] = true
+	if b := lb.Pick("", nil); b == nil {
+		t.Errorf("Expected a backend when no instances are healthy")
+	}
+}
+
+func TestBalancerEjection(t *testing.T) {
+	now := time.Now()
+	lb := newBalancer()
+	lb.now = func() time.Time { return now }
+	lb.SetAddresses(testAddresses(2))
+
+	failed := lb.Pick("", nil)
+	lb.Release(failed)
+	lb.Fail(failed)
+	if expected := now.Add(ejectInterval); !failed.ejectedUntil.Equal(expected) {
+		t.Errorf("Expected ejection until %s, got %s", expected, failed.ejectedUntil)
+	}
+	for i := 0; i < 4; i++ {
+		if b := lb.Pick("", nil); b == failed {
+			t.Errorf("Expected ejected backend to be skipped")
+		}
+	}
+
+	// excluding the only available backend falls back to the ejected one
+	tried := make(map[*backend]bool)
+	tried[lb.Pick("", nil)] = true
+	if b := lb.Pick("", tried); b != failed {
+		t.Errorf("Expected ejected backend as a last resort")
+	}
+	tried[failed] = true
+	if b := lb.Pick("", tried); b != nil {
+		t.Errorf("Expected no backend, got %v", b.address)
+	}
+
+	// the ejection backs off with each failure up to the maximum
+	for i := 0; i < 10; i++ {
+		lb.Fail(failed)
+	}
+	if expected := now.Add(maxEjectInterval); !failed.ejectedUntil.Equal(expected) {
+		t.Errorf("Expected ejection until %s, got %s", expected, failed.ejectedUntil)
+	}
+
+	// stats survive an address update and success restores the backend
+	lb.SetAddresses(testAddresses(2))
+	if lb.backends[failed.address.instanceID] != failed {
+		t.Errorf("Expected backend to be kept across address updates")
+	}
+	lb.Succeed(failed)
+	now = now.Add(time.Second)
+	seen := false
+	for i := 0; i < 2; i++ {
+		seen = seen || lb.Pick("", nil) == failed
+	}
+	if !seen {
+		t.Errorf("Expected backend to be picked after a successful dial")
+	}
+}
@@ -439,6 +439,7 @@ func NewController(options ControllerOptions) (*Controller, error) {
 		TCPMuxPort:           uint16(options.Mux.Port),
 		UseTLS:               !options.Mux.DisableTLS,
 		VirtualAddressSubnet: options.VirtualAddressSubnet,
+		ServicedEndpoint:     options.ServicedEndpoint,
 	}
 	c.endpoints, err = NewContainerEndpoints(service, opts)
 	if err != nil {
@@ -836,7 +837,7 @@ func (c *Controller) kickOffHealthChecks(healthExit chan struct{}) {
 	}
 	for name, hc := range c.healthChecks {
 		glog.Infof("Kicking off health check %s.", name)
-		glog.Infof("Setting up health check: %s", hc.Script)
+		glog.Infof("Setting up health check: %s", hc.String())
 		key := health.HealthStatusKey{
 			ServiceID:       c.options.Service.ID,
 			InstanceID:      instanceID,
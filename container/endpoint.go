@@ -24,6 +24,7 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/zzk"
 	"github.com/control-center/serviced/zzk/registry"
 	zkservice "github.com/control-center/serviced/zzk/service"
@@ -38,6 +39,7 @@ type ContainerEndpointsOptions struct {
 	TCPMuxPort           uint16
 	UseTLS               bool
 	VirtualAddressSubnet string
+	ServicedEndpoint     string
 }
 
 // ContainerEndpoints manages import and export bindings for the instance.
@@ -66,7 +68,7 @@ func NewContainerEndpoints(svc *service.Service, opts ContainerEndpointsOptions)
 	}
 
 	// set up the proxy cache
-	ce.cache = newProxyCache(opts.TenantID, opts.TCPMuxPort, opts.UseTLS, allowDirect)
+	ce.cache = newProxyCache(opts.TenantID, opts.TCPMuxPort, opts.UseTLS, allowDirect, newInstanceHealth(opts.ServicedEndpoint))
 
 	// set up virtual interface registry
 	if err := ce.vifs.SetSubnet(opts.VirtualAddressSubnet); err != nil {
@@ -118,6 +120,7 @@ func (ce *ContainerEndpoints) loadState(svc *service.Service) (bool, error) {
 					Purpose:        ep.Purpose,
 					PortNumber:     ep.PortNumber,
 					VirtualAddress: ep.VirtualAddress,
+					LoadBalancing:  ep.LoadBalancing,
 				})
 			}
 		}
@@ -210,6 +213,7 @@ func (ce *ContainerEndpoints) AddExport(cancel <-chan struct{}, bind zkservice.E
 		PrivateIP:     ce.state.PrivateIP,
 		HostIP:        ce.state.HostIP,
 		MuxPort:       ce.opts.TCPMuxPort,
+		ServiceID:     ce.state.ServiceID,
 		InstanceID:    ce.state.InstanceID,
 	}
 
@@ -377,7 +381,7 @@ func (ce *ContainerEndpoints) UpdateRemoteExports(bind zkservice.ImportBinding,
 			}
 
 			// update the proxy; returns a boolean if a new proxy was created.
-			isNew, err := ce.cache.Set(bind.Application, port, export)
+			isNew, err := ce.cache.Set(bind.Application, port, bind.LoadBalancing, export)
 			if err != nil {
 				exLogger.WithError(err).Error("Could not update proxy")
 				return
@@ -433,7 +437,7 @@ func (ce *ContainerEndpoints) UpdateRemoteExports(bind zkservice.ImportBinding,
 		}
 
 		// update the proxy
-		isNew, err := ce.cache.Set(bind.Application, port, exports...)
+		isNew, err := ce.cache.Set(bind.Application, port, bind.LoadBalancing, exports...)
 		if err != nil {
 			exLogger.WithError(err).Error("Could not update proxy")
 			return
@@ -476,9 +480,10 @@ type proxyCache struct {
 	tcpMuxPort  uint16
 	useTLS      bool
 	allowDirect bool
+	health      *instanceHealth
 }
 
-func newProxyCache(tenantID string, tcpMuxPort uint16, useTLS, allowDirect bool) *proxyCache {
+func newProxyCache(tenantID string, tcpMuxPort uint16, useTLS, allowDirect bool, health *instanceHealth) *proxyCache {
 	return &proxyCache{
 		mu:          &sync.Mutex{},
 		cache:       make(map[proxyKey]*proxy),
@@ -486,11 +491,12 @@ func newProxyCache(tenantID string, tcpMuxPort uint16, useTLS, allowDirect bool)
 		tcpMuxPort:  tcpMuxPort,
 		useTLS:      useTLS,
 		allowDirect: allowDirect,
+		health:      health,
 	}
 }
 
 // Set returns true if the key was created and an error
-func (c *proxyCache) Set(application string, portNumber uint16, exports ...registry.ExportDetails) (bool, error) {
+func (c *proxyCache) Set(application string, portNumber uint16, policy servicedefinition.LoadBalancing, exports ...registry.ExportDetails) (bool, error) {
 	logger := plog.WithFields(log.Fields{
 		"application": application,
 		"portnumber":  portNumber,
@@ -544,8 +550,11 @@ func (c *proxyCache) Set(application string, portNumber uint16, exports ...regis
 		addresses[i] = addressTuple{
 			host:          export.HostIP,
 			containerAddr: fmt.Sprintf("%s:%d", export.PrivateIP, export.PortNumber),
+			serviceID:     export.ServiceID,
+			instanceID:    export.InstanceID,
 		}
 	}
+	prxy.SetLoadBalancing(policy, c.health.Healthy)
 	prxy.SetNewAddresses(addresses)
 
 	logger.Debug("Set exports for proxy")
@@ -0,0 +1,90 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/health"
+	"github.com/control-center/serviced/node"
+)
+
+// healthRefreshInterval is how long health check results are cached
+const healthRefreshInterval = 10 * time.Second
+
+// instanceHealth caches the health check results of service instances for
+// proxies that use the health aware load balancing policy.  Results are
+// refreshed in the background, so a lookup never waits on the agent.
+type instanceHealth struct {
+	mu       sync.Mutex
+	endpoint string
+	services map[string]struct{}
+	statuses map[string]map[int]map[string]health.HealthStatus
+	updated  time.Time
+	updating bool
+}
+
+func newInstanceHealth(endpoint string) *instanceHealth {
+	return &instanceHealth{endpoint: endpoint, services: make(map[string]struct{})}
+}
+
+// Healthy returns false if any of the health checks for the instance are
+// failing.  Instances without results are considered healthy.  Only the
+// results of services that were looked up are loaded from the agent.
+func (h *instanceHealth) Healthy(serviceID string, instanceID int) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.services[serviceID]; !ok {
+		h.services[serviceID] = struct{}{}
+		h.updated = time.Time{}
+	}
+	if h.endpoint != "" && !h.updating && time.Since(h.updated) > healthRefreshInterval {
+		h.updating = true
+		serviceIDs := make([]string, 0, len(h.services))
+		for id := range h.services {
+			serviceIDs = append(serviceIDs, id)
+		}
+		go h.refresh(serviceIDs)
+	}
+
+	for _, status := range h.statuses[serviceID][instanceID] {
+		if status.Status == health.Failed {
+			return false
+		}
+	}
+	return true
+}
+
+// refresh loads the latest health check results of the services from the
+// agent
+func (h *instanceHealth) refresh(serviceIDs []string) {
+	var statuses map[string]map[int]map[string]health.HealthStatus
+	client, err := node.NewLBClient(h.endpoint)
+	if err == nil {
+		defer client.Close()
+		err = client.GetServicesHealth(serviceIDs, &statuses)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.updating = false
+	h.updated = time.Now()
+	if err != nil {
+		plog.WithError(err).Debug("Could not load health check results; keeping the previous results")
+		return
+	}
+	h.statuses = statuses
+}
@@ -15,15 +15,18 @@ package container
 
 import (
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"math/rand"
 	"net"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/utils"
 	"github.com/zenoss/glog"
 )
@@ -71,9 +74,18 @@ The netcat (nc) command is particularly useful for this:
     nc 127.0.0.1 4321
 */
 
+// maxDialAttempts is the number of backends a connection is offered to
+// before it is dropped
+const maxDialAttempts = 3
+
+// errNoAuthToken is returned when the mux header cannot be signed
+var errNoAuthToken = errors.New("unable to retrieve authentication token")
+
 type addressTuple struct {
 	host          string // IP of the host on which the container is running
 	containerAddr string // Container IP:port of the remote service
+	serviceID     string // ID of the service exporting the endpoint
+	instanceID    int    // Instance ID of the remote service
 }
 
 type proxy struct {
@@ -86,6 +98,7 @@ type proxy struct {
 	newAddresses     chan []addressTuple // a stream of updates to the addresses
 	listener         net.Listener        // handle on the listening socket
 	allowDirectConn  bool                // allow container to container connections
+	lb               *balancer           // chooses the address for each connection
 }
 
 // Newproxy create a new proxy object. It starts listening on the prxy port asynchronously.
@@ -101,6 +114,7 @@ func newProxy(name, tenantEndpointID string, tcpMuxPort uint16, useTLS bool, lis
 		useTLS:           useTLS,
 		listener:         listener,
 		allowDirectConn:  allowDirectConn,
+		lb:               newBalancer(),
 	}
 	p.newAddresses = make(chan []addressTuple, 2)
 	go p.listenAndproxy()
@@ -138,6 +152,12 @@ func (p *proxy) SetNewAddresses(addresses []addressTuple) {
 	p.newAddresses <- dest
 }
 
+// SetLoadBalancing sets the policy used to choose the address for each
+// connection and the health lookup used by the health aware policy.
+func (p *proxy) SetLoadBalancing(policy servicedefinition.LoadBalancing, healthy func(serviceID string, instanceID int) bool) {
+	p.lb.SetPolicy(policy, healthy)
+}
+
 // Close() terminates the prxy; it can not be restarted.
 func (p *proxy) Close() error {
 	p.listener.Close()
@@ -161,20 +181,18 @@ func (p *proxy) listenAndproxy() {
 		}
 	}(p.listener, connections)
 
-	i := 0
 	for {
 		select {
 		case conn := <-connections:
-			if len(p.addresses) == 0 {
+			if p.lb.Len() == 0 {
 				glog.Warningf("No remote services available for prxying %v", p)
 				conn.Close()
 				continue
 			}
-			i++
-			// round robin connections to list of addresses
 			glog.V(1).Infof("choosing address from %v", p.addresses)
-			go p.prxy(conn, p.addresses[i%len(p.addresses)])
+			go p.prxy(conn)
 		case p.addresses = <-p.newAddresses:
+			p.lb.SetAddresses(p.addresses)
 		case errc := <-p.closing:
 			p.listener.Close()
 			errc <- nil
@@ -192,10 +210,98 @@ func getPort(addr string) (int, error) {
 	return strconv.Atoi(port)
 }
 
-// prxy takes an established local connection, Dials the remote address specified
-// by the proxy structure and then copies data to and from the resulting pair
-// of endpoints.
-func (p *proxy) prxy(local net.Conn, address addressTuple) {
+// prxy takes an established local connection, Dials a remote address chosen
+// by the proxy's balancer and then copies data to and from the resulting pair
+// of endpoints.  Addresses that cannot be dialed are ejected from the
+// balancer and the connection is offered to another address.  Addresses that
+// close the connection before replying are ejected too, but the connection
+// is not retried because the client's data has already been sent.
+func (p *proxy) prxy(local net.Conn) {
+	clientIP, _, _ := net.SplitHostPort(local.RemoteAddr().String())
+	tried := make(map[*backend]bool)
+	for attempt := 0; attempt < maxDialAttempts; attempt++ {
+		b := p.lb.Pick(clientIP, tried)
+		if b == nil {
+			break
+		}
+		tried[b] = true
+
+		remote, err := p.dial(b.address)
+		if err == errNoAuthToken {
+			p.lb.Release(b)
+			break
+		} else if err != nil {
+			glog.Warningf("Could not connect to %v, trying another address: %s", b.address, err)
+			p.lb.Release(b)
+			p.lb.Fail(b)
+			continue
+		}
+
+		glog.V(2).Infof("Using hostAgent:%v to prxy %v<->%v<->%v<->%v",
+			remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), b.address)
+		var wg sync.WaitGroup
+		wg.Add(2)
+		clientDone := make(chan struct{})
+		go func(b *backend) {
+			defer wg.Done()
+			defer local.Close()
+			defer remote.Close()
+			// A mux that rejects the connection, or a remote that goes
+			// away, closes it without replying, so the backend is only
+			// returned to service once it replies.
+			reply := &replyReader{Reader: remote, onReply: func() { p.lb.Succeed(b) }}
+			io.Copy(local, reply)
+			select {
+			case <-clientDone:
+			default:
+				if !reply.replied {
+					glog.Warningf("Connection to %v was closed before it replied", b.address)
+					p.lb.Fail(b)
+				}
+			}
+			glog.V(2).Infof("Closing hostAgent:%v to prxy %v<->%v<->%v<->%v",
+				remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), b.address.containerAddr)
+		}(b)
+		go func(address string) {
+			defer wg.Done()
+			defer local.Close()
+			defer remote.Close()
+			io.Copy(remote, local)
+			close(clientDone)
+			glog.V(2).Infof("closing hostAgent:%v to prxy %v<->%v<->%v<->%v",
+				remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), address)
+		}(b.address.containerAddr)
+		go func() {
+			wg.Wait()
+			p.lb.Release(b)
+		}()
+		return
+	}
+	glog.Warningf("Could not connect to any remote service for prxying %v", p)
+	local.Close()
+}
+
+// replyReader reads the remote end of a proxied connection and calls onReply
+// when the first data arrives
+type replyReader struct {
+	io.Reader
+	replied bool
+	onReply func()
+}
+
+// Read implements io.Reader
+func (r *replyReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 && !r.replied {
+		r.replied = true
+		r.onReply()
+	}
+	return n, err
+}
+
+// dial connects to the address, either directly to a container on this host
+// or through the mux port on the remote host, and writes the mux header.
+func (p *proxy) dial(address addressTuple) (net.Conn, error) {
 
 	var (
 		remote net.Conn
@@ -248,13 +354,13 @@ func (p *proxy) prxy(local net.Conn, address addressTuple) {
 		muxAddrPacked, err = utils.PackTCPAddressString(address.containerAddr)
 		if err != nil {
 			glog.Errorf("Container address is invalid. Can't create proxy: %s", address.containerAddr)
-			return
+			return nil, err
 		}
 		select {
 		case token = <-auth.AuthToken(nil):
 		case <-time.After(tokenTimeout):
 			glog.Error("Unable to retrieve authentication token with 30 seconds")
-			return
+			return nil, errNoAuthToken
 		}
 	}
 
@@ -266,7 +372,7 @@ func (p *proxy) prxy(local net.Conn, address addressTuple) {
 		remote, err = net.Dial("tcp4", localAddr)
 		if err != nil {
 			glog.Errorf("Error Local (net.Dial): %s", err)
-			return
+			return nil, err
 		}
 	case p.useTLS:
 		glog.V(2).Infof("dialing remote tls => %s", muxAddr)
@@ -274,7 +380,7 @@ func (p *proxy) prxy(local net.Conn, address addressTuple) {
 		tlsConn, err := tls.Dial("tcp4", muxAddr, &config)
 		if err != nil {
 			glog.Errorf("Error TLS (net.Dial): %s", err)
-			return
+			return nil, err
 		}
 		remote = tlsConn // cast it to the net.Conn interface
 		cipher := tlsConn.ConnectionState().CipherSuite
@@ -284,7 +390,7 @@ func (p *proxy) prxy(local net.Conn, address addressTuple) {
 		remote, err = net.Dial("tcp4", muxAddr)
 		if err != nil {
 			glog.Errorf("Error Remote (net.Dial): %s", err)
-			return
+			return nil, err
 		}
 	}
 
@@ -292,21 +398,5 @@ func (p *proxy) prxy(local net.Conn, address addressTuple) {
 	if token != "" && len(muxAddrPacked) > 0 {
 		auth.AddSignedMuxHeader(remote, muxAddrPacked, token)
 	}
-
-	glog.V(2).Infof("Using hostAgent:%v to prxy %v<->%v<->%v<->%v",
-		remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), address)
-	go func(address string) {
-		defer local.Close()
-		defer remote.Close()
-		io.Copy(local, remote)
-		glog.V(2).Infof("Closing hostAgent:%v to prxy %v<->%v<->%v<->%v",
-			remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), address)
-	}(address.containerAddr)
-	go func(address string) {
-		defer local.Close()
-		defer remote.Close()
-		io.Copy(remote, local)
-		glog.V(2).Infof("closing hostAgent:%v to prxy %v<->%v<->%v<->%v",
-			remote.RemoteAddr(), local.LocalAddr(), local.RemoteAddr(), remote.LocalAddr(), address)
-	}(address.containerAddr)
+	return remote, nil
 }
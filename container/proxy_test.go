@@ -62,7 +62,7 @@ func TestNoMux(t *testing.T) {
 		t.Fatalf("Could not create a prxy: %s", err)
 	}
 	host := strings.Split(remote.Addr().String(), ":")[0]
-	addresses := []addressTuple{addressTuple{host, remote.Addr().String()}}
+	addresses := []addressTuple{addressTuple{host: host, containerAddr: remote.Addr().String()}}
 	prxy.SetNewAddresses(addresses)
 	stringChan := stringAcceptor(remote)
 	conn, err := net.Dial("tcp4", local.Addr().String())
@@ -92,3 +92,25 @@ func TestNoMux(t *testing.T) {
 		t.Fatalf("Timed out reading response from test port")
 	}
 }
+
+func TestReplyReader(t *testing.T) {
+	replies := 0
+	reply := &replyReader{Reader: strings.NewReader("response"), onReply: func() { replies++ }}
+	buffer := make([]byte, 4)
+	for i := 0; i < 2; i++ {
+		if _, err := reply.Read(buffer); err != nil {
+			t.Fatalf("Could not read the reply: %s", err)
+		}
+	}
+	if !reply.replied || replies != 1 {
+		t.Fatalf("Expected one reply notification, got %d", replies)
+	}
+
+	reply = &replyReader{Reader: strings.NewReader(""), onReply: func() { replies++ }}
+	if _, err := reply.Read(buffer); err == nil {
+		t.Fatalf("Expected the closed connection to return an error")
+	}
+	if reply.replied || replies != 1 {
+		t.Fatalf("Expected a closed connection not to count as a reply")
+	}
+}
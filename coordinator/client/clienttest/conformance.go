@@ -0,0 +1,599 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit integration
+
+// Package clienttest provides a conformance suite that every
+// coordinator/client driver must pass.
+package clienttest
+
+import (
+	"fmt"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+// Connect opens a new connection with its own session to the coordination
+// service under test, rooted at basePath.
+type Connect func(basePath string) (client.Connection, error)
+
+// TestNode is the node stored by the conformance suite
+type TestNode struct {
+	Name    string
+	version interface{}
+}
+
+// Version implements client.Node
+func (n *TestNode) Version() interface{} { return n.version }
+
+// SetVersion implements client.Node
+func (n *TestNode) SetVersion(version interface{}) { n.version = version }
+
+// blockTimeout is how long an operation must block to be considered blocked,
+// and how long to wait for one that should not block.
+const blockTimeout = 500 * time.Millisecond
+
+// RunConformance runs the conformance suite against a driver. Each test uses
+// its own base path so the suite can share a single service.
+func RunConformance(t *testing.T, connect Connect) {
+	tests := []struct {
+		name string
+		test func(*testing.T, Connect, string)
+	}{
+		{"CRUD", testCRUD},
+		{"VersionConflict", testVersionConflict},
+		{"Multi", testMulti},
+		{"Ephemeral", testEphemeral},
+		{"Sequential", testSequential},
+		{"Watch", testWatch},
+		{"CancelWatch", testCancelWatch},
+		{"Lock", testLock},
+		{"Leader", testLeader},
+	}
+	for _, tt := range tests {
+		basePath := fmt.Sprintf("/conformance-%s-%d", tt.name, time.Now().UnixNano())
+		test := tt.test
+		t.Run(tt.name, func(t *testing.T) { test(t, connect, basePath) })
+	}
+}
+
+func mustConnect(t *testing.T, connect Connect, basePath string) client.Connection {
+	conn, err := connect(basePath)
+	if err != nil {
+		t.Fatalf("Could not connect: %s", err)
+	}
+	return conn
+}
+
+func testCRUD(t *testing.T, connect Connect, basePath string) {
+	conn := mustConnect(t, connect, basePath)
+	defer conn.Close()
+
+	if exists, err := conn.Exists("/foo"); err != nil {
+		t.Fatalf("Could not check /foo: %s", err)
+	} else if exists {
+		t.Fatalf("/foo should not exist")
+	}
+	if err := conn.Delete("/foo"); err != client.ErrNoNode {
+		t.Fatalf("Expected %s deleting /foo, got %v", client.ErrNoNode, err)
+	}
+	if err := conn.CreateDir("/foo"); err != nil {
+		t.Fatalf("Could not create /foo: %s", err)
+	}
+	if err := conn.CreateDir("/foo"); err != client.ErrNodeExists {
+		t.Fatalf("Expected %s creating /foo again, got %v", client.ErrNodeExists, err)
+	}
+	if err := conn.Get("/foo", &TestNode{}); err != client.ErrEmptyNode {
+		t.Fatalf("Expected %s getting /foo, got %v", client.ErrEmptyNode, err)
+	}
+
+	node := &TestNode{Name: "test"}
+	if err := conn.Create("/foo/bar", node); err != nil {
+		t.Fatalf("Could not create /foo/bar: %s", err)
+	}
+	if node.Version() == nil {
+		t.Errorf("Expected a version to be set on /foo/bar")
+	}
+	if err := conn.CreateIfExists("/missing/bar", &TestNode{}); err != client.ErrNoNode {
+		t.Errorf("Expected %s creating /missing/bar, got %v", client.ErrNoNode, err)
+	}
+
+	out := &TestNode{}
+	if err := conn.Get("/foo/bar", out); err != nil {
+		t.Fatalf("Could not get /foo/bar: %s", err)
+	} else if out.Name != "test" {
+		t.Errorf("Expected test, got %s", out.Name)
+	}
+	out.Name = "abc"
+	if err := conn.Set("/foo/bar", out); err != nil {
+		t.Fatalf("Could not update /foo/bar: %s", err)
+	}
+	if err := conn.Get("/foo/bar", out); err != nil {
+		t.Fatalf("Could not get /foo/bar: %s", err)
+	} else if out.Name != "abc" {
+		t.Errorf("Expected abc, got %s", out.Name)
+	}
+	if err := conn.Set("/foo/missing", out); err != client.ErrNoNode {
+		t.Errorf("Expected %s setting /foo/missing, got %v", client.ErrNoNode, err)
+	}
+
+	if err := conn.CreateDir("/foo/baz"); err != nil {
+		t.Fatalf("Could not create /foo/baz: %s", err)
+	}
+	if children, err := conn.Children("/foo"); err != nil {
+		t.Fatalf("Could not get the children of /foo: %s", err)
+	} else if len(children) != 2 {
+		t.Errorf("Expected 2 children of /foo, got %v", children)
+	}
+	if _, err := conn.Children("/missing"); err != client.ErrNoNode {
+		t.Errorf("Expected %s listing /missing, got %v", client.ErrNoNode, err)
+	}
+
+	// delete is recursive
+	if err := conn.Delete("/foo"); err != nil {
+		t.Fatalf("Could not delete /foo: %s", err)
+	}
+	if exists, err := conn.Exists("/foo/bar"); err != nil {
+		t.Fatalf("Could not check /foo/bar: %s", err)
+	} else if exists {
+		t.Errorf("/foo/bar should have been deleted")
+	}
+
+	// create makes the parent path
+	if err := conn.CreateDir("/fum/bar/baz/echo/p/q"); err != nil {
+		t.Fatalf("Could not create /fum/bar/baz/echo/p/q: %s", err)
+	}
+	if exists, err := conn.Exists("/fum/bar/baz/echo/p/q"); err != nil {
+		t.Fatalf("Could not check /fum/bar/baz/echo/p/q: %s", err)
+	} else if !exists {
+		t.Errorf("/fum/bar/baz/echo/p/q should exist")
+	}
+}
+
+func testVersionConflict(t *testing.T, connect Connect, basePath string) {
+	conn := mustConnect(t, connect, basePath)
+	defer conn.Close()
+
+	if err := conn.Create("/node", &TestNode{Name: "a"}); err != nil {
+		t.Fatalf("Could not create /node: %s", err)
+	}
+	first, second := &TestNode{}, &TestNode{}
+	if err := conn.Get("/node", first); err != nil {
+		t.Fatalf("Could not get /node: %s", err)
+	}
+	if err := conn.Get("/node", second); err != nil {
+		t.Fatalf("Could not get /node: %s", err)
+	}
+	first.Name = "b"
+	if err := conn.Set("/node", first); err != nil {
+		t.Fatalf("Could not update /node: %s", err)
+	}
+	second.Name = "c"
+	if err := conn.Set("/node", second); err != client.ErrBadVersion {
+		t.Fatalf("Expected %s updating a stale /node, got %v", client.ErrBadVersion, err)
+	}
+
+	// the update succeeds with the latest version
+	if err := conn.Get("/node", second); err != nil {
+		t.Fatalf("Could not get /node: %s", err)
+	} else if second.Name != "b" {
+		t.Errorf("Expected b, got %s", second.Name)
+	}
+	second.Name = "c"
+	if err := conn.Set("/node", second); err != nil {
+		t.Fatalf("Could not update /node: %s", err)
+	}
+}
+
+func testMulti(t *testing.T, connect Connect, basePath string) {
+	conn := mustConnect(t, connect, basePath)
+	defer conn.Close()
+
+	if err := conn.CreateDir("/"); err != nil {
+		t.Fatalf("Could not create %s: %s", basePath, err)
+	}
+	node0, node1 := &TestNode{Name: "test0"}, &TestNode{Name: "test1"}
+
+	// creating a node and setting a missing one does not commit
+	err := conn.NewTransaction().Create("/test0", node0).Set("/test1", node1).Commit()
+	if err == nil {
+		t.Fatalf("Creating /test0 and setting /test1 should have failed")
+	}
+	if exists, err := conn.Exists("/test0"); err != nil {
+		t.Fatalf("Could not check /test0: %s", err)
+	} else if exists {
+		t.Fatalf("/test0 should not have been created")
+	}
+
+	// creating two nodes commits
+	if err := conn.NewTransaction().Create("/test0", node0).Create("/test1", node1).Commit(); err != nil {
+		t.Fatalf("Could not create /test0 and /test1: %s", err)
+	}
+	out := &TestNode{}
+	if err := conn.Get("/test1", out); err != nil {
+		t.Fatalf("Could not get /test1: %s", err)
+	} else if out.Name != "test1" {
+		t.Errorf("Expected test1, got %s", out.Name)
+	}
+
+	// setting both nodes commits
+	node0.Name, node1.Name = "test0b", "test1b"
+	if err := conn.NewTransaction().Set("/test0", node0).Set("/test1", node1).Commit(); err != nil {
+		t.Fatalf("Could not set /test0 and /test1: %s", err)
+	}
+	if err := conn.Get("/test0", out); err != nil {
+		t.Fatalf("Could not get /test0: %s", err)
+	} else if out.Name != "test0b" {
+		t.Errorf("Expected test0b, got %s", out.Name)
+	}
+
+	// deleting the same node twice does not commit
+	if err := conn.NewTransaction().Delete("/test0").Delete("/test0").Commit(); err == nil {
+		t.Fatalf("Expected an error deleting /test0 twice")
+	}
+	if exists, err := conn.Exists("/test0"); err != nil {
+		t.Fatalf("Could not check /test0: %s", err)
+	} else if !exists {
+		t.Fatalf("/test0 should not have been deleted")
+	}
+
+	// deleting both nodes commits
+	if err := conn.NewTransaction().Delete("/test0").Delete("/test1").Commit(); err != nil {
+		t.Fatalf("Could not delete /test0 and /test1: %s", err)
+	}
+	for _, p := range []string{"/test0", "/test1"} {
+		if exists, err := conn.Exists(p); err != nil {
+			t.Fatalf("Could not check %s: %s", p, err)
+		} else if exists {
+			t.Errorf("%s should have been deleted", p)
+		}
+	}
+
+	// creating the same node twice does not commit
+	if err := conn.NewTransaction().Create("/test0", node0).Create("/test0", node1).Commit(); err == nil {
+		t.Fatalf("Expected an error creating /test0 twice")
+	}
+	if exists, err := conn.Exists("/test0"); err != nil {
+		t.Fatalf("Could not check /test0: %s", err)
+	} else if exists {
+		t.Errorf("/test0 should not have been created")
+	}
+}
+
+func testEphemeral(t *testing.T, connect Connect, basePath string) {
+	conn := mustConnect(t, connect, basePath)
+	defer conn.Close()
+	owner := mustConnect(t, connect, basePath)
+	defer owner.Close()
+
+	epath, err := owner.CreateEphemeral("/ephemeral", &TestNode{Name: "ephemeral"})
+	if err != nil {
+		t.Fatalf("Could not create /ephemeral: %s", err)
+	}
+	// the returned path is from the root, so trim it to the relative location
+	ename := "/" + path.Base(epath)
+	if exists, err := conn.Exists(ename); err != nil {
+		t.Fatalf("Could not check %s: %s", ename, err)
+	} else if !exists {
+		t.Fatalf("Ephemeral %s was not created", ename)
+	}
+	if err := owner.CreateDir(path.Join(ename, "child")); err != client.ErrNoChildrenForEphemerals {
+		t.Errorf("Expected %s creating a child of %s, got %v", client.ErrNoChildrenForEphemerals, ename, err)
+	}
+
+	// closing the owner deletes the node
+	done := make(chan struct{})
+	defer close(done)
+	_, ev, err := conn.ExistsW(ename, done)
+	if err != nil {
+		t.Fatalf("Could not watch %s: %s", ename, err)
+	}
+	owner.Close()
+	select {
+	case e := <-ev:
+		if e.Type != client.EventNodeDeleted {
+			t.Errorf("Expected %v, got %v", client.EventNodeDeleted, e.Type)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for %s to be deleted", ename)
+	}
+	if exists, err := conn.Exists(ename); err != nil {
+		t.Fatalf("Could not check %s: %s", ename, err)
+	} else if exists {
+		t.Errorf("Ephemeral %s should have been deleted", ename)
+	}
+
+	// ephemeral nodes can also be deleted by the client
+	epath, err = conn.CreateEphemeral("/ephemeral", &TestNode{Name: "ephemeral"})
+	if err != nil {
+		t.Fatalf("Could not create /ephemeral: %s", err)
+	}
+	ename = "/" + path.Base(epath)
+	if err := conn.Delete(ename); err != nil {
+		t.Fatalf("Could not delete %s: %s", ename, err)
+	}
+	if exists, err := conn.Exists(ename); err != nil {
+		t.Fatalf("Could not check %s: %s", ename, err)
+	} else if exists {
+		t.Errorf("Ephemeral %s should have been deleted", ename)
+	}
+}
+
+func testSequential(t *testing.T, connect Connect, basePath string) {
+	conn := mustConnect(t, connect, basePath)
+	defer conn.Close()
+
+	var names []string
+	for i := 0; i < 3; i++ {
+		epath, err := conn.CreateEphemeral("/seq/node-", &TestNode{})
+		if err != nil {
+			t.Fatalf("Could not create /seq/node-: %s", err)
+		}
+		names = append(names, path.Base(epath))
+	}
+	last := ""
+	for _, name := range names {
+		seq := name[len(name)-10:]
+		if seq <= last {
+			t.Errorf("Expected increasing sequence numbers, got %v", names)
+		}
+		last = seq
+	}
+	children, err := conn.Children("/seq")
+	if err != nil {
+		t.Fatalf("Could not get the children of /seq: %s", err)
+	} else if len(children) != 3 {
+		t.Errorf("Expected 3 children of /seq, got %v", children)
+	}
+}
+
+func expectEvent(t *testing.T, ev <-chan client.Event, expected client.EventType) {
+	select {
+	case e := <-ev:
+		if e.Type != expected {
+			t.Errorf("Expected %v, got %v", expected, e.Type)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("Timed out waiting for %v", expected)
+	}
+}
+
+func testWatch(t *testing.T, connect Connect, basePath string) {
+	conn := mustConnect(t, connect, basePath)
+	defer conn.Close()
+	done := make(chan struct{})
+	defer close(done)
+
+	if err := conn.CreateDir("/foo"); err != nil {
+		t.Fatalf("Could not create /foo: %s", err)
+	}
+
+	// children watches fire on every watcher
+	_, w1, err := conn.ChildrenW("/foo", done)
+	if err != nil {
+		t.Fatalf("Could not watch /foo: %s", err)
+	}
+	_, w2, err := conn.ChildrenW("/foo", done)
+	if err != nil {
+		t.Fatalf("Could not watch /foo: %s", err)
+	}
+	exists, w3, err := conn.ExistsW("/foo/bar", done)
+	if err != nil {
+		t.Fatalf("Could not watch /foo/bar: %s", err)
+	} else if exists {
+		t.Fatalf("/foo/bar should not exist")
+	}
+	node := &TestNode{Name: "test"}
+	if err := conn.Create("/foo/bar", node); err != nil {
+		t.Fatalf("Could not create /foo/bar: %s", err)
+	}
+	expectEvent(t, w1, client.EventNodeChildrenChanged)
+	expectEvent(t, w2, client.EventNodeChildrenChanged)
+	expectEvent(t, w3, client.EventNodeCreated)
+
+	// data watches fire on set and delete
+	ev, err := conn.GetW("/foo/bar", node, done)
+	if err != nil {
+		t.Fatalf("Could not watch /foo/bar: %s", err)
+	}
+	node.Name = "changed"
+	if err := conn.Set("/foo/bar", node); err != nil {
+		t.Fatalf("Could not update /foo/bar: %s", err)
+	}
+	expectEvent(t, ev, client.EventNodeDataChanged)
+
+	if ev, err = conn.GetW("/foo/bar", node, done); err != nil {
+		t.Fatalf("Could not watch /foo/bar: %s", err)
+	}
+	_, w1, err = conn.ChildrenW("/foo", done)
+	if err != nil {
+		t.Fatalf("Could not watch /foo: %s", err)
+	}
+	if err := conn.Delete("/foo/bar"); err != nil {
+		t.Fatalf("Could not delete /foo/bar: %s", err)
+	}
+	expectEvent(t, ev, client.EventNodeDeleted)
+	expectEvent(t, w1, client.EventNodeChildrenChanged)
+
+	if _, err := conn.GetW("/foo/bar", node, done); err != client.ErrNoNode {
+		t.Errorf("Expected %s watching a missing node, got %v", client.ErrNoNode, err)
+	}
+}
+
+func testCancelWatch(t *testing.T, connect Connect, basePath string) {
+	conn := mustConnect(t, connect, basePath)
+	defer conn.Close()
+
+	if err := conn.Create("/node", &TestNode{Name: "a"}); err != nil {
+		t.Fatalf("Could not create /node: %s", err)
+	}
+	done := make(chan struct{})
+	ev, err := conn.GetW("/node", &TestNode{}, done)
+	if err != nil {
+		t.Fatalf("Could not watch /node: %s", err)
+	}
+	close(done)
+	time.Sleep(100 * time.Millisecond)
+	node := &TestNode{}
+	if err := conn.Get("/node", node); err != nil {
+		t.Fatalf("Could not get /node: %s", err)
+	}
+	node.Name = "b"
+	if err := conn.Set("/node", node); err != nil {
+		t.Fatalf("Could not update /node: %s", err)
+	}
+	select {
+	case e := <-ev:
+		t.Errorf("Received an event on a cancelled watch: %v", e)
+	case <-time.After(blockTimeout):
+	}
+}
+
+func testLock(t *testing.T, connect Connect, basePath string) {
+	conn := mustConnect(t, connect, basePath)
+	defer conn.Close()
+
+	lock, err := conn.NewLock("/foo/bar")
+	if err != nil {
+		t.Fatalf("Could not initialize lock: %s", err)
+	}
+	if err := lock.Lock(); err != nil {
+		t.Fatalf("Could not acquire lock: %s", err)
+	}
+
+	// a second lock blocks until the first is released
+	lock2, err := conn.NewLock("/foo/bar")
+	if err != nil {
+		t.Fatalf("Could not initialize lock: %s", err)
+	}
+	lock2Response := make(chan error)
+	go func() {
+		lock2Response <- lock2.Lock()
+	}()
+	select {
+	case err := <-lock2Response:
+		t.Fatalf("Expected second lock to block, got %v", err)
+	case <-time.After(blockTimeout):
+	}
+	if err := lock.Unlock(); err != nil {
+		t.Fatalf("Could not release lock: %s", err)
+	}
+	select {
+	case err := <-lock2Response:
+		if err != nil {
+			t.Fatalf("Could not acquire second lock: %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for second lock")
+	}
+	if err := lock2.Unlock(); err != nil {
+		t.Fatalf("Could not release lock: %s", err)
+	}
+	if err := lock2.Unlock(); err == nil {
+		t.Errorf("Expected an error releasing a lock that is not held")
+	}
+
+	// a lock held by a closed connection is released
+	owner := mustConnect(t, connect, basePath)
+	lock3, err := owner.NewLock("/foo/bar")
+	if err != nil {
+		t.Fatalf("Could not initialize lock: %s", err)
+	}
+	if err := lock3.Lock(); err != nil {
+		t.Fatalf("Could not acquire lock: %s", err)
+	}
+	go func() {
+		lock2Response <- lock.Lock()
+	}()
+	select {
+	case err := <-lock2Response:
+		t.Fatalf("Expected lock to block, got %v", err)
+	case <-time.After(blockTimeout):
+	}
+	owner.Close()
+	select {
+	case err := <-lock2Response:
+		if err != nil {
+			t.Fatalf("Could not acquire lock: %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for lock")
+	}
+	lock.Unlock()
+}
+
+func testLeader(t *testing.T, connect Connect, basePath string) {
+	conn := mustConnect(t, connect, basePath)
+	defer conn.Close()
+
+	leader1Node := &TestNode{Name: "leader1"}
+	leader1, err := conn.NewLeader("/like/a/boss")
+	if err != nil {
+		t.Fatalf("Could not initialize leader: %s", err)
+	}
+	leaderDone1 := make(chan struct{})
+	defer close(leaderDone1)
+	if _, err := leader1.TakeLead(leader1Node, leaderDone1); err != nil {
+		t.Fatalf("Could not take lead: %s", err)
+	}
+
+	// a second leader blocks until the first releases the lead
+	leader2Node := &TestNode{Name: "leader2"}
+	leader2, err := conn.NewLeader("/like/a/boss")
+	if err != nil {
+		t.Fatalf("Could not initialize leader: %s", err)
+	}
+	leader2Response := make(chan error)
+	leaderDone2 := make(chan struct{})
+	defer close(leaderDone2)
+	go func() {
+		_, err := leader2.TakeLead(leader2Node, leaderDone2)
+		leader2Response <- err
+	}()
+	select {
+	case err := <-leader2Response:
+		t.Fatalf("Expected leader2 to block, got %v", err)
+	case <-time.After(blockTimeout):
+	}
+
+	current, err := conn.NewLeader("/like/a/boss")
+	if err != nil {
+		t.Fatalf("Could not initialize leader: %s", err)
+	}
+	currentNode := &TestNode{}
+	if err := current.Current(currentNode); err != nil {
+		t.Fatalf("Could not get the current leader: %s", err)
+	} else if currentNode.Name != leader1Node.Name {
+		t.Fatalf("Expected leader %s, got %s", leader1Node.Name, currentNode.Name)
+	}
+
+	if err := leader1.ReleaseLead(); err != nil {
+		t.Fatalf("Could not release leader1: %s", err)
+	}
+	select {
+	case err := <-leader2Response:
+		if err != nil {
+			t.Fatalf("Could not take lead with leader2: %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for leader2 to take the lead")
+	}
+	if err := current.Current(currentNode); err != nil {
+		t.Fatalf("Could not get the current leader: %s", err)
+	} else if currentNode.Name != leader2Node.Name {
+		t.Errorf("Expected leader %s, got %s", leader2Node.Name, currentNode.Name)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package memory implements an in-process coordination service that behaves
+// like zookeeper, so code written against coordinator/client can be run
+// without a zookeeper server.
+package memory
+
+import (
+	"sync"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/logging"
+)
+
+var (
+	plog = logging.PackageLogger() // the standard package logger
+)
+
+var (
+	serversLock sync.Mutex
+	servers     = make(map[string]*server)
+)
+
+// Driver implements an in-memory client.Driver interface. The dsn names the
+// tree to connect to; connections made with the same dsn share nodes, watches
+// and sessions for the life of the process.
+type Driver struct{}
+
+// Assert that the memory driver meets the Driver interface
+var _ client.Driver = &Driver{}
+
+func init() {
+	client.RegisterDriver("memory", &Driver{})
+}
+
+// GetConnection returns a connection with a new session on the tree named by
+// the dsn. The caller is responsible for closing the returned connection.
+func (driver *Driver) GetConnection(dsn, basePath string) (client.Connection, error) {
+	serversLock.Lock()
+	srv, ok := servers[dsn]
+	if !ok {
+		srv = newServer()
+		servers[dsn] = srv
+	}
+	serversLock.Unlock()
+
+	conn := srv.connect()
+	plog.WithField("dsn", dsn).WithField("session", conn.id).Debug("Opened in-memory coordinator session")
+	return &Connection{
+		basePath: basePath,
+		conn:     conn,
+	}, nil
+}
+
+// Reset discards the tree named by the dsn. Sessions that are still open keep
+// the old tree; new connections start from an empty one.
+func Reset(dsn string) {
+	serversLock.Lock()
+	defer serversLock.Unlock()
+	delete(servers, dsn)
+}
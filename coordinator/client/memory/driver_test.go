@@ -0,0 +1,130 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package memory
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/coordinator/client/clienttest"
+)
+
+func TestMemoryDriver_Conformance(t *testing.T) {
+	dsn := fmt.Sprintf("conformance-%d", time.Now().UnixNano())
+	defer Reset(dsn)
+	drv := Driver{}
+	clienttest.RunConformance(t, func(basePath string) (client.Connection, error) {
+		return drv.GetConnection(dsn, basePath)
+	})
+}
+
+func TestMemoryDriver_SeparateTrees(t *testing.T) {
+	drv := Driver{}
+	conn1, err := drv.GetConnection("tree1", "/")
+	if err != nil {
+		t.Fatalf("Could not connect: %s", err)
+	}
+	defer Reset("tree1")
+	defer conn1.Close()
+	conn2, err := drv.GetConnection("tree2", "/")
+	if err != nil {
+		t.Fatalf("Could not connect: %s", err)
+	}
+	defer Reset("tree2")
+	defer conn2.Close()
+
+	if err := conn1.CreateDir("/foo"); err != nil {
+		t.Fatalf("Could not create /foo: %s", err)
+	}
+	if exists, err := conn2.Exists("/foo"); err != nil {
+		t.Fatalf("Could not check /foo: %s", err)
+	} else if exists {
+		t.Errorf("/foo should not exist in another tree")
+	}
+
+	Reset("tree1")
+	conn3, err := drv.GetConnection("tree1", "/")
+	if err != nil {
+		t.Fatalf("Could not connect: %s", err)
+	}
+	defer conn3.Close()
+	if exists, err := conn3.Exists("/foo"); err != nil {
+		t.Fatalf("Could not check /foo: %s", err)
+	} else if exists {
+		t.Errorf("/foo should not exist after a reset")
+	}
+}
+
+func TestMemoryDriver_Closed(t *testing.T) {
+	defer Reset("closed")
+	drv := Driver{}
+	conn, err := drv.GetConnection("closed", "/")
+	if err != nil {
+		t.Fatalf("Could not connect: %s", err)
+	}
+	lock, err := conn.NewLock("/lock")
+	if err != nil {
+		t.Fatalf("Could not initialize lock: %s", err)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	if err := conn.CreateDir("/foo"); err != nil {
+		t.Fatalf("Could not create /foo: %s", err)
+	}
+	_, ev, err := conn.ChildrenW("/foo", done)
+	if err != nil {
+		t.Fatalf("Could not watch /foo: %s", err)
+	}
+
+	conn.Close()
+	select {
+	case e := <-ev:
+		if e.Type != client.EventNotWatching {
+			t.Errorf("Expected %v, got %v", client.EventNotWatching, e.Type)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Timed out waiting for the watch to end")
+	}
+	if _, err := conn.Exists("/foo"); err != client.ErrConnectionClosed {
+		t.Errorf("Expected %s, got %v", client.ErrConnectionClosed, err)
+	}
+	if err := lock.Lock(); err != client.ErrConnectionClosed {
+		t.Errorf("Expected %s, got %v", client.ErrConnectionClosed, err)
+	}
+}
+
+func TestMemoryDriver_Client(t *testing.T) {
+	defer Reset("client")
+	c, err := client.New("memory", "client", "/", nil)
+	if err != nil {
+		t.Fatalf("Could not create client: %s", err)
+	}
+	if err := client.EnsurePath(c, "/a/b", true); err != nil {
+		t.Fatalf("Could not ensure path: %s", err)
+	}
+	conn, err := c.GetConnection()
+	if err != nil {
+		t.Fatalf("Could not connect: %s", err)
+	}
+	defer conn.Close()
+	if exists, err := conn.Exists("/a/b"); err != nil {
+		t.Fatalf("Could not check /a/b: %s", err)
+	} else if !exists {
+		t.Errorf("/a/b should exist")
+	}
+}
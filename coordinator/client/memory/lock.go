@@ -0,0 +1,132 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+// Lock is a distributed lock that follows the zookeeper lock recipe.
+type Lock struct {
+	c        *session
+	path     string
+	lockPath string
+	seq      uint64
+}
+
+// newLock creates a new lock instance at the given path bound to a session.
+func newLock(c *session, path string) *Lock {
+	return &Lock{
+		c:    c,
+		path: path,
+	}
+}
+
+// Lock attempts to acquire the lock. It blocks until the lock is acquired or
+// the session is closed.
+func (l *Lock) Lock() error {
+	if l.lockPath != "" {
+		return ErrDeadlock
+	}
+
+	prefix := fmt.Sprintf("%s/lock-", l.path)
+
+	var lockPath string
+	var err error
+	for i := 0; i < 3; i++ {
+		lockPath, err = l.c.CreateProtectedEphemeralSequential(prefix, []byte{})
+		if err == client.ErrNoNode {
+			// create the parent node
+			parts := strings.Split(l.path, "/")
+			pth := ""
+			for _, p := range parts[1:] {
+				pth += "/" + p
+				if _, err = l.c.Create(pth, []byte{}, 0); err != nil && err != client.ErrNodeExists {
+					return err
+				}
+			}
+		} else {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	seq, err := parseSeq(lockPath)
+	if err != nil {
+		return err
+	}
+
+	for {
+		children, _, err := l.c.Children(l.path)
+		if err != nil {
+			return err
+		}
+
+		lowestSeq := seq
+		var prevSeq uint64
+		prevSeqPath := ""
+		for _, p := range children {
+			s, err := parseSeq(p)
+			if err != nil {
+				return err
+			}
+			if s < lowestSeq {
+				lowestSeq = s
+			}
+			if s < seq && (prevSeqPath == "" || s > prevSeq) {
+				prevSeq = s
+				prevSeqPath = p
+			}
+		}
+
+		if seq == lowestSeq {
+			// acquired the lock
+			break
+		}
+
+		// wait on the node next in line for the lock
+		_, _, ch, err := l.c.GetW(l.path + "/" + prevSeqPath)
+		if err == client.ErrNoNode {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if ev := <-ch; ev.Err != nil {
+			return ev.Err
+		}
+	}
+
+	l.seq = seq
+	l.lockPath = lockPath
+	return nil
+}
+
+// Unlock releases an acquired lock. It returns ErrNotLocked if the lock is
+// not held by this instance.
+func (l *Lock) Unlock() error {
+	if l.lockPath == "" {
+		return ErrNotLocked
+	}
+	if err := l.c.Delete(l.lockPath, -1); err != nil {
+		return err
+	}
+	l.lockPath = ""
+	l.seq = 0
+	return nil
+}
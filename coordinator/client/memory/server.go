@@ -0,0 +1,578 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+const (
+	flagEphemeral   = 1
+	flagSequence    = 2
+	protectedPrefix = "_c_"
+)
+
+// Stat describes the state of a node. It is the version object the memory
+// driver sets on client nodes and mirrors the zookeeper stat.
+type Stat struct {
+	Czxid          int64 // the transaction that created the node
+	Mzxid          int64 // the transaction that last modified the node
+	Version        int32 // the number of changes to the data of the node
+	Cversion       int32 // the number of changes to the children of the node
+	EphemeralOwner int64 // the session that owns the node if ephemeral
+	NumChildren    int32 // the number of children of the node
+}
+
+// znode is a node in the tree
+type znode struct {
+	data     []byte
+	stat     Stat
+	children map[string]struct{}
+}
+
+// copy returns a deep copy of the node, minus its data which is never
+// modified in place.
+func (n *znode) copy() *znode {
+	cp := &znode{data: n.data, stat: n.stat, children: make(map[string]struct{})}
+	for name := range n.children {
+		cp.children[name] = struct{}{}
+	}
+	return cp
+}
+
+type watchType int
+
+const (
+	watchData watchType = iota
+	watchChildren
+)
+
+// watcher is a one-shot watch set by a session on a path
+type watcher struct {
+	session int64
+	kind    watchType
+	ch      chan client.Event
+}
+
+// trigger is a change that fires the watches of a kind on a path
+type trigger struct {
+	path  string
+	event client.EventType
+	kinds []watchType
+}
+
+// createRequest, setRequest and deleteRequest are the operations of a multi
+// request.
+type createRequest struct {
+	Path  string
+	Data  []byte
+	Flags int32
+}
+
+type setRequest struct {
+	Path    string
+	Data    []byte
+	Version int32
+}
+
+type deleteRequest struct {
+	Path    string
+	Version int32
+}
+
+// server is an in-process coordination service. Every connection made with
+// the same dsn shares a server.
+type server struct {
+	sync.Mutex
+	nodes    map[string]*znode
+	watchers map[string][]*watcher
+	sessions map[int64]struct{}
+	zxid     int64
+	lastID   int64
+}
+
+func newServer() *server {
+	return &server{
+		nodes: map[string]*znode{
+			"/": {children: make(map[string]struct{})},
+		},
+		watchers: make(map[string][]*watcher),
+		sessions: make(map[int64]struct{}),
+	}
+}
+
+// connect opens a new session on the server
+func (s *server) connect() *session {
+	s.Lock()
+	defer s.Unlock()
+	s.lastID++
+	s.sessions[s.lastID] = struct{}{}
+	return &session{srv: s, id: s.lastID}
+}
+
+// validatePath returns an error if p is not an absolute, normalized path.
+// Sequential nodes may end with a slash, since a sequence number is appended.
+func validatePath(p string, isSequential bool) error {
+	if p == "" || p[0] != '/' {
+		return client.ErrInvalidPath
+	}
+	if p == "/" {
+		return nil
+	}
+	parts := strings.Split(p[1:], "/")
+	for i, part := range parts {
+		if part == "" && isSequential && i == len(parts)-1 {
+			continue
+		}
+		if part == "" || part == "." || part == ".." || strings.ContainsRune(part, 0) {
+			return client.ErrInvalidPath
+		}
+	}
+	return nil
+}
+
+// split returns the parent path and the name of the node at p
+func split(p string) (string, string) {
+	i := strings.LastIndex(p, "/")
+	if i == 0 {
+		return "/", p[1:]
+	}
+	return p[:i], p[i+1:]
+}
+
+func join(dir, name string) string {
+	if dir == "/" {
+		return "/" + name
+	}
+	return dir + "/" + name
+}
+
+func (s *server) checkSession(id int64) error {
+	if _, ok := s.sessions[id]; !ok {
+		return client.ErrConnectionClosed
+	}
+	return nil
+}
+
+func (s *server) create(p string, data []byte, flags int32, owner int64, triggers *[]trigger) (string, error) {
+	if err := validatePath(p, flags&flagSequence != 0); err != nil {
+		return "", err
+	}
+	if p == "/" {
+		return "", client.ErrNodeExists
+	}
+	dir, name := split(p)
+	parent, ok := s.nodes[dir]
+	if !ok {
+		return "", client.ErrNoNode
+	} else if parent.stat.EphemeralOwner != 0 {
+		return "", client.ErrNoChildrenForEphemerals
+	}
+	if flags&flagSequence != 0 {
+		name = fmt.Sprintf("%s%010d", name, parent.stat.Cversion)
+		p = join(dir, name)
+	}
+	if _, ok := s.nodes[p]; ok {
+		return "", client.ErrNodeExists
+	}
+	s.zxid++
+	node := &znode{
+		data:     data,
+		stat:     Stat{Czxid: s.zxid, Mzxid: s.zxid},
+		children: make(map[string]struct{}),
+	}
+	if flags&flagEphemeral != 0 {
+		node.stat.EphemeralOwner = owner
+	}
+	s.nodes[p] = node
+	parent.children[name] = struct{}{}
+	parent.stat.Cversion++
+	parent.stat.NumChildren++
+	*triggers = append(*triggers,
+		trigger{p, client.EventNodeCreated, []watchType{watchData}},
+		trigger{dir, client.EventNodeChildrenChanged, []watchType{watchChildren}},
+	)
+	return p, nil
+}
+
+func (s *server) set(p string, data []byte, version int32, triggers *[]trigger) (*Stat, error) {
+	if err := validatePath(p, false); err != nil {
+		return nil, err
+	}
+	node, ok := s.nodes[p]
+	if !ok {
+		return nil, client.ErrNoNode
+	} else if version != -1 && version != node.stat.Version {
+		return nil, client.ErrBadVersion
+	}
+	s.zxid++
+	node.data = data
+	node.stat.Version++
+	node.stat.Mzxid = s.zxid
+	*triggers = append(*triggers, trigger{p, client.EventNodeDataChanged, []watchType{watchData}})
+	stat := node.stat
+	return &stat, nil
+}
+
+func (s *server) delete(p string, version int32, triggers *[]trigger) error {
+	if err := validatePath(p, false); err != nil {
+		return err
+	}
+	if p == "/" {
+		return client.ErrInvalidPath
+	}
+	node, ok := s.nodes[p]
+	if !ok {
+		return client.ErrNoNode
+	} else if version != -1 && version != node.stat.Version {
+		return client.ErrBadVersion
+	} else if len(node.children) > 0 {
+		return client.ErrNotEmpty
+	}
+	s.zxid++
+	dir, name := split(p)
+	parent := s.nodes[dir]
+	delete(parent.children, name)
+	parent.stat.Cversion++
+	parent.stat.NumChildren--
+	delete(s.nodes, p)
+	*triggers = append(*triggers,
+		trigger{p, client.EventNodeDeleted, []watchType{watchData, watchChildren}},
+		trigger{dir, client.EventNodeChildrenChanged, []watchType{watchChildren}},
+	)
+	return nil
+}
+
+// fire sends the event of each trigger to the matching watchers and removes
+// them, since watches only fire once.
+func (s *server) fire(triggers []trigger) {
+	for _, t := range triggers {
+		var keep []*watcher
+		for _, w := range s.watchers[t.path] {
+			matched := false
+			for _, kind := range t.kinds {
+				if w.kind == kind {
+					matched = true
+					break
+				}
+			}
+			if matched {
+				w.ch <- client.Event{Type: t.event, Path: t.path}
+			} else {
+				keep = append(keep, w)
+			}
+		}
+		if len(keep) > 0 {
+			s.watchers[t.path] = keep
+		} else {
+			delete(s.watchers, t.path)
+		}
+	}
+}
+
+// watch sets a watch of the given kind on a path for a session
+func (s *server) watch(id int64, p string, kind watchType) <-chan client.Event {
+	w := &watcher{session: id, kind: kind, ch: make(chan client.Event, 1)}
+	s.watchers[p] = append(s.watchers[p], w)
+	return w.ch
+}
+
+// snapshot returns a copy of the tree that can be restored if a multi
+// request fails.
+func (s *server) snapshot() map[string]*znode {
+	nodes := make(map[string]*znode, len(s.nodes))
+	for p, node := range s.nodes {
+		nodes[p] = node.copy()
+	}
+	return nodes
+}
+
+// session is a client session on the server. Ephemeral nodes created by a
+// session and the watches it sets live until the session is closed.
+type session struct {
+	srv *server
+	id  int64
+}
+
+// Close ends the session. Its watches are notified that they are no longer
+// watching and its ephemeral nodes are deleted.
+func (c *session) Close() {
+	s := c.srv
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.sessions[c.id]; !ok {
+		return
+	}
+	delete(s.sessions, c.id)
+	for p, watchers := range s.watchers {
+		var keep []*watcher
+		for _, w := range watchers {
+			if w.session == c.id {
+				w.ch <- client.Event{Type: client.EventNotWatching, Path: p, Err: client.ErrClosing}
+			} else {
+				keep = append(keep, w)
+			}
+		}
+		if len(keep) > 0 {
+			s.watchers[p] = keep
+		} else {
+			delete(s.watchers, p)
+		}
+	}
+	var ephemerals []string
+	for p, node := range s.nodes {
+		if node.stat.EphemeralOwner == c.id {
+			ephemerals = append(ephemerals, p)
+		}
+	}
+	sort.Strings(ephemerals)
+	var triggers []trigger
+	for _, p := range ephemerals {
+		s.delete(p, -1, &triggers)
+	}
+	s.fire(triggers)
+}
+
+// Create adds a node at the given path and returns its actual path, which
+// differs from the one requested for sequential nodes.
+func (c *session) Create(p string, data []byte, flags int32) (string, error) {
+	s := c.srv
+	s.Lock()
+	defer s.Unlock()
+	if err := s.checkSession(c.id); err != nil {
+		return "", err
+	}
+	var triggers []trigger
+	p, err := s.create(p, data, flags, c.id, &triggers)
+	if err != nil {
+		return "", err
+	}
+	s.fire(triggers)
+	return p, nil
+}
+
+// CreateProtectedEphemeralSequential creates an ephemeral sequential node
+// whose name is prefixed with a guid, the way the zookeeper client does.
+func (c *session) CreateProtectedEphemeralSequential(p string, data []byte) (string, error) {
+	if err := validatePath(p, true); err != nil {
+		return "", err
+	}
+	var guid [16]byte
+	if _, err := io.ReadFull(rand.Reader, guid[:]); err != nil {
+		return "", err
+	}
+	dir, name := path.Split(p)
+	return c.Create(dir+fmt.Sprintf("%s%x-%s", protectedPrefix, guid, name), data, flagEphemeral|flagSequence)
+}
+
+// Set updates the data of a node if its version matches. A version of -1
+// matches any version.
+func (c *session) Set(p string, data []byte, version int32) (*Stat, error) {
+	s := c.srv
+	s.Lock()
+	defer s.Unlock()
+	if err := s.checkSession(c.id); err != nil {
+		return nil, err
+	}
+	var triggers []trigger
+	stat, err := s.set(p, data, version, &triggers)
+	if err != nil {
+		return nil, err
+	}
+	s.fire(triggers)
+	return stat, nil
+}
+
+// Delete removes a childless node if its version matches. A version of -1
+// matches any version.
+func (c *session) Delete(p string, version int32) error {
+	s := c.srv
+	s.Lock()
+	defer s.Unlock()
+	if err := s.checkSession(c.id); err != nil {
+		return err
+	}
+	var triggers []trigger
+	if err := s.delete(p, version, &triggers); err != nil {
+		return err
+	}
+	s.fire(triggers)
+	return nil
+}
+
+// Multi applies all of the requests or none of them.
+func (c *session) Multi(ops ...interface{}) error {
+	s := c.srv
+	s.Lock()
+	defer s.Unlock()
+	if err := s.checkSession(c.id); err != nil {
+		return err
+	}
+	nodes, zxid := s.snapshot(), s.zxid
+	var triggers []trigger
+	for _, op := range ops {
+		var err error
+		switch req := op.(type) {
+		case *createRequest:
+			_, err = s.create(req.Path, req.Data, req.Flags, c.id, &triggers)
+		case *setRequest:
+			_, err = s.set(req.Path, req.Data, req.Version, &triggers)
+		case *deleteRequest:
+			err = s.delete(req.Path, req.Version, &triggers)
+		default:
+			err = client.ErrAPIError
+		}
+		if err != nil {
+			s.nodes, s.zxid = nodes, zxid
+			return err
+		}
+	}
+	s.fire(triggers)
+	return nil
+}
+
+// Exists returns whether the node at the given path exists.
+func (c *session) Exists(p string) (bool, *Stat, error) {
+	ok, stat, _, err := c.exists(p, false)
+	return ok, stat, err
+}
+
+// ExistsW returns whether the node at the given path exists and sets a watch
+// that fires when it is created, changed or deleted.
+func (c *session) ExistsW(p string) (bool, *Stat, <-chan client.Event, error) {
+	return c.exists(p, true)
+}
+
+func (c *session) exists(p string, watch bool) (bool, *Stat, <-chan client.Event, error) {
+	s := c.srv
+	s.Lock()
+	defer s.Unlock()
+	if err := s.checkSession(c.id); err != nil {
+		return false, nil, nil, err
+	} else if err := validatePath(p, false); err != nil {
+		return false, nil, nil, err
+	}
+	var ch <-chan client.Event
+	if watch {
+		ch = s.watch(c.id, p, watchData)
+	}
+	node, ok := s.nodes[p]
+	if !ok {
+		return false, nil, ch, nil
+	}
+	stat := node.stat
+	return true, &stat, ch, nil
+}
+
+// Get returns the data and stat of the node at the given path.
+func (c *session) Get(p string) ([]byte, *Stat, error) {
+	data, stat, _, err := c.get(p, false)
+	return data, stat, err
+}
+
+// GetW returns the data and stat of the node at the given path and sets a
+// watch that fires when it is changed or deleted.
+func (c *session) GetW(p string) ([]byte, *Stat, <-chan client.Event, error) {
+	return c.get(p, true)
+}
+
+func (c *session) get(p string, watch bool) ([]byte, *Stat, <-chan client.Event, error) {
+	s := c.srv
+	s.Lock()
+	defer s.Unlock()
+	if err := s.checkSession(c.id); err != nil {
+		return nil, nil, nil, err
+	} else if err := validatePath(p, false); err != nil {
+		return nil, nil, nil, err
+	}
+	node, ok := s.nodes[p]
+	if !ok {
+		return nil, nil, nil, client.ErrNoNode
+	}
+	var ch <-chan client.Event
+	if watch {
+		ch = s.watch(c.id, p, watchData)
+	}
+	data := make([]byte, len(node.data))
+	copy(data, node.data)
+	stat := node.stat
+	return data, &stat, ch, nil
+}
+
+// Children returns the sorted names of the children of the node at the given
+// path.
+func (c *session) Children(p string) ([]string, *Stat, error) {
+	children, stat, _, err := c.children(p, false)
+	return children, stat, err
+}
+
+// ChildrenW returns the children of the node at the given path and sets a
+// watch that fires when a child is added or removed or the node is deleted.
+func (c *session) ChildrenW(p string) ([]string, *Stat, <-chan client.Event, error) {
+	return c.children(p, true)
+}
+
+func (c *session) children(p string, watch bool) ([]string, *Stat, <-chan client.Event, error) {
+	s := c.srv
+	s.Lock()
+	defer s.Unlock()
+	if err := s.checkSession(c.id); err != nil {
+		return nil, nil, nil, err
+	} else if err := validatePath(p, false); err != nil {
+		return nil, nil, nil, err
+	}
+	node, ok := s.nodes[p]
+	if !ok {
+		return nil, nil, nil, client.ErrNoNode
+	}
+	var ch <-chan client.Event
+	if watch {
+		ch = s.watch(c.id, p, watchChildren)
+	}
+	children := make([]string, 0, len(node.children))
+	for name := range node.children {
+		children = append(children, name)
+	}
+	sort.Strings(children)
+	stat := node.stat
+	return children, &stat, ch, nil
+}
+
+// CancelEvent removes the watch that sends on ch.
+func (c *session) CancelEvent(ch <-chan client.Event) {
+	s := c.srv
+	s.Lock()
+	defer s.Unlock()
+	for p, watchers := range s.watchers {
+		for i, w := range watchers {
+			if (<-chan client.Event)(w.ch) == ch {
+				watchers = append(watchers[:i], watchers[i+1:]...)
+				if len(watchers) > 0 {
+					s.watchers[p] = watchers
+				} else {
+					delete(s.watchers, p)
+				}
+				return
+			}
+		}
+	}
+}
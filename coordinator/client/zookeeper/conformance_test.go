@@ -0,0 +1,43 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration
+
+package zookeeper
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	coordclient "github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/coordinator/client/clienttest"
+	zzktest "github.com/control-center/serviced/zzk/test"
+)
+
+func TestZkDriver_Conformance(t *testing.T) {
+	zzkServer := &zzktest.ZZKServer{}
+	if err := zzkServer.Start(); err != nil {
+		t.Fatalf("Could not start zookeeper: %s", err)
+	}
+	defer zzkServer.Stop()
+	time.Sleep(time.Second)
+
+	servers := []string{fmt.Sprintf("127.0.0.1:%d", zzkServer.Port)}
+	dsn := DSN{Servers: servers, SessionTimeout: time.Second * 15}.String()
+
+	drv := Driver{}
+	clienttest.RunConformance(t, func(basePath string) (coordclient.Connection, error) {
+		return drv.GetConnection(dsn, basePath)
+	})
+}
@@ -58,6 +58,7 @@ type ControlPlaneDao struct {
 	facade       *facade.Facade
 	metricClient *metrics.Client
 	backupsPath  string
+	backupKey    []byte
 }
 
 func serviceGetter(ctx datastore.Context, f *facade.Facade) service.GetService {
@@ -156,3 +157,8 @@ func NewControlSvc(hostName string, port int, facade *facade.Facade, backupsPath
 
 	return s, nil
 }
+
+// SetBackupKey sets the passphrase that encrypts backups
+func (this *ControlPlaneDao) SetBackupKey(key []byte) {
+	this.backupKey = key
+}
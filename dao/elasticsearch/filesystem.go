@@ -15,9 +15,9 @@ package elasticsearch
 
 import (
 	"fmt"
-	"os"
+	"io"
+	"strings"
 
-	"path/filepath"
 	"sync"
 	"time"
 
@@ -26,6 +26,7 @@ import (
 	model "github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/dfs/target"
 	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/volume"
 	gzip "github.com/klauspost/pgzip"
@@ -89,8 +90,8 @@ func (p *InProgress) GetProgress() (bool, string, string, error) {
 
 var inprogress = &InProgress{locker: &sync.RWMutex{}}
 
-// Backup takes a backup of the full application stack and returns the filename
-// that it is written to.
+// Backup takes a backup of the full application stack, or of the tenant of the
+// request, and returns the filename that it is written to.
 func (dao *ControlPlaneDao) Backup(backupRequest model.BackupRequest, filename *string) (err error) {
 	ctx := datastore.Get()
 	if len(backupRequest.Username) > 0 {
@@ -101,8 +102,28 @@ func (dao *ControlPlaneDao) Backup(backupRequest model.BackupRequest, filename *
 	dfslocker.Lock("backup")
 	defer dfslocker.Unlock()
 
-	if backupRequest.Dirpath == "" {
-		backupRequest.Dirpath = dao.backupsPath
+	t, err := dao.backupTarget(backupRequest.Dirpath)
+	if err != nil {
+		log.WithError(err).WithField("dirpath", backupRequest.Dirpath).Error("Could not find backup target")
+		return err
+	}
+	backupRequest.Dirpath = t.Path("")
+	// load the parent of an incremental backup, which is named on the same
+	// target unless it is given as a path
+	var parentfilename string
+	var parent *dfs.BackupInfo
+	if backupRequest.IncrementalFrom != "" {
+		parentTarget, parentName := t, backupRequest.IncrementalFrom
+		if strings.Contains(parentName, "/") {
+			if parentTarget, parentName, err = dao.facade.SplitBackupPath(parentName); err != nil {
+				return err
+			}
+		}
+		parentfilename = parentTarget.Path(parentName)
+		if parent, err = dao.readBackupInfo(parentTarget, parentName); err != nil {
+			log.WithError(err).WithField("parent", parentfilename).Error("Could not read parent backup")
+			return fmt.Errorf("could not read parent backup %s: %s", parentfilename, err)
+		}
 	}
 	// CC-2421: Check for space before doing backup
 	est := model.BackupEstimate{}
@@ -128,40 +149,105 @@ func (dao *ControlPlaneDao) Backup(backupRequest model.BackupRequest, filename *
 
 	// set the progress of the backup file
 	*filename = time.Now().UTC().Format("backup-2006-01-02-150405.tgz")
-	backupfilename := filepath.Join(backupRequest.Dirpath, *filename)
+	backupfilename := t.Path(*filename)
 
 	inprogress.SetProgress(backupfilename, "backup")
 	defer func() {
 		if err != nil {
 			log.WithError(err).Error("Backup failed with error")
-			os.Remove(backupfilename)
 		}
 		inprogress.SetError(err)
 	}()
-	// create the file and write
-	fh, err := os.Create(backupfilename)
+	// create the file and stream the backup to it
+	fh, err := t.Create(*filename)
 	if err != nil {
 		log.WithError(err).WithField("backupfilename", backupfilename).Error("Could not create backup file")
 		return
 	}
-	defer fh.Close()
-	w := gzip.NewWriter(fh)
+	if err = dao.writeBackup(ctx, fh, backupRequest, backupfilename, parentfilename, parent); err != nil {
+		fh.Abort()
+		return
+	}
+	if err = fh.Close(); err != nil {
+		fh.Abort()
+	}
+	return
+}
+
+// writeBackup writes a compressed backup of the application stack, which is
+// encrypted if there is a backup key
+func (dao *ControlPlaneDao) writeBackup(ctx datastore.Context, w io.Writer, backupRequest model.BackupRequest, backupfilename, parentfilename string, parent *dfs.BackupInfo) error {
+	var crypt io.WriteCloser
+	if len(dao.backupKey) > 0 {
+		var err error
+		if crypt, err = dfs.NewBackupEncrypter(w, dao.backupKey); err != nil {
+			log.WithError(err).Error("Could not encrypt backup")
+			return err
+		}
+		w = crypt
+	}
+	gz := gzip.NewWriter(w)
 	// CC-2292: Limit concurrency of backup gzipping
 	// This setting will cause the writer to process up to 2 100KB blocks
 	// at a time before the writer blocks. The default was 16 250KB blocks.
 	// Smaller blocks will allow other goroutines to get time more frequently.
-	w.SetConcurrency(100000, 2)
-	defer w.Close()
-	err = dao.facade.Backup(ctx, w, backupRequest.Excludes, backupRequest.SnapshotSpacePercent, backupfilename)
-	return
+	gz.SetConcurrency(100000, 2)
+	if err := dao.facade.Backup(ctx, gz, backupRequest.Excludes, backupRequest.TenantID, backupRequest.SnapshotSpacePercent, backupfilename, parentfilename, parent); err != nil {
+		gz.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	if crypt != nil {
+		// the last chunk must be written after the compressed data
+		return crypt.Close()
+	}
+	return nil
+}
+
+// backupTarget returns the target of the backups at a location, which
+// defaults to the backup target of the master
+func (dao *ControlPlaneDao) backupTarget(location string) (target.Target, error) {
+	if dao.facade.BackupTarget() == nil {
+		if location == "" {
+			location = dao.backupsPath
+		}
+		return target.New(location)
+	}
+	if location == "" {
+		return dao.facade.BackupTarget(), nil
+	}
+	t, err := target.New(location)
+	if err != nil {
+		return nil, err
+	}
+	if t.Path("") == dao.facade.BackupTarget().Path("") {
+		return dao.facade.BackupTarget(), nil
+	}
+	return t, nil
+}
+
+// readBackupInfo reads the metadata at the front of a backup file
+func (dao *ControlPlaneDao) readBackupInfo(t target.Target, name string) (*dfs.BackupInfo, error) {
+	r, err := dao.openBackup(t, name)()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return dao.facade.BackupInfo(datastore.Get(), r)
 }
 
 func (dao *ControlPlaneDao) GetBackupEstimate(backupRequest model.BackupRequest, backupEstimate *model.BackupEstimate) (err error) {
 	ctx := datastore.Get()
 	start := time.Now()
 	if backupRequest.Dirpath == "" {
-		log.WithField("backupspath", dao.backupsPath).Info("Dirpath was empty. Using value from dao.BackupsPath")
-		backupRequest.Dirpath = dao.backupsPath
+		t, err := dao.backupTarget("")
+		if err != nil {
+			return err
+		}
+		log.WithField("backupspath", t.Path("")).Info("Dirpath was empty. Using the default backup target")
+		backupRequest.Dirpath = t.Path("")
 	}
 	err = dao.facade.EstimateBackup(ctx, backupRequest, backupEstimate)
 	if err != nil {
@@ -205,24 +291,62 @@ func (dao *ControlPlaneDao) Restore(restoreRequest model.RestoreRequest, _ *int)
 		}
 		inprogress.SetError(err)
 	}()
-	info, err := dfs.ExtractBackupInfo(restoreRequest.Filename)
+	t, name, err := dao.facade.SplitBackupPath(restoreRequest.Filename)
 	if err != nil {
 		return err
 	}
-	fh, err := os.Open(restoreRequest.Filename)
+	info, err := dao.readBackupInfo(t, name)
 	if err != nil {
 		return err
 	}
-	defer fh.Close()
-	gz, err := gzip.NewReader(fh)
+	if info.Parent != "" {
+		chain, err := dao.backupChain(t, name, info)
+		if err != nil {
+			return err
+		}
+		return dao.facade.RestoreChain(ctx, chain, restoreRequest.Filename)
+	}
+	r, err := dao.openBackup(t, name)()
 	if err != nil {
 		return err
 	}
-	defer gz.Close()
-	err = dao.facade.Restore(ctx, gz, info, restoreRequest.Filename)
+	defer r.Close()
+	err = dao.facade.Restore(ctx, r, info, restoreRequest.Filename)
 	return err
 }
 
+// backupChain returns the chain of backups that an incremental backup was
+// taken from, starting with the full backup.  The parent backups must be on
+// the same target as the incremental backup.
+func (dao *ControlPlaneDao) backupChain(t target.Target, name string, info *dfs.BackupInfo) ([]dfs.ChainedBackup, error) {
+	chain := []dfs.ChainedBackup{{Info: info, Open: dao.openBackup(t, name)}}
+	seen := map[string]bool{name: true}
+	for info.Parent != "" {
+		if seen[info.Parent] {
+			return nil, fmt.Errorf("backup %s is its own parent", info.Parent)
+		}
+		seen[info.Parent] = true
+		parent, err := dao.readBackupInfo(t, info.Parent)
+		if err != nil {
+			return nil, fmt.Errorf("could not read parent backup %s: %s", t.Path(info.Parent), err)
+		}
+		chain = append([]dfs.ChainedBackup{{Info: parent, Open: dao.openBackup(t, info.Parent)}}, chain...)
+		info = parent
+	}
+	return chain, nil
+}
+
+// openBackup returns a function that opens the tar stream of a backup file
+func (dao *ControlPlaneDao) openBackup(t target.Target, name string) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		r, err := t.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		return dfs.OpenBackup(r, dao.backupKey)
+	}
+}
+
 // AsyncRestore is the same as restore, but asynchronous.
 func (dao *ControlPlaneDao) AsyncRestore(restoreRequest model.RestoreRequest, unused *int) (err error) {
 	ctx := datastore.Get()
@@ -242,16 +366,11 @@ func (dao *ControlPlaneDao) ListBackups(dirpath string, files *[]model.BackupFil
 	*files = []model.BackupFile{}
 
 	// Read the contents of the directory
-	if dirpath == "" {
-		dirpath = dao.backupsPath
-	}
-	dir, err := os.Open(dirpath)
+	t, err := dao.backupTarget(dirpath)
 	if err != nil {
 		return err
 	}
-	defer dir.Close()
-
-	fis, err := dir.Readdir(0)
+	fis, err := t.List()
 	if err != nil {
 		return err
 	}
@@ -259,43 +378,24 @@ func (dao *ControlPlaneDao) ListBackups(dirpath string, files *[]model.BackupFil
 	running, fp, _, _ := inprogress.GetProgress()
 
 	for _, fi := range fis {
-		if !fi.IsDir() {
-			// Set up the backup file
-			fullpath := filepath.Join(dirpath, fi.Name())
-			bf := model.BackupFile{
-				InProgress: running && fullpath == fp,
-				FullPath:   fullpath,
-				Name:       fi.Name(),
-				Size:       fi.Size(),
-				Mode:       fi.Mode(),
-				ModTime:    fi.ModTime(),
-			}
-			// If it is not running, make sure the backup is legit
-			if !bf.InProgress {
-				isbackup := func(filename string) bool {
-					fh, err := os.Open(fullpath)
-					if err != nil {
-						return false
-					}
-					defer fh.Close()
-					gz, err := gzip.NewReader(fh)
-					if err != nil {
-						return false
-					}
-					defer gz.Close()
-					_, err = dao.facade.BackupInfo(datastore.Get(), gz)
-					if err != nil {
-						return false
-					}
-					return true
-				}(fullpath)
-				if !isbackup {
-					continue
-				}
+		// Set up the backup file
+		fullpath := t.Path(fi.Name)
+		bf := model.BackupFile{
+			InProgress: running && fullpath == fp,
+			FullPath:   fullpath,
+			Name:       fi.Name,
+			Size:       fi.Size,
+			Mode:       fi.Mode,
+			ModTime:    fi.ModTime,
+		}
+		// If it is not running, make sure the backup is legit
+		if !bf.InProgress {
+			if _, err := dao.readBackupInfo(t, fi.Name); err != nil {
+				continue
 			}
-			// Add the file to the list
-			*files = append(*files, bf)
 		}
+		// Add the file to the list
+		*files = append(*files, bf)
 	}
 	return
 }
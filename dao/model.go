@@ -127,6 +127,8 @@ type BackupRequest struct {
 	Excludes             []string
 	Force                bool
 	Username             string
+	IncrementalFrom      string // backup to take an incremental backup from
+	TenantID             string // tenant to back up; every tenant if empty
 }
 
 type RestoreRequest struct {
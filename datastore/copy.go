@@ -0,0 +1,46 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datastore
+
+import (
+	"fmt"
+)
+
+// Enumerator is implemented by connections that can walk every entity they
+// hold.
+type Enumerator interface {
+	// Enumerate calls fn with the key and data of every entity.
+	Enumerate(fn func(key Key, msg JSONMessage) error) error
+}
+
+// Copy writes every entity from the source connection into the destination
+// connection and returns the number of entities copied.  Entities that
+// already exist in the destination are overwritten.
+func Copy(src, dst Connection) (int, error) {
+	enum, ok := src.(Enumerator)
+	if !ok {
+		return 0, fmt.Errorf("datastore connection %T cannot enumerate its entities", src)
+	}
+	count := 0
+	err := enum.Enumerate(func(key Key, msg JSONMessage) error {
+		// write without a version so that the destination does not
+		// check for conflicts
+		if err := dst.Put(key, NewJSONMessage(msg.Bytes(), 0)); err != nil {
+			return fmt.Errorf("could not copy %s %s: %s", key.Kind(), key.ID(), err)
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
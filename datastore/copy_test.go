@@ -0,0 +1,92 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package datastore
+
+import (
+	"errors"
+	"testing"
+)
+
+// mapConn is a connection that keeps its entities in a map.
+type mapConn struct {
+	testConn
+	entities map[string]JSONMessage
+	order    []Key
+	err      error
+}
+
+func (c *mapConn) Put(key Key, data JSONMessage) error {
+	if c.err != nil {
+		return c.err
+	}
+	if c.entities == nil {
+		c.entities = make(map[string]JSONMessage)
+	}
+	c.entities[key.Kind()+"/"+key.ID()] = data
+	c.order = append(c.order, key)
+	return nil
+}
+
+func (c *mapConn) Enumerate(fn func(Key, JSONMessage) error) error {
+	for _, key := range c.order {
+		if err := fn(key, c.entities[key.Kind()+"/"+key.ID()]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestCopy(t *testing.T) {
+	src := &mapConn{}
+	src.Put(NewKey("host", "h1"), NewJSONMessage([]byte(`{"ID":"h1"}`), 4))
+	src.Put(NewKey("pool", "p1"), NewJSONMessage([]byte(`{"ID":"p1"}`), 2))
+
+	dst := &mapConn{}
+	count, err := Copy(src, dst)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 entities to be copied, got %d", count)
+	}
+	msg, ok := dst.entities["host/h1"]
+	if !ok {
+		t.Fatalf("Expected host h1 to be copied")
+	}
+	if string(msg.Bytes()) != `{"ID":"h1"}` {
+		t.Errorf("Unexpected data %s", msg.Bytes())
+	}
+	if msg.Version() != 0 {
+		t.Errorf("Expected the version to be cleared, got %d", msg.Version())
+	}
+}
+
+func TestCopyErrors(t *testing.T) {
+	if _, err := Copy(testConn{}, &mapConn{}); err == nil {
+		t.Errorf("Expected an error copying from a connection that cannot enumerate")
+	}
+
+	src := &mapConn{}
+	src.Put(NewKey("host", "h1"), NewJSONMessage([]byte(`{}`), 1))
+	dst := &mapConn{err: errors.New("disk full")}
+	count, err := Copy(src, dst)
+	if err == nil || err.Error() != "could not copy host h1: disk full" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected no entities to be copied, got %d", count)
+	}
+}
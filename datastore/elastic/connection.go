@@ -140,6 +140,36 @@ func (ec *elasticConnection) Query(query interface{}) ([]datastore.JSONMessage,
 	}
 }
 
+// Enumerate walks every document in the index with a scan and scroll search.
+func (ec *elasticConnection) Enumerate(fn func(datastore.Key, datastore.JSONMessage) error) error {
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"match_all": map[string]interface{}{},
+		},
+	}
+	resp, err := core.SearchRequest(false, ec.index, "", query, "5m", 500)
+	if err != nil {
+		return fmt.Errorf("error executing query %v", err)
+	}
+	scrollID := resp.ScrollId
+	for {
+		resp, err = core.Scroll(false, scrollID, "5m")
+		if err != nil {
+			return fmt.Errorf("error scrolling query %v", err)
+		}
+		if len(resp.Hits.Hits) == 0 {
+			return nil
+		}
+		for _, hit := range resp.Hits.Hits {
+			msg := datastore.NewJSONMessage(hit.Source, hit.Version)
+			if err := fn(datastore.NewKey(hit.Type, hit.Id), msg); err != nil {
+				return err
+			}
+		}
+		scrollID = resp.ScrollId
+	}
+}
+
 // convert search result of json host to dao.Host array
 func toJSONMessages(result *core.SearchResult) []datastore.JSONMessage {
 	var total = len(result.Hits.Hits)
@@ -0,0 +1,134 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"errors"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/datastore"
+)
+
+// ErrConflict is returned when an entity is written with a version that does
+// not match the stored version.  The message matches the one returned by the
+// elastic driver.
+var ErrConflict = errors.New("Your changes conflict with those made by another user. Please reload and try your changes again.")
+
+type localConnection struct {
+	store *store
+}
+
+func (lc *localConnection) Put(key datastore.Key, msg datastore.JSONMessage) error {
+	logger := plog.WithFields(log.Fields{
+		"kind": key.Kind(),
+		"id":   key.ID(),
+	})
+	logger.Debug("Put")
+
+	lc.store.Lock()
+	defer lc.store.Unlock()
+
+	version := 1
+	current, ok := lc.store.get(key.Kind(), key.ID())
+	if msg.Version() != 0 {
+		if !ok || current.Version != msg.Version() {
+			logger.WithField("version", msg.Version()).Debug("Version conflict")
+			return ErrConflict
+		}
+	}
+	if ok {
+		version = current.Version + 1
+	}
+	return lc.store.write(&record{
+		Kind:    key.Kind(),
+		ID:      key.ID(),
+		Version: version,
+		Data:    append([]byte{}, msg.Bytes()...),
+	})
+}
+
+func (lc *localConnection) Get(key datastore.Key) (datastore.JSONMessage, error) {
+	plog.WithFields(log.Fields{
+		"kind": key.Kind(),
+		"id":   key.ID(),
+	}).Debug("Get")
+
+	lc.store.RLock()
+	defer lc.store.RUnlock()
+
+	rec, ok := lc.store.get(key.Kind(), key.ID())
+	if !ok {
+		return nil, datastore.ErrNoSuchEntity{Key: key}
+	}
+	return datastore.NewJSONMessage(rec.Data, rec.Version), nil
+}
+
+func (lc *localConnection) Delete(key datastore.Key) error {
+	plog.WithFields(log.Fields{
+		"kind": key.Kind(),
+		"id":   key.ID(),
+	}).Debug("Delete")
+
+	lc.store.Lock()
+	defer lc.store.Unlock()
+
+	rec, ok := lc.store.get(key.Kind(), key.ID())
+	if !ok {
+		return datastore.ErrNoSuchEntity{Key: key}
+	}
+	return lc.store.write(&record{Kind: key.Kind(), ID: key.ID(), Version: rec.Version + 1})
+}
+
+func (lc *localConnection) Query(query interface{}) ([]datastore.JSONMessage, error) {
+	req, err := parseRequest(query)
+	if err != nil {
+		plog.WithError(err).Error("Unable to parse query")
+		return nil, err
+	}
+
+	lc.store.RLock()
+	defer lc.store.RUnlock()
+
+	var recs []*record
+	if len(req.types) == 0 {
+		recs = lc.store.all("")
+	} else {
+		for _, kind := range req.types {
+			recs = append(recs, lc.store.all(kind)...)
+		}
+	}
+	msgs, err := req.execute(recs)
+	if err != nil {
+		plog.WithError(err).Error("Unable to execute query")
+		return nil, err
+	}
+	plog.WithField("total", len(msgs)).Debug("Query finished")
+	return msgs, nil
+}
+
+// Enumerate calls fn with every entity in the datastore, ordered by kind and
+// id.
+func (lc *localConnection) Enumerate(fn func(datastore.Key, datastore.JSONMessage) error) error {
+	lc.store.RLock()
+	recs := lc.store.all("")
+	lc.store.RUnlock()
+
+	for _, rec := range recs {
+		key := datastore.NewKey(rec.Kind, rec.ID)
+		if err := fn(key, datastore.NewJSONMessage(rec.Data, rec.Version)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
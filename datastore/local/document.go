@@ -0,0 +1,486 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// document is a stored entity decoded so that search clauses can be
+// evaluated against it.
+type document struct {
+	kind   string
+	id     string
+	source map[string]interface{}
+}
+
+func newDocument(rec *record) (*document, error) {
+	doc := &document{kind: rec.Kind, id: rec.ID}
+	if err := json.Unmarshal(rec.Data, &doc.source); err != nil {
+		return nil, fmt.Errorf("could not decode %s %s: %s", rec.Kind, rec.ID, err)
+	}
+	return doc, nil
+}
+
+// values returns every value found at the dotted field path.  Arrays along
+// the path are flattened, the way Elasticsearch indexes them.
+func (doc *document) values(field string) []interface{} {
+	switch field {
+	case "_id":
+		return []interface{}{doc.id}
+	case "_type":
+		return []interface{}{doc.kind}
+	}
+	values := []interface{}{doc.source}
+	for _, name := range strings.Split(field, ".") {
+		var next []interface{}
+		for _, v := range values {
+			if m, ok := v.(map[string]interface{}); ok {
+				if child, ok := m[name]; ok {
+					next = appendValue(next, child)
+				}
+			}
+		}
+		values = next
+	}
+	return values
+}
+
+func appendValue(values []interface{}, v interface{}) []interface{} {
+	switch v := v.(type) {
+	case nil:
+		return values
+	case []interface{}:
+		for _, item := range v {
+			values = appendValue(values, item)
+		}
+		return values
+	default:
+		return append(values, v)
+	}
+}
+
+// leaves returns every scalar value in the document.
+func (doc *document) leaves() []interface{} {
+	var leaves []interface{}
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		case nil:
+		default:
+			leaves = append(leaves, v)
+		}
+	}
+	walk(doc.source)
+	return leaves
+}
+
+// project returns the document limited to the requested fields.
+func (doc *document) project(fields []string) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, field := range fields {
+		var v interface{} = doc.source
+		found := true
+		for _, name := range strings.Split(field, ".") {
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				found = false
+				break
+			}
+			if v, ok = m[name]; !ok {
+				found = false
+				break
+			}
+		}
+		if found {
+			result[field] = v
+		}
+	}
+	return result
+}
+
+// matches evaluates a query or filter clause against the document.  Every
+// key of the clause has to match.
+func (doc *document) matches(clause map[string]interface{}) (bool, error) {
+	// evaluate the keys in a fixed order so that errors are reported
+	// consistently
+	keys := make([]string, 0, len(clause))
+	for k := range clause {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		ok, err := doc.match(k, clause[k])
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (doc *document) match(name string, body interface{}) (bool, error) {
+	switch name {
+	case "match_all":
+		return true, nil
+	case "term":
+		return doc.matchFields(name, body, func(field string, value interface{}) (bool, error) {
+			return doc.hasValue(field, value), nil
+		})
+	case "terms":
+		return doc.matchTerms(body)
+	case "ids":
+		return doc.matchIDs(body)
+	case "prefix":
+		return doc.matchFields(name, body, func(field string, value interface{}) (bool, error) {
+			prefix := toString(value)
+			for _, v := range doc.values(field) {
+				if strings.HasPrefix(toString(v), prefix) {
+					return true, nil
+				}
+			}
+			return false, nil
+		})
+	case "regexp":
+		return doc.matchFields(name, body, doc.matchRegexp)
+	case "range":
+		return doc.matchFields(name, body, doc.matchRange)
+	case "exists", "missing":
+		m, ok := body.(map[string]interface{})
+		if !ok {
+			return false, fmt.Errorf("invalid %s clause", name)
+		}
+		exists := len(doc.values(toString(m["field"]))) > 0
+		return exists == (name == "exists"), nil
+	case "query_string":
+		m, ok := body.(map[string]interface{})
+		if !ok {
+			return false, fmt.Errorf("invalid query_string clause")
+		}
+		return doc.matchQueryString(m)
+	case "bool":
+		return doc.matchBool(body)
+	case "filtered":
+		m, ok := body.(map[string]interface{})
+		if !ok {
+			return false, fmt.Errorf("invalid filtered clause")
+		}
+		for _, k := range []string{"query", "filter"} {
+			if sub, ok := m[k].(map[string]interface{}); ok {
+				if ok, err := doc.matches(sub); err != nil || !ok {
+					return false, err
+				}
+			}
+		}
+		return true, nil
+	case "and", "or":
+		clauses, err := toClauses(body, "filters")
+		if err != nil {
+			return false, err
+		}
+		for _, sub := range clauses {
+			ok, err := doc.matches(sub)
+			if err != nil {
+				return false, err
+			}
+			if ok == (name == "or") {
+				return ok, nil
+			}
+		}
+		return name == "and", nil
+	case "not":
+		clauses, err := toClauses(body, "filter")
+		if err != nil {
+			return false, err
+		}
+		for _, sub := range clauses {
+			if ok, err := doc.matches(sub); err != nil || ok {
+				return false, err
+			}
+		}
+		return true, nil
+	default:
+		return false, fmt.Errorf("unsupported search clause %q", name)
+	}
+}
+
+// matchFields evaluates a clause of the form {field: value, ...}.
+func (doc *document) matchFields(name string, body interface{}, fn func(string, interface{}) (bool, error)) (bool, error) {
+	m, ok := body.(map[string]interface{})
+	if !ok {
+		return false, fmt.Errorf("invalid %s clause", name)
+	}
+	for field, value := range m {
+		if ok, err := fn(field, value); err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (doc *document) matchTerms(body interface{}) (bool, error) {
+	m, ok := body.(map[string]interface{})
+	if !ok {
+		return false, fmt.Errorf("invalid terms clause")
+	}
+	for field, values := range m {
+		switch field {
+		case "execution", "_cache", "minimum_should_match":
+			continue
+		}
+		list, ok := values.([]interface{})
+		if !ok {
+			list = []interface{}{values}
+		}
+		found := false
+		for _, value := range list {
+			if doc.hasValue(field, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (doc *document) matchIDs(body interface{}) (bool, error) {
+	m, ok := body.(map[string]interface{})
+	if !ok {
+		return false, fmt.Errorf("invalid ids clause")
+	}
+	values, _ := m["values"].([]interface{})
+	for _, v := range values {
+		if toString(v) == doc.id {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// matchRegexp matches a field against a regular expression.  Like
+// Elasticsearch, the expression must match the whole value.
+func (doc *document) matchRegexp(field string, value interface{}) (bool, error) {
+	if m, ok := value.(map[string]interface{}); ok {
+		value = m["value"]
+	}
+	re, err := regexp.Compile("^(?:" + toString(value) + ")$")
+	if err != nil {
+		return false, fmt.Errorf("invalid regular expression for %s: %s", field, err)
+	}
+	for _, v := range doc.values(field) {
+		if re.MatchString(toString(v)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (doc *document) matchRange(field string, value interface{}) (bool, error) {
+	bounds, ok := value.(map[string]interface{})
+	if !ok {
+		return false, fmt.Errorf("invalid range for %s", field)
+	}
+	includeLower, includeUpper := true, true
+	if v, ok := bounds["include_lower"].(bool); ok {
+		includeLower = v
+	}
+	if v, ok := bounds["include_upper"].(bool); ok {
+		includeUpper = v
+	}
+
+	inRange := func(v interface{}) bool {
+		for op, bound := range bounds {
+			if bound == nil {
+				continue
+			}
+			cmp := compare(v, bound)
+			switch op {
+			case "gt":
+				if cmp <= 0 {
+					return false
+				}
+			case "gte":
+				if cmp < 0 {
+					return false
+				}
+			case "lt":
+				if cmp >= 0 {
+					return false
+				}
+			case "lte":
+				if cmp > 0 {
+					return false
+				}
+			case "from":
+				if cmp < 0 || (cmp == 0 && !includeLower) {
+					return false
+				}
+			case "to":
+				if cmp > 0 || (cmp == 0 && !includeUpper) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	for _, v := range doc.values(field) {
+		if inRange(v) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// matchBool evaluates a bool query.  Should clauses are only required when
+// there are no must clauses.
+func (doc *document) matchBool(body interface{}) (bool, error) {
+	m, ok := body.(map[string]interface{})
+	if !ok {
+		return false, fmt.Errorf("invalid bool clause")
+	}
+	must, err := toClauses(m["must"], "")
+	if err != nil {
+		return false, err
+	}
+	for _, sub := range must {
+		if ok, err := doc.matches(sub); err != nil || !ok {
+			return false, err
+		}
+	}
+	mustNot, err := toClauses(m["must_not"], "")
+	if err != nil {
+		return false, err
+	}
+	for _, sub := range mustNot {
+		if ok, err := doc.matches(sub); err != nil || ok {
+			return false, err
+		}
+	}
+	should, err := toClauses(m["should"], "")
+	if err != nil {
+		return false, err
+	}
+	if len(should) == 0 || len(must) > 0 {
+		return true, nil
+	}
+	for _, sub := range should {
+		if ok, err := doc.matches(sub); err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// hasValue returns true if any value of the field equals the term.
+func (doc *document) hasValue(field string, term interface{}) bool {
+	want := toString(term)
+	for _, v := range doc.values(field) {
+		if toString(v) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// toClauses reads a single clause or a list of clauses.  Some filters accept
+// their clauses wrapped in an object under the given key.
+func toClauses(body interface{}, key string) ([]map[string]interface{}, error) {
+	if m, ok := body.(map[string]interface{}); ok {
+		if inner, ok := m[key]; ok && key != "" {
+			return toClauses(inner, "")
+		}
+		return []map[string]interface{}{m}, nil
+	}
+	if body == nil {
+		return nil, nil
+	}
+	list, ok := body.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid list of clauses")
+	}
+	clauses := make([]map[string]interface{}, len(list))
+	for i, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid clause %v", item)
+		}
+		clauses[i] = m
+	}
+	return clauses, nil
+}
+
+// toString formats a json value the way it is compared as a term.
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return ""
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// compare orders two json values as numbers, as dates or as strings,
+// whichever applies to both.
+func compare(a, b interface{}) int {
+	as, bs := toString(a), toString(b)
+	if af, err := strconv.ParseFloat(as, 64); err == nil {
+		if bf, err := strconv.ParseFloat(bs, 64); err == nil {
+			switch {
+			case af < bf:
+				return -1
+			case af > bf:
+				return 1
+			default:
+				return 0
+			}
+		}
+	}
+	if at, err := time.Parse(time.RFC3339Nano, as); err == nil {
+		if bt, err := time.Parse(time.RFC3339Nano, bs); err == nil {
+			switch {
+			case at.Before(bt):
+				return -1
+			case at.After(bt):
+				return 1
+			default:
+				return 0
+			}
+		}
+	}
+	return strings.Compare(as, bs)
+}
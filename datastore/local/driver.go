@@ -0,0 +1,58 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/logging"
+)
+
+var plog = logging.PackageLogger() // the standard package logger
+
+// LocalDriver is a datastore driver that keeps every entity in a journal file
+// on the local disk, so that a single-node master can run without
+// Elasticsearch.
+type LocalDriver interface {
+	// Initialize opens the journal file and loads it into memory.
+	Initialize() error
+	// GetConnection returns a connection to the datastore.
+	GetConnection() (datastore.Connection, error)
+	// Close flushes and releases the journal file.
+	Close() error
+}
+
+// New returns a driver for the datastore journal at path.  Only one process
+// may have the journal open at a time.
+func New(path string) LocalDriver {
+	return &localDriver{store: newStore(path)}
+}
+
+var _ datastore.Driver = &localDriver{}
+
+type localDriver struct {
+	store *store
+}
+
+func (ld *localDriver) Initialize() error {
+	plog.WithField("path", ld.store.path).Info("Opening local datastore")
+	return ld.store.open()
+}
+
+func (ld *localDriver) GetConnection() (datastore.Connection, error) {
+	return &localConnection{ld.store}, nil
+}
+
+func (ld *localDriver) Close() error {
+	return ld.store.close()
+}
@@ -0,0 +1,189 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package local
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/control-center/serviced/datastore"
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type LocalSuite struct {
+	dir    string
+	path   string
+	driver LocalDriver
+	conn   datastore.Connection
+}
+
+var _ = Suite(&LocalSuite{})
+
+func (s *LocalSuite) SetUpTest(c *C) {
+	var err error
+	s.dir, err = ioutil.TempDir("", "local-datastore-")
+	c.Assert(err, IsNil)
+	s.path = filepath.Join(s.dir, "datastore", "controlplane.db")
+	s.driver = New(s.path)
+	c.Assert(s.driver.Initialize(), IsNil)
+	s.conn, err = s.driver.GetConnection()
+	c.Assert(err, IsNil)
+}
+
+func (s *LocalSuite) TearDownTest(c *C) {
+	s.driver.Close()
+	os.RemoveAll(s.dir)
+}
+
+// reopen closes the driver and loads the journal again.
+func (s *LocalSuite) reopen(c *C) {
+	c.Assert(s.driver.Close(), IsNil)
+	s.driver = New(s.path)
+	c.Assert(s.driver.Initialize(), IsNil)
+	var err error
+	s.conn, err = s.driver.GetConnection()
+	c.Assert(err, IsNil)
+}
+
+func (s *LocalSuite) put(c *C, kind, id, data string) {
+	err := s.conn.Put(datastore.NewKey(kind, id), datastore.NewJSONMessage([]byte(data), 0))
+	c.Assert(err, IsNil)
+}
+
+func (s *LocalSuite) TestPutGet(c *C) {
+	key := datastore.NewKey("host", "h1")
+	_, err := s.conn.Get(key)
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+
+	s.put(c, "host", "h1", `{"ID":"h1","PoolID":"default"}`)
+	msg, err := s.conn.Get(key)
+	c.Assert(err, IsNil)
+	c.Assert(string(msg.Bytes()), Equals, `{"ID":"h1","PoolID":"default"}`)
+	c.Assert(msg.Version(), Equals, 1)
+
+	err = s.conn.Put(key, datastore.NewJSONMessage([]byte(`{"ID":"h1","PoolID":"other"}`), 1))
+	c.Assert(err, IsNil)
+	msg, err = s.conn.Get(key)
+	c.Assert(err, IsNil)
+	c.Assert(string(msg.Bytes()), Equals, `{"ID":"h1","PoolID":"other"}`)
+	c.Assert(msg.Version(), Equals, 2)
+
+	// the same id in another kind is a different entity
+	_, err = s.conn.Get(datastore.NewKey("pool", "h1"))
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+}
+
+func (s *LocalSuite) TestPutConflict(c *C) {
+	key := datastore.NewKey("host", "h1")
+	err := s.conn.Put(key, datastore.NewJSONMessage([]byte(`{}`), 3))
+	c.Assert(err, Equals, ErrConflict)
+
+	s.put(c, "host", "h1", `{}`)
+	s.put(c, "host", "h1", `{}`)
+	err = s.conn.Put(key, datastore.NewJSONMessage([]byte(`{}`), 1))
+	c.Assert(err, Equals, ErrConflict)
+	err = s.conn.Put(key, datastore.NewJSONMessage([]byte(`{}`), 2))
+	c.Assert(err, IsNil)
+}
+
+func (s *LocalSuite) TestDelete(c *C) {
+	key := datastore.NewKey("host", "h1")
+	err := s.conn.Delete(key)
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+
+	s.put(c, "host", "h1", `{}`)
+	c.Assert(s.conn.Delete(key), IsNil)
+	_, err = s.conn.Get(key)
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+}
+
+func (s *LocalSuite) TestPersistence(c *C) {
+	s.put(c, "host", "h1", `{"ID":"h1"}`)
+	s.put(c, "host", "h2", `{"ID":"h2"}`)
+	s.put(c, "host", "h2", `{"ID":"h2","Name":"two"}`)
+	c.Assert(s.conn.Delete(datastore.NewKey("host", "h1")), IsNil)
+
+	s.reopen(c)
+	_, err := s.conn.Get(datastore.NewKey("host", "h1"))
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+	msg, err := s.conn.Get(datastore.NewKey("host", "h2"))
+	c.Assert(err, IsNil)
+	c.Assert(string(msg.Bytes()), Equals, `{"ID":"h2","Name":"two"}`)
+	c.Assert(msg.Version(), Equals, 2)
+
+	// the journal was compacted when it was loaded
+	data, err := ioutil.ReadFile(s.path)
+	c.Assert(err, IsNil)
+	c.Assert(strings.Count(string(data), "\n"), Equals, 1)
+}
+
+func (s *LocalSuite) TestTruncatedJournal(c *C) {
+	s.put(c, "host", "h1", `{"ID":"h1"}`)
+	c.Assert(s.driver.Close(), IsNil)
+
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0600)
+	c.Assert(err, IsNil)
+	_, err = f.WriteString(`{"Kind":"host","ID":"h2","Vers`)
+	c.Assert(err, IsNil)
+	f.Close()
+
+	s.reopen(c)
+	_, err = s.conn.Get(datastore.NewKey("host", "h1"))
+	c.Assert(err, IsNil)
+	_, err = s.conn.Get(datastore.NewKey("host", "h2"))
+	c.Assert(datastore.IsErrNoSuchEntity(err), Equals, true)
+}
+
+func (s *LocalSuite) TestCorruptJournal(c *C) {
+	c.Assert(s.driver.Close(), IsNil)
+	err := ioutil.WriteFile(s.path, []byte("not json\n"), 0600)
+	c.Assert(err, IsNil)
+
+	s.driver = New(s.path)
+	err = s.driver.Initialize()
+	c.Assert(err, ErrorMatches, "could not read record 1 of .*")
+}
+
+func (s *LocalSuite) TestInUse(c *C) {
+	other := New(s.path)
+	err := other.Initialize()
+	c.Assert(err, ErrorMatches, "datastore .* is in use by another process")
+
+	c.Assert(s.driver.Close(), IsNil)
+	c.Assert(other.Initialize(), IsNil)
+	c.Assert(other.Close(), IsNil)
+}
+
+func (s *LocalSuite) TestEnumerate(c *C) {
+	s.put(c, "pool", "p1", `{"ID":"p1"}`)
+	s.put(c, "host", "h2", `{"ID":"h2"}`)
+	s.put(c, "host", "h1", `{"ID":"h1"}`)
+
+	var keys []string
+	err := s.conn.(datastore.Enumerator).Enumerate(func(key datastore.Key, msg datastore.JSONMessage) error {
+		keys = append(keys, key.Kind()+"/"+key.ID())
+		return nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(keys, DeepEquals, []string{"host/h1", "host/h2", "pool/p1"})
+}
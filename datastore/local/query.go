@@ -0,0 +1,226 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
+	"github.com/zenoss/elastigo/search"
+)
+
+// defaultSize is the number of hits Elasticsearch returns when a search does
+// not set a size.
+const defaultSize = 10
+
+// request is a search normalized from one of the query types accepted by the
+// elastic driver.
+type request struct {
+	types  []string
+	query  map[string]interface{}
+	filter map[string]interface{}
+	fields []string
+	sort   []sortField
+	from   int
+	size   int
+}
+
+// sortField is a field that the hits of a search are ordered by
+type sortField struct {
+	name string
+	desc bool
+}
+
+// parseRequest converts an elastigo search or an ElasticSearchRequest into a
+// request.
+func parseRequest(query interface{}) (*request, error) {
+	switch s := query.(type) {
+	case *search.SearchDsl:
+		req, err := parseBody(s)
+		if err != nil {
+			return nil, err
+		}
+		// The types and url arguments of the search are not exported, so
+		// they are read the same way elastigo builds the search url.
+		v := reflect.ValueOf(s).Elem()
+		types := v.FieldByName("types")
+		for i := 0; i < types.Len(); i++ {
+			req.types = append(req.types, types.Index(i).String())
+		}
+		args := url.Values{}
+		argsv := v.FieldByName("args")
+		for _, k := range argsv.MapKeys() {
+			vals := argsv.MapIndex(k)
+			for i := 0; i < vals.Len(); i++ {
+				args.Add(k.String(), vals.Index(i).String())
+			}
+		}
+		if size := args.Get("size"); size != "" {
+			if req.size, err = strconv.Atoi(size); err != nil {
+				return nil, fmt.Errorf("invalid search size %q", size)
+			}
+		}
+		if from := args.Get("from"); from != "" {
+			if req.from, err = strconv.Atoi(from); err != nil {
+				return nil, fmt.Errorf("invalid search offset %q", from)
+			}
+		}
+		return req, nil
+	case elastic.ElasticSearchRequest:
+		req, err := parseBody(s.Query)
+		if err != nil {
+			return nil, err
+		}
+		if s.Type != "" && s.Type != "*" {
+			req.types = []string{s.Type}
+		}
+		return req, nil
+	default:
+		return nil, fmt.Errorf("invalid search type %v", reflect.ValueOf(query))
+	}
+}
+
+// parseBody reads the json body of a search.
+func parseBody(body interface{}) (*request, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Query  map[string]interface{} `json:"query"`
+		Filter map[string]interface{} `json:"filter"`
+		Fields []string               `json:"fields"`
+		Sort   []interface{}          `json:"sort"`
+		From   int                    `json:"from"`
+		Size   *int                   `json:"size"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("could not read search: %s", err)
+	}
+	req := &request{
+		query:  doc.Query,
+		filter: doc.Filter,
+		fields: doc.Fields,
+		from:   doc.From,
+		size:   defaultSize,
+	}
+	if doc.Size != nil {
+		req.size = *doc.Size
+	}
+	for _, field := range doc.Sort {
+		switch field := field.(type) {
+		case string:
+			req.sort = append(req.sort, sortField{name: field})
+		case map[string]interface{}:
+			for name, order := range field {
+				if m, ok := order.(map[string]interface{}); ok {
+					order = m["order"]
+				}
+				req.sort = append(req.sort, sortField{name: name, desc: order == "desc"})
+			}
+		default:
+			return nil, fmt.Errorf("invalid sort %v", field)
+		}
+	}
+	return req, nil
+}
+
+// execute returns the records that match the request, ordered and limited to
+// the requested page and fields.
+func (req *request) execute(recs []*record) ([]datastore.JSONMessage, error) {
+	var hits []hit
+	for _, rec := range recs {
+		if len(req.sort) == 0 && len(hits) >= req.from+req.size {
+			break
+		}
+		doc, err := newDocument(rec)
+		if err != nil {
+			return nil, err
+		}
+		if req.query != nil {
+			if ok, err := doc.matches(req.query); err != nil {
+				return nil, err
+			} else if !ok {
+				continue
+			}
+		}
+		if req.filter != nil {
+			if ok, err := doc.matches(req.filter); err != nil {
+				return nil, err
+			} else if !ok {
+				continue
+			}
+		}
+		hits = append(hits, hit{rec: rec, doc: doc})
+	}
+	if len(req.sort) > 0 {
+		sort.Stable(&hitsByFields{hits: hits, fields: req.sort})
+	}
+
+	msgs := []datastore.JSONMessage{}
+	for i := req.from; i < len(hits) && len(msgs) < req.size; i++ {
+		data := []byte(hits[i].rec.Data)
+		if len(req.fields) > 0 {
+			var err error
+			if data, err = json.Marshal(hits[i].doc.project(req.fields)); err != nil {
+				return nil, err
+			}
+		}
+		msgs = append(msgs, datastore.NewJSONMessage(data, hits[i].rec.Version))
+	}
+	return msgs, nil
+}
+
+// hit is a record that matches a search
+type hit struct {
+	rec *record
+	doc *document
+}
+
+// hitsByFields orders hits by the sort fields of a search.  Hits without a
+// value for a field are ordered last, as they are by Elasticsearch.
+type hitsByFields struct {
+	hits   []hit
+	fields []sortField
+}
+
+func (h *hitsByFields) Len() int      { return len(h.hits) }
+func (h *hitsByFields) Swap(i, j int) { h.hits[i], h.hits[j] = h.hits[j], h.hits[i] }
+func (h *hitsByFields) Less(i, j int) bool {
+	for _, field := range h.fields {
+		a, b := h.hits[i].doc.values(field.name), h.hits[j].doc.values(field.name)
+		switch {
+		case len(a) == 0 && len(b) == 0:
+			continue
+		case len(a) == 0:
+			return false
+		case len(b) == 0:
+			return true
+		}
+		cmp := compare(a[0], b[0])
+		if field.desc {
+			cmp = -cmp
+		}
+		if cmp != 0 {
+			return cmp < 0
+		}
+	}
+	return false
+}
@@ -0,0 +1,220 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package local
+
+import (
+	"encoding/json"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/datastore/elastic"
+	"github.com/zenoss/elastigo/search"
+	. "gopkg.in/check.v1"
+)
+
+func (s *LocalSuite) seed(c *C) {
+	s.put(c, "service", "s1", `{"ID":"s1","Name":"Zope","PoolID":"default","ParentServiceID":"","Tags":["daemon","web"],"UpdatedAt":"2019-05-01T10:00:00Z","Endpoints":[{"Purpose":"export","AddressConfig":{"Port":8080},"Protocol":"tcp","VHostList":[{"Name":"zope"}]}]}`)
+	s.put(c, "service", "s2", `{"ID":"s2","Name":"zenhub","PoolID":"default","ParentServiceID":"s1","Tags":["daemon"],"UpdatedAt":"2019-06-01T10:00:00Z","Endpoints":[{"Purpose":"import","AddressConfig":{"Port":0}}]}`)
+	s.put(c, "service", "s3", `{"ID":"s3","Name":"mariadb","PoolID":"other","ParentServiceID":"s1","UpdatedAt":"2019-07-01T10:00:00Z","Endpoints":[{"Purpose":"export","AddressAssignment":{"IPAddr":"10.0.0.5"}}]}`)
+	s.put(c, "host", "h1", `{"ID":"h1","PoolID":"default","IPs":[{"IPAddress":"10.0.0.5"}]}`)
+}
+
+// ids runs the query and returns the ids of the results.
+func (s *LocalSuite) ids(c *C, query interface{}) []string {
+	msgs, err := s.conn.Query(query)
+	c.Assert(err, IsNil)
+	ids := []string{}
+	for _, msg := range msgs {
+		var doc struct{ ID string }
+		c.Assert(json.Unmarshal(msg.Bytes(), &doc), IsNil)
+		ids = append(ids, doc.ID)
+	}
+	return ids
+}
+
+func (s *LocalSuite) TestQueryTerm(c *C) {
+	s.seed(c)
+	q := search.Search("controlplane").Type("service").Size("50000").Query(search.Query().Term("PoolID", "default"))
+	c.Assert(s.ids(c, q), DeepEquals, []string{"s1", "s2"})
+
+	q = search.Search("controlplane").Type("host").Query(search.Query().Term("IPs.IPAddress", "10.0.0.5"))
+	c.Assert(s.ids(c, q), DeepEquals, []string{"h1"})
+
+	// without a type every kind is searched
+	q = search.Search("controlplane").Query(search.Query().Term("PoolID", "default"))
+	c.Assert(s.ids(c, q), DeepEquals, []string{"h1", "s1", "s2"})
+}
+
+func (s *LocalSuite) TestQueryTermsFilter(c *C) {
+	s.seed(c)
+	q := search.Search("controlplane").Type("service").Filter(
+		"and",
+		search.Filter().Terms("ParentServiceID", "s1"),
+		search.Filter().Terms("Name", "mariadb"),
+	)
+	c.Assert(s.ids(c, q), DeepEquals, []string{"s3"})
+
+	q = search.Search("controlplane").Type("service").Filter(
+		"and",
+		search.Filter().Terms("ParentServiceID", ""),
+		search.Filter().Terms("Name", "mariadb"),
+	)
+	c.Assert(s.ids(c, q), DeepEquals, []string{})
+
+	// numbers match their string form
+	q = search.Search("controlplane").Type("service").Filter(search.Filter().Terms("Endpoints.AddressConfig.Port", "8080"))
+	c.Assert(s.ids(c, q), DeepEquals, []string{"s1"})
+}
+
+func (s *LocalSuite) TestQueryString(c *C) {
+	s.seed(c)
+	query := func(qs string) []string {
+		q := search.Search("controlplane").Type("service").Size("50000").Query(search.Query().Search(qs))
+		return s.ids(c, q)
+	}
+	c.Assert(query("_exists_:ID"), DeepEquals, []string{"s1", "s2", "s3"})
+	c.Assert(query("_missing_:Tags"), DeepEquals, []string{"s3"})
+	c.Assert(query("daemon AND web"), DeepEquals, []string{"s1"})
+	c.Assert(query("web OR mariadb"), DeepEquals, []string{"s1", "s3"})
+	c.Assert(query("daemon AND NOT web"), DeepEquals, []string{"s2"})
+	c.Assert(query("Endpoints.AddressAssignment.IPAddr:10.0.0.5"), DeepEquals, []string{"s3"})
+	c.Assert(query("Name:zen*"), DeepEquals, []string{"s2"})
+	c.Assert(query(`Name:"Zope"`), DeepEquals, []string{"s1"})
+
+	msgs, err := s.conn.Query(search.Search("controlplane").Type("service").Query(search.Query().Search("(a OR b)")))
+	c.Assert(err, ErrorMatches, "grouping is not supported.*")
+	c.Assert(msgs, IsNil)
+}
+
+func (s *LocalSuite) TestQueryRange(c *C) {
+	s.seed(c)
+	q := search.Search("controlplane").Type("service").Size("50000").Query(
+		search.Query().Range(search.Range().Field("UpdatedAt").From("2019-06-01T10:00:00Z")).Search("_exists_:ID"),
+	)
+	c.Assert(s.ids(c, q), DeepEquals, []string{"s2", "s3"})
+}
+
+func (s *LocalSuite) TestQuerySize(c *C) {
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		s.put(c, "template", id, `{"ID":"`+id+`"}`)
+	}
+	q := search.Search("controlplane").Type("template").Query(search.Query().Search("_exists_:ID"))
+	c.Assert(s.ids(c, q), HasLen, defaultSize)
+
+	q = search.Search("controlplane").Type("template").Size("3").From("2").Query(search.Query().Search("_exists_:ID"))
+	c.Assert(s.ids(c, q), DeepEquals, []string{"c", "d", "e"})
+}
+
+func (s *LocalSuite) TestQuerySort(c *C) {
+	s.put(c, "auditevent", "a", `{"ID":"a","Time":"2019-06-01T10:00:00Z"}`)
+	s.put(c, "auditevent", "b", `{"ID":"b","Time":"2019-06-03T10:00:00Z"}`)
+	s.put(c, "auditevent", "c", `{"ID":"c"}`)
+	s.put(c, "auditevent", "d", `{"ID":"d","Time":"2019-06-02T10:00:00Z"}`)
+
+	q := search.Search("controlplane").Type("auditevent").Size("2").Sort(search.Sort("Time").Desc()).Query(search.Query().Search("_exists_:ID"))
+	c.Assert(s.ids(c, q), DeepEquals, []string{"b", "d"})
+
+	q = search.Search("controlplane").Type("auditevent").Size("10").From("1").Sort(search.Sort("Time")).Query(search.Query().Search("_exists_:ID"))
+	c.Assert(s.ids(c, q), DeepEquals, []string{"d", "b", "c"})
+}
+
+func (s *LocalSuite) TestQueryElasticSearchRequest(c *C) {
+	s.seed(c)
+	request := func(query map[string]interface{}) elastic.ElasticSearchRequest {
+		query["size"] = 50000
+		return elastic.ElasticSearchRequest{Index: "controlplane", Type: "service", Query: query}
+	}
+
+	q := request(map[string]interface{}{
+		"query": map[string]interface{}{
+			"ids": map[string]interface{}{"values": []string{"s2"}},
+		},
+	})
+	c.Assert(s.ids(c, q), DeepEquals, []string{"s2"})
+
+	// ids or a case insensitive name match
+	q = request(map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should": []map[string]interface{}{
+					{"ids": map[string]interface{}{"values": []string{"s3"}}},
+					{"regexp": map[string]interface{}{"Name": ".*[zZ][oO][pP][eE].*"}},
+				},
+			},
+		},
+	})
+	c.Assert(s.ids(c, q), DeepEquals, []string{"s1", "s3"})
+
+	q = request(map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": []map[string]interface{}{
+					{"range": map[string]interface{}{
+						"Endpoints.AddressConfig.Port": map[string]interface{}{"gt": 0, "lte": 65535},
+					}},
+					{"regexp": map[string]interface{}{"Endpoints.Protocol": ".+"}},
+				},
+			},
+		},
+	})
+	c.Assert(s.ids(c, q), DeepEquals, []string{"s1"})
+
+	q = request(map[string]interface{}{
+		"query": map[string]interface{}{
+			"term": map[string]interface{}{"Endpoints.Purpose": "export"},
+		},
+	})
+	c.Assert(s.ids(c, q), DeepEquals, []string{"s1", "s3"})
+}
+
+func (s *LocalSuite) TestQueryFields(c *C) {
+	s.seed(c)
+	q := elastic.ElasticSearchRequest{
+		Index: "controlplane",
+		Type:  "service",
+		Query: map[string]interface{}{
+			"query":  map[string]interface{}{"term": map[string]string{"ParentServiceID": "s1"}},
+			"fields": []string{"ID", "Name"},
+			"size":   1,
+		},
+	}
+	msgs, err := s.conn.Query(q)
+	c.Assert(err, IsNil)
+	c.Assert(msgs, HasLen, 1)
+	c.Assert(string(msgs[0].Bytes()), Equals, `{"ID":"s2","Name":"zenhub"}`)
+	c.Assert(msgs[0].Version(), Equals, 1)
+}
+
+func (s *LocalSuite) TestQueryUnsupported(c *C) {
+	s.seed(c)
+	q := elastic.ElasticSearchRequest{
+		Type:  "service",
+		Query: map[string]interface{}{"query": map[string]interface{}{"fuzzy": map[string]string{"Name": "zope"}}},
+	}
+	_, err := s.conn.Query(q)
+	c.Assert(err, ErrorMatches, `unsupported search clause "fuzzy"`)
+
+	_, err = s.conn.Query("_exists_:ID")
+	c.Assert(err, ErrorMatches, "invalid search type .*")
+}
+
+func (s *LocalSuite) TestQueryDatastore(c *C) {
+	s.seed(c)
+	datastore.Register(s.driver)
+	q := search.Search("controlplane").Type("host").Query(search.Query().Term("PoolID", "default"))
+	results, err := datastore.NewQuery(datastore.Get()).Execute(q)
+	c.Assert(err, IsNil)
+	c.Assert(results.Len(), Equals, 1)
+}
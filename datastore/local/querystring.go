@@ -0,0 +1,266 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// qsTerm is a single term of a query string.
+type qsTerm struct {
+	field  string
+	value  string
+	quoted bool
+	negate bool
+}
+
+// matchQueryString evaluates the subset of the Lucene query string syntax
+// used by the stores: field:value, _exists_:field and _missing_:field terms,
+// bare words, quoted phrases, * and ? wildcards, and the AND, OR and NOT
+// operators.  Grouping with parentheses is not supported.
+func (doc *document) matchQueryString(clause map[string]interface{}) (bool, error) {
+	defaultOp := strings.ToUpper(toString(clause["default_operator"]))
+	if defaultOp == "" {
+		defaultOp = "OR"
+	}
+	groups, err := parseQueryString(toString(clause["query"]), defaultOp)
+	if err != nil {
+		return false, err
+	}
+	defaultField := toString(clause["default_field"])
+
+	for _, group := range groups {
+		ok := true
+		for _, term := range group {
+			if term.field == "" && defaultField != "" && defaultField != "_all" {
+				term.field = defaultField
+			}
+			matched, err := doc.matchTerm(term)
+			if err != nil {
+				return false, err
+			}
+			if matched == term.negate {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// parseQueryString splits a query string into groups of terms.  The document
+// must match every term of at least one group.
+func parseQueryString(query, defaultOp string) ([][]qsTerm, error) {
+	tokens, err := tokenizeQueryString(query)
+	if err != nil {
+		return nil, err
+	}
+	groups := [][]qsTerm{{}}
+	op, negate := "", false
+	for _, tok := range tokens {
+		switch tok {
+		case "AND", "&&":
+			op = "AND"
+			continue
+		case "OR", "||":
+			op = "OR"
+			continue
+		case "NOT", "!":
+			negate = !negate
+			continue
+		}
+		term, err := parseTerm(tok)
+		if err != nil {
+			return nil, err
+		}
+		if negate {
+			term.negate = !term.negate
+			negate = false
+		}
+		last := len(groups) - 1
+		if len(groups[last]) > 0 {
+			if op == "OR" || (op == "" && defaultOp == "OR") {
+				groups = append(groups, []qsTerm{})
+				last++
+			}
+		}
+		groups[last] = append(groups[last], term)
+		op = ""
+	}
+	return groups, nil
+}
+
+// tokenizeQueryString splits the query on whitespace outside of quotes.
+func tokenizeQueryString(query string) ([]string, error) {
+	var tokens []string
+	var tok []rune
+	quoted, escaped := false, false
+	for _, r := range query {
+		switch {
+		case escaped:
+			tok = append(tok, '\\', r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+			tok = append(tok, r)
+		case !quoted && (r == '(' || r == ')'):
+			return nil, fmt.Errorf("grouping is not supported in query %q", query)
+		case !quoted && unicode.IsSpace(r):
+			if len(tok) > 0 {
+				tokens = append(tokens, string(tok))
+				tok = nil
+			}
+		default:
+			tok = append(tok, r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in query %q", query)
+	}
+	if len(tok) > 0 {
+		tokens = append(tokens, string(tok))
+	}
+	return tokens, nil
+}
+
+// parseTerm reads a [+-][field:]value token.
+func parseTerm(tok string) (qsTerm, error) {
+	var term qsTerm
+	if strings.HasPrefix(tok, "-") {
+		term.negate = true
+		tok = tok[1:]
+	} else if strings.HasPrefix(tok, "+") {
+		tok = tok[1:]
+	}
+	for i := 0; i < len(tok); i++ {
+		if tok[i] == '\\' {
+			i++
+		} else if tok[i] == '"' {
+			break
+		} else if tok[i] == ':' {
+			term.field, tok = unescape(tok[:i]), tok[i+1:]
+			break
+		}
+	}
+	if len(tok) >= 2 && strings.HasPrefix(tok, "\"") && strings.HasSuffix(tok, "\"") {
+		term.quoted = true
+		tok = tok[1 : len(tok)-1]
+	}
+	if tok == "" {
+		return term, fmt.Errorf("empty term in query")
+	}
+	term.value = tok
+	return term, nil
+}
+
+// matchTerm evaluates a single term of a query string.
+func (doc *document) matchTerm(term qsTerm) (bool, error) {
+	switch term.field {
+	case "_exists_":
+		return len(doc.values(unescape(term.value))) > 0, nil
+	case "_missing_":
+		return len(doc.values(unescape(term.value))) == 0, nil
+	}
+
+	var match func(string) bool
+	if !term.quoted && strings.ContainsAny(term.value, "*?") {
+		re, err := wildcard(term.value)
+		if err != nil {
+			return false, err
+		}
+		match = re.MatchString
+	} else {
+		value := unescape(term.value)
+		if term.field == "" {
+			// bare words search every field, which elasticsearch analyzes
+			// into lowercase tokens
+			match = func(s string) bool {
+				if strings.EqualFold(s, value) {
+					return true
+				}
+				for _, word := range strings.FieldsFunc(s, isSeparator) {
+					if strings.EqualFold(word, value) {
+						return true
+					}
+				}
+				return false
+			}
+		} else {
+			match = func(s string) bool { return s == value }
+		}
+	}
+
+	var values []interface{}
+	if term.field == "" {
+		values = doc.leaves()
+	} else {
+		values = doc.values(term.field)
+	}
+	for _, v := range values {
+		if match(toString(v)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// wildcard converts a term with * and ? wildcards into a regular expression.
+func wildcard(value string) (*regexp.Regexp, error) {
+	var expr []string
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			if i+1 < len(value) {
+				i++
+				expr = append(expr, regexp.QuoteMeta(value[i:i+1]))
+			}
+		case '*':
+			expr = append(expr, ".*")
+		case '?':
+			expr = append(expr, ".")
+		default:
+			expr = append(expr, regexp.QuoteMeta(value[i:i+1]))
+		}
+	}
+	return regexp.Compile("^" + strings.Join(expr, "") + "$")
+}
+
+func unescape(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+	var out []rune
+	escaped := false
+	for _, r := range s {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		out = append(out, r)
+		escaped = false
+	}
+	return string(out)
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
@@ -0,0 +1,265 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// compactThreshold is the number of stale journal entries that are tolerated
+// before the journal is rewritten with only the live records.
+const compactThreshold = 1000
+
+// record is a single line in the journal.  A record with no data marks the
+// deletion of an entity.
+type record struct {
+	Kind    string
+	ID      string
+	Version int
+	Data    json.RawMessage `json:",omitempty"`
+}
+
+// store is an append-only journal of records backed by an in-memory index of
+// the latest version of every entity.
+type store struct {
+	sync.RWMutex
+	path    string
+	lock    *os.File
+	file    *os.File
+	kinds   map[string]map[string]*record
+	live    int
+	entries int
+}
+
+func newStore(path string) *store {
+	return &store{path: path}
+}
+
+// open locks the store, replays the journal and compacts it.
+func (s *store) open() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.file != nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0750); err != nil {
+		return err
+	}
+	lock, err := os.OpenFile(s.path+".lock", os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	if err := syscall.Flock(int(lock.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		lock.Close()
+		return fmt.Errorf("datastore %s is in use by another process", s.path)
+	}
+	s.lock = lock
+	s.kinds = make(map[string]map[string]*record)
+	s.live, s.entries = 0, 0
+	if err := s.replay(); err != nil {
+		s.unlock()
+		return err
+	}
+	if err := s.compact(); err != nil {
+		s.unlock()
+		return err
+	}
+	return nil
+}
+
+// close releases the journal and the lock on the store.
+func (s *store) close() error {
+	s.Lock()
+	defer s.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	s.unlock()
+	return err
+}
+
+func (s *store) unlock() {
+	if s.lock != nil {
+		syscall.Flock(int(s.lock.Fd()), syscall.LOCK_UN)
+		s.lock.Close()
+		s.lock = nil
+	}
+}
+
+// replay loads the journal into memory.  A truncated final line, left behind
+// if the process died in the middle of a write, is discarded.
+func (s *store) replay() error {
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for lineno := 1; ; lineno++ {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			if len(bytes.TrimSpace(line)) > 0 {
+				plog.WithFields(log.Fields{
+					"path": s.path,
+					"line": lineno,
+				}).Warn("Discarding incomplete record at the end of the datastore journal")
+			}
+			return nil
+		} else if err != nil {
+			return err
+		}
+		var rec record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return fmt.Errorf("could not read record %d of %s: %s", lineno, s.path, err)
+		}
+		s.apply(&rec)
+	}
+}
+
+// apply updates the in-memory index with a record.
+func (s *store) apply(rec *record) {
+	s.entries++
+	ids, ok := s.kinds[rec.Kind]
+	if !ok {
+		ids = make(map[string]*record)
+		s.kinds[rec.Kind] = ids
+	}
+	_, exists := ids[rec.ID]
+	if len(rec.Data) == 0 {
+		if exists {
+			delete(ids, rec.ID)
+			s.live--
+		}
+		return
+	}
+	if !exists {
+		s.live++
+	}
+	ids[rec.ID] = rec
+}
+
+// compact rewrites the journal with only the live records and reopens it for
+// appending.
+func (s *store) compact() error {
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, rec := range s.all("") {
+		if err := enc.Encode(rec); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file, err = os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	s.entries = s.live
+	return nil
+}
+
+// write appends a record to the journal and applies it.
+func (s *store) write(rec *record) error {
+	if s.file == nil {
+		return fmt.Errorf("datastore %s is not open", s.path)
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	if err := s.file.Sync(); err != nil {
+		return err
+	}
+	s.apply(rec)
+	if s.entries-s.live > compactThreshold && s.entries > 2*s.live {
+		if err := s.compact(); err != nil {
+			plog.WithError(err).WithField("path", s.path).Warn("Unable to compact datastore journal")
+		}
+	}
+	return nil
+}
+
+// get returns the record for the kind and id.
+func (s *store) get(kind, id string) (*record, bool) {
+	rec, ok := s.kinds[kind][id]
+	return rec, ok
+}
+
+// all returns the records of the kind ordered by id, or every record ordered
+// by kind and id if the kind is empty.
+func (s *store) all(kind string) []*record {
+	var recs []*record
+	for k, ids := range s.kinds {
+		if kind != "" && k != kind {
+			continue
+		}
+		for _, rec := range ids {
+			recs = append(recs, rec)
+		}
+	}
+	sort.Sort(recordsByKey(recs))
+	return recs
+}
+
+type recordsByKey []*record
+
+func (r recordsByKey) Len() int      { return len(r) }
+func (r recordsByKey) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r recordsByKey) Less(i, j int) bool {
+	if r[i].Kind != r[j].Kind {
+		return r[i].Kind < r[j].Kind
+	}
+	return r[i].ID < r[j].ID
+}
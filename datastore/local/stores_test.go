@@ -0,0 +1,209 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package local
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/auditlog"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/domain/session"
+	. "gopkg.in/check.v1"
+)
+
+// The queries below are the ones issued by the domain stores, so they are run
+// through the stores rather than rebuilt by hand.
+
+func (s *LocalSuite) TestPoolStore(c *C) {
+	datastore.Register(s.driver)
+	ctx := datastore.Get()
+	store := pool.NewStore()
+
+	for _, p := range []*pool.ResourcePool{
+		{ID: "default", Realm: "default", VirtualIPs: []pool.VirtualIP{{PoolID: "default", IP: "10.0.0.9"}}},
+		{ID: "remote", Realm: "east"},
+	} {
+		c.Assert(store.Put(ctx, pool.Key(p.ID), p), IsNil)
+	}
+
+	pools, err := store.GetResourcePools(ctx)
+	c.Assert(err, IsNil)
+	c.Assert(pools, HasLen, 2)
+
+	pools, err = store.GetResourcePoolsByRealm(ctx, "east")
+	c.Assert(err, IsNil)
+	c.Assert(pools, HasLen, 1)
+	c.Assert(pools[0].ID, Equals, "remote")
+
+	ok, err := store.HasVirtualIP(ctx, "default", "10.0.0.9")
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+	ok, err = store.HasVirtualIP(ctx, "remote", "10.0.0.9")
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+
+	// updates must carry the current version
+	p := &pool.ResourcePool{}
+	c.Assert(store.Get(ctx, pool.Key("remote"), p), IsNil)
+	p.Description = "first"
+	c.Assert(store.Put(ctx, pool.Key("remote"), p), IsNil)
+	p.DatabaseVersion = 1
+	c.Assert(store.Put(ctx, pool.Key("remote"), p), Equals, ErrConflict)
+}
+
+func (s *LocalSuite) TestServiceStore(c *C) {
+	datastore.Register(s.driver)
+	ctx := datastore.Get()
+	store := service.NewStore()
+
+	tenant := &service.Service{ID: "tenant", Name: "Zenoss", PoolID: "default", Launch: "auto", DeploymentID: "dep"}
+	child := &service.Service{
+		ID:              "child",
+		Name:            "Zope",
+		PoolID:          "default",
+		Launch:          "auto",
+		DeploymentID:    "dep",
+		ParentServiceID: "tenant",
+		Endpoints: []service.ServiceEndpoint{
+			{Name: "zope", Application: "zope", Purpose: "export", VHostList: []servicedefinition.VHost{{Name: "zope", Enabled: true}}},
+		},
+	}
+	other := &service.Service{ID: "other", Name: "mariadb", PoolID: "remote", Launch: "auto", DeploymentID: "dep", ParentServiceID: "tenant"}
+	for _, svc := range []*service.Service{tenant, child, other} {
+		c.Assert(store.Put(ctx, svc), IsNil)
+	}
+
+	svcs, err := store.GetServicesByPool(ctx, "default")
+	c.Assert(err, IsNil)
+	c.Assert(svcs, HasLen, 2)
+
+	svcs, err = store.GetChildServices(ctx, "tenant")
+	c.Assert(err, IsNil)
+	c.Assert(svcs, HasLen, 2)
+
+	svc, err := store.FindChildService(ctx, "dep", "tenant", "Zope")
+	c.Assert(err, IsNil)
+	c.Assert(svc, NotNil)
+	c.Assert(svc.ID, Equals, "child")
+
+	svc, err = store.FindTenantByDeploymentID(ctx, "dep", "Zenoss")
+	c.Assert(err, IsNil)
+	c.Assert(svc, NotNil)
+	c.Assert(svc.ID, Equals, "tenant")
+
+	details, err := store.GetServiceDetailsByIDOrName(ctx, "zop", false)
+	c.Assert(err, IsNil)
+	c.Assert(details, HasLen, 1)
+	c.Assert(details[0].ID, Equals, "child")
+
+	details, err = store.GetServiceDetailsByParentID(ctx, "tenant", 0)
+	c.Assert(err, IsNil)
+	c.Assert(details, HasLen, 2)
+
+	d, err := store.GetServiceDetails(ctx, "tenant")
+	c.Assert(err, IsNil)
+	c.Assert(d.HasChildren, Equals, true)
+
+	peps, err := store.GetAllPublicEndpoints(ctx)
+	c.Assert(err, IsNil)
+	c.Assert(peps, HasLen, 1)
+	c.Assert(peps[0].VHostName, Equals, "zope")
+
+	c.Assert(store.Delete(ctx, "other"), IsNil)
+	svcs, err = store.GetServices(ctx)
+	c.Assert(err, IsNil)
+	c.Assert(svcs, HasLen, 2)
+}
+
+func (s *LocalSuite) TestAuditEventStore(c *C) {
+	datastore.Register(s.driver)
+	ctx := datastore.Get()
+	store := auditlog.NewStore()
+
+	t0 := time.Date(2019, 3, 5, 10, 0, 0, 0, time.UTC)
+	for i, e := range []audit.Event{
+		{Time: t0, User: "admin", Action: audit.Stop, Type: "service", EntityID: "svc1", Success: true},
+		{Time: t0.Add(time.Hour), User: "admin", Action: audit.Start, Type: "service", EntityID: "svc1", Success: false},
+		{Time: t0.Add(2 * time.Hour), User: "system", Action: audit.Add, Type: "resourcepool", EntityID: "pool1", Success: true},
+	} {
+		ev := &auditlog.Event{ID: string('a' + rune(i)), Event: e}
+		c.Assert(store.Put(ctx, ev), IsNil)
+	}
+
+	events, err := store.GetEvents(ctx, auditlog.Filter{})
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 3)
+	c.Assert(events[0].ID, Equals, "c")
+	c.Assert(events[2].ID, Equals, "a")
+
+	events, err = store.GetEvents(ctx, auditlog.Filter{User: "admin", Type: "service", EntityID: "svc1", Limit: 1})
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 1)
+	c.Assert(events[0].ID, Equals, "b")
+
+	failed := false
+	events, err = store.GetEvents(ctx, auditlog.Filter{Success: &failed})
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 1)
+	c.Assert(events[0].ID, Equals, "b")
+
+	events, err = store.GetEvents(ctx, auditlog.Filter{Action: audit.Stop, Since: t0, Until: t0.Add(time.Hour)})
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 1)
+	c.Assert(events[0].ID, Equals, "a")
+
+	count, err := store.DeleteBefore(ctx, t0.Add(90*time.Minute))
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 2)
+	events, err = store.GetEvents(ctx, auditlog.Filter{})
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 1)
+	c.Assert(events[0].ID, Equals, "c")
+}
+
+func (s *LocalSuite) TestSessionStore(c *C) {
+	datastore.Register(s.driver)
+	ctx := datastore.Get()
+	store := session.NewStore()
+
+	t0 := time.Date(2019, 3, 5, 10, 0, 0, 0, time.UTC)
+	for _, sess := range []*session.Session{
+		{ID: "s1", Kind: session.KindShell, User: "admin", ServiceID: "svc1", TenantID: "tenant", InstanceID: -1, Started: t0},
+		{ID: "s2", Kind: session.KindAttach, User: "jdoe", ServiceID: "svc2", TenantID: "tenant", Started: t0.Add(time.Hour)},
+		{ID: "s3", Kind: session.KindRun, User: "jdoe", ServiceID: "svc3", TenantID: "other", InstanceID: -1, Started: t0.Add(2 * time.Hour)},
+	} {
+		c.Assert(store.Put(ctx, sess), IsNil)
+	}
+
+	sessions, err := store.GetSessions(ctx, session.Filter{})
+	c.Assert(err, IsNil)
+	c.Assert(sessions, HasLen, 3)
+	c.Assert(sessions[0].ID, Equals, "s3")
+
+	sessions, err = store.GetSessions(ctx, session.Filter{User: "jdoe", TenantID: "tenant"})
+	c.Assert(err, IsNil)
+	c.Assert(sessions, HasLen, 1)
+	c.Assert(sessions[0].ID, Equals, "s2")
+
+	sessions, err = store.GetSessions(ctx, session.Filter{ServiceID: "svc1"})
+	c.Assert(err, IsNil)
+	c.Assert(sessions, HasLen, 1)
+	c.Assert(sessions[0].InstanceID, Equals, -1)
+}
@@ -34,8 +34,10 @@ const (
 	DockerImagesFile     = "IMAGES.dkr"
 )
 
-// Backup writes all application data into an export stream
-func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) error {
+// Backup writes all application data into an export stream.  If there is a
+// parent backup, only the volume data and images that have changed since the
+// parent backup are written.
+func (dfs *DistributedFilesystem) Backup(data BackupInfo, parent *BackupInfo, w io.Writer) error {
 
 	backupLogger := plog.WithFields(log.Fields{
 		"backupversion": data.BackupVersion,
@@ -45,13 +47,9 @@ func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) error {
 	progress := NewProgressCounter(300)
 	progress.Log = func() { plog.Infof("Written %v bytes to archive for backup", progress.Total) }
 
-	tarOut := tar.NewWriter(io.MultiWriter(w, progress))
-
-	// write the backup metadata
-	if err := dfs.writeBackupMetadata(data, tarOut); err != nil {
-		plog.WithError(err).Error("Unable to write metadata for backup")
-		return err
-	}
+	sums := newChecksumWriter()
+	defer sums.Close()
+	tarOut := tar.NewWriter(io.MultiWriter(w, progress, sums))
 
 	var images []string
 
@@ -87,12 +85,14 @@ func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) error {
 
 	backupLogger.WithField("total", numberOfSnapshots).Info("Preparing snapshots for backup")
 
-	// export the snapshots
+	vols := make([]volume.Volume, numberOfSnapshots)
+	infos := make([]*volume.SnapshotInfo, numberOfSnapshots)
 	for i, snapshot := range data.Snapshots {
 		vol, info, err := dfs.getSnapshotVolumeAndInfo(snapshot)
 		if err != nil {
 			return err
 		}
+		vols[i], infos[i] = vol, info
 
 		// load the images from this snapshot
 		tenantLogger := backupLogger.WithField("tenant", info.TenantID)
@@ -132,13 +132,40 @@ func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) error {
 		}
 
 		timer.Stop()
+	}
+
+	// only keep the images that changed since the parent backup
+	images, imageIDs, err := dfs.changedImages(images, parent)
+	if err != nil {
+		return err
+	}
+	data.ImageIDs = imageIDs
 
+	// write the backup metadata
+	if err := dfs.writeBackupMetadata(data, tarOut); err != nil {
+		plog.WithError(err).Error("Unable to write metadata for backup")
+		return err
+	}
+
+	// export the snapshots
+	for i, snapshot := range data.Snapshots {
+		vol, info := vols[i], infos[i]
 		snapshotLogger := backupLogger.WithField("snapshot", snapshot)
 
+		var parentSnapshot string
+		var parentManifest Manifest
+		if parent != nil {
+			if parentSnapshot, parentManifest, err = dfs.parentManifest(vol, info.TenantID, parent); err != nil {
+				snapshotLogger.WithError(err).Error("Could not get the manifest of the parent backup")
+				return err
+			}
+		}
+
 		// dump the snapshot into the backup
 		prefix := path.Join(SnapshotsMetadataDir, info.TenantID, info.Label)
-		snapReader, errchan := dfs.snapshotSavePipe(vol, info.Label, data.SnapshotExcludes[snapshot])
-		if err := rewriteTar(prefix, tarOut, snapReader); err != nil {
+		snapReader, errchan := dfs.snapshotSavePipe(vol, info.Label, parentSnapshot, data.SnapshotExcludes[snapshot])
+		manifest, err := rewriteSnapshotTar(prefix, tarOut, snapReader, parentManifest)
+		if err != nil {
 			// be a good citizen and clean up any running threads
 			<-errchan
 			snapshotLogger.WithError(err).Error("Could not write snapshot to backup")
@@ -148,6 +175,22 @@ func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) error {
 			return err
 		}
 
+		// save the manifest with the backup and the snapshot, so that the
+		// next incremental backup can find what changed
+		if err := writeManifest(tarOut, prefix+BackupManifestSuffix, manifest); err != nil {
+			snapshotLogger.WithError(err).Error("Could not write snapshot manifest to backup")
+			return err
+		}
+		mw, err := vol.WriteMetadata(info.Label, BackupManifestFile)
+		if err != nil {
+			snapshotLogger.WithError(err).Error("Could not save manifest to snapshot")
+			return err
+		}
+		if err := exportJSON(mw, manifest); err != nil {
+			snapshotLogger.WithError(err).Error("Could not save manifest to snapshot")
+			return err
+		}
+
 		snapshotLogger.WithFields(log.Fields{
 			"numbercomplete": i + 1,
 			"total":          numberOfSnapshots,
@@ -155,8 +198,12 @@ func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) error {
 	}
 
 	// dump the images from all the snapshots into the backup
-	imageReader, errchan := dfs.dockerSavePipe(images...)
 	imageLogger := backupLogger.WithField("images", images)
+	if len(images) == 0 {
+		imageLogger.Info("No images to export to backup")
+		return dfs.finishBackup(tarOut, sums)
+	}
+	imageReader, errchan := dfs.dockerSavePipe(images...)
 	imageLogger.Info("Starting export of images to backup")
 	if err := rewriteTar(DockerImagesFile, tarOut, imageReader); err != nil {
 		// be a good citizen and clean up any running threads
@@ -167,11 +214,28 @@ func (dfs *DistributedFilesystem) Backup(data BackupInfo, w io.Writer) error {
 		imageLogger.WithError(err).Error("Could not export images for backup")
 		return err
 	}
-	tarOut.Close()
 
 	imageLogger.Info("Exported images to backup")
 
-	return nil
+	return dfs.finishBackup(tarOut, sums)
+}
+
+// finishBackup writes the checksums of the entries of the backup and closes
+// the backup
+func (dfs *DistributedFilesystem) finishBackup(tarOut *tar.Writer, sums *checksumWriter) error {
+	if err := tarOut.Flush(); err != nil {
+		return err
+	}
+	result := sums.Close()
+	if result.err != nil {
+		plog.WithError(result.err).Error("Could not compute checksums for backup")
+		return result.err
+	}
+	if err := writeChecksums(tarOut, result.sums, dfs.backupKey); err != nil {
+		plog.WithError(err).Error("Could not write checksums to backup")
+		return err
+	}
+	return tarOut.Close()
 }
 
 // savePipe is a generic io pipe that returns the reader
@@ -193,10 +257,12 @@ func (dfs *DistributedFilesystem) dockerSavePipe(images ...string) (*io.PipeRead
 	})
 }
 
-// snapshotSavePipe returns a pipe that exports a given volume to the pipe's stdout
-func (dfs *DistributedFilesystem) snapshotSavePipe(vol volume.Volume, label string, excludes []string) (*io.PipeReader, <-chan error) {
+// snapshotSavePipe returns a pipe that exports a given volume to the pipe's
+// stdout.  If parent is set, only the changes since the parent snapshot are
+// exported.
+func (dfs *DistributedFilesystem) snapshotSavePipe(vol volume.Volume, label, parent string, excludes []string) (*io.PipeReader, <-chan error) {
 	return savePipe(func(w io.Writer) error {
-		return vol.Export(label, "", w, excludes)
+		return vol.Export(label, parent, w, excludes)
 	})
 }
 
@@ -94,7 +94,10 @@ func (s *DFSTestSuite) BenchmarkBackup(c *C) {
 		c.Logf("ReadMetadata should return %#v", imagesbuf)
 		s.registry.On("PullImage", mock.AnythingOfType("<-chan time.Time"), "TENANT/repo:tag").Return(nil)
 		s.docker.On("PullImage", "library/repo:tag").Return(nil)
-		s.docker.On("FindImage", "library/repo:tag").Return(&dockerclient.Image{}, dockerclient.ErrNoSuchImage)
+		s.docker.On("FindImage", "library/repo:tag").Return(&dockerclient.Image{}, dockerclient.ErrNoSuchImage).Once()
+		s.docker.On("FindImage", "library/repo:tag").Return(&dockerclient.Image{ID: "baseimage"}, nil)
+		s.docker.On("FindImage", "testserver:5000/TENANT/repo:tag").Return(&dockerclient.Image{ID: "tenantimage"}, nil)
+		vol.On("WriteMetadata", SnapshotLabel, BackupManifestFile).Return(&NopCloser{bytes.NewBufferString("")}, nil)
 		s.registry.On("ImagePath", "TENANT/repo:tag").Return("testserver:5000/TENANT/repo:tag", nil)
 		vol.On("Export", SnapshotLabel, "", mock.AnythingOfType("*io.PipeWriter")).Return(nil).Run(func(a mock.Arguments) {
 			writer := a.Get(2).(io.Writer)
@@ -108,7 +111,7 @@ func (s *DFSTestSuite) BenchmarkBackup(c *C) {
 		})
 		buf := bytes.NewBufferString("")
 		c.StartTimer()
-		err := s.dfs.Backup(backupInfo, buf)
+		err := s.dfs.Backup(backupInfo, nil, buf)
 		c.Assert(err, IsNil)
 	}
 }
@@ -54,7 +54,7 @@ func (s *DFSTestSuite) TestBackup_DelayOnExportError(c *C) {
 	timeout := time.NewTimer(time.Second)
 	errC := make(chan error)
 	go func() {
-		errC <- s.dfs.Backup(backupInfo, buf)
+		errC <- s.dfs.Backup(backupInfo, nil, buf)
 		timeout.Stop()
 	}()
 
@@ -80,12 +80,12 @@ func (s *DFSTestSuite) TestBackup_ImageNotFound(c *C) {
 		Timestamp: time.Now().UTC(),
 	}
 	s.docker.On("FindImage", "library/repo:tag").Return(&dockerclient.Image{}, ErrTestImageNotFound).Once()
-	err := s.dfs.Backup(backupInfo, buf)
+	err := s.dfs.Backup(backupInfo, nil, buf)
 	c.Assert(err, Equals, ErrTestImageNotFound)
 	buf.Reset()
 	s.docker.On("FindImage", "library/repo:tag").Return(&dockerclient.Image{}, dockerclient.ErrNoSuchImage).Once()
 	s.docker.On("PullImage", "library/repo:tag").Return(ErrTestNoPull).Once()
-	err = s.dfs.Backup(backupInfo, buf)
+	err = s.dfs.Backup(backupInfo, nil, buf)
 	c.Assert(err, Equals, ErrTestNoPull)
 }
 
@@ -127,6 +127,8 @@ func (s *DFSTestSuite) TestBackup_SkipTemplateImage(c *C) {
 		tarwriter.Write(data)
 		tarwriter.Close()
 	})
+	s.docker.On("FindImage", "testserver:5000/BASE/repo:tag").Return(&dockerclient.Image{ID: "tenantimage"}, nil)
+	vol.On("WriteMetadata", "LABEL", BackupManifestFile).Return(&NopCloser{bytes.NewBufferString("")}, nil)
 	allImages := []string{"testserver:5000/BASE/repo:tag"}
 	s.docker.On("SaveImages", allImages, mock.AnythingOfType("*io.PipeWriter")).Return(nil).Run(func(a mock.Arguments) {
 		writer := a.Get(1).(io.Writer)
@@ -137,7 +139,7 @@ func (s *DFSTestSuite) TestBackup_SkipTemplateImage(c *C) {
 		tarwriter.Write(data)
 		tarwriter.Close()
 	})
-	err = s.dfs.Backup(backupInfo, buf)
+	err = s.dfs.Backup(backupInfo, nil, buf)
 	c.Assert(err, IsNil)
 	c.Assert(buf.Len() > 0, Equals, true)
 }
@@ -180,6 +182,9 @@ func (s *DFSTestSuite) TestBackup(c *C) {
 		tarwriter.Write(data)
 		tarwriter.Close()
 	})
+	s.docker.On("FindImage", "library/repo:tag").Return(&dockerclient.Image{ID: "baseimage"}, nil)
+	s.docker.On("FindImage", "testserver:5000/BASE/repo:tag").Return(&dockerclient.Image{ID: "tenantimage"}, nil)
+	vol.On("WriteMetadata", "LABEL", BackupManifestFile).Return(&NopCloser{bytes.NewBufferString("")}, nil)
 	allImages := append(backupInfo.BaseImages, "testserver:5000/BASE/repo:tag")
 	s.docker.On("SaveImages", allImages, mock.AnythingOfType("*io.PipeWriter")).Return(nil).Run(func(a mock.Arguments) {
 		writer := a.Get(1).(io.Writer)
@@ -190,7 +195,7 @@ func (s *DFSTestSuite) TestBackup(c *C) {
 		tarwriter.Write(data)
 		tarwriter.Close()
 	})
-	err = s.dfs.Backup(backupInfo, buf)
+	err = s.dfs.Backup(backupInfo, nil, buf)
 	c.Assert(err, IsNil)
 	c.Assert(buf.Len() > 0, Equals, true)
 }
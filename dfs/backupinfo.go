@@ -17,6 +17,7 @@ import (
 	"archive/tar"
 	"encoding/json"
 	"io"
+	"io/ioutil"
 	"os/exec"
 
 	"github.com/zenoss/glog"
@@ -46,9 +47,31 @@ func (dfs *DistributedFilesystem) BackupInfo(r io.Reader) (*BackupInfo, error) {
 
 // ExtractBackupInfo extracts the backup metadata from a tarball on disk in as
 // cheaply a manner as possible. The serialized BackupInfo is stored at the
-// front of the tarball to facilitate this.
-func ExtractBackupInfo(filename string) (*BackupInfo, error) {
+// front of the tarball to facilitate this.  Encrypted backups are decrypted
+// with the passphrase.
+func ExtractBackupInfo(filename string, passphrase []byte) (*BackupInfo, error) {
 	var info BackupInfo
+	if encrypted, err := IsEncryptedBackupFile(filename); err != nil {
+		return nil, ErrRestoreNoInfo
+	} else if encrypted {
+		r, err := OpenBackupFile(filename, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		header, err := tar.NewReader(r).Next()
+		if err != nil || header.Name != BackupMetadataFile {
+			return nil, ErrRestoreNoInfo
+		}
+		data, err := ioutil.ReadAll(io.LimitReader(r, header.Size))
+		if err != nil {
+			return nil, ErrRestoreNoInfo
+		}
+		if err := json.Unmarshal(data, &info); err != nil {
+			return nil, ErrRestoreNoInfo
+		}
+		return &info, nil
+	}
 	data, err := exec.Command("tar", "-O", "--occurrence", "-xzf", filename, BackupMetadataFile).CombinedOutput()
 	if err != nil {
 		return nil, ErrRestoreNoInfo
@@ -0,0 +1,234 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import (
+	"archive/tar"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// BackupChecksumsFile is the last entry of a backup, which holds the
+// checksums of all the other entries
+const BackupChecksumsFile = ".CHECKSUMS"
+
+// backupSignatureSalt derives the signing key of backups from the backup
+// passphrase
+const backupSignatureSalt = "serviced-backup-signature"
+
+var (
+	ErrBackupTampered = errors.New("backup does not match its signed checksums")
+	ErrBackupUnsigned = errors.New("backup is not signed")
+	ErrBackupDupEntry = errors.New("backup has more than one entry with the same name")
+)
+
+// BackupChecksums is the manifest of the entries of a backup
+type BackupChecksums struct {
+	Entries   map[string]string // sha256 of each entry, by name
+	Signature string            // hmac-sha256 of the entries, if the backup is signed
+}
+
+// BackupVerification describes a backup that passed verification
+type BackupVerification struct {
+	Entries   int
+	Signed    bool
+	Encrypted bool
+}
+
+// entryChecksum returns the checksum of a tar entry, which covers the
+// header fields that are restored as well as the content
+func entryChecksum(header *tar.Header, r io.Reader) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%c\x00%o\x00%s\x00%d\x00", header.Name, header.Typeflag, header.Mode, header.Linkname, header.Size)
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// sign returns the signature of the entries under the passphrase
+func (c *BackupChecksums) sign(passphrase []byte) (string, error) {
+	data, err := json.Marshal(c.Entries)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, deriveBackupKey(passphrase, []byte(backupSignatureSalt)))
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// checksumTar reads a backup tar stream to the end and returns the checksums
+// of its entries, along with the checksums recorded in the backup, if any.
+// Names are unique within a backup, since a later entry would silently
+// replace the earlier one on restore.
+func checksumTar(r io.Reader) (map[string]string, *BackupChecksums, error) {
+	sums := make(map[string]string)
+	var recorded *BackupChecksums
+	tarReader := tar.NewReader(r)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+		if _, ok := sums[header.Name]; ok || (header.Name == BackupChecksumsFile && recorded != nil) {
+			return nil, nil, ErrBackupDupEntry
+		}
+		if header.Name == BackupChecksumsFile {
+			recorded = &BackupChecksums{}
+			if err := json.NewDecoder(tarReader).Decode(recorded); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		if sums[header.Name], err = entryChecksum(header, tarReader); err != nil {
+			return nil, nil, err
+		}
+	}
+	// consume the end of the archive
+	io.Copy(ioutil.Discard, r)
+	return sums, recorded, nil
+}
+
+type checksumResult struct {
+	sums     map[string]string
+	recorded *BackupChecksums
+	err      error
+}
+
+// checksumWriter computes the checksums of the tar stream written to it.
+// Anything written after it is closed is ignored.
+type checksumWriter struct {
+	w      *io.PipeWriter
+	result <-chan checksumResult
+	final  *checksumResult
+}
+
+func newChecksumWriter() *checksumWriter {
+	r, w := io.Pipe()
+	result := make(chan checksumResult, 1)
+	go func() {
+		sums, recorded, err := checksumTar(r)
+		r.CloseWithError(err)
+		result <- checksumResult{sums: sums, recorded: recorded, err: err}
+	}()
+	return &checksumWriter{w: w, result: result}
+}
+
+// Write implements io.Writer
+func (c *checksumWriter) Write(p []byte) (int, error) {
+	if c.final != nil {
+		return len(p), nil
+	}
+	return c.w.Write(p)
+}
+
+// Close ends the stream and returns the checksums of its entries
+func (c *checksumWriter) Close() checksumResult {
+	if c.final == nil {
+		c.w.Close()
+		result := <-c.result
+		c.final = &result
+	}
+	return *c.final
+}
+
+// writeChecksums writes the checksums of a backup as its last entry, signed
+// if there is a passphrase
+func writeChecksums(w *tar.Writer, sums map[string]string, passphrase []byte) error {
+	checksums := BackupChecksums{Entries: sums}
+	if len(passphrase) > 0 {
+		var err error
+		if checksums.Signature, err = checksums.sign(passphrase); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(checksums)
+	if err != nil {
+		return err
+	}
+	if err := w.WriteHeader(&tar.Header{Name: BackupChecksumsFile, Size: int64(len(data)), Mode: 0644}); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// verifyChecksums compares the checksums of a backup with the checksums it
+// recorded.  With a passphrase, the recorded checksums must also be signed
+// with it.  Backups taken before checksums were recorded are accepted only
+// without a passphrase.
+func verifyChecksums(result checksumResult, passphrase []byte) (*BackupVerification, error) {
+	if result.err != nil {
+		return nil, result.err
+	}
+	verification := &BackupVerification{Entries: len(result.sums)}
+	recorded := result.recorded
+	if recorded == nil {
+		if len(passphrase) > 0 {
+			return nil, ErrBackupUnsigned
+		}
+		plog.Warn("Backup has no checksums and cannot be verified")
+		return verification, nil
+	}
+	if len(passphrase) > 0 {
+		if recorded.Signature == "" {
+			return nil, ErrBackupUnsigned
+		}
+		signature, err := recorded.sign(passphrase)
+		if err != nil {
+			return nil, err
+		}
+		if !hmac.Equal([]byte(signature), []byte(recorded.Signature)) {
+			return nil, ErrBackupTampered
+		}
+		verification.Signed = true
+	} else if recorded.Signature != "" {
+		plog.Warn("Backup is signed, but no backup key is configured to check the signature")
+	}
+	if len(recorded.Entries) != len(result.sums) {
+		return nil, ErrBackupTampered
+	}
+	for name, sum := range result.sums {
+		if recorded.Entries[name] != sum {
+			plog.WithField("entry", name).Warn("Backup entry does not match its checksum")
+			return nil, ErrBackupTampered
+		}
+	}
+	return verification, nil
+}
+
+// VerifyBackup checks the entries of a compressed backup stream against the
+// checksums recorded in the backup, without restoring anything.
+func (dfs *DistributedFilesystem) VerifyBackup(r io.Reader) (*BackupVerification, error) {
+	backup, err := OpenBackup(ioutil.NopCloser(r), dfs.backupKey)
+	if err != nil {
+		return nil, err
+	}
+	defer backup.Close()
+	sums, recorded, err := checksumTar(backup)
+	verification, err := verifyChecksums(checksumResult{sums: sums, recorded: recorded, err: err}, dfs.backupKey)
+	if err != nil {
+		return nil, err
+	}
+	verification.Encrypted = backup.Encrypted
+	return verification, nil
+}
@@ -0,0 +1,190 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package dfs_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/domain/servicetemplate"
+	. "gopkg.in/check.v1"
+)
+
+// signedTestBackup takes a backup without any snapshots or images
+func (s *DFSTestSuite) signedTestBackup(c *C, key []byte) *bytes.Buffer {
+	backupInfo := BackupInfo{
+		Templates:     []servicetemplate.ServiceTemplate{{ID: "test-template-1"}},
+		Timestamp:     time.Now().UTC(),
+		BackupVersion: 1,
+	}
+	buf := bytes.NewBufferString("")
+	s.dfs.SetBackupKey(key)
+	err := s.dfs.Backup(backupInfo, nil, buf)
+	c.Assert(err, IsNil)
+	return buf
+}
+
+// writeTestBackupFile compresses a backup into a file
+func writeTestBackupFile(c *C, dir string, data []byte) string {
+	filename := filepath.Join(dir, "backup.tgz")
+	fh, err := os.Create(filename)
+	c.Assert(err, IsNil)
+	defer fh.Close()
+	gz := gzip.NewWriter(fh)
+	_, err = gz.Write(data)
+	c.Assert(err, IsNil)
+	c.Assert(gz.Close(), IsNil)
+	return filename
+}
+
+// verifyTestBackupFile verifies a backup file
+func (s *DFSTestSuite) verifyTestBackupFile(c *C, filename string) (*BackupVerification, error) {
+	fh, err := os.Open(filename)
+	c.Assert(err, IsNil)
+	defer fh.Close()
+	return s.dfs.VerifyBackup(fh)
+}
+
+func (s *DFSTestSuite) TestVerifyBackup_Signed(c *C) {
+	buf := s.signedTestBackup(c, []byte("secret"))
+	filename := writeTestBackupFile(c, c.MkDir(), buf.Bytes())
+
+	verification, err := s.verifyTestBackupFile(c, filename)
+	c.Assert(err, IsNil)
+	c.Check(verification, DeepEquals, &BackupVerification{Entries: 1, Signed: true})
+
+	// a signed backup can still be checked without the key
+	s.dfs.SetBackupKey(nil)
+	verification, err = s.verifyTestBackupFile(c, filename)
+	c.Assert(err, IsNil)
+	c.Check(verification, DeepEquals, &BackupVerification{Entries: 1})
+
+	// but not with the wrong key
+	s.dfs.SetBackupKey([]byte("other"))
+	_, err = s.verifyTestBackupFile(c, filename)
+	c.Assert(err, Equals, ErrBackupTampered)
+}
+
+func (s *DFSTestSuite) TestVerifyBackup_Tampered(c *C) {
+	buf := s.signedTestBackup(c, []byte("secret"))
+	data := bytes.Replace(buf.Bytes(), []byte("test-template-1"), []byte("test-template-2"), 1)
+	filename := writeTestBackupFile(c, c.MkDir(), data)
+
+	_, err := s.verifyTestBackupFile(c, filename)
+	c.Assert(err, Equals, ErrBackupTampered)
+}
+
+func (s *DFSTestSuite) TestVerifyBackup_Unsigned(c *C) {
+	buf := s.signedTestBackup(c, nil)
+	filename := writeTestBackupFile(c, c.MkDir(), buf.Bytes())
+
+	verification, err := s.verifyTestBackupFile(c, filename)
+	c.Assert(err, IsNil)
+	c.Check(verification, DeepEquals, &BackupVerification{Entries: 1})
+
+	s.dfs.SetBackupKey([]byte("secret"))
+	_, err = s.verifyTestBackupFile(c, filename)
+	c.Assert(err, Equals, ErrBackupUnsigned)
+}
+
+func (s *DFSTestSuite) TestVerifyBackup_DuplicateEntry(c *C) {
+	buf := s.signedTestBackup(c, []byte("secret"))
+
+	// repeat each entry, which leaves the checksums of the names unchanged
+	dup := bytes.NewBufferString("")
+	tr, tw := tar.NewReader(buf), tar.NewWriter(dup)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, IsNil)
+		data, err := ioutil.ReadAll(tr)
+		c.Assert(err, IsNil)
+		copies := 2
+		if hdr.Name == BackupChecksumsFile {
+			copies = 1
+		}
+		for i := 0; i < copies; i++ {
+			c.Assert(tw.WriteHeader(hdr), IsNil)
+			_, err = tw.Write(data)
+			c.Assert(err, IsNil)
+		}
+	}
+	c.Assert(tw.Close(), IsNil)
+	filename := writeTestBackupFile(c, c.MkDir(), dup.Bytes())
+
+	_, err := s.verifyTestBackupFile(c, filename)
+	c.Assert(err, Equals, ErrBackupDupEntry)
+}
+
+func (s *DFSTestSuite) TestRestore_Tampered(c *C) {
+	buf := s.signedTestBackup(c, []byte("secret"))
+	data := bytes.Replace(buf.Bytes(), []byte("test-template-1"), []byte("test-template-2"), 1)
+
+	err := s.dfs.Restore(bytes.NewBuffer(data), 1)
+	c.Assert(err, Equals, ErrBackupTampered)
+
+	err = s.dfs.Restore(buf, 1)
+	c.Assert(err, IsNil)
+}
+
+func (s *DFSTestSuite) TestRestore_UnsignedWithKey(c *C) {
+	buf := s.signedTestBackup(c, nil)
+	s.dfs.SetBackupKey([]byte("secret"))
+
+	err := s.dfs.Restore(buf, 1)
+	c.Assert(err, Equals, ErrBackupUnsigned)
+
+	err = s.dfs.Restore(bytes.NewBufferString(""), 0)
+	c.Assert(err, Equals, ErrBackupUnsigned)
+}
+
+func (s *DFSTestSuite) TestExtractBackupInfo_Encrypted(c *C) {
+	buf := s.signedTestBackup(c, []byte("secret"))
+	compressed := bytes.NewBufferString("")
+	gz := gzip.NewWriter(compressed)
+	_, err := gz.Write(buf.Bytes())
+	c.Assert(err, IsNil)
+	c.Assert(gz.Close(), IsNil)
+
+	encrypted := bytes.NewBufferString("")
+	w, err := NewBackupEncrypter(encrypted, []byte("secret"))
+	c.Assert(err, IsNil)
+	_, err = w.Write(compressed.Bytes())
+	c.Assert(err, IsNil)
+	c.Assert(w.Close(), IsNil)
+	filename := filepath.Join(c.MkDir(), "backup.tgz")
+	c.Assert(ioutil.WriteFile(filename, encrypted.Bytes(), 0644), IsNil)
+
+	info, err := ExtractBackupInfo(filename, []byte("secret"))
+	c.Assert(err, IsNil)
+	c.Check(info.Templates[0].ID, Equals, "test-template-1")
+
+	_, err = ExtractBackupInfo(filename, nil)
+	c.Assert(err, Equals, ErrBackupEncrypted)
+
+	verification, err := s.verifyTestBackupFile(c, filename)
+	c.Assert(err, IsNil)
+	c.Check(verification, DeepEquals, &BackupVerification{Entries: 1, Signed: true, Encrypted: true})
+}
@@ -43,6 +43,9 @@ type DFS interface {
 	Destroy(tenantID string) error
 	// Download adds an image for an application into the registry
 	Download(image, tenantID string, upgrade bool) (registry string, err error)
+	// VerifyImage checks that an image can be downloaded, without adding it
+	// to the registry
+	VerifyImage(image string) error
 	// Commit uploads a new image into the registry
 	Commit(ctrID string) (tenantID string, err error)
 	// Snapshot captures application data at a specific point in time
@@ -55,10 +58,16 @@ type DFS interface {
 	List(tenantID string) (snapshots []string, err error)
 	// Info provides detailed info for a particular snapshot
 	Info(snapshotID string) (*SnapshotInfo, error)
-	// Backup saves and exports the current state of the system
-	Backup(info BackupInfo, w io.Writer) error
+	// Backup saves and exports the current state of the system.  If parent
+	// is set, only the changes since the parent backup are exported.
+	Backup(info BackupInfo, parent *BackupInfo, w io.Writer) error
 	// Restore restores the system to the state of the backup
 	Restore(r io.Reader, version int) error
+	// RestoreChain restores the system to the state of the last backup in a
+	// chain of full and incremental backups
+	RestoreChain(chain []ChainedBackup) error
+	// VerifyBackup checks a compressed backup against its recorded checksums
+	VerifyBackup(r io.Reader) (*BackupVerification, error)
 	// BackupInfo provides detailed info for a particular backup
 	BackupInfo(r io.Reader) (*BackupInfo, error)
 	// Tag adds a tag to an existing snapshot
@@ -93,6 +102,8 @@ type BackupInfo struct {
 	SnapshotExcludes map[string][]string
 	Timestamp        time.Time
 	BackupVersion    int
+	Parent           string            // file name of the parent of an incremental backup
+	ImageIDs         map[string]string // docker image ids of the backed up images
 }
 
 // SnapshotInfo provides meta info about a snapshot
@@ -111,10 +122,11 @@ type DistributedFilesystem struct {
 	disk   volume.Driver
 	// FIXME: replace this with a NFS server, instead of restarting the
 	// daemon
-	net     storage.StorageDriver
-	timeout time.Duration
-	locker  *csync.TimedMutex
-	tmp     string // tmp directory where backups are temporarily spooled
+	net       storage.StorageDriver
+	timeout   time.Duration
+	locker    *csync.TimedMutex
+	tmp       string // tmp directory where backups are temporarily spooled
+	backupKey []byte // passphrase that signs backups
 }
 
 // ImageInfo provides meta info about a Docker image
@@ -141,6 +153,12 @@ func (dfs *DistributedFilesystem) Timeout() time.Duration {
 	return dfs.timeout
 }
 
+// SetBackupKey sets the passphrase that signs backups and verifies them on
+// restore
+func (dfs *DistributedFilesystem) SetBackupKey(key []byte) {
+	dfs.backupKey = key
+}
+
 // SetTmp sets the temp directory for the spooler
 func (dfs *DistributedFilesystem) SetTmp(tmp string) {
 	dfs.tmp = tmp
@@ -84,6 +84,17 @@ func (dfs *DistributedFilesystem) Download(image, tenantID string, upgrade bool)
 	return rImage, nil
 }
 
+// VerifyImage makes sure the image is available to the master, pulling it if
+// it isn't found locally, without adding it to the registry.
+func (dfs *DistributedFilesystem) VerifyImage(image string) error {
+	if _, err := commons.ParseImageID(image); err != nil {
+		glog.Errorf("Could not parse image %s: %s", image, err)
+		return err
+	}
+	_, err := dfs.pullImage(image)
+	return err
+}
+
 // findImage will verify whether the image has already been deployed with the
 // application.
 func (dfs *DistributedFilesystem) findImage(image, tenantID string) (string, error) {
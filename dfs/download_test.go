@@ -20,6 +20,7 @@ import (
 	index "github.com/control-center/serviced/dfs/registry"
 	"github.com/control-center/serviced/domain/registry"
 	dockerclient "github.com/fsouza/go-dockerclient"
+	"github.com/stretchr/testify/mock"
 	. "gopkg.in/check.v1"
 )
 
@@ -203,3 +204,14 @@ func (s *DFSTestSuite) TestDownload_NoHash(c *C) {
 	c.Assert(img, Equals, "")
 	c.Assert(err, Equals, ErrTestNoHash)
 }
+
+func (s *DFSTestSuite) TestVerifyImage(c *C) {
+	image := &dockerclient.Image{ID: "testimage"}
+	s.docker.On("FindImage", "library/repo:tag").Return(image, nil)
+	c.Assert(s.dfs.VerifyImage("library/repo:tag"), IsNil)
+	s.docker.On("FindImage", "library/repo2:tag").Return(nil, dockerclient.ErrNoSuchImage)
+	s.docker.On("PullImage", "library/repo2:tag").Return(ErrTestNoPull)
+	c.Assert(s.dfs.VerifyImage("library/repo2:tag"), Equals, ErrTestNoPull)
+	c.Assert(s.dfs.VerifyImage("library/repo:tag:bad"), NotNil)
+	s.index.AssertNotCalled(c, "PushImage", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,303 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+
+	gzip "github.com/klauspost/pgzip"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// backupCryptMagic starts every encrypted backup
+	backupCryptMagic = "SVCDBAK1"
+
+	// backupCryptSaltSize is the size of the random salt that follows the
+	// magic of an encrypted backup
+	backupCryptSaltSize = 16
+
+	// backupCryptChunkSize is the most plain text sealed in one chunk
+	backupCryptChunkSize = 64 * 1024
+
+	// backupCryptFinal flags the length of the last chunk of a backup
+	backupCryptFinal = 1 << 31
+
+	// backupKeyIterations is the number of PBKDF2 iterations used to derive
+	// keys from the backup passphrase
+	backupKeyIterations = 100000
+)
+
+var (
+	ErrEmptyBackupKey  = errors.New("backup key file is empty")
+	ErrBackupEncrypted = errors.New("backup is encrypted and no backup key is configured")
+	ErrBackupDecrypt   = errors.New("could not decrypt backup; the backup is corrupt or the key is wrong")
+)
+
+// LoadBackupKey reads the passphrase used to encrypt and sign backups from a
+// file.  Leading and trailing white space is ignored.
+func LoadBackupKey(filename string) ([]byte, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	key := bytes.TrimSpace(data)
+	if len(key) == 0 {
+		return nil, ErrEmptyBackupKey
+	}
+	return key, nil
+}
+
+// deriveBackupKey derives a 256-bit key from the passphrase with PBKDF2 and
+// HMAC-SHA256
+func deriveBackupKey(passphrase, salt []byte) []byte {
+	return pbkdf2.Key(passphrase, salt, backupKeyIterations, 32, sha256.New)
+}
+
+// chunkNonce returns the nonce of a chunk, which binds its position in the
+// stream and whether it is the last chunk
+func chunkNonce(counter uint64, final bool) []byte {
+	nonce := make([]byte, 12)
+	binary.BigEndian.PutUint64(nonce, counter)
+	if final {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+// backupEncrypter seals a stream into authenticated chunks
+type backupEncrypter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	buf     []byte
+	counter uint64
+	closed  bool
+}
+
+// NewBackupEncrypter returns a writer that encrypts a backup with AES-GCM
+// under a key derived from the passphrase.  The writer must be closed to
+// finish the backup.
+func NewBackupEncrypter(w io.Writer, passphrase []byte) (io.WriteCloser, error) {
+	salt := make([]byte, backupCryptSaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	aead, err := newBackupAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(append([]byte(backupCryptMagic), salt...)); err != nil {
+		return nil, err
+	}
+	return &backupEncrypter{w: w, aead: aead, buf: make([]byte, 0, backupCryptChunkSize)}, nil
+}
+
+func newBackupAEAD(passphrase, salt []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(deriveBackupKey(passphrase, salt))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Write implements io.Writer
+func (e *backupEncrypter) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		if len(e.buf) == backupCryptChunkSize {
+			if err := e.seal(false); err != nil {
+				return n, err
+			}
+		}
+		size := backupCryptChunkSize - len(e.buf)
+		if size > len(p) {
+			size = len(p)
+		}
+		e.buf = append(e.buf, p[:size]...)
+		p = p[size:]
+		n += size
+	}
+	return n, nil
+}
+
+// Close writes the last chunk of the backup
+func (e *backupEncrypter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.seal(true)
+}
+
+func (e *backupEncrypter) seal(final bool) error {
+	sealed := e.aead.Seal(nil, chunkNonce(e.counter, final), e.buf, nil)
+	length := uint32(len(sealed))
+	if final {
+		length |= backupCryptFinal
+	}
+	if err := binary.Write(e.w, binary.BigEndian, length); err != nil {
+		return err
+	}
+	if _, err := e.w.Write(sealed); err != nil {
+		return err
+	}
+	e.counter++
+	e.buf = e.buf[:0]
+	return nil
+}
+
+// backupDecrypter opens the authenticated chunks of an encrypted backup
+type backupDecrypter struct {
+	r       io.Reader
+	aead    cipher.AEAD
+	buf     []byte
+	counter uint64
+	done    bool
+}
+
+// NewBackupDecrypter returns a reader of the plain text of an encrypted
+// backup.  A backup that has been altered or truncated returns
+// ErrBackupDecrypt.
+func NewBackupDecrypter(r io.Reader, passphrase []byte) (io.Reader, error) {
+	header := make([]byte, len(backupCryptMagic)+backupCryptSaltSize)
+	if _, err := io.ReadFull(r, header); err != nil || string(header[:len(backupCryptMagic)]) != backupCryptMagic {
+		return nil, ErrBackupDecrypt
+	}
+	aead, err := newBackupAEAD(passphrase, header[len(backupCryptMagic):])
+	if err != nil {
+		return nil, err
+	}
+	return &backupDecrypter{r: r, aead: aead}, nil
+}
+
+// Read implements io.Reader
+func (d *backupDecrypter) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+func (d *backupDecrypter) open() error {
+	var length uint32
+	if err := binary.Read(d.r, binary.BigEndian, &length); err != nil {
+		return ErrBackupDecrypt
+	}
+	final := length&backupCryptFinal != 0
+	length &^= backupCryptFinal
+	if length > backupCryptChunkSize+uint32(d.aead.Overhead()) {
+		return ErrBackupDecrypt
+	}
+	sealed := make([]byte, length)
+	if _, err := io.ReadFull(d.r, sealed); err != nil {
+		return ErrBackupDecrypt
+	}
+	plain, err := d.aead.Open(sealed[:0], chunkNonce(d.counter, final), sealed, nil)
+	if err != nil {
+		return ErrBackupDecrypt
+	}
+	if final {
+		// nothing may follow the last chunk
+		if n, _ := d.r.Read(make([]byte, 1)); n > 0 {
+			return ErrBackupDecrypt
+		}
+		d.done = true
+	}
+	d.counter++
+	d.buf = plain
+	return nil
+}
+
+// IsEncryptedBackup returns true if the stream starts like an encrypted
+// backup
+func IsEncryptedBackup(r *bufio.Reader) bool {
+	magic, err := r.Peek(len(backupCryptMagic))
+	return err == nil && string(magic) == backupCryptMagic
+}
+
+// IsEncryptedBackupFile returns true if the file is an encrypted backup
+func IsEncryptedBackupFile(filename string) (bool, error) {
+	fh, err := os.Open(filename)
+	if err != nil {
+		return false, err
+	}
+	defer fh.Close()
+	return IsEncryptedBackup(bufio.NewReader(fh)), nil
+}
+
+// BackupReader reads the tar stream of a compressed backup
+type BackupReader struct {
+	io.Reader
+	Encrypted bool // whether the backup was decrypted
+	gz        *gzip.Reader
+	closer    io.Closer
+}
+
+// Close closes the decompressor and the underlying stream
+func (r *BackupReader) Close() error {
+	r.gz.Close()
+	return r.closer.Close()
+}
+
+// OpenBackup returns the tar stream of a compressed backup, which is
+// decrypted with the passphrase if the backup is encrypted.  The backup
+// stream is closed with the reader, or if the backup cannot be read.
+func OpenBackup(rc io.ReadCloser, passphrase []byte) (*BackupReader, error) {
+	br := bufio.NewReader(rc)
+	var r io.Reader = br
+	encrypted := IsEncryptedBackup(br)
+	if encrypted {
+		if len(passphrase) == 0 {
+			rc.Close()
+			return nil, ErrBackupEncrypted
+		}
+		var err error
+		if r, err = NewBackupDecrypter(br, passphrase); err != nil {
+			rc.Close()
+			return nil, err
+		}
+	}
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+	return &BackupReader{Reader: gz, Encrypted: encrypted, gz: gz, closer: rc}, nil
+}
+
+// OpenBackupFile opens the tar stream of a compressed backup file
+func OpenBackupFile(filename string, passphrase []byte) (*BackupReader, error) {
+	fh, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	return OpenBackup(fh, passphrase)
+}
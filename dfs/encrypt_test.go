@@ -0,0 +1,101 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package dfs_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+
+	. "github.com/control-center/serviced/dfs"
+	. "gopkg.in/check.v1"
+)
+
+// encryptTestData encrypts data with the passphrase
+func encryptTestData(c *C, data, passphrase []byte) []byte {
+	buf := bytes.NewBufferString("")
+	w, err := NewBackupEncrypter(buf, passphrase)
+	c.Assert(err, IsNil)
+	_, err = w.Write(data)
+	c.Assert(err, IsNil)
+	c.Assert(w.Close(), IsNil)
+	return buf.Bytes()
+}
+
+// decryptTestData decrypts data with the passphrase
+func decryptTestData(data, passphrase []byte) ([]byte, error) {
+	r, err := NewBackupDecrypter(bytes.NewBuffer(data), passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(r)
+}
+
+func (s *DFSTestSuite) TestBackupEncrypter_RoundTrip(c *C) {
+	data := bytes.Repeat([]byte("backup data "), 20000)
+	encrypted := encryptTestData(c, data, []byte("secret"))
+	c.Check(bytes.Contains(encrypted, []byte("backup data")), Equals, false)
+
+	actual, err := decryptTestData(encrypted, []byte("secret"))
+	c.Assert(err, IsNil)
+	c.Check(bytes.Equal(actual, data), Equals, true)
+
+	// empty streams still have a final chunk
+	encrypted = encryptTestData(c, nil, []byte("secret"))
+	actual, err = decryptTestData(encrypted, []byte("secret"))
+	c.Assert(err, IsNil)
+	c.Check(actual, HasLen, 0)
+}
+
+func (s *DFSTestSuite) TestBackupDecrypter_Invalid(c *C) {
+	data := bytes.Repeat([]byte("backup data "), 20000)
+	encrypted := encryptTestData(c, data, []byte("secret"))
+
+	// wrong key
+	_, err := decryptTestData(encrypted, []byte("other"))
+	c.Check(err, Equals, ErrBackupDecrypt)
+
+	// altered
+	altered := append([]byte{}, encrypted...)
+	altered[len(altered)/2] ^= 0xff
+	_, err = decryptTestData(altered, []byte("secret"))
+	c.Check(err, Equals, ErrBackupDecrypt)
+
+	// truncated
+	_, err = decryptTestData(encrypted[:len(encrypted)/2], []byte("secret"))
+	c.Check(err, Equals, ErrBackupDecrypt)
+
+	// extra data after the final chunk
+	_, err = decryptTestData(append(encrypted, 0), []byte("secret"))
+	c.Check(err, Equals, ErrBackupDecrypt)
+
+	// not an encrypted backup
+	_, err = decryptTestData(data, []byte("secret"))
+	c.Check(err, Equals, ErrBackupDecrypt)
+}
+
+func (s *DFSTestSuite) TestLoadBackupKey(c *C) {
+	dir := c.MkDir()
+	filename := filepath.Join(dir, "key")
+	c.Assert(ioutil.WriteFile(filename, []byte("  secret\n"), 0600), IsNil)
+	key, err := LoadBackupKey(filename)
+	c.Assert(err, IsNil)
+	c.Check(string(key), Equals, "secret")
+
+	c.Assert(ioutil.WriteFile(filename, []byte("\n"), 0600), IsNil)
+	_, err = LoadBackupKey(filename)
+	c.Check(err, Equals, ErrEmptyBackupKey)
+}
@@ -0,0 +1,515 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfs
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/volume"
+)
+
+const (
+	// BackupManifestFile is the snapshot metadata file that describes the
+	// volume data of the snapshot when it was last backed up
+	BackupManifestFile = ".BACKUPMANIFEST"
+
+	// BackupManifestSuffix is appended to the snapshot directory of a backup
+	// to name the manifest of the snapshot
+	BackupManifestSuffix = ".manifest"
+
+	// IncrementalBackupVersion is the version of a backup that only contains
+	// the changes since its parent backup
+	IncrementalBackupVersion = 2
+
+	// volumeDirSuffix ends the top level directory of a snapshot export that
+	// holds the volume data
+	volumeDirSuffix = "-volume"
+)
+
+var (
+	ErrNoParentSnapshot   = errors.New("snapshot of the parent backup is no longer available; take a full backup")
+	ErrIncrementalRestore = errors.New("incremental backup must be restored with its parent backups")
+	ErrInvalidBackupChain = errors.New("backup chain must be a full backup followed by incremental backups")
+)
+
+// ManifestEntry stamps a path in the volume of a snapshot
+type ManifestEntry struct {
+	Type     byte
+	Size     int64
+	Mode     int64
+	ModTime  time.Time
+	Linkname string
+	Included bool // whether the data of the path is in the backup
+}
+
+func newManifestEntry(header *tar.Header) ManifestEntry {
+	return ManifestEntry{
+		Type:     header.Typeflag,
+		Size:     header.Size,
+		Mode:     header.Mode,
+		ModTime:  header.ModTime,
+		Linkname: header.Linkname,
+	}
+}
+
+// Manifest describes the volume data of a snapshot by path
+type Manifest map[string]ManifestEntry
+
+// remove deletes a path and everything under it from the manifest
+func (m Manifest) remove(p string) {
+	delete(m, p)
+	prefix := strings.TrimSuffix(p, "/") + "/"
+	for name := range m {
+		if strings.HasPrefix(name, prefix) {
+			delete(m, name)
+		}
+	}
+}
+
+// ChainedBackup is a backup in a chain of a full backup and the incremental
+// backups taken on top of it
+type ChainedBackup struct {
+	Info *BackupInfo
+	Open func() (io.ReadCloser, error)
+}
+
+// volumePath returns the path of an entry of a snapshot export relative to
+// the root of the volume, and the top level directory of the entry.  If the
+// entry is not volume data, the path is empty.
+func volumePath(name string) (string, string) {
+	parts := strings.SplitN(name, "/", 2)
+	if !strings.HasSuffix(parts[0], volumeDirSuffix) {
+		return "", parts[0]
+	} else if len(parts) == 1 {
+		return "/", parts[0]
+	}
+	return path.Clean("/" + parts[1]), parts[0]
+}
+
+// rewriteSnapshotTar is rewriteTar for the export of a snapshot that also
+// builds the manifest of the snapshot's volume.  For an export of the changes
+// since a parent snapshot, the paths that are not in the export keep their
+// entries in the parent manifest, and the paths whited out by the export are
+// removed.
+func rewriteSnapshotTar(prefix string, tarWriter *tar.Writer, r *io.PipeReader, parent Manifest) (Manifest, error) {
+	defer r.Close()
+	tarReader := tar.NewReader(r)
+	manifest := make(Manifest)
+	for p, entry := range parent {
+		entry.Included = false
+		manifest[p] = entry
+	}
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if p, _ := volumePath(header.Name); p != "" {
+			if base := path.Base(p); strings.HasPrefix(base, volume.WhiteoutPrefix) {
+				manifest.remove(path.Join(path.Dir(p), strings.TrimPrefix(base, volume.WhiteoutPrefix)))
+				continue
+			}
+			entry := newManifestEntry(header)
+			entry.Included = true
+			if previous, ok := manifest[p]; ok && previous.Type == tar.TypeDir && entry.Type != tar.TypeDir {
+				// a directory was replaced, so its contents are gone
+				manifest.remove(p)
+			}
+			manifest[p] = entry
+		}
+
+		header.Name = filepath.Join(prefix, header.Name)
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(tarWriter, tarReader); err != nil {
+			return nil, err
+		}
+	}
+
+	return manifest, nil
+}
+
+// writeManifest writes the manifest of a snapshot into a backup
+func writeManifest(w *tar.Writer, name string, manifest Manifest) error {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	if err := w.WriteHeader(&tar.Header{Name: name, Size: int64(len(data)), Mode: 0644}); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// parentManifest returns the tenant's snapshot in the parent backup and its
+// manifest, or nothing if the tenant was not part of the parent backup.
+func (dfs *DistributedFilesystem) parentManifest(vol volume.Volume, tenantID string, parent *BackupInfo) (string, Manifest, error) {
+	for _, snapshot := range parent.Snapshots {
+		if !strings.HasPrefix(snapshot, tenantID+"_") {
+			continue
+		}
+		r, err := vol.ReadMetadata(snapshot, BackupManifestFile)
+		if err != nil {
+			plog.WithError(err).WithField("snapshot", snapshot).Debug("Could not read manifest of parent backup snapshot")
+			return "", nil, ErrNoParentSnapshot
+		}
+		var manifest Manifest
+		if err := importJSON(r, &manifest); err != nil {
+			return "", nil, err
+		}
+		return snapshot, manifest, nil
+	}
+	return "", nil, nil
+}
+
+// changedImages looks up the ids of the images and returns the ones that
+// have changed since the parent backup, if any.
+func (dfs *DistributedFilesystem) changedImages(images []string, parent *BackupInfo) ([]string, map[string]string, error) {
+	var changed []string
+	ids := make(map[string]string)
+	for _, image := range images {
+		img, err := dfs.docker.FindImage(image)
+		if err != nil {
+			plog.WithError(err).WithField("image", image).Error("Could not find image for backup")
+			return nil, nil, err
+		}
+		ids[image] = img.ID
+		if parent == nil || parent.ImageIDs[image] != img.ID {
+			changed = append(changed, image)
+		}
+	}
+	return changed, ids, nil
+}
+
+// chainedTenant tracks the restore of a tenant's volume from a backup chain
+type chainedTenant struct {
+	label     string
+	volumeDir string
+	settings  []chainedEntry
+	stream    *chainedStream
+}
+
+// chainedEntry is a snapshot export entry held in memory
+type chainedEntry struct {
+	header *tar.Header
+	data   []byte
+}
+
+// chainedStream is a pipe to a snapshot import or image load
+type chainedStream struct {
+	tarwriter *tar.Writer
+	writer    *io.PipeWriter
+	errc      <-chan error
+}
+
+func newChainedStream(writer *io.PipeWriter, errc <-chan error) *chainedStream {
+	return &chainedStream{tarwriter: tar.NewWriter(writer), writer: writer, errc: errc}
+}
+
+// write copies a tar entry into the stream
+func (s *chainedStream) write(header *tar.Header, r io.Reader) error {
+	if err := s.tarwriter.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := io.Copy(s.tarwriter, r)
+	return err
+}
+
+// close finishes the stream and waits for its result
+func (s *chainedStream) close() error {
+	s.tarwriter.Close()
+	s.writer.Close()
+	return <-s.errc
+}
+
+// abort stops the stream with an error and waits for it to exit
+func (s *chainedStream) abort(err error) {
+	s.writer.CloseWithError(err)
+	<-s.errc
+}
+
+// RestoreChain restores application data from a chain of backups, ordered
+// from the full backup to the most recent incremental backup.  The volume of
+// each tenant is imported as the snapshot of the most recent backup, with the
+// data of each path taken from the latest backup that contains it.
+func (dfs *DistributedFilesystem) RestoreChain(chain []ChainedBackup) error {
+	if len(chain) == 0 || chain[0].Info.BackupVersion != 1 {
+		return ErrInvalidBackupChain
+	}
+	for _, b := range chain[1:] {
+		if b.Info.BackupVersion != IncrementalBackupVersion {
+			return ErrInvalidBackupChain
+		}
+	}
+	if len(chain) == 1 {
+		r, err := chain[0].Open()
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		return dfs.restoreV1(r)
+	}
+
+	// Read the manifests of the incremental backups, newest first, so that
+	// the tenants and their volume settings come from the most recent backup
+	// before any data is restored.
+	last := len(chain) - 1
+	tenants := make(map[string]*chainedTenant)
+	manifests := make([]map[string]Manifest, len(chain))
+	for i := last; i > 0; i-- {
+		manifests[i] = make(map[string]Manifest)
+		if err := scanIncrementalBackup(chain[i], i == last, tenants, manifests[i]); err != nil {
+			plog.WithError(err).WithField("parent", chain[i].Info.Parent).Error("Could not read incremental backup")
+			return err
+		}
+	}
+	for tenant := range tenants {
+		if _, ok := manifests[last][tenant]; !ok {
+			plog.WithField("tenant", tenant).Error("Incremental backup is missing the manifest of a tenant")
+			return ErrRestoreNoInfo
+		}
+	}
+
+	// Start the import of each tenant's volume with the settings of its
+	// snapshot in the most recent backup.
+	var dataError error
+	defer func() {
+		if dataError == nil || dataError == io.EOF {
+			dataError = errors.New("unexpected error reading backup")
+		}
+		for _, t := range tenants {
+			if t.stream != nil {
+				t.stream.abort(dataError)
+			}
+		}
+	}()
+	for tenant, t := range tenants {
+		plog.WithFields(log.Fields{
+			"label":  t.label,
+			"tenant": tenant,
+		}).Info("Loading snapshot for tenant from backup chain")
+		t.stream = newChainedStream(dfs.snapshotLoadPipe(tenant, t.label))
+		for _, e := range t.settings {
+			if err := t.stream.write(e.header, bytes.NewReader(e.data)); err != nil {
+				dataError = err
+				return err
+			}
+		}
+	}
+
+	// Replay the volume data and images of the backups, oldest first.
+	for i, b := range chain {
+		if err := dfs.replayChainedBackup(b, i, tenants, manifests); err != nil {
+			dataError = err
+			return err
+		}
+	}
+
+	// load the snapshots and update the images in the registry
+	for tenant, t := range tenants {
+		s := t.stream
+		t.stream = nil
+		if err := s.close(); err != nil {
+			plog.WithError(err).WithField("tenant", tenant).Error("Error trying to import")
+			dataError = err
+			continue
+		}
+		if err := dfs.loadSnapshotImages(tenant, t.label); err != nil {
+			dataError = err
+			continue
+		}
+	}
+	return dataError
+}
+
+// scanIncrementalBackup reads the manifests of the snapshots in an
+// incremental backup.  For the most recent backup of the chain, it also
+// records the tenants and the volume settings of their snapshots.
+func scanIncrementalBackup(b ChainedBackup, isLast bool, tenants map[string]*chainedTenant, manifests map[string]Manifest) error {
+	r, err := b.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	backuptar := tar.NewReader(r)
+
+	for {
+		hdr, err := backuptar.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(hdr.Name, SnapshotsMetadataDir) {
+			continue
+		}
+		parts := strings.SplitN(hdr.Name, "/", 4)
+		if len(parts) == 3 && strings.HasSuffix(parts[2], BackupManifestSuffix) {
+			var manifest Manifest
+			if err := json.NewDecoder(backuptar).Decode(&manifest); err != nil {
+				return err
+			}
+			manifests[parts[1]] = manifest
+			continue
+		} else if len(parts) <= 3 || !isLast {
+			continue
+		}
+
+		tenant, label := parts[1], parts[2]
+		t, ok := tenants[tenant]
+		if !ok {
+			t = &chainedTenant{label: label}
+			tenants[tenant] = t
+		}
+		if p, dir := volumePath(parts[3]); p != "" {
+			t.volumeDir = dir
+		} else {
+			data, err := ioutil.ReadAll(backuptar)
+			if err != nil {
+				return err
+			}
+			hdr.Name = parts[3]
+			t.settings = append(t.settings, chainedEntry{header: hdr, data: data})
+		}
+	}
+}
+
+// replayChainedBackup writes the volume data of a backup that belongs to the
+// restored snapshots into their import streams, and loads the images of the
+// backup.
+func (dfs *DistributedFilesystem) replayChainedBackup(b ChainedBackup, index int, tenants map[string]*chainedTenant, manifests []map[string]Manifest) error {
+	r, err := b.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	sums := newChecksumWriter()
+	defer sums.Close()
+	backuptar := tar.NewReader(io.TeeReader(r, sums))
+
+	var images *chainedStream
+	defer func() {
+		if images != nil {
+			images.abort(errors.New("unexpected error reading backup"))
+		}
+	}()
+
+	last := len(manifests) - 1
+	for {
+		hdr, err := backuptar.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			plog.WithError(err).Error("Could not read backup file")
+			return err
+		}
+
+		switch {
+		case hdr.Name == BackupMetadataFile:
+			// Skip it, we've already got it
+		case strings.HasPrefix(hdr.Name, SnapshotsMetadataDir):
+			parts := strings.SplitN(hdr.Name, "/", 4)
+			if len(parts) <= 3 {
+				continue
+			}
+			tenant := parts[1]
+			t, ok := tenants[tenant]
+			if !ok {
+				// the tenant is not part of the most recent backup
+				continue
+			}
+			p, dir := volumePath(parts[3])
+			if p == "" {
+				// the settings came from the most recent backup
+				continue
+			}
+			final, ok := manifests[last][tenant][p]
+			if !ok || final.Type != hdr.Typeflag {
+				// the path was removed or replaced in a later backup
+				continue
+			}
+			if hdr.Typeflag != tar.TypeDir && chainSupplier(manifests, tenant, p) != index {
+				// a later backup has the data of the path
+				continue
+			}
+			hdr.Name = t.volumeDir + strings.TrimPrefix(parts[3], dir)
+			if err := t.stream.write(hdr, backuptar); err != nil {
+				plog.WithError(err).WithFields(log.Fields{
+					"header": hdr.Name,
+					"tenant": tenant,
+				}).Error("Could not write snapshot data for tenant")
+				return err
+			}
+		case strings.HasPrefix(hdr.Name, DockerImagesFile):
+			parts := strings.SplitN(hdr.Name, "/", 2)
+			if len(parts) <= 1 {
+				continue
+			}
+			if images == nil {
+				plog.Info("Loading docker images from backup")
+				images = newChainedStream(dfs.imageLoadPipe())
+			}
+			hdr.Name = parts[1]
+			if err := images.write(hdr, backuptar); err != nil {
+				plog.WithError(err).WithField("header", hdr.Name).Error("Could not write image data with header")
+				return err
+			}
+		default:
+			plog.WithField("name", hdr.Name).Warn("Unrecognized file")
+		}
+	}
+
+	// refuse the backup if it was altered, before any data is committed
+	if _, err := verifyChecksums(sums.Close(), dfs.backupKey); err != nil {
+		plog.WithError(err).WithField("parent", b.Info.Parent).Error("Could not verify backup")
+		return err
+	}
+
+	if images != nil {
+		s := images
+		images = nil
+		if err := s.close(); err != nil {
+			plog.WithError(err).Error("Could not load docker images from backup")
+			return err
+		}
+	}
+	return nil
+}
+
+// chainSupplier returns the position in the chain of the most recent backup
+// that contains the data of a path.
+func chainSupplier(manifests []map[string]Manifest, tenant, p string) int {
+	for i := len(manifests) - 1; i > 0; i-- {
+		if entry, ok := manifests[i][tenant][p]; ok && entry.Included {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,248 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package dfs_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"time"
+
+	. "github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/volume"
+	volumemocks "github.com/control-center/serviced/volume/mocks"
+	dockerclient "github.com/fsouza/go-dockerclient"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+var testModTime = time.Unix(1500000000, 0)
+
+func writeTestEntry(c *C, tw *tar.Writer, name string, typeflag byte, data string) {
+	hdr := &tar.Header{Name: name, Typeflag: typeflag, Mode: 0644, ModTime: testModTime}
+	if typeflag == tar.TypeReg {
+		hdr.Size = int64(len(data))
+	}
+	c.Assert(tw.WriteHeader(hdr), IsNil)
+	_, err := tw.Write([]byte(data))
+	c.Assert(err, IsNil)
+}
+
+func writeTestManifest(c *C, tw *tar.Writer, name string, manifest Manifest) {
+	data, err := json.Marshal(manifest)
+	c.Assert(err, IsNil)
+	writeTestEntry(c, tw, name, tar.TypeReg, string(data))
+}
+
+func readTestTar(c *C, r io.Reader) map[string]string {
+	entries := make(map[string]string)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return entries
+		}
+		c.Assert(err, IsNil)
+		data, err := ioutil.ReadAll(tr)
+		c.Assert(err, IsNil)
+		entries[hdr.Name] = string(data)
+	}
+}
+
+func testStamp(size int64, included bool) ManifestEntry {
+	return ManifestEntry{Type: tar.TypeReg, Size: size, Mode: 0644, ModTime: testModTime, Included: included}
+}
+
+func (s *DFSTestSuite) TestBackup_Incremental(c *C) {
+	buf := bytes.NewBufferString("")
+	backupInfo := BackupInfo{
+		Snapshots: []string{"BASE_LABEL"},
+		Timestamp: time.Now().UTC(),
+	}
+	parent := &BackupInfo{
+		Snapshots: []string{"BASE_PARENT"},
+		ImageIDs:  map[string]string{"testserver:5000/BASE/repo:tag": "tenantimage"},
+	}
+	vol := s.getVolumeFromSnapshot("BASE_LABEL", "BASE")
+	info := &volume.SnapshotInfo{
+		Name:     "BASE_LABEL",
+		TenantID: "BASE",
+		Label:    "LABEL",
+		Created:  time.Now().UTC(),
+	}
+	vol.On("SnapshotInfo", "BASE_LABEL").Return(info, nil)
+	vol.On("ReadMetadata", "LABEL", ImagesMetadataFile).Return(&NopCloser{bytes.NewBufferString(`["BASE/repo:tag"]`)}, nil)
+	s.registry.On("PullImage", mock.AnythingOfType("<-chan time.Time"), "BASE/repo:tag").Return(nil)
+	s.registry.On("ImagePath", "BASE/repo:tag").Return("testserver:5000/BASE/repo:tag", nil)
+	s.docker.On("FindImage", "testserver:5000/BASE/repo:tag").Return(&dockerclient.Image{ID: "tenantimage"}, nil)
+
+	parentManifest, err := json.Marshal(Manifest{
+		"/same":    testStamp(4, true),
+		"/changed": testStamp(5, true),
+		"/removed": testStamp(6, true),
+	})
+	c.Assert(err, IsNil)
+	vol.On("ReadMetadata", "BASE_PARENT", BackupManifestFile).Return(&NopCloser{bytes.NewBuffer(parentManifest)}, nil)
+	// the volume only exports the changes since the parent snapshot
+	vol.On("Export", "LABEL", "BASE_PARENT", mock.AnythingOfType("*io.PipeWriter")).Return(nil).Run(func(a mock.Arguments) {
+		tw := tar.NewWriter(a.Get(2).(io.Writer))
+		writeTestEntry(c, tw, "BASE_LABEL-driver", tar.TypeReg, "rsync")
+		writeTestEntry(c, tw, "BASE_LABEL-volume", tar.TypeDir, "")
+		writeTestEntry(c, tw, "BASE_LABEL-volume/changed", tar.TypeReg, "new")
+		writeTestEntry(c, tw, "BASE_LABEL-volume/added", tar.TypeReg, "added")
+		writeTestEntry(c, tw, "BASE_LABEL-volume/"+volume.WhiteoutPrefix+"removed", tar.TypeReg, "")
+		tw.Close()
+	})
+	saved := &NopCloser{bytes.NewBufferString("")}
+	vol.On("WriteMetadata", "LABEL", BackupManifestFile).Return(saved, nil)
+
+	err = s.dfs.Backup(backupInfo, parent, buf)
+	c.Assert(err, IsNil)
+	s.docker.AssertNotCalled(c, "SaveImages", mock.Anything, mock.Anything)
+
+	entries := readTestTar(c, buf)
+	c.Assert(entries, HasLen, 7)
+	for _, name := range []string{
+		BackupChecksumsFile,
+		"SNAPSHOTS/BASE/LABEL/BASE_LABEL-driver",
+		"SNAPSHOTS/BASE/LABEL/BASE_LABEL-volume",
+		"SNAPSHOTS/BASE/LABEL/BASE_LABEL-volume/added",
+		"SNAPSHOTS/BASE/LABEL.manifest",
+	} {
+		_, ok := entries[name]
+		c.Check(ok, Equals, true, Commentf("missing %s", name))
+	}
+	c.Check(entries["SNAPSHOTS/BASE/LABEL/BASE_LABEL-volume/changed"], Equals, "new")
+
+	var actualInfo BackupInfo
+	err = json.Unmarshal([]byte(entries[BackupMetadataFile]), &actualInfo)
+	c.Assert(err, IsNil)
+	c.Check(actualInfo.ImageIDs, DeepEquals, parent.ImageIDs)
+
+	var manifest Manifest
+	err = json.NewDecoder(saved).Decode(&manifest)
+	c.Assert(err, IsNil)
+	c.Assert(manifest, HasLen, 4)
+	c.Check(manifest["/"].Included, Equals, true)
+	c.Check(manifest["/same"].Included, Equals, false)
+	c.Check(manifest["/same"].Size, Equals, int64(4))
+	c.Check(manifest["/same"].ModTime.Equal(testModTime), Equals, true)
+	c.Check(manifest["/changed"].Included, Equals, true)
+	c.Check(manifest["/added"].Included, Equals, true)
+}
+
+func (s *DFSTestSuite) TestBackup_IncrementalNoParentSnapshot(c *C) {
+	buf := bytes.NewBufferString("")
+	backupInfo := BackupInfo{
+		Snapshots: []string{"BASE_LABEL"},
+		Timestamp: time.Now().UTC(),
+	}
+	parent := &BackupInfo{Snapshots: []string{"BASE_PARENT"}}
+	vol := s.getVolumeFromSnapshot("BASE_LABEL", "BASE")
+	info := &volume.SnapshotInfo{
+		Name:     "BASE_LABEL",
+		TenantID: "BASE",
+		Label:    "LABEL",
+		Created:  time.Now().UTC(),
+	}
+	vol.On("SnapshotInfo", "BASE_LABEL").Return(info, nil)
+	vol.On("ReadMetadata", "LABEL", ImagesMetadataFile).Return(&NopCloser{bytes.NewBufferString("[]")}, nil)
+	vol.On("ReadMetadata", "BASE_PARENT", BackupManifestFile).Return(&NopCloser{bytes.NewBufferString("")}, ErrTestSnapshotNotFound)
+
+	err := s.dfs.Backup(backupInfo, parent, buf)
+	c.Assert(err, Equals, ErrNoParentSnapshot)
+	vol.AssertNotCalled(c, "Export", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *DFSTestSuite) TestRestore_Incremental(c *C) {
+	err := s.dfs.Restore(bytes.NewBufferString(""), IncrementalBackupVersion)
+	c.Assert(err, Equals, ErrIncrementalRestore)
+}
+
+func (s *DFSTestSuite) TestRestoreChain_Invalid(c *C) {
+	open := func() (io.ReadCloser, error) { return &NopCloser{bytes.NewBufferString("")}, nil }
+	err := s.dfs.RestoreChain([]ChainedBackup{
+		{Info: &BackupInfo{BackupVersion: IncrementalBackupVersion}, Open: open},
+	})
+	c.Assert(err, Equals, ErrInvalidBackupChain)
+	err = s.dfs.RestoreChain([]ChainedBackup{
+		{Info: &BackupInfo{BackupVersion: 1}, Open: open},
+		{Info: &BackupInfo{BackupVersion: 1}, Open: open},
+	})
+	c.Assert(err, Equals, ErrInvalidBackupChain)
+}
+
+func (s *DFSTestSuite) TestRestoreChain(c *C) {
+	full := bytes.NewBufferString("")
+	tw := tar.NewWriter(full)
+	fullInfo := BackupInfo{Snapshots: []string{"BASE_OLD"}, BackupVersion: 1}
+	s.writeBackupInfo(c, tw, fullInfo)
+	writeTestEntry(c, tw, "SNAPSHOTS/BASE/OLD/BASE_OLD-driver", tar.TypeReg, "rsync")
+	writeTestEntry(c, tw, "SNAPSHOTS/BASE/OLD/BASE_OLD-volume", tar.TypeDir, "")
+	writeTestEntry(c, tw, "SNAPSHOTS/BASE/OLD/BASE_OLD-volume/same", tar.TypeReg, "same")
+	writeTestEntry(c, tw, "SNAPSHOTS/BASE/OLD/BASE_OLD-volume/changed", tar.TypeReg, "old")
+	writeTestEntry(c, tw, "SNAPSHOTS/BASE/OLD/BASE_OLD-volume/deleted", tar.TypeReg, "deleted")
+	writeTestEntry(c, tw, "SNAPSHOTS/OTHER/OLD/OTHER_OLD-driver", tar.TypeReg, "rsync")
+	writeTestEntry(c, tw, DockerImagesFile+"/afile", tar.TypeReg, "image")
+	tw.Close()
+
+	incremental := bytes.NewBufferString("")
+	tw = tar.NewWriter(incremental)
+	incrementalInfo := BackupInfo{Snapshots: []string{"BASE_NEW"}, BackupVersion: IncrementalBackupVersion, Parent: "full.tgz"}
+	s.writeBackupInfo(c, tw, incrementalInfo)
+	writeTestEntry(c, tw, "SNAPSHOTS/BASE/NEW/BASE_NEW-driver", tar.TypeReg, "rsync")
+	writeTestEntry(c, tw, "SNAPSHOTS/BASE/NEW/BASE_NEW-volume", tar.TypeDir, "")
+	writeTestEntry(c, tw, "SNAPSHOTS/BASE/NEW/BASE_NEW-volume/changed", tar.TypeReg, "new")
+	writeTestManifest(c, tw, "SNAPSHOTS/BASE/NEW.manifest", Manifest{
+		"/":        {Type: tar.TypeDir, Mode: 0644, ModTime: testModTime, Included: true},
+		"/same":    testStamp(4, false),
+		"/changed": testStamp(3, true),
+	})
+	tw.Close()
+
+	chain := []ChainedBackup{
+		{Info: &fullInfo, Open: func() (io.ReadCloser, error) {
+			return &NopCloser{bytes.NewBuffer(full.Bytes())}, nil
+		}},
+		{Info: &incrementalInfo, Open: func() (io.ReadCloser, error) {
+			return &NopCloser{bytes.NewBuffer(incremental.Bytes())}, nil
+		}},
+	}
+
+	var imported map[string]string
+	s.disk.On("Create", "BASE").Return(&volumemocks.Volume{}, volume.ErrVolumeExists)
+	vol := s.getVolumeFromSnapshot("BASE_NEW", "BASE")
+	vol.On("Import", "NEW", mock.Anything).Return(nil).Run(func(a mock.Arguments) {
+		imported = readTestTar(c, a.Get(1).(io.Reader))
+	})
+	vol.On("ReadMetadata", "NEW", ImagesMetadataFile).Return(&NopCloser{bytes.NewBufferString("[]")}, nil)
+	s.docker.On("LoadImage", mock.Anything).Return(nil).Run(func(a mock.Arguments) {
+		ioutil.ReadAll(a.Get(0).(io.Reader))
+	}).Once()
+
+	err := s.dfs.RestoreChain(chain)
+	c.Assert(err, IsNil)
+	c.Assert(imported, DeepEquals, map[string]string{
+		"BASE_NEW-driver":         "rsync",
+		"BASE_NEW-volume":         "",
+		"BASE_NEW-volume/same":    "same",
+		"BASE_NEW-volume/changed": "new",
+	})
+	s.disk.AssertNotCalled(c, "Create", "OTHER")
+	s.docker.AssertExpectations(c)
+}
@@ -208,13 +208,13 @@ func (_m *DFS) Info(snapshotID string) (*dfs.SnapshotInfo, error) {
 	return r0, r1
 }
 
-// Backup provides a mock function with given fields: info, w
-func (_m *DFS) Backup(info dfs.BackupInfo, w io.Writer) error {
-	ret := _m.Called(info, w)
+// Backup provides a mock function with given fields: info, parent, w
+func (_m *DFS) Backup(info dfs.BackupInfo, parent *dfs.BackupInfo, w io.Writer) error {
+	ret := _m.Called(info, parent, w)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(dfs.BackupInfo, io.Writer) error); ok {
-		r0 = rf(info, w)
+	if rf, ok := ret.Get(0).(func(dfs.BackupInfo, *dfs.BackupInfo, io.Writer) error); ok {
+		r0 = rf(info, parent, w)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -236,6 +236,43 @@ func (_m *DFS) Restore(r io.Reader, version int) error {
 	return r0
 }
 
+// RestoreChain provides a mock function with given fields: chain
+func (_m *DFS) RestoreChain(chain []dfs.ChainedBackup) error {
+	ret := _m.Called(chain)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]dfs.ChainedBackup) error); ok {
+		r0 = rf(chain)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyBackup provides a mock function with given fields: r
+func (_m *DFS) VerifyBackup(r io.Reader) (*dfs.BackupVerification, error) {
+	ret := _m.Called(r)
+
+	var r0 *dfs.BackupVerification
+	if rf, ok := ret.Get(0).(func(io.Reader) *dfs.BackupVerification); ok {
+		r0 = rf(r)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dfs.BackupVerification)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(io.Reader) error); ok {
+		r1 = rf(r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // BackupInfo provides a mock function with given fields: r
 func (_m *DFS) BackupInfo(r io.Reader) (*dfs.BackupInfo, error) {
 	ret := _m.Called(r)
@@ -424,3 +461,16 @@ func (_m *DFS) VerifyTenantMounts(tenantid string) error {
 
 	return r0
 }
+
+func (_m *DFS) VerifyImage(image string) error {
+	ret := _m.Called(image)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(image)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -34,9 +34,15 @@ func (dfs *DistributedFilesystem) Restore(r io.Reader, version int) error {
 	plog.WithField("version", version).Info("Detected backup version")
 	switch version {
 	case 0:
+		if len(dfs.backupKey) > 0 {
+			// these backups have no checksums to verify
+			return ErrBackupUnsigned
+		}
 		return dfs.restoreV0(r)
 	case 1:
 		return dfs.restoreV1(r)
+	case IncrementalBackupVersion:
+		return ErrIncrementalRestore
 	default:
 		return ErrInvalidBackupVersion
 	}
@@ -112,7 +118,9 @@ func (dfs *DistributedFilesystem) restoreV0(r io.Reader) error {
 // and independent tar file within the tar stream (but is now included inline),
 // and one for each DFS snapshot being restored.
 func (dfs *DistributedFilesystem) restoreV1(r io.Reader) error {
-	backuptar := tar.NewReader(r)
+	sums := newChecksumWriter()
+	defer sums.Close()
+	backuptar := tar.NewReader(io.TeeReader(r, sums))
 
 	// Keep track of all the data pipes
 	var dataError error
@@ -232,6 +240,13 @@ func (dfs *DistributedFilesystem) restoreV1(r io.Reader) error {
 		}
 	}
 
+	// refuse the backup if it was altered, before any data is committed
+	if _, err := verifyChecksums(sums.Close(), dfs.backupKey); err != nil {
+		plog.WithError(err).Error("Could not verify backup")
+		dataError = err
+		return err
+	}
+
 	// make sure the image load finishes first
 	s, ok := streamMap[DockerImagesFile]
 	if ok {
@@ -0,0 +1,81 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package target
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Local stores backup files in a directory on the master
+type Local struct {
+	dir string
+}
+
+// NewLocal returns a target for a directory on the master
+func NewLocal(dir string) *Local {
+	return &Local{dir: dir}
+}
+
+// localWriter removes the file it was writing if it is aborted
+type localWriter struct {
+	*os.File
+}
+
+// Abort implements Writer
+func (w *localWriter) Abort() error {
+	w.File.Close()
+	return os.Remove(w.Name())
+}
+
+// Create implements Target
+func (t *Local) Create(name string) (Writer, error) {
+	fh, err := os.Create(t.Path(name))
+	if err != nil {
+		return nil, err
+	}
+	return &localWriter{fh}, nil
+}
+
+// Open implements Target
+func (t *Local) Open(name string) (io.ReadCloser, error) {
+	return os.Open(t.Path(name))
+}
+
+// List implements Target
+func (t *Local) List() ([]File, error) {
+	fis, err := ioutil.ReadDir(t.dir)
+	if err != nil {
+		return nil, err
+	}
+	files := []File{}
+	for _, fi := range fis {
+		if fi.Mode().IsRegular() {
+			files = append(files, File{Name: fi.Name(), Size: fi.Size(), Mode: fi.Mode(), ModTime: fi.ModTime()})
+		}
+	}
+	return files, nil
+}
+
+// Remove implements Target
+func (t *Local) Remove(name string) error {
+	return os.Remove(t.Path(name))
+}
+
+// Path implements Target
+func (t *Local) Path(name string) string {
+	return filepath.Join(t.dir, name)
+}
@@ -0,0 +1,162 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package target
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	minio "github.com/minio/minio-go/v6"
+	"github.com/minio/minio-go/v6/pkg/credentials"
+)
+
+const (
+	// s3PartSize is the size of the parts of a backup uploaded to s3.  Parts
+	// are buffered in memory, and a multipart upload has at most 10000 of
+	// them.
+	s3PartSize = 64 * 1024 * 1024
+
+	s3DefaultRegion = "us-east-1"
+)
+
+// S3 stores backup files in a bucket of an S3-compatible object store
+type S3 struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+// NewS3 returns a target for a url of the form
+// s3://[accesskey:secretkey@]bucket[/prefix][?endpoint=url][&region=name].
+// Without credentials in the url, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+// are used.
+func NewS3(u *url.URL) (*S3, error) {
+	if u.Host == "" {
+		return nil, ErrInvalidTarget
+	}
+	query := u.Query()
+	region := query.Get("region")
+	if region == "" {
+		region = s3DefaultRegion
+	}
+	endpoint := query.Get("endpoint")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	e, err := url.Parse(endpoint)
+	if err != nil || e.Host == "" {
+		return nil, ErrInvalidTarget
+	}
+	creds := credentials.NewEnvAWS()
+	if u.User != nil {
+		secretKey, _ := u.User.Password()
+		creds = credentials.NewStaticV4(u.User.Username(), secretKey, "")
+	}
+	client, err := minio.NewWithOptions(e.Host, &minio.Options{
+		Creds:  creds,
+		Secure: e.Scheme == "https",
+		Region: region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	t := &S3{client: client, bucket: u.Host, prefix: strings.Trim(u.Path, "/")}
+	if t.prefix != "" {
+		t.prefix += "/"
+	}
+	return t, nil
+}
+
+// Create implements Target.  The file is sent as a multipart upload as it is
+// written.
+func (t *S3) Create(name string) (Writer, error) {
+	r, w := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		_, err := t.client.PutObject(t.bucket, t.prefix+name, r, -1, minio.PutObjectOptions{PartSize: s3PartSize})
+		r.CloseWithError(err)
+		done <- err
+	}()
+	return &s3Writer{w: w, done: done}, nil
+}
+
+// Open implements Target
+func (t *S3) Open(name string) (io.ReadCloser, error) {
+	obj, err := t.client.GetObject(t.bucket, t.prefix+name, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// the object is not requested until it is used
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		return nil, err
+	}
+	return obj, nil
+}
+
+// List implements Target
+func (t *S3) List() ([]File, error) {
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	files := []File{}
+	for obj := range t.client.ListObjectsV2(t.bucket, t.prefix, false, doneCh) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+		name := strings.TrimPrefix(obj.Key, t.prefix)
+		if name == "" || strings.HasSuffix(name, "/") {
+			continue
+		}
+		files = append(files, File{Name: name, Size: obj.Size, ModTime: obj.LastModified})
+	}
+	return files, nil
+}
+
+// Remove implements Target
+func (t *S3) Remove(name string) error {
+	return t.client.RemoveObject(t.bucket, t.prefix+name)
+}
+
+// Path implements Target
+func (t *S3) Path(name string) string {
+	return fmt.Sprintf("s3://%s/%s%s", t.bucket, t.prefix, name)
+}
+
+// s3Writer feeds a backup file to its upload
+type s3Writer struct {
+	w    *io.PipeWriter
+	done chan error
+}
+
+// Write implements io.Writer
+func (w *s3Writer) Write(p []byte) (int, error) {
+	return w.w.Write(p)
+}
+
+// Close completes the upload
+func (w *s3Writer) Close() error {
+	w.w.Close()
+	return <-w.done
+}
+
+// Abort stops the upload, which discards the parts that were uploaded
+func (w *s3Writer) Abort() error {
+	w.w.CloseWithError(errAborted)
+	if err := <-w.done; err != nil && err != errAborted {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package target
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	minio "github.com/minio/minio-go/v6"
+	. "gopkg.in/check.v1"
+)
+
+// fakeS3 is an object store with one bucket that handles the requests the
+// target makes
+type fakeS3 struct {
+	sync.Mutex
+	objects map[string][]byte
+	uploads map[string][][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+	body, _ := ioutil.ReadAll(r.Body)
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, "<Error><Code>AccessDenied</Code><Message>bad signature</Message></Error>")
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	query := r.URL.Query()
+	switch {
+	case r.Method == "POST" && query.Get("uploads") == "" && strings.Contains(r.URL.RawQuery, "uploads"):
+		id := fmt.Sprintf("upload%d", len(f.uploads))
+		f.uploads[id] = nil
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", id)
+	case r.Method == "PUT" && query.Get("uploadId") != "":
+		id := query.Get("uploadId")
+		f.uploads[id] = append(f.uploads[id], body)
+		w.Header().Set("ETag", fmt.Sprintf("\"%s-%s\"", id, query.Get("partNumber")))
+	case r.Method == "POST" && query.Get("uploadId") != "":
+		id := query.Get("uploadId")
+		var data []byte
+		for _, part := range f.uploads[id] {
+			data = append(data, part...)
+		}
+		f.objects[key] = data
+		delete(f.uploads, id)
+		fmt.Fprintf(w, "<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>%s</Key></CompleteMultipartUploadResult>", key)
+	case r.Method == "PUT":
+		f.objects[key] = body
+		w.Header().Set("ETag", "\"etag\"")
+	case r.Method == "DELETE" && query.Get("uploadId") != "":
+		delete(f.uploads, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == "DELETE":
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == "GET" && key == "":
+		var keys []string
+		for k := range f.objects {
+			if strings.HasPrefix(k, query.Get("prefix")) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		fmt.Fprint(w, "<ListBucketResult>")
+		for _, k := range keys {
+			fmt.Fprintf(w, "<Contents><Key>%s</Key><Size>%d</Size><LastModified>2019-01-02T03:04:05.000Z</LastModified></Contents>", k, len(f.objects[k]))
+		}
+		fmt.Fprint(w, "<IsTruncated>false</IsTruncated></ListBucketResult>")
+	case r.Method == "GET" || r.Method == "HEAD":
+		data, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, "<Error><Code>NoSuchKey</Code><Message>not found</Message></Error>")
+			return
+		}
+		w.Header().Set("ETag", "\"etag\"")
+		w.Header().Set("Last-Modified", "Wed, 02 Jan 2019 03:04:05 GMT")
+		w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+		if r.Method == "GET" {
+			w.Write(data)
+		}
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (s *TargetSuite) newS3(c *C) (*S3, *fakeS3, func()) {
+	fake := &fakeS3{objects: make(map[string][]byte), uploads: make(map[string][][]byte)}
+	server := httptest.NewServer(fake)
+	t, err := New(fmt.Sprintf("s3://key:secret@bucket/backups?endpoint=%s", url.QueryEscape(server.URL)))
+	c.Assert(err, IsNil)
+	return t.(*S3), fake, server.Close
+}
+
+func (s *TargetSuite) TestS3(c *C) {
+	t, fake, done := s.newS3(c)
+	defer done()
+
+	w, err := t.Create("backup.tgz")
+	c.Assert(err, IsNil)
+	data := strings.Repeat("0123456789", 3) + "tail"
+	_, err = w.Write([]byte(data))
+	c.Assert(err, IsNil)
+	c.Assert(w.Close(), IsNil)
+	c.Check(string(fake.objects["backups/backup.tgz"]), Equals, data)
+
+	w, err = t.Create("aborted.tgz")
+	c.Assert(err, IsNil)
+	_, err = w.Write([]byte(data))
+	c.Assert(err, IsNil)
+	c.Assert(w.Abort(), IsNil)
+	c.Check(fake.uploads, HasLen, 0)
+
+	files, err := t.List()
+	c.Assert(err, IsNil)
+	c.Assert(files, HasLen, 1)
+	c.Check(files[0], DeepEquals, File{Name: "backup.tgz", Size: int64(len(data)), ModTime: time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)})
+
+	r, err := t.Open("backup.tgz")
+	c.Assert(err, IsNil)
+	actual, err := ioutil.ReadAll(r)
+	r.Close()
+	c.Assert(err, IsNil)
+	c.Check(string(actual), Equals, data)
+
+	c.Assert(t.Remove("backup.tgz"), IsNil)
+	_, err = t.Open("backup.tgz")
+	c.Assert(err, NotNil)
+	c.Check(minio.ToErrorResponse(err).Code, Equals, "NoSuchKey")
+}
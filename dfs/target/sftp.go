@@ -0,0 +1,203 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package target
+
+import (
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/pkg/sftp"
+)
+
+// SFTP stores backup files in a directory on an sftp server.  It runs the
+// sftp subsystem through the ssh client of the master, so authentication
+// uses the ssh keys and known hosts of the user running serviced.
+type SFTP struct {
+	dir  string
+	args []string // arguments of the ssh command
+	dial func() (*sftpSession, error)
+	host string
+}
+
+// NewSFTP returns a target for a url of the form
+// sftp://[user@]host[:port]/path[?identity=keyfile]
+func NewSFTP(u *url.URL) (*SFTP, error) {
+	if u.Hostname() == "" || u.Path == "" || strings.HasPrefix(u.Hostname(), "-") {
+		return nil, ErrInvalidTarget
+	}
+	if u.User != nil && strings.HasPrefix(u.User.Username(), "-") {
+		return nil, ErrInvalidTarget
+	}
+	args := []string{"-o", "BatchMode=yes"}
+	if port := u.Port(); port != "" {
+		args = append(args, "-p", port)
+	}
+	if identity := u.Query().Get("identity"); identity != "" {
+		args = append(args, "-i", identity)
+	}
+	dest, host := u.Hostname(), u.Host
+	if u.User != nil {
+		dest = u.User.Username() + "@" + dest
+		host = u.User.Username() + "@" + host
+	}
+	args = append(args, "-s", "--", dest, "sftp")
+	t := &SFTP{dir: u.Path, args: args, host: host}
+	t.dial = func() (*sftpSession, error) { return dialSFTP(exec.Command("ssh", t.args...)) }
+	return t, nil
+}
+
+// Create implements Target.  The file is written under a temporary name and
+// renamed when it is complete.
+func (t *SFTP) Create(name string) (Writer, error) {
+	session, err := t.dial()
+	if err != nil {
+		return nil, err
+	}
+	filename := path.Join(t.dir, name)
+	file, err := session.Create(filename + partialSuffix)
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+	return &sftpWriter{session: session, File: file, filename: filename}, nil
+}
+
+// Open implements Target
+func (t *SFTP) Open(name string) (io.ReadCloser, error) {
+	session, err := t.dial()
+	if err != nil {
+		return nil, err
+	}
+	file, err := session.Open(path.Join(t.dir, name))
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+	return &sftpReader{session: session, File: file}, nil
+}
+
+// List implements Target.  Only regular files that are complete are listed.
+func (t *SFTP) List() ([]File, error) {
+	session, err := t.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+	infos, err := session.ReadDir(t.dir)
+	if err != nil {
+		return nil, err
+	}
+	files := []File{}
+	for _, info := range infos {
+		if info.Mode().IsRegular() && !strings.HasSuffix(info.Name(), partialSuffix) {
+			files = append(files, File{Name: info.Name(), Size: info.Size(), ModTime: info.ModTime()})
+		}
+	}
+	return files, nil
+}
+
+// Remove implements Target
+func (t *SFTP) Remove(name string) error {
+	session, err := t.dial()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	return session.Remove(path.Join(t.dir, name))
+}
+
+// Path implements Target
+func (t *SFTP) Path(name string) string {
+	return "sftp://" + t.host + path.Join(t.dir, name)
+}
+
+// sftpSession is an sftp client that ends the command running the sftp
+// subsystem when it is closed
+type sftpSession struct {
+	*sftp.Client
+	wait func() error
+}
+
+// dialSFTP starts the command that runs the sftp subsystem and negotiates
+// the protocol version
+func dialSFTP(cmd *exec.Cmd) (*sftpSession, error) {
+	w, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	r, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	client, err := sftp.NewClientPipe(r, w)
+	if err != nil {
+		plog.WithError(err).WithField("command", strings.Join(cmd.Args, " ")).Debug("Could not start sftp session")
+		w.Close()
+		cmd.Wait()
+		return nil, err
+	}
+	return &sftpSession{Client: client, wait: cmd.Wait}, nil
+}
+
+// Close ends the session
+func (s *sftpSession) Close() error {
+	err := s.Client.Close()
+	if s.wait != nil {
+		s.wait()
+	}
+	return err
+}
+
+// sftpWriter writes a file under a temporary name
+type sftpWriter struct {
+	*sftp.File
+	session  *sftpSession
+	filename string
+}
+
+// Close finishes the file and gives it its name
+func (w *sftpWriter) Close() error {
+	defer w.session.Close()
+	if err := w.File.Close(); err != nil {
+		return err
+	}
+	return w.session.Rename(w.filename+partialSuffix, w.filename)
+}
+
+// Abort removes the partial file
+func (w *sftpWriter) Abort() error {
+	defer w.session.Close()
+	w.File.Close()
+	return w.session.Remove(w.filename + partialSuffix)
+}
+
+// sftpReader reads a file
+type sftpReader struct {
+	*sftp.File
+	session *sftpSession
+}
+
+// Close closes the file and ends the session
+func (r *sftpReader) Close() error {
+	defer r.session.Close()
+	return r.File.Close()
+}
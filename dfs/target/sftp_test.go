@@ -0,0 +1,101 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package target
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/sftp"
+	. "gopkg.in/check.v1"
+)
+
+// sftpPipe connects the server end of an sftp session
+type sftpPipe struct {
+	io.Reader
+	io.WriteCloser
+}
+
+func (s *TargetSuite) newSFTP(c *C) *SFTP {
+	handlers := sftp.InMemHandler()
+	t, err := New("sftp://backup@host/srv/backups")
+	c.Assert(err, IsNil)
+	target := t.(*SFTP)
+	target.dial = func() (*sftpSession, error) {
+		serverR, clientW := io.Pipe()
+		clientR, serverW := io.Pipe()
+		server := sftp.NewRequestServer(sftpPipe{serverR, serverW}, handlers)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			server.Serve()
+			server.Close()
+		}()
+		client, err := sftp.NewClientPipe(clientR, clientW)
+		if err != nil {
+			return nil, err
+		}
+		return &sftpSession{Client: client, wait: func() error { <-done; return nil }}, nil
+	}
+	session, err := target.dial()
+	c.Assert(err, IsNil)
+	defer session.Close()
+	c.Assert(session.MkdirAll("/srv/backups"), IsNil)
+	return target
+}
+
+func (s *TargetSuite) TestSFTP(c *C) {
+	t := s.newSFTP(c)
+
+	w, err := t.Create("backup.tgz")
+	c.Assert(err, IsNil)
+	data := strings.Repeat("0123456789", 64*1024)
+	_, err = w.Write([]byte(data))
+	c.Assert(err, IsNil)
+	c.Assert(w.Close(), IsNil)
+
+	w, err = t.Create("aborted.tgz")
+	c.Assert(err, IsNil)
+	_, err = w.Write([]byte(data))
+	c.Assert(err, IsNil)
+	c.Assert(w.Abort(), IsNil)
+
+	session, err := t.dial()
+	c.Assert(err, IsNil)
+	infos, err := session.ReadDir("/srv/backups")
+	c.Assert(err, IsNil)
+	c.Assert(session.Close(), IsNil)
+	c.Check(infos, HasLen, 1)
+
+	files, err := t.List()
+	c.Assert(err, IsNil)
+	c.Assert(files, HasLen, 1)
+	c.Check(files[0].Name, Equals, "backup.tgz")
+	c.Check(files[0].Size, Equals, int64(len(data)))
+
+	r, err := t.Open("backup.tgz")
+	c.Assert(err, IsNil)
+	actual, err := ioutil.ReadAll(r)
+	c.Assert(err, IsNil)
+	c.Assert(r.Close(), IsNil)
+	c.Check(string(actual), Equals, data)
+
+	c.Assert(t.Remove("backup.tgz"), IsNil)
+	_, err = t.Open("backup.tgz")
+	c.Assert(os.IsNotExist(err), Equals, true)
+}
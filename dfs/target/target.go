@@ -0,0 +1,132 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package target stores backup files on the local filesystem of the master
+// or on remote storage.
+package target
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/logging"
+)
+
+var plog = logging.PackageLogger()
+
+var (
+	ErrUnsupportedScheme = errors.New("unsupported backup target")
+	ErrInvalidTarget     = errors.New("invalid backup target")
+
+	errAborted = errors.New("backup upload was aborted")
+)
+
+// partialSuffix marks a backup file that is still being written on targets
+// that cannot create a file atomically
+const partialSuffix = ".part"
+
+// Target is a place where backup files are stored
+type Target interface {
+	// Create starts writing a new backup file.  The file is not complete
+	// until the writer is closed.
+	Create(name string) (Writer, error)
+
+	// Open reads a backup file
+	Open(name string) (io.ReadCloser, error)
+
+	// List returns the backup files on the target
+	List() ([]File, error)
+
+	// Remove deletes a backup file
+	Remove(name string) error
+
+	// Path returns the location of a backup file on the target
+	Path(name string) string
+}
+
+// Writer writes a backup file to a target
+type Writer interface {
+	io.WriteCloser
+
+	// Abort discards the file
+	Abort() error
+}
+
+// File describes a backup file on a target
+type File struct {
+	Name    string
+	Size    int64
+	Mode    os.FileMode // only known for local files
+	ModTime time.Time
+}
+
+// IsRemote returns true if the location is the url of remote storage rather
+// than a directory on the master
+func IsRemote(location string) bool {
+	u, err := url.Parse(location)
+	return err == nil && u.Scheme != "" && u.Scheme != "file"
+}
+
+// New returns the target at a location, which is either a directory on the
+// master or the url of remote storage:
+//
+//	/opt/serviced/var/backups
+//	s3://bucket/prefix?endpoint=https://minio:9000&region=us-east-1
+//	sftp://user@host:22/path/to/backups?identity=/root/.ssh/id_rsa
+func New(location string) (Target, error) {
+	if !strings.Contains(location, "://") {
+		return NewLocal(location), nil
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, ErrInvalidTarget
+	}
+	switch u.Scheme {
+	case "file":
+		return NewLocal(u.Path), nil
+	case "s3":
+		return NewS3(u)
+	case "sftp":
+		return NewSFTP(u)
+	default:
+		return nil, ErrUnsupportedScheme
+	}
+}
+
+// Split returns the target of a backup file and the name of the file on the
+// target
+func Split(location string) (Target, string, error) {
+	if !strings.Contains(location, "://") {
+		return NewLocal(filepath.Dir(location)), filepath.Base(location), nil
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, "", ErrInvalidTarget
+	}
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		return nil, "", ErrInvalidTarget
+	}
+	u.Path = path.Dir(u.Path)
+	t, err := New(u.String())
+	if err != nil {
+		return nil, "", err
+	}
+	return t, name, nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package target
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+var _ = Suite(&TargetSuite{})
+
+type TargetSuite struct{}
+
+func (s *TargetSuite) TestNew(c *C) {
+	t, err := New("/opt/serviced/var/backups")
+	c.Assert(err, IsNil)
+	c.Check(t.Path("backup.tgz"), Equals, "/opt/serviced/var/backups/backup.tgz")
+
+	t, err = New("s3://key:secret@bucket/backups?endpoint=http://minio:9000&region=eu-west-1")
+	c.Assert(err, IsNil)
+	c.Check(t.(*S3).client.EndpointURL().String(), Equals, "http://minio:9000")
+	c.Check(t.Path("backup.tgz"), Equals, "s3://bucket/backups/backup.tgz")
+
+	t, err = New("sftp://backup@host:2222/srv/backups")
+	c.Assert(err, IsNil)
+	c.Check(t.Path("backup.tgz"), Equals, "sftp://backup@host:2222/srv/backups/backup.tgz")
+	c.Check(t.(*SFTP).args, DeepEquals, []string{"-o", "BatchMode=yes", "-p", "2222", "-s", "--", "backup@host", "sftp"})
+
+	_, err = New("ftp://host/backups")
+	c.Check(err, Equals, ErrUnsupportedScheme)
+	_, err = New("sftp://host")
+	c.Check(err, Equals, ErrInvalidTarget)
+	_, err = New("sftp://-oProxyCommand=sh/srv/backups")
+	c.Check(err, Equals, ErrInvalidTarget)
+	_, err = New("sftp://-oProxyCommand=sh@host/srv/backups")
+	c.Check(err, Equals, ErrInvalidTarget)
+
+	c.Check(IsRemote("/opt/serviced/var/backups"), Equals, false)
+	c.Check(IsRemote("file:///opt/serviced/var/backups"), Equals, false)
+	c.Check(IsRemote("s3://bucket"), Equals, true)
+}
+
+func (s *TargetSuite) TestSplit(c *C) {
+	t, name, err := Split("/opt/serviced/var/backups/backup.tgz")
+	c.Assert(err, IsNil)
+	c.Check(name, Equals, "backup.tgz")
+	c.Check(t.Path(""), Equals, "/opt/serviced/var/backups")
+
+	t, name, err = Split("s3://bucket/backups/backup.tgz?endpoint=http://minio:9000")
+	c.Assert(err, IsNil)
+	c.Check(name, Equals, "backup.tgz")
+	c.Check(t.(*S3).client.EndpointURL().Host, Equals, "minio:9000")
+	c.Check(t.Path(""), Equals, "s3://bucket/backups/")
+
+	t, name, err = Split("s3://bucket/backup.tgz")
+	c.Assert(err, IsNil)
+	c.Check(name, Equals, "backup.tgz")
+	c.Check(t.Path(""), Equals, "s3://bucket/")
+
+	_, _, err = Split("s3://bucket/")
+	c.Check(err, Equals, ErrInvalidTarget)
+}
+
+func (s *TargetSuite) TestLocal(c *C) {
+	dir := c.MkDir()
+	c.Assert(os.Mkdir(filepath.Join(dir, "subdir"), 0755), IsNil)
+	t := NewLocal(dir)
+
+	w, err := t.Create("backup.tgz")
+	c.Assert(err, IsNil)
+	_, err = w.Write([]byte("data"))
+	c.Assert(err, IsNil)
+	c.Assert(w.Close(), IsNil)
+
+	w, err = t.Create("aborted.tgz")
+	c.Assert(err, IsNil)
+	c.Assert(w.Abort(), IsNil)
+
+	files, err := t.List()
+	c.Assert(err, IsNil)
+	c.Assert(files, HasLen, 1)
+	c.Check(files[0].Name, Equals, "backup.tgz")
+	c.Check(files[0].Size, Equals, int64(4))
+
+	r, err := t.Open("backup.tgz")
+	c.Assert(err, IsNil)
+	data, err := ioutil.ReadAll(r)
+	r.Close()
+	c.Assert(err, IsNil)
+	c.Check(string(data), Equals, "data")
+
+	c.Assert(t.Remove("backup.tgz"), IsNil)
+	files, err = t.List()
+	c.Assert(err, IsNil)
+	c.Check(files, HasLen, 0)
+}
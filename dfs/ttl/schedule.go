@@ -0,0 +1,210 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ttl
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/schedule"
+)
+
+// ScheduleInterface is the client handler for ScheduleRunner
+type ScheduleInterface interface {
+	// GetSchedules returns all snapshot and backup schedules
+	GetSchedules() ([]schedule.Schedule, error)
+	// SetScheduleStatus records the outcome of a run of a schedule
+	SetScheduleStatus(scheduleID string, status schedule.Status) error
+	// Snapshot takes a snapshot of a tenant
+	Snapshot(dao.SnapshotRequest, *string) error
+	// ListSnapshots returns the list of all snapshots given a service id
+	ListSnapshots(string, *[]dao.SnapshotInfo) error
+	// DeleteSnapshot deletes a snapshot by SnapshotID
+	DeleteSnapshot(string, *int) error
+	// Backup takes a backup and returns the name of the backup file
+	Backup(dao.BackupRequest, *string) error
+	// ListBackups returns the backup files in a directory
+	ListBackups(string, *[]dao.BackupFile) error
+	// DeleteBackup deletes a backup file by its path
+	DeleteBackup(string) error
+}
+
+// ScheduleRunner takes the snapshots and backups of the schedules that are
+// due, and removes the ones that fall out of their retention policies
+type ScheduleRunner struct {
+	client               ScheduleInterface
+	snapshotSpacePercent int
+}
+
+// NewScheduleRunner returns a runner of the schedules
+func NewScheduleRunner(client ScheduleInterface, snapshotSpacePercent int) *ScheduleRunner {
+	return &ScheduleRunner{client: client, snapshotSpacePercent: snapshotSpacePercent}
+}
+
+// RunSchedules checks for schedules that are due at every interval until it
+// is cancelled
+func RunSchedules(client ScheduleInterface, cancel <-chan interface{}, interval time.Duration, snapshotSpacePercent int) {
+	runner := NewScheduleRunner(client, snapshotSpacePercent)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			runner.RunDue(now)
+		case <-cancel:
+			return
+		}
+	}
+}
+
+// RunDue runs each enabled schedule whose next run is not after now.  Runs
+// that were missed while there was no leader are made up by a single run.
+func (r *ScheduleRunner) RunDue(now time.Time) {
+	schedules, err := r.client.GetSchedules()
+	if err != nil {
+		plog.WithError(err).Error("Could not look up schedules")
+		return
+	}
+	for i := range schedules {
+		s := &schedules[i]
+		if s.Disabled {
+			continue
+		}
+		logger := plog.WithFields(log.Fields{
+			"scheduleid": s.ID,
+			"kind":       s.Kind,
+		})
+		switch {
+		case s.Status.NextRun.IsZero():
+			// not scheduled yet, so wait for its next time
+		case now.Before(s.Status.NextRun):
+			continue
+		default:
+			r.run(s, now)
+		}
+		if err := s.UpdateNextRun(now); err != nil {
+			logger.WithError(err).Warn("Could not schedule the next run")
+			continue
+		}
+		if err := r.client.SetScheduleStatus(s.ID, s.Status); err != nil {
+			logger.WithError(err).Error("Could not update the status of the schedule")
+		}
+	}
+}
+
+// run takes the snapshot or backup of a schedule and applies its retention
+// policy
+func (r *ScheduleRunner) run(s *schedule.Schedule, now time.Time) {
+	logger := plog.WithFields(log.Fields{
+		"scheduleid": s.ID,
+		"kind":       s.Kind,
+	})
+	var id string
+	var err error
+	switch s.Kind {
+	case schedule.KindSnapshot:
+		req := dao.SnapshotRequest{
+			ServiceID:            s.TenantID,
+			Message:              fmt.Sprintf("scheduled snapshot (%s)", s.ID),
+			Tag:                  s.SnapshotTag(now),
+			SnapshotSpacePercent: r.snapshotSpacePercent,
+		}
+		err = r.client.Snapshot(req, &id)
+	case schedule.KindBackup:
+		req := dao.BackupRequest{
+			Dirpath:              s.Target,
+			SnapshotSpacePercent: r.snapshotSpacePercent,
+			TenantID:             s.TenantID,
+		}
+		err = r.client.Backup(req, &id)
+	default:
+		err = fmt.Errorf("invalid schedule kind %q", s.Kind)
+	}
+
+	s.Status.LastRun = now
+	if err != nil {
+		logger.WithError(err).Warn("Scheduled run failed")
+		s.Status.LastOutcome = schedule.OutcomeFailed
+		s.Status.LastError = err.Error()
+	} else {
+		logger.WithField("id", id).Info("Scheduled run succeeded")
+		s.Status.LastOutcome = schedule.OutcomeSucceeded
+		s.Status.LastError = ""
+		s.Status.Artifacts = append(s.Status.Artifacts, schedule.Artifact{ID: id, Created: now})
+	}
+	r.expire(s)
+}
+
+// expire removes the artifacts of a schedule that its retention policy does
+// not keep.  Artifacts that were already removed by hand are forgotten.
+func (r *ScheduleRunner) expire(s *schedule.Schedule) {
+	logger := plog.WithField("scheduleid", s.ID)
+	existing, err := r.listArtifacts(s)
+	if err != nil {
+		logger.WithError(err).Warn("Could not look up the snapshots or backups of the schedule")
+		return
+	}
+	artifacts := []schedule.Artifact{}
+	for _, a := range s.Status.Artifacts {
+		if _, ok := existing[a.ID]; ok {
+			artifacts = append(artifacts, a)
+		}
+	}
+	removed := make(map[string]bool)
+	for _, a := range s.Retention.Expired(artifacts) {
+		if s.Kind == schedule.KindSnapshot {
+			err = r.client.DeleteSnapshot(a.ID, nil)
+		} else {
+			err = r.client.DeleteBackup(existing[a.ID])
+		}
+		if err != nil {
+			logger.WithError(err).WithField("id", a.ID).Warn("Could not remove expired snapshot or backup")
+			continue
+		}
+		logger.WithField("id", a.ID).Debug("Removed expired snapshot or backup")
+		removed[a.ID] = true
+	}
+	s.Status.Artifacts = []schedule.Artifact{}
+	for _, a := range artifacts {
+		if !removed[a.ID] {
+			s.Status.Artifacts = append(s.Status.Artifacts, a)
+		}
+	}
+}
+
+// listArtifacts returns the snapshots or backups that exist for the
+// schedule, mapped to the path used to delete them
+func (r *ScheduleRunner) listArtifacts(s *schedule.Schedule) (map[string]string, error) {
+	existing := make(map[string]string)
+	if s.Kind == schedule.KindSnapshot {
+		var snapshots []dao.SnapshotInfo
+		if err := r.client.ListSnapshots(s.TenantID, &snapshots); err != nil {
+			return nil, err
+		}
+		for _, snapshot := range snapshots {
+			existing[snapshot.SnapshotID] = snapshot.SnapshotID
+		}
+		return existing, nil
+	}
+	var files []dao.BackupFile
+	if err := r.client.ListBackups(s.Target, &files); err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		existing[file.Name] = file.FullPath
+	}
+	return existing, nil
+}
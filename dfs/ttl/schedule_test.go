@@ -0,0 +1,265 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package ttl
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/schedule"
+)
+
+var _ = Suite(&ScheduleTestSuite{})
+
+type ScheduleTestSuite struct{}
+
+type TestScheduleInterface struct {
+	schedules  []schedule.Schedule
+	statuses   map[string]schedule.Status
+	snapshots  []dao.SnapshotInfo
+	backups    []dao.BackupFile
+	requests   []interface{}
+	taken      int
+	backupErr  error
+	listFailed bool
+}
+
+func (iface *TestScheduleInterface) GetSchedules() ([]schedule.Schedule, error) {
+	return iface.schedules, nil
+}
+
+func (iface *TestScheduleInterface) SetScheduleStatus(scheduleID string, status schedule.Status) error {
+	iface.statuses[scheduleID] = status
+	return nil
+}
+
+func (iface *TestScheduleInterface) Snapshot(req dao.SnapshotRequest, snapshotID *string) error {
+	iface.requests = append(iface.requests, req)
+	*snapshotID = fmt.Sprintf("%s_%d", req.ServiceID, iface.taken)
+	iface.taken++
+	iface.snapshots = append(iface.snapshots, dao.SnapshotInfo{SnapshotID: *snapshotID, TenantID: req.ServiceID, Tags: []string{req.Tag}})
+	return nil
+}
+
+func (iface *TestScheduleInterface) ListSnapshots(tenantID string, snaps *[]dao.SnapshotInfo) error {
+	if iface.listFailed {
+		return errors.New("error")
+	}
+	*snaps = iface.snapshots
+	return nil
+}
+
+func (iface *TestScheduleInterface) DeleteSnapshot(snapshotID string, _ *int) error {
+	for i, snap := range iface.snapshots {
+		if snap.SnapshotID == snapshotID {
+			iface.snapshots = append(iface.snapshots[:i], iface.snapshots[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("snapshot not found")
+}
+
+func (iface *TestScheduleInterface) Backup(req dao.BackupRequest, filename *string) error {
+	iface.requests = append(iface.requests, req)
+	if iface.backupErr != nil {
+		return iface.backupErr
+	}
+	*filename = fmt.Sprintf("backup-%d.tgz", iface.taken)
+	iface.taken++
+	iface.backups = append(iface.backups, dao.BackupFile{Name: *filename, FullPath: "s3://bucket/" + *filename})
+	return nil
+}
+
+func (iface *TestScheduleInterface) ListBackups(dirpath string, files *[]dao.BackupFile) error {
+	*files = iface.backups
+	return nil
+}
+
+func (iface *TestScheduleInterface) DeleteBackup(filename string) error {
+	for i, file := range iface.backups {
+		if file.FullPath == filename {
+			iface.backups = append(iface.backups[:i], iface.backups[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("backup not found")
+}
+
+func newTestScheduleInterface(schedules ...schedule.Schedule) *TestScheduleInterface {
+	return &TestScheduleInterface{schedules: schedules, statuses: make(map[string]schedule.Status)}
+}
+
+// runDaily runs the schedules at 02:00 on each of a number of days, feeding
+// each status back into the next run
+func runDaily(iface *TestScheduleInterface, start time.Time, days int) {
+	runner := NewScheduleRunner(iface, 10)
+	for i := 0; i < days; i++ {
+		runner.RunDue(start.AddDate(0, 0, i))
+		for j := range iface.schedules {
+			iface.schedules[j].Status = iface.statuses[iface.schedules[j].ID]
+		}
+	}
+}
+
+func (s *ScheduleTestSuite) TestRunDue_NotDue(c *C) {
+	now := time.Date(2019, 3, 1, 1, 0, 0, 0, time.UTC)
+	iface := newTestScheduleInterface(schedule.Schedule{
+		ID: "s1", Kind: schedule.KindSnapshot, TenantID: "tenant", Cron: "0 2 * * *",
+		Status: schedule.Status{NextRun: now.Add(time.Hour)},
+	})
+	NewScheduleRunner(iface, 10).RunDue(now)
+	c.Assert(iface.requests, HasLen, 0)
+	c.Assert(iface.statuses, HasLen, 0)
+}
+
+func (s *ScheduleTestSuite) TestRunDue_Disabled(c *C) {
+	now := time.Date(2019, 3, 1, 2, 0, 0, 0, time.UTC)
+	iface := newTestScheduleInterface(schedule.Schedule{
+		ID: "s1", Kind: schedule.KindSnapshot, TenantID: "tenant", Cron: "0 2 * * *", Disabled: true,
+		Status: schedule.Status{NextRun: now},
+	})
+	NewScheduleRunner(iface, 10).RunDue(now)
+	c.Assert(iface.requests, HasLen, 0)
+}
+
+func (s *ScheduleTestSuite) TestRunDue_Snapshot(c *C) {
+	now := time.Date(2019, 3, 1, 2, 0, 30, 0, time.UTC)
+	iface := newTestScheduleInterface(schedule.Schedule{
+		ID: "s1", Kind: schedule.KindSnapshot, TenantID: "tenant", Cron: "0 2 * * *",
+		Status: schedule.Status{NextRun: now.Truncate(time.Minute)},
+	})
+	NewScheduleRunner(iface, 10).RunDue(now)
+	c.Assert(iface.requests, DeepEquals, []interface{}{dao.SnapshotRequest{
+		ServiceID:            "tenant",
+		Message:              "scheduled snapshot (s1)",
+		Tag:                  "sched-s1-20190301-020030",
+		SnapshotSpacePercent: 10,
+	}})
+	status := iface.statuses["s1"]
+	c.Assert(status.LastRun, Equals, now)
+	c.Assert(status.LastOutcome, Equals, schedule.OutcomeSucceeded)
+	c.Assert(status.NextRun, Equals, time.Date(2019, 3, 2, 2, 0, 0, 0, time.UTC))
+	c.Assert(status.Artifacts, DeepEquals, []schedule.Artifact{{ID: "tenant_0", Created: now}})
+}
+
+func (s *ScheduleTestSuite) TestRunDue_SnapshotRetention(c *C) {
+	start := time.Date(2019, 3, 1, 2, 0, 0, 0, time.UTC)
+	iface := newTestScheduleInterface(schedule.Schedule{
+		ID: "s1", Kind: schedule.KindSnapshot, TenantID: "tenant", Cron: "0 2 * * *",
+		Retention: schedule.Retention{Daily: 3},
+		Status:    schedule.Status{NextRun: start},
+	})
+	// a snapshot that was not taken by the schedule is left alone
+	iface.snapshots = []dao.SnapshotInfo{{SnapshotID: "manual", TenantID: "tenant"}}
+	runDaily(iface, start, 5)
+
+	ids := []string{}
+	for _, snapshot := range iface.snapshots {
+		ids = append(ids, snapshot.SnapshotID)
+	}
+	c.Assert(ids, DeepEquals, []string{"manual", "tenant_2", "tenant_3", "tenant_4"})
+	c.Assert(iface.statuses["s1"].Artifacts, HasLen, 3)
+}
+
+func (s *ScheduleTestSuite) TestRunDue_BackupRetention(c *C) {
+	start := time.Date(2019, 3, 1, 2, 0, 0, 0, time.UTC)
+	iface := newTestScheduleInterface(schedule.Schedule{
+		ID: "s1", Kind: schedule.KindBackup, Cron: "0 2 * * *", Target: "s3://bucket",
+		Retention: schedule.Retention{Daily: 2, Weekly: 1},
+		Status:    schedule.Status{NextRun: start},
+	})
+	runDaily(iface, start, 4)
+
+	c.Assert(iface.requests[0], DeepEquals, dao.BackupRequest{Dirpath: "s3://bucket", SnapshotSpacePercent: 10})
+	names := []string{}
+	for _, file := range iface.backups {
+		names = append(names, file.Name)
+	}
+	// the newest backup of the week is also kept by the daily rule
+	c.Assert(names, DeepEquals, []string{"backup-2.tgz", "backup-3.tgz"})
+}
+
+func (s *ScheduleTestSuite) TestRunDue_ForgetsRemovedArtifacts(c *C) {
+	start := time.Date(2019, 3, 1, 2, 0, 0, 0, time.UTC)
+	iface := newTestScheduleInterface(schedule.Schedule{
+		ID: "s1", Kind: schedule.KindBackup, Cron: "0 2 * * *",
+		Retention: schedule.Retention{Daily: 5},
+		Status:    schedule.Status{NextRun: start},
+	})
+	runDaily(iface, start, 2)
+	iface.backups = iface.backups[1:]
+	runDaily(iface, start.AddDate(0, 0, 2), 1)
+
+	artifacts := iface.statuses["s1"].Artifacts
+	c.Assert(artifacts, HasLen, 2)
+	c.Assert(artifacts[0].ID, Equals, "backup-1.tgz")
+	c.Assert(artifacts[1].ID, Equals, "backup-2.tgz")
+}
+
+func (s *ScheduleTestSuite) TestRunDue_ListFailed(c *C) {
+	start := time.Date(2019, 3, 1, 2, 0, 0, 0, time.UTC)
+	iface := newTestScheduleInterface(schedule.Schedule{
+		ID: "s1", Kind: schedule.KindSnapshot, TenantID: "tenant", Cron: "0 2 * * *",
+		Retention: schedule.Retention{Daily: 1},
+		Status:    schedule.Status{NextRun: start},
+	})
+	iface.listFailed = true
+	runDaily(iface, start, 2)
+	c.Assert(iface.snapshots, HasLen, 2)
+	c.Assert(iface.statuses["s1"].Artifacts, HasLen, 2)
+}
+
+func (s *ScheduleTestSuite) TestRunDue_Failed(c *C) {
+	now := time.Date(2019, 3, 1, 2, 0, 0, 0, time.UTC)
+	iface := newTestScheduleInterface(schedule.Schedule{
+		ID: "s1", Kind: schedule.KindBackup, Cron: "0 2 * * *",
+		Status: schedule.Status{NextRun: now.Add(-24 * time.Hour)},
+	})
+	iface.backupErr = errors.New("not enough space")
+	NewScheduleRunner(iface, 10).RunDue(now)
+
+	status := iface.statuses["s1"]
+	c.Assert(status.LastOutcome, Equals, schedule.OutcomeFailed)
+	c.Assert(status.LastError, Equals, "not enough space")
+	c.Assert(status.NextRun, Equals, now.Add(24*time.Hour))
+	c.Assert(status.Artifacts, HasLen, 0)
+}
+
+func (s *ScheduleTestSuite) TestRunDue_BackupTenant(c *C) {
+	now := time.Date(2019, 3, 1, 2, 0, 0, 0, time.UTC)
+	iface := newTestScheduleInterface(schedule.Schedule{
+		ID: "s1", Kind: schedule.KindBackup, TenantID: "tenant", Cron: "0 2 * * *",
+		Status: schedule.Status{NextRun: now.Add(-24 * time.Hour)},
+	})
+	NewScheduleRunner(iface, 10).RunDue(now)
+
+	c.Assert(iface.requests, HasLen, 1)
+	req := iface.requests[0].(dao.BackupRequest)
+	c.Assert(req.TenantID, Equals, "tenant")
+	c.Assert(iface.statuses["s1"].LastOutcome, Equals, schedule.OutcomeSucceeded)
+}
+
+func (s *ScheduleTestSuite) TestRunDue_NotScheduled(c *C) {
+	now := time.Date(2019, 3, 1, 2, 0, 0, 0, time.UTC)
+	iface := newTestScheduleInterface(schedule.Schedule{ID: "s1", Kind: schedule.KindBackup, Cron: "0 2 * * *"})
+	NewScheduleRunner(iface, 10).RunDue(now)
+	c.Assert(iface.requests, HasLen, 0)
+	c.Assert(iface.statuses["s1"].NextRun, Equals, now.Add(24*time.Hour))
+}
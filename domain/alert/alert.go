@@ -0,0 +1,102 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alert
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+)
+
+// ErrAlertResolved is returned when acknowledging an alert that is resolved
+var ErrAlertResolved = errors.New("alert is already resolved")
+
+// State is the lifecycle state of an alert
+type State string
+
+const (
+	// StateOpen is an alert whose threshold is still breached
+	StateOpen State = "open"
+	// StateAcknowledged is an open alert that a user has taken ownership of
+	StateAcknowledged State = "acknowledged"
+	// StateResolved is an alert whose threshold is no longer breached
+	StateResolved State = "resolved"
+)
+
+// Alert is raised when a data point of a service breaches a threshold in its
+// monitoring profile, and stays active until the threshold is satisfied again
+type Alert struct {
+	ID             string
+	ServiceID      string
+	TenantID       string
+	ThresholdID    string // ID of the ThresholdConfig that was breached
+	ThresholdName  string
+	MetricSource   string // ID of the MetricConfig the threshold applies to
+	DataPoint      string // ID of the metric that breached the threshold
+	Value          float64
+	Message        string
+	State          State
+	Opened         time.Time
+	Acknowledged   time.Time `json:",omitempty"`
+	AcknowledgedBy string    `json:",omitempty"`
+	Resolved       time.Time `json:",omitempty"`
+	datastore.VersionedEntity
+}
+
+// Active returns true if the alert has not been resolved
+func (a *Alert) Active() bool {
+	return a.State != StateResolved
+}
+
+// Matches returns true if the alert was raised for the same service,
+// threshold and data point as other
+func (a *Alert) Matches(other *Alert) bool {
+	return a.ServiceID == other.ServiceID &&
+		a.ThresholdID == other.ThresholdID &&
+		a.MetricSource == other.MetricSource &&
+		a.DataPoint == other.DataPoint
+}
+
+// Acknowledge records that a user has taken ownership of an active alert
+func (a *Alert) Acknowledge(user string, now time.Time) error {
+	if !a.Active() {
+		return ErrAlertResolved
+	}
+	a.State = StateAcknowledged
+	a.Acknowledged = now
+	a.AcknowledgedBy = user
+	return nil
+}
+
+// Resolve closes the alert
+func (a *Alert) Resolve(now time.Time) {
+	a.State = StateResolved
+	a.Resolved = now
+}
+
+// GetType returns the type of alerts in the datastore
+func GetType() string {
+	return kind
+}
+
+// GetType returns the Alert's type
+func (a *Alert) GetType() string {
+	return GetType()
+}
+
+// GetID returns the Alert's ID
+func (a *Alert) GetID() string {
+	return a.ID
+}
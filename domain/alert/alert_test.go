@@ -0,0 +1,63 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package alert
+
+import (
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// This plumbs gocheck into testing
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type alertSuite struct{}
+
+var _ = Suite(&alertSuite{})
+
+func (s *alertSuite) TestAlert_Lifecycle(c *C) {
+	now := time.Now()
+	a := &Alert{ID: "a1", ServiceID: "svc", ThresholdID: "th", State: StateOpen, Opened: now}
+	c.Assert(a.ValidEntity(), IsNil)
+	c.Assert(a.Active(), Equals, true)
+
+	c.Assert(a.Acknowledge("jdoe", now), IsNil)
+	c.Assert(a.State, Equals, StateAcknowledged)
+	c.Assert(a.AcknowledgedBy, Equals, "jdoe")
+	c.Assert(a.Active(), Equals, true)
+
+	a.Resolve(now)
+	c.Assert(a.State, Equals, StateResolved)
+	c.Assert(a.Active(), Equals, false)
+	c.Assert(a.Acknowledge("jdoe", now), Equals, ErrAlertResolved)
+}
+
+func (s *alertSuite) TestAlert_Matches(c *C) {
+	a := &Alert{ServiceID: "svc", ThresholdID: "th", MetricSource: "mem", DataPoint: "rss"}
+	b := *a
+	b.ID = "other"
+	c.Assert(a.Matches(&b), Equals, true)
+	b.DataPoint = "cache"
+	c.Assert(a.Matches(&b), Equals, false)
+}
+
+func (s *alertSuite) TestAlert_ValidEntity(c *C) {
+	a := &Alert{ID: "a1", ServiceID: "svc", ThresholdID: "th", State: "bogus"}
+	c.Assert(a.ValidEntity(), NotNil)
+}
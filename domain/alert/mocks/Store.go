@@ -0,0 +1,94 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocks
+
+import (
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/alert"
+	"github.com/stretchr/testify/mock"
+)
+
+type Store struct {
+	mock.Mock
+}
+
+func (_m *Store) Get(ctx datastore.Context, id string) (*alert.Alert, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *alert.Alert
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *alert.Alert); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*alert.Alert)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+func (_m *Store) Put(ctx datastore.Context, a *alert.Alert) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, *alert.Alert) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+func (_m *Store) Delete(ctx datastore.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+func (_m *Store) GetAlerts(ctx datastore.Context, states ...alert.State) ([]alert.Alert, error) {
+	ret := _m.Called(ctx, states)
+
+	var r0 []alert.Alert
+	if rf, ok := ret.Get(0).(func(datastore.Context, ...alert.State) []alert.Alert); ok {
+		r0 = rf(ctx, states...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]alert.Alert)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, ...alert.State) error); ok {
+		r1 = rf(ctx, states...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
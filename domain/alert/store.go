@@ -0,0 +1,104 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alert
+
+import (
+	"strings"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+// Store is the database for alerts
+type Store interface {
+	// Get an Alert by id. Return ErrNoSuchEntity if not found
+	Get(ctx datastore.Context, id string) (*Alert, error)
+
+	// Put adds or updates an Alert
+	Put(ctx datastore.Context, a *Alert) error
+
+	// Delete removes an Alert if it exists
+	Delete(ctx datastore.Context, id string) error
+
+	// GetAlerts returns the alerts in any of the given states, or all alerts
+	// if no state is given
+	GetAlerts(ctx datastore.Context, states ...State) ([]Alert, error)
+}
+
+type storeImpl struct {
+	ds datastore.DataStore
+}
+
+// NewStore creates a Store for alerts
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Get an Alert by id.  Return ErrNoSuchEntity if not found
+func (s *storeImpl) Get(ctx datastore.Context, id string) (*Alert, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("AlertStore.Get"))
+	val := &Alert{}
+	if err := s.ds.Get(ctx, Key(id), val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// Put adds/updates an Alert
+func (s *storeImpl) Put(ctx datastore.Context, a *Alert) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("AlertStore.Put"))
+	return s.ds.Put(ctx, Key(a.ID), a)
+}
+
+// Delete removes an Alert
+func (s *storeImpl) Delete(ctx datastore.Context, id string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("AlertStore.Delete"))
+	return s.ds.Delete(ctx, Key(id))
+}
+
+// GetAlerts returns the alerts in any of the given states
+func (s *storeImpl) GetAlerts(ctx datastore.Context, states ...State) ([]Alert, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("AlertStore.GetAlerts"))
+	query := search.Search("controlplane").Type(kind).Size("50000")
+	if len(states) > 0 {
+		terms := make([]interface{}, len(states))
+		for i, state := range states {
+			terms[i] = string(state)
+		}
+		query = query.Filter(search.Filter().Terms("State", terms...))
+	} else {
+		query = query.Query(search.Query().Search("_exists_:ServiceID"))
+	}
+	q := datastore.NewQuery(ctx)
+	results, err := q.Execute(query)
+	if err != nil {
+		return nil, err
+	}
+	return convert(results)
+}
+
+// Key creates a Key suitable for getting, putting and deleting Alerts
+func Key(id string) datastore.Key {
+	return datastore.NewKey(kind, strings.TrimSpace(id))
+}
+
+func convert(results datastore.Results) ([]Alert, error) {
+	alerts := make([]Alert, results.Len())
+	for idx := range alerts {
+		if err := results.Get(idx, &alerts[idx]); err != nil {
+			return nil, err
+		}
+	}
+	return alerts, nil
+}
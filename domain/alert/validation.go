@@ -0,0 +1,38 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alert
+
+import (
+	"fmt"
+
+	"github.com/control-center/serviced/validation"
+)
+
+// ValidEntity validates Alert fields
+func (a *Alert) ValidEntity() error {
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("Alert.ID", a.ID))
+	violations.Add(validation.NotEmpty("Alert.ServiceID", a.ServiceID))
+	violations.Add(validation.NotEmpty("Alert.ThresholdID", a.ThresholdID))
+	switch a.State {
+	case StateOpen, StateAcknowledged, StateResolved:
+	default:
+		violations.AddViolation(fmt.Sprintf("invalid alert state %q", a.State))
+	}
+
+	if len(violations.Errors) > 0 {
+		return violations
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auditlog
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+)
+
+// Event is an audit event stored in the datastore
+type Event struct {
+	ID string
+	audit.Event
+	datastore.VersionedEntity
+}
+
+// Filter selects the audit events to return.  Empty fields match any event.
+type Filter struct {
+	User     string
+	Type     string
+	EntityID string
+	Action   string
+	Since    time.Time // Only events at or after this time
+	Until    time.Time // Only events at or before this time
+	Success  *bool     // Only successful (true) or failed (false) events
+	Limit    int       // Maximum number of events, newest first; 0 for all
+}
+
+// ByTime sorts events from the newest to the oldest
+type ByTime []Event
+
+func (s ByTime) Len() int           { return len(s) }
+func (s ByTime) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s ByTime) Less(i, j int) bool { return s[i].Time.After(s[j].Time) }
+
+// GetType returns the type of audit events in the datastore
+func GetType() string {
+	return kind
+}
+
+// GetType returns the Event's type
+func (e *Event) GetType() string {
+	return GetType()
+}
+
+// GetID returns the Event's ID
+func (e *Event) GetID() string {
+	return e.ID
+}
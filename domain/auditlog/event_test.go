@@ -0,0 +1,66 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package auditlog
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/audit"
+	. "gopkg.in/check.v1"
+)
+
+// This plumbs gocheck into testing
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type auditlogSuite struct{}
+
+var _ = Suite(&auditlogSuite{})
+
+func (s *auditlogSuite) TestEvent_ValidEntity(c *C) {
+	e := &Event{}
+	c.Assert(e.ValidEntity(), NotNil)
+	e.ID = "e1"
+	c.Assert(e.ValidEntity(), NotNil)
+	e.Time = time.Now()
+	c.Assert(e.ValidEntity(), IsNil)
+}
+
+func (s *auditlogSuite) TestEvent_JSON(c *C) {
+	e := Event{ID: "e1", Event: audit.Event{Action: audit.Stop, EntityID: "svc1"}}
+	data, err := json.Marshal(e)
+	c.Assert(err, IsNil)
+	var fields map[string]interface{}
+	c.Assert(json.Unmarshal(data, &fields), IsNil)
+	c.Assert(fields["ID"], Equals, "e1")
+	c.Assert(fields["EntityID"], Equals, "svc1")
+	c.Assert(fields["Action"], Equals, audit.Stop)
+}
+
+func (s *auditlogSuite) TestByTime(c *C) {
+	t0 := time.Now()
+	events := []Event{
+		{ID: "old", Event: audit.Event{Time: t0}},
+		{ID: "new", Event: audit.Event{Time: t0.Add(time.Minute)}},
+	}
+	sort.Sort(ByTime(events))
+	c.Assert(events[0].ID, Equals, "new")
+	c.Assert(events[1].ID, Equals, "old")
+}
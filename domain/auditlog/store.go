@@ -0,0 +1,177 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auditlog
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+const (
+	// maxEvents is the number of events GetEvents returns without a limit
+	maxEvents = 50000
+
+	// deleteBatchSize is the number of events DeleteBefore removes per query
+	deleteBatchSize = 1000
+)
+
+// Store is the database for audit events
+type Store interface {
+	// Get an Event by id. Return ErrNoSuchEntity if not found
+	Get(ctx datastore.Context, id string) (*Event, error)
+
+	// Put adds or updates an Event
+	Put(ctx datastore.Context, e *Event) error
+
+	// Delete removes an Event if it exists
+	Delete(ctx datastore.Context, id string) error
+
+	// GetEvents returns the events selected by the filter, newest first
+	GetEvents(ctx datastore.Context, filter Filter) ([]Event, error)
+
+	// DeleteBefore removes the events recorded before the given time and
+	// returns the number of events removed
+	DeleteBefore(ctx datastore.Context, before time.Time) (int, error)
+}
+
+type storeImpl struct {
+	ds datastore.DataStore
+}
+
+// NewStore creates a Store for audit events
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Get an Event by id.  Return ErrNoSuchEntity if not found
+func (s *storeImpl) Get(ctx datastore.Context, id string) (*Event, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("AuditEventStore.Get"))
+	val := &Event{}
+	if err := s.ds.Get(ctx, Key(id), val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// Put adds/updates an Event
+func (s *storeImpl) Put(ctx datastore.Context, e *Event) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("AuditEventStore.Put"))
+	return s.ds.Put(ctx, Key(e.ID), e)
+}
+
+// Delete removes an Event
+func (s *storeImpl) Delete(ctx datastore.Context, id string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("AuditEventStore.Delete"))
+	return s.ds.Delete(ctx, Key(id))
+}
+
+// GetEvents returns the events selected by the filter, newest first
+func (s *storeImpl) GetEvents(ctx datastore.Context, filter Filter) ([]Event, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("AuditEventStore.GetEvents"))
+	var filters []interface{}
+	for field, value := range map[string]string{
+		"User":     filter.User,
+		"Type":     filter.Type,
+		"EntityID": filter.EntityID,
+		"Action":   filter.Action,
+	} {
+		if value != "" {
+			filters = append(filters, search.Filter().Terms(field, value))
+		}
+	}
+	if filter.Success != nil {
+		filters = append(filters, search.Filter().Terms("Success", *filter.Success))
+	}
+	if !filter.Since.IsZero() || !filter.Until.IsZero() {
+		r := search.Range().Field("Time")
+		if !filter.Since.IsZero() {
+			r = r.From(filter.Since.UTC().Format(time.RFC3339Nano))
+		}
+		if !filter.Until.IsZero() {
+			r = r.To(filter.Until.UTC().Format(time.RFC3339Nano))
+		}
+		filters = append(filters, r)
+	}
+
+	size := maxEvents
+	if filter.Limit > 0 && filter.Limit < size {
+		size = filter.Limit
+	}
+	return s.query(ctx, size, filters...)
+}
+
+// DeleteBefore removes the events recorded before the given time, a batch at
+// a time
+func (s *storeImpl) DeleteBefore(ctx datastore.Context, before time.Time) (int, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("AuditEventStore.DeleteBefore"))
+	older := search.Range().Field("Time")
+	older.Range["Time"]["lt"] = before.UTC().Format(time.RFC3339Nano)
+	count := 0
+	for {
+		events, err := s.query(ctx, deleteBatchSize, older)
+		if err != nil {
+			return count, err
+		}
+		deleted := 0
+		for _, e := range events {
+			if err := s.Delete(ctx, e.ID); datastore.IsErrNoSuchEntity(err) {
+				continue
+			} else if err != nil {
+				return count, err
+			}
+			deleted++
+		}
+		count += deleted
+		// stop when the last batch was partial, or when none of it could be
+		// removed, so a stale index cannot keep the loop going
+		if len(events) < deleteBatchSize || deleted == 0 {
+			return count, nil
+		}
+	}
+}
+
+// query returns up to size events selected by the filters, newest first
+func (s *storeImpl) query(ctx datastore.Context, size int, filters ...interface{}) ([]Event, error) {
+	query := search.Search("controlplane").Type(kind).Size(strconv.Itoa(size)).Sort(search.Sort("Time").Desc())
+	if len(filters) > 0 {
+		query = query.Filter(append([]interface{}{"and"}, filters...)...)
+	} else {
+		query = query.Query(search.Query().Search("_exists_:ID"))
+	}
+	q := datastore.NewQuery(ctx)
+	results, err := q.Execute(query)
+	if err != nil {
+		return nil, err
+	}
+	return convert(results)
+}
+
+// Key creates a Key suitable for getting, putting and deleting Events
+func Key(id string) datastore.Key {
+	return datastore.NewKey(kind, strings.TrimSpace(id))
+}
+
+func convert(results datastore.Results) ([]Event, error) {
+	events := make([]Event, results.Len())
+	for idx := range events {
+		if err := results.Get(idx, &events[idx]); err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+)
+
+// ExpiryWarning is how long before it expires that a certificate is reported
+// in the host and pool status
+const ExpiryWarning = 30 * 24 * time.Hour
+
+// ErrNoCertificate is returned when the certificate PEM has no certificate
+var ErrNoCertificate = errors.New("no certificate found in PEM data")
+
+// Certificate is a TLS certificate and key served for a virtual host or a
+// public port.  The ID is the name the certificate is selected by: a vhost
+// name ("app"), a hostname ("app.example.com"), a wildcard hostname
+// ("*.example.com") or a port address (":9443").
+type Certificate struct {
+	ID        string
+	CertPEM   string
+	KeyPEM    string    `json:",omitempty"`
+	Subject   string    // common name of the leaf certificate
+	DNSNames  []string  // subject alternative names of the leaf certificate
+	NotBefore time.Time // start of the validity of the leaf certificate
+	NotAfter  time.Time // end of the validity of the leaf certificate
+	datastore.VersionedEntity
+}
+
+// Load checks that the key matches the certificate and fills in the subject
+// and validity of the certificate from its PEM data
+func (c *Certificate) Load() error {
+	cert, err := c.TLSCertificate()
+	if err != nil {
+		return err
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return err
+	}
+	c.Subject = leaf.Subject.CommonName
+	c.DNSNames = leaf.DNSNames
+	c.NotBefore = leaf.NotBefore
+	c.NotAfter = leaf.NotAfter
+	return nil
+}
+
+// TLSCertificate returns the certificate chain and key for serving
+func (c *Certificate) TLSCertificate() (*tls.Certificate, error) {
+	cert, err := tls.X509KeyPair([]byte(c.CertPEM), []byte(c.KeyPEM))
+	if err != nil {
+		return nil, err
+	}
+	if len(cert.Certificate) == 0 {
+		return nil, ErrNoCertificate
+	}
+	return &cert, nil
+}
+
+// WithoutKey returns a copy of the certificate without its private key, for
+// showing to clients
+func (c Certificate) WithoutKey() Certificate {
+	c.KeyPEM = ""
+	return c
+}
+
+// Warning returns a message if the certificate has expired or expires within
+// the warning period at a given time, otherwise it returns an empty string
+func (c *Certificate) Warning(now time.Time) string {
+	switch {
+	case !now.Before(c.NotAfter):
+		return fmt.Sprintf("certificate %s expired on %s", c.ID, c.NotAfter.UTC().Format("2006-01-02"))
+	case now.Add(ExpiryWarning).After(c.NotAfter):
+		return fmt.Sprintf("certificate %s expires on %s", c.ID, c.NotAfter.UTC().Format("2006-01-02"))
+	default:
+		return ""
+	}
+}
+
+// GetType returns the type of certificates in the datastore
+func GetType() string {
+	return kind
+}
+
+// GetType returns the Certificate's type
+func (c *Certificate) GetType() string {
+	return GetType()
+}
+
+// GetID returns the Certificate's ID
+func (c *Certificate) GetID() string {
+	return c.ID
+}
@@ -0,0 +1,143 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type certificateSuite struct{}
+
+var _ = Suite(&certificateSuite{})
+
+// selfSigned returns the PEM data of a self-signed certificate and its key
+func selfSigned(c *C, name string, notBefore, notAfter time.Time) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, IsNil)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	c.Assert(err, IsNil)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	c.Assert(err, IsNil)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func (s *certificateSuite) TestLoad(c *C) {
+	notBefore := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := notBefore.AddDate(1, 0, 0)
+	certPEM, keyPEM := selfSigned(c, "app.example.com", notBefore, notAfter)
+
+	cert := &Certificate{ID: "app.example.com", CertPEM: certPEM, KeyPEM: keyPEM}
+	c.Assert(cert.Load(), IsNil)
+	c.Check(cert.Subject, Equals, "app.example.com")
+	c.Check(cert.DNSNames, DeepEquals, []string{"app.example.com"})
+	c.Check(cert.NotBefore.Equal(notBefore), Equals, true)
+	c.Check(cert.NotAfter.Equal(notAfter), Equals, true)
+	c.Check(cert.ValidEntity(), IsNil)
+}
+
+func (s *certificateSuite) TestLoad_KeyMismatch(c *C) {
+	now := time.Now()
+	certPEM, _ := selfSigned(c, "a.example.com", now, now.Add(time.Hour))
+	_, keyPEM := selfSigned(c, "b.example.com", now, now.Add(time.Hour))
+
+	cert := &Certificate{ID: "a.example.com", CertPEM: certPEM, KeyPEM: keyPEM}
+	c.Assert(cert.Load(), NotNil)
+}
+
+func (s *certificateSuite) TestValidEntity(c *C) {
+	now := time.Now()
+	certPEM, keyPEM := selfSigned(c, "app", now, now.Add(time.Hour))
+	for _, tc := range []struct {
+		id    string
+		valid bool
+	}{
+		{"app", true},
+		{"app.example.com", true},
+		{"*.example.com", true},
+		{":9443", true},
+		{"10.0.0.1:9443", true},
+		{"", false},
+		{"app..example.com", false},
+		{"ex*mple.com", false},
+		{":http", false},
+		{":70000", false},
+	} {
+		cert := &Certificate{ID: tc.id, CertPEM: certPEM, KeyPEM: keyPEM}
+		c.Assert(cert.Load(), IsNil)
+		if tc.valid {
+			c.Check(cert.ValidEntity(), IsNil, Commentf("id %q", tc.id))
+		} else {
+			c.Check(cert.ValidEntity(), NotNil, Commentf("id %q", tc.id))
+		}
+	}
+}
+
+func (s *certificateSuite) TestWarning(c *C) {
+	now := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	cert := &Certificate{ID: "app", NotAfter: now.AddDate(0, 2, 0)}
+	c.Check(cert.Warning(now), Equals, "")
+
+	cert.NotAfter = now.AddDate(0, 0, 10)
+	c.Check(cert.Warning(now), Equals, "certificate app expires on 2019-06-11")
+
+	cert.NotAfter = now.AddDate(0, 0, -1)
+	c.Check(cert.Warning(now), Equals, "certificate app expired on 2019-05-31")
+}
+
+func (s *certificateSuite) TestFind(c *C) {
+	certs := []Certificate{
+		{ID: "app.example.com"},
+		{ID: "app"},
+		{ID: "*.example.com"},
+		{ID: ":9443"},
+		{ID: "10.0.0.1:8443"},
+	}
+	id := func(serverName, portAddr string) string {
+		if cert := Find(certs, serverName, portAddr); cert != nil {
+			return cert.ID
+		}
+		return ""
+	}
+	c.Check(id("APP.example.com.", ":443"), Equals, "app.example.com")
+	c.Check(id("app.cc.example.org", ":443"), Equals, "app")
+	c.Check(id("www.example.com", ":443"), Equals, "*.example.com")
+	c.Check(id("www.example.org", "0.0.0.0:9443"), Equals, ":9443")
+	c.Check(id("", "10.0.0.1:8443"), Equals, "10.0.0.1:8443")
+	c.Check(id("", "10.0.0.2:8443"), Equals, "")
+	c.Check(id("www.example.org", ":443"), Equals, "")
+}
@@ -0,0 +1,52 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"net"
+	"strings"
+)
+
+// Find returns the certificate to serve to a client that connected to a port
+// address and asked for a server name, or nil if none of the certificates
+// apply.  The most specific certificate wins: the full server name, then the
+// vhost name (its first label), then a wildcard of the server name and last
+// the port address.
+func Find(certs []Certificate, serverName, portAddr string) *Certificate {
+	byID := make(map[string]*Certificate, len(certs))
+	for i := range certs {
+		byID[certs[i].ID] = &certs[i]
+	}
+
+	var candidates []string
+	if name := Normalize(serverName); name != "" {
+		candidates = append(candidates, name)
+		if i := strings.Index(name, "."); i > 0 {
+			candidates = append(candidates, name[:i], "*"+name[i:])
+		}
+	}
+	if portAddr != "" {
+		candidates = append(candidates, portAddr)
+		if _, port, err := net.SplitHostPort(portAddr); err == nil {
+			candidates = append(candidates, ":"+port)
+		}
+	}
+
+	for _, id := range candidates {
+		if cert, ok := byID[id]; ok {
+			return cert
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"strings"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+// Store is the database for certificates
+type Store interface {
+	// Get a Certificate by id. Return ErrNoSuchEntity if not found
+	Get(ctx datastore.Context, id string) (*Certificate, error)
+
+	// Put adds or updates a Certificate
+	Put(ctx datastore.Context, cert *Certificate) error
+
+	// Delete removes a Certificate if it exists
+	Delete(ctx datastore.Context, id string) error
+
+	// GetCertificates returns all certificates
+	GetCertificates(ctx datastore.Context) ([]Certificate, error)
+}
+
+type storeImpl struct {
+	ds datastore.DataStore
+}
+
+// NewStore creates a Store for certificates
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Get a Certificate by id.  Return ErrNoSuchEntity if not found
+func (s *storeImpl) Get(ctx datastore.Context, id string) (*Certificate, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("CertificateStore.Get"))
+	val := &Certificate{}
+	if err := s.ds.Get(ctx, Key(id), val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// Put adds/updates a Certificate
+func (s *storeImpl) Put(ctx datastore.Context, cert *Certificate) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("CertificateStore.Put"))
+	return s.ds.Put(ctx, Key(cert.ID), cert)
+}
+
+// Delete removes a Certificate
+func (s *storeImpl) Delete(ctx datastore.Context, id string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("CertificateStore.Delete"))
+	return s.ds.Delete(ctx, Key(id))
+}
+
+// GetCertificates returns all certificates
+func (s *storeImpl) GetCertificates(ctx datastore.Context) ([]Certificate, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("CertificateStore.GetCertificates"))
+	query := search.Search("controlplane").Type(kind).Size("50000").
+		Query(search.Query().Search("_exists_:CertPEM"))
+	q := datastore.NewQuery(ctx)
+	results, err := q.Execute(query)
+	if err != nil {
+		return nil, err
+	}
+	return convert(results)
+}
+
+// Key creates a Key suitable for getting, putting and deleting Certificates
+func Key(id string) datastore.Key {
+	return datastore.NewKey(kind, strings.TrimSpace(id))
+}
+
+func convert(results datastore.Results) ([]Certificate, error) {
+	certificates := make([]Certificate, results.Len())
+	for idx := range certificates {
+		if err := results.Get(idx, &certificates[idx]); err != nil {
+			return nil, err
+		}
+	}
+	return certificates, nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package certificate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/control-center/serviced/validation"
+)
+
+var hostnamePattern = regexp.MustCompile(`^(\*\.)?[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*$`)
+
+// ValidEntity validates Certificate fields
+func (c *Certificate) ValidEntity() error {
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("Certificate.ID", c.ID))
+	if c.ID != "" {
+		if IsPortAddress(c.ID) {
+			violations.Add(validation.ValidUIAddress(c.ID))
+		} else if !hostnamePattern.MatchString(c.ID) {
+			violations.AddViolation(fmt.Sprintf("invalid certificate name %q; must be a hostname or a port address", c.ID))
+		}
+	}
+	violations.Add(validation.NotEmpty("Certificate.CertPEM", c.CertPEM))
+	violations.Add(validation.NotEmpty("Certificate.KeyPEM", c.KeyPEM))
+	if c.CertPEM != "" && c.KeyPEM != "" {
+		if _, err := c.TLSCertificate(); err != nil {
+			violations.AddViolation(fmt.Sprintf("invalid certificate or key: %s", err))
+		}
+	}
+	if !c.NotAfter.After(c.NotBefore) {
+		violations.AddViolation("certificate must expire after it becomes valid")
+	}
+
+	if len(violations.Errors) > 0 {
+		return violations
+	}
+	return nil
+}
+
+// IsPortAddress returns true if the certificate id names a public port
+func IsPortAddress(id string) bool {
+	return strings.Contains(id, ":")
+}
+
+// Normalize returns the certificate id of a hostname or port address
+func Normalize(id string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(id), "."))
+}
@@ -74,6 +74,7 @@ type Host struct {
 		Release   string
 	}
 	MonitoringProfile domain.MonitorProfile
+	Labels            map[string]string // Labels matched by the placement constraints of services
 	datastore.VersionedEntity
 	NatIP string
 }
@@ -106,6 +107,8 @@ type HostStatus struct {
 	MemoryUsage   service.Usage
 	Active        bool
 	Authenticated bool
+
+	CertificateWarnings []string `json:",omitempty"` // Certificates of the pool's public endpoints that have expired or expire soon
 }
 
 func (a *Host) TotalRAM() (mem uint64) {
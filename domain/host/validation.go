@@ -50,6 +50,9 @@ func (h *Host) ValidEntity() error {
 	} else if err != nil {
 		violations.Add(err)
 	}
+	for key, value := range h.Labels {
+		violations.Add(validation.ValidLabel(key, value))
+	}
 	if len(violations.Errors) > 0 {
 		return violations
 	}
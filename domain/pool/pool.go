@@ -57,9 +57,26 @@ type ResourcePool struct {
 	UpdatedAt         time.Time
 	MonitoringProfile domain.MonitorProfile
 	Permissions       Permission
+	Rebalance         RebalancePolicy // How instances are moved between the hosts of the pool
 	datastore.VersionedEntity
 }
 
+// RebalancePolicy controls how the pool leader moves running instances to the
+// hosts that the scheduler strategies would choose for them.
+type RebalancePolicy struct {
+	Automatic        bool // Rebalance periodically, not only on request
+	DisruptionBudget int  // Most instances of a service that may be unavailable while rebalancing, 0 = 1
+}
+
+// GetDisruptionBudget returns the number of instances of a service that may
+// be unavailable while the pool is rebalanced
+func (p RebalancePolicy) GetDisruptionBudget() int {
+	if p.DisruptionBudget <= 0 {
+		return 1
+	}
+	return p.DisruptionBudget
+}
+
 func (p ResourcePool) GetConnectionTimeout() time.Duration {
 	return time.Duration(p.ConnectionTimeout) * time.Millisecond
 }
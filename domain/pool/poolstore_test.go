@@ -107,6 +107,23 @@ func (s *S) Test_ValidateConnectionTimeout(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (s *S) Test_ValidateDisruptionBudget(c *C) {
+	defer s.ps.Delete(s.ctx, Key("Test_GetPools1"))
+	pool := New("Test_GetPools1")
+	pool.Realm = "test_realm1"
+	pool.Rebalance.DisruptionBudget = -1
+	err := s.ps.Put(s.ctx, Key(pool.ID), pool)
+	c.Assert(strings.Contains(err.Error(), "rebalance disruption budget cannot be less than 0"), Equals, true)
+
+	pool.Rebalance.DisruptionBudget = 2
+	err = s.ps.Put(s.ctx, Key(pool.ID), pool)
+	c.Assert(err, IsNil)
+	c.Assert(pool.Rebalance.GetDisruptionBudget(), Equals, 2)
+
+	pool.Rebalance.DisruptionBudget = 0
+	c.Assert(pool.Rebalance.GetDisruptionBudget(), Equals, 1)
+}
+
 func (s *S) Test_GetPools(t *C) {
 	defer s.ps.Delete(s.ctx, Key("Test_GetPools1"))
 	defer s.ps.Delete(s.ctx, Key("Test_GetPools2"))
@@ -27,4 +27,6 @@ type ReadPool struct {
 	CreatedAt         time.Time  // When the pool was created
 	UpdatedAt         time.Time  // When the poool was last updated
 	Permissions       Permission // A bitset of pemissions for this pool's hosts
+
+	CertificateWarnings []string `json:",omitempty"` // Certificates of the pool's public endpoints that have expired or expire soon
 }
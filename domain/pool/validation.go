@@ -37,6 +37,10 @@ func (p *ResourcePool) ValidEntity() error {
 		violations.Add(validation.NewViolation(fmt.Sprintf("connection timeout cannot be less than 0")))
 	}
 
+	if p.Rebalance.DisruptionBudget < 0 {
+		violations.Add(validation.NewViolation("rebalance disruption budget cannot be less than 0"))
+	}
+
 	if len(violations.Errors) > 0 {
 		return violations
 	}
@@ -0,0 +1,90 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// This plumbs gocheck into testing
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type scheduleSuite struct{}
+
+var _ = Suite(&scheduleSuite{})
+
+func at(value string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", value, time.UTC)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func (s *scheduleSuite) TestCron_Next(c *C) {
+	for _, tc := range []struct {
+		expr, from, next string
+	}{
+		{"* * * * *", "2019-03-01 10:15", "2019-03-01 10:16"},
+		{"30 2 * * *", "2019-03-01 10:15", "2019-03-02 02:30"},
+		{"@daily", "2019-03-01 00:00", "2019-03-02 00:00"},
+		{"@hourly", "2019-03-01 10:15", "2019-03-01 11:00"},
+		{"*/20 * * * *", "2019-03-01 10:15", "2019-03-01 10:20"},
+		{"5/20 * * * *", "2019-03-01 10:45", "2019-03-01 11:05"},
+		{"0 9-17/4 * * *", "2019-03-01 14:00", "2019-03-01 17:00"},
+		{"0 0 * * mon-fri", "2019-03-01 10:00", "2019-03-04 00:00"},
+		{"0 0 * * 7", "2019-03-01 10:00", "2019-03-03 00:00"},
+		{"0 0 1 jan,jul *", "2019-03-01 10:00", "2019-07-01 00:00"},
+		{"0 0 29 2 *", "2019-03-01 10:00", "2020-02-29 00:00"},
+		// either day field matches when neither is *
+		{"0 0 15 * sun", "2019-03-01 10:00", "2019-03-03 00:00"},
+		{"0 0 15 * sun", "2019-03-11 10:00", "2019-03-15 00:00"},
+	} {
+		cron, err := ParseCron(tc.expr)
+		c.Assert(err, IsNil, Commentf(tc.expr))
+		c.Check(cron.Next(at(tc.from)), Equals, at(tc.next), Commentf(tc.expr))
+	}
+}
+
+func (s *scheduleSuite) TestCron_NextNeverMatches(c *C) {
+	cron, err := ParseCron("0 0 30 2 *")
+	c.Assert(err, IsNil)
+	c.Assert(cron.Next(at("2019-03-01 10:00")).IsZero(), Equals, true)
+}
+
+func (s *scheduleSuite) TestParseCron_Invalid(c *C) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * foo *",
+		"@often",
+	} {
+		_, err := ParseCron(expr)
+		c.Check(err, NotNil, Commentf(expr))
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Artifact is a snapshot or backup file created by a schedule
+type Artifact struct {
+	ID      string // snapshot id or path of the backup file
+	Created time.Time
+}
+
+// Retention is a policy such as "keep 7 daily, 4 weekly, 6 monthly".  Each
+// rule keeps the newest artifact of each of that many of the most recent
+// periods that have an artifact.  An artifact is kept if any rule keeps it,
+// and a policy without rules keeps every artifact.
+type Retention struct {
+	Hourly  int
+	Daily   int
+	Weekly  int
+	Monthly int
+	Yearly  int
+}
+
+// newestFirst sorts artifacts from the newest to the oldest
+type newestFirst []Artifact
+
+func (a newestFirst) Len() int           { return len(a) }
+func (a newestFirst) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a newestFirst) Less(i, j int) bool { return a[i].Created.After(a[j].Created) }
+
+type retentionRule struct {
+	name   string
+	count  int
+	period func(time.Time) string
+}
+
+func (r Retention) rules() []retentionRule {
+	return []retentionRule{
+		{"hourly", r.Hourly, func(t time.Time) string { return t.Format("2006-01-02T15") }},
+		{"daily", r.Daily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{"weekly", r.Weekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+		{"monthly", r.Monthly, func(t time.Time) string { return t.Format("2006-01") }},
+		{"yearly", r.Yearly, func(t time.Time) string { return t.Format("2006") }},
+	}
+}
+
+// IsZero returns true if the policy has no rules
+func (r Retention) IsZero() bool {
+	return r == Retention{}
+}
+
+// String describes the policy, e.g. "7 daily, 4 weekly, 6 monthly"
+func (r Retention) String() string {
+	var parts []string
+	for _, rule := range r.rules() {
+		if rule.count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", rule.count, rule.name))
+		}
+	}
+	if len(parts) == 0 {
+		return "keep all"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Expired returns the artifacts that the policy does not keep, oldest first.
+// Periods are in the local time of the artifacts.
+func (r Retention) Expired(artifacts []Artifact) []Artifact {
+	if r.IsZero() {
+		return nil
+	}
+	sorted := make([]Artifact, len(artifacts))
+	copy(sorted, artifacts)
+	sort.Stable(newestFirst(sorted))
+
+	keep := make(map[string]bool)
+	for _, rule := range r.rules() {
+		periods := make(map[string]bool)
+		for _, a := range sorted {
+			if len(periods) >= rule.count {
+				break
+			}
+			if period := rule.period(a.Created); !periods[period] {
+				periods[period] = true
+				keep[a.ID] = true
+			}
+		}
+	}
+
+	var expired []Artifact
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if !keep[sorted[i].ID] {
+			expired = append(expired, sorted[i])
+		}
+	}
+	return expired
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package schedule
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// daily returns an artifact at 02:00 on each of the days before the day of
+// end, newest first
+func daily(end string, days int) []Artifact {
+	t := at(end)
+	artifacts := make([]Artifact, days)
+	for i := range artifacts {
+		created := t.AddDate(0, 0, -i)
+		artifacts[i] = Artifact{ID: created.Format("2006-01-02"), Created: created}
+	}
+	return artifacts
+}
+
+func ids(artifacts []Artifact) []string {
+	result := make([]string, len(artifacts))
+	for i, a := range artifacts {
+		result[i] = a.ID
+	}
+	return result
+}
+
+func (s *scheduleSuite) TestRetention_KeepAll(c *C) {
+	c.Assert(Retention{}.Expired(daily("2019-03-31 02:00", 10)), HasLen, 0)
+	c.Assert(Retention{}.String(), Equals, "keep all")
+}
+
+func (s *scheduleSuite) TestRetention_Daily(c *C) {
+	expired := Retention{Daily: 7}.Expired(daily("2019-03-31 02:00", 10))
+	c.Assert(ids(expired), DeepEquals, []string{"2019-03-22", "2019-03-23", "2019-03-24"})
+}
+
+func (s *scheduleSuite) TestRetention_DailyWeeklyMonthly(c *C) {
+	policy := Retention{Daily: 7, Weekly: 4, Monthly: 6}
+	c.Assert(policy.String(), Equals, "7 daily, 4 weekly, 6 monthly")
+
+	artifacts := daily("2019-03-31 02:00", 120)
+	expired := policy.Expired(artifacts)
+	kept := make(map[string]bool)
+	for _, a := range artifacts {
+		kept[a.ID] = true
+	}
+	for _, a := range expired {
+		delete(kept, a.ID)
+	}
+	// the last 7 days, plus the newest of the 3 weeks and the 3 months
+	// before them
+	c.Assert(len(kept), Equals, 7+3+3)
+	for _, day := range []string{"2019-03-24", "2019-03-17", "2019-03-10"} {
+		c.Assert(kept[day], Equals, true, Commentf(day))
+	}
+	// there are only 4 months with artifacts, so every month is kept
+	for _, day := range []string{"2019-02-28", "2019-01-31", "2018-12-31"} {
+		c.Assert(kept[day], Equals, true, Commentf(day))
+	}
+	c.Assert(kept["2018-12-30"], Equals, false)
+}
+
+func (s *scheduleSuite) TestRetention_SeveralPerPeriod(c *C) {
+	base := at("2019-03-31 00:00")
+	artifacts := []Artifact{
+		{ID: "morning", Created: base.Add(6 * time.Hour)},
+		{ID: "evening", Created: base.Add(18 * time.Hour)},
+		{ID: "yesterday", Created: base.Add(-6 * time.Hour)},
+	}
+	expired := Retention{Daily: 1}.Expired(artifacts)
+	c.Assert(ids(expired), DeepEquals, []string{"yesterday", "morning"})
+}
+
+func (s *scheduleSuite) TestSchedule_Valid(c *C) {
+	sched := &Schedule{ID: "s1", Kind: KindSnapshot, TenantID: "tenant", Cron: "@daily", Retention: Retention{Daily: 7}}
+	c.Assert(sched.ValidEntity(), IsNil)
+
+	sched.TenantID = ""
+	c.Assert(sched.ValidEntity(), NotNil)
+
+	sched.Kind = KindBackup
+	c.Assert(sched.ValidEntity(), IsNil)
+	sched.TenantID = "tenant"
+	c.Assert(sched.ValidEntity(), IsNil)
+
+	sched = &Schedule{ID: "s1", Kind: KindBackup, Cron: "every day"}
+	c.Assert(sched.ValidEntity(), NotNil)
+	sched = &Schedule{ID: "s1", Kind: KindBackup, Cron: "@daily", Retention: Retention{Weekly: -1}}
+	c.Assert(sched.ValidEntity(), NotNil)
+	sched = &Schedule{ID: "s1", Kind: "restore", Cron: "@daily"}
+	c.Assert(sched.ValidEntity(), NotNil)
+}
+
+func (s *scheduleSuite) TestSchedule_UpdateNextRun(c *C) {
+	sched := &Schedule{ID: "s1", Kind: KindBackup, Cron: "0 3 * * *"}
+	c.Assert(sched.UpdateNextRun(at("2019-03-31 04:00")), IsNil)
+	c.Assert(sched.Status.NextRun, Equals, at("2019-04-01 03:00"))
+	c.Assert(sched.SnapshotTag(at("2019-03-31 04:00")), Equals, "sched-s1-20190331-040000")
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/user"
+)
+
+// Kind is what a schedule takes
+type Kind string
+
+const (
+	// KindSnapshot takes snapshots of a tenant
+	KindSnapshot Kind = "snapshot"
+	// KindBackup takes backups of a tenant, or of every tenant
+	KindBackup Kind = "backup"
+)
+
+// Outcome is the result of a scheduled run
+type Outcome string
+
+const (
+	// OutcomeSucceeded is a run that created its snapshot or backup
+	OutcomeSucceeded Outcome = "succeeded"
+	// OutcomeFailed is a run that did not
+	OutcomeFailed Outcome = "failed"
+)
+
+// Status is the record the leader keeps of the runs of a schedule
+type Status struct {
+	LastRun     time.Time  `json:",omitempty"`
+	LastOutcome Outcome    `json:",omitempty"`
+	LastError   string     `json:",omitempty"`
+	NextRun     time.Time  `json:",omitempty"`
+	Artifacts   []Artifact // snapshots or backups created by the schedule that have not expired
+}
+
+// Schedule takes snapshots or backups at the times of a cron expression and
+// removes the ones that fall out of its retention policy
+type Schedule struct {
+	ID        string
+	Kind      Kind
+	TenantID  string // tenant to snapshot or back up; empty for backups of every tenant
+	Cron      string
+	Retention Retention
+	Target    string `json:",omitempty"` // directory or url for backups; defaults to the backup target of the master
+	Disabled  bool
+	Status    Status
+	datastore.VersionedEntity
+}
+
+// snapshotTagPrefix starts the tags of scheduled snapshots.  Tagged snapshots
+// are left alone by the snapshot ttl, so that retention decides their fate.
+const snapshotTagPrefix = "sched"
+
+// SnapshotTag returns the tag of the snapshot the schedule takes at a time
+func (s *Schedule) SnapshotTag(t time.Time) string {
+	return fmt.Sprintf("%s-%s-%s", snapshotTagPrefix, s.ID, t.UTC().Format("20060102-150405"))
+}
+
+// AccessRequest returns the access needed to manage the schedule
+func (s *Schedule) AccessRequest() user.AccessRequest {
+	if s.Kind == KindSnapshot {
+		return user.AccessRequest{Action: user.ActionSnapshot, ServiceID: s.TenantID}
+	}
+	return user.AccessRequest{Action: user.ActionBackup}
+}
+
+// UpdateNextRun sets the next run of the schedule after a time
+func (s *Schedule) UpdateNextRun(t time.Time) error {
+	c, err := ParseCron(s.Cron)
+	if err != nil {
+		return err
+	}
+	s.Status.NextRun = c.Next(t)
+	return nil
+}
+
+// GetType returns the type of schedules in the datastore
+func GetType() string {
+	return kind
+}
+
+// GetType returns the Schedule's type
+func (s *Schedule) GetType() string {
+	return GetType()
+}
+
+// GetID returns the Schedule's ID
+func (s *Schedule) GetID() string {
+	return s.ID
+}
@@ -0,0 +1,95 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"strings"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+// Store is the database for schedules
+type Store interface {
+	// Get a Schedule by id. Return ErrNoSuchEntity if not found
+	Get(ctx datastore.Context, id string) (*Schedule, error)
+
+	// Put adds or updates a Schedule
+	Put(ctx datastore.Context, s *Schedule) error
+
+	// Delete removes a Schedule if it exists
+	Delete(ctx datastore.Context, id string) error
+
+	// GetSchedules returns all schedules
+	GetSchedules(ctx datastore.Context) ([]Schedule, error)
+}
+
+type storeImpl struct {
+	ds datastore.DataStore
+}
+
+// NewStore creates a Store for schedules
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Get a Schedule by id.  Return ErrNoSuchEntity if not found
+func (s *storeImpl) Get(ctx datastore.Context, id string) (*Schedule, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("ScheduleStore.Get"))
+	val := &Schedule{}
+	if err := s.ds.Get(ctx, Key(id), val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// Put adds/updates a Schedule
+func (s *storeImpl) Put(ctx datastore.Context, sched *Schedule) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("ScheduleStore.Put"))
+	return s.ds.Put(ctx, Key(sched.ID), sched)
+}
+
+// Delete removes a Schedule
+func (s *storeImpl) Delete(ctx datastore.Context, id string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("ScheduleStore.Delete"))
+	return s.ds.Delete(ctx, Key(id))
+}
+
+// GetSchedules returns all schedules
+func (s *storeImpl) GetSchedules(ctx datastore.Context) ([]Schedule, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("ScheduleStore.GetSchedules"))
+	query := search.Search("controlplane").Type(kind).Size("50000").
+		Query(search.Query().Search("_exists_:Kind"))
+	q := datastore.NewQuery(ctx)
+	results, err := q.Execute(query)
+	if err != nil {
+		return nil, err
+	}
+	return convert(results)
+}
+
+// Key creates a Key suitable for getting, putting and deleting Schedules
+func Key(id string) datastore.Key {
+	return datastore.NewKey(kind, strings.TrimSpace(id))
+}
+
+func convert(results datastore.Results) ([]Schedule, error) {
+	schedules := make([]Schedule, results.Len())
+	for idx := range schedules {
+		if err := results.Get(idx, &schedules[idx]); err != nil {
+			return nil, err
+		}
+	}
+	return schedules, nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"fmt"
+
+	"github.com/control-center/serviced/validation"
+)
+
+// ValidEntity validates Schedule fields
+func (s *Schedule) ValidEntity() error {
+	violations := validation.NewValidationError()
+	violations.Add(validation.NotEmpty("Schedule.ID", s.ID))
+	switch s.Kind {
+	case KindSnapshot:
+		violations.Add(validation.NotEmpty("Schedule.TenantID", s.TenantID))
+	case KindBackup:
+		// backups of every tenant are taken if there is no tenant
+	default:
+		violations.AddViolation(fmt.Sprintf("invalid schedule kind %q", s.Kind))
+	}
+	if _, err := ParseCron(s.Cron); err != nil {
+		violations.AddViolation(err.Error())
+	}
+	for _, rule := range s.Retention.rules() {
+		if rule.count < 0 {
+			violations.AddViolation(fmt.Sprintf("%s retention cannot be negative", rule.name))
+		}
+	}
+
+	if len(violations.Errors) > 0 {
+		return violations
+	}
+	return nil
+}
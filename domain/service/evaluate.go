@@ -324,7 +324,8 @@ func (service *Service) EvaluatePrereqsTemplate(gs GetService, fc FindChildServi
 	return
 }
 
-// EvaluateHealthCheckTemplate parses and evals the Script field for each HealthCheck.
+// EvaluateHealthCheckTemplate parses and evals the Script field for each
+// HealthCheck, and the URL or Address of its HTTP or TCP check.
 func (service *Service) EvaluateHealthCheckTemplate(gs GetService, fc FindChildService, instanceID int) (err error) {
 	log.WithFields(log.Fields{
 		"servicename": service.Name,
@@ -339,8 +340,28 @@ func (service *Service) EvaluateHealthCheckTemplate(gs GetService, fc FindChildS
 		}
 		if result != "" {
 			healthcheck.Script = result
-			service.HealthChecks[key] = healthcheck
 		}
+		// the checks are shared with the service definition, so the evaluated
+		// ones are copies
+		if healthcheck.HTTP != nil {
+			err, result := service.evaluateTemplate(gs, fc, instanceID, healthcheck.HTTP.URL)
+			if err != nil {
+				return err
+			}
+			httpCheck := *healthcheck.HTTP
+			httpCheck.URL = result
+			healthcheck.HTTP = &httpCheck
+		}
+		if healthcheck.TCP != nil {
+			err, result := service.evaluateTemplate(gs, fc, instanceID, healthcheck.TCP.Address)
+			if err != nil {
+				return err
+			}
+			tcpCheck := *healthcheck.TCP
+			tcpCheck.Address = result
+			healthcheck.TCP = &tcpCheck
+		}
+		service.HealthChecks[key] = healthcheck
 	}
 	return
 }
@@ -17,6 +17,7 @@ package service_test
 
 import (
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/health"
 	. "gopkg.in/check.v1"
 )
 
@@ -40,3 +41,26 @@ func (s *ServiceDomainUnitTestSuite) TestRound(c *C) {
 		c.Assert(service.Round(test.value), Equals, test.expected)
 	}
 }
+
+func (s *ServiceDomainUnitTestSuite) TestEvaluateHealthCheckTemplate_Native(c *C) {
+	httpCheck := &health.HTTPCheck{URL: "http://localhost:{{plus 8000 .InstanceID}}/ping"}
+	tcpCheck := &health.TCPCheck{Address: "localhost:{{plus 5000 .InstanceID}}"}
+	svc := service.Service{
+		ID: "svc",
+		HealthChecks: map[string]health.HealthCheck{
+			"http": {HTTP: httpCheck},
+			"tcp":  {TCP: tcpCheck},
+		},
+	}
+	gs := func(string) (service.Service, error) { return svc, nil }
+	fc := func(string, string) (service.Service, error) { return svc, nil }
+
+	err := svc.EvaluateHealthCheckTemplate(gs, fc, 2)
+	c.Assert(err, IsNil)
+	c.Assert(svc.HealthChecks["http"].HTTP.URL, Equals, "http://localhost:8002/ping")
+	c.Assert(svc.HealthChecks["tcp"].TCP.Address, Equals, "localhost:5002")
+
+	// the templates the checks were evaluated from are kept
+	c.Assert(httpCheck.URL, Equals, "http://localhost:{{plus 8000 .InstanceID}}/ping")
+	c.Assert(tcpCheck.Address, Equals, "localhost:{{plus 5000 .InstanceID}}")
+}
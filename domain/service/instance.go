@@ -69,10 +69,13 @@ type Instance struct {
 type StrategyInstance struct {
 	HostID        string
 	ServiceID     string
+	Name          string
+	DeploymentID  string
 	CPUCommitment int
 	RAMCommitment uint64
 	RAMThreshold  uint
 	HostPolicy    servicedefinition.HostPolicy
+	Placement     servicedefinition.PlacementConstraints
 }
 
 // LocationInstance collection location information about a service instance
@@ -208,53 +208,57 @@ func DesiredStateIsRedundant(desiredState DesiredState, emergency bool, currentS
 
 // Service A Service that can run in serviced.
 type Service struct {
-	ID                string
-	Name              string
-	Title             string // Title is a label used when describing this service in the context of a service tree
-	Version           string
-	Context           map[string]interface{}
-	Environment       []string
-	Startup           string
-	RunAs             string
-	Description       string
-	Tags              []string
-	OriginalConfigs   map[string]servicedefinition.ConfigFile
-	ConfigFiles       map[string]servicedefinition.ConfigFile
-	Instances         int
-	InstanceLimits    domain.MinMax
-	ChangeOptions     []servicedefinition.ChangeOption
-	ImageID           string
-	PoolID            string
-	DesiredState      int
-	CurrentState      string
-	HostPolicy        servicedefinition.HostPolicy
-	Hostname          string
-	Privileged        bool
-	Launch            string
-	Endpoints         []ServiceEndpoint
-	ParentServiceID   string
-	Volumes           []servicedefinition.Volume
-	CreatedAt         time.Time
-	UpdatedAt         time.Time
-	DeploymentID      string
-	DisableImage      bool
-	LogConfigs        []servicedefinition.LogConfig
-	Snapshot          servicedefinition.SnapshotCommands
-	DisableShell      bool
-	Runs              map[string]string // FIXME: This field is deprecated. Remove when possible.
-	Commands          map[string]domain.Command
-	RAMCommitment     utils.EngNotation
-	RAMThreshold      uint
-	CPUCommitment     uint64
-	Actions           map[string]string
-	HealthChecks      map[string]health.HealthCheck // A health check for the service.
-	Prereqs           []domain.Prereq               // Optional list of scripts that must be successfully run before kicking off the service command.
-	MonitoringProfile domain.MonitorProfile
-	MemoryLimit       float64
-	CPUShares         int64
-	OomKillDisable    bool
-	OomScoreAdj       int64
-	PIDFile           string
+	ID                  string
+	Name                string
+	Title               string // Title is a label used when describing this service in the context of a service tree
+	Version             string
+	Context             map[string]interface{}
+	Environment         []string
+	Startup             string
+	RunAs               string
+	Description         string
+	Tags                []string
+	OriginalConfigs     map[string]servicedefinition.ConfigFile
+	ConfigFiles         map[string]servicedefinition.ConfigFile
+	Instances           int
+	InstanceLimits      domain.MinMax
+	ChangeOptions       []servicedefinition.ChangeOption
+	ImageID             string
+	PoolID              string
+	DesiredState        int
+	CurrentState        string
+	HostPolicy          servicedefinition.HostPolicy
+	Placement           servicedefinition.PlacementConstraints
+	Hostname            string
+	Privileged          bool
+	Launch              string
+	Endpoints           []ServiceEndpoint
+	ParentServiceID     string
+	Volumes             []servicedefinition.Volume
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	DeploymentID        string
+	TemplateID          string // ID of the template the service was deployed from
+	TemplateVersion     string // Version of the template the service was deployed from
+	DisableImage        bool
+	LogConfigs          []servicedefinition.LogConfig
+	Snapshot            servicedefinition.SnapshotCommands
+	DisableShell        bool
+	RecordShellSessions bool              // on a tenant, requires shell and attach sessions on its services to be recorded
+	Runs                map[string]string // FIXME: This field is deprecated. Remove when possible.
+	Commands            map[string]domain.Command
+	RAMCommitment       utils.EngNotation
+	RAMThreshold        uint
+	CPUCommitment       uint64
+	Actions             map[string]string
+	HealthChecks        map[string]health.HealthCheck // A health check for the service.
+	Prereqs             []domain.Prereq               // Optional list of scripts that must be successfully run before kicking off the service command.
+	MonitoringProfile   domain.MonitorProfile
+	MemoryLimit         float64
+	CPUShares           int64
+	OomKillDisable      bool
+	OomScoreAdj         int64
+	PIDFile             string
 	// StartLevel represents the order in which services are started and stopped
 	// in normal operations.  All services of a given level start before any services
 	// at higher levels.  Stopping services occurs in the reverse order.  Services
@@ -289,7 +293,8 @@ type ServiceEndpoint struct {
 	// subdomain, i.e "myapplication"  not "myapplication.host.com"
 	VHostList         []servicedefinition.VHost // VHost is used to request named vhost(s) for this endpoint.
 	AddressAssignment addressassignment.AddressAssignment
-	PortList          []servicedefinition.Port // The list of enabled/disabled ports to assign to this endpoint.
+	PortList          []servicedefinition.Port        // The list of enabled/disabled ports to assign to this endpoint.
+	LoadBalancing     servicedefinition.LoadBalancing // How an import chooses among the instances exporting the endpoint
 }
 
 // IsConfigurable returns true if the endpoint is configurable
@@ -334,6 +339,7 @@ func BuildServiceEndpoint(epd servicedefinition.EndpointDefinition) ServiceEndpo
 	sep.VHosts = epd.VHosts
 	sep.VHostList = epd.VHostList
 	sep.PortList = epd.PortList
+	sep.LoadBalancing = epd.LoadBalancing
 
 	// run public ports through scrubber to allow for "almost correct" port addresses
 	for index, port := range sep.PortList {
@@ -374,6 +380,7 @@ func BuildService(sd servicedefinition.ServiceDefinition, parentServiceID string
 	svc.DesiredState = desiredState
 	svc.Launch = sd.Launch
 	svc.HostPolicy = sd.HostPolicy
+	svc.Placement = sd.Placement
 	svc.Hostname = sd.Hostname
 	svc.Privileged = sd.Privileged
 	svc.OriginalConfigs = sd.ConfigFiles
@@ -389,6 +396,7 @@ func BuildService(sd servicedefinition.ServiceDefinition, parentServiceID string
 	svc.RAMThreshold = sd.RAMThreshold
 	svc.CPUCommitment = sd.CPUCommitment
 	svc.DisableShell = sd.DisableShell
+	svc.RecordShellSessions = sd.RecordShellSessions
 	svc.Runs = sd.Runs
 	svc.Commands = sd.Commands
 	svc.Actions = sd.Actions
@@ -419,6 +427,76 @@ func BuildService(sd servicedefinition.ServiceDefinition, parentServiceID string
 	return &svc, nil
 }
 
+// BuildServiceDefinition builds a service definition from a service; the
+// reverse of BuildService.  IDs, pool and deployment information and address
+// assignments are dropped, and child services are not included.
+func BuildServiceDefinition(svc Service) servicedefinition.ServiceDefinition {
+	sd := servicedefinition.ServiceDefinition{}
+	sd.Name = svc.Name
+	sd.Title = svc.Title
+	sd.Version = svc.Version
+	sd.Context = svc.Context
+	sd.Command = svc.Startup
+	sd.RunAs = svc.RunAs
+	sd.Description = svc.Description
+	sd.Environment = svc.Environment
+	sd.Tags = svc.Tags
+	sd.Instances = svc.InstanceLimits
+	sd.Instances.Default = svc.Instances
+	sd.ChangeOptions = svc.ChangeOptions
+	sd.ImageID = svc.ImageID
+	sd.Launch = svc.Launch
+	sd.HostPolicy = svc.HostPolicy
+	sd.Placement = svc.Placement
+	sd.Hostname = svc.Hostname
+	sd.Privileged = svc.Privileged
+	sd.ConfigFiles = svc.ConfigFiles
+	sd.Volumes = svc.Volumes
+	sd.LogConfigs = svc.LogConfigs
+	sd.Snapshot = svc.Snapshot
+	sd.RAMCommitment = svc.RAMCommitment
+	sd.RAMThreshold = svc.RAMThreshold
+	sd.CPUCommitment = svc.CPUCommitment
+	sd.DisableShell = svc.DisableShell
+	sd.RecordShellSessions = svc.RecordShellSessions
+	sd.Commands = svc.Commands
+	sd.Actions = svc.Actions
+	sd.HealthChecks = svc.HealthChecks
+	sd.Prereqs = svc.Prereqs
+	sd.MonitoringProfile = svc.MonitoringProfile
+	sd.MemoryLimit = svc.MemoryLimit
+	sd.CPUShares = svc.CPUShares
+	sd.OomKillDisable = svc.OomKillDisable
+	sd.OomScoreAdj = svc.OomScoreAdj
+	sd.PIDFile = svc.PIDFile
+	sd.StartLevel = svc.StartLevel
+	sd.EmergencyShutdownLevel = svc.EmergencyShutdownLevel
+
+	for _, ep := range svc.Endpoints {
+		epd := servicedefinition.EndpointDefinition{
+			Name:                ep.Name,
+			Purpose:             ep.Purpose,
+			Protocol:            ep.Protocol,
+			PortNumber:          ep.PortNumber,
+			PortTemplate:        ep.PortTemplate,
+			VirtualAddress:      ep.VirtualAddress,
+			Application:         ep.Application,
+			ApplicationTemplate: ep.ApplicationTemplate,
+			AddressConfig:       ep.AddressConfig,
+			VHosts:              ep.VHosts,
+			VHostList:           ep.VHostList,
+			PortList:            ep.PortList,
+			LoadBalancing:       ep.LoadBalancing,
+		}
+		if epd.ApplicationTemplate != "" {
+			// the application is evaluated from the template at deployment
+			epd.Application = ""
+		}
+		sd.Endpoints = append(sd.Endpoints, epd)
+	}
+	return sd
+}
+
 //CloneService copies a service and mutates id and names
 func CloneService(fromSvc *Service, suffix string) (*Service, error) {
 	svcuuid, err := utils.NewUUID36()
@@ -703,6 +781,37 @@ func (s *Service) EnablePort(application string, portAddr string, enable bool) e
 	return nil
 }
 
+// SetPortAccess sets the access policy of a port for given service; a nil
+// policy allows every client
+func (s *Service) SetPortAccess(application, portAddr string, access *servicedefinition.AccessPolicy) error {
+	appFound := false
+	portFound := false
+	for _, ep := range s.GetServicePorts() {
+		if ep.Application == application {
+			appFound = true
+			for i, port := range ep.PortList {
+				if port.PortAddr == portAddr {
+					portFound = true
+					ep.PortList[i].Access = access
+					plog.WithFields(log.Fields{
+						"portaddr":    portAddr,
+						"serviceid":   s.ID,
+						"application": application,
+					}).Debug("Set port access policy")
+				}
+			}
+		}
+	}
+	if !appFound {
+		return fmt.Errorf("port %s not found; application %s not found in service %s:%s", portAddr, application, s.ID, s.Name)
+	}
+	if !portFound {
+		return fmt.Errorf("port %s not found in service %s:%s", portAddr, s.ID, s.Name)
+	}
+
+	return nil
+}
+
 // Make best effort to make a port address valid
 func ScrubPortString(port string) string {
 	// remove possible protocol at string beginning
@@ -747,6 +856,37 @@ func (s *Service) EnableVirtualHost(application, vhostName string, enable bool)
 	return nil
 }
 
+// SetVirtualHostAccess sets the access policy of a virtual host for given
+// service; a nil policy allows every client
+func (s *Service) SetVirtualHostAccess(application, vhostName string, access *servicedefinition.AccessPolicy) error {
+	appFound := false
+	vhostFound := false
+	for _, ep := range s.GetServiceVHosts() {
+		if ep.Application == application {
+			appFound = true
+			for i, vhost := range ep.VHostList {
+				if vhost.Name == vhostName {
+					vhostFound = true
+					ep.VHostList[i].Access = access
+					plog.WithFields(log.Fields{
+						"vhostname":   vhostName,
+						"serviceid":   s.ID,
+						"application": application,
+					}).Debug("Set vhost access policy")
+				}
+			}
+		}
+	}
+	if !appFound {
+		return fmt.Errorf("vhost %s not found; application %s not found in service %s:%s", vhostName, application, s.ID, s.Name)
+	}
+	if !vhostFound {
+		return fmt.Errorf("vhost %s not found in service %s:%s", vhostName, s.ID, s.Name)
+	}
+
+	return nil
+}
+
 // RemoveVirtualHost Remove a virtual host for given service
 func (s *Service) RemoveVirtualHost(application, vhostName string) error {
 	if s.Endpoints != nil {
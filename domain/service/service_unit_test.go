@@ -16,6 +16,7 @@
 package service_test
 
 import (
+	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/utils"
@@ -72,6 +73,7 @@ func (s *ServiceDomainUnitTestSuite) TestBuildServiceSimple(t *C) {
 		RAMCommitment: ramCommitment,
 		CPUCommitment: cpuCommitment,
 		DisableShell:  disableShell,
+		RecordShellSessions: true,
 		Runs:          runs,
 		// Commands: commands,
 		Actions: actions,
@@ -102,6 +104,7 @@ func (s *ServiceDomainUnitTestSuite) TestBuildServiceSimple(t *C) {
 	t.Check(actual.RAMCommitment, Equals, ramCommitment)
 	t.Check(actual.CPUCommitment, Equals, cpuCommitment)
 	t.Check(actual.DisableShell, Equals, disableShell)
+	t.Check(actual.RecordShellSessions, Equals, true)
 	t.Check(actual.Runs, DeepEquals, runs)
 	t.Check(actual.Actions, DeepEquals, actions)
 	t.Check(actual.MemoryLimit, Equals, memoryLimit)
@@ -110,3 +113,73 @@ func (s *ServiceDomainUnitTestSuite) TestBuildServiceSimple(t *C) {
 	t.Check(actual.StartLevel, Equals, startLevel)
 	t.Check(actual.EmergencyShutdownLevel, Equals, shutdownLevel)
 }
+
+// Test that a service definition built from a service keeps its settings
+// but not its deployment
+func (s *ServiceDomainUnitTestSuite) TestBuildServiceDefinition(t *C) {
+	sd := servicedefinition.ServiceDefinition{
+		Name:      "Name",
+		Command:   "command",
+		ImageID:   "imageid",
+		Instances: domain.MinMax{Min: 1, Max: 5, Default: 2},
+		Launch:    "auto",
+		Context:   map[string]interface{}{"foo": "bar"},
+		ConfigFiles: map[string]servicedefinition.ConfigFile{
+			"/etc/app.conf": {Filename: "/etc/app.conf", Content: "original"},
+		},
+		Endpoints: []servicedefinition.EndpointDefinition{
+			{Name: "web", Purpose: "export", Protocol: "tcp", PortNumber: 8080, ApplicationTemplate: "{{.Name}}_web"},
+			{Name: "db", Purpose: "import", Protocol: "tcp", PortNumber: 5432, Application: "db"},
+		},
+	}
+	svc, err := service.BuildService(sd, "parentid", "poolid", 0, "deploymentid")
+	t.Assert(err, IsNil)
+	svc.Instances = 3
+	svc.Endpoints[0].Application = "Name_web"
+	svc.ConfigFiles = map[string]servicedefinition.ConfigFile{
+		"/etc/app.conf": {Filename: "/etc/app.conf", Content: "edited"},
+	}
+
+	actual := service.BuildServiceDefinition(*svc)
+	t.Check(actual.Name, Equals, "Name")
+	t.Check(actual.Command, Equals, "command")
+	t.Check(actual.ImageID, Equals, "imageid")
+	t.Check(actual.Instances, Equals, domain.MinMax{Min: 1, Max: 5, Default: 3})
+	t.Check(actual.Launch, Equals, "auto")
+	t.Check(actual.Context, DeepEquals, sd.Context)
+	t.Check(actual.ConfigFiles["/etc/app.conf"].Content, Equals, "edited")
+	t.Assert(actual.Endpoints, HasLen, 2)
+	t.Check(actual.Endpoints[0].Application, Equals, "")
+	t.Check(actual.Endpoints[0].ApplicationTemplate, Equals, "{{.Name}}_web")
+	t.Check(actual.Endpoints[1].Application, Equals, "db")
+	t.Check(actual.Services, IsNil)
+}
+
+func (s *ServiceDomainUnitTestSuite) TestSetEndpointAccess(t *C) {
+	svc := service.Service{
+		ID:   "svcid",
+		Name: "svc",
+		Endpoints: []service.ServiceEndpoint{
+			service.BuildServiceEndpoint(
+				servicedefinition.EndpointDefinition{
+					Purpose:     "export",
+					Application: "server",
+					PortList:    []servicedefinition.Port{{PortAddr: ":1234"}},
+					VHostList:   []servicedefinition.VHost{{Name: "web"}},
+				}),
+		},
+	}
+	access := &servicedefinition.AccessPolicy{Allow: []string{"10.0.0.0/8"}, RateLimit: 60}
+
+	t.Assert(svc.SetPortAccess("other", ":1234", access), NotNil)
+	t.Assert(svc.SetPortAccess("server", ":4321", access), NotNil)
+	t.Assert(svc.SetPortAccess("server", ":1234", access), IsNil)
+	t.Check(svc.Endpoints[0].PortList[0].Access, DeepEquals, access)
+	t.Assert(svc.SetPortAccess("server", ":1234", nil), IsNil)
+	t.Check(svc.Endpoints[0].PortList[0].Access, IsNil)
+
+	t.Assert(svc.SetVirtualHostAccess("other", "web", access), NotNil)
+	t.Assert(svc.SetVirtualHostAccess("server", "app", access), NotNil)
+	t.Assert(svc.SetVirtualHostAccess("server", "web", access), IsNil)
+	t.Check(svc.Endpoints[0].VHostList[0].Access, DeepEquals, access)
+}
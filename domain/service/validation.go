@@ -17,6 +17,7 @@ import (
 	"fmt"
 
 	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/validation"
 )
 
@@ -46,6 +47,9 @@ func (s *Service) ValidEntity() error {
 	// validate the monitoring profile
 	vErr.Add(s.MonitoringProfile.ValidEntity())
 
+	// validate the placement constraints
+	vErr.Add(s.Placement.ValidEntity())
+
 	for _, ep := range s.Endpoints {
 		vErr.Add(ep.ValidEntity())
 	}
@@ -72,6 +76,9 @@ func (endpoint ServiceEndpoint) ValidEntity() error {
 	}
 
 	violations.Add(validation.NotEmpty("endpoint.Application", endpoint.Application))
+	violations.Add(validation.StringIn(string(endpoint.LoadBalancing), "",
+		string(servicedefinition.RoundRobin), string(servicedefinition.LeastConnections),
+		string(servicedefinition.HealthAware), string(servicedefinition.ClientIP)))
 
 	if violations.HasError() {
 		return violations
@@ -0,0 +1,68 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicedefinition
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// AccessPolicy restricts which clients may use a public endpoint
+type AccessPolicy struct {
+	Allow     []string `json:",omitempty"` // CIDRs of the clients that may connect; any client not denied if empty
+	Deny      []string `json:",omitempty"` // CIDRs of the clients that may not connect
+	RateLimit int      `json:",omitempty"` // requests (vhosts and http ports) or connections (other ports) per minute from each client address; 0 is unlimited
+}
+
+// IsZero returns true if the policy does not restrict access
+func (p *AccessPolicy) IsZero() bool {
+	return p == nil || (len(p.Allow) == 0 && len(p.Deny) == 0 && p.RateLimit == 0)
+}
+
+// ValidEntity verifies the networks and rate limit of the policy
+func (p *AccessPolicy) ValidEntity() error {
+	if p == nil {
+		return nil
+	}
+	for _, cidr := range append(append([]string{}, p.Allow...), p.Deny...) {
+		if _, err := ParseCIDR(cidr); err != nil {
+			return err
+		}
+	}
+	if p.RateLimit < 0 {
+		return fmt.Errorf("rate limit must not be negative")
+	}
+	return nil
+}
+
+// ParseCIDR parses a network in CIDR notation, or a single IP address
+func ParseCIDR(cidr string) (*net.IPNet, error) {
+	cidr = strings.TrimSpace(cidr)
+	if !strings.Contains(cidr, "/") {
+		ip := net.ParseIP(cidr)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid address %q", cidr)
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network %q", cidr)
+	}
+	return network, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicedefinition
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/control-center/serviced/validation"
+)
+
+// PlacementConstraints restrict the hosts that may run instances of a
+// service, in addition to its HostPolicy.  Services are named either by id or
+// by the name of a service in the same application.
+type PlacementConstraints struct {
+	HostLabels   map[string]string // Labels the host must carry, e.g. {"ssd": "true"}
+	Affinity     []string          // Services that must already run on the host
+	AntiAffinity []string          // Services that must not run on the host
+}
+
+// IsEmpty returns true if there are no constraints
+func (p PlacementConstraints) IsEmpty() bool {
+	return len(p.HostLabels) == 0 && len(p.Affinity) == 0 && len(p.AntiAffinity) == 0
+}
+
+// ValidEntity validates the placement constraints
+func (p PlacementConstraints) ValidEntity() error {
+	for key, value := range p.HostLabels {
+		if err := validation.ValidLabel(key, value); err != nil {
+			return err
+		}
+	}
+	anti := make(map[string]struct{})
+	for _, name := range p.AntiAffinity {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("anti-affinity must name a service")
+		}
+		anti[name] = struct{}{}
+	}
+	for _, name := range p.Affinity {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("affinity must name a service")
+		}
+		if _, ok := anti[name]; ok {
+			return fmt.Errorf("service %s is listed in both affinity and anti-affinity", name)
+		}
+	}
+	return nil
+}
@@ -28,7 +28,7 @@ import (
 // initialize the package logger
 var plog = logging.PackageLogger()
 
-//ServiceDefinition is the definition of a service hierarchy.
+// ServiceDefinition is the definition of a service hierarchy.
 type ServiceDefinition struct {
 	Name                   string                 // Name of the defined service
 	Title                  string                 // Title is a label used when describing this service in the context of a service tree
@@ -43,6 +43,7 @@ type ServiceDefinition struct {
 	ChangeOptions          []ChangeOption         // Control options for what happens when a running service is changed
 	Launch                 string                 // Must be "AUTO", the default, or "MANUAL"
 	HostPolicy             HostPolicy             // Policy for starting up instances
+	Placement              PlacementConstraints   // Constraints on the hosts that may run instances
 	Hostname               string                 // Optional hostname which should be set on run
 	Privileged             bool                   // Whether to run the container with extended privileges
 	ConfigFiles            map[string]ConfigFile  // Config file templates
@@ -57,6 +58,7 @@ type ServiceDefinition struct {
 	RAMThreshold           uint                          // RAM Threshold
 	CPUCommitment          uint64                        // expected CPU commitment (#cores) to use for scheduling
 	DisableShell           bool                          // disables shell commands on the service
+	RecordShellSessions    bool                          // on a tenant, requires shell and attach sessions on its services to be recorded
 	Runs                   map[string]string             // FIXME: This field is deprecated. Remove when possible.
 	Commands               map[string]domain.Command     // Map of commands that can be executed with 'serviced run ...'
 	Actions                map[string]string             // Map of commands that can be executed with 'serviced action ...'
@@ -65,8 +67,8 @@ type ServiceDefinition struct {
 	MonitoringProfile      domain.MonitorProfile         // An optional list of queryable metrics, graphs, and thresholds
 	MemoryLimit            float64
 	CPUShares              int64
-	OomKillDisable         bool                   // Whether to disable OOM Killer for the container or not
-	OomScoreAdj            int64                  // Tune containers OOM preferences (-1000 to 1000)
+	OomKillDisable         bool   // Whether to disable OOM Killer for the container or not
+	OomScoreAdj            int64  // Tune containers OOM preferences (-1000 to 1000)
 	PIDFile                string // An optional path or command to generate a path for a PID file to which signals are relayed.
 	StartLevel             uint   // Services start in the order implied by this field (low to high) and stopped in reverse order
 	EmergencyShutdownLevel uint   // In case of low storage, Services stopped in the order implied by this field (low to high)
@@ -91,22 +93,25 @@ type EndpointDefinition struct {
 	AddressConfig       AddressResourceConfig
 	VHosts              []string // VHost is used to request named vhost for this endpoint. Should be the name of a
 	// subdomain, i.e "myapplication"  not "myapplication.host.com"
-	VHostList []VHost // VHost is used to request named vhost(s) for this endpoint.
-	PortList  []Port
+	VHostList     []VHost // VHost is used to request named vhost(s) for this endpoint.
+	PortList      []Port
+	LoadBalancing LoadBalancing // How an import chooses among the instances exporting the endpoint
 }
 
 // VHost is the configuration for an application endpoint that wants an http VHost endpoint provided by Control Center
 type VHost struct {
-	Name    string // name of the vhost subdomain subdomain, i.e "myapplication"  not "myapplication.host.com
-	Enabled bool   // whether the vhost should be enabled or disabled.
+	Name    string        // name of the vhost subdomain subdomain, i.e "myapplication"  not "myapplication.host.com
+	Enabled bool          // whether the vhost should be enabled or disabled.
+	Access  *AccessPolicy `json:",omitempty"` // which clients may use the vhost
 }
 
 // Port is the configuration for an application endpoint port.
 type Port struct {
-	PortAddr string // which port number to use for this endpoint
-	Enabled  bool   // whether the port should be enabled or disabled.
-	UseTLS   bool   // Does this port endpoint use tls.
-	Protocol string // What protocol (if any) does the endpoind use.
+	PortAddr string        // which port number to use for this endpoint
+	Enabled  bool          // whether the port should be enabled or disabled.
+	UseTLS   bool          // Does this port endpoint use tls.
+	Protocol string        // What protocol (if any) does the endpoind use.
+	Access   *AccessPolicy `json:",omitempty"` // which clients may use the port
 }
 
 // Volume import defines a file system directory underneath an export directory
@@ -133,7 +138,7 @@ func GetConfigFileType() string {
 	return "serviceconfigurationfile"
 }
 
-//AddressResourceConfig defines an external facing port for a service definition
+// AddressResourceConfig defines an external facing port for a service definition
 type AddressResourceConfig struct {
 	Port     uint16
 	Protocol string
@@ -188,6 +193,37 @@ func (p *HostPolicy) UnmarshalText(b []byte) error {
 	return nil
 }
 
+// LoadBalancing is the policy an imported endpoint uses to choose which of
+// the instances exporting the endpoint receives a new connection. Default is
+// round robin.
+type LoadBalancing string
+
+const (
+	// RoundRobin hands connections to each instance in turn
+	RoundRobin = LoadBalancing("round_robin")
+	// LeastConnections hands connections to the instance with the fewest open
+	// connections
+	LeastConnections = LoadBalancing("least_connections")
+	// HealthAware hands connections in turn to the instances whose health
+	// checks are passing
+	HealthAware = LoadBalancing("health_aware")
+	// ClientIP always hands connections from the same client address to the
+	// same instance while it is available
+	ClientIP = LoadBalancing("client_ip")
+)
+
+// UnmarshalText implements the encoding/TextUnmarshaler interface
+func (lb *LoadBalancing) UnmarshalText(b []byte) error {
+	s := LoadBalancing(strings.ToLower(strings.Trim(string(b), `"`)))
+	switch s {
+	case "", RoundRobin, LeastConnections, HealthAware, ClientIP:
+		*lb = s
+	default:
+		return errors.New("Invalid LoadBalancing: " + string(s))
+	}
+	return nil
+}
+
 // ChangeOption is the policy for what happens in the scheduler Sync when the
 // running services change
 type ChangeOption string
@@ -293,7 +329,7 @@ func (s ServiceDefinition) String() string {
 	return s.Name
 }
 
-//BuildFromPath given a path will create a ServiceDefintion
+// BuildFromPath given a path will create a ServiceDefintion
 func BuildFromPath(path string) (*ServiceDefinition, error) {
 	sd, err := getServiceDefinition(path)
 	if err != nil {
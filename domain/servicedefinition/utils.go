@@ -189,3 +189,106 @@ func getFiltersFromDirectory(path string) (filters map[string]string, err error)
 	}).Debug("Found filters")
 	return filters, nil
 }
+
+// WriteToPath writes a service definition out as a directory that
+// BuildFromPath reads back: path/NAME/service.json holds the definition,
+// config file contents go under -CONFIGS-, log filters under FILTERS and each
+// child service gets a subdirectory of its own.
+func WriteToPath(sd *ServiceDefinition, path string) error {
+	switch sd.Name {
+	case "", ".", "..", "service.json", "makefile", "-CONFIGS-", "FILTERS":
+		return fmt.Errorf("service name %q cannot be used as a directory name", sd.Name)
+	}
+	if strings.Contains(sd.Name, "/") {
+		return fmt.Errorf("service name %q cannot be used as a directory name", sd.Name)
+	}
+	dir := filepath.Join(path, sd.Name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	def := *sd
+	def.Name = ""
+	def.Services = nil
+	def.LogFilters = nil
+	def.ConfigFiles = make(map[string]ConfigFile)
+	for _, configFile := range sd.ConfigFiles {
+		if !filepath.IsAbs(configFile.Filename) || filepath.Clean(configFile.Filename) != configFile.Filename {
+			return fmt.Errorf("config file %q of service %s is not a clean absolute path", configFile.Filename, sd.Name)
+		}
+		filename := filepath.Join(dir, "-CONFIGS-", configFile.Filename)
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filename, []byte(configFile.Content), 0644); err != nil {
+			return err
+		}
+		configFile.Content = ""
+		def.ConfigFiles[configFile.Filename] = configFile
+	}
+	if len(sd.LogFilters) > 0 {
+		if err := os.MkdirAll(filepath.Join(dir, "FILTERS"), 0755); err != nil {
+			return err
+		}
+		for name, filter := range sd.LogFilters {
+			if err := ioutil.WriteFile(filepath.Join(dir, "FILTERS", name+".conf"), []byte(filter), 0644); err != nil {
+				return err
+			}
+		}
+	}
+
+	blob, err := marshalServiceJSON(&def)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "service.json"), blob, 0644); err != nil {
+		return err
+	}
+
+	for i := range sd.Services {
+		if err := WriteToPath(&sd.Services[i], dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// marshalServiceJSON marshals a service definition, leaving out the fields
+// that are set to their zero value.
+func marshalServiceJSON(sd *ServiceDefinition) ([]byte, error) {
+	blob, err := json.Marshal(sd)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(blob, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range fields {
+		switch v := value.(type) {
+		case nil:
+			delete(fields, name)
+		case string:
+			if v == "" {
+				delete(fields, name)
+			}
+		case float64:
+			if v == 0 {
+				delete(fields, name)
+			}
+		case bool:
+			if !v {
+				delete(fields, name)
+			}
+		case []interface{}:
+			if len(v) == 0 {
+				delete(fields, name)
+			}
+		case map[string]interface{}:
+			if len(v) == 0 {
+				delete(fields, name)
+			}
+		}
+	}
+	return json.MarshalIndent(fields, "", "  ")
+}
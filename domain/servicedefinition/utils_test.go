@@ -0,0 +1,77 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package servicedefinition
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func sortServices(sd *ServiceDefinition) {
+	sort.Sort(ServiceDefinitionByName(sd.Services))
+	for i := range sd.Services {
+		sortServices(&sd.Services[i])
+	}
+}
+
+func TestWriteToPath(t *testing.T) {
+	sd, err := BuildFromPath("./testsvc")
+	if err != nil {
+		t.Fatalf("Could not build test service definition: %s", err)
+	}
+	sd.Services[0].LogFilters = map[string]string{"access": "grok { }"}
+	sortServices(sd)
+
+	dir, err := ioutil.TempDir("", "servicedefinition-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := WriteToPath(sd, dir); err != nil {
+		t.Fatalf("Could not write service definition: %s", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "testsvc", "s2", "-CONFIGS-", "foo", "bar.txt")); err != nil {
+		t.Errorf("Expected config file to be written: %s", err)
+	}
+
+	actual, err := BuildFromPath(filepath.Join(dir, "testsvc"))
+	if err != nil {
+		t.Fatalf("Could not build written service definition: %s", err)
+	}
+	sortServices(actual)
+	if !reflect.DeepEqual(sd, actual) {
+		t.Errorf("Expected %+v, got %+v", sd, actual)
+	}
+}
+
+func TestWriteToPathBadName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "servicedefinition-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"", "..", "a/b", "-CONFIGS-"} {
+		if err := WriteToPath(&ServiceDefinition{Name: name}, dir); err == nil {
+			t.Errorf("Expected an error writing a service named %q", name)
+		}
+	}
+}
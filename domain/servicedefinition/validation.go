@@ -43,6 +43,10 @@ func (sd *ServiceDefinition) validate(context *validationContext) error {
 		return fmt.Errorf("service definition %v: invalid launch setting %v", sd.Name, err)
 	}
 
+	if err := sd.Placement.ValidEntity(); err != nil {
+		return fmt.Errorf("service definition %v: invalid placement constraints: %v", sd.Name, err)
+	}
+
 	//validate endpoint config
 	names := make(map[string]struct{})
 	for _, se := range sd.Endpoints {
@@ -121,6 +125,19 @@ func (se EndpointDefinition) ValidEntity() error {
 			return fmt.Errorf("endpoint '%s': %s", se.Name, err)
 		}
 	}
+	if err := validation.StringIn(string(se.LoadBalancing), "", string(RoundRobin), string(LeastConnections), string(HealthAware), string(ClientIP)); err != nil {
+		return fmt.Errorf("endpoint '%s': invalid load balancing: %s", se.Name, err)
+	}
+	for _, port := range se.PortList {
+		if err := port.Access.ValidEntity(); err != nil {
+			return fmt.Errorf("endpoint '%s': port %s: %s", se.Name, port.PortAddr, err)
+		}
+	}
+	for _, vhost := range se.VHostList {
+		if err := vhost.Access.ValidEntity(); err != nil {
+			return fmt.Errorf("endpoint '%s': vhost %s: %s", se.Name, vhost.Name, err)
+		}
+	}
 	return se.AddressConfig.ValidEntity()
 }
 
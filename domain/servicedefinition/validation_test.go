@@ -20,6 +20,7 @@ import (
 	. "github.com/control-center/serviced/domain/servicedefinition"
 	. "github.com/control-center/serviced/domain/servicedefinition/testutils"
 
+	"encoding/json"
 	"strings"
 	"testing"
 )
@@ -137,3 +138,93 @@ func TestServiceDefinitionEmptyEndpointName(t *testing.T) {
 		t.Errorf("Unexpected Error %v", err)
 	}
 }
+
+func TestServiceDefinitionPlacement(t *testing.T) {
+	sd := *ValidSvcDef
+	sd.Placement = PlacementConstraints{
+		HostLabels:   map[string]string{"ssd": "true"},
+		Affinity:     []string{"cache"},
+		AntiAffinity: []string{"db"},
+	}
+	if err := sd.ValidEntity(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	sd.Placement.HostLabels = map[string]string{"bad key": "true"}
+	if err := sd.ValidEntity(); err == nil {
+		t.Error("Expected error for an invalid host label")
+	}
+
+	sd.Placement.HostLabels = nil
+	sd.Placement.AntiAffinity = []string{"cache"}
+	err := sd.ValidEntity()
+	if err == nil || !strings.Contains(err.Error(), "both affinity and anti-affinity") {
+		t.Errorf("Expected error for a service with affinity and anti-affinity, got %v", err)
+	}
+}
+
+func TestServiceDefinitionEndpointLoadBalancing(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	sd.Services[0].Endpoints[0].LoadBalancing = LeastConnections
+	if err := sd.ValidEntity(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	sd.Services[0].Endpoints[0].LoadBalancing = LoadBalancing("random")
+	err := sd.ValidEntity()
+	if err == nil || !strings.Contains(err.Error(), "invalid load balancing") {
+		t.Errorf("Expected error for an invalid load balancing policy, got %v", err)
+	}
+
+	var lb LoadBalancing
+	if err := json.Unmarshal([]byte(`"Client_IP"`), &lb); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	} else if lb != ClientIP {
+		t.Errorf("Expected %s, got %s", ClientIP, lb)
+	}
+	if err := json.Unmarshal([]byte(`"random"`), &lb); err == nil {
+		t.Error("Expected error unmarshaling an invalid load balancing policy")
+	}
+}
+
+func TestServiceDefinitionEndpointAccess(t *testing.T) {
+	sd := CreateValidServiceDefinition()
+	ep := &sd.Services[0].Endpoints[0]
+	ep.PortList = []Port{{PortAddr: ":1234", Access: &AccessPolicy{Allow: []string{"10.0.0.0/8", "192.168.1.5"}, RateLimit: 60}}}
+	ep.VHostList = []VHost{{Name: "app", Access: &AccessPolicy{Deny: []string{"fe80::/10"}}}}
+	if err := sd.ValidEntity(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	ep.VHostList[0].Access.Deny = []string{"10.0.0.0/33"}
+	err := sd.ValidEntity()
+	if err == nil || !strings.Contains(err.Error(), `invalid network "10.0.0.0/33"`) {
+		t.Errorf("Expected error for an invalid network, got %v", err)
+	}
+
+	ep.VHostList[0].Access = nil
+	ep.PortList[0].Access.RateLimit = -1
+	err = sd.ValidEntity()
+	if err == nil || !strings.Contains(err.Error(), "rate limit") {
+		t.Errorf("Expected error for a negative rate limit, got %v", err)
+	}
+}
+
+func TestParseCIDR(t *testing.T) {
+	for value, expected := range map[string]string{
+		"10.1.2.3":       "10.1.2.3/32",
+		"10.1.2.3/16":    "10.1.0.0/16",
+		"2001:db8::1":    "2001:db8::1/128",
+		" 172.16.0.0/12": "172.16.0.0/12",
+	} {
+		network, err := ParseCIDR(value)
+		if err != nil {
+			t.Errorf("Unexpected error parsing %q: %v", value, err)
+		} else if network.String() != expected {
+			t.Errorf("Expected %s, got %s", expected, network)
+		}
+	}
+	if _, err := ParseCIDR("example.com"); err == nil {
+		t.Error("Expected error parsing a host name")
+	}
+}
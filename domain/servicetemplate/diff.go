@@ -0,0 +1,222 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicetemplate
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+)
+
+// Kinds of change between two versions of an application's service definitions
+const (
+	ChangeServiceAdded   = "service added"
+	ChangeServiceRemoved = "service removed"
+	ChangeImage          = "image"
+	ChangeCommand        = "command"
+	ChangeEndpoint       = "endpoint"
+	ChangeConfigFile     = "config file"
+	ChangeVolume         = "volume"
+)
+
+// Change is a single difference between two versions of a service definition
+type Change struct {
+	Path      string // Name path of the service, e.g. "Zenoss.core/Zope"
+	Kind      string // What changed; one of the Change* constants
+	Name      string // Endpoint name, config file name or volume container path
+	Old       string // Summary of the old value; empty if it was added
+	New       string // Summary of the new value; empty if it was removed
+	Preserved bool   // The deployed config file was edited and is kept on upgrade
+}
+
+// TemplateDiff describes how upgrading a deployed application to a template
+// changes its service definitions.
+type TemplateDiff struct {
+	TenantID       string   // ID of the application's tenant service
+	TemplateID     string   // ID of the template being upgraded to
+	Version        string   // Version of the template being upgraded to
+	FromTemplateID string   // ID of the template the application was deployed from, if known
+	FromVersion    string   // Version of the template the application was deployed from
+	Changes        []Change // Changes, in service tree order
+	Snapshot       string   // Snapshot taken before the upgrade was applied
+}
+
+// Diff compares two versions of an application's service definition tree.
+// Services are matched by name; an added or removed service is reported once,
+// without its child services.
+func Diff(from, to servicedefinition.ServiceDefinition) []Change {
+	var changes []Change
+	diffService(from.Name, &from, &to, &changes)
+	return changes
+}
+
+func diffService(path string, from, to *servicedefinition.ServiceDefinition, changes *[]Change) {
+	add := func(kind, name, oldVal, newVal string) {
+		*changes = append(*changes, Change{Path: path, Kind: kind, Name: name, Old: oldVal, New: newVal})
+	}
+
+	if from.ImageID != to.ImageID {
+		add(ChangeImage, "", from.ImageID, to.ImageID)
+	}
+	if from.Command != to.Command {
+		add(ChangeCommand, "", from.Command, to.Command)
+	}
+
+	// endpoints
+	oldEndpoints := make(map[string]servicedefinition.EndpointDefinition)
+	for _, ep := range from.Endpoints {
+		oldEndpoints[ep.Name] = ep
+	}
+	newEndpoints := make(map[string]servicedefinition.EndpointDefinition)
+	for _, ep := range to.Endpoints {
+		newEndpoints[ep.Name] = ep
+	}
+	for _, name := range unionKeys(oldEndpoints, newEndpoints) {
+		oldEp, inOld := oldEndpoints[name]
+		newEp, inNew := newEndpoints[name]
+		if inOld && inNew && reflect.DeepEqual(normalizeEndpoint(oldEp), normalizeEndpoint(newEp)) {
+			continue
+		}
+		var oldVal, newVal string
+		if inOld {
+			oldVal = describeEndpoint(oldEp)
+		}
+		if inNew {
+			newVal = describeEndpoint(newEp)
+		}
+		add(ChangeEndpoint, name, oldVal, settingsChanged(oldVal, newVal))
+	}
+
+	// config files
+	for _, name := range unionKeys(from.ConfigFiles, to.ConfigFiles) {
+		oldFile, inOld := from.ConfigFiles[name]
+		newFile, inNew := to.ConfigFiles[name]
+		if inOld && inNew && oldFile == newFile {
+			continue
+		}
+		var oldVal, newVal string
+		if inOld {
+			oldVal = describeConfigFile(oldFile)
+		}
+		if inNew {
+			newVal = describeConfigFile(newFile)
+		}
+		add(ChangeConfigFile, name, oldVal, settingsChanged(oldVal, newVal))
+	}
+
+	// volumes
+	oldVolumes := make(map[string]servicedefinition.Volume)
+	for _, v := range from.Volumes {
+		oldVolumes[v.ContainerPath] = v
+	}
+	newVolumes := make(map[string]servicedefinition.Volume)
+	for _, v := range to.Volumes {
+		newVolumes[v.ContainerPath] = v
+	}
+	for _, name := range unionKeys(oldVolumes, newVolumes) {
+		oldVol, inOld := oldVolumes[name]
+		newVol, inNew := newVolumes[name]
+		if inOld && inNew && oldVol == newVol {
+			continue
+		}
+		var oldVal, newVal string
+		if inOld {
+			oldVal = describeVolume(oldVol)
+		}
+		if inNew {
+			newVal = describeVolume(newVol)
+		}
+		add(ChangeVolume, name, oldVal, settingsChanged(oldVal, newVal))
+	}
+
+	// child services
+	oldChildren := make(map[string]*servicedefinition.ServiceDefinition)
+	for i := range from.Services {
+		oldChildren[from.Services[i].Name] = &from.Services[i]
+	}
+	newChildren := make(map[string]*servicedefinition.ServiceDefinition)
+	for i := range to.Services {
+		newChildren[to.Services[i].Name] = &to.Services[i]
+	}
+	for _, name := range unionKeys(oldChildren, newChildren) {
+		oldChild, inOld := oldChildren[name]
+		newChild, inNew := newChildren[name]
+		childPath := path + "/" + name
+		switch {
+		case !inNew:
+			*changes = append(*changes, Change{Path: childPath, Kind: ChangeServiceRemoved, Old: oldChild.ImageID})
+		case !inOld:
+			*changes = append(*changes, Change{Path: childPath, Kind: ChangeServiceAdded, New: newChild.ImageID})
+		default:
+			diffService(childPath, oldChild, newChild, changes)
+		}
+	}
+}
+
+// unionKeys returns the sorted keys of two maps with string keys
+func unionKeys(a, b interface{}) []string {
+	seen := make(map[string]struct{})
+	for _, m := range []interface{}{a, b} {
+		for _, k := range reflect.ValueOf(m).MapKeys() {
+			seen[k.String()] = struct{}{}
+		}
+	}
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// settingsChanged marks a new value whose summary hides what changed
+func settingsChanged(oldVal, newVal string) string {
+	if oldVal != "" && oldVal == newVal {
+		return newVal + " (other settings changed)"
+	}
+	return newVal
+}
+
+// normalizeEndpoint clears empty lists so that an endpoint read back from the
+// database compares equal to its definition
+func normalizeEndpoint(ep servicedefinition.EndpointDefinition) servicedefinition.EndpointDefinition {
+	if len(ep.VHosts) == 0 {
+		ep.VHosts = nil
+	}
+	if len(ep.VHostList) == 0 {
+		ep.VHostList = nil
+	}
+	if len(ep.PortList) == 0 {
+		ep.PortList = nil
+	}
+	return ep
+}
+
+func describeEndpoint(ep servicedefinition.EndpointDefinition) string {
+	app := ep.Application
+	if ep.ApplicationTemplate != "" {
+		app = ep.ApplicationTemplate
+	}
+	return fmt.Sprintf("%s %s %s:%d", ep.Purpose, app, ep.Protocol, ep.PortNumber)
+}
+
+func describeConfigFile(cf servicedefinition.ConfigFile) string {
+	return fmt.Sprintf("%s %s, %d bytes", cf.Owner, cf.Permissions, len(cf.Content))
+}
+
+func describeVolume(v servicedefinition.Volume) string {
+	return fmt.Sprintf("%s %s %s %s", v.ResourcePath, v.Type, v.Owner, v.Permission)
+}
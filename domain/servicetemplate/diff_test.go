@@ -0,0 +1,84 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package servicetemplate
+
+import (
+	"testing"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+)
+
+func diffTestDefinition() servicedefinition.ServiceDefinition {
+	return servicedefinition.ServiceDefinition{
+		Name:    "app",
+		ImageID: "zenoss/app:1.0",
+		Command: "run",
+		Endpoints: []servicedefinition.EndpointDefinition{
+			{Name: "web", Purpose: "export", Application: "web", Protocol: "tcp", PortNumber: 8080},
+		},
+		ConfigFiles: map[string]servicedefinition.ConfigFile{
+			"/etc/app.conf": {Filename: "/etc/app.conf", Owner: "root:root", Permissions: "0644", Content: "a"},
+		},
+		Volumes: []servicedefinition.Volume{
+			{ResourcePath: "data", ContainerPath: "/data", Type: "dfs", Owner: "root:root", Permission: "0755"},
+		},
+		Services: []servicedefinition.ServiceDefinition{
+			{Name: "worker", ImageID: "zenoss/app:1.0", Command: "work"},
+			{Name: "cron", ImageID: "zenoss/app:1.0", Command: "cron"},
+		},
+	}
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	from, to := diffTestDefinition(), diffTestDefinition()
+	to.Endpoints[0].VHosts = []string{}
+	if changes := Diff(from, to); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %+v", changes)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	from, to := diffTestDefinition(), diffTestDefinition()
+	to.ImageID = "zenoss/app:2.0"
+	to.Endpoints[0].PortNumber = 8081
+	to.Endpoints = append(to.Endpoints, servicedefinition.EndpointDefinition{Name: "db", Purpose: "import", Application: "db", Protocol: "tcp", PortNumber: 5432})
+	to.ConfigFiles = map[string]servicedefinition.ConfigFile{
+		"/etc/app.conf": {Filename: "/etc/app.conf", Owner: "root:root", Permissions: "0644", Content: "b"},
+	}
+	to.Volumes = nil
+	to.Services[0].Command = "work harder"
+	to.Services = append(to.Services[:1], servicedefinition.ServiceDefinition{Name: "reports", ImageID: "zenoss/reports:1.0"})
+
+	expected := []Change{
+		{Path: "app", Kind: ChangeImage, Old: "zenoss/app:1.0", New: "zenoss/app:2.0"},
+		{Path: "app", Kind: ChangeEndpoint, Name: "db", New: "import db tcp:5432"},
+		{Path: "app", Kind: ChangeEndpoint, Name: "web", Old: "export web tcp:8080", New: "export web tcp:8081"},
+		{Path: "app", Kind: ChangeConfigFile, Name: "/etc/app.conf", Old: "root:root 0644, 1 bytes", New: "root:root 0644, 1 bytes (other settings changed)"},
+		{Path: "app", Kind: ChangeVolume, Name: "/data", Old: "data dfs root:root 0755"},
+		{Path: "app/cron", Kind: ChangeServiceRemoved, Old: "zenoss/app:1.0"},
+		{Path: "app/reports", Kind: ChangeServiceAdded, New: "zenoss/reports:1.0"},
+		{Path: "app/worker", Kind: ChangeCommand, Old: "work", New: "work harder"},
+	}
+	changes := Diff(from, to)
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %d: %+v", len(expected), len(changes), changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("Change %d: expected %+v, got %+v", i, expected[i], changes[i])
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+)
+
+// Kind describes how a session was started
+type Kind string
+
+const (
+	// KindShell is a 'serviced service shell' session in a new container
+	KindShell Kind = "shell"
+	// KindRun is a 'serviced service run' session in a new container
+	KindRun Kind = "run"
+	// KindAttach is a 'serviced service attach' session in a running
+	// service instance
+	KindAttach Kind = "attach"
+)
+
+// Session describes a recorded interactive session in a service container.
+// The recording itself is kept on the master in asciicast format.
+type Session struct {
+	ID         string
+	Kind       Kind
+	User       string
+	HostID     string
+	ServiceID  string
+	TenantID   string
+	InstanceID int // The attached instance, or -1 for a new container
+	Command    string
+	Started    time.Time
+	Ended      time.Time
+	ExitCode   int
+	Size       int64 // Size of the recording in bytes
+	datastore.VersionedEntity
+}
+
+// Duration returns how long the session lasted
+func (s *Session) Duration() time.Duration {
+	if s.Ended.Before(s.Started) {
+		return 0
+	}
+	return s.Ended.Sub(s.Started)
+}
+
+// ByStarted sorts sessions from the most to the least recently started
+type ByStarted []Session
+
+func (s ByStarted) Len() int           { return len(s) }
+func (s ByStarted) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s ByStarted) Less(i, j int) bool { return s[i].Started.After(s[j].Started) }
+
+// GetType returns the type of sessions in the datastore
+func GetType() string {
+	return kind
+}
+
+// GetType returns the Session's type
+func (s *Session) GetType() string {
+	return GetType()
+}
+
+// GetID returns the Session's ID
+func (s *Session) GetID() string {
+	return s.ID
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package session
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// This plumbs gocheck into testing
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type sessionSuite struct{}
+
+var _ = Suite(&sessionSuite{})
+
+func (s *sessionSuite) TestSession_ValidEntity(c *C) {
+	sess := &Session{ID: "s1", ServiceID: "svc1", Kind: "ssh", Started: time.Now()}
+	c.Assert(sess.ValidEntity(), NotNil)
+	sess.Kind = KindAttach
+	c.Assert(sess.ValidEntity(), IsNil)
+	sess.Started = time.Time{}
+	c.Assert(sess.ValidEntity(), NotNil)
+}
+
+func (s *sessionSuite) TestSession_Duration(c *C) {
+	now := time.Now()
+	sess := &Session{Started: now, Ended: now.Add(90 * time.Second)}
+	c.Assert(sess.Duration(), Equals, 90*time.Second)
+	sess.Ended = time.Time{}
+	c.Assert(sess.Duration(), Equals, time.Duration(0))
+}
+
+func (s *sessionSuite) TestByStarted(c *C) {
+	now := time.Now()
+	sessions := []Session{
+		{ID: "old", Started: now},
+		{ID: "new", Started: now.Add(time.Minute)},
+	}
+	sort.Sort(ByStarted(sessions))
+	c.Assert(sessions[0].ID, Equals, "new")
+	c.Assert(sessions[1].ID, Equals, "old")
+}
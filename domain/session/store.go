@@ -0,0 +1,124 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
+)
+
+// Filter selects the sessions to return.  Empty fields match any session.
+type Filter struct {
+	User      string
+	ServiceID string
+	TenantID  string
+}
+
+// Store is the database for shell sessions
+type Store interface {
+	// Get a Session by id. Return ErrNoSuchEntity if not found
+	Get(ctx datastore.Context, id string) (*Session, error)
+
+	// Put adds or updates a Session
+	Put(ctx datastore.Context, s *Session) error
+
+	// Delete removes a Session if it exists
+	Delete(ctx datastore.Context, id string) error
+
+	// GetSessions returns the sessions selected by the filter, most recently
+	// started first
+	GetSessions(ctx datastore.Context, filter Filter) ([]Session, error)
+}
+
+type storeImpl struct {
+	ds datastore.DataStore
+}
+
+// NewStore creates a Store for shell sessions
+func NewStore() Store {
+	return &storeImpl{}
+}
+
+// Get a Session by id.  Return ErrNoSuchEntity if not found
+func (s *storeImpl) Get(ctx datastore.Context, id string) (*Session, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("SessionStore.Get"))
+	val := &Session{}
+	if err := s.ds.Get(ctx, Key(id), val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// Put adds/updates a Session
+func (s *storeImpl) Put(ctx datastore.Context, sess *Session) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("SessionStore.Put"))
+	return s.ds.Put(ctx, Key(sess.ID), sess)
+}
+
+// Delete removes a Session
+func (s *storeImpl) Delete(ctx datastore.Context, id string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("SessionStore.Delete"))
+	return s.ds.Delete(ctx, Key(id))
+}
+
+// GetSessions returns the sessions selected by the filter, most recently
+// started first
+func (s *storeImpl) GetSessions(ctx datastore.Context, filter Filter) ([]Session, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("SessionStore.GetSessions"))
+	query := search.Search("controlplane").Type(kind).Size("50000")
+	filters := []interface{}{"and"}
+	for _, term := range []struct{ field, value string }{
+		{"User", filter.User},
+		{"ServiceID", filter.ServiceID},
+		{"TenantID", filter.TenantID},
+	} {
+		if term.value != "" {
+			filters = append(filters, search.Filter().Terms(term.field, term.value))
+		}
+	}
+	if len(filters) > 1 {
+		query = query.Filter(filters...)
+	} else {
+		query = query.Query(search.Query().Search("_exists_:ID"))
+	}
+	q := datastore.NewQuery(ctx)
+	results, err := q.Execute(query)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := convert(results)
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(ByStarted(sessions))
+	return sessions, nil
+}
+
+// Key creates a Key suitable for getting, putting and deleting Sessions
+func Key(id string) datastore.Key {
+	return datastore.NewKey(kind, strings.TrimSpace(id))
+}
+
+func convert(results datastore.Results) ([]Session, error) {
+	sessions := make([]Session, results.Len())
+	for idx := range sessions {
+		if err := results.Get(idx, &sessions[idx]); err != nil {
+			return nil, err
+		}
+	}
+	return sessions, nil
+}
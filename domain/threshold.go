@@ -84,6 +84,7 @@ func (t *DurationThreshold) UnmarshalJSON(data []byte) error {
 type HoltWintersThreshold struct {
 	Alpha  float64 //A number from 0 to 1 that controls how quickly the model adapts to unexpected values
 	Beta   float64 //A number from 0 to 1 that controls how quicly the model adapts to changes in unexpected rates changes.
+	Gamma  float64 //A number from 0 to 1 that controls how quickly the model adapts to changes in the seasonal pattern; Alpha is used if it is not set
 	Rows   int64   //The number of points to use for predictive purposes
 	Season int64   //The number of primary data points in a season.  Note that Rows must be at least as large as Season
 }
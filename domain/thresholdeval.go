@@ -0,0 +1,188 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/commons/statistics"
+)
+
+const (
+	// ThresholdMinMax is the type of a MinMaxThreshold
+	ThresholdMinMax = "MinMax"
+	// ThresholdDuration is the type of a DurationThreshold
+	ThresholdDuration = "Duration"
+	// ThresholdHoltWinters is the type of a HoltWintersThreshold
+	ThresholdHoltWinters = "HoltWinters"
+
+	// defaultEvaluationWindow is the window of data evaluated by thresholds
+	// that do not define their own
+	defaultEvaluationWindow = 5 * time.Minute
+
+	// holtWintersDeviations is the number of standard deviations of the
+	// forecast error that an observed value may stray from its forecast
+	holtWintersDeviations = 3.0
+)
+
+// ThresholdBreach describes the violation of a threshold by a data point
+type ThresholdBreach struct {
+	Value   float64 // the value that breached the threshold
+	Message string  // a human readable description of the breach
+}
+
+// ThresholdEvaluator decides whether a series of values violates a threshold
+type ThresholdEvaluator interface {
+	// Window is how much recent data the evaluator needs
+	Window() time.Duration
+	// Evaluate returns a breach if the values, oldest first, violate the
+	// threshold, or nil otherwise
+	Evaluate(values []float64) *ThresholdBreach
+}
+
+// Evaluator returns the evaluator for the threshold data of the config, which
+// may have been decoded from JSON into a generic map
+func (config *ThresholdConfig) Evaluator() (ThresholdEvaluator, error) {
+	var evaluator ThresholdEvaluator
+	switch config.Type {
+	case ThresholdMinMax:
+		evaluator = &MinMaxThreshold{}
+	case ThresholdDuration:
+		evaluator = &DurationThreshold{}
+	case ThresholdHoltWinters:
+		evaluator = &HoltWintersThreshold{}
+	default:
+		return nil, fmt.Errorf("threshold %s has unsupported type %q", config.ID, config.Type)
+	}
+	data, err := json.Marshal(config.Threshold)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, evaluator); err != nil {
+		return nil, fmt.Errorf("threshold %s has invalid %s data: %s", config.ID, config.Type, err)
+	}
+	return evaluator, nil
+}
+
+// Window returns the default evaluation window
+func (t *MinMaxThreshold) Window() time.Duration {
+	return defaultEvaluationWindow
+}
+
+// Evaluate checks the most recent value against the min and max bounds.  An
+// empty or unparseable bound is ignored.
+func (t *MinMaxThreshold) Evaluate(values []float64) *ThresholdBreach {
+	if len(values) == 0 {
+		return nil
+	}
+	value := values[len(values)-1]
+	if min, ok := parseBound(t.Min); ok && value < min {
+		return &ThresholdBreach{Value: value, Message: fmt.Sprintf("value %g is below the minimum of %g", value, min)}
+	}
+	if max, ok := parseBound(t.Max); ok && value > max {
+		return &ThresholdBreach{Value: value, Message: fmt.Sprintf("value %g is above the maximum of %g", value, max)}
+	}
+	return nil
+}
+
+func parseBound(bound string) (float64, bool) {
+	bound = strings.TrimSpace(bound)
+	if bound == "" {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(bound, 64)
+	return value, err == nil
+}
+
+// Window returns the time period of the threshold
+func (t *DurationThreshold) Window() time.Duration {
+	if t.TimePeriod <= 0 {
+		return defaultEvaluationWindow
+	}
+	return t.TimePeriod
+}
+
+// Evaluate checks whether at least Percentage percent of the values are
+// outside of the min and max bounds.  A Percentage of 0 means any violation
+// is a breach.
+func (t *DurationThreshold) Evaluate(values []float64) *ThresholdBreach {
+	if len(values) == 0 {
+		return nil
+	}
+	violations := 0
+	var last float64
+	for _, v := range values {
+		if (t.Min != nil && v < float64(*t.Min)) || (t.Max != nil && v > float64(*t.Max)) {
+			violations++
+			last = v
+		}
+	}
+	if violations == 0 {
+		return nil
+	}
+	percent := 100 * violations / len(values)
+	if percent < t.Percentage {
+		return nil
+	}
+	return &ThresholdBreach{
+		Value:   last,
+		Message: fmt.Sprintf("%d%% of values were out of bounds in the last %s", percent, t.Window()),
+	}
+}
+
+// Window returns the time covered by Rows minutes of data
+func (t *HoltWintersThreshold) Window() time.Duration {
+	if t.Rows <= 0 {
+		return defaultEvaluationWindow
+	}
+	return time.Duration(t.Rows) * time.Minute
+}
+
+// Evaluate fits a Holt-Winters model to all but the most recent value and
+// reports a breach if the most recent value strays from its forecast by more
+// than three standard deviations of the model's past forecast errors.
+func (t *HoltWintersThreshold) Evaluate(values []float64) *ThresholdBreach {
+	season := int(t.Season)
+	if season < 1 {
+		season = 1
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	// thresholds from before Gamma was added smooth the seasonal components
+	// with Alpha
+	gamma := t.Gamma
+	if gamma == 0 {
+		gamma = t.Alpha
+	}
+	history, value := values[:len(values)-1], values[len(values)-1]
+	hw, err := statistics.FitHoltWinters(history, season, t.Alpha, t.Beta, gamma)
+	if err != nil {
+		return nil
+	}
+	deviation := math.Sqrt(hw.SSE / float64(hw.N))
+	forecast := hw.Forecast(1)
+	if math.Abs(value-forecast) <= holtWintersDeviations*deviation {
+		return nil
+	}
+	return &ThresholdBreach{
+		Value:   value,
+		Message: fmt.Sprintf("value %g deviates from the forecast of %g", value, forecast),
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package domain
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestThresholdConfigEvaluator(t *testing.T) {
+	var config ThresholdConfig
+	data := `{"ID":"mem","Type":"Duration","Threshold":{"Max":99,"TimePeriod":60,"Percentage":50}}`
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		t.Fatalf("Could not unmarshal threshold config: %s", err)
+	}
+	evaluator, err := config.Evaluator()
+	if err != nil {
+		t.Fatalf("Could not get evaluator: %s", err)
+	}
+	if window := evaluator.Window(); window != time.Minute {
+		t.Errorf("Expected a window of 1m, got %s", window)
+	}
+
+	config.Type = "ValueChange"
+	if _, err := config.Evaluator(); err == nil {
+		t.Errorf("Expected an error for an unsupported threshold type")
+	}
+}
+
+func TestMinMaxThresholdEvaluate(t *testing.T) {
+	threshold := MinMaxThreshold{Min: "10", Max: "20"}
+	if breach := threshold.Evaluate([]float64{5, 15}); breach != nil {
+		t.Errorf("Unexpected breach: %+v", breach)
+	}
+	if breach := threshold.Evaluate([]float64{15, 5}); breach == nil || breach.Value != 5 {
+		t.Errorf("Expected a breach of the minimum, got %+v", breach)
+	}
+	if breach := threshold.Evaluate([]float64{25}); breach == nil || breach.Value != 25 {
+		t.Errorf("Expected a breach of the maximum, got %+v", breach)
+	}
+	if breach := (&MinMaxThreshold{Max: "20"}).Evaluate([]float64{-100}); breach != nil {
+		t.Errorf("Unexpected breach of an empty minimum: %+v", breach)
+	}
+}
+
+func TestDurationThresholdEvaluate(t *testing.T) {
+	threshold := testDTH
+	threshold.Percentage = 50
+	if breach := threshold.Evaluate([]float64{50, 100, 50, 50}); breach != nil {
+		t.Errorf("Unexpected breach: %+v", breach)
+	}
+	if breach := threshold.Evaluate([]float64{50, 100, 0, 50}); breach == nil {
+		t.Errorf("Expected a breach")
+	}
+	threshold.Percentage = 0
+	if breach := threshold.Evaluate([]float64{50, 100, 50, 50}); breach == nil || breach.Value != 100 {
+		t.Errorf("Expected a breach on any violation, got %+v", breach)
+	}
+}
+
+func TestHoltWintersThresholdEvaluate(t *testing.T) {
+	threshold := HoltWintersThreshold{Alpha: 0.5, Beta: 0.1, Gamma: 0.3, Rows: 60, Season: 4}
+	values := []float64{}
+	for i := 0; i < 40; i++ {
+		values = append(values, float64(10+(i%4)*5+i%3))
+	}
+	if breach := threshold.Evaluate(append(values, 10)); breach != nil {
+		t.Errorf("Unexpected breach: %+v", breach)
+	}
+	if breach := threshold.Evaluate(append(values, 200)); breach == nil {
+		t.Errorf("Expected a breach")
+	}
+	if breach := threshold.Evaluate(values[:5]); breach != nil {
+		t.Errorf("Unexpected breach with too little data: %+v", breach)
+	}
+}
+
+func TestHoltWintersThresholdEvaluateWithoutGamma(t *testing.T) {
+	legacy := HoltWintersThreshold{Alpha: 0.5, Beta: 0.1, Rows: 60, Season: 4}
+	explicit := HoltWintersThreshold{Alpha: 0.5, Beta: 0.1, Gamma: 0.5, Rows: 60, Season: 4}
+	values := []float64{}
+	for i := 0; i < 40; i++ {
+		// the seasonal pattern doubles halfway through
+		values = append(values, float64((1+i/20)*(10+(i%4)*5)))
+	}
+	for _, value := range []float64{10, 70, 200} {
+		expected := explicit.Evaluate(append(values, value))
+		actual := legacy.Evaluate(append(values, value))
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Expected %+v for %g without Gamma, got %+v", expected, value, actual)
+		}
+	}
+}
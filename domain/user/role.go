@@ -0,0 +1,238 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrAccessDenied is returned when a user does not hold a role that allows
+// the requested action
+var ErrAccessDenied = errors.New("access denied")
+
+// Role is a named set of actions that may be granted to a user
+type Role string
+
+const (
+	// RoleViewer may read everything within its scope
+	RoleViewer Role = "viewer"
+	// RoleOperator may additionally start, stop and restart services
+	RoleOperator Role = "operator"
+	// RoleTenantAdmin may additionally edit and snapshot the services of a
+	// tenant
+	RoleTenantAdmin Role = "tenant-admin"
+	// RoleClusterAdmin may perform any action
+	RoleClusterAdmin Role = "cluster-admin"
+)
+
+// Roles lists every valid role, from the least to the most privileged
+var Roles = []Role{RoleViewer, RoleOperator, RoleTenantAdmin, RoleClusterAdmin}
+
+// Action is an operation that is subject to authorization
+type Action string
+
+const (
+	// ActionView reads services, hosts, pools and templates
+	ActionView Action = "view"
+	// ActionControlService starts, stops, restarts and kills service instances
+	ActionControlService Action = "control-service"
+	// ActionEditService adds, updates, removes and deploys services and their
+	// endpoints, ips and config files
+	ActionEditService Action = "edit-service"
+	// ActionSnapshot takes and rolls back snapshots of a tenant
+	ActionSnapshot Action = "snapshot"
+	// ActionEditHost adds, updates and removes hosts
+	ActionEditHost Action = "edit-host"
+	// ActionEditPool adds, updates and removes resource pools
+	ActionEditPool Action = "edit-pool"
+	// ActionEditTemplate adds, removes and deploys service templates
+	ActionEditTemplate Action = "edit-template"
+	// ActionBackup creates backups
+	ActionBackup Action = "backup"
+	// ActionRestore restores backups
+	ActionRestore Action = "restore"
+	// ActionManageUsers adds, removes and grants roles to users
+	ActionManageUsers Action = "manage-users"
+	// ActionManageCertificates adds and removes the certificates of virtual
+	// hosts and public ports
+	ActionManageCertificates Action = "manage-certificates"
+	// ActionViewAudit reads the audit trail
+	ActionViewAudit Action = "view-audit"
+)
+
+// permissions describes the actions allowed by each role
+var permissions = map[Role][]Action{
+	RoleViewer:      {ActionView},
+	RoleOperator:    {ActionView, ActionControlService},
+	RoleTenantAdmin: {ActionView, ActionControlService, ActionEditService, ActionSnapshot},
+	RoleClusterAdmin: {
+		ActionView, ActionControlService, ActionEditService, ActionSnapshot,
+		ActionEditHost, ActionEditPool, ActionEditTemplate, ActionBackup,
+		ActionRestore, ActionManageUsers, ActionManageCertificates,
+		ActionViewAudit,
+	},
+}
+
+// Allows returns true if the role permits the action
+func (r Role) Allows(action Action) bool {
+	for _, a := range permissions[r] {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// Valid returns an error if the role is unknown
+func (r Role) Valid() error {
+	if _, ok := permissions[r]; !ok {
+		return fmt.Errorf("unknown role %q", r)
+	}
+	return nil
+}
+
+// Scope identifies the entity an action is performed against. An empty field
+// means the action is not bound to a tenant or pool.
+type Scope struct {
+	TenantID string
+	PoolID   string
+}
+
+// AnyScope is used when checking whether a user holds a role anywhere
+var AnyScope = Scope{TenantID: "*", PoolID: "*"}
+
+// RoleBinding grants a role to a user, optionally restricted to a single
+// tenant or resource pool.  A binding with neither TenantID nor PoolID set
+// applies to the whole cluster.
+type RoleBinding struct {
+	Role     Role
+	TenantID string `json:",omitempty"`
+	PoolID   string `json:",omitempty"`
+}
+
+// Global returns true if the binding is not restricted to a tenant or pool
+func (b RoleBinding) Global() bool {
+	return b.TenantID == "" && b.PoolID == ""
+}
+
+// Covers returns true if the binding applies to the scope
+func (b RoleBinding) Covers(scope Scope) bool {
+	if b.Global() || scope == AnyScope {
+		return true
+	}
+	if b.TenantID != "" && b.TenantID != scope.TenantID {
+		return false
+	}
+	if b.PoolID != "" && b.PoolID != scope.PoolID {
+		return false
+	}
+	return true
+}
+
+// String describes the binding, e.g. "operator@tenant:abc123"
+func (b RoleBinding) String() string {
+	switch {
+	case b.TenantID != "":
+		return fmt.Sprintf("%s@tenant:%s", b.Role, b.TenantID)
+	case b.PoolID != "":
+		return fmt.Sprintf("%s@pool:%s", b.Role, b.PoolID)
+	default:
+		return string(b.Role)
+	}
+}
+
+// ParseRoleBinding reads a binding in the form written by String, e.g.
+// "operator", "operator@tenant:abc123" or "viewer@pool:default"
+func ParseRoleBinding(s string) (RoleBinding, error) {
+	parts := strings.SplitN(strings.TrimSpace(s), "@", 2)
+	binding := RoleBinding{Role: Role(parts[0])}
+	if len(parts) == 2 {
+		scope := strings.SplitN(parts[1], ":", 2)
+		if len(scope) != 2 || scope[1] == "" {
+			return RoleBinding{}, fmt.Errorf("invalid role binding %q", s)
+		}
+		switch scope[0] {
+		case "tenant":
+			binding.TenantID = scope[1]
+		case "pool":
+			binding.PoolID = scope[1]
+		default:
+			return RoleBinding{}, fmt.Errorf("invalid role binding %q", s)
+		}
+	}
+	if err := binding.ValidEntity(); err != nil {
+		return RoleBinding{}, err
+	}
+	return binding, nil
+}
+
+// RoleBindings is the set of roles held by a user
+type RoleBindings []RoleBinding
+
+// ClusterAdmin is the set of role bindings granted to the system user and to
+// members of the host's administrative group.
+var ClusterAdmin = RoleBindings{{Role: RoleClusterAdmin}}
+
+// Allows returns true if any binding permits the action on the scope
+func (bs RoleBindings) Allows(action Action, scope Scope) bool {
+	for _, b := range bs {
+		if b.Role.Allows(action) && b.Covers(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize returns ErrAccessDenied if no binding permits the action on the
+// scope
+func (bs RoleBindings) Authorize(action Action, scope Scope) error {
+	if !bs.Allows(action, scope) {
+		return ErrAccessDenied
+	}
+	return nil
+}
+
+// Add grants a binding, returning false if it is already held
+func (bs *RoleBindings) Add(binding RoleBinding) bool {
+	for _, b := range *bs {
+		if b == binding {
+			return false
+		}
+	}
+	*bs = append(*bs, binding)
+	return true
+}
+
+// Remove revokes a binding, returning false if it was not held
+func (bs *RoleBindings) Remove(binding RoleBinding) bool {
+	for i, b := range *bs {
+		if b == binding {
+			*bs = append((*bs)[:i], (*bs)[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// AccessRequest asks whether a user may perform an action.  The scope of the
+// request is derived from the service, host or pool it names.
+type AccessRequest struct {
+	User      string
+	Action    Action
+	ServiceID string
+	HostID    string
+	PoolID    string
+}
@@ -0,0 +1,112 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package user
+
+import (
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+// This plumbs gocheck into testing
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type roleSuite struct{}
+
+var _ = Suite(&roleSuite{})
+
+func (s *roleSuite) TestRole_Allows(c *C) {
+	c.Assert(RoleViewer.Allows(ActionView), Equals, true)
+	c.Assert(RoleViewer.Allows(ActionControlService), Equals, false)
+	c.Assert(RoleOperator.Allows(ActionControlService), Equals, true)
+	c.Assert(RoleOperator.Allows(ActionEditService), Equals, false)
+	c.Assert(RoleTenantAdmin.Allows(ActionEditService), Equals, true)
+	c.Assert(RoleTenantAdmin.Allows(ActionEditHost), Equals, false)
+	c.Assert(RoleTenantAdmin.Allows(ActionRestore), Equals, false)
+	c.Assert(RoleClusterAdmin.Allows(ActionRestore), Equals, true)
+	c.Assert(Role("bogus").Allows(ActionView), Equals, false)
+}
+
+func (s *roleSuite) TestRoleBindings_TenantScope(c *C) {
+	roles := RoleBindings{{Role: RoleOperator, TenantID: "tenant1"}}
+
+	c.Assert(roles.Allows(ActionControlService, Scope{TenantID: "tenant1", PoolID: "default"}), Equals, true)
+	c.Assert(roles.Allows(ActionControlService, Scope{TenantID: "tenant2", PoolID: "default"}), Equals, false)
+	c.Assert(roles.Allows(ActionEditHost, Scope{PoolID: "default"}), Equals, false)
+	c.Assert(roles.Authorize(ActionRestore, Scope{}), Equals, ErrAccessDenied)
+	c.Assert(roles.Allows(ActionView, AnyScope), Equals, true)
+}
+
+func (s *roleSuite) TestRoleBindings_PoolScope(c *C) {
+	roles := RoleBindings{{Role: RoleTenantAdmin, PoolID: "pool1"}}
+
+	c.Assert(roles.Allows(ActionEditService, Scope{TenantID: "tenant1", PoolID: "pool1"}), Equals, true)
+	c.Assert(roles.Allows(ActionEditService, Scope{TenantID: "tenant1", PoolID: "pool2"}), Equals, false)
+}
+
+func (s *roleSuite) TestRoleBindings_Global(c *C) {
+	c.Assert(ClusterAdmin.Allows(ActionRestore, Scope{}), Equals, true)
+	c.Assert(ClusterAdmin.Allows(ActionEditHost, Scope{PoolID: "pool1"}), Equals, true)
+	c.Assert(RoleBindings{}.Allows(ActionView, AnyScope), Equals, false)
+}
+
+func (s *roleSuite) TestRoleBindings_AddRemove(c *C) {
+	var roles RoleBindings
+	binding := RoleBinding{Role: RoleViewer, TenantID: "tenant1"}
+
+	c.Assert(roles.Add(binding), Equals, true)
+	c.Assert(roles.Add(binding), Equals, false)
+	c.Assert(roles, HasLen, 1)
+	c.Assert(roles.Remove(RoleBinding{Role: RoleViewer}), Equals, false)
+	c.Assert(roles.Remove(binding), Equals, true)
+	c.Assert(roles, HasLen, 0)
+}
+
+func (s *roleSuite) TestParseRoleBinding(c *C) {
+	for _, binding := range []RoleBinding{
+		{Role: RoleClusterAdmin},
+		{Role: RoleOperator, TenantID: "tenant1"},
+		{Role: RoleViewer, PoolID: "pool1"},
+	} {
+		parsed, err := ParseRoleBinding(binding.String())
+		c.Assert(err, IsNil)
+		c.Assert(parsed, Equals, binding)
+	}
+
+	for _, invalid := range []string{"", "boss", "viewer@tenant", "viewer@host:h1", "cluster-admin@pool:p1"} {
+		_, err := ParseRoleBinding(invalid)
+		c.Check(err, NotNil, Commentf("%q", invalid))
+	}
+}
+
+func (s *roleSuite) TestUser_ValidRoles(c *C) {
+	u := User{Name: "jdoe", Password: "secret"}
+	c.Assert(u.ValidEntity(), IsNil)
+
+	u.Roles = RoleBindings{{Role: RoleOperator, TenantID: "tenant1"}}
+	c.Assert(u.ValidEntity(), IsNil)
+
+	u.Roles = RoleBindings{{Role: "superuser"}}
+	c.Assert(u.ValidEntity(), NotNil)
+
+	u.Roles = RoleBindings{{Role: RoleClusterAdmin, TenantID: "tenant1"}}
+	c.Assert(u.ValidEntity(), NotNil)
+
+	u.Roles = RoleBindings{{Role: RoleViewer, TenantID: "tenant1", PoolID: "pool1"}}
+	c.Assert(u.ValidEntity(), NotNil)
+}
@@ -20,8 +20,9 @@ import (
 
 // User for the system???
 type User struct {
-	Name     string // the unique identifier for a user
-	Password string // no requirements on passwords yet
+	Name     string       // the unique identifier for a user
+	Password string       // no requirements on passwords yet
+	Roles    RoleBindings // the roles granted to the user
 	datastore.VersionedEntity
 }
 
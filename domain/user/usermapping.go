@@ -23,7 +23,14 @@ var (
      "user": {
       "properties":{
         "Name":           {"type": "string", "index":"not_analyzed"},
-        "Password":       {"type": "string", "index":"not_analyzed"}
+        "Password":       {"type": "string", "index":"not_analyzed"},
+        "Roles": {
+          "properties": {
+            "Role":     {"type": "string", "index":"not_analyzed"},
+            "TenantID": {"type": "string", "index":"not_analyzed"},
+            "PoolID":   {"type": "string", "index":"not_analyzed"}
+          }
+        }
       }
     }
 }
@@ -15,6 +15,7 @@ package user
 
 import (
 	"github.com/control-center/serviced/datastore"
+	"github.com/zenoss/elastigo/search"
 
 	"strings"
 )
@@ -27,12 +28,34 @@ func NewStore() Store {
 // UserStore type for interacting with User persistent storage
 type Store interface {
 	datastore.EntityStore
+
+	// GetUsers returns all of the users
+	GetUsers(ctx datastore.Context) ([]User, error)
 }
 
 type userStoreImpl struct {
 	datastore.DataStore
 }
 
+// GetUsers returns all of the users
+func (s *userStoreImpl) GetUsers(ctx datastore.Context) ([]User, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("UserStore.GetUsers"))
+	q := datastore.NewQuery(ctx)
+	query := search.Query().Search("_exists_:Name")
+	search := search.Search("controlplane").Type(kind).Size("50000").Query(query)
+	results, err := q.Execute(search)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]User, results.Len())
+	for i := range users {
+		if err := results.Get(i, &users[i]); err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
+}
+
 //Key creates a Key suitable for getting, putting and deleting Users
 func Key(id string) datastore.Key {
 	id = strings.TrimSpace(id)
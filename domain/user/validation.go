@@ -14,6 +14,7 @@
 package user
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/control-center/serviced/validation"
@@ -28,8 +29,26 @@ func (u *User) ValidEntity() error {
 
 	violations.Add(validation.NotEmpty("User.Password", u.Password))
 
+	for _, binding := range u.Roles {
+		violations.Add(binding.ValidEntity())
+	}
+
 	if len(violations.Errors) > 0 {
 		return violations
 	}
 	return nil
 }
+
+// ValidEntity validates a role binding
+func (b RoleBinding) ValidEntity() error {
+	if err := b.Role.Valid(); err != nil {
+		return err
+	}
+	if b.TenantID != "" && b.PoolID != "" {
+		return fmt.Errorf("role %s cannot be bound to both a tenant and a pool", b.Role)
+	}
+	if b.Role == RoleClusterAdmin && !b.Global() {
+		return fmt.Errorf("role %s cannot be bound to a tenant or pool", b.Role)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain"
+	"github.com/control-center/serviced/domain/alert"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/utils"
+)
+
+const (
+	// thresholdAppliedToRunning restricts a threshold to running services
+	thresholdAppliedToRunning = 2
+
+	// serviceIDTag is the metric tag that identifies the service a metric
+	// was reported by
+	serviceIDTag = "controlplane_service_id"
+)
+
+// ErrNoMetricsClient is returned when thresholds are evaluated without a
+// connection to the metrics server
+var ErrNoMetricsClient = errors.New("no metrics client is available")
+
+// GetAlerts returns the active alerts, or every alert if all is set
+func (f *Facade) GetAlerts(ctx datastore.Context, all bool) ([]alert.Alert, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetAlerts"))
+	if all {
+		return f.alertStore.GetAlerts(ctx)
+	}
+	return f.alertStore.GetAlerts(ctx, alert.StateOpen, alert.StateAcknowledged)
+}
+
+// GetAlert returns an alert by its id
+func (f *Facade) GetAlert(ctx datastore.Context, alertID string) (*alert.Alert, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetAlert"))
+	return f.alertStore.Get(ctx, alertID)
+}
+
+// AcknowledgeAlert records that the user has taken ownership of an active
+// alert
+func (f *Facade) AcknowledgeAlert(ctx datastore.Context, alertID, userName string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.AcknowledgeAlert"))
+	alog := f.auditLogger.Message(ctx, "Acknowledging Alert").Action(audit.Update).Type(alert.GetType()).ID(alertID)
+	a, err := f.alertStore.Get(ctx, alertID)
+	if err != nil {
+		return alog.Error(err)
+	}
+	if err := a.Acknowledge(userName, time.Now()); err != nil {
+		return alog.Error(err)
+	}
+	if err := f.alertStore.Put(ctx, a); err != nil {
+		return alog.Error(err)
+	}
+	alog.Succeeded()
+	return nil
+}
+
+// EvaluateThresholds queries the metrics of every service against the
+// thresholds in its monitoring profile.  A new alert is opened for each
+// breached data point that does not already have an active alert, and the
+// active alerts of data points that are no longer breached are resolved.
+func (f *Facade) EvaluateThresholds(ctx datastore.Context) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.EvaluateThresholds"))
+	if f.metricsClient == nil {
+		return ErrNoMetricsClient
+	}
+
+	active, err := f.alertStore.GetAlerts(ctx, alert.StateOpen, alert.StateAcknowledged)
+	if err != nil {
+		return err
+	}
+	svcs, err := f.serviceStore.GetServices(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range svcs {
+		svc := &svcs[i]
+		for _, threshold := range svc.MonitoringProfile.ThresholdConfigs {
+			logger := plog.WithFields(logrus.Fields{
+				"serviceid":   svc.ID,
+				"thresholdid": threshold.ID,
+			})
+			if threshold.AppliedTo == thresholdAppliedToRunning && svc.DesiredState != int(service.SVCRun) {
+				continue
+			}
+			evaluator, err := threshold.Evaluator()
+			if err != nil {
+				logger.WithError(err).Debug("Skipping threshold")
+				continue
+			}
+			for _, dataPoint := range threshold.DataPoints {
+				candidate := &alert.Alert{
+					ServiceID:     svc.ID,
+					ThresholdID:   threshold.ID,
+					ThresholdName: threshold.Name,
+					MetricSource:  threshold.MetricSource,
+					DataPoint:     dataPoint,
+				}
+				series, err := f.metricsClient.GetMetricSeries(evaluator.Window(), "avg", dataPoint, map[string][]string{serviceIDTag: {svc.ID}})
+				if err != nil {
+					logger.WithError(err).WithField("datapoint", dataPoint).Warn("Unable to query metrics for threshold")
+					continue
+				}
+				breach := evaluator.Evaluate(series.Y())
+				current := findAlert(active, candidate)
+				if breach != nil && current == nil {
+					if err := f.openAlert(ctx, candidate, breach, now); err != nil {
+						logger.WithError(err).WithField("datapoint", dataPoint).Warn("Unable to open alert")
+					}
+				} else if breach == nil && current != nil {
+					current.Resolve(now)
+					if err := f.alertStore.Put(ctx, current); err != nil {
+						logger.WithError(err).WithField("alertid", current.ID).Warn("Unable to resolve alert")
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (f *Facade) openAlert(ctx datastore.Context, a *alert.Alert, breach *domain.ThresholdBreach, now time.Time) error {
+	var err error
+	if a.ID, err = utils.NewUUID36(); err != nil {
+		return err
+	}
+	if a.TenantID, err = f.GetTenantID(ctx, a.ServiceID); err != nil {
+		return err
+	}
+	a.Value = breach.Value
+	a.Message = breach.Message
+	a.State = alert.StateOpen
+	a.Opened = now
+	if err := f.alertStore.Put(ctx, a); err != nil {
+		return err
+	}
+	plog.WithFields(logrus.Fields{
+		"alertid":     a.ID,
+		"serviceid":   a.ServiceID,
+		"thresholdid": a.ThresholdID,
+		"datapoint":   a.DataPoint,
+	}).Info(a.Message)
+	f.notifyAlertOpened(a)
+	return nil
+}
+
+// findAlert returns the alert in alerts that matches a, if any
+func findAlert(alerts []alert.Alert, a *alert.Alert) *alert.Alert {
+	for i := range alerts {
+		if alerts[i].Matches(a) {
+			return &alerts[i]
+		}
+	}
+	return nil
+}
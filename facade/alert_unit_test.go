@@ -0,0 +1,109 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"github.com/control-center/serviced/domain"
+	"github.com/control-center/serviced/domain/alert"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/metrics"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+var activeAlertStates = []alert.State{alert.StateOpen, alert.StateAcknowledged}
+
+func (ft *FacadeUnitTest) setupThresholdService(values ...float64) {
+	svc := service.Service{
+		ID:           "alerttenant",
+		PoolID:       "default",
+		DesiredState: int(service.SVCRun),
+		MonitoringProfile: domain.MonitorProfile{
+			ThresholdConfigs: []domain.ThresholdConfig{
+				{
+					ID:           "memory",
+					Name:         "Memory",
+					Type:         domain.ThresholdMinMax,
+					MetricSource: "metrics",
+					DataPoints:   []string{"cgroup.memory.totalrss"},
+					Threshold:    map[string]interface{}{"Max": "100"},
+				},
+			},
+		},
+	}
+	ft.serviceStore.On("GetServices", ft.ctx).Return([]service.Service{svc}, nil)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, svc.ID).
+		Return(&service.ServiceDetails{ID: svc.ID, PoolID: svc.PoolID}, nil)
+
+	datapoints := make([]metrics.Datapoint, len(values))
+	for i, v := range values {
+		datapoints[i] = metrics.Datapoint{Timestamp: int64(i), Value: metrics.Float{Value: v}}
+	}
+	series := metrics.DatapointsToSeries(datapoints)
+	ft.metricsClient.On("GetMetricSeries", mock.AnythingOfType("time.Duration"), "avg", "cgroup.memory.totalrss",
+		map[string][]string{"controlplane_service_id": {svc.ID}}).Return(&series, nil)
+}
+
+func (ft *FacadeUnitTest) Test_EvaluateThresholds_Opens(c *C) {
+	ft.setupThresholdService(50, 150)
+	ft.alertStore.On("GetAlerts", ft.ctx, activeAlertStates).Return([]alert.Alert{}, nil)
+	ft.alertStore.On("Put", ft.ctx, mock.AnythingOfType("*alert.Alert")).Return(nil)
+
+	c.Assert(ft.Facade.EvaluateThresholds(ft.ctx), IsNil)
+	ft.alertStore.AssertNumberOfCalls(c, "Put", 1)
+	a := ft.alertStore.Calls[len(ft.alertStore.Calls)-1].Arguments.Get(1).(*alert.Alert)
+	c.Assert(a.ID, Not(Equals), "")
+	c.Assert(a.TenantID, Equals, "alerttenant")
+	c.Assert(a.State, Equals, alert.StateOpen)
+	c.Assert(a.Value, Equals, 150.0)
+	c.Assert(a.ValidEntity(), IsNil)
+}
+
+func (ft *FacadeUnitTest) Test_EvaluateThresholds_KeepsActive(c *C) {
+	ft.setupThresholdService(150)
+	active := alert.Alert{ID: "a1", ServiceID: "alerttenant", ThresholdID: "memory", MetricSource: "metrics",
+		DataPoint: "cgroup.memory.totalrss", State: alert.StateAcknowledged}
+	ft.alertStore.On("GetAlerts", ft.ctx, activeAlertStates).Return([]alert.Alert{active}, nil)
+
+	c.Assert(ft.Facade.EvaluateThresholds(ft.ctx), IsNil)
+	ft.alertStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_EvaluateThresholds_Resolves(c *C) {
+	ft.setupThresholdService(150, 50)
+	active := alert.Alert{ID: "a1", ServiceID: "alerttenant", ThresholdID: "memory", MetricSource: "metrics",
+		DataPoint: "cgroup.memory.totalrss", State: alert.StateOpen}
+	ft.alertStore.On("GetAlerts", ft.ctx, activeAlertStates).Return([]alert.Alert{active}, nil)
+	ft.alertStore.On("Put", ft.ctx, mock.AnythingOfType("*alert.Alert")).Return(nil)
+
+	c.Assert(ft.Facade.EvaluateThresholds(ft.ctx), IsNil)
+	a := ft.alertStore.Calls[len(ft.alertStore.Calls)-1].Arguments.Get(1).(*alert.Alert)
+	c.Assert(a.ID, Equals, "a1")
+	c.Assert(a.State, Equals, alert.StateResolved)
+}
+
+func (ft *FacadeUnitTest) Test_AcknowledgeAlert(c *C) {
+	ft.alertStore.On("Get", ft.ctx, "a1").Return(&alert.Alert{ID: "a1", State: alert.StateOpen}, nil)
+	ft.alertStore.On("Get", ft.ctx, "a2").Return(&alert.Alert{ID: "a2", State: alert.StateResolved}, nil)
+	ft.alertStore.On("Put", ft.ctx, mock.AnythingOfType("*alert.Alert")).Return(nil)
+
+	c.Assert(ft.Facade.AcknowledgeAlert(ft.ctx, "a1", "jdoe"), IsNil)
+	a := ft.alertStore.Calls[len(ft.alertStore.Calls)-1].Arguments.Get(1).(*alert.Alert)
+	c.Assert(a.State, Equals, alert.StateAcknowledged)
+	c.Assert(a.AcknowledgedBy, Equals, "jdoe")
+
+	c.Assert(ft.Facade.AcknowledgeAlert(ft.ctx, "a2", "jdoe"), Equals, alert.ErrAlertResolved)
+}
@@ -0,0 +1,121 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/auditlog"
+	"github.com/control-center/serviced/utils"
+)
+
+// auditQueueSize is the number of audit events that may wait to be written
+// to the datastore before new events are dropped
+const auditQueueSize = 1024
+
+// auditRecorder writes audit events to the datastore in the background, so
+// that recording an event never blocks or fails the audited operation
+type auditRecorder struct {
+	ctx    datastore.Context
+	store  auditlog.Store
+	events chan audit.Event
+}
+
+// Record queues an event to be written to the datastore
+func (r *auditRecorder) Record(event audit.Event) {
+	select {
+	case r.events <- event:
+	default:
+		plog.WithFields(logrus.Fields{
+			"action": event.Action,
+			"type":   event.Type,
+			"id":     event.EntityID,
+		}).Warn("Audit trail is backed up, dropping event")
+	}
+}
+
+// run writes queued events until shutdown, and then writes the events that
+// are still queued
+func (r *auditRecorder) run(shutdown <-chan interface{}) {
+	for {
+		select {
+		case event := <-r.events:
+			r.write(event)
+		case <-shutdown:
+			for {
+				select {
+				case event := <-r.events:
+					r.write(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (r *auditRecorder) write(event audit.Event) {
+	id, err := utils.NewUUID36()
+	if err != nil {
+		plog.WithError(err).Warn("Unable to create an id for an audit event")
+		return
+	}
+	if err := r.store.Put(r.ctx, &auditlog.Event{ID: id, Event: event}); err != nil {
+		plog.WithError(err).WithFields(logrus.Fields{
+			"action": event.Action,
+			"type":   event.Type,
+			"id":     event.EntityID,
+		}).Warn("Unable to record audit event")
+	}
+}
+
+// StartAuditTrail persists every audit log entry written by the facade in the
+// datastore until shutdown
+func (f *Facade) StartAuditTrail(ctx datastore.Context, shutdown <-chan interface{}) {
+	r := &auditRecorder{
+		ctx:    ctx,
+		store:  f.auditEventStore,
+		events: make(chan audit.Event, auditQueueSize),
+	}
+	f.auditLogger = audit.NewRecordingLogger(r)
+	go r.run(shutdown)
+}
+
+// GetAuditEvents returns the recorded audit events selected by the filter,
+// newest first
+func (f *Facade) GetAuditEvents(ctx datastore.Context, filter auditlog.Filter) ([]auditlog.Event, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetAuditEvents"))
+	return f.auditEventStore.GetEvents(ctx, filter)
+}
+
+// PruneAuditEvents removes the audit events recorded more than retention ago
+// and returns the number of events removed
+func (f *Facade) PruneAuditEvents(ctx datastore.Context, retention time.Duration) (int, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.PruneAuditEvents"))
+	count, err := f.auditEventStore.DeleteBefore(ctx, time.Now().Add(-retention))
+	if err != nil {
+		plog.WithError(err).Warn("Unable to prune the audit trail")
+		return count, err
+	}
+	if count > 0 {
+		plog.WithFields(logrus.Fields{
+			"count":     count,
+			"retention": retention,
+		}).Info("Pruned the audit trail")
+	}
+	return count, nil
+}
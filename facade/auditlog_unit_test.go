@@ -0,0 +1,76 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/domain/auditlog"
+	"github.com/control-center/serviced/domain/certificate"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_StartAuditTrail(c *C) {
+	shutdown := make(chan interface{})
+	defer close(shutdown)
+	recorded := make(chan *auditlog.Event, 1)
+	ft.ctx.On("User").Return("admin")
+	ft.auditEventStore.On("Put", ft.ctx, mock.AnythingOfType("*auditlog.Event")).Return(nil).Run(func(args mock.Arguments) {
+		recorded <- args.Get(1).(*auditlog.Event)
+	})
+	ft.certificateStore.On("Delete", ft.ctx, "app").Return(nil)
+
+	ft.Facade.StartAuditTrail(ft.ctx, shutdown)
+	c.Assert(ft.Facade.RemoveCertificate(ft.ctx, "app"), IsNil)
+
+	select {
+	case e := <-recorded:
+		c.Check(e.ID, Not(Equals), "")
+		c.Check(e.User, Equals, "admin")
+		c.Check(e.Action, Equals, audit.Remove)
+		c.Check(e.Type, Equals, certificate.GetType())
+		c.Check(e.EntityID, Equals, "app")
+		c.Check(e.Success, Equals, true)
+		c.Check(e.Time.IsZero(), Equals, false)
+	case <-time.After(5 * time.Second):
+		c.Fatalf("audit event was not recorded")
+	}
+}
+
+func (ft *FacadeUnitTest) Test_GetAuditEvents(c *C) {
+	filter := auditlog.Filter{User: "admin", Limit: 10}
+	events := []auditlog.Event{{ID: "e1"}}
+	ft.auditEventStore.On("GetEvents", ft.ctx, filter).Return(events, nil)
+
+	result, err := ft.Facade.GetAuditEvents(ft.ctx, filter)
+	c.Assert(err, IsNil)
+	c.Assert(result, DeepEquals, events)
+}
+
+func (ft *FacadeUnitTest) Test_PruneAuditEvents(c *C) {
+	retention := 24 * time.Hour
+	expected := time.Now().Add(-retention)
+	ft.auditEventStore.On("DeleteBefore", ft.ctx, mock.MatchedBy(func(before time.Time) bool {
+		d := before.Sub(expected)
+		return d >= 0 && d < time.Minute
+	})).Return(3, nil)
+
+	count, err := ft.Facade.PruneAuditEvents(ft.ctx, retention)
+	c.Assert(err, IsNil)
+	c.Assert(count, Equals, 3)
+}
@@ -0,0 +1,128 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"sort"
+	"time"
+
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/certificate"
+)
+
+// GetCertificates returns the certificates of the virtual hosts and public
+// ports
+func (f *Facade) GetCertificates(ctx datastore.Context) ([]certificate.Certificate, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetCertificates"))
+	return f.certificateStore.GetCertificates(ctx)
+}
+
+// GetCertificate returns a certificate by its hostname or port address
+func (f *Facade) GetCertificate(ctx datastore.Context, certificateID string) (*certificate.Certificate, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetCertificate"))
+	return f.certificateStore.Get(ctx, certificate.Normalize(certificateID))
+}
+
+// AddCertificate adds the certificate of a hostname or port address, or
+// replaces it if one exists.  The subject and validity of the certificate are
+// read from its PEM data.
+func (f *Facade) AddCertificate(ctx datastore.Context, cert *certificate.Certificate) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.AddCertificate"))
+	cert.ID = certificate.Normalize(cert.ID)
+	alog := f.auditLogger.Message(ctx, "Adding Certificate").Action(audit.Add).Type(certificate.GetType()).ID(cert.ID)
+	if err := cert.Load(); err != nil {
+		return alog.Error(err)
+	}
+	if err := cert.ValidEntity(); err != nil {
+		return alog.Error(err)
+	}
+	if current, err := f.certificateStore.Get(ctx, cert.ID); err == nil {
+		cert.DatabaseVersion = current.DatabaseVersion
+	} else if !datastore.IsErrNoSuchEntity(err) {
+		return alog.Error(err)
+	}
+	if err := f.certificateStore.Put(ctx, cert); err != nil {
+		return alog.Error(err)
+	}
+	alog.Succeeded()
+	return nil
+}
+
+// RemoveCertificate removes a certificate.  The hostname or port address falls
+// back to the certificate of the master.
+func (f *Facade) RemoveCertificate(ctx datastore.Context, certificateID string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RemoveCertificate"))
+	certificateID = certificate.Normalize(certificateID)
+	alog := f.auditLogger.Message(ctx, "Removing Certificate").Action(audit.Remove).Type(certificate.GetType()).ID(certificateID)
+	if err := f.certificateStore.Delete(ctx, certificateID); err != nil {
+		return alog.Error(err)
+	}
+	alog.Succeeded()
+	return nil
+}
+
+// getCertificateWarnings returns the warnings about certificates that have
+// expired or are about to, by the pool of the services whose public
+// endpoints they are served for
+func (f *Facade) getCertificateWarnings(ctx datastore.Context) map[string][]string {
+	certs, err := f.certificateStore.GetCertificates(ctx)
+	if err != nil {
+		plog.WithError(err).Warn("Could not look up certificates")
+		return nil
+	}
+	now := time.Now()
+	expiring := []certificate.Certificate{}
+	for _, cert := range certs {
+		if cert.Warning(now) != "" {
+			expiring = append(expiring, cert)
+		}
+	}
+	if len(expiring) == 0 {
+		return nil
+	}
+
+	endpoints, err := f.GetAllPublicEndpoints(ctx)
+	if err != nil {
+		plog.WithError(err).Warn("Could not look up public endpoints")
+		return nil
+	}
+	warnings := make(map[string][]string)
+	seen := make(map[string]bool)
+	pools := make(map[string]string)
+	for _, ep := range endpoints {
+		cert := certificate.Find(expiring, ep.VHostName, ep.PortAddress)
+		if cert == nil {
+			continue
+		}
+		poolID, ok := pools[ep.ServiceID]
+		if !ok {
+			svc, err := f.serviceStore.GetServiceDetails(ctx, ep.ServiceID)
+			if err != nil {
+				plog.WithError(err).WithField("serviceid", ep.ServiceID).Warn("Could not look up service")
+				continue
+			}
+			poolID = svc.PoolID
+			pools[ep.ServiceID] = poolID
+		}
+		if key := poolID + "/" + cert.ID; !seen[key] {
+			seen[key] = true
+			warnings[poolID] = append(warnings[poolID], cert.Warning(now))
+		}
+	}
+	for _, w := range warnings {
+		sort.Strings(w)
+	}
+	return warnings
+}
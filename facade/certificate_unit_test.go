@@ -0,0 +1,95 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/domain/certificate"
+	certificatemocks "github.com/control-center/serviced/domain/certificate/mocks"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_AddCertificate_Invalid(c *C) {
+	cert := &certificate.Certificate{ID: "app", CertPEM: "not a certificate", KeyPEM: "not a key"}
+	err := ft.Facade.AddCertificate(ft.ctx, cert)
+	c.Assert(err, NotNil)
+	ft.certificateStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_RemoveCertificate(c *C) {
+	ft.certificateStore.On("Delete", ft.ctx, "app.example.com").Return(nil)
+	err := ft.Facade.RemoveCertificate(ft.ctx, " App.Example.com. ")
+	c.Assert(err, IsNil)
+	ft.certificateStore.AssertCalled(c, "Delete", ft.ctx, "app.example.com")
+}
+
+func (ft *FacadeUnitTest) setupCertificateWarnings() {
+	now := time.Now()
+	ft.certificateStore = &certificatemocks.Store{}
+	ft.Facade.SetCertificateStore(ft.certificateStore)
+	ft.certificateStore.On("GetCertificates", ft.ctx).Return([]certificate.Certificate{
+		{ID: "app", NotAfter: now.AddDate(0, 0, 10)},
+		{ID: ":9443", NotAfter: now.AddDate(1, 0, 0)},
+		{ID: ":8443", NotAfter: now.AddDate(0, 0, -1)},
+		{ID: "unused", NotAfter: now.AddDate(0, 0, -1)},
+	}, nil)
+	ft.serviceStore.On("GetAllPublicEndpoints", ft.ctx).Return([]service.PublicEndpoint{
+		{ServiceID: "svc1", VHostName: "app"},
+		{ServiceID: "svc1", PortAddress: ":8443"},
+		{ServiceID: "svc2", PortAddress: "0.0.0.0:9443"},
+		{ServiceID: "svc3", VHostName: "other"},
+	}, nil)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, "svc1").Return(&service.ServiceDetails{ID: "svc1", PoolID: "pool1"}, nil)
+}
+
+func (ft *FacadeUnitTest) Test_GetReadPools_CertificateWarnings(c *C) {
+	ft.setupCertificateWarnings()
+	ft.poolStore.On("GetResourcePools", ft.ctx).Return([]pool.ResourcePool{{ID: "pool1"}, {ID: "pool2"}}, nil)
+	ft.hostStore.On("FindHostsWithPoolID", ft.ctx, mock.AnythingOfType("string")).Return([]host.Host{}, nil)
+	ft.serviceStore.On("GetServicesByPool", ft.ctx, mock.AnythingOfType("string")).Return([]service.Service{}, nil)
+
+	pools, err := ft.Facade.GetReadPools(ft.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(pools, HasLen, 2)
+	c.Assert(pools[0].CertificateWarnings, HasLen, 2)
+	c.Check(pools[0].CertificateWarnings[0], Matches, "certificate :8443 expired on .*")
+	c.Check(pools[0].CertificateWarnings[1], Matches, "certificate app expires on .*")
+	c.Assert(pools[1].CertificateWarnings, HasLen, 0)
+
+	// endpoints that are only served by current certificates are not looked up
+	ft.serviceStore.AssertNotCalled(c, "GetServiceDetails", ft.ctx, "svc2")
+	ft.serviceStore.AssertNotCalled(c, "GetServiceDetails", ft.ctx, "svc3")
+}
+
+func (ft *FacadeUnitTest) Test_GetReadPools_CertificateError(c *C) {
+	ft.certificateStore = &certificatemocks.Store{}
+	ft.Facade.SetCertificateStore(ft.certificateStore)
+	ft.certificateStore.On("GetCertificates", ft.ctx).Return(nil, errors.New("unavailable"))
+	ft.poolStore.On("GetResourcePools", ft.ctx).Return([]pool.ResourcePool{{ID: "pool1"}}, nil)
+	ft.hostStore.On("FindHostsWithPoolID", ft.ctx, "pool1").Return([]host.Host{}, nil)
+	ft.serviceStore.On("GetServicesByPool", ft.ctx, "pool1").Return([]service.Service{}, nil)
+
+	pools, err := ft.Facade.GetReadPools(ft.ctx)
+	c.Assert(err, IsNil)
+	c.Assert(pools, HasLen, 1)
+	c.Assert(pools[0].CertificateWarnings, HasLen, 0)
+}
@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/Sirupsen/logrus"
@@ -31,6 +32,7 @@ import (
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/dfs/target"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/metrics"
@@ -45,8 +47,16 @@ const (
 	oldLocalRegistryContainerNameBase = "cc-temp-registry-v%d"
 	registryRootSubdir                = "docker-registry"
 	upgradedMarkerFile                = "cc-upgraded"
+
+	// BackupSnapshotTag marks the snapshot of a tenant's most recent backup,
+	// which is kept as the base of the next incremental backup
+	BackupSnapshotTag = "backup-base"
 )
 
+// ErrBackupTenantNotFound is returned when a backup is requested for a tenant
+// that does not exist
+var ErrBackupTenantNotFound = errors.New("facade: tenant to back up does not exist")
+
 type registryVersionInfo struct {
 	version int
 	rootDir string
@@ -66,13 +76,23 @@ var registryVersionInfos = map[int]registryVersionInfo{
 	},
 }
 
-// Backup takes a backup of all installed applications
-func (f *Facade) Backup(ctx datastore.Context, w io.Writer, excludes []string, snapshotSpacePercent int, backupFilename string) error {
+// Backup takes a backup of all installed applications, or of a single tenant
+// if a tenant id is provided.  If a parent backup is provided, the backup is
+// incremental and only contains the changes since the parent.
+func (f *Facade) Backup(ctx datastore.Context, w io.Writer, excludes []string, tenantID string, snapshotSpacePercent int, backupFilename, parentFilename string, parent *dfs.BackupInfo) (err error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.Backup"))
+	defer func() {
+		if err != nil {
+			f.notifyBackupFailed(backupFilename, err)
+		}
+	}()
 	// Do not DFSLock here, ControlPlaneDao does that
 	stime := time.Now()
 	message := fmt.Sprintf("started backup at %s", stime.UTC())
-	plog.WithField("excludes", excludes).Info("Started backup")
+	plog.WithFields(logrus.Fields{
+		"excludes": excludes,
+		"tenantid": tenantID,
+	}).Info("Started backup")
 	alog := f.auditLogger.Message(ctx, "Started Backup").
 		Action(audit.Backup).
 		WithFields(logrus.Fields{
@@ -93,7 +113,7 @@ func (f *Facade) Backup(ctx datastore.Context, w io.Writer, excludes []string, s
 		return alog.Error(err)
 	}
 	plog.WithField("elapsed", time.Since(stime)).Info("Loaded resource pools")
-	tenants, err := f.GetTenantIDs(ctx)
+	tenants, err := f.backupTenantIDs(ctx, tenantID)
 	if err != nil {
 		plog.WithError(err).Debug("Could not get tenants")
 		return alog.Error(err)
@@ -103,13 +123,20 @@ func (f *Facade) Backup(ctx datastore.Context, w io.Writer, excludes []string, s
 	for i, tenant := range tenants {
 		tenantLogger := plog.WithField("tenant", tenant)
 		tag := fmt.Sprintf("backup-%s-%s", tenant, stime)
-		snapshot, err := f.Snapshot(ctx, tenant, message, []string{tag}, snapshotSpacePercent)
+		var snapshot string
+		snapshot, err = f.Snapshot(ctx, tenant, message, []string{tag}, snapshotSpacePercent)
 		if err != nil {
 			tenantLogger.WithError(err).Debug("Could not snapshot tenant")
 			return alog.Error(err)
 		}
 
 		defer func(tenant, snapshot, tag string) {
+			if err == nil {
+				if err := f.retainBackupSnapshot(ctx, tenant, snapshot); err == nil {
+					return
+				}
+				tenantLogger.WithError(err).Warn("Could not keep snapshot as the base of incremental backups")
+			}
 			if err := f.DeleteSnapshot(ctx, snapshot); err != nil {
 				tenantLogger.WithError(err).Warn("Could not delete snapshot; untagging for consumption by TTL")
 				if _, err := f.RemoveSnapshotTag(ctx, tenant, tag); err != nil {
@@ -132,8 +159,12 @@ func (f *Facade) Backup(ctx datastore.Context, w io.Writer, excludes []string, s
 		Timestamp:        stime,
 		BackupVersion:    1,
 	}
+	if parent != nil {
+		data.Parent = filepath.Base(parentFilename)
+		data.BackupVersion = dfs.IncrementalBackupVersion
+	}
 	plog.WithField("data", data).Info("Calling dfs.Backup")
-	if err := f.dfs.Backup(data, w); err != nil {
+	if err := f.dfs.Backup(data, parent, w); err != nil {
 		plog.WithError(err).Debug("Could not backup")
 		return alog.Error(err)
 	}
@@ -146,6 +177,33 @@ func (f *Facade) Backup(ctx datastore.Context, w io.Writer, excludes []string, s
 	return nil
 }
 
+// backupTenantIDs returns the ids of the tenants to back up, which are all of
+// the tenants if no tenant id is provided
+func (f *Facade) backupTenantIDs(ctx datastore.Context, tenantID string) ([]string, error) {
+	tenants, err := f.GetTenantIDs(ctx)
+	if err != nil || tenantID == "" {
+		return tenants, err
+	}
+	for _, tenant := range tenants {
+		if tenant == tenantID {
+			return []string{tenantID}, nil
+		}
+	}
+	return nil, ErrBackupTenantNotFound
+}
+
+// retainBackupSnapshot keeps the snapshot of a backup as the base of the next
+// incremental backup of the tenant, in place of the snapshot of the tenant's
+// previous backup.
+func (f *Facade) retainBackupSnapshot(ctx datastore.Context, tenantID, snapshotID string) error {
+	if info, err := f.dfs.TagInfo(tenantID, BackupSnapshotTag); err == nil {
+		if err := f.DeleteSnapshot(ctx, info.Name); err != nil {
+			return err
+		}
+	}
+	return f.TagSnapshot(snapshotID, BackupSnapshotTag)
+}
+
 // EstimateBackup estimates storage requirements to take a backup of all installed applications
 func (f *Facade) EstimateBackup(ctx datastore.Context, request dao.BackupRequest, estimate *dao.BackupEstimate) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.EstimateBackup"))
@@ -158,9 +216,15 @@ func (f *Facade) EstimateBackup(ctx datastore.Context, request dao.BackupRequest
 	// Do not DFSLock here, ControlPlaneDao does that
 
 	estimate.BackupPath = request.Dirpath
-	// Get Filesystem free space
-	estimate.AvailableBytes = volume.FilesystemBytesAvailable(request.Dirpath)
-	estimate.AvailableString = humanize.Bytes(estimate.AvailableBytes)
+	// Get Filesystem free space.  The space on remote backup targets is not
+	// known, so backups to them are always allowed.
+	remote := target.IsRemote(request.Dirpath)
+	if remote {
+		estimate.AvailableString = "unknown"
+	} else {
+		estimate.AvailableBytes = volume.FilesystemBytesAvailable(request.Dirpath)
+		estimate.AvailableString = humanize.Bytes(estimate.AvailableBytes)
+	}
 
 	plog.WithFields(logrus.Fields{
 		"dirpath":  request.Dirpath,
@@ -176,7 +240,7 @@ func (f *Facade) EstimateBackup(ctx datastore.Context, request dao.BackupRequest
 
 	plog.WithField("elapsed", time.Since(stime)).Debug("Loaded templates and their images")
 
-	tenants, err := f.GetTenantIDs(ctx)
+	tenants, err := f.backupTenantIDs(ctx, request.TenantID)
 	if err != nil {
 		plog.WithError(err).Debug("Could not get tenants")
 		return err
@@ -219,7 +283,7 @@ func (f *Facade) EstimateBackup(ctx datastore.Context, request dao.BackupRequest
 	AdjustedBytesRequired := uint64(float64(TotalBytesRequired)/CompressionEst+0.5) + MinOverheadBytes
 	estimate.EstimatedBytes = AdjustedBytesRequired
 	estimate.EstimatedString = humanize.Bytes(AdjustedBytesRequired)
-	estimate.AllowBackup = estimate.EstimatedBytes < estimate.AvailableBytes
+	estimate.AllowBackup = remote || estimate.EstimatedBytes < estimate.AvailableBytes
 
 	plog.WithFields(logrus.Fields{
 		"duration":                   time.Since(stime),
@@ -243,6 +307,62 @@ func (f *Facade) BackupInfo(ctx datastore.Context, r io.Reader) (*dfs.BackupInfo
 	return info, nil
 }
 
+// BackupTarget returns the target where backups are stored by default, or
+// nil if it is not set
+func (f *Facade) BackupTarget() target.Target {
+	return f.backupTarget
+}
+
+// SplitBackupPath returns the target of a backup file and the name of the
+// file on the target.  Files on the default backup target are accessed
+// through it, so that they use its credentials.
+func (f *Facade) SplitBackupPath(filename string) (target.Target, string, error) {
+	t, name, err := target.Split(filename)
+	if err != nil {
+		return nil, "", err
+	}
+	if f.backupTarget != nil && f.backupTarget.Path("") == t.Path("") {
+		return f.backupTarget, name, nil
+	}
+	return t, name, nil
+}
+
+// DeleteBackup removes a backup file
+func (f *Facade) DeleteBackup(ctx datastore.Context, filename string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.DeleteBackup"))
+	t, name, err := f.SplitBackupPath(filename)
+	if err != nil {
+		return err
+	}
+	if err := t.Remove(name); err != nil {
+		plog.WithError(err).WithField("filename", filename).Debug("Could not delete backup")
+		return err
+	}
+	plog.WithField("filename", filename).Info("Deleted backup")
+	return nil
+}
+
+// VerifyBackup checks a backup file against the checksums it recorded
+func (f *Facade) VerifyBackup(ctx datastore.Context, filename string) (*dfs.BackupVerification, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.VerifyBackup"))
+	t, name, err := f.SplitBackupPath(filename)
+	if err != nil {
+		return nil, err
+	}
+	r, err := t.Open(name)
+	if err != nil {
+		plog.WithError(err).WithField("filename", filename).Debug("Could not open backup")
+		return nil, err
+	}
+	defer r.Close()
+	verification, err := f.dfs.VerifyBackup(r)
+	if err != nil {
+		plog.WithError(err).WithField("filename", filename).Warn("Backup failed verification")
+		return nil, err
+	}
+	return verification, nil
+}
+
 // Commit commits a container to the docker registry and takes a snapshot.
 func (f *Facade) Commit(ctx datastore.Context, ctrID, message string, tags []string, snapshotSpacePercent int) (string, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.Commit"))
@@ -577,6 +697,26 @@ func (f *Facade) markLocalDockerRegistryUpgraded(version int) error {
 // Restore restores application data from a backup.
 func (f *Facade) Restore(ctx datastore.Context, r io.Reader, backupInfo *dfs.BackupInfo, backupFilename string) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.Restore"))
+	return f.restore(ctx, backupInfo, backupFilename, func() error {
+		return f.dfs.Restore(r, backupInfo.BackupVersion)
+	})
+}
+
+// RestoreChain restores application data from a chain of backups, from the
+// full backup to the most recent incremental backup
+func (f *Facade) RestoreChain(ctx datastore.Context, chain []dfs.ChainedBackup, backupFilename string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RestoreChain"))
+	if len(chain) == 0 {
+		return dfs.ErrInvalidBackupChain
+	}
+	return f.restore(ctx, chain[len(chain)-1].Info, backupFilename, func() error {
+		return f.dfs.RestoreChain(chain)
+	})
+}
+
+// restore restores the application data with restoreData and then the
+// templates, pools and services of the backup
+func (f *Facade) restore(ctx datastore.Context, backupInfo *dfs.BackupInfo, backupFilename string, restoreData func() error) error {
 	// Do not DFSLock here, ControlPlaneDao does that
 	stime := time.Now()
 	plog.Info("Started restore from backup")
@@ -586,7 +726,7 @@ func (f *Facade) Restore(ctx datastore.Context, r io.Reader, backupInfo *dfs.Bac
 				"starttime": stime.UTC().Format("2006-01-02-150405"),
 			})
 	alog.Succeeded()
-	if err := f.dfs.Restore(r, backupInfo.BackupVersion); err != nil {
+	if err := restoreData(); err != nil {
 		plog.WithError(err).Debug("Could not restore from backup")
 		return alog.Error(err)
 	}
@@ -909,39 +1049,124 @@ func (f *Facade) DockerOverride(ctx datastore.Context, newImageName, oldImageNam
 
 // PredictStorageAvailability returns the predicted available storage after
 // a given period for the thin pool data device, the thin pool metadata device,
-// and each tenant filesystem.
-func (f *Facade) PredictStorageAvailability(ctx datastore.Context, lookahead time.Duration) (map[string]float64, error) {
+// and each tenant filesystem, using the model configured for each.
+func (f *Facade) PredictStorageAvailability(ctx datastore.Context, lookahead time.Duration) (map[string]statistics.Forecast, error) {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.PredictStorageAvailability"))
 	options := config.GetOptions()
 
+	models, err := ParseStorageForecastModels(options.StorageForecastModel)
+	if err != nil {
+		return nil, err
+	}
+	season := time.Duration(options.StorageForecastSeason) * time.Second
+
 	// First, get a list of all tenant IDs
 	tenantIDs, err := f.ListTenants(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	// Next, query metrics for our window
+	// Next, query metrics for our window.  Seasonal models also need two
+	// full seasons of history.
 	window := time.Duration(options.StorageMetricMonitorWindow) * time.Second
-	perfdata, err := f.metricsClient.GetAvailableStorage(window, "mimmax", tenantIDs...)
+	query := window
+	for _, model := range models {
+		if model == statistics.HoltWintersModel && window+2*season > query {
+			query = window + 2*season
+		}
+	}
+	perfdata, err := f.metricsClient.GetAvailableStorage(query, "mimmax", tenantIDs...)
 	if err != nil {
 		return nil, err
 	}
-	result := make(map[string]float64)
-	predict := func(series metrics.MetricSeries) (float64, error) {
-		return statistics.LeastSquaresPredictor.Predict(lookahead, series.X(), series.Y())
+	since := float64(time.Now().UTC().Add(-window).Unix())
+	result := make(map[string]statistics.Forecast)
+	predict := func(name string, series metrics.MetricSeries) {
+		model, ok := models[name]
+		if !ok {
+			model = models[""]
+		}
+		predictor := storagePredictor(model, season)
+		xs, ys := series.X(), series.Y()
+		if _, ok := predictor.(statistics.Seasonal); !ok {
+			xs, ys = seriesSince(xs, ys, since)
+		}
+		forecast, err := predictor.Forecast(lookahead, xs, ys)
+		if err == statistics.ErrInsufficientData && model == statistics.HoltWintersModel {
+			// Until there are two seasons of history, fall back to a
+			// straight line through the monitor window
+			xs, ys = seriesSince(series.X(), series.Y(), since)
+			forecast, err = statistics.LeastSquaresPredictor.Forecast(lookahead, xs, ys)
+		}
+		if err != nil {
+			plog.WithError(err).WithFields(logrus.Fields{
+				"name":  name,
+				"model": model,
+			}).Debug("Unable to predict storage availability")
+			return
+		}
+		result[name] = forecast
 	}
-	if avail, err := predict(perfdata.PoolDataAvailable); err == nil {
-		result[metrics.PoolDataAvailableName] = avail
+	predict(metrics.PoolDataAvailableName, perfdata.PoolDataAvailable)
+	predict(metrics.PoolMetadataAvailableName, perfdata.PoolMetadataAvailable)
+	for tenant, series := range perfdata.Tenants {
+		predict(tenant, series)
 	}
-	if avail, err := predict(perfdata.PoolMetadataAvailable); err == nil {
-		result[metrics.PoolMetadataAvailableName] = avail
+	return result, nil
+}
+
+// ParseStorageForecastModels parses the comma-separated list of models used
+// to predict storage availability.  Each entry is either a model, which
+// applies to everything not otherwise named, or NAME=MODEL, where NAME is
+// thinpool-data, thinpool-metadata, or a tenant ID, e.g.
+// "theilsen,thinpool-data=holtwinters".  The map returned holds the default
+// model under the empty name.
+func ParseStorageForecastModels(spec string) (map[string]string, error) {
+	models := map[string]string{"": statistics.LeastSquaresModel}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, model := "", entry
+		if i := strings.Index(entry, "="); i >= 0 {
+			name, model = strings.TrimSpace(entry[:i]), strings.TrimSpace(entry[i+1:])
+			if name == "" {
+				return nil, fmt.Errorf("storage forecast model %q does not name a volume", entry)
+			}
+		}
+		switch model {
+		case statistics.LeastSquaresModel, statistics.TheilSenModel, statistics.HoltWintersModel:
+		default:
+			return nil, fmt.Errorf("unknown storage forecast model %q", model)
+		}
+		models[name] = model
 	}
-	for tenant, series := range perfdata.Tenants {
-		if avail, err := predict(series); err == nil {
-			result[tenant] = avail
+	return models, nil
+}
+
+// storagePredictor returns the predictor for a storage forecast model
+func storagePredictor(model string, season time.Duration) statistics.Predictor {
+	switch model {
+	case statistics.TheilSenModel:
+		return statistics.TheilSenPredictor
+	case statistics.HoltWintersModel:
+		return statistics.NewHoltWintersPredictor(season)
+	default:
+		return statistics.LeastSquaresPredictor
+	}
+}
+
+// seriesSince returns the points of a series at or after the given timestamp
+func seriesSince(xs, ys []float64, since float64) ([]float64, []float64) {
+	var rx, ry []float64
+	for i, x := range xs {
+		if x >= since {
+			rx = append(rx, x)
+			ry = append(ry, ys[i])
 		}
 	}
-	return result, nil
+	return rx, ry
 }
 
 // Interface to allow filtering DFS clients
@@ -0,0 +1,138 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/control-center/serviced/commons/statistics"
+	"github.com/control-center/serviced/config"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/facade"
+	"github.com/control-center/serviced/metrics"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_ParseStorageForecastModels(c *C) {
+	models, err := facade.ParseStorageForecastModels("")
+	c.Assert(err, IsNil)
+	c.Assert(models, DeepEquals, map[string]string{"": statistics.LeastSquaresModel})
+
+	models, err = facade.ParseStorageForecastModels(" theilsen, thinpool-data = holtwinters ,tenant1=leastsquares")
+	c.Assert(err, IsNil)
+	c.Assert(models, DeepEquals, map[string]string{
+		"":              statistics.TheilSenModel,
+		"thinpool-data": statistics.HoltWintersModel,
+		"tenant1":       statistics.LeastSquaresModel,
+	})
+
+	_, err = facade.ParseStorageForecastModels("thinpool-data=arima")
+	c.Assert(err, ErrorMatches, `unknown storage forecast model "arima"`)
+
+	_, err = facade.ParseStorageForecastModels("=theilsen")
+	c.Assert(err, ErrorMatches, `storage forecast model "=theilsen" does not name a volume`)
+}
+
+// storageSeries returns a series of the values given, one a minute, ending
+// now
+func storageSeries(values ...float64) metrics.MetricSeries {
+	now := time.Now().UTC().Unix()
+	datapoints := make([]metrics.Datapoint, len(values))
+	for i, v := range values {
+		datapoints[i] = metrics.Datapoint{
+			Timestamp: now - int64(60*(len(values)-i)),
+			Value:     metrics.Float{Value: v},
+		}
+	}
+	return metrics.DatapointsToSeries(datapoints)
+}
+
+func (ft *FacadeUnitTest) Test_PredictStorageAvailability(c *C) {
+	saved := config.GetOptions()
+	defer config.LoadOptions(saved)
+	opts := saved
+	opts.StorageMetricMonitorWindow = 600
+	opts.StorageForecastModel = "theilsen,thinpool-metadata=holtwinters,tenant2=leastsquares"
+	opts.StorageForecastSeason = 3600
+	config.LoadOptions(opts)
+
+	ft.serviceStore.On("GetServiceDetailsByParentID", ft.ctx, "", time.Duration(0)).
+		Return([]service.ServiceDetails{{ID: "tenant1"}, {ID: "tenant2"}}, nil)
+
+	// A backup frees a lot of space in the middle of the window
+	falling := storageSeries(1000, 990, 980, 970, 5000, 950, 940, 930, 920)
+	ft.metricsClient.On("GetAvailableStorage", 2*time.Hour+10*time.Minute, "mimmax", []string{"tenant1", "tenant2"}).
+		Return(&metrics.StorageMetrics{
+			PoolDataAvailable:     falling,
+			PoolMetadataAvailable: falling,
+			Tenants: map[string]metrics.MetricSeries{
+				"tenant1": falling,
+				"tenant2": falling,
+			},
+		}, nil)
+
+	forecasts, err := ft.Facade.PredictStorageAvailability(ft.ctx, 10*time.Minute)
+	c.Assert(err, IsNil)
+	c.Assert(forecasts, HasLen, 4)
+
+	// Theil-Sen ignores the backup and keeps falling ten a minute
+	data := forecasts[metrics.PoolDataAvailableName]
+	c.Assert(data.Model, Equals, statistics.TheilSenModel)
+	c.Assert(data.Value > 805 && data.Value < 815, Equals, true, Commentf("got %f", data.Value))
+	c.Assert(forecasts["tenant1"].Model, Equals, statistics.TheilSenModel)
+
+	// Without two hours of history, Holt-Winters falls back to least squares
+	c.Assert(forecasts[metrics.PoolMetadataAvailableName].Model, Equals, statistics.LeastSquaresModel)
+
+	tenant2 := forecasts["tenant2"]
+	c.Assert(tenant2.Model, Equals, statistics.LeastSquaresModel)
+	c.Assert(tenant2.Lower < tenant2.Value && tenant2.Value < tenant2.Upper, Equals, true)
+	ft.metricsClient.AssertExpectations(c)
+}
+
+func (ft *FacadeUnitTest) Test_PredictStorageAvailability_InvalidModel(c *C) {
+	saved := config.GetOptions()
+	defer config.LoadOptions(saved)
+	opts := saved
+	opts.StorageForecastModel = "arima"
+	config.LoadOptions(opts)
+
+	_, err := ft.Facade.PredictStorageAvailability(ft.ctx, 10*time.Minute)
+	c.Assert(err, ErrorMatches, `unknown storage forecast model "arima"`)
+	ft.metricsClient.AssertNotCalled(c, "GetAvailableStorage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_EstimateBackup_Tenant(c *C) {
+	opts := config.GetOptions()
+	ft.templateStore.On("GetServiceTemplates", ft.ctx).Return([]*servicetemplate.ServiceTemplate{}, nil)
+	ft.serviceStore.On("GetServiceDetailsByParentID", ft.ctx, "", time.Duration(0)).
+		Return([]service.ServiceDetails{{ID: "tenant1"}, {ID: "tenant2"}}, nil)
+	ft.dfs.On("DfPath", filepath.Join(opts.VolumesPath, "tenant2"), []string(nil)).Return(uint64(1000), nil)
+	ft.dfs.On("EstimateImagePullSize", []string(nil)).Return(uint64(0), nil)
+
+	estimate := dao.BackupEstimate{}
+	err := ft.Facade.EstimateBackup(ft.ctx, dao.BackupRequest{Dirpath: "s3://bucket/backups", TenantID: "tenant2"}, &estimate)
+	c.Assert(err, IsNil)
+	c.Assert(estimate.AllowBackup, Equals, true)
+	ft.dfs.AssertNotCalled(c, "DfPath", filepath.Join(opts.VolumesPath, "tenant1"), mock.Anything)
+
+	err = ft.Facade.EstimateBackup(ft.ctx, dao.BackupRequest{Dirpath: "s3://bucket/backups", TenantID: "tenant3"}, &estimate)
+	c.Assert(err, Equals, facade.ErrBackupTenantNotFound)
+}
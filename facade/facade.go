@@ -19,17 +19,24 @@ import (
 	"github.com/control-center/serviced/audit"
 	"github.com/control-center/serviced/auth"
 	"github.com/control-center/serviced/dfs"
+	"github.com/control-center/serviced/dfs/target"
+	"github.com/control-center/serviced/domain/alert"
+	"github.com/control-center/serviced/domain/auditlog"
+	"github.com/control-center/serviced/domain/certificate"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/hostkey"
 	"github.com/control-center/serviced/domain/pool"
 	"github.com/control-center/serviced/domain/registry"
+	"github.com/control-center/serviced/domain/schedule"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/serviceconfigfile"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/session"
 	"github.com/control-center/serviced/domain/user"
 	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/metrics"
+	"github.com/control-center/serviced/notify"
 	"github.com/control-center/serviced/scheduler/servicestatemanager"
 	"github.com/control-center/serviced/domain/logfilter"
 )
@@ -37,6 +44,7 @@ import (
 type MetricsClient interface {
 	GetInstanceMemoryStats(time.Time, ...metrics.ServiceInstance) ([]metrics.MemoryUsageStats, error)
 	GetAvailableStorage(time.Duration, string, ...string) (*metrics.StorageMetrics, error)
+	GetMetricSeries(time.Duration, string, string, map[string][]string) (*metrics.MetricSeries, error)
 }
 
 // instantiate the package logger
@@ -48,47 +56,61 @@ var _ FacadeInterface = &Facade{}
 // New creates an initialized Facade instance
 func New() *Facade {
 	return &Facade{
-		auditLogger:    audit.NewLogger(),
-		hostStore:      host.NewStore(),
-		hostkeyStore:   hostkey.NewStore(),
-		registryStore:  registry.NewStore(),
-		poolStore:      pool.NewStore(),
-		serviceStore:   service.NewStore(),
-		configStore:    serviceconfigfile.NewStore(),
-		templateStore:  servicetemplate.NewStore(),
-		logFilterStore: logfilter.NewStore(),
-		userStore:      user.NewStore(),
-		serviceCache:   NewServiceCache(),
-		poolCache:      NewPoolCache(),
-		hostRegistry:   auth.NewHostExpirationRegistry(),
-		deployments:    NewPendingDeploymentMgr(),
-		zzk:            getZZK(),
+		auditLogger:      audit.NewLogger(),
+		hostStore:        host.NewStore(),
+		hostkeyStore:     hostkey.NewStore(),
+		registryStore:    registry.NewStore(),
+		poolStore:        pool.NewStore(),
+		serviceStore:     service.NewStore(),
+		configStore:      serviceconfigfile.NewStore(),
+		templateStore:    servicetemplate.NewStore(),
+		logFilterStore:   logfilter.NewStore(),
+		userStore:        user.NewStore(),
+		alertStore:       alert.NewStore(),
+		scheduleStore:    schedule.NewStore(),
+		certificateStore: certificate.NewStore(),
+		auditEventStore:  auditlog.NewStore(),
+		sessionStore:     session.NewStore(),
+		notifier:         notify.Discard,
+		serviceCache:     NewServiceCache(),
+		poolCache:        NewPoolCache(),
+		hostRegistry:     auth.NewHostExpirationRegistry(),
+		deployments:      NewPendingDeploymentMgr(),
+		zzk:              getZZK(),
 	}
 }
 
 // Facade is an entrypoint to available controlplane methods
 type Facade struct {
-	hostStore      host.Store
-	hostkeyStore   hostkey.Store
-	registryStore  registry.ImageRegistryStore
-	poolStore      pool.Store
-	templateStore  servicetemplate.Store
-	logFilterStore logfilter.Store
-	serviceStore   service.Store
-	configStore    serviceconfigfile.Store
-	userStore      user.Store
+	hostStore        host.Store
+	hostkeyStore     hostkey.Store
+	registryStore    registry.ImageRegistryStore
+	poolStore        pool.Store
+	templateStore    servicetemplate.Store
+	logFilterStore   logfilter.Store
+	serviceStore     service.Store
+	configStore      serviceconfigfile.Store
+	userStore        user.Store
+	alertStore       alert.Store
+	scheduleStore    schedule.Store
+	certificateStore certificate.Store
+	auditEventStore  auditlog.Store
+	sessionStore     session.Store
 
 	auditLogger   audit.Logger
 	zzk           ZZK
 	dfs           dfs.DFS
 	hcache        *health.HealthStatusCache
 	metricsClient MetricsClient
+	notifier      notify.Notifier
 	serviceCache  *serviceCache
 	poolCache     *poolCache
 	hostRegistry  auth.HostExpirationRegistryInterface
 	deployments   *PendingDeploymentMgr
 	ssm           servicestatemanager.ServiceStateManager
 	isvcsPath     string
+	sessionsPath  string
+	backupTarget  target.Target
 
 	rollingRestartTimeout time.Duration
 }
@@ -124,6 +146,16 @@ func (f *Facade) SetConfigStore(store serviceconfigfile.Store) { f.configStore =
 
 func (f *Facade) SetUserStore(store user.Store) { f.userStore = store }
 
+func (f *Facade) SetAlertStore(store alert.Store) { f.alertStore = store }
+
+func (f *Facade) SetScheduleStore(store schedule.Store) { f.scheduleStore = store }
+
+func (f *Facade) SetCertificateStore(store certificate.Store) { f.certificateStore = store }
+
+func (f *Facade) SetAuditEventStore(store auditlog.Store) { f.auditEventStore = store }
+
+func (f *Facade) SetSessionStore(store session.Store) { f.sessionStore = store }
+
 func (f *Facade) SetTemplateStore(store servicetemplate.Store) { f.templateStore = store }
 
 func (f *Facade) SetLogFilterStore(store logfilter.Store) { f.logFilterStore = store }
@@ -132,8 +164,14 @@ func (f *Facade) SetHealthCache(hcache *health.HealthStatusCache) { f.hcache = h
 
 func (f *Facade) SetMetricsClient(client MetricsClient) { f.metricsClient = client }
 
+func (f *Facade) SetNotifier(notifier notify.Notifier) { f.notifier = notifier }
+
 func (f *Facade) SetIsvcsPath(path string) { f.isvcsPath = path }
 
+func (f *Facade) SetSessionsPath(path string) { f.sessionsPath = path }
+
+func (f *Facade) SetBackupTarget(t target.Target) { f.backupTarget = t }
+
 func (f *Facade) SetHostExpirationRegistry(hostRegistry auth.HostExpirationRegistryInterface) {
 	f.hostRegistry = hostRegistry
 }
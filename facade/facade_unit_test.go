@@ -21,16 +21,23 @@ import (
 	auditmocks "github.com/control-center/serviced/audit/mocks"
 	"github.com/control-center/serviced/auth"
 	authmocks "github.com/control-center/serviced/auth/mocks"
+	"github.com/control-center/serviced/domain/certificate"
+	alertmocks "github.com/control-center/serviced/domain/alert/mocks"
+	auditlogmocks "github.com/control-center/serviced/domain/auditlog/mocks"
+	certificatemocks "github.com/control-center/serviced/domain/certificate/mocks"
 	datastoremocks "github.com/control-center/serviced/datastore/mocks"
 	dfsmocks "github.com/control-center/serviced/dfs/mocks"
 	hostmocks "github.com/control-center/serviced/domain/host/mocks"
 	keymocks "github.com/control-center/serviced/domain/hostkey/mocks"
 	poolmocks "github.com/control-center/serviced/domain/pool/mocks"
 	registrymocks "github.com/control-center/serviced/domain/registry/mocks"
+	schedulemocks "github.com/control-center/serviced/domain/schedule/mocks"
 	servicemocks "github.com/control-center/serviced/domain/service/mocks"
 	configmocks "github.com/control-center/serviced/domain/serviceconfigfile/mocks"
 	templatemocks "github.com/control-center/serviced/domain/servicetemplate/mocks"
+	sessionmocks "github.com/control-center/serviced/domain/session/mocks"
 	logfiltermocks "github.com/control-center/serviced/domain/logfilter/mocks"
+	usermocks "github.com/control-center/serviced/domain/user/mocks"
 	"github.com/control-center/serviced/facade"
 	zzkmocks "github.com/control-center/serviced/facade/mocks"
 	"github.com/control-center/serviced/metrics"
@@ -53,6 +60,12 @@ type FacadeUnitTest struct {
 	configStore      *configmocks.Store
 	templateStore    *templatemocks.Store
 	logFilterStore   *logfiltermocks.Store
+	userStore        *usermocks.Store
+	alertStore       *alertmocks.Store
+	scheduleStore    *schedulemocks.Store
+	certificateStore *certificatemocks.Store
+	auditEventStore  *auditlogmocks.Store
+	sessionStore     *sessionmocks.Store
 	metricsClient    *zzkmocks.MetricsClient
 	hostauthregistry *authmocks.HostExpirationRegistryInterface
 }
@@ -112,6 +125,25 @@ func (ft *FacadeUnitTest) SetUpTest(c *C) {
 	ft.logFilterStore = &logfiltermocks.Store{}
 	ft.Facade.SetLogFilterStore(ft.logFilterStore)
 
+	ft.userStore = &usermocks.Store{}
+	ft.Facade.SetUserStore(ft.userStore)
+
+	ft.alertStore = &alertmocks.Store{}
+	ft.Facade.SetAlertStore(ft.alertStore)
+
+	ft.scheduleStore = &schedulemocks.Store{}
+	ft.Facade.SetScheduleStore(ft.scheduleStore)
+
+	ft.certificateStore = &certificatemocks.Store{}
+	ft.Facade.SetCertificateStore(ft.certificateStore)
+	ft.certificateStore.On("GetCertificates", ft.ctx).Return([]certificate.Certificate{}, nil)
+
+	ft.auditEventStore = &auditlogmocks.Store{}
+	ft.Facade.SetAuditEventStore(ft.auditEventStore)
+
+	ft.sessionStore = &sessionmocks.Store{}
+	ft.Facade.SetSessionStore(ft.sessionStore)
+
 	ft.zzk = &zzkmocks.ZZK{}
 	ft.Facade.SetZZK(ft.zzk)
 
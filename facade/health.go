@@ -23,9 +23,13 @@ import (
 	"github.com/zenoss/glog"
 )
 
-// ReportHealthStatus writes the status of a health check to the cache.
+// ReportHealthStatus writes the status of a health check to the cache, and
+// sends a notification if the check failed.
 func (f *Facade) ReportHealthStatus(key health.HealthStatusKey, value health.HealthStatus, expires time.Duration) {
 	f.hcache.Set(key, value, expires)
+	if value.Status == health.Failed || value.Status == health.Timeout {
+		f.notifyHealthCheckFailed(key, value)
+	}
 }
 
 // ReportInstanceDead removes all health checks of a particular instance from
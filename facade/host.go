@@ -519,6 +519,7 @@ func (f *Facade) GetHostStatuses(ctx datastore.Context, hostIDs []string, since
 		return []host.HostStatus{}, nil
 	}
 
+	warnings := f.getCertificateWarnings(ctx)
 	statuses := []host.HostStatus{}
 	for _, id := range hostIDs {
 		h, err := f.GetHost(ctx, id)
@@ -526,7 +527,7 @@ func (f *Facade) GetHostStatuses(ctx datastore.Context, hostIDs []string, since
 			continue
 		}
 
-		status := host.HostStatus{HostID: id, HostName: h.Name, MemoryUsage: service.Usage{}}
+		status := host.HostStatus{HostID: id, HostName: h.Name, MemoryUsage: service.Usage{}, CertificateWarnings: warnings[h.PoolID]}
 		active, err := f.zzk.IsHostActive(h.PoolID, h.ID)
 		if err != nil {
 			continue
@@ -439,9 +439,12 @@ func (f *Facade) GetHostStrategyInstances(ctx datastore.Context, hosts []host.Ho
 				}
 				inst = service.StrategyInstance{
 					ServiceID:     s.ID,
+					Name:          s.Name,
+					DeploymentID:  s.DeploymentID,
 					CPUCommitment: int(s.CPUCommitment),
 					RAMCommitment: s.RAMCommitment.Value,
 					HostPolicy:    s.HostPolicy,
+					Placement:     s.Placement,
 				}
 				svcMap[state.ServiceID] = inst
 			}
@@ -424,7 +424,11 @@ func (ft *FacadeUnitTest) TestGetHostStrategyInstances(c *C) {
 		RAMCommitment: utils.EngNotation{
 			Value: uint64(1000),
 		},
-		HostPolicy: servicedefinition.Pack,
+		HostPolicy:   servicedefinition.Pack,
+		DeploymentID: "deployment1",
+		Placement: servicedefinition.PlacementConstraints{
+			HostLabels: map[string]string{"ssd": "true"},
+		},
 	}
 	ft.serviceStore.On("Get", ft.ctx, "testservice").Return(svc, nil)
 
@@ -432,16 +436,22 @@ func (ft *FacadeUnitTest) TestGetHostStrategyInstances(c *C) {
 		hst1.ID: {
 			HostID:        hst1.ID,
 			ServiceID:     svc.ID,
+			Name:          svc.Name,
+			DeploymentID:  svc.DeploymentID,
 			CPUCommitment: int(svc.CPUCommitment),
 			RAMCommitment: svc.RAMCommitment.Value,
 			HostPolicy:    svc.HostPolicy,
+			Placement:     svc.Placement,
 		},
 		hst2.ID: {
 			HostID:        hst2.ID,
 			ServiceID:     svc.ID,
+			Name:          svc.Name,
+			DeploymentID:  svc.DeploymentID,
 			CPUCommitment: int(svc.CPUCommitment),
 			RAMCommitment: svc.RAMCommitment.Value,
 			HostPolicy:    svc.HostPolicy,
+			Placement:     svc.Placement,
 		},
 	}
 	actual, err := ft.Facade.GetHostStrategyInstances(ft.ctx, []host.Host{hst1, hst2})
@@ -450,6 +460,6 @@ func (ft *FacadeUnitTest) TestGetHostStrategyInstances(c *C) {
 	for _, result := range actual {
 		expected, ok := expectedMap[(*result).HostID]
 		c.Assert(ok, Equals, true)
-		c.Assert(*result, Equals, expected)
+		c.Assert(*result, DeepEquals, expected)
 	}
 }
@@ -16,18 +16,25 @@ package facade
 import (
 	"time"
 
+	"github.com/control-center/serviced/commons/statistics"
 	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/health"
 
 	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/alert"
+	"github.com/control-center/serviced/domain/auditlog"
+	"github.com/control-center/serviced/domain/certificate"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/schedule"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/session"
 	"github.com/control-center/serviced/domain/user"
+	"github.com/control-center/serviced/scheduler/simulation"
 	"github.com/control-center/serviced/utils"
 )
 
@@ -78,6 +85,10 @@ type FacadeInterface interface {
 
 	DeployTemplateStatus(deploymentID string, lastStatus string, timeout time.Duration) (status string, err error)
 
+	TemplateDiff(ctx datastore.Context, tenantID, templateID string) (*servicetemplate.TemplateDiff, error)
+
+	UpgradeTemplate(ctx datastore.Context, tenantID, templateID string, snapshotSpacePercent int) (*servicetemplate.TemplateDiff, error)
+
 	AddHost(ctx datastore.Context, entity *host.Host) ([]byte, error)
 
 	AddHostPrivate(ctx datastore.Context, entity *host.Host) ([]byte, error)
@@ -120,6 +131,10 @@ type FacadeInterface interface {
 
 	UpdateResourcePool(ctx datastore.Context, entity *pool.ResourcePool) error
 
+	RebalancePool(ctx datastore.Context, poolID string, dryRun bool) (*simulation.Plan, error)
+
+	SimulatePool(ctx datastore.Context, poolID string, changes simulation.Changes, template *servicetemplate.ServiceTemplate) (*simulation.Plan, error)
+
 	GetHealthChecksForService(ctx datastore.Context, id string) (map[string]health.HealthCheck, error)
 
 	AddPublicEndpointPort(ctx datastore.Context, serviceid, endpointName, portAddr string, usetls bool, protocol string, isEnabled bool, restart bool) (*servicedefinition.Port, error)
@@ -134,6 +149,10 @@ type FacadeInterface interface {
 
 	EnablePublicEndpointVHost(ctx datastore.Context, serviceid, endpointName, vhost string, isEnabled bool) error
 
+	SetPublicEndpointPortAccess(ctx datastore.Context, serviceid, endpointName, portAddr string, access *servicedefinition.AccessPolicy) error
+
+	SetPublicEndpointVHostAccess(ctx datastore.Context, serviceid, endpointName, vhost string, access *servicedefinition.AccessPolicy) error
+
 	GetHostInstances(ctx datastore.Context, since time.Time, hostid string) ([]service.Instance, error)
 
 	ListTenants(datastore.Context) ([]string, error)
@@ -178,6 +197,58 @@ type FacadeInterface interface {
 
 	ValidateCredentials(ctx datastore.Context, u user.User) (bool, error)
 
+	GetUsers(ctx datastore.Context) ([]user.User, error)
+
+	SetUserRoles(ctx datastore.Context, userName string, roles user.RoleBindings) error
+
+	GetUserRoles(ctx datastore.Context, userName string) (user.RoleBindings, error)
+
+	GetAccessScope(ctx datastore.Context, req user.AccessRequest) (user.Scope, error)
+
+	CheckAccess(ctx datastore.Context, req user.AccessRequest) error
+
+	GetAlerts(ctx datastore.Context, all bool) ([]alert.Alert, error)
+
+	GetAlert(ctx datastore.Context, alertID string) (*alert.Alert, error)
+
+	AcknowledgeAlert(ctx datastore.Context, alertID, userName string) error
+
+	EvaluateThresholds(ctx datastore.Context) error
+
+	GetSchedules(ctx datastore.Context) ([]schedule.Schedule, error)
+
+	GetSchedule(ctx datastore.Context, scheduleID string) (*schedule.Schedule, error)
+
+	AddSchedule(ctx datastore.Context, s *schedule.Schedule) error
+
+	UpdateSchedule(ctx datastore.Context, s *schedule.Schedule) error
+
+	RemoveSchedule(ctx datastore.Context, scheduleID string) error
+
+	SetScheduleStatus(ctx datastore.Context, scheduleID string, status schedule.Status) error
+
+	GetCertificates(ctx datastore.Context) ([]certificate.Certificate, error)
+
+	GetCertificate(ctx datastore.Context, certificateID string) (*certificate.Certificate, error)
+
+	AddCertificate(ctx datastore.Context, cert *certificate.Certificate) error
+
+	RemoveCertificate(ctx datastore.Context, certificateID string) error
+
+	GetAuditEvents(ctx datastore.Context, filter auditlog.Filter) ([]auditlog.Event, error)
+
+	PruneAuditEvents(ctx datastore.Context, retention time.Duration) (int, error)
+
+	AddSession(ctx datastore.Context, sess *session.Session, recording []byte) error
+
+	GetSessions(ctx datastore.Context, filter session.Filter) ([]session.Session, error)
+
+	GetSession(ctx datastore.Context, sessionID string) (*session.Session, error)
+
+	GetSessionRecording(ctx datastore.Context, sessionID string) ([]byte, error)
+
+	SessionRecordingRequired(ctx datastore.Context, serviceID string) (bool, error)
+
 	GetServicesHealth(ctx datastore.Context) (map[string]map[int]map[string]health.HealthStatus, error)
 
 	ReportHealthStatus(key health.HealthStatusKey, value health.HealthStatus, expires time.Duration)
@@ -188,6 +259,8 @@ type FacadeInterface interface {
 
 	GetServiceConfig(ctx datastore.Context, fileID string) (*servicedefinition.ConfigFile, error)
 
+	GetServiceConfigServiceID(ctx datastore.Context, fileID string) (string, error)
+
 	AddServiceConfig(ctx datastore.Context, serviceID string, conf servicedefinition.ConfigFile) error
 
 	UpdateServiceConfig(ctx datastore.Context, fileID string, conf servicedefinition.ConfigFile) error
@@ -206,7 +279,7 @@ type FacadeInterface interface {
 
 	ClearEmergencyStopFlag(ctx datastore.Context, serviceID string) (int, error)
 
-	PredictStorageAvailability(ctx datastore.Context, lookahead time.Duration) (map[string]float64, error)
+	PredictStorageAvailability(ctx datastore.Context, lookahead time.Duration) (map[string]statistics.Forecast, error)
 
 	QueryServiceDetails(ctx datastore.Context, query service.Query) ([]service.ServiceDetails, error)
 
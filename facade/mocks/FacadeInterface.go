@@ -1,6 +1,9 @@
 package mocks
 
 import addressassignment "github.com/control-center/serviced/domain/addressassignment"
+import alert "github.com/control-center/serviced/domain/alert"
+import auditlog "github.com/control-center/serviced/domain/auditlog"
+import certificate "github.com/control-center/serviced/domain/certificate"
 import dao "github.com/control-center/serviced/dao"
 import datastore "github.com/control-center/serviced/datastore"
 import domain "github.com/control-center/serviced/domain"
@@ -9,9 +12,13 @@ import health "github.com/control-center/serviced/health"
 import host "github.com/control-center/serviced/domain/host"
 import mock "github.com/stretchr/testify/mock"
 import pool "github.com/control-center/serviced/domain/pool"
+import schedule "github.com/control-center/serviced/domain/schedule"
 import service "github.com/control-center/serviced/domain/service"
 import servicedefinition "github.com/control-center/serviced/domain/servicedefinition"
 import servicetemplate "github.com/control-center/serviced/domain/servicetemplate"
+import statistics "github.com/control-center/serviced/commons/statistics"
+import session "github.com/control-center/serviced/domain/session"
+import simulation "github.com/control-center/serviced/scheduler/simulation"
 import time "time"
 import user "github.com/control-center/serviced/domain/user"
 import "github.com/control-center/serviced/utils"
@@ -21,6 +28,20 @@ type FacadeInterface struct {
 	mock.Mock
 }
 
+// AcknowledgeAlert provides a mock function with given fields: ctx, alertID, userName
+func (_m *FacadeInterface) AcknowledgeAlert(ctx datastore.Context, alertID string, userName string) error {
+	ret := _m.Called(ctx, alertID, userName)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string) error); ok {
+		r0 = rf(ctx, alertID, userName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddHost provides a mock function with given fields: ctx, entity
 func (_m *FacadeInterface) AddHost(ctx datastore.Context, entity *host.Host) ([]byte, error) {
 	ret := _m.Called(ctx, entity)
@@ -204,6 +225,517 @@ func (_m *FacadeInterface) AssignIPs(ctx datastore.Context, assignmentRequest ad
 	return r0
 }
 
+// CheckAccess provides a mock function with given fields: ctx, req
+func (_m *FacadeInterface) CheckAccess(ctx datastore.Context, req user.AccessRequest) error {
+	ret := _m.Called(ctx, req)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, user.AccessRequest) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EvaluateThresholds provides a mock function with given fields: ctx
+func (_m *FacadeInterface) EvaluateThresholds(ctx datastore.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAccessScope provides a mock function with given fields: ctx, req
+func (_m *FacadeInterface) GetAccessScope(ctx datastore.Context, req user.AccessRequest) (user.Scope, error) {
+	ret := _m.Called(ctx, req)
+
+	var r0 user.Scope
+	if rf, ok := ret.Get(0).(func(datastore.Context, user.AccessRequest) user.Scope); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(user.Scope)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, user.AccessRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAlert provides a mock function with given fields: ctx, alertID
+func (_m *FacadeInterface) GetAlert(ctx datastore.Context, alertID string) (*alert.Alert, error) {
+	ret := _m.Called(ctx, alertID)
+
+	var r0 *alert.Alert
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *alert.Alert); ok {
+		r0 = rf(ctx, alertID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*alert.Alert)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, alertID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAlerts provides a mock function with given fields: ctx, all
+func (_m *FacadeInterface) GetAlerts(ctx datastore.Context, all bool) ([]alert.Alert, error) {
+	ret := _m.Called(ctx, all)
+
+	var r0 []alert.Alert
+	if rf, ok := ret.Get(0).(func(datastore.Context, bool) []alert.Alert); ok {
+		r0 = rf(ctx, all)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]alert.Alert)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, bool) error); ok {
+		r1 = rf(ctx, all)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddSchedule provides a mock function with given fields: ctx, s
+func (_m *FacadeInterface) AddSchedule(ctx datastore.Context, s *schedule.Schedule) error {
+	ret := _m.Called(ctx, s)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, *schedule.Schedule) error); ok {
+		r0 = rf(ctx, s)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetSchedule provides a mock function with given fields: ctx, scheduleID
+func (_m *FacadeInterface) GetSchedule(ctx datastore.Context, scheduleID string) (*schedule.Schedule, error) {
+	ret := _m.Called(ctx, scheduleID)
+
+	var r0 *schedule.Schedule
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *schedule.Schedule); ok {
+		r0 = rf(ctx, scheduleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schedule.Schedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, scheduleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSchedules provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetSchedules(ctx datastore.Context) ([]schedule.Schedule, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []schedule.Schedule
+	if rf, ok := ret.Get(0).(func(datastore.Context) []schedule.Schedule); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]schedule.Schedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCertificates provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetCertificates(ctx datastore.Context) ([]certificate.Certificate, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []certificate.Certificate
+	if rf, ok := ret.Get(0).(func(datastore.Context) []certificate.Certificate); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]certificate.Certificate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCertificate provides a mock function with given fields: ctx, certificateID
+func (_m *FacadeInterface) GetCertificate(ctx datastore.Context, certificateID string) (*certificate.Certificate, error) {
+	ret := _m.Called(ctx, certificateID)
+
+	var r0 *certificate.Certificate
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *certificate.Certificate); ok {
+		r0 = rf(ctx, certificateID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*certificate.Certificate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, certificateID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddCertificate provides a mock function with given fields: ctx, cert
+func (_m *FacadeInterface) AddCertificate(ctx datastore.Context, cert *certificate.Certificate) error {
+	ret := _m.Called(ctx, cert)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, *certificate.Certificate) error); ok {
+		r0 = rf(ctx, cert)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveCertificate provides a mock function with given fields: ctx, certificateID
+func (_m *FacadeInterface) RemoveCertificate(ctx datastore.Context, certificateID string) error {
+	ret := _m.Called(ctx, certificateID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, certificateID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAuditEvents provides a mock function with given fields: ctx, filter
+func (_m *FacadeInterface) GetAuditEvents(ctx datastore.Context, filter auditlog.Filter) ([]auditlog.Event, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []auditlog.Event
+	if rf, ok := ret.Get(0).(func(datastore.Context, auditlog.Filter) []auditlog.Event); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]auditlog.Event)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, auditlog.Filter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PruneAuditEvents provides a mock function with given fields: ctx, retention
+func (_m *FacadeInterface) PruneAuditEvents(ctx datastore.Context, retention time.Duration) (int, error) {
+	ret := _m.Called(ctx, retention)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(datastore.Context, time.Duration) int); ok {
+		r0 = rf(ctx, retention)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, time.Duration) error); ok {
+		r1 = rf(ctx, retention)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddSession provides a mock function with given fields: ctx, sess, recording
+func (_m *FacadeInterface) AddSession(ctx datastore.Context, sess *session.Session, recording []byte) error {
+	ret := _m.Called(ctx, sess, recording)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, *session.Session, []byte) error); ok {
+		r0 = rf(ctx, sess, recording)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetSessions provides a mock function with given fields: ctx, filter
+func (_m *FacadeInterface) GetSessions(ctx datastore.Context, filter session.Filter) ([]session.Session, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []session.Session
+	if rf, ok := ret.Get(0).(func(datastore.Context, session.Filter) []session.Session); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]session.Session)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, session.Filter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSession provides a mock function with given fields: ctx, sessionID
+func (_m *FacadeInterface) GetSession(ctx datastore.Context, sessionID string) (*session.Session, error) {
+	ret := _m.Called(ctx, sessionID)
+
+	var r0 *session.Session
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) *session.Session); ok {
+		r0 = rf(ctx, sessionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*session.Session)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSessionRecording provides a mock function with given fields: ctx, sessionID
+func (_m *FacadeInterface) GetSessionRecording(ctx datastore.Context, sessionID string) ([]byte, error) {
+	ret := _m.Called(ctx, sessionID)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) []byte); ok {
+		r0 = rf(ctx, sessionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SessionRecordingRequired provides a mock function with given fields: ctx, serviceID
+func (_m *FacadeInterface) SessionRecordingRequired(ctx datastore.Context, serviceID string) (bool, error) {
+	ret := _m.Called(ctx, serviceID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) bool); ok {
+		r0 = rf(ctx, serviceID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RebalancePool provides a mock function with given fields: ctx, poolID, dryRun
+func (_m *FacadeInterface) RebalancePool(ctx datastore.Context, poolID string, dryRun bool) (*simulation.Plan, error) {
+	ret := _m.Called(ctx, poolID, dryRun)
+
+	var r0 *simulation.Plan
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, bool) *simulation.Plan); ok {
+		r0 = rf(ctx, poolID, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*simulation.Plan)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, bool) error); ok {
+		r1 = rf(ctx, poolID, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveSchedule provides a mock function with given fields: ctx, scheduleID
+func (_m *FacadeInterface) RemoveSchedule(ctx datastore.Context, scheduleID string) error {
+	ret := _m.Called(ctx, scheduleID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) error); ok {
+		r0 = rf(ctx, scheduleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetScheduleStatus provides a mock function with given fields: ctx, scheduleID, status
+func (_m *FacadeInterface) SetScheduleStatus(ctx datastore.Context, scheduleID string, status schedule.Status) error {
+	ret := _m.Called(ctx, scheduleID, status)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, schedule.Status) error); ok {
+		r0 = rf(ctx, scheduleID, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SimulatePool provides a mock function with given fields: ctx, poolID, changes, template
+func (_m *FacadeInterface) SimulatePool(ctx datastore.Context, poolID string, changes simulation.Changes, template *servicetemplate.ServiceTemplate) (*simulation.Plan, error) {
+	ret := _m.Called(ctx, poolID, changes, template)
+
+	var r0 *simulation.Plan
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, simulation.Changes, *servicetemplate.ServiceTemplate) *simulation.Plan); ok {
+		r0 = rf(ctx, poolID, changes, template)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*simulation.Plan)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, simulation.Changes, *servicetemplate.ServiceTemplate) error); ok {
+		r1 = rf(ctx, poolID, changes, template)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateSchedule provides a mock function with given fields: ctx, s
+func (_m *FacadeInterface) UpdateSchedule(ctx datastore.Context, s *schedule.Schedule) error {
+	ret := _m.Called(ctx, s)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, *schedule.Schedule) error); ok {
+		r0 = rf(ctx, s)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetUserRoles provides a mock function with given fields: ctx, userName
+func (_m *FacadeInterface) GetUserRoles(ctx datastore.Context, userName string) (user.RoleBindings, error) {
+	ret := _m.Called(ctx, userName)
+
+	var r0 user.RoleBindings
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) user.RoleBindings); ok {
+		r0 = rf(ctx, userName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(user.RoleBindings)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, userName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUsers provides a mock function with given fields: ctx
+func (_m *FacadeInterface) GetUsers(ctx datastore.Context) ([]user.User, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []user.User
+	if rf, ok := ret.Get(0).(func(datastore.Context) []user.User); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]user.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RemoveIPs provides a mock function with given fields: ctx, []string
 func (_m *FacadeInterface) RemoveIPs(ctx datastore.Context, args []string) error {
 	ret := _m.Called(ctx, args)
@@ -357,6 +889,52 @@ func (_m *FacadeInterface) DeployTemplateStatus(deploymentID string, lastStatus
 	return r0, r1
 }
 
+// TemplateDiff provides a mock function with given fields: ctx, tenantID, templateID
+func (_m *FacadeInterface) TemplateDiff(ctx datastore.Context, tenantID string, templateID string) (*servicetemplate.TemplateDiff, error) {
+	ret := _m.Called(ctx, tenantID, templateID)
+
+	var r0 *servicetemplate.TemplateDiff
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string) *servicetemplate.TemplateDiff); ok {
+		r0 = rf(ctx, tenantID, templateID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*servicetemplate.TemplateDiff)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, string) error); ok {
+		r1 = rf(ctx, tenantID, templateID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpgradeTemplate provides a mock function with given fields: ctx, tenantID, templateID, snapshotSpacePercent
+func (_m *FacadeInterface) UpgradeTemplate(ctx datastore.Context, tenantID string, templateID string, snapshotSpacePercent int) (*servicetemplate.TemplateDiff, error) {
+	ret := _m.Called(ctx, tenantID, templateID, snapshotSpacePercent)
+
+	var r0 *servicetemplate.TemplateDiff
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, int) *servicetemplate.TemplateDiff); ok {
+		r0 = rf(ctx, tenantID, templateID, snapshotSpacePercent)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*servicetemplate.TemplateDiff)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string, string, int) error); ok {
+		r1 = rf(ctx, tenantID, templateID, snapshotSpacePercent)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EmergencyStopService provides a mock function with given fields: ctx, request
 func (_m *FacadeInterface) EmergencyStopService(ctx datastore.Context, request dao.ScheduleServiceRequest) (int, error) {
 	ret := _m.Called(ctx, request)
@@ -406,6 +984,34 @@ func (_m *FacadeInterface) EnablePublicEndpointVHost(ctx datastore.Context, serv
 	return r0
 }
 
+// SetPublicEndpointPortAccess provides a mock function with given fields: ctx, serviceid, endpointName, portAddr, access
+func (_m *FacadeInterface) SetPublicEndpointPortAccess(ctx datastore.Context, serviceid string, endpointName string, portAddr string, access *servicedefinition.AccessPolicy) error {
+	ret := _m.Called(ctx, serviceid, endpointName, portAddr, access)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string, *servicedefinition.AccessPolicy) error); ok {
+		r0 = rf(ctx, serviceid, endpointName, portAddr, access)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPublicEndpointVHostAccess provides a mock function with given fields: ctx, serviceid, endpointName, vhost, access
+func (_m *FacadeInterface) SetPublicEndpointVHostAccess(ctx datastore.Context, serviceid string, endpointName string, vhost string, access *servicedefinition.AccessPolicy) error {
+	ret := _m.Called(ctx, serviceid, endpointName, vhost, access)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, string, string, *servicedefinition.AccessPolicy) error); ok {
+		r0 = rf(ctx, serviceid, endpointName, vhost, access)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindHostsInPool provides a mock function with given fields: ctx, poolID
 func (_m *FacadeInterface) FindHostsInPool(ctx datastore.Context, poolID string) ([]host.Host, error) {
 	ret := _m.Called(ctx, poolID)
@@ -866,6 +1472,27 @@ func (_m *FacadeInterface) GetServiceConfig(ctx datastore.Context, fileID string
 	return r0, r1
 }
 
+// GetServiceConfigServiceID provides a mock function with given fields: ctx, fileID
+func (_m *FacadeInterface) GetServiceConfigServiceID(ctx datastore.Context, fileID string) (string, error) {
+	ret := _m.Called(ctx, fileID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(datastore.Context, string) string); ok {
+		r0 = rf(ctx, fileID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(datastore.Context, string) error); ok {
+		r1 = rf(ctx, fileID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetServiceConfigs provides a mock function with given fields: ctx, serviceID
 func (_m *FacadeInterface) GetServiceConfigs(ctx datastore.Context, serviceID string) ([]service.Config, error) {
 	ret := _m.Called(ctx, serviceID)
@@ -1308,15 +1935,15 @@ func (_m *FacadeInterface) MigrateServices(ctx datastore.Context, request dao.Se
 }
 
 // PredictStorageAvailability provides a mock function with given fields: ctx, lookahead
-func (_m *FacadeInterface) PredictStorageAvailability(ctx datastore.Context, lookahead time.Duration) (map[string]float64, error) {
+func (_m *FacadeInterface) PredictStorageAvailability(ctx datastore.Context, lookahead time.Duration) (map[string]statistics.Forecast, error) {
 	ret := _m.Called(ctx, lookahead)
 
-	var r0 map[string]float64
-	if rf, ok := ret.Get(0).(func(datastore.Context, time.Duration) map[string]float64); ok {
+	var r0 map[string]statistics.Forecast
+	if rf, ok := ret.Get(0).(func(datastore.Context, time.Duration) map[string]statistics.Forecast); ok {
 		r0 = rf(ctx, lookahead)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[string]float64)
+			r0 = ret.Get(0).(map[string]statistics.Forecast)
 		}
 	}
 
@@ -1608,6 +2235,20 @@ func (_m *FacadeInterface) RestartService(ctx datastore.Context, request dao.Sch
 	return r0, r1
 }
 
+// SetUserRoles provides a mock function with given fields: ctx, userName, roles
+func (_m *FacadeInterface) SetUserRoles(ctx datastore.Context, userName string, roles user.RoleBindings) error {
+	ret := _m.Called(ctx, userName, roles)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(datastore.Context, string, user.RoleBindings) error); ok {
+		r0 = rf(ctx, userName, roles)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SyncServiceRegistry provides a mock function with given fields: ctx, svc
 func (_m *FacadeInterface) SyncServiceRegistry(ctx datastore.Context, svc *service.Service) error {
 	ret := _m.Called(ctx, svc)
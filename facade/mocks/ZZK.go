@@ -252,6 +252,18 @@ func (_m *ZZK) RemoveResourcePool(poolID string) error {
 
 	return r0
 }
+func (_m *ZZK) RequestRebalance(poolID string) error {
+	ret := _m.Called(poolID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(poolID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
 func (_m *ZZK) AddVirtualIP(vip *pool.VirtualIP) error {
 	ret := _m.Called(vip)
 
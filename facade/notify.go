@@ -0,0 +1,73 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"fmt"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/alert"
+	"github.com/control-center/serviced/health"
+	"github.com/control-center/serviced/notify"
+)
+
+// notifyHealthCheckFailed sends a notification about a failing health check.
+// Repeated failures of the same check are deduplicated by the notifier.
+func (f *Facade) notifyHealthCheckFailed(key health.HealthStatusKey, value health.HealthStatus) {
+	// health checks are reported constantly, so don't look up the tenant
+	// when notifications are off
+	if f.notifier == notify.Discard {
+		return
+	}
+	tenantID, err := f.GetTenantID(datastore.Get(), key.ServiceID)
+	if err != nil {
+		plog.WithError(err).WithField("serviceid", key.ServiceID).Debug("Could not look up tenant of failing health check")
+	}
+	verb := "failed"
+	if value.Status == health.Timeout {
+		verb = "timed out"
+	}
+	f.notifier.Notify(notify.Event{
+		Kind:      notify.KindHealthCheckFailed,
+		Key:       fmt.Sprintf("%s/%d/%s", key.ServiceID, key.InstanceID, key.HealthCheckName),
+		TenantID:  tenantID,
+		ServiceID: key.ServiceID,
+		Summary:   fmt.Sprintf("Health check %s of instance %d of service %s %s", key.HealthCheckName, key.InstanceID, key.ServiceID, verb),
+		Time:      value.StartedAt,
+	})
+}
+
+// notifyBackupFailed sends a notification about a backup that did not
+// complete
+func (f *Facade) notifyBackupFailed(backupFilename string, err error) {
+	f.notifier.Notify(notify.Event{
+		Kind:    notify.KindBackupFailed,
+		Key:     backupFilename,
+		Summary: fmt.Sprintf("Backup %s failed", backupFilename),
+		Details: err.Error(),
+	})
+}
+
+// notifyAlertOpened sends a notification about a newly opened alert
+func (f *Facade) notifyAlertOpened(a *alert.Alert) {
+	f.notifier.Notify(notify.Event{
+		Kind:      notify.KindAlertOpened,
+		Key:       a.ID,
+		TenantID:  a.TenantID,
+		ServiceID: a.ServiceID,
+		Summary:   fmt.Sprintf("Threshold %s of service %s was breached by %s", a.ThresholdName, a.ServiceID, a.DataPoint),
+		Details:   a.Message,
+		Time:      a.Opened,
+	})
+}
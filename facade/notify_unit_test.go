@@ -0,0 +1,81 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/datastore"
+	datastoremocks "github.com/control-center/serviced/datastore/mocks"
+	"github.com/control-center/serviced/domain/alert"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/health"
+	"github.com/control-center/serviced/notify"
+	notifymocks "github.com/control-center/serviced/notify/mocks"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) Test_ReportHealthStatus_NotifiesFailure(c *C) {
+	// health checks are reported without a context, so the tenant is looked
+	// up with the global one
+	datastore.Register(&datastoremocks.Driver{})
+	notifier := &notifymocks.Notifier{}
+	notifier.On("Notify", mock.AnythingOfType("notify.Event")).Return()
+	ft.Facade.SetNotifier(notifier)
+	ft.Facade.SetHealthCache(health.New())
+	ft.serviceStore.On("GetServiceDetails", mock.Anything, "svc1").
+		Return(&service.ServiceDetails{ID: "svc1", ParentServiceID: "tenant1"}, nil)
+	ft.serviceStore.On("GetServiceDetails", mock.Anything, "tenant1").
+		Return(&service.ServiceDetails{ID: "tenant1"}, nil)
+
+	key := health.HealthStatusKey{ServiceID: "svc1", InstanceID: 0, HealthCheckName: "answering"}
+	ft.Facade.ReportHealthStatus(key, health.HealthStatus{Status: health.OK}, time.Minute)
+	notifier.AssertNotCalled(c, "Notify", mock.Anything)
+
+	ft.Facade.ReportHealthStatus(key, health.HealthStatus{Status: health.Failed}, time.Minute)
+	notifier.AssertNumberOfCalls(c, "Notify", 1)
+	e := notifier.Calls[0].Arguments.Get(0).(notify.Event)
+	c.Assert(e.Kind, Equals, notify.KindHealthCheckFailed)
+	c.Assert(e.TenantID, Equals, "tenant1")
+	c.Assert(e.ServiceID, Equals, "svc1")
+	c.Assert(e.Key, Equals, "svc1/0/answering")
+}
+
+func (ft *FacadeUnitTest) Test_ReportHealthStatus_NotificationsOff(c *C) {
+	ft.Facade.SetNotifier(notify.Discard)
+	ft.Facade.SetHealthCache(health.New())
+
+	// the tenant is not looked up, so the service store has no expectations
+	key := health.HealthStatusKey{ServiceID: "svc1", InstanceID: 0, HealthCheckName: "answering"}
+	ft.Facade.ReportHealthStatus(key, health.HealthStatus{Status: health.Failed}, time.Minute)
+	ft.serviceStore.AssertNotCalled(c, "GetServiceDetails", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_EvaluateThresholds_NotifiesAlert(c *C) {
+	notifier := &notifymocks.Notifier{}
+	notifier.On("Notify", mock.AnythingOfType("notify.Event")).Return()
+	ft.Facade.SetNotifier(notifier)
+	ft.setupThresholdService(150)
+	ft.alertStore.On("GetAlerts", ft.ctx, activeAlertStates).Return([]alert.Alert{}, nil)
+	ft.alertStore.On("Put", ft.ctx, mock.AnythingOfType("*alert.Alert")).Return(nil)
+
+	c.Assert(ft.Facade.EvaluateThresholds(ft.ctx), IsNil)
+	notifier.AssertNumberOfCalls(c, "Notify", 1)
+	e := notifier.Calls[0].Arguments.Get(0).(notify.Event)
+	c.Assert(e.Kind, Equals, notify.KindAlertOpened)
+	c.Assert(e.TenantID, Equals, "alerttenant")
+}
@@ -587,5 +587,18 @@ func (f *Facade) GetReadPools(ctx datastore.Context) ([]pool.ReadPool, error) {
 		return readPools, nil
 	}
 
-	return f.poolCache.GetPools(getPoolsFunc)
+	readPools, err := f.poolCache.GetPools(getPoolsFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	// certificates expire regardless of the cache, so their warnings are
+	// added to a copy of the cached pools
+	warnings := f.getCertificateWarnings(ctx)
+	pools := make([]pool.ReadPool, len(readPools))
+	for i := range readPools {
+		pools[i] = readPools[i]
+		pools[i].CertificateWarnings = warnings[pools[i].ID]
+	}
+	return pools, nil
 }
@@ -472,3 +472,90 @@ func (f *Facade) GetAllPublicEndpoints(ctx datastore.Context) ([]service.PublicE
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("GetAllPublicEndpoints"))
 	return f.serviceStore.GetAllPublicEndpoints(ctx)
 }
+
+// SetPublicEndpointPortAccess sets the access policy of a port public
+// endpoint.  A nil policy allows every client.
+func (f *Facade) SetPublicEndpointPortAccess(ctx datastore.Context, serviceid, endpointName, portAddr string, access *servicedefinition.AccessPolicy) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SetPublicEndpointPortAccess"))
+	alog := f.auditLogger.Message(ctx, "Setting Public Endpoint Port Access").Action(audit.Update).ID(serviceid).
+		WithFields(logrus.Fields{
+			"endpointname": endpointName,
+			"portaddr":     portAddr,
+		})
+	// Scrub the port for all checks, as this is what gets stored against the service.
+	portAddr = service.ScrubPortString(portAddr)
+
+	if access.IsZero() {
+		access = nil
+	} else if err := access.ValidEntity(); err != nil {
+		return alog.Error(err)
+	}
+
+	// Get the service for this service id.
+	svc, err := f.GetService(ctx, serviceid)
+	if err != nil {
+		err = fmt.Errorf("Could not find service %s: %s", serviceid, err)
+		glog.Error(err)
+		return alog.Error(err)
+	}
+	alog = alog.Entity(svc)
+
+	if err = svc.SetPortAccess(endpointName, portAddr, access); err != nil {
+		err = fmt.Errorf("error setting access for port %s, service (%s): %v", portAddr, svc.Name, err)
+		return alog.Error(err)
+	}
+
+	glog.V(2).Infof("Port (%s) access policy set for service (%s)", portAddr, svc.Name)
+
+	if err = f.UpdateService(ctx, *svc); err != nil {
+		glog.Error(err)
+		return alog.Error(err)
+	}
+
+	glog.V(2).Infof("Service (%s) updated", svc.Name)
+	alog.Succeeded()
+	return nil
+}
+
+// SetPublicEndpointVHostAccess sets the access policy of a vhost public
+// endpoint.  A nil policy allows every client.
+func (f *Facade) SetPublicEndpointVHostAccess(ctx datastore.Context, serviceid, endpointName, vhost string, access *servicedefinition.AccessPolicy) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SetPublicEndpointVHostAccess"))
+	alog := f.auditLogger.Message(ctx, "Setting Public Endpoint VHost Access").Action(audit.Update).ID(serviceid).
+		WithFields(logrus.Fields{
+			"endpointname": endpointName,
+			"vhost":        vhost,
+		})
+
+	if access.IsZero() {
+		access = nil
+	} else if err := access.ValidEntity(); err != nil {
+		return alog.Error(err)
+	}
+
+	// Get the service for this service id.
+	svc, err := f.GetService(ctx, serviceid)
+	if err != nil {
+		err = fmt.Errorf("Could not find service %s: %s", serviceid, err)
+		glog.Error(err)
+		return alog.Error(err)
+	}
+	alog = alog.Entity(svc)
+
+	if err = svc.SetVirtualHostAccess(endpointName, vhost, access); err != nil {
+		err = fmt.Errorf("Error setting vhost (%s) access for service (%s): %v", vhost, svc.Name, err)
+		glog.Error(err)
+		return alog.Error(err)
+	}
+
+	glog.V(2).Infof("VHost (%s) access policy set for service (%s)", vhost, svc.Name)
+
+	if err = f.UpdateService(ctx, *svc); err != nil {
+		glog.Error(err)
+		return alog.Error(err)
+	}
+
+	glog.V(2).Infof("Service (%s) updated", svc.Name)
+	alog.Succeeded()
+	return nil
+}
@@ -698,3 +698,51 @@ func (ft *FacadeIntegrationTest) Test_PublicEndpoint_SetAddressConfig(c *C) {
 
 	fmt.Println(" ##### Test_PublicEndpoint_SetAddressConfig: PASSED")
 }
+
+func (ft *FacadeIntegrationTest) Test_PublicEndpointPort_SetAccess(c *C) {
+	fmt.Println(" ##### Test_PublicEndpointPort_SetAccess: STARTED")
+
+	svcA, _ := ft.setupServiceWithPublicEndpoints(c)
+
+	// Restrict the port to a network.
+	ft.zzk.On("GetVHost", "zproxy").Return(svcA.ID, "zproxy", nil).Once()
+	ft.zzk.On("GetPublicPort", ":22222").Return(svcA.ID, "zproxy", nil).Once()
+	access := &servicedefinition.AccessPolicy{Allow: []string{"10.0.0.0/8"}, RateLimit: 60}
+	err := ft.Facade.SetPublicEndpointPortAccess(ft.CTX, svcA.ID, "zproxy", "22222", access)
+	c.Assert(err, IsNil)
+
+	svc, err := ft.Facade.GetService(ft.CTX, svcA.ID)
+	c.Assert(err, IsNil)
+	c.Assert(svc.Endpoints[0].PortList[0].Access, DeepEquals, access)
+
+	// An empty policy removes the restriction.
+	ft.zzk.On("GetVHost", "zproxy").Return(svcA.ID, "zproxy", nil).Once()
+	ft.zzk.On("GetPublicPort", ":22222").Return(svcA.ID, "zproxy", nil).Once()
+	err = ft.Facade.SetPublicEndpointPortAccess(ft.CTX, svcA.ID, "zproxy", ":22222", &servicedefinition.AccessPolicy{})
+	c.Assert(err, IsNil)
+
+	svc, err = ft.Facade.GetService(ft.CTX, svcA.ID)
+	c.Assert(err, IsNil)
+	c.Assert(svc.Endpoints[0].PortList[0].Access, IsNil)
+
+	fmt.Println(" ##### Test_PublicEndpointPort_SetAccess: PASSED")
+}
+
+func (ft *FacadeIntegrationTest) Test_PublicEndpointVHost_SetInvalidAccess(c *C) {
+	fmt.Println(" ##### Test_PublicEndpointVHost_SetInvalidAccess: STARTED")
+
+	svcA, _ := ft.setupServiceWithPublicEndpoints(c)
+
+	access := &servicedefinition.AccessPolicy{Deny: []string{"not-a-network"}}
+	err := ft.Facade.SetPublicEndpointVHostAccess(ft.CTX, svcA.ID, "zproxy", "zproxy", access)
+	if err == nil {
+		c.Errorf("Expected failure setting an invalid access policy")
+	}
+
+	err = ft.Facade.SetPublicEndpointVHostAccess(ft.CTX, svcA.ID, "zproxy", "invalid", nil)
+	if err == nil {
+		c.Errorf("Expected failure setting the access policy of an invalid vhost")
+	}
+
+	fmt.Println(" ##### Test_PublicEndpointVHost_SetInvalidAccess: PASSED")
+}
@@ -0,0 +1,126 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/dfs/target"
+	"github.com/control-center/serviced/domain/schedule"
+	"github.com/control-center/serviced/utils"
+)
+
+// GetSchedules returns the snapshot and backup schedules
+func (f *Facade) GetSchedules(ctx datastore.Context) ([]schedule.Schedule, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetSchedules"))
+	return f.scheduleStore.GetSchedules(ctx)
+}
+
+// GetSchedule returns a schedule by its id
+func (f *Facade) GetSchedule(ctx datastore.Context, scheduleID string) (*schedule.Schedule, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetSchedule"))
+	return f.scheduleStore.Get(ctx, scheduleID)
+}
+
+// AddSchedule adds a snapshot or backup schedule and sets its id.  The
+// schedule first runs at the next time that matches its cron expression.
+func (f *Facade) AddSchedule(ctx datastore.Context, s *schedule.Schedule) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.AddSchedule"))
+	alog := f.auditLogger.Message(ctx, "Adding Schedule").Action(audit.Add).Type(schedule.GetType())
+	if s.ID == "" {
+		var err error
+		if s.ID, err = utils.NewUUID36(); err != nil {
+			return alog.Error(err)
+		}
+	}
+	alog = alog.ID(s.ID)
+	s.Status = schedule.Status{}
+	if err := f.checkSchedule(ctx, s); err != nil {
+		return alog.Error(err)
+	}
+	if err := f.scheduleStore.Put(ctx, s); err != nil {
+		return alog.Error(err)
+	}
+	alog.Succeeded()
+	return nil
+}
+
+// UpdateSchedule changes the cron expression, retention or target of a
+// schedule, or enables or disables it.  The status of the schedule is kept.
+func (f *Facade) UpdateSchedule(ctx datastore.Context, s *schedule.Schedule) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.UpdateSchedule"))
+	alog := f.auditLogger.Message(ctx, "Updating Schedule").Action(audit.Update).Type(schedule.GetType()).ID(s.ID)
+	current, err := f.scheduleStore.Get(ctx, s.ID)
+	if err != nil {
+		return alog.Error(err)
+	}
+	s.Status = current.Status
+	s.DatabaseVersion = current.DatabaseVersion
+	if err := f.checkSchedule(ctx, s); err != nil {
+		return alog.Error(err)
+	}
+	if err := f.scheduleStore.Put(ctx, s); err != nil {
+		return alog.Error(err)
+	}
+	alog.Succeeded()
+	return nil
+}
+
+// checkSchedule validates a schedule that is being added or updated and sets
+// its next run
+func (f *Facade) checkSchedule(ctx datastore.Context, s *schedule.Schedule) error {
+	if err := s.ValidEntity(); err != nil {
+		return err
+	}
+	if s.Kind == schedule.KindSnapshot {
+		tenantID, err := f.GetTenantID(ctx, s.TenantID)
+		if err != nil {
+			return err
+		} else if tenantID != s.TenantID {
+			return fmt.Errorf("service %s is not a tenant", s.TenantID)
+		}
+	}
+	if s.Target != "" {
+		if _, err := target.New(s.Target); err != nil {
+			return err
+		}
+	}
+	return s.UpdateNextRun(time.Now())
+}
+
+// RemoveSchedule removes a schedule.  The snapshots and backups it took are
+// kept.
+func (f *Facade) RemoveSchedule(ctx datastore.Context, scheduleID string) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RemoveSchedule"))
+	alog := f.auditLogger.Message(ctx, "Removing Schedule").Action(audit.Remove).Type(schedule.GetType()).ID(scheduleID)
+	if err := f.scheduleStore.Delete(ctx, scheduleID); err != nil {
+		return alog.Error(err)
+	}
+	alog.Succeeded()
+	return nil
+}
+
+// SetScheduleStatus records the outcome of a run of a schedule
+func (f *Facade) SetScheduleStatus(ctx datastore.Context, scheduleID string, status schedule.Status) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SetScheduleStatus"))
+	s, err := f.scheduleStore.Get(ctx, scheduleID)
+	if err != nil {
+		return err
+	}
+	s.Status = status
+	return f.scheduleStore.Put(ctx, s)
+}
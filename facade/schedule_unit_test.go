@@ -0,0 +1,100 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/domain/schedule"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupScheduleTenant(serviceID, parentID string) {
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, serviceID).
+		Return(&service.ServiceDetails{ID: serviceID, ParentServiceID: parentID}, nil)
+	if parentID != "" {
+		ft.serviceStore.On("GetServiceDetails", ft.ctx, parentID).
+			Return(&service.ServiceDetails{ID: parentID}, nil)
+	}
+}
+
+func (ft *FacadeUnitTest) Test_AddSchedule(c *C) {
+	ft.setupScheduleTenant("tenant1", "")
+	ft.scheduleStore.On("Put", ft.ctx, mock.AnythingOfType("*schedule.Schedule")).Return(nil)
+
+	s := &schedule.Schedule{
+		Kind:      schedule.KindSnapshot,
+		TenantID:  "tenant1",
+		Cron:      "@daily",
+		Retention: schedule.Retention{Daily: 7},
+		Status:    schedule.Status{LastOutcome: schedule.OutcomeFailed},
+	}
+	err := ft.Facade.AddSchedule(ft.ctx, s)
+	c.Assert(err, IsNil)
+	c.Assert(s.ID, Not(Equals), "")
+	c.Assert(s.Status.LastOutcome, Equals, schedule.Outcome(""))
+	c.Assert(s.Status.NextRun.After(time.Now()), Equals, true)
+	ft.scheduleStore.AssertCalled(c, "Put", ft.ctx, s)
+}
+
+func (ft *FacadeUnitTest) Test_AddSchedule_NotTenant(c *C) {
+	ft.setupScheduleTenant("child", "tenant1")
+
+	s := &schedule.Schedule{Kind: schedule.KindSnapshot, TenantID: "child", Cron: "@daily"}
+	err := ft.Facade.AddSchedule(ft.ctx, s)
+	c.Assert(err, NotNil)
+	ft.scheduleStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_AddSchedule_Invalid(c *C) {
+	s := &schedule.Schedule{Kind: schedule.KindBackup, Cron: "whenever"}
+	err := ft.Facade.AddSchedule(ft.ctx, s)
+	c.Assert(err, NotNil)
+	ft.scheduleStore.AssertNotCalled(c, "Put", mock.Anything, mock.Anything)
+}
+
+func (ft *FacadeUnitTest) Test_UpdateSchedule_KeepsStatus(c *C) {
+	lastRun := time.Now().Add(-time.Hour)
+	current := &schedule.Schedule{
+		ID:     "sched1",
+		Kind:   schedule.KindBackup,
+		Cron:   "@daily",
+		Status: schedule.Status{LastRun: lastRun, Artifacts: []schedule.Artifact{{ID: "/backups/backup.tgz", Created: lastRun}}},
+	}
+	ft.scheduleStore.On("Get", ft.ctx, "sched1").Return(current, nil)
+	ft.scheduleStore.On("Put", ft.ctx, mock.AnythingOfType("*schedule.Schedule")).Return(nil)
+
+	s := &schedule.Schedule{ID: "sched1", Kind: schedule.KindBackup, Cron: "@hourly", Disabled: true}
+	err := ft.Facade.UpdateSchedule(ft.ctx, s)
+	c.Assert(err, IsNil)
+	c.Assert(s.Status.LastRun, Equals, lastRun)
+	c.Assert(s.Status.Artifacts, DeepEquals, current.Status.Artifacts)
+	c.Assert(s.Status.NextRun.Sub(time.Now()) <= time.Hour, Equals, true)
+}
+
+func (ft *FacadeUnitTest) Test_SetScheduleStatus(c *C) {
+	ft.scheduleStore.On("Get", ft.ctx, "sched1").Return(&schedule.Schedule{ID: "sched1", Kind: schedule.KindBackup, Cron: "@daily"}, nil)
+	ft.scheduleStore.On("Put", ft.ctx, mock.AnythingOfType("*schedule.Schedule")).Return(nil)
+
+	status := schedule.Status{LastRun: time.Now(), LastOutcome: schedule.OutcomeSucceeded}
+	err := ft.Facade.SetScheduleStatus(ft.ctx, "sched1", status)
+	c.Assert(err, IsNil)
+	ft.scheduleStore.AssertCalled(c, "Put", ft.ctx, mock.MatchedBy(func(s *schedule.Schedule) bool {
+		return s.Status.LastOutcome == schedule.OutcomeSucceeded
+	}))
+}
@@ -2362,9 +2362,20 @@ func (f *Facade) AssignIPs(ctx datastore.Context, request addressassignment.Assi
 }
 
 // ServiceUse will tag a new image (imageName) in a given registry for a given tenant
-// to latest, making sure to push changes to the registry
+// to latest, making sure to push changes to the registry.  If noOp is set, it
+// only checks that the images can be used, without changing the registry or
+// the services.
 func (f *Facade) ServiceUse(ctx datastore.Context, tenantID, serviceID, imageName, registryName string, replaceImgs []string, noOp bool) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.ServiceUse"))
+	if noOp {
+		for _, replaceImg := range replaceImgs {
+			if _, err := commons.ParseImageID(replaceImg); err != nil {
+				return fmt.Errorf("error parsing image ID %s: %s", replaceImg, err)
+			}
+		}
+		return f.dfs.VerifyImage(imageName)
+	}
+
 	// Push into elastic
 	if err := f.Download(imageName, tenantID); err != nil {
 		return err
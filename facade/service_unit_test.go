@@ -23,6 +23,7 @@ import (
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/serviceconfigfile"
 	"github.com/control-center/serviced/utils"
+	"github.com/stretchr/testify/mock"
 	. "gopkg.in/check.v1"
 )
 
@@ -304,6 +305,21 @@ func (ft *FacadeUnitTest) Test_CheckRemoveRegistryImage_KeepsImage(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (ft *FacadeUnitTest) Test_ServiceUse_NoOp(c *C) {
+	// Expectations: the image is verified, but not downloaded into the
+	// registry, and the services are not looked up or updated
+	ft.dfs.On("VerifyImage", "zenoss/core:5.1").Return(nil)
+	ft.dfs.On("VerifyImage", "zenoss/missing:1").Return(fmt.Errorf("no such image"))
+
+	err := ft.Facade.ServiceUse(ft.ctx, "tenantID", "", "zenoss/core:5.1", "", []string{"zenoss/core"}, true)
+	c.Assert(err, IsNil)
+
+	err = ft.Facade.ServiceUse(ft.ctx, "tenantID", "", "zenoss/missing:1", "", nil, true)
+	c.Assert(err, ErrorMatches, "no such image")
+
+	ft.dfs.AssertNotCalled(c, "Download", mock.Anything, mock.Anything, mock.Anything)
+}
+
 // Test that the 'getService' function defined by facade.evaluateService() works properly on failure
 func (ft *FacadeUnitTest) Test_GetEvaluatedServiceGetParentFails(c *C) {
 	parentID := "parentServiceID"
@@ -16,6 +16,7 @@ package facade
 import (
 	"errors"
 	"os"
+	"path"
 	"reflect"
 
 	log "github.com/Sirupsen/logrus"
@@ -76,6 +77,22 @@ func (f *Facade) GetServiceConfig(ctx datastore.Context, fileID string) (*servic
 	return &file.ConfFile, nil
 }
 
+// GetServiceConfigServiceID returns the id of the service that a config file
+// belongs to
+func (f *Facade) GetServiceConfigServiceID(ctx datastore.Context, fileID string) (string, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetServiceConfigServiceID"))
+	logger := plog.WithField("fileid", fileID)
+
+	file := &serviceconfigfile.SvcConfigFile{}
+	if err := f.configStore.Get(ctx, serviceconfigfile.Key(fileID), file); err != nil {
+		logger.WithError(err).Debug("Could not get service config file")
+		return "", err
+	}
+
+	// the service path is made of the ids of the service and its parents
+	return path.Base(file.ServicePath), nil
+}
+
 // AddServiceConfig creates a config file for a service
 func (f *Facade) AddServiceConfig(ctx datastore.Context, serviceID string, conf servicedefinition.ConfigFile) error {
 	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.AddServiceConfig"))
@@ -91,6 +91,7 @@ func (sc *serviceRegistryCache) BuildSyncRequest(tenantID string, svc *service.S
 					ServiceID:   svc.ID,
 					Protocol:    p.Protocol,
 					UseTLS:      p.UseTLS,
+					Access:      p.Access,
 				}
 				request.PortsToPublish[key] = pub
 			}
@@ -107,6 +108,7 @@ func (sc *serviceRegistryCache) BuildSyncRequest(tenantID string, svc *service.S
 					TenantID:    tenantID,
 					Application: ep.Application,
 					ServiceID:   svc.ID,
+					Access:      v.Access,
 				}
 				request.VHostsToPublish[key] = vh
 			}
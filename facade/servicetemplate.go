@@ -15,10 +15,13 @@ package facade
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/dao"
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
@@ -344,7 +347,7 @@ func (f *Facade) DeployTemplate(ctx datastore.Context, poolID string, templateID
 	tenantIDs := make([]string, len(template.Services))
 	for i, sd := range template.Services {
 		logger.WithField("servicename", sd.Name).Info("Deploying service")
-		tenantID, err := f.deployService(ctx, "", "", deploymentID, poolID, false, sd, statusUpdater)
+		tenantID, err := f.deployService(ctx, "", "", deploymentID, poolID, false, sd, template, statusUpdater)
 		if err != nil {
 			logger.WithError(err).Error("Could not deploy application")
 			return nil, alog.Error(err)
@@ -418,11 +421,13 @@ func (f *Facade) DeployService(ctx datastore.Context, poolID, parentID string, o
 	}
 
 	var statusUpdater = func(status string) {}
-	result, err := f.deployService(ctx, tenantID, svc.ID, svc.DeploymentID, poolID, overwrite, svcDef, statusUpdater)
+	result, err := f.deployService(ctx, tenantID, svc.ID, svc.DeploymentID, poolID, overwrite, svcDef, nil, statusUpdater)
 	return result, alog.Error(err)
 }
 
-func (f *Facade) deployService(ctx datastore.Context, tenantID string, parentServiceID, deploymentID, poolID string, overwrite bool, svcDef servicedefinition.ServiceDefinition, updateStatus func(string)) (string, error) {
+// deployService deploys a service definition and its children.  If tpl is
+// set, the services record that they were deployed from that template.
+func (f *Facade) deployService(ctx datastore.Context, tenantID string, parentServiceID, deploymentID, poolID string, overwrite bool, svcDef servicedefinition.ServiceDefinition, tpl *servicetemplate.ServiceTemplate, updateStatus func(string)) (string, error) {
 	logger := plog.WithFields(logrus.Fields{
 		"tenant":       tenantID,
 		"parentid":     parentServiceID,
@@ -436,6 +441,10 @@ func (f *Facade) deployService(ctx datastore.Context, tenantID string, parentSer
 		logger.WithError(err).Error("Could not create service")
 		return "", err
 	}
+	if tpl != nil {
+		newsvc.TemplateID = tpl.ID
+		newsvc.TemplateVersion = tpl.Version
+	}
 
 	updateStatus("deploy_loading_service|" + newsvc.Name)
 	logger = logger.WithField("service", newsvc.Name)
@@ -483,7 +492,7 @@ func (f *Facade) deployService(ctx datastore.Context, tenantID string, parentSer
 
 	// walk child services
 	for _, sd := range svcDef.Services {
-		if _, err := f.deployService(ctx, tenantID, newsvc.ID, deploymentID, poolID, overwrite, sd, updateStatus); err != nil {
+		if _, err := f.deployService(ctx, tenantID, newsvc.ID, deploymentID, poolID, overwrite, sd, tpl, updateStatus); err != nil {
 			logger.WithError(err).WithFields(logrus.Fields{
 				"serviceid": newsvc.ID,
 				"service":   sd.Name,
@@ -513,3 +522,335 @@ func (f *Facade) evaluateEndpointTemplates(ctx datastore.Context, newsvc *servic
 
 	return newsvc.EvaluateEndpointTemplates(getService, findChildService, 0)
 }
+
+// templateUpgrade is the plan for upgrading a deployed application to a
+// template
+type templateUpgrade struct {
+	diff     *servicetemplate.TemplateDiff
+	template *servicetemplate.ServiceTemplate
+	services map[string]*service.Service                     // deployed services by name path
+	defs     map[string]*servicedefinition.ServiceDefinition // template service definitions by name path
+}
+
+// TemplateDiff returns the changes that upgrading a deployed application to a
+// template would make to its services.  Changes are relative to the template
+// the application was deployed from if it is still installed, otherwise to the
+// deployed services themselves.
+func (f *Facade) TemplateDiff(ctx datastore.Context, tenantID, templateID string) (*servicetemplate.TemplateDiff, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.TemplateDiff"))
+	upgrade, err := f.getTemplateUpgrade(ctx, tenantID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	return upgrade.diff, nil
+}
+
+// UpgradeTemplate upgrades a deployed application to a template as a single
+// migration.  The tenant is snapshotted first and rolled back to the snapshot
+// if the upgrade fails.  Config files that were edited since the application
+// was deployed are kept, and services that the template no longer defines are
+// left in place.
+func (f *Facade) UpgradeTemplate(ctx datastore.Context, tenantID, templateID string, snapshotSpacePercent int) (*servicetemplate.TemplateDiff, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.UpgradeTemplate"))
+	alog := f.auditLogger.Message(ctx, "Upgrading Application to Service Template").
+		Action(audit.Migrate).ID(templateID).Type(servicetemplate.GetType()).
+		WithField("tenantid", tenantID)
+	logger := plog.WithFields(logrus.Fields{
+		"tenantid":   tenantID,
+		"templateid": templateID,
+	})
+	if err := f.DFSLock(ctx).LockWithTimeout("upgrade template", userLockTimeout); err != nil {
+		logger.WithError(err).Debug("Could not lock the dfs")
+		return nil, alog.Error(err)
+	}
+	defer f.DFSLock(ctx).Unlock()
+
+	upgrade, err := f.getTemplateUpgrade(ctx, tenantID, templateID)
+	if err != nil {
+		return nil, alog.Error(err)
+	}
+
+	message := fmt.Sprintf("before upgrade to template %s version %s", upgrade.template.Name, upgrade.template.Version)
+	snapshot, err := f.Snapshot(ctx, tenantID, message, nil, snapshotSpacePercent)
+	if err != nil {
+		logger.WithError(err).Debug("Could not snapshot tenant")
+		return nil, alog.Error(err)
+	}
+	upgrade.diff.Snapshot = snapshot
+	logger = logger.WithField("snapshot", snapshot)
+	logger.Info("Took snapshot of application before upgrade")
+
+	if err := f.applyTemplateUpgrade(ctx, upgrade); err != nil {
+		logger.WithError(err).Error("Could not upgrade application, rolling back")
+		if rerr := f.Rollback(ctx, snapshot, true); rerr != nil {
+			logger.WithError(rerr).Error("Could not roll back application after failed upgrade")
+			return nil, alog.Error(fmt.Errorf("%s; could not roll back to snapshot %s: %s", err, snapshot, rerr))
+		}
+		return nil, alog.Error(fmt.Errorf("%s; rolled back to snapshot %s", err, snapshot))
+	}
+
+	if err := f.ReloadLogstashConfig(ctx); err != nil {
+		logger.WithError(err).Error("Could not reload logstash configs after upgrading")
+	}
+	logger.WithField("changes", len(upgrade.diff.Changes)).Info("Upgraded application")
+	alog.Succeeded()
+	return upgrade.diff, nil
+}
+
+// getTemplateUpgrade loads a tenant's services and a template and works out
+// the changes needed to upgrade the tenant to the template.
+func (f *Facade) getTemplateUpgrade(ctx datastore.Context, tenantID, templateID string) (*templateUpgrade, error) {
+	logger := plog.WithFields(logrus.Fields{
+		"tenantid":   tenantID,
+		"templateid": templateID,
+	})
+	template, err := f.templateStore.Get(ctx, templateID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not load template")
+		return nil, err
+	}
+	svcs, err := f.GetServiceList(ctx, tenantID)
+	if err != nil {
+		logger.WithError(err).Debug("Could not load services of tenant")
+		return nil, err
+	}
+
+	// index the deployed services by name path
+	byID := make(map[string]*service.Service)
+	children := make(map[string][]*service.Service)
+	for _, svc := range svcs {
+		byID[svc.ID] = svc
+		children[svc.ParentServiceID] = append(children[svc.ParentServiceID], svc)
+	}
+	tenant, ok := byID[tenantID]
+	if !ok || tenant.ParentServiceID != "" {
+		return nil, fmt.Errorf("service %s is not an application", tenantID)
+	}
+	upgrade := &templateUpgrade{
+		template: template,
+		services: make(map[string]*service.Service),
+		defs:     make(map[string]*servicedefinition.ServiceDefinition),
+	}
+	var indexServices func(path string, svc *service.Service)
+	indexServices = func(path string, svc *service.Service) {
+		upgrade.services[path] = svc
+		for _, child := range children[svc.ID] {
+			indexServices(path+"/"+child.Name, child)
+		}
+	}
+	indexServices(tenant.Name, tenant)
+
+	to := findTemplateService(template, tenant.Name)
+	if to == nil {
+		return nil, fmt.Errorf("template %s does not define application %s", templateID, tenant.Name)
+	}
+	var indexDefinitions func(path string, sd *servicedefinition.ServiceDefinition)
+	indexDefinitions = func(path string, sd *servicedefinition.ServiceDefinition) {
+		upgrade.defs[path] = sd
+		for i := range sd.Services {
+			indexDefinitions(path+"/"+sd.Services[i].Name, &sd.Services[i])
+		}
+	}
+	indexDefinitions(tenant.Name, to)
+
+	// compare with the template the application was deployed from, if we
+	// still have it
+	var from *servicedefinition.ServiceDefinition
+	if tenant.TemplateID != "" {
+		if fromTemplate, err := f.templateStore.Get(ctx, tenant.TemplateID); err == nil {
+			from = findTemplateService(fromTemplate, tenant.Name)
+		} else if !datastore.IsErrNoSuchEntity(err) {
+			logger.WithError(err).WithField("fromtemplateid", tenant.TemplateID).Debug("Could not load template the application was deployed from")
+			return nil, err
+		}
+	}
+	if from == nil {
+		logger.Info("Template the application was deployed from is not installed; comparing with the deployed services")
+		sd := deployedDefinition(tenant, children)
+		from = &sd
+	}
+
+	upgrade.diff = &servicetemplate.TemplateDiff{
+		TenantID:       tenantID,
+		TemplateID:     template.ID,
+		Version:        template.Version,
+		FromTemplateID: tenant.TemplateID,
+		FromVersion:    tenant.TemplateVersion,
+		Changes:        servicetemplate.Diff(*from, *to),
+	}
+	for i, change := range upgrade.diff.Changes {
+		if change.Kind != servicetemplate.ChangeConfigFile {
+			continue
+		}
+		if svc, ok := upgrade.services[change.Path]; ok && configFileEdited(svc, change.Name) {
+			upgrade.diff.Changes[i].Preserved = true
+		}
+	}
+	return upgrade, nil
+}
+
+// applyTemplateUpgrade migrates the deployed services to their new template
+// definitions and deploys the services the template added.
+func (f *Facade) applyTemplateUpgrade(ctx datastore.Context, upgrade *templateUpgrade) error {
+	tenantID := upgrade.diff.TenantID
+	changes := make(map[string][]servicetemplate.Change)
+	for _, change := range upgrade.diff.Changes {
+		changes[change.Path] = append(changes[change.Path], change)
+	}
+
+	paths := make([]string, 0, len(upgrade.services))
+	for path := range upgrade.services {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	images := make(map[string]string)
+	req := dao.ServiceMigrationRequest{ServiceID: tenantID}
+	for _, path := range paths {
+		svc := upgrade.services[path]
+		sd, ok := upgrade.defs[path]
+		if !ok {
+			// not defined by the template
+			continue
+		}
+		for _, change := range changes[path] {
+			if change.Kind != servicetemplate.ChangeImage || sd.ImageID == "" {
+				continue
+			}
+			image, ok := images[sd.ImageID]
+			if !ok {
+				var err error
+				if image, err = f.dfs.Download(sd.ImageID, tenantID, true); err != nil {
+					plog.WithError(err).WithField("image", sd.ImageID).Error("Could not download image")
+					return err
+				}
+				images[sd.ImageID] = image
+			}
+			svc.ImageID = image
+		}
+		upgradeService(svc, sd, changes[path])
+		svc.TemplateID = upgrade.template.ID
+		svc.TemplateVersion = upgrade.template.Version
+		if err := f.evaluateEndpointTemplates(ctx, svc); err != nil {
+			plog.WithError(err).WithField("service", path).Error("Could not evaluate endpoint templates for service")
+			return err
+		}
+		req.Modified = append(req.Modified, svc)
+	}
+	if err := f.MigrateServices(ctx, req); err != nil {
+		return err
+	}
+
+	for _, change := range upgrade.diff.Changes {
+		if change.Kind != servicetemplate.ChangeServiceAdded {
+			continue
+		}
+		logger := plog.WithField("service", change.Path)
+		if _, ok := upgrade.services[change.Path]; ok {
+			logger.Info("Service is already deployed")
+			continue
+		}
+		parent, ok := upgrade.services[change.Path[:strings.LastIndex(change.Path, "/")]]
+		if !ok {
+			logger.Info("Parent of service is not deployed; skipping")
+			continue
+		}
+		sd := upgrade.defs[change.Path]
+		if _, err := f.deployService(ctx, tenantID, parent.ID, parent.DeploymentID, parent.PoolID, false, *sd, upgrade.template, func(string) {}); err != nil {
+			logger.WithError(err).Error("Could not deploy service")
+			return err
+		}
+	}
+	return nil
+}
+
+// upgradeService applies the endpoint, command, config file and volume
+// changes of a template to a deployed service.  Config files that were edited
+// since deployment keep their content, but their original is updated so later
+// upgrades compare with the new template.
+func upgradeService(svc *service.Service, sd *servicedefinition.ServiceDefinition, changes []servicetemplate.Change) {
+	originalConfigs := make(map[string]servicedefinition.ConfigFile)
+	for name, conf := range svc.OriginalConfigs {
+		originalConfigs[name] = conf
+	}
+	configFiles := make(map[string]servicedefinition.ConfigFile)
+	for name, conf := range svc.ConfigFiles {
+		configFiles[name] = conf
+	}
+
+	for _, change := range changes {
+		switch change.Kind {
+		case servicetemplate.ChangeCommand:
+			svc.Startup = sd.Command
+		case servicetemplate.ChangeEndpoint:
+			var endpoints []service.ServiceEndpoint
+			for _, ep := range svc.Endpoints {
+				if ep.Name != change.Name {
+					endpoints = append(endpoints, ep)
+				}
+			}
+			for _, epd := range sd.Endpoints {
+				if epd.Name == change.Name {
+					endpoints = append(endpoints, service.BuildServiceEndpoint(epd))
+				}
+			}
+			svc.Endpoints = endpoints
+		case servicetemplate.ChangeConfigFile:
+			conf, ok := sd.ConfigFiles[change.Name]
+			if !change.Preserved {
+				delete(configFiles, change.Name)
+				if ok {
+					configFiles[change.Name] = conf
+				}
+			}
+			delete(originalConfigs, change.Name)
+			if ok {
+				originalConfigs[change.Name] = conf
+			}
+		case servicetemplate.ChangeVolume:
+			var volumes []servicedefinition.Volume
+			for _, v := range svc.Volumes {
+				if v.ContainerPath != change.Name {
+					volumes = append(volumes, v)
+				}
+			}
+			for _, v := range sd.Volumes {
+				if v.ContainerPath == change.Name {
+					volumes = append(volumes, v)
+				}
+			}
+			svc.Volumes = volumes
+		}
+	}
+	svc.OriginalConfigs = originalConfigs
+	svc.ConfigFiles = configFiles
+}
+
+// findTemplateService returns a template's definition of an application
+func findTemplateService(template *servicetemplate.ServiceTemplate, name string) *servicedefinition.ServiceDefinition {
+	for i := range template.Services {
+		if template.Services[i].Name == name {
+			return &template.Services[i]
+		}
+	}
+	return nil
+}
+
+// deployedDefinition rebuilds the definition of a deployed service and its
+// children as it was deployed, ignoring edits to its config files.
+func deployedDefinition(svc *service.Service, children map[string][]*service.Service) servicedefinition.ServiceDefinition {
+	sd := service.BuildServiceDefinition(*svc)
+	sd.ConfigFiles = svc.OriginalConfigs
+	for _, child := range children[svc.ID] {
+		sd.Services = append(sd.Services, deployedDefinition(child, children))
+	}
+	return sd
+}
+
+// configFileEdited returns true if a deployed config file no longer matches
+// the template's original
+func configFileEdited(svc *service.Service, filename string) bool {
+	original, inOriginal := svc.OriginalConfigs[filename]
+	current, inCurrent := svc.ConfigFiles[filename]
+	return inOriginal != inCurrent || original != current
+}
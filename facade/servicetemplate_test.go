@@ -25,6 +25,7 @@ import (
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/stretchr/testify/mock"
 	"github.com/zenoss/glog"
 	. "gopkg.in/check.v1"
 )
@@ -252,3 +253,60 @@ func (ft *FacadeIntegrationTest) verifyLogFilters(c *C, version string) {
 	c.Assert(logFilter.Filter, Equals, filter2)
 }
 
+func (ft *FacadeIntegrationTest) TestFacadeTemplateDiff(c *C) {
+	err := ft.Facade.AddResourcePool(ft.CTX, &pool.ResourcePool{ID: "upgrade-pool"})
+	c.Assert(err, IsNil)
+	ft.dfs.On("Create", mock.AnythingOfType("string")).Return(nil)
+
+	conf := servicedefinition.ConfigFile{Filename: "/etc/app.conf", Owner: "root:root", Permissions: "0644", Content: "v1"}
+	template := servicetemplate.ServiceTemplate{
+		Name:    "upgradeapp",
+		Version: "1.0",
+		Services: []servicedefinition.ServiceDefinition{
+			{
+				Name:        "upgradeapp",
+				Launch:      "manual",
+				Command:     "run v1",
+				ConfigFiles: map[string]servicedefinition.ConfigFile{conf.Filename: conf},
+				Services: []servicedefinition.ServiceDefinition{
+					{Name: "worker", Launch: "manual", Command: "work"},
+				},
+			},
+		},
+	}
+	fromID, err := ft.Facade.AddServiceTemplate(ft.CTX, template, false)
+	c.Assert(err, IsNil)
+	tenantIDs, err := ft.Facade.DeployTemplate(ft.CTX, "upgrade-pool", fromID, "upgrade-deployment")
+	c.Assert(err, IsNil)
+	c.Assert(tenantIDs, HasLen, 1)
+	tenantID := tenantIDs[0]
+
+	svc, err := ft.Facade.GetService(ft.CTX, tenantID)
+	c.Assert(err, IsNil)
+	c.Assert(svc.TemplateID, Equals, fromID)
+	c.Assert(svc.TemplateVersion, Equals, "1.0")
+
+	conf.Content = "v2"
+	template.Version = "2.0"
+	template.Services[0].Command = "run v2"
+	template.Services[0].ConfigFiles = map[string]servicedefinition.ConfigFile{conf.Filename: conf}
+	template.Services[0].Services = []servicedefinition.ServiceDefinition{
+		{Name: "scheduler", Launch: "manual", Command: "schedule"},
+	}
+	toID, err := ft.Facade.AddServiceTemplate(ft.CTX, template, false)
+	c.Assert(err, IsNil)
+
+	diff, err := ft.Facade.TemplateDiff(ft.CTX, tenantID, toID)
+	c.Assert(err, IsNil)
+	c.Assert(diff.FromTemplateID, Equals, fromID)
+	c.Assert(diff.FromVersion, Equals, "1.0")
+	c.Assert(diff.Version, Equals, "2.0")
+	c.Assert(diff.Changes, HasLen, 4)
+	c.Assert(diff.Changes[0].Kind, Equals, servicetemplate.ChangeCommand)
+	c.Assert(diff.Changes[1].Kind, Equals, servicetemplate.ChangeConfigFile)
+	c.Assert(diff.Changes[1].Preserved, Equals, false)
+	c.Assert(diff.Changes[2].Kind, Equals, servicetemplate.ChangeServiceAdded)
+	c.Assert(diff.Changes[2].Path, Equals, "upgradeapp/scheduler")
+	c.Assert(diff.Changes[3].Kind, Equals, servicetemplate.ChangeServiceRemoved)
+	c.Assert(diff.Changes[3].Path, Equals, "upgradeapp/worker")
+}
@@ -0,0 +1,117 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/control-center/serviced/audit"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/session"
+	"github.com/control-center/serviced/utils"
+)
+
+// ErrSessionHasID is returned when a session to be stored already has an id
+var ErrSessionHasID = errors.New("facade: session ids are assigned by the master")
+
+// sessionRecordingPath returns where the recording of a session is kept
+func (f *Facade) sessionRecordingPath(sessionID string) string {
+	return filepath.Join(f.sessionsPath, filepath.Base(sessionID)+".cast")
+}
+
+// AddSession stores a recorded shell session and its asciicast recording.
+// The session is given a new id and the tenant of its service; sessions that
+// already have an id are rejected, so that callers cannot overwrite the
+// records and recordings of other sessions.
+func (f *Facade) AddSession(ctx datastore.Context, sess *session.Session, recording []byte) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.AddSession"))
+	if sess.ID != "" {
+		return ErrSessionHasID
+	}
+	id, err := utils.NewUUID36()
+	if err != nil {
+		return err
+	}
+	sess.ID = id
+	alog := f.auditLogger.Message(ctx, "Recording Session").Action(audit.Add).Type(session.GetType()).ID(sess.ID).
+		WithField("user", sess.User).WithField("kind", string(sess.Kind)).WithField("serviceid", sess.ServiceID).
+		WithField("instanceid", strconv.Itoa(sess.InstanceID)).WithField("hostid", sess.HostID)
+	tenantID, err := f.GetTenantID(ctx, sess.ServiceID)
+	if err != nil {
+		return alog.Error(err)
+	}
+	sess.TenantID = tenantID
+	sess.Size = int64(len(recording))
+	if err := sess.ValidEntity(); err != nil {
+		return alog.Error(err)
+	}
+	if f.sessionsPath == "" {
+		return alog.Error(fmt.Errorf("no path is configured for session recordings"))
+	}
+	if err := os.MkdirAll(f.sessionsPath, 0700); err != nil {
+		return alog.Error(err)
+	}
+	path := f.sessionRecordingPath(sess.ID)
+	if err := ioutil.WriteFile(path, recording, 0600); err != nil {
+		return alog.Error(err)
+	}
+	if err := f.sessionStore.Put(ctx, sess); err != nil {
+		os.Remove(path)
+		return alog.Error(err)
+	}
+	alog.Succeeded()
+	return nil
+}
+
+// GetSessions returns the recorded shell sessions selected by the filter,
+// most recently started first
+func (f *Facade) GetSessions(ctx datastore.Context, filter session.Filter) ([]session.Session, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetSessions"))
+	return f.sessionStore.GetSessions(ctx, filter)
+}
+
+// GetSession returns a recorded shell session
+func (f *Facade) GetSession(ctx datastore.Context, sessionID string) (*session.Session, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetSession"))
+	return f.sessionStore.Get(ctx, sessionID)
+}
+
+// GetSessionRecording returns the asciicast recording of a shell session
+func (f *Facade) GetSessionRecording(ctx datastore.Context, sessionID string) ([]byte, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetSessionRecording"))
+	if _, err := f.sessionStore.Get(ctx, sessionID); err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(f.sessionRecordingPath(sessionID))
+}
+
+// SessionRecordingRequired returns whether the tenant of a service requires
+// shell sessions on its services to be recorded
+func (f *Facade) SessionRecordingRequired(ctx datastore.Context, serviceID string) (bool, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SessionRecordingRequired"))
+	tenantID, err := f.GetTenantID(ctx, serviceID)
+	if err != nil {
+		return false, err
+	}
+	tenant, err := f.serviceStore.Get(ctx, tenantID)
+	if err != nil {
+		return false, err
+	}
+	return tenant.RecordShellSessions, nil
+}
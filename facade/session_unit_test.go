@@ -0,0 +1,108 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/session"
+	"github.com/control-center/serviced/facade"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupSessionTenant(recordShellSessions bool) {
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, "svc").Return(&service.ServiceDetails{ID: "svc", ParentServiceID: "tenant"}, nil)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, "tenant").Return(&service.ServiceDetails{ID: "tenant"}, nil)
+	ft.serviceStore.On("Get", ft.ctx, "tenant").Return(&service.Service{ID: "tenant", RecordShellSessions: recordShellSessions}, nil)
+}
+
+func (ft *FacadeUnitTest) Test_AddSession(c *C) {
+	dir := c.MkDir()
+	ft.Facade.SetSessionsPath(dir)
+	ft.setupSessionTenant(false)
+	ft.sessionStore.On("Put", ft.ctx, mock.AnythingOfType("*session.Session")).Return(nil)
+
+	sess := &session.Session{
+		Kind:       session.KindAttach,
+		User:       "admin",
+		ServiceID:  "svc",
+		InstanceID: 0,
+		Started:    time.Now(),
+	}
+	recording := []byte("{\"version\":2}\n")
+	c.Assert(ft.Facade.AddSession(ft.ctx, sess, recording), IsNil)
+	c.Check(sess.ID, Not(Equals), "")
+	c.Check(sess.TenantID, Equals, "tenant")
+	c.Check(sess.Size, Equals, int64(len(recording)))
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, sess.ID+".cast"))
+	c.Assert(err, IsNil)
+	c.Check(data, DeepEquals, recording)
+
+	ft.sessionStore.On("Get", ft.ctx, sess.ID).Return(sess, nil)
+	data, err = ft.Facade.GetSessionRecording(ft.ctx, sess.ID)
+	c.Assert(err, IsNil)
+	c.Check(data, DeepEquals, recording)
+}
+
+func (ft *FacadeUnitTest) Test_AddSession_Invalid(c *C) {
+	ft.Facade.SetSessionsPath(c.MkDir())
+	ft.setupSessionTenant(false)
+
+	sess := &session.Session{Kind: "bogus", ServiceID: "svc", Started: time.Now()}
+	c.Assert(ft.Facade.AddSession(ft.ctx, sess, nil), NotNil)
+	ft.sessionStore.AssertNotCalled(c, "Put", ft.ctx, sess)
+}
+
+func (ft *FacadeUnitTest) Test_AddSession_WithID(c *C) {
+	dir := c.MkDir()
+	ft.Facade.SetSessionsPath(dir)
+	ft.setupSessionTenant(false)
+
+	sess := &session.Session{
+		ID:        "existing",
+		Kind:      session.KindAttach,
+		User:      "admin",
+		ServiceID: "svc",
+		Started:   time.Now(),
+	}
+	c.Assert(ft.Facade.AddSession(ft.ctx, sess, []byte("{}\n")), Equals, facade.ErrSessionHasID)
+	ft.sessionStore.AssertNotCalled(c, "Put", ft.ctx, sess)
+	_, err := os.Stat(filepath.Join(dir, "existing.cast"))
+	c.Check(os.IsNotExist(err), Equals, true)
+}
+
+func (ft *FacadeUnitTest) Test_GetSessions(c *C) {
+	filter := session.Filter{User: "admin"}
+	sessions := []session.Session{{ID: "s1"}}
+	ft.sessionStore.On("GetSessions", ft.ctx, filter).Return(sessions, nil)
+
+	result, err := ft.Facade.GetSessions(ft.ctx, filter)
+	c.Assert(err, IsNil)
+	c.Assert(result, DeepEquals, sessions)
+}
+
+func (ft *FacadeUnitTest) Test_SessionRecordingRequired(c *C) {
+	ft.setupSessionTenant(true)
+	required, err := ft.Facade.SessionRecordingRequired(ft.ctx, "svc")
+	c.Assert(err, IsNil)
+	c.Assert(required, Equals, true)
+}
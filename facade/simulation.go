@@ -0,0 +1,217 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/scheduler/simulation"
+)
+
+// simulationDeploymentID is the deployment id of the services of a template
+// deployed in a simulation
+const simulationDeploymentID = "simulation"
+
+// ErrPoolNotFound is returned when a pool does not exist
+var ErrPoolNotFound = errors.New("pool not found")
+
+// SimulatePool places the instances that the scheduler would start in a pool
+// after the changes are made and the template, if any, is deployed and
+// started.  Instances that are already running stay where they are.  Nothing
+// is scheduled; the running instances are only read.
+func (f *Facade) SimulatePool(ctx datastore.Context, poolID string, changes simulation.Changes, template *servicetemplate.ServiceTemplate) (*simulation.Plan, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SimulatePool"))
+
+	sim, svcs, running, err := f.loadSimulation(ctx, poolID, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]*service.Service, len(svcs))
+	for i := range svcs {
+		pending[i] = &svcs[i]
+	}
+	if template != nil {
+		for _, sd := range template.Services {
+			tsvcs, err := simulatedServices(sd, "", poolID)
+			if err != nil {
+				return nil, err
+			}
+			pending = append(pending, tsvcs...)
+		}
+	}
+	for _, svc := range pending {
+		if changes.HostPolicy != servicedefinition.DEFAULT {
+			svc.HostPolicy = changes.HostPolicy
+		}
+		if svc.DesiredState != int(service.SVCRun) {
+			continue
+		}
+		for instanceID := 0; instanceID < svc.Instances; instanceID++ {
+			if !running[svc.ID][instanceID] {
+				sim.Place(svc, instanceID)
+			}
+		}
+	}
+	return sim.Plan(), nil
+}
+
+// RebalancePool returns the instances of a pool that the pool leader would
+// move to other hosts, one at a time, so that each instance runs where the
+// strategy of its service would place it now.  Unless it is a dry run, the
+// pool leader is asked to rebalance the pool.
+func (f *Facade) RebalancePool(ctx datastore.Context, poolID string, dryRun bool) (*simulation.Plan, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.RebalancePool"))
+
+	sim, _, _, err := f.loadSimulation(ctx, poolID, simulation.Changes{})
+	if err != nil {
+		return nil, err
+	}
+	sim.Rebalance()
+	plan := sim.Plan()
+	if !dryRun {
+		if err := f.zzk.RequestRebalance(poolID); err != nil {
+			plog.WithField("poolid", poolID).WithError(err).Debug("Could not request a rebalance of the pool")
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// loadSimulation returns a simulation of the hosts of a pool and the
+// instances running on them after the changes are made, along with the
+// services of the pool and the instances of each service that are running.
+func (f *Facade) loadSimulation(ctx datastore.Context, poolID string, changes simulation.Changes) (*simulation.Simulation, []service.Service, map[string]map[int]bool, error) {
+	if p, err := f.GetResourcePool(ctx, poolID); err != nil {
+		return nil, nil, nil, err
+	} else if p == nil {
+		return nil, nil, nil, ErrPoolNotFound
+	}
+
+	hosts, err := f.FindHostsInPool(ctx, poolID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	removed := make(map[string]bool)
+	for _, hostID := range changes.RemoveHosts {
+		removed[hostID] = true
+	}
+	kept := []host.Host{}
+	for _, h := range hosts {
+		if removed[h.ID] {
+			delete(removed, h.ID)
+			continue
+		}
+		kept = append(kept, h)
+	}
+	for hostID := range removed {
+		return nil, nil, nil, fmt.Errorf("host %s is not in pool %s", hostID, poolID)
+	}
+	simHosts := append([]host.Host{}, kept...)
+	for i, h := range changes.AddHosts {
+		if h.ID == "" {
+			h.ID = fmt.Sprintf("simulated-%d", i+1)
+		}
+		h.PoolID = poolID
+		simHosts = append(simHosts, h)
+	}
+	sim := simulation.New(poolID, simHosts)
+
+	svcs, err := f.serviceStore.GetServicesByPool(ctx, poolID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sort.Sort(servicesByName(svcs))
+	byID := make(map[string]*service.Service)
+	for i := range svcs {
+		byID[svcs[i].ID] = &svcs[i]
+	}
+	for _, change := range changes.Services {
+		svc, ok := byID[change.ServiceID]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("service %s is not in pool %s", change.ServiceID, poolID)
+		}
+		change.Apply(svc)
+	}
+
+	// instances that keep running on their hosts
+	running := make(map[string]map[int]bool)
+	for _, h := range kept {
+		states, err := f.zzk.GetHostStates(ctx, poolID, h.ID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for _, state := range states {
+			svc, ok := byID[state.ServiceID]
+			if !ok || state.DesiredState == service.SVCStop {
+				continue
+			}
+			// surplus instances would be stopped
+			if svc.DesiredState != int(service.SVCRun) || state.InstanceID >= svc.Instances {
+				continue
+			}
+			if err := sim.AddInstance(h.ID, svc, state.InstanceID); err != nil {
+				return nil, nil, nil, err
+			}
+			if running[svc.ID] == nil {
+				running[svc.ID] = make(map[int]bool)
+			}
+			running[svc.ID][state.InstanceID] = true
+		}
+	}
+	return sim, svcs, running, nil
+}
+
+// simulatedServices builds the services of a service definition and its
+// children, as if they were deployed and started.  Services that are
+// launched manually are left stopped.
+func simulatedServices(sd servicedefinition.ServiceDefinition, parentID, poolID string) ([]*service.Service, error) {
+	desiredState := service.SVCRun
+	if strings.ToLower(strings.TrimSpace(sd.Launch)) == commons.MANUAL {
+		desiredState = service.SVCStop
+	}
+	svc, err := service.BuildService(sd, parentID, poolID, int(desiredState), simulationDeploymentID)
+	if err != nil {
+		return nil, err
+	}
+	svcs := []*service.Service{svc}
+	for _, child := range sd.Services {
+		children, err := simulatedServices(child, svc.ID, poolID)
+		if err != nil {
+			return nil, err
+		}
+		svcs = append(svcs, children...)
+	}
+	return svcs, nil
+}
+
+type servicesByName []service.Service
+
+func (l servicesByName) Len() int      { return len(l) }
+func (l servicesByName) Swap(i, j int) { l[i], l[j] = l[j], l[i] }
+func (l servicesByName) Less(i, j int) bool {
+	if l[i].Name != l[j].Name {
+		return l[i].Name < l[j].Name
+	}
+	return l[i].ID < l[j].ID
+}
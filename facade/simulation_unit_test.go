@@ -0,0 +1,195 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/facade"
+	"github.com/control-center/serviced/scheduler/simulation"
+	"github.com/control-center/serviced/utils"
+	zkservice "github.com/control-center/serviced/zzk/service"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupSimulation(hosts []host.Host, svcs []service.Service) {
+	ft.poolStore.On("Get", ft.ctx, pool.Key("default"), mock.AnythingOfType("*pool.ResourcePool")).Return(nil).Run(
+		func(args mock.Arguments) {
+			args.Get(2).(*pool.ResourcePool).ID = "default"
+		})
+	ft.hostStore.On("FindHostsWithPoolID", ft.ctx, "default").Return(hosts, nil)
+	ft.serviceStore.On("GetServicesByPool", ft.ctx, "default").Return(svcs, nil)
+}
+
+func (ft *FacadeUnitTest) simulationFixture() ([]host.Host, []service.Service) {
+	hosts := []host.Host{
+		{ID: "host1", Name: "one", PoolID: "default", Cores: 4, Memory: 8 << 30},
+		{ID: "host2", Name: "two", PoolID: "default", Cores: 4, Memory: 8 << 30},
+	}
+	svcs := []service.Service{
+		{
+			ID:            "svc1",
+			Name:          "web",
+			PoolID:        "default",
+			Instances:     2,
+			DesiredState:  int(service.SVCRun),
+			CPUCommitment: 1,
+			RAMCommitment: utils.NewEngNotation(1 << 30),
+		},
+	}
+	return hosts, svcs
+}
+
+func (ft *FacadeUnitTest) TestSimulatePool_PoolNotFound(c *C) {
+	ft.poolStore.On("Get", ft.ctx, pool.Key("nope"), mock.AnythingOfType("*pool.ResourcePool")).Return(datastore.ErrNoSuchEntity{})
+
+	plan, err := ft.Facade.SimulatePool(ft.ctx, "nope", simulation.Changes{}, nil)
+	c.Assert(err, Equals, facade.ErrPoolNotFound)
+	c.Assert(plan, IsNil)
+}
+
+func (ft *FacadeUnitTest) TestSimulatePool_PlacesMissingInstances(c *C) {
+	hosts, svcs := ft.simulationFixture()
+	ft.setupSimulation(hosts, svcs)
+	ft.zzk.On("GetHostStates", ft.ctx, "default", "host1").Return([]zkservice.State{
+		{
+			HostID:     "host1",
+			ServiceID:  "svc1",
+			InstanceID: 0,
+			HostState:  zkservice.HostState{DesiredState: service.SVCRun},
+		},
+	}, nil)
+	ft.zzk.On("GetHostStates", ft.ctx, "default", "host2").Return([]zkservice.State{}, nil)
+
+	plan, err := ft.Facade.SimulatePool(ft.ctx, "default", simulation.Changes{}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(plan.Placements, DeepEquals, []simulation.Placement{
+		{ServiceID: "svc1", ServiceName: "web", InstanceID: 1, HostID: "host2"},
+	})
+	c.Assert(plan.Unplaced, HasLen, 0)
+	c.Assert(plan.Hosts, HasLen, 2)
+	c.Assert(plan.Hosts[0].Instances, Equals, 1)
+	c.Assert(plan.Hosts[1].Instances, Equals, 1)
+}
+
+func (ft *FacadeUnitTest) TestSimulatePool_Changes(c *C) {
+	hosts, svcs := ft.simulationFixture()
+	ft.setupSimulation(hosts, svcs)
+	ft.zzk.On("GetHostStates", ft.ctx, "default", "host2").Return([]zkservice.State{}, nil)
+
+	instances := 3
+	changes := simulation.Changes{
+		RemoveHosts: []string{"host1"},
+		AddHosts:    []host.Host{{Name: "extra", Cores: 4, Memory: 8 << 30}},
+		Services:    []simulation.ServiceChange{{ServiceID: "svc1", Instances: &instances}},
+		HostPolicy:  servicedefinition.RequireSeparate,
+	}
+	plan, err := ft.Facade.SimulatePool(ft.ctx, "default", changes, nil)
+	c.Assert(err, IsNil)
+	c.Assert(plan.Placements, HasLen, 2)
+	c.Assert(plan.Unplaced, HasLen, 1)
+	c.Assert(plan.Unplaced[0].InstanceID, Equals, 2)
+	c.Assert(plan.Hosts, HasLen, 2)
+	c.Assert(plan.Hosts[0].HostID, Equals, "host2")
+	c.Assert(plan.Hosts[1].HostID, Equals, "simulated-1")
+	ft.zzk.AssertNotCalled(c, "GetHostStates", ft.ctx, "default", "host1")
+}
+
+func (ft *FacadeUnitTest) TestSimulatePool_UnknownService(c *C) {
+	hosts, svcs := ft.simulationFixture()
+	ft.setupSimulation(hosts, svcs)
+
+	changes := simulation.Changes{Services: []simulation.ServiceChange{{ServiceID: "missing"}}}
+	plan, err := ft.Facade.SimulatePool(ft.ctx, "default", changes, nil)
+	c.Assert(err, NotNil)
+	c.Assert(plan, IsNil)
+}
+
+func (ft *FacadeUnitTest) TestSimulatePool_Template(c *C) {
+	hosts, _ := ft.simulationFixture()
+	ft.setupSimulation(hosts, []service.Service{})
+	ft.zzk.On("GetHostStates", ft.ctx, "default", mock.AnythingOfType("string")).Return([]zkservice.State{}, nil)
+
+	template := &servicetemplate.ServiceTemplate{
+		Services: []servicedefinition.ServiceDefinition{
+			{
+				Name:      "app",
+				Launch:    "auto",
+				Instances: domain.MinMax{Default: 1},
+				Services: []servicedefinition.ServiceDefinition{
+					{Name: "worker", Launch: "auto", Instances: domain.MinMax{Default: 2}},
+					{Name: "tool", Launch: "manual", Instances: domain.MinMax{Default: 1}},
+				},
+			},
+		},
+	}
+	plan, err := ft.Facade.SimulatePool(ft.ctx, "default", simulation.Changes{}, template)
+	c.Assert(err, IsNil)
+	c.Assert(plan.Placements, HasLen, 3)
+	c.Assert(plan.Placements[0].ServiceName, Equals, "app")
+	c.Assert(plan.Placements[1].ServiceName, Equals, "worker")
+	c.Assert(plan.Placements[2].ServiceName, Equals, "worker")
+}
+
+func (ft *FacadeUnitTest) TestRebalancePool_DryRun(c *C) {
+	hosts, svcs := ft.simulationFixture()
+	ft.setupSimulation(hosts, svcs)
+	ft.zzk.On("GetHostStates", ft.ctx, "default", "host1").Return([]zkservice.State{
+		{
+			HostID:     "host1",
+			ServiceID:  "svc1",
+			InstanceID: 0,
+			HostState:  zkservice.HostState{DesiredState: service.SVCRun},
+		}, {
+			HostID:     "host1",
+			ServiceID:  "svc1",
+			InstanceID: 1,
+			HostState:  zkservice.HostState{DesiredState: service.SVCRun},
+		},
+	}, nil)
+	ft.zzk.On("GetHostStates", ft.ctx, "default", "host2").Return([]zkservice.State{}, nil)
+
+	plan, err := ft.Facade.RebalancePool(ft.ctx, "default", true)
+	c.Assert(err, IsNil)
+	c.Assert(plan.Moves, DeepEquals, []simulation.Move{
+		{ServiceID: "svc1", ServiceName: "web", InstanceID: 0, FromHostID: "host1", ToHostID: "host2"},
+	})
+	c.Assert(plan.Placements, HasLen, 0)
+	ft.zzk.AssertNotCalled(c, "RequestRebalance", "default")
+}
+
+func (ft *FacadeUnitTest) TestRebalancePool_Request(c *C) {
+	hosts, svcs := ft.simulationFixture()
+	ft.setupSimulation(hosts, svcs)
+	ft.zzk.On("GetHostStates", ft.ctx, "default", mock.AnythingOfType("string")).Return([]zkservice.State{}, nil)
+	ft.zzk.On("RequestRebalance", "default").Return(ErrTestZK).Once()
+
+	plan, err := ft.Facade.RebalancePool(ft.ctx, "default", false)
+	c.Assert(err, Equals, ErrTestZK)
+	c.Assert(plan, IsNil)
+
+	ft.zzk.On("RequestRebalance", "default").Return(nil)
+	plan, err = ft.Facade.RebalancePool(ft.ctx, "default", false)
+	c.Assert(err, IsNil)
+	c.Assert(plan.Moves, HasLen, 0)
+	ft.zzk.AssertCalled(c, "RequestRebalance", "default")
+}
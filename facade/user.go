@@ -174,3 +174,103 @@ func (f *Facade) CreateSystemUser(ctx datastore.Context) error {
 	INSTANCE_PASSWORD = password
 	return f.UpdateUser(ctx, user)
 }
+
+// GetUsers returns all of the stored users
+func (f *Facade) GetUsers(ctx datastore.Context) ([]userdomain.User, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetUsers"))
+	return f.userStore.GetUsers(ctx)
+}
+
+// SetUserRoles replaces the roles granted to a user without changing its
+// password
+func (f *Facade) SetUserRoles(ctx datastore.Context, userName string, roles userdomain.RoleBindings) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.SetUserRoles"))
+	logger := plog.WithFields(log.Fields{
+		"userName": userName,
+		"roles":    roles,
+	})
+	logger.Debug("Started Facade.SetUserRoles")
+	defer logger.Debug("Finished Facade.SetUserRoles")
+
+	if userName == SYSTEM_USER_NAME {
+		return errors.New("cannot change the roles of the system user")
+	}
+
+	var stored userdomain.User
+	if err := f.userStore.Get(ctx, userdomain.Key(userName), &stored); err != nil {
+		return err
+	}
+	stored.Roles = roles
+	return f.userStore.Put(ctx, userdomain.Key(userName), &stored)
+}
+
+// GetUserRoles returns the roles granted to a user.  The system user is
+// always a cluster administrator.
+func (f *Facade) GetUserRoles(ctx datastore.Context, userName string) (userdomain.RoleBindings, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetUserRoles"))
+	if userName == SYSTEM_USER_NAME {
+		return userdomain.ClusterAdmin, nil
+	}
+	u, err := f.GetUser(ctx, userName)
+	if err != nil {
+		return nil, err
+	}
+	return u.Roles, nil
+}
+
+// GetAccessScope returns the tenant and pool affected by an access request
+func (f *Facade) GetAccessScope(ctx datastore.Context, req userdomain.AccessRequest) (userdomain.Scope, error) {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.GetAccessScope"))
+	scope := userdomain.Scope{PoolID: req.PoolID}
+	if req.ServiceID != "" {
+		svc, err := f.GetServiceDetails(ctx, req.ServiceID)
+		if err != nil {
+			return scope, err
+		}
+		tenantID, err := f.GetTenantID(ctx, req.ServiceID)
+		if err != nil {
+			return scope, err
+		}
+		scope.TenantID = tenantID
+		scope.PoolID = svc.PoolID
+	} else if req.HostID != "" {
+		h, err := f.GetHost(ctx, req.HostID)
+		if err != nil {
+			return scope, err
+		} else if h == nil {
+			return scope, fmt.Errorf("host %s not found", req.HostID)
+		}
+		scope.PoolID = h.PoolID
+	}
+	return scope, nil
+}
+
+// CheckAccess returns userdomain.ErrAccessDenied if the user does not hold a
+// role that permits the requested action
+func (f *Facade) CheckAccess(ctx datastore.Context, req userdomain.AccessRequest) error {
+	defer ctx.Metrics().Stop(ctx.Metrics().Start("Facade.CheckAccess"))
+	logger := plog.WithFields(log.Fields{
+		"user":      req.User,
+		"action":    req.Action,
+		"serviceid": req.ServiceID,
+		"hostid":    req.HostID,
+		"poolid":    req.PoolID,
+	})
+
+	roles, err := f.GetUserRoles(ctx, req.User)
+	if datastore.IsErrNoSuchEntity(err) {
+		logger.Debug("User not found")
+		return userdomain.ErrAccessDenied
+	} else if err != nil {
+		return err
+	}
+	scope, err := f.GetAccessScope(ctx, req)
+	if err != nil {
+		return err
+	}
+	if err := roles.Authorize(req.Action, scope); err != nil {
+		logger.Warn("User is not authorized to perform action")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package facade_test
+
+import (
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/user"
+	"github.com/control-center/serviced/facade"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (ft *FacadeUnitTest) setupUserRoles(name string, roles user.RoleBindings) {
+	ft.userStore.On("Get", ft.ctx, user.Key(name), mock.AnythingOfType("*user.User")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			u := args.Get(2).(*user.User)
+			u.Name = name
+			u.Roles = roles
+		})
+}
+
+func (ft *FacadeUnitTest) Test_CheckAccess_SystemUser(c *C) {
+	req := user.AccessRequest{User: facade.SYSTEM_USER_NAME, Action: user.ActionRestore}
+	c.Assert(ft.Facade.CheckAccess(ft.ctx, req), IsNil)
+}
+
+func (ft *FacadeUnitTest) Test_CheckAccess_UnknownUser(c *C) {
+	ft.userStore.On("Get", ft.ctx, user.Key("nobody"), mock.AnythingOfType("*user.User")).
+		Return(datastore.ErrNoSuchEntity{Key: user.Key("nobody")})
+
+	req := user.AccessRequest{User: "nobody", Action: user.ActionView}
+	c.Assert(ft.Facade.CheckAccess(ft.ctx, req), Equals, user.ErrAccessDenied)
+}
+
+func (ft *FacadeUnitTest) Test_CheckAccess_TenantOperator(c *C) {
+	ft.setupUserRoles("oncall", user.RoleBindings{{Role: user.RoleOperator, TenantID: "accesstenant"}})
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, "accesstenant").
+		Return(&service.ServiceDetails{ID: "accesstenant", PoolID: "default"}, nil)
+	ft.serviceStore.On("GetServiceDetails", ft.ctx, "accesschild").
+		Return(&service.ServiceDetails{ID: "accesschild", ParentServiceID: "accesstenant", PoolID: "default"}, nil)
+	ft.hostStore.On("Get", ft.ctx, host.HostKey("accesshost"), mock.AnythingOfType("*host.Host")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			h := args.Get(2).(*host.Host)
+			h.ID = "accesshost"
+			h.PoolID = "default"
+		})
+
+	req := user.AccessRequest{User: "oncall", Action: user.ActionControlService, ServiceID: "accesschild"}
+	c.Assert(ft.Facade.CheckAccess(ft.ctx, req), IsNil)
+
+	req = user.AccessRequest{User: "oncall", Action: user.ActionEditService, ServiceID: "accesschild"}
+	c.Assert(ft.Facade.CheckAccess(ft.ctx, req), Equals, user.ErrAccessDenied)
+
+	req = user.AccessRequest{User: "oncall", Action: user.ActionEditHost, HostID: "accesshost"}
+	c.Assert(ft.Facade.CheckAccess(ft.ctx, req), Equals, user.ErrAccessDenied)
+
+	req = user.AccessRequest{User: "oncall", Action: user.ActionRestore}
+	c.Assert(ft.Facade.CheckAccess(ft.ctx, req), Equals, user.ErrAccessDenied)
+}
+
+func (ft *FacadeUnitTest) Test_SetUserRoles(c *C) {
+	ft.setupUserRoles("grantee", nil)
+	ft.userStore.On("Put", ft.ctx, user.Key("grantee"), mock.AnythingOfType("*user.User")).Return(nil)
+
+	roles := user.RoleBindings{{Role: user.RoleViewer, PoolID: "default"}}
+	c.Assert(ft.Facade.SetUserRoles(ft.ctx, "grantee", roles), IsNil)
+	put := ft.userStore.Calls[len(ft.userStore.Calls)-1].Arguments.Get(2).(*user.User)
+	c.Assert(put.Roles, DeepEquals, roles)
+
+	c.Assert(ft.Facade.SetUserRoles(ft.ctx, facade.SYSTEM_USER_NAME, roles), NotNil)
+}
@@ -448,6 +448,15 @@ func (z *zkf) RemoveResourcePool(poolID string) error {
 	return zks.RemoveResourcePool(conn, poolID)
 }
 
+// RequestRebalance asks the leader of a pool to rebalance its instances
+func (z *zkf) RequestRebalance(poolID string) error {
+	conn, err := zzk.GetLocalConnection("/")
+	if err != nil {
+		return err
+	}
+	return zks.RequestRebalance(conn, poolID)
+}
+
 func (z *zkf) GetVirtualIPHostID(poolID, ip string) (string, error) {
 	conn, err := zzk.GetLocalConnection("/")
 	if err != nil {
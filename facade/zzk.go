@@ -40,6 +40,7 @@ type ZZK interface {
 	IsHostActive(poolID string, hostId string) (bool, error)
 	UpdateResourcePool(_pool *pool.ResourcePool) error
 	RemoveResourcePool(poolID string) error
+	RequestRebalance(poolID string) error
 	GetRegistryImage(id string) (*registry.Image, error)
 	SetRegistryImage(rImage *registry.Image) error
 	DeleteRegistryImage(id string) error
@@ -14,8 +14,10 @@
 package health
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"net"
 	"os/exec"
 	"syscall"
 	"time"
@@ -105,9 +107,13 @@ type HealthStatus struct {
 	KillFlag  bool
 }
 
-// HealthCheck is the health check object.
+// HealthCheck is the health check object.  The check runs its Script with
+// the shell, unless it is an HTTP or TCP check, which the container controller
+// performs itself.
 type HealthCheck struct {
 	Script    string
+	HTTP      *HTTPCheck
+	TCP       *TCPCheck
 	Timeout   time.Duration
 	Interval  time.Duration
 	Tolerance int
@@ -123,6 +129,8 @@ type HealthCheck struct {
 func (hc HealthCheck) MarshalJSON() ([]byte, error) {
 	jhc := struct {
 		Script         string
+		HTTP           *HTTPCheck `json:",omitempty"`
+		TCP            *TCPCheck  `json:",omitempty"`
 		Timeout        float64
 		Interval       float64
 		Tolerance      int
@@ -130,6 +138,8 @@ func (hc HealthCheck) MarshalJSON() ([]byte, error) {
 		KillCountLimit int   `json:",omitempty"`
 	}{
 		Script:         hc.Script,
+		HTTP:           hc.HTTP,
+		TCP:            hc.TCP,
 		Timeout:        hc.Timeout.Seconds(),
 		Interval:       hc.Interval.Seconds(),
 		Tolerance:      hc.Tolerance,
@@ -143,6 +153,8 @@ func (hc HealthCheck) MarshalJSON() ([]byte, error) {
 func (hc *HealthCheck) UnmarshalJSON(data []byte) error {
 	jhc := struct {
 		Script         string
+		HTTP           *HTTPCheck `json:",omitempty"`
+		TCP            *TCPCheck  `json:",omitempty"`
 		Timeout        float64
 		Interval       float64
 		Tolerance      int
@@ -154,6 +166,8 @@ func (hc *HealthCheck) UnmarshalJSON(data []byte) error {
 	}
 	*hc = HealthCheck{
 		Script:         jhc.Script,
+		HTTP:           jhc.HTTP,
+		TCP:            jhc.TCP,
 		Timeout:        time.Duration(jhc.Timeout) * time.Second,
 		Interval:       time.Duration(jhc.Interval) * time.Second,
 		Tolerance:      jhc.Tolerance,
@@ -199,8 +213,24 @@ func (hc *HealthCheck) Unknown() HealthStatus {
 	}
 }
 
+// String describes what the health check runs
+func (hc *HealthCheck) String() string {
+	switch {
+	case hc.HTTP != nil:
+		method := hc.HTTP.Method
+		if method == "" {
+			method = "GET"
+		}
+		return method + " " + hc.HTTP.URL
+	case hc.TCP != nil:
+		return "tcp " + hc.TCP.Address
+	default:
+		return hc.Script
+	}
+}
+
 // Run returns the health status as a result of running the health check
-// script.
+// script, or of making the request of an HTTP or TCP check.
 func (hc *HealthCheck) Run(key HealthStatusKey) (stat HealthStatus) {
 	logger := plog.WithFields(log.Fields{
 		"service":     key.ServiceID,
@@ -208,6 +238,11 @@ func (hc *HealthCheck) Run(key HealthStatusKey) (stat HealthStatus) {
 		"healthcheck": key.HealthCheckName,
 	})
 	stat.StartedAt = time.Now()
+	if hc.HTTP != nil || hc.TCP != nil {
+		stat.Status = hc.runNative(logger)
+		stat.Duration = time.Since(stat.StartedAt)
+		return
+	}
 	cmd := exec.Command("sh", "-c", hc.Script)
 	cmd.Start()
 	timer := time.NewTimer(hc.GetTimeout())
@@ -269,6 +304,40 @@ func (hc *HealthCheck) Run(key HealthStatusKey) (stat HealthStatus) {
 	return
 }
 
+// runNative performs an HTTP or TCP check within the timeout.  A failure counts
+// toward the kill count like a script that exits with an error; a check that
+// times out does not.
+func (hc *HealthCheck) runNative(logger *log.Entry) Status {
+	ctx, cancel := context.WithTimeout(context.Background(), hc.GetTimeout())
+	defer cancel()
+
+	var err error
+	if hc.HTTP != nil {
+		err = hc.HTTP.Check(ctx)
+	} else {
+		err = hc.TCP.Check(ctx)
+	}
+
+	if err == nil {
+		if hc.KillCounter > 0 {
+			logger.Infof("Resetting KillCounter. KillCounter was %d", hc.KillCounter)
+			hc.KillCounter = 0
+		}
+		return OK
+	} else if ctx.Err() == context.DeadlineExceeded {
+		return Timeout
+	} else if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		return Timeout
+	}
+
+	logger.WithError(err).Debug("Health check failed")
+	if hc.KillCountLimit > 0 {
+		hc.KillCounter++
+		logger.Debugf("KillCounter is now %d", hc.KillCounter)
+	}
+	return Failed
+}
+
 // Ping performs the health check on the specified interval.
 func (hc *HealthCheck) Ping(cancel <-chan struct{}, key HealthStatusKey, report func(HealthStatus)) {
 	timer := time.NewTimer(0)
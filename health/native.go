@@ -0,0 +1,164 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"regexp"
+)
+
+// maxBodyBytes limits how much of a response body is matched against the
+// body pattern of an http check
+const maxBodyBytes = 1 << 20
+
+// TLSOptions describes how a native health check connects with TLS
+type TLSOptions struct {
+	InsecureSkipVerify bool   `json:",omitempty"` // accept any certificate, e.g. a self-signed one
+	ServerName         string `json:",omitempty"` // name to verify the certificate against, if not the host of the address
+	CAFile             string `json:",omitempty"` // PEM bundle of the authorities to trust instead of the system roots
+}
+
+// config returns the tls configuration of a connection to a host
+func (opts *TLSOptions) config(host string) (*tls.Config, error) {
+	config := &tls.Config{ServerName: host}
+	if opts == nil {
+		return config, nil
+	}
+	config.InsecureSkipVerify = opts.InsecureSkipVerify
+	if opts.ServerName != "" {
+		config.ServerName = opts.ServerName
+	}
+	if opts.CAFile != "" {
+		pem, err := ioutil.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", opts.CAFile)
+		}
+	}
+	return config, nil
+}
+
+// HTTPCheck is a health check that makes an http request from the container
+// controller instead of running a script
+type HTTPCheck struct {
+	Method         string      `json:",omitempty"` // defaults to GET
+	URL            string      // templated like a script, e.g. http://localhost:8080/ping
+	ExpectedStatus []int       `json:",omitempty"` // status codes that pass; defaults to any 2xx code
+	BodyRegex      string      `json:",omitempty"` // pattern the response body must match
+	TLS            *TLSOptions `json:",omitempty"` // options for https urls
+}
+
+// Check makes the request and returns an error if the response does not pass
+func (check *HTTPCheck) Check(ctx context.Context) error {
+	method := check.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequest(method, check.URL, nil)
+	if err != nil {
+		return err
+	}
+	config, err := check.TLS.config(req.URL.Hostname())
+	if err != nil {
+		return err
+	}
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: config, DisableKeepAlives: true},
+		// redirects are reported as their status
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if !check.passes(resp.StatusCode) {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if check.BodyRegex != "" {
+		pattern, err := regexp.Compile(check.BodyRegex)
+		if err != nil {
+			return err
+		}
+		body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+		if err != nil {
+			return err
+		}
+		if !pattern.Match(body) {
+			return errors.New("response body does not match")
+		}
+	}
+	return nil
+}
+
+// passes returns true if a status code is expected
+func (check *HTTPCheck) passes(code int) bool {
+	if len(check.ExpectedStatus) == 0 {
+		return code >= 200 && code < 300
+	}
+	for _, expected := range check.ExpectedStatus {
+		if code == expected {
+			return true
+		}
+	}
+	return false
+}
+
+// TCPCheck is a health check that connects to an address from the container
+// controller instead of running a script
+type TCPCheck struct {
+	Address string      // templated like a script, e.g. localhost:5432
+	TLS     *TLSOptions `json:",omitempty"` // completes a tls handshake after connecting, if set
+}
+
+// Check connects to the address and returns an error if it cannot
+func (check *TCPCheck) Check(ctx context.Context) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", check.Address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if check.TLS != nil {
+		host, _, err := net.SplitHostPort(check.Address)
+		if err != nil {
+			return err
+		}
+		config, err := check.TLS.config(host)
+		if err != nil {
+			return err
+		}
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+		tlsConn := tls.Client(conn, config)
+		if err := tlsConn.Handshake(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
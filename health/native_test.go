@@ -0,0 +1,146 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package health_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/control-center/serviced/health"
+	. "gopkg.in/check.v1"
+)
+
+func (s *HealthCheckTestSuite) TestJSON_HTTPCheck(c *C) {
+	check := HealthCheck{
+		HTTP: &HTTPCheck{
+			URL:            "https://localhost:8443/ping",
+			ExpectedStatus: []int{200, 204},
+			BodyRegex:      "ok",
+			TLS:            &TLSOptions{InsecureSkipVerify: true},
+		},
+		Timeout:  5 * time.Second,
+		Interval: 10 * time.Second,
+	}
+	data, err := json.Marshal(check)
+	c.Assert(err, IsNil)
+
+	var actual HealthCheck
+	c.Assert(json.Unmarshal(data, &actual), IsNil)
+	c.Assert(actual, DeepEquals, check)
+	c.Assert(actual.String(), Equals, "GET https://localhost:8443/ping")
+}
+
+func (s *HealthCheckTestSuite) TestRun_HTTP(c *C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			fmt.Fprint(w, `{"status": "ok"}`)
+		case "/starting":
+			fmt.Fprint(w, `{"status": "starting"}`)
+		case "/slow":
+			time.Sleep(time.Second)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	for _, tc := range []struct {
+		check    HTTPCheck
+		expected Status
+	}{
+		{HTTPCheck{URL: server.URL + "/ok"}, OK},
+		{HTTPCheck{URL: server.URL + "/ok", BodyRegex: `"status":\s*"ok"`}, OK},
+		{HTTPCheck{URL: server.URL + "/starting", BodyRegex: `"status":\s*"ok"`}, Failed},
+		{HTTPCheck{URL: server.URL + "/missing"}, Failed},
+		{HTTPCheck{URL: server.URL + "/missing", ExpectedStatus: []int{404}}, OK},
+		{HTTPCheck{Method: "HEAD", URL: server.URL + "/ok"}, OK},
+		{HTTPCheck{URL: server.URL + "/slow"}, Timeout},
+	} {
+		httpCheck := tc.check
+		check := HealthCheck{HTTP: &httpCheck, Timeout: 250 * time.Millisecond, Interval: time.Second}
+		stat := check.Run(hcKey)
+		c.Check(stat.Status, Equals, tc.expected, Commentf("%s", check.String()))
+		c.Check(stat.Duration > 0, Equals, true)
+	}
+}
+
+func (s *HealthCheckTestSuite) TestRun_HTTPS(c *C) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// the test server's certificate is self-signed
+	check := HealthCheck{HTTP: &HTTPCheck{URL: server.URL}, Timeout: time.Second}
+	c.Check(check.Run(hcKey).Status, Equals, Status(Failed))
+
+	check.HTTP.TLS = &TLSOptions{InsecureSkipVerify: true}
+	c.Check(check.Run(hcKey).Status, Equals, OK)
+}
+
+func (s *HealthCheckTestSuite) TestRun_TCP(c *C) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	address := listener.Addr().String()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	check := HealthCheck{TCP: &TCPCheck{Address: address}, Timeout: time.Second}
+	c.Check(check.Run(hcKey).Status, Equals, OK)
+	c.Check(check.String(), Equals, "tcp "+address)
+
+	// nothing listens once the listener is closed
+	listener.Close()
+	c.Check(check.Run(hcKey).Status, Equals, Status(Failed))
+}
+
+func (s *HealthCheckTestSuite) TestKillNative(c *C) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	address := listener.Addr().String()
+	listener.Close()
+
+	hc := HealthCheck{
+		TCP:            &TCPCheck{Address: address},
+		Timeout:        time.Second,
+		Interval:       100 * time.Millisecond,
+		KillCountLimit: 2,
+	}
+	cancel := make(chan struct{})
+	interval := 0
+	hc.Ping(cancel, hcKey, func(status HealthStatus) {
+		interval++
+		switch interval {
+		case 1:
+			c.Check(hc.KillCounter, Equals, 1)
+			c.Check(status.KillFlag, Equals, false)
+		case 2:
+			c.Check(hc.KillCounter, Equals, 2)
+			c.Check(status.KillFlag, Equals, true)
+			close(cancel)
+		}
+	})
+}
@@ -15,6 +15,8 @@ package health
 
 import (
 	"fmt"
+	"regexp"
+
 	"github.com/control-center/serviced/validation"
 )
 
@@ -24,8 +26,58 @@ func (hc HealthCheck) ValidEntity() error {
 		violations.Add(fmt.Errorf("the KillCountLimit must be set if KillExitCodes are specified"))
 	}
 
+	kinds := 0
+	if hc.Script != "" {
+		kinds++
+	}
+	if hc.HTTP != nil {
+		kinds++
+		violations.Add(hc.HTTP.validate())
+	}
+	if hc.TCP != nil {
+		kinds++
+		violations.Add(hc.TCP.validate())
+	}
+	if kinds > 1 {
+		violations.Add(fmt.Errorf("a health check must have only one of a Script, an HTTP check or a TCP check"))
+	}
+	if (hc.HTTP != nil || hc.TCP != nil) && len(hc.KillExitCodes) > 0 {
+		violations.Add(fmt.Errorf("KillExitCodes only apply to health check scripts"))
+	}
+
+	if violations.HasError() {
+		return violations
+	}
+	return nil
+}
+
+func (check *HTTPCheck) validate() error {
+	violations := validation.NewValidationError()
+	if check.Method != "" {
+		violations.Add(validation.StringIn(check.Method, "GET", "HEAD", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"))
+	}
+	// the url may be a template; it is checked once it has been evaluated
+	violations.Add(validation.NotEmpty("HTTP.URL", check.URL))
+	for _, code := range check.ExpectedStatus {
+		if code < 100 || code > 599 {
+			violations.Add(fmt.Errorf("invalid expected status %d", code))
+		}
+	}
+	if check.BodyRegex != "" {
+		if _, err := regexp.Compile(check.BodyRegex); err != nil {
+			violations.Add(fmt.Errorf("invalid body regex: %s", err))
+		}
+	}
+
 	if violations.HasError() {
 		return violations
 	}
 	return nil
 }
+
+func (check *TCPCheck) validate() error {
+	if err := validation.NotEmpty("TCP.Address", check.Address); err != nil {
+		return err
+	}
+	return nil
+}
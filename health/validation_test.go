@@ -37,3 +37,23 @@ func (vs *ValidationSuite) Test_Validation_Invalid_HealthCheck(c *C) {
 	err = hc.ValidEntity()
 	c.Assert(err, IsNil)
 }
+
+func (vs *ValidationSuite) Test_Validation_NativeHealthCheck(c *C) {
+	hc := HealthCheck{HTTP: &HTTPCheck{URL: "http://localhost:{{(context).port}}/ping", ExpectedStatus: []int{200}}}
+	c.Assert(hc.ValidEntity(), IsNil)
+
+	hc = HealthCheck{TCP: &TCPCheck{Address: "localhost:5432"}, KillCountLimit: 3}
+	c.Assert(hc.ValidEntity(), IsNil)
+
+	for _, invalid := range []HealthCheck{
+		{HTTP: &HTTPCheck{}},
+		{HTTP: &HTTPCheck{URL: "http://localhost", Method: "FETCH"}},
+		{HTTP: &HTTPCheck{URL: "http://localhost", ExpectedStatus: []int{1000}}},
+		{HTTP: &HTTPCheck{URL: "http://localhost", BodyRegex: "("}},
+		{TCP: &TCPCheck{}},
+		{Script: "true", TCP: &TCPCheck{Address: "localhost:5432"}},
+		{TCP: &TCPCheck{Address: "localhost:5432"}, KillExitCodes: []int{1}, KillCountLimit: 3},
+	} {
+		c.Check(invalid.ValidEntity(), NotNil, Commentf("%+v", invalid))
+	}
+}
@@ -15,6 +15,7 @@ package isvcs
 
 import (
 	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/config"
 
 	"errors"
 	"encoding/json"
@@ -232,7 +233,11 @@ func queryElasticSearchStats(address string) ElasticSearchStats {
 func GetElasticSearchCustomStats(halt <-chan struct{}) error {
 	timeout := 30 * time.Second
 	timer := time.NewTimer(timeout)
-	es_addresses := [2]string{"http://127.0.0.1:9200", "http://127.0.0.1:9100"}
+	es_addresses := []string{"http://127.0.0.1:9200", "http://127.0.0.1:9100"}
+	if config.GetOptions().DatastoreDriver == config.DatastoreLocal {
+		// elasticsearch-serviced is not running
+		es_addresses = es_addresses[1:]
+	}
 
 	for {
 		select {
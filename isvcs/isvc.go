@@ -77,11 +77,15 @@ func Init(esStartupTimeoutInSeconds int, dockerLogDriver string, dockerLogConfig
 
 	Mgr = NewManager(utils.LocalDir("images"), utils.TempDir("var/isvcs"), dockerLogDriver, dockerLogConfig)
 
-	elasticsearch_serviced.docker = dockerAPI
-	if err := Mgr.Register(elasticsearch_serviced); err != nil {
-		log.WithFields(logrus.Fields{
-			"isvc": "elasticsearch-serviced",
-		}).WithError(err).Fatal("Unable to register internal service")
+	// The master's data lives in a local file when the local datastore
+	// driver is used, so elasticsearch-serviced is not needed.
+	if config.GetOptions().DatastoreDriver != config.DatastoreLocal {
+		elasticsearch_serviced.docker = dockerAPI
+		if err := Mgr.Register(elasticsearch_serviced); err != nil {
+			log.WithFields(logrus.Fields{
+				"isvc": "elasticsearch-serviced",
+			}).WithError(err).Fatal("Unable to register internal service")
+		}
 	}
 	elasticsearch_logstash.docker = dockerAPI
 	if err := Mgr.Register(elasticsearch_logstash); err != nil {
@@ -0,0 +1,52 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"time"
+)
+
+// GetMetricSeries returns the values of a metric over the window, aggregated
+// across all series matching the tags.
+func (c *Client) GetMetricSeries(window time.Duration, aggregator, metric string, tags map[string][]string) (*MetricSeries, error) {
+	log.WithField("metric", metric).WithField("tags", tags).Debug("Requesting metric series")
+
+	options := PerformanceOptions{
+		Start:     time.Now().UTC().Add(-window).Format(timeFormat),
+		End:       "now",
+		Returnset: "exact",
+		Metrics: []MetricOptions{
+			{
+				Metric:     metric,
+				Aggregator: aggregator,
+				Tags:       tags,
+			},
+		},
+	}
+	data, err := c.performanceQuery(options)
+	if err != nil {
+		log.WithError(err).WithField("options", options).Debug("Metric series query failed")
+		return nil, err
+	}
+	var datapoints []Datapoint
+	for _, result := range data.Results {
+		for _, dp := range result.Datapoints {
+			if !dp.Value.IsNaN {
+				datapoints = append(datapoints, dp)
+			}
+		}
+	}
+	series := DatapointsToSeries(datapoints)
+	return &series, nil
+}
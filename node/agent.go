@@ -197,6 +197,9 @@ func NewHostAgent(options AgentOptions, reg registry.Registry) (*HostAgent, erro
 	agent.proxyRegistry = proxy.NewDefaultProxyRegistry()
 	agent.pullreg = reg
 	agent.vip = NewVirtualIPManager("cc")
+	if agent.mux != nil {
+		agent.mux.SetAuthorizer(newMuxAuthorizer(agent))
+	}
 	return agent, err
 }
 
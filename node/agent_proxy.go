@@ -27,6 +27,7 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/rpc/master"
 	"github.com/zenoss/glog"
 )
@@ -112,6 +113,29 @@ func (a *HostAgent) ReportHealthStatus(req master.HealthStatusRequest, unused *i
 	return masterClient.ReportHealthStatus(req.Key, req.Value, req.Expires)
 }
 
+// GetServicesHealth proxies GetServicesHealth to the master server.  Only the
+// results of the requested services are returned, so a container cannot read
+// the health of the whole cluster.
+func (a *HostAgent) GetServicesHealth(serviceIDs []string, results *map[string]map[int]map[string]health.HealthStatus) error {
+	masterClient, err := master.NewClient(a.master)
+	if err != nil {
+		glog.Errorf("Could not start Control Center client: %s", err)
+		return err
+	}
+	defer masterClient.Close()
+	statuses, err := masterClient.GetServicesHealth()
+	if err != nil {
+		return err
+	}
+	*results = make(map[string]map[int]map[string]health.HealthStatus)
+	for _, serviceID := range serviceIDs {
+		if status, ok := statuses[serviceID]; ok {
+			(*results)[serviceID] = status
+		}
+	}
+	return nil
+}
+
 // ReportInstanceDead proxies ReportInstanceDead to the master server.
 func (a *HostAgent) ReportInstanceDead(req master.ServiceInstanceRequest, unused *int) error {
 	masterClient, err := master.NewClient(a.master)
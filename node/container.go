@@ -646,6 +646,7 @@ func (a *HostAgent) createContainerConfig(tenantID string, svc *service.Service,
 					PortNumber:     endpoint.PortNumber,
 					PortTemplate:   endpoint.PortTemplate,
 					VirtualAddress: endpoint.VirtualAddress,
+					LoadBalancing:  endpoint.LoadBalancing,
 				})
 			}
 		}
@@ -26,6 +26,7 @@ import (
 	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/domain/applicationendpoint"
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/rpc/master"
 )
 
@@ -127,6 +128,10 @@ type LoadBalancer interface {
 	// ReportHealthStatus writes the health check status to the cache
 	ReportHealthStatus(req master.HealthStatusRequest, unused *int) error
 
+	// GetServicesHealth returns the health check results of the instances of
+	// the given services
+	GetServicesHealth(serviceIDs []string, results *map[string]map[int]map[string]health.HealthStatus) error
+
 	// ReportInstanceDead removes all health checks for the provided instance from the
 	// cache.
 	ReportInstanceDead(req master.ServiceInstanceRequest, unused *int) error
@@ -15,6 +15,7 @@ package node
 
 import (
 	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/rpc/master"
 	"github.com/control-center/serviced/rpc/rpcutils"
 	"github.com/zenoss/glog"
@@ -90,6 +91,13 @@ func (a *LBClient) ReportHealthStatus(req master.HealthStatusRequest, unused *in
 	return a.rpcClient.Call("ControlCenterAgent.ReportHealthStatus", req, unused, 0)
 }
 
+// GetServicesHealth returns the health check results of the instances of the
+// given services.
+func (a *LBClient) GetServicesHealth(serviceIDs []string, results *map[string]map[int]map[string]health.HealthStatus) error {
+	glog.V(4).Infof("ControlCenterAgent.GetServicesHealth()")
+	return a.rpcClient.Call("ControlCenterAgent.GetServicesHealth", serviceIDs, results, 0)
+}
+
 // ReportInstanceDead removes health check results for an instance.
 func (a *LBClient) ReportInstanceDead(req master.ServiceInstanceRequest, unused *int) error {
 	glog.V(4).Infof("ControlCenterAgent.ReportInstanceDead()")
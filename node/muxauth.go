@@ -0,0 +1,199 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"errors"
+	"net"
+	"path"
+	"strconv"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/utils"
+	"github.com/control-center/serviced/utils/cache"
+	"github.com/control-center/serviced/zzk"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+var (
+	// ErrMuxUnknownAddress is returned when a mux connection is requested to
+	// an address that is neither this host nor one of its containers
+	ErrMuxUnknownAddress = errors.New("address is not served by this host")
+	// ErrMuxTenantNotPermitted is returned when the sender's pool has no
+	// services of the tenant that owns the requested container
+	ErrMuxTenantNotPermitted = errors.New("sender is not permitted to reach the tenant")
+	// ErrMuxPortNotPermitted is returned when a mux connection is requested
+	// to a port of this host that does not serve a control center endpoint
+	ErrMuxPortNotPermitted = errors.New("port is not a control center endpoint")
+)
+
+// muxAuthorizer decides which addresses the sender of a mux connection may
+// reach.  Senders with admin access may reach any address.  Other senders may
+// reach the control center endpoints imported by every service on the
+// loopback and the addresses of this host, and the containers of tenants that
+// have services in the sender's pool.
+type muxAuthorizer struct {
+	endpointPorts map[int]struct{} // ports of the control center endpoints
+	lookupIP      func(host string) ([]net.IP, error)
+	hostAddresses func() ([]string, error)
+	hostInstances func() ([]zkservice.State, error)
+	tenantID      func(serviceID string, instanceID int) (string, error)
+	poolServices  func(poolID string) ([]string, error)
+	tenants       cache.LRUCache // pool id => ids of tenants with services in the pool
+}
+
+func newMuxAuthorizer(a *HostAgent) *muxAuthorizer {
+	m := &muxAuthorizer{
+		endpointPorts: a.controlPlanePorts(),
+		lookupIP:      net.LookupIP,
+		hostAddresses: func() ([]string, error) {
+			ips, err := utils.GetIPv4Addresses()
+			return append(ips, a.ipaddress), err
+		},
+		hostInstances: func() ([]zkservice.State, error) {
+			conn, err := zzk.GetLocalConnection("/")
+			if err != nil {
+				return nil, err
+			}
+			return zkservice.GetHostStates(conn, a.poolID, a.hostID)
+		},
+		tenantID: func(serviceID string, instanceID int) (string, error) {
+			_, tenantID, _, err := a.serviceCache.GetEvaluatedService(serviceID, instanceID)
+			return tenantID, err
+		},
+		poolServices: func(poolID string) ([]string, error) {
+			conn, err := zzk.GetLocalConnection("/")
+			if err != nil {
+				return nil, err
+			}
+			return conn.Children(path.Join("/pools", poolID, "services"))
+		},
+	}
+	m.tenants, _ = cache.NewSimpleLRUCache(60, time.Minute, 30*time.Second, nil)
+	return m
+}
+
+// AuthorizeMux implements proxy.MuxAuthorizer
+func (m *muxAuthorizer) AuthorizeMux(sender auth.Identity, address string) error {
+	logger := plog.WithFields(log.Fields{
+		"senderhostid": sender.HostID(),
+		"senderpoolid": sender.PoolID(),
+		"address":      address,
+	})
+
+	if sender.HasAdminAccess() {
+		return nil
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ips, err := m.lookupIP(host)
+	if err != nil {
+		logger.WithError(err).Debug("Could not resolve the address")
+		return err
+	}
+
+	// Check the loopback and the addresses of the host
+	hostIPs, err := m.hostAddresses()
+	if err != nil {
+		logger.WithError(err).Debug("Could not load all of the host's addresses")
+	}
+	for _, ip := range ips {
+		if ip.IsLoopback() || utils.StringInSlice(ip.String(), hostIPs) {
+			if p, err := strconv.Atoi(port); err == nil {
+				if _, ok := m.endpointPorts[p]; ok {
+					return nil
+				}
+			}
+			return ErrMuxPortNotPermitted
+		}
+	}
+
+	// Find the container that owns the address
+	states, err := m.hostInstances()
+	if err != nil {
+		logger.WithError(err).Warn("Could not load the instances running on this host")
+		return err
+	}
+	for _, state := range states {
+		if !containsIP(ips, state.PrivateIP) {
+			continue
+		}
+		tenantID, err := m.tenantID(state.ServiceID, state.InstanceID)
+		if err != nil {
+			logger.WithError(err).Warn("Could not look up the tenant of the container")
+			return err
+		}
+		tenantIDs, err := m.getPoolTenants(sender.PoolID())
+		if err != nil {
+			logger.WithError(err).Warn("Could not look up the tenants of the sender's pool")
+			return err
+		}
+		if _, ok := tenantIDs[tenantID]; ok {
+			return nil
+		}
+		return ErrMuxTenantNotPermitted
+	}
+	return ErrMuxUnknownAddress
+}
+
+// controlPlanePorts returns the ports of the control center endpoints that
+// the agent exports to every service
+func (a *HostAgent) controlPlanePorts() map[int]struct{} {
+	endpoints := make(map[string][]applicationendpoint.ApplicationEndpoint)
+	a.GetISvcEndpoints("", &endpoints)
+	ports := make(map[int]struct{})
+	for _, eps := range endpoints {
+		for _, ep := range eps {
+			ports[int(ep.ContainerPort)] = struct{}{}
+		}
+	}
+	return ports
+}
+
+// getPoolTenants returns the ids of the tenants with services in the pool
+func (m *muxAuthorizer) getPoolTenants(poolID string) (map[string]struct{}, error) {
+	if data, ok := m.tenants.Get(poolID); ok {
+		return data.(map[string]struct{}), nil
+	}
+	serviceIDs, err := m.poolServices(poolID)
+	if err != nil {
+		return nil, err
+	}
+	tenantIDs := make(map[string]struct{})
+	for _, serviceID := range serviceIDs {
+		tenantID, err := m.tenantID(serviceID, 0)
+		if err != nil {
+			return nil, err
+		}
+		tenantIDs[tenantID] = struct{}{}
+	}
+	m.tenants.Set(poolID, tenantIDs)
+	return tenantIDs, nil
+}
+
+// containsIP returns true if the address is one of the ips
+func containsIP(ips []net.IP, address string) bool {
+	for _, ip := range ips {
+		if ip.Equal(net.ParseIP(address)) {
+			return true
+		}
+	}
+	return false
+}
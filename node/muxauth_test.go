@@ -0,0 +1,153 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package node
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/control-center/serviced/auth/mocks"
+	"github.com/control-center/serviced/utils/cache"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+func testMuxAuthorizer() *muxAuthorizer {
+	m := &muxAuthorizer{
+		endpointPorts: map[int]struct{}{443: {}, 8443: {}, 5601: {}},
+		lookupIP: func(host string) ([]net.IP, error) {
+			switch host {
+			case "localhost":
+				return []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")}, nil
+			case "master.example.com":
+				return []net.IP{net.ParseIP("10.0.0.5")}, nil
+			case "db.example.com":
+				return []net.IP{net.ParseIP("172.17.0.2")}, nil
+			}
+			if ip := net.ParseIP(host); ip != nil {
+				return []net.IP{ip}, nil
+			}
+			return nil, errors.New("no such host")
+		},
+		hostAddresses: func() ([]string, error) {
+			return []string{"10.0.0.5"}, nil
+		},
+		hostInstances: func() ([]zkservice.State, error) {
+			return []zkservice.State{
+				{
+					ServiceState: zkservice.ServiceState{PrivateIP: "172.17.0.2"},
+					ServiceID:    "svc-a",
+				}, {
+					ServiceState: zkservice.ServiceState{PrivateIP: "172.17.0.3"},
+					ServiceID:    "svc-b",
+				},
+			}, nil
+		},
+		tenantID: func(serviceID string, instanceID int) (string, error) {
+			switch serviceID {
+			case "svc-a", "svc-pool1":
+				return "tenant-a", nil
+			case "svc-b", "svc-pool2":
+				return "tenant-b", nil
+			}
+			return "", errors.New("service not found")
+		},
+		poolServices: func(poolID string) ([]string, error) {
+			switch poolID {
+			case "pool1":
+				return []string{"svc-pool1"}, nil
+			case "pool2":
+				return []string{"svc-pool2"}, nil
+			}
+			return nil, nil
+		},
+	}
+	m.tenants, _ = cache.NewSimpleLRUCache(10, time.Minute, 0, nil)
+	return m
+}
+
+func testSender(poolID string, admin bool) *mocks.Identity {
+	sender := &mocks.Identity{}
+	sender.On("HostID").Return("sender")
+	sender.On("PoolID").Return(poolID)
+	sender.On("HasAdminAccess").Return(admin)
+	return sender
+}
+
+func TestMuxAuthorizer(t *testing.T) {
+	m := testMuxAuthorizer()
+
+	for _, tc := range []struct {
+		poolID  string
+		admin   bool
+		address string
+		err     error
+	}{
+		{"pool1", false, "127.0.0.1:8443", nil},
+		{"pool1", false, "127.0.0.1:5601", nil},
+		{"pool1", false, "[::1]:5601", nil},
+		{"pool1", false, "localhost:5601", nil},
+		{"pool1", false, "127.0.0.1:2181", ErrMuxPortNotPermitted},
+		{"pool1", false, "localhost:2181", ErrMuxPortNotPermitted},
+		{"pool1", false, "master.example.com:8443", nil},
+		{"pool1", false, "master.example.com:2181", ErrMuxPortNotPermitted},
+		{"pool1", false, "db.example.com:3306", nil},
+		{"pool1", false, "10.0.0.5:8443", nil},
+		{"pool1", false, "10.0.0.5:2181", ErrMuxPortNotPermitted},
+		{"pool1", false, "172.17.0.2:3306", nil},
+		{"pool1", false, "172.17.0.3:3306", ErrMuxTenantNotPermitted},
+		{"pool2", false, "172.17.0.3:3306", nil},
+		{"pool3", false, "172.17.0.2:3306", ErrMuxTenantNotPermitted},
+		{"pool1", false, "192.168.0.9:22", ErrMuxUnknownAddress},
+		{"pool3", true, "192.168.0.9:22", nil},
+	} {
+		err := m.AuthorizeMux(testSender(tc.poolID, tc.admin), tc.address)
+		if err != tc.err {
+			t.Errorf("Expected %v for %s from %s (admin=%v), got %v", tc.err, tc.address, tc.poolID, tc.admin, err)
+		}
+	}
+}
+
+func TestMuxAuthorizerLookupError(t *testing.T) {
+	m := testMuxAuthorizer()
+	m.poolServices = func(poolID string) ([]string, error) {
+		return []string{"svc-missing"}, nil
+	}
+	if err := m.AuthorizeMux(testSender("pool1", false), "172.17.0.2:3306"); err == nil {
+		t.Errorf("Expected an error when the tenants of the pool cannot be loaded")
+	}
+}
+
+func TestMuxAuthorizerUnresolvedAddress(t *testing.T) {
+	m := testMuxAuthorizer()
+	if err := m.AuthorizeMux(testSender("pool1", false), "nowhere.example.com:8443"); err == nil {
+		t.Errorf("Expected an error when the address cannot be resolved")
+	}
+}
+
+func TestControlPlanePorts(t *testing.T) {
+	agent := &HostAgent{master: "10.0.0.1:4979", uiport: ":443"}
+	ports := agent.controlPlanePorts()
+	for _, port := range []int{443, 8443, 5042, 5043, 5601} {
+		if _, ok := ports[port]; !ok {
+			t.Errorf("Expected port %d to be a control center endpoint", port)
+		}
+	}
+	if _, ok := ports[4979]; ok {
+		t.Errorf("Expected the rpc port not to be a control center endpoint")
+	}
+}
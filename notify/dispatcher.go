@@ -0,0 +1,240 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+const (
+	// DefaultDedupInterval is how long repeats of an event are suppressed if
+	// the configuration does not say otherwise
+	DefaultDedupInterval = 15 * time.Minute
+
+	defaultWebhookTimeout = 10 * time.Second
+)
+
+// SinkConfig describes a named sink.  Type is one of "webhook", "smtp" or
+// "syslog", and determines which of the other fields are used.
+type SinkConfig struct {
+	Name string
+	Type string
+
+	// webhook
+	URL     string
+	Timeout int // seconds
+
+	// smtp
+	Address  string
+	Username string
+	Password string
+	From     string
+	To       []string
+
+	// syslog
+	Network string
+	Tag     string
+}
+
+// Route sends the events that match it to a list of sinks
+type Route struct {
+	TenantID string // matches every tenant, and events without one, if empty
+	Kinds    []Kind // matches every kind if empty
+	Sinks    []string
+}
+
+// Matches returns true if the event should be sent along the route
+func (r Route) Matches(e Event) bool {
+	if r.TenantID != "" && r.TenantID != e.TenantID {
+		return false
+	}
+	if len(r.Kinds) == 0 {
+		return true
+	}
+	for _, kind := range r.Kinds {
+		if kind == e.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Config is the notification configuration, usually read from a JSON file
+type Config struct {
+	DedupInterval int // seconds; DefaultDedupInterval is used if 0
+	Sinks         []SinkConfig
+	Routes        []Route
+}
+
+// LoadConfig reads the configuration from a JSON file
+func LoadConfig(filename string) (*Config, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("could not parse notification config %s: %s", filename, err)
+	}
+	return config, nil
+}
+
+// NewSink creates the sink described by the config
+func NewSink(config SinkConfig) (Sink, error) {
+	switch config.Type {
+	case "webhook":
+		if config.URL == "" {
+			return nil, fmt.Errorf("webhook sink %s has no URL", config.Name)
+		}
+		timeout := defaultWebhookTimeout
+		if config.Timeout > 0 {
+			timeout = time.Duration(config.Timeout) * time.Second
+		}
+		return NewWebhookSink(config.URL, timeout), nil
+	case "smtp":
+		if config.Address == "" || len(config.To) == 0 {
+			return nil, fmt.Errorf("smtp sink %s needs an address and recipients", config.Name)
+		}
+		return NewSMTPSink(config.Address, config.Username, config.Password, config.From, config.To), nil
+	case "syslog":
+		tag := config.Tag
+		if tag == "" {
+			tag = "serviced"
+		}
+		return NewSyslogSink(config.Network, config.Address, tag)
+	default:
+		return nil, fmt.Errorf("sink %s has unknown type %q", config.Name, config.Type)
+	}
+}
+
+// Dispatcher routes events to sinks, suppressing repeats of an event within
+// the dedup interval
+type Dispatcher struct {
+	sinks    map[string]Sink
+	routes   []Route
+	interval time.Duration
+	now      func() time.Time
+
+	mu   sync.Mutex
+	sent map[string]time.Time
+}
+
+// NewDispatcher creates the sinks of the config and returns a dispatcher
+// that routes events to them
+func NewDispatcher(config *Config) (*Dispatcher, error) {
+	sinks := make(map[string]Sink)
+	for _, sc := range config.Sinks {
+		if _, ok := sinks[sc.Name]; ok {
+			return nil, fmt.Errorf("sink %s is defined more than once", sc.Name)
+		}
+		sink, err := NewSink(sc)
+		if err != nil {
+			return nil, err
+		}
+		sinks[sc.Name] = sink
+	}
+	interval := DefaultDedupInterval
+	if config.DedupInterval > 0 {
+		interval = time.Duration(config.DedupInterval) * time.Second
+	}
+	return newDispatcher(sinks, config.Routes, interval)
+}
+
+func newDispatcher(sinks map[string]Sink, routes []Route, interval time.Duration) (*Dispatcher, error) {
+	for _, route := range routes {
+		for _, name := range route.Sinks {
+			if _, ok := sinks[name]; !ok {
+				return nil, fmt.Errorf("route refers to undefined sink %s", name)
+			}
+		}
+	}
+	return &Dispatcher{
+		sinks:    sinks,
+		routes:   routes,
+		interval: interval,
+		now:      time.Now,
+		sent:     make(map[string]time.Time),
+	}, nil
+}
+
+// Notify sends the event to the sinks of every matching route in the
+// background, unless the event was already sent within the dedup interval
+func (d *Dispatcher) Notify(e Event) {
+	names := d.route(e)
+	if len(names) == 0 {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = d.now()
+	}
+	for _, name := range names {
+		go d.send(name, e)
+	}
+}
+
+// route returns the names of the sinks that should receive the event, or
+// nothing if the event is a repeat
+func (d *Dispatcher) route(e Event) []string {
+	names := []string{}
+	seen := make(map[string]struct{})
+	for _, route := range d.routes {
+		if !route.Matches(e) {
+			continue
+		}
+		for _, name := range route.Sinks {
+			if _, ok := seen[name]; !ok {
+				seen[name] = struct{}{}
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 || d.isRepeat(e) {
+		return nil
+	}
+	return names
+}
+
+// isRepeat records the event and returns true if the same event was already
+// recorded within the dedup interval
+func (d *Dispatcher) isRepeat(e Event) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	for key, last := range d.sent {
+		if now.Sub(last) >= d.interval {
+			delete(d.sent, key)
+		}
+	}
+	key := e.DedupKey()
+	if _, ok := d.sent[key]; ok {
+		return true
+	}
+	d.sent[key] = now
+	return false
+}
+
+func (d *Dispatcher) send(name string, e Event) {
+	if err := d.sinks[name].Send(e); err != nil {
+		plog.WithError(err).WithFields(logrus.Fields{
+			"sink":  name,
+			"event": e.Kind,
+		}).Warn("Unable to send notification")
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package notify
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+var _ = Suite(&DispatcherSuite{})
+
+type DispatcherSuite struct {
+	now        time.Time
+	dispatcher *Dispatcher
+}
+
+type nullSink struct{}
+
+func (nullSink) Send(Event) error { return nil }
+
+func (s *DispatcherSuite) SetUpTest(c *C) {
+	sinks := map[string]Sink{"ops": nullSink{}, "tenant1": nullSink{}, "mail": nullSink{}}
+	routes := []Route{
+		{Sinks: []string{"ops"}},
+		{TenantID: "tenant1", Sinks: []string{"tenant1", "ops"}},
+		{Kinds: []Kind{KindBackupFailed}, Sinks: []string{"mail"}},
+	}
+	var err error
+	s.dispatcher, err = newDispatcher(sinks, routes, time.Minute)
+	c.Assert(err, IsNil)
+	s.now = time.Now()
+	s.dispatcher.now = func() time.Time { return s.now }
+}
+
+func (s *DispatcherSuite) TestRoute(c *C) {
+	names := s.dispatcher.route(Event{Kind: KindHostInactive, HostID: "host1"})
+	c.Assert(names, DeepEquals, []string{"ops"})
+
+	names = s.dispatcher.route(Event{Kind: KindHealthCheckFailed, TenantID: "tenant1", ServiceID: "svc1"})
+	c.Assert(names, DeepEquals, []string{"ops", "tenant1"})
+
+	names = s.dispatcher.route(Event{Kind: KindBackupFailed})
+	c.Assert(names, DeepEquals, []string{"ops", "mail"})
+}
+
+func (s *DispatcherSuite) TestRouteDedup(c *C) {
+	e := Event{Kind: KindHealthCheckFailed, TenantID: "tenant2", ServiceID: "svc1"}
+	c.Assert(s.dispatcher.route(e), HasLen, 1)
+
+	s.now = s.now.Add(30 * time.Second)
+	c.Assert(s.dispatcher.route(e), HasLen, 0)
+
+	other := e
+	other.ServiceID = "svc2"
+	c.Assert(s.dispatcher.route(other), HasLen, 1)
+
+	s.now = s.now.Add(time.Minute)
+	c.Assert(s.dispatcher.route(e), HasLen, 1)
+}
+
+func (s *DispatcherSuite) TestNewDispatcherUndefinedSink(c *C) {
+	_, err := newDispatcher(map[string]Sink{}, []Route{{Sinks: []string{"missing"}}}, time.Minute)
+	c.Assert(err, NotNil)
+}
+
+func (s *DispatcherSuite) TestNewSinkInvalid(c *C) {
+	_, err := NewSink(SinkConfig{Name: "a", Type: "pager"})
+	c.Assert(err, NotNil)
+	_, err = NewSink(SinkConfig{Name: "b", Type: "webhook"})
+	c.Assert(err, NotNil)
+	_, err = NewSink(SinkConfig{Name: "c", Type: "smtp", Address: "localhost:25"})
+	c.Assert(err, NotNil)
+}
+
+func (s *DispatcherSuite) TestWebhookSink(c *C) {
+	received := make(chan Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e Event
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &e)
+		received <- e
+	}))
+	defer server.Close()
+
+	sink, err := NewSink(SinkConfig{Name: "hook", Type: "webhook", URL: server.URL})
+	c.Assert(err, IsNil)
+	c.Assert(sink.Send(Event{Kind: KindEmergencyShutdown, TenantID: "tenant1"}), IsNil)
+	e := <-received
+	c.Assert(e.Kind, Equals, KindEmergencyShutdown)
+	c.Assert(e.TenantID, Equals, "tenant1")
+}
+
+func (s *DispatcherSuite) TestWebhookSinkError(c *C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, time.Second)
+	c.Assert(sink.Send(Event{Kind: KindBackupFailed}), NotNil)
+}
@@ -0,0 +1,14 @@
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+import notify "github.com/control-center/serviced/notify"
+
+// Notifier is an autogenerated mock type for the Notifier type
+type Notifier struct {
+	mock.Mock
+}
+
+// Notify provides a mock function with given fields: e
+func (_m *Notifier) Notify(e notify.Event) {
+	_m.Called(e)
+}
@@ -0,0 +1,83 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notify delivers notifications about service and host events to
+// external sinks such as webhooks, email and syslog.
+package notify
+
+import (
+	"strings"
+	"time"
+
+	"github.com/control-center/serviced/logging"
+)
+
+var plog = logging.PackageLogger()
+
+// Kind identifies the type of event being notified
+type Kind string
+
+const (
+	// KindHealthCheckFailed is sent when a health check of a service
+	// instance fails or times out
+	KindHealthCheckFailed Kind = "health-check-failed"
+	// KindHostInactive is sent when a host is no longer active
+	KindHostInactive Kind = "host-inactive"
+	// KindEmergencyShutdown is sent when the services of a tenant are
+	// stopped because its storage is running out
+	KindEmergencyShutdown Kind = "emergency-shutdown"
+	// KindBackupFailed is sent when a backup does not complete
+	KindBackupFailed Kind = "backup-failed"
+	// KindAlertOpened is sent when a monitoring threshold is breached
+	KindAlertOpened Kind = "alert-opened"
+)
+
+// Event is a notification about something that happened in the cluster
+type Event struct {
+	Kind      Kind
+	Key       string // identifies repeats of the event; derived from the kind and ids if empty
+	TenantID  string
+	ServiceID string
+	HostID    string
+	Summary   string
+	Details   string
+	Time      time.Time
+}
+
+// DedupKey returns the key used to detect repeats of the event
+func (e Event) DedupKey() string {
+	if e.Key != "" {
+		return string(e.Kind) + "/" + e.Key
+	}
+	return strings.Join([]string{string(e.Kind), e.TenantID, e.ServiceID, e.HostID}, "/")
+}
+
+// Sink delivers events to an external system
+type Sink interface {
+	// Send delivers the event, returning an error if it could not
+	Send(e Event) error
+}
+
+// Notifier accepts events for delivery
+type Notifier interface {
+	// Notify routes the event to its sinks.  It does not wait for the event
+	// to be delivered.
+	Notify(e Event)
+}
+
+// Discard is a Notifier that drops every event
+var Discard Notifier = discard{}
+
+type discard struct{}
+
+func (discard) Notify(Event) {}
@@ -0,0 +1,115 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// WebhookSink posts events as JSON to a URL
+type WebhookSink struct {
+	URL    string
+	client *http.Client
+}
+
+// NewWebhookSink returns a sink that posts to url, giving up on a request
+// after timeout
+func NewWebhookSink(url string, timeout time.Duration) *WebhookSink {
+	return &WebhookSink{URL: url, client: &http.Client{Timeout: timeout}}
+}
+
+// Send posts the event to the webhook
+func (s *WebhookSink) Send(e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned %s", s.URL, resp.Status)
+	}
+	return nil
+}
+
+// SMTPSink emails events to a list of recipients
+type SMTPSink struct {
+	Addr string
+	From string
+	To   []string
+	auth smtp.Auth
+}
+
+// NewSMTPSink returns a sink that sends mail through the server at addr
+// (host:port).  The server is authenticated against if a username is given.
+func NewSMTPSink(addr, username, password, from string, to []string) *SMTPSink {
+	s := &SMTPSink{Addr: addr, From: from, To: to}
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		s.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return s
+}
+
+// Send emails the event
+func (s *SMTPSink) Send(e Event) error {
+	msg := &bytes.Buffer{}
+	fmt.Fprintf(msg, "From: %s\r\n", s.From)
+	fmt.Fprintf(msg, "To: %s\r\n", strings.Join(s.To, ", "))
+	fmt.Fprintf(msg, "Subject: [serviced] %s\r\n", e.Summary)
+	fmt.Fprintf(msg, "Date: %s\r\n\r\n", e.Time.Format(time.RFC1123Z))
+	fmt.Fprintf(msg, "%s\r\n\r\n", e.Summary)
+	if e.Details != "" {
+		fmt.Fprintf(msg, "%s\r\n\r\n", e.Details)
+	}
+	fmt.Fprintf(msg, "Event: %s\r\n", e.Kind)
+	for _, field := range [][2]string{{"Tenant", e.TenantID}, {"Service", e.ServiceID}, {"Host", e.HostID}} {
+		if field[1] != "" {
+			fmt.Fprintf(msg, "%s: %s\r\n", field[0], field[1])
+		}
+	}
+	return smtp.SendMail(s.Addr, s.auth, s.From, s.To, msg.Bytes())
+}
+
+// SyslogSink writes events to a syslog daemon
+type SyslogSink struct {
+	writer *syslog.Writer
+}
+
+// NewSyslogSink connects to the syslog daemon at raddr over network, or to
+// the local daemon if both are empty
+func NewSyslogSink(network, raddr, tag string) (*SyslogSink, error) {
+	writer, err := syslog.Dial(network, raddr, syslog.LOG_WARNING|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogSink{writer: writer}, nil
+}
+
+// Send writes the event as a warning
+func (s *SyslogSink) Send(e Event) error {
+	msg := fmt.Sprintf("event=%s tenant=%s service=%s host=%s: %s", e.Kind, e.TenantID, e.ServiceID, e.HostID, e.Summary)
+	return s.writer.Warning(msg)
+}
@@ -15,28 +15,45 @@ package proxy
 
 import (
 	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/audit"
 	"github.com/control-center/serviced/auth"
 	"github.com/control-center/serviced/logging"
 	"github.com/control-center/serviced/utils"
 
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 )
 
 var (
 	log = logging.PackageLogger()
+
+	// ErrMuxSenderInvalid is returned when the sender's identity is missing
+	// or no longer valid
+	ErrMuxSenderInvalid = errors.New("mux sender identity is not valid")
 )
 
+// MuxAuthorizer decides whether the sender of a mux connection may reach the
+// requested address.
+type MuxAuthorizer interface {
+	AuthorizeMux(sender auth.Identity, address string) error
+}
+
 // TCPMux is an implementation of tcp muxing RFC 1078.
 type TCPMux struct {
 	listener    net.Listener    // the connection this mux listens on
 	connections chan net.Conn   // stream of accepted connections
 	closing     chan chan error // shutdown noticiation
 	log         *logrus.Entry
+
+	mu          sync.RWMutex
+	authorizer  MuxAuthorizer // authorizes the sender of each connection
+	auditLogger audit.Logger
 }
 
 // NewTCPMux creates a new tcp mux with the given listener. If it succees, it
@@ -54,12 +71,22 @@ func NewTCPMux(listener net.Listener) (mux *TCPMux, err error) {
 		log: log.WithFields(logrus.Fields{
 			"address": listener.Addr(),
 		}),
+		auditLogger: audit.NewLogger(),
 	}
 	go mux.loop()
 	mux.log.Info("Started TCP multiplexer")
 	return mux, nil
 }
 
+// SetAuthorizer sets the authorizer that decides which addresses the sender
+// of a connection may reach.  Without an authorizer, any sender with a valid
+// identity is allowed.
+func (mux *TCPMux) SetAuthorizer(authorizer MuxAuthorizer) {
+	mux.mu.Lock()
+	defer mux.mu.Unlock()
+	mux.authorizer = authorizer
+}
+
 func (mux *TCPMux) Close() {
 	mux.log.Debug("Closing TCP multiplexer")
 	close(mux.closing)
@@ -142,16 +169,32 @@ func (mux *TCPMux) muxConnection(conn net.Conn) {
 	// make sure that we don't block indefinitely
 	conn.SetReadDeadline(time.Now().Add(time.Second * 5))
 
-	// TODO retrieve and validate the identity of the sender
-	addrPacked, _, err := auth.ReadMuxHeader(conn)
+	// Reading the header verifies the signature of the header and the
+	// sender's identity token
+	addrPacked, sender, err := auth.ReadMuxHeader(conn)
 	if err != nil {
 		log.WithError(err).Warn("Unable to read valid mux header. Closing connection")
+		if herr, ok := err.(*auth.AuthHeaderError); ok && len(herr.Payload) == auth.ADDRESS_BYTES {
+			mux.auditRejected(conn, nil, utils.UnpackTCPAddressToString(herr.Payload), err)
+		} else {
+			mux.auditRejected(conn, nil, "", err)
+		}
 		conn.Close()
 		return
 	}
 
 	address := utils.UnpackTCPAddressToString(addrPacked)
 
+	// Make sure the sender is allowed to reach the address
+	if err := mux.authorize(sender, address); err != nil {
+		log.WithError(err).WithFields(logrus.Fields{
+			"containeraddr": address,
+		}).Warn("Mux sender is not authorized. Closing connection")
+		mux.auditRejected(conn, sender, address, err)
+		conn.Close()
+		return
+	}
+
 	// Restore the read deadline
 	conn.SetReadDeadline(time.Time{})
 
@@ -172,6 +215,40 @@ func (mux *TCPMux) muxConnection(conn net.Conn) {
 	go ProxyLoop(conn, svc, quit)
 }
 
+// authorize returns an error if the sender may not connect to the address
+func (mux *TCPMux) authorize(sender auth.Identity, address string) error {
+	if sender == nil {
+		return ErrMuxSenderInvalid
+	}
+	if err := sender.Valid(); err != nil {
+		return err
+	}
+	mux.mu.RLock()
+	authorizer := mux.authorizer
+	mux.mu.RUnlock()
+	if authorizer != nil {
+		return authorizer.AuthorizeMux(sender, address)
+	}
+	return nil
+}
+
+// auditRejected writes an audit entry for a rejected connection attempt
+func (mux *TCPMux) auditRejected(conn net.Conn, sender auth.Identity, address string, reason error) {
+	user := "unknown"
+	if sender != nil {
+		user = sender.HostID()
+	}
+	mux.auditLogger.Action(audit.Connect).
+		UserMessage(user, "Rejected mux connection").
+		Type("mux").
+		WithFields(logrus.Fields{
+			"remoteaddr":    conn.RemoteAddr().String(),
+			"containeraddr": address,
+			"reason":        reason.Error(),
+		}).
+		Failed()
+}
+
 func ProxyLoop(client net.Conn, backend net.Conn, quit chan bool) {
 	event := make(chan int64)
 	var broker = func(to, from net.Conn) {
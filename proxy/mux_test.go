@@ -161,3 +161,61 @@ func TestTCPMux(t *testing.T) {
 	conn.Close()
 
 }
+
+type denyAuthorizer struct {
+	senders []string
+}
+
+func (a *denyAuthorizer) AuthorizeMux(sender auth.Identity, address string) error {
+	a.senders = append(a.senders, sender.HostID())
+	return fmt.Errorf("%s may not connect to %s", sender.HostID(), address)
+}
+
+func TestTCPMuxUnauthorized(t *testing.T) {
+
+	// Create a master key pair
+	pub, priv, _ := auth.GenerateRSAKeyPairPEM(nil)
+	auth.LoadMasterKeysFromPEM(pub, priv)
+
+	dpub, priv, _ := auth.GenerateRSAKeyPairPEM(nil)
+	auth.LoadDelegateKeysFromPEM(pub, priv)
+
+	auth.RefreshToken(func() (string, int64, error) {
+		return auth.CreateJWTIdentity("host", "pool", false, false, dpub, time.Duration(365*24*60*60)*time.Second)
+	}, "")
+
+	target := newEchoListener(t)
+	defer target.Close()
+
+	muxEndpoint, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatalf("could not create tcpmux endpoint: %s", err)
+	}
+	mux, err := NewTCPMux(muxEndpoint)
+	if err != nil {
+		t.Fatalf("did not expect failure creating TCPMux: %s", err)
+	}
+	authorizer := &denyAuthorizer{}
+	mux.SetAuthorizer(authorizer)
+
+	conn := mux.testConnect(t)
+	defer conn.Close()
+	addr, err := utils.PackTCPAddressString(fmt.Sprintf("127.0.0.1:%s", listenerToPort(target.listener)))
+	if err != nil {
+		t.Fail()
+	}
+	token, err := auth.AuthTokenNonBlocking()
+	if err != nil {
+		t.Fail()
+	}
+	auth.AddSignedMuxHeader(conn, addr, token)
+	conn.Write([]byte("\nhello\n"))
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buffer := make([]byte, 4096)
+	if n, err := conn.Read(buffer); err == nil || n > 0 {
+		t.Fatalf("expected the connection to be closed, got %d bytes and error %v", n, err)
+	}
+	if len(authorizer.senders) != 1 || authorizer.senders[0] != "host" {
+		t.Errorf("expected the authorizer to check sender host, got %v", authorizer.senders)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/alert"
+)
+
+// GetAlerts returns the active alerts, or every alert if all is set
+func (c *Client) GetAlerts(all bool) ([]alert.Alert, error) {
+	alerts := []alert.Alert{}
+	err := c.call("GetAlerts", all, &alerts)
+	return alerts, err
+}
+
+// GetAlert returns an alert by its id
+func (c *Client) GetAlert(alertID string) (*alert.Alert, error) {
+	a := &alert.Alert{}
+	if err := c.call("GetAlert", alertID, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// AcknowledgeAlert acknowledges an active alert on behalf of a user
+func (c *Client) AcknowledgeAlert(alertID, userName string) error {
+	req := AcknowledgeAlertRequest{AlertID: alertID, UserName: userName}
+	return c.call("AcknowledgeAlert", req, nil)
+}
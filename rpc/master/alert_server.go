@@ -0,0 +1,49 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/alert"
+)
+
+// AcknowledgeAlertRequest is the request object for AcknowledgeAlert
+type AcknowledgeAlertRequest struct {
+	AlertID  string
+	UserName string
+}
+
+// GetAlerts returns the active alerts, or every alert if all is set
+func (s *Server) GetAlerts(all bool, alerts *[]alert.Alert) error {
+	result, err := s.f.GetAlerts(s.context(), all)
+	if err != nil {
+		return err
+	}
+	*alerts = result
+	return nil
+}
+
+// GetAlert returns an alert by its id
+func (s *Server) GetAlert(alertID string, a *alert.Alert) error {
+	result, err := s.f.GetAlert(s.context(), alertID)
+	if err != nil {
+		return err
+	}
+	*a = *result
+	return nil
+}
+
+// AcknowledgeAlert acknowledges an active alert on behalf of a user
+func (s *Server) AcknowledgeAlert(req AcknowledgeAlertRequest, _ *struct{}) error {
+	return s.f.AcknowledgeAlert(s.context(), req.AlertID, req.UserName)
+}
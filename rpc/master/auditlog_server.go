@@ -0,0 +1,29 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/auditlog"
+)
+
+// GetAuditEvents returns the recorded audit events selected by the filter,
+// newest first
+func (s *Server) GetAuditEvents(filter auditlog.Filter, events *[]auditlog.Event) error {
+	result, err := s.f.GetAuditEvents(s.context(), filter)
+	if err != nil {
+		return err
+	}
+	*events = result
+	return nil
+}
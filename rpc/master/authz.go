@@ -0,0 +1,355 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/schedule"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/user"
+	"github.com/control-center/serviced/facade"
+	"github.com/control-center/serviced/rpc/rpcutils"
+)
+
+// ErrLoginRequired is returned when a delegate makes a call on behalf of a
+// user without the user having logged in
+var ErrLoginRequired = errors.New("this call requires a user; run 'serviced user login' first")
+
+// ErrMasterOnly is returned when a delegate makes a call that only the master
+// may make
+var ErrMasterOnly = errors.New("this call may only be made by the master")
+
+// accessRule returns the access requests that a call must satisfy, given the
+// decoded argument of the call
+type accessRule func(f facade.FacadeInterface, ctx datastore.Context, args interface{}) ([]user.AccessRequest, error)
+
+// allow requires the action on the scope of a single entity
+func allow(action user.Action, scope func(args interface{}) user.AccessRequest) accessRule {
+	return func(_ facade.FacadeInterface, _ datastore.Context, args interface{}) ([]user.AccessRequest, error) {
+		req := scope(args)
+		req.Action = action
+		return []user.AccessRequest{req}, nil
+	}
+}
+
+// anywhere requires the action without regard to scope
+func anywhere(action user.Action) accessRule {
+	return allow(action, func(interface{}) user.AccessRequest { return user.AccessRequest{} })
+}
+
+func byServiceID(args interface{}) user.AccessRequest {
+	return user.AccessRequest{ServiceID: *args.(*string)}
+}
+
+func byHostID(args interface{}) user.AccessRequest {
+	return user.AccessRequest{HostID: *args.(*string)}
+}
+
+func byPoolID(args interface{}) user.AccessRequest {
+	return user.AccessRequest{PoolID: *args.(*string)}
+}
+
+func bySnapshotID(args interface{}) user.AccessRequest {
+	return user.AccessRequest{ServiceID: snapshotTenantID(*args.(*string))}
+}
+
+func byPublicEndpoint(args interface{}) user.AccessRequest {
+	return user.AccessRequest{ServiceID: args.(*PublicEndpointRequest).Serviceid}
+}
+
+// snapshotTenantID returns the id of the tenant that a snapshot was taken of
+func snapshotTenantID(snapshotID string) string {
+	return strings.SplitN(snapshotID, "_", 2)[0]
+}
+
+// scheduleServices requires the action on every one of the services
+func scheduleServices(action user.Action) accessRule {
+	return func(_ facade.FacadeInterface, _ datastore.Context, args interface{}) ([]user.AccessRequest, error) {
+		serviceIDs := args.(*dao.ScheduleServiceRequest).ServiceIDs
+		reqs := make([]user.AccessRequest, len(serviceIDs))
+		for i, serviceID := range serviceIDs {
+			reqs[i] = user.AccessRequest{Action: action, ServiceID: serviceID}
+		}
+		return reqs, nil
+	}
+}
+
+// accessRules are the calls that act on behalf of a user, by service method.
+// Calls that are missing are made by the hosts themselves and are covered by
+// host authentication alone.
+var accessRules = map[string]accessRule{
+	"Master.AcknowledgeAlert": func(f facade.FacadeInterface, ctx datastore.Context, args interface{}) ([]user.AccessRequest, error) {
+		al, err := f.GetAlert(ctx, args.(*AcknowledgeAlertRequest).AlertID)
+		if err != nil {
+			return nil, err
+		}
+		return []user.AccessRequest{{Action: user.ActionControlService, ServiceID: al.ServiceID}}, nil
+	},
+	"Master.GetAuditEvents":      anywhere(user.ActionViewAudit),
+	"Master.VerifyBackup":        anywhere(user.ActionRestore),
+	"Master.AddCertificate":      anywhere(user.ActionManageCertificates),
+	"Master.RemoveCertificate":   anywhere(user.ActionManageCertificates),
+	"Master.DebugEnableMetrics":  anywhere(user.ActionEditHost),
+	"Master.DebugDisableMetrics": anywhere(user.ActionEditHost),
+	"Master.ResetRegistry":       anywhere(user.ActionEditTemplate),
+	"Master.SyncRegistry":        anywhere(user.ActionEditTemplate),
+	"Master.UpgradeRegistry":     anywhere(user.ActionEditTemplate),
+	"Master.DockerOverride":      anywhere(user.ActionEditTemplate),
+	"Master.AddHost": allow(user.ActionEditHost, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{PoolID: args.(*host.Host).PoolID}
+	}),
+	"Master.UpdateHost": allow(user.ActionEditHost, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{HostID: args.(*host.Host).ID}
+	}),
+	"Master.RemoveHost":   allow(user.ActionEditHost, byHostID),
+	"Master.ResetHostKey": allow(user.ActionEditHost, byHostID),
+	"Master.StopServiceInstance": allow(user.ActionControlService, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{ServiceID: args.(*ServiceInstanceRequest).ServiceID}
+	}),
+	"Master.SendDockerAction": allow(user.ActionControlService, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{ServiceID: args.(*DockerActionRequest).ServiceID}
+	}),
+	"Master.AddResourcePool": anywhere(user.ActionEditPool),
+	"Master.UpdateResourcePool": allow(user.ActionEditPool, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{PoolID: args.(*pool.ResourcePool).ID}
+	}),
+	"Master.RemoveResourcePool": allow(user.ActionEditPool, byPoolID),
+	"Master.AddVirtualIP": allow(user.ActionEditPool, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{PoolID: args.(*pool.VirtualIP).PoolID}
+	}),
+	"Master.RemoveVirtualIP": allow(user.ActionEditPool, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{PoolID: args.(*pool.VirtualIP).PoolID}
+	}),
+	"Master.RebalancePool": func(_ facade.FacadeInterface, _ datastore.Context, args interface{}) ([]user.AccessRequest, error) {
+		req := args.(*RebalancePoolRequest)
+		action := user.ActionEditPool
+		if req.DryRun {
+			action = user.ActionView
+		}
+		return []user.AccessRequest{{Action: action, PoolID: req.PoolID}}, nil
+	},
+	"Master.AddPublicEndpointPort":        allow(user.ActionEditService, byPublicEndpoint),
+	"Master.RemovePublicEndpointPort":     allow(user.ActionEditService, byPublicEndpoint),
+	"Master.EnablePublicEndpointPort":     allow(user.ActionEditService, byPublicEndpoint),
+	"Master.AddPublicEndpointVHost":       allow(user.ActionEditService, byPublicEndpoint),
+	"Master.RemovePublicEndpointVHost":    allow(user.ActionEditService, byPublicEndpoint),
+	"Master.EnablePublicEndpointVHost":    allow(user.ActionEditService, byPublicEndpoint),
+	"Master.SetPublicEndpointPortAccess":  allow(user.ActionEditService, byPublicEndpoint),
+	"Master.SetPublicEndpointVHostAccess": allow(user.ActionEditService, byPublicEndpoint),
+	"Master.AddSchedule": func(_ facade.FacadeInterface, _ datastore.Context, args interface{}) ([]user.AccessRequest, error) {
+		return []user.AccessRequest{args.(*schedule.Schedule).AccessRequest()}, nil
+	},
+	"Master.UpdateSchedule": func(f facade.FacadeInterface, ctx datastore.Context, args interface{}) ([]user.AccessRequest, error) {
+		updated := args.(*schedule.Schedule)
+		current, err := f.GetSchedule(ctx, updated.ID)
+		if err != nil {
+			return nil, err
+		}
+		// the user must be allowed to act on both the old and new scope
+		return []user.AccessRequest{current.AccessRequest(), updated.AccessRequest()}, nil
+	},
+	"Master.RemoveSchedule": func(f facade.FacadeInterface, ctx datastore.Context, args interface{}) ([]user.AccessRequest, error) {
+		s, err := f.GetSchedule(ctx, *args.(*string))
+		if err != nil {
+			return nil, err
+		}
+		return []user.AccessRequest{s.AccessRequest()}, nil
+	},
+	"Master.ServiceUse": func(_ facade.FacadeInterface, _ datastore.Context, args interface{}) ([]user.AccessRequest, error) {
+		req := args.(*ServiceUseRequest)
+		action := user.ActionEditService
+		if req.NoOp {
+			action = user.ActionView
+		}
+		serviceID := req.ServiceID
+		if serviceID == "" {
+			serviceID = req.TenantID
+		}
+		return []user.AccessRequest{{Action: action, ServiceID: serviceID}}, nil
+	},
+	"Master.ClearEmergency": allow(user.ActionControlService, byServiceID),
+	"Master.RemoveIPs": allow(user.ActionEditService, func(args interface{}) user.AccessRequest {
+		if a := *args.(*[]string); len(a) > 0 {
+			return user.AccessRequest{ServiceID: a[0]}
+		}
+		return user.AccessRequest{}
+	}),
+	"Master.SetIPs": allow(user.ActionEditService, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{ServiceID: args.(*addressassignment.AssignmentRequest).ServiceID}
+	}),
+	"Master.AddServiceTemplate":    anywhere(user.ActionEditTemplate),
+	"Master.RemoveServiceTemplate": anywhere(user.ActionEditTemplate),
+	"Master.DeployTemplate": allow(user.ActionEditService, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{PoolID: args.(*servicetemplate.ServiceTemplateDeploymentRequest).PoolID}
+	}),
+	"Master.TemplateDiff": allow(user.ActionView, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{ServiceID: args.(*TemplateUpgradeRequest).TenantID}
+	}),
+	"Master.UpgradeTemplate": allow(user.ActionEditService, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{ServiceID: args.(*TemplateUpgradeRequest).TenantID}
+	}),
+	"Master.GetSessions":         anywhere(user.ActionViewAudit),
+	"Master.GetSession":          anywhere(user.ActionViewAudit),
+	"Master.GetSessionRecording": anywhere(user.ActionViewAudit),
+	"Master.GetUsers":            anywhere(user.ActionManageUsers),
+	"Master.AddUser":             anywhere(user.ActionManageUsers),
+	"Master.RemoveUser":          anywhere(user.ActionManageUsers),
+	"Master.SetUserRoles":        anywhere(user.ActionManageUsers),
+
+	"ControlCenter.AddService": allow(user.ActionEditService, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{ServiceID: args.(*service.Service).ParentServiceID}
+	}),
+	"ControlCenter.CloneService": allow(user.ActionEditService, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{ServiceID: args.(*dao.ServiceCloneRequest).ServiceID}
+	}),
+	"ControlCenter.DeployService": allow(user.ActionEditService, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{ServiceID: args.(*dao.ServiceDeploymentRequest).ParentID}
+	}),
+	"ControlCenter.UpdateService": allow(user.ActionEditService, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{ServiceID: args.(*service.Service).ID}
+	}),
+	"ControlCenter.MigrateServices": allow(user.ActionEditService, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{ServiceID: args.(*dao.ServiceMigrationRequest).ServiceID}
+	}),
+	"ControlCenter.RemoveService":    allow(user.ActionEditService, byServiceID),
+	"ControlCenter.StartService":     scheduleServices(user.ActionControlService),
+	"ControlCenter.RestartService":   scheduleServices(user.ActionControlService),
+	"ControlCenter.RebalanceService": scheduleServices(user.ActionControlService),
+	"ControlCenter.StopService":      scheduleServices(user.ActionControlService),
+	"ControlCenter.PauseService":     scheduleServices(user.ActionControlService),
+	"ControlCenter.StopRunningInstance": allow(user.ActionControlService, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{HostID: args.(*dao.HostServiceRequest).HostID}
+	}),
+	"ControlCenter.Action": allow(user.ActionControlService, func(args interface{}) user.AccessRequest {
+		if running := args.(*dao.AttachRequest).Running; running != nil {
+			return user.AccessRequest{ServiceID: running.ServiceID}
+		}
+		return user.AccessRequest{}
+	}),
+	"ControlCenter.AssignIPs": allow(user.ActionEditService, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{ServiceID: args.(*addressassignment.AssignmentRequest).ServiceID}
+	}),
+	"ControlCenter.Backup":       anywhere(user.ActionBackup),
+	"ControlCenter.AsyncBackup":  anywhere(user.ActionBackup),
+	"ControlCenter.Restore":      anywhere(user.ActionRestore),
+	"ControlCenter.AsyncRestore": anywhere(user.ActionRestore),
+	"ControlCenter.Snapshot": allow(user.ActionSnapshot, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{ServiceID: args.(*dao.SnapshotRequest).ServiceID}
+	}),
+	"ControlCenter.Rollback": allow(user.ActionSnapshot, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{ServiceID: snapshotTenantID(args.(*dao.RollbackRequest).SnapshotID)}
+	}),
+	"ControlCenter.DeleteSnapshot":  allow(user.ActionSnapshot, bySnapshotID),
+	"ControlCenter.DeleteSnapshots": allow(user.ActionSnapshot, byServiceID),
+	"ControlCenter.TagSnapshot": allow(user.ActionSnapshot, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{ServiceID: snapshotTenantID(args.(*dao.TagSnapshotRequest).SnapshotID)}
+	}),
+	"ControlCenter.RemoveSnapshotTag": allow(user.ActionSnapshot, func(args interface{}) user.AccessRequest {
+		return user.AccessRequest{ServiceID: args.(*dao.SnapshotByTagRequest).ServiceID}
+	}),
+	"ControlCenter.ResetRegistry":  anywhere(user.ActionEditTemplate),
+	"ControlCenter.RepairRegistry": anywhere(user.ActionEditTemplate),
+}
+
+// systemUserCall returns true if the call reads or checks the credentials of
+// the system user, which is a cluster administrator.  Only the master may make
+// these calls.
+func systemUserCall(serviceMethod string, args interface{}) bool {
+	switch serviceMethod {
+	case "Master.GetSystemUser":
+		return true
+	case "Master.LoginUser":
+		return args.(*LoginUserRequest).Name == facade.SYSTEM_USER_NAME
+	case "Master.ValidateCredentials":
+		return args.(*user.User).Name == facade.SYSTEM_USER_NAME
+	}
+	return false
+}
+
+// NewAuthorizer returns an rpcutils.Authorizer that enforces the roles of the
+// user on whose behalf a call is made.  Calls that need a user may be made
+// without one only by the master itself; other hosts must log the user in.
+func NewAuthorizer(f facade.FacadeInterface, masterHostID string) rpcutils.Authorizer {
+	return func(serviceMethod string, ident auth.Identity, args interface{}) error {
+		userName := auth.UserOf(ident)
+		identify(serviceMethod, ident, userName, args)
+
+		if hostID := ident.HostID(); hostID != "" && hostID != masterHostID && systemUserCall(serviceMethod, args) {
+			return ErrMasterOnly
+		}
+
+		rule, ok := accessRules[serviceMethod]
+		if !ok {
+			return nil
+		}
+		if userName == "" {
+			if hostID := ident.HostID(); hostID == "" || hostID == masterHostID {
+				return nil
+			}
+			if h, ok := args.(*host.Host); ok && serviceMethod == "Master.UpdateHost" && h.ID == ident.HostID() {
+				// delegates keep their own host record up to date
+				return nil
+			}
+			return ErrLoginRequired
+		}
+
+		ctx := datastore.Get()
+		reqs, err := rule(f, ctx, args)
+		if err != nil {
+			return err
+		}
+		for _, req := range reqs {
+			req.User = userName
+			if err := f.CheckAccess(ctx, req); err != nil {
+				return fmt.Errorf("user %s may not perform %s: %s", userName, req.Action, err)
+			}
+		}
+		return nil
+	}
+}
+
+// identify fills in the fields of a request that name the caller, so that
+// they cannot be claimed by the client
+func identify(serviceMethod string, ident auth.Identity, userName string, args interface{}) {
+	switch req := args.(type) {
+	case *LoginUserRequest:
+		req.HostID = ident.HostID()
+	case *user.AccessRequest:
+		if userName != "" {
+			req.User = userName
+		}
+	case *AcknowledgeAlertRequest:
+		if userName != "" {
+			req.UserName = userName
+		}
+	case *dao.BackupRequest:
+		if userName != "" {
+			req.Username = userName
+		}
+	case *dao.RestoreRequest:
+		if userName != "" {
+			req.Username = userName
+		}
+	}
+}
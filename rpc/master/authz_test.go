@@ -0,0 +1,155 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package master
+
+import (
+	"testing"
+
+	"github.com/control-center/serviced/auth"
+	authmocks "github.com/control-center/serviced/auth/mocks"
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/user"
+	"github.com/control-center/serviced/facade/mocks"
+	"github.com/stretchr/testify/mock"
+)
+
+// userIdent is a host identity acting on behalf of a user
+type userIdent struct {
+	*authmocks.Identity
+	user string
+}
+
+func (u userIdent) User() string {
+	return u.user
+}
+
+func hostIdent(hostID string) *authmocks.Identity {
+	ident := &authmocks.Identity{}
+	ident.On("HostID").Return(hostID)
+	return ident
+}
+
+func TestAuthorizer_WithoutUser(t *testing.T) {
+	f := &mocks.FacadeInterface{}
+	authz := NewAuthorizer(f, "masterhost")
+
+	// calls made by the hosts themselves need no user
+	if err := authz("Master.GetServicesHealth", hostIdent("delegate"), &struct{}{}); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	// the master may make any call
+	if err := authz("Master.AddUser", hostIdent("masterhost"), &user.User{Name: "bob"}); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	// delegates must log in a user first
+	if err := authz("Master.AddUser", hostIdent("delegate"), &user.User{Name: "bob"}); err != ErrLoginRequired {
+		t.Fatalf("Expected %v, got %v", ErrLoginRequired, err)
+	}
+
+	// but may update their own host
+	if err := authz("Master.UpdateHost", hostIdent("delegate"), &host.Host{ID: "delegate"}); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if err := authz("Master.UpdateHost", hostIdent("delegate"), &host.Host{ID: "other"}); err != ErrLoginRequired {
+		t.Fatalf("Expected %v, got %v", ErrLoginRequired, err)
+	}
+	f.AssertNotCalled(t, "CheckAccess", mock.Anything, mock.Anything)
+}
+
+func TestAuthorizer_SystemUser(t *testing.T) {
+	f := &mocks.FacadeInterface{}
+	authz := NewAuthorizer(f, "masterhost")
+	alice := userIdent{hostIdent("delegate"), "alice"}
+
+	// delegates may not read or use the credentials of the system user
+	for _, ident := range []auth.Identity{hostIdent("delegate"), alice} {
+		if err := authz("Master.GetSystemUser", ident, &struct{}{}); err != ErrMasterOnly {
+			t.Fatalf("Expected %v, got %v", ErrMasterOnly, err)
+		}
+	}
+	login := &LoginUserRequest{Name: "system_user", Password: "secret"}
+	if err := authz("Master.LoginUser", hostIdent("delegate"), login); err != ErrMasterOnly {
+		t.Fatalf("Expected %v, got %v", ErrMasterOnly, err)
+	}
+	if err := authz("Master.ValidateCredentials", hostIdent("delegate"), &user.User{Name: "system_user"}); err != ErrMasterOnly {
+		t.Fatalf("Expected %v, got %v", ErrMasterOnly, err)
+	}
+
+	// other users may still log in from delegates
+	if err := authz("Master.LoginUser", hostIdent("delegate"), &LoginUserRequest{Name: "alice"}); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if err := authz("Master.ValidateCredentials", hostIdent("delegate"), &user.User{Name: "alice"}); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	// and the master may use the system user
+	if err := authz("Master.GetSystemUser", hostIdent("masterhost"), &struct{}{}); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if err := authz("Master.LoginUser", hostIdent("masterhost"), &LoginUserRequest{Name: "system_user"}); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+}
+
+func TestAuthorizer_WithUser(t *testing.T) {
+	f := &mocks.FacadeInterface{}
+	authz := NewAuthorizer(f, "masterhost")
+	alice := userIdent{hostIdent("delegate"), "alice"}
+
+	f.On("CheckAccess", mock.Anything, user.AccessRequest{User: "alice", Action: user.ActionControlService, ServiceID: "svc1"}).Return(nil)
+	f.On("CheckAccess", mock.Anything, user.AccessRequest{User: "alice", Action: user.ActionControlService, ServiceID: "svc2"}).Return(user.ErrAccessDenied)
+	f.On("CheckAccess", mock.Anything, user.AccessRequest{User: "alice", Action: user.ActionManageUsers}).Return(user.ErrAccessDenied)
+
+	if err := authz("ControlCenter.StartService", alice, &dao.ScheduleServiceRequest{ServiceIDs: []string{"svc1"}}); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if err := authz("ControlCenter.StartService", alice, &dao.ScheduleServiceRequest{ServiceIDs: []string{"svc1", "svc2"}}); err == nil {
+		t.Fatalf("Expected an error starting svc2")
+	}
+
+	// the master is held to the roles of the user, too
+	admin := userIdent{hostIdent("masterhost"), "alice"}
+	if err := authz("Master.AddUser", admin, &user.User{Name: "bob"}); err == nil {
+		t.Fatalf("Expected an error adding a user")
+	}
+}
+
+func TestAuthorizer_Identify(t *testing.T) {
+	f := &mocks.FacadeInterface{}
+	authz := NewAuthorizer(f, "masterhost")
+	alice := userIdent{hostIdent("delegate"), "alice"}
+
+	login := &LoginUserRequest{Name: "alice", Password: "secret", HostID: "spoofed"}
+	if err := authz("Master.LoginUser", hostIdent("delegate"), login); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if login.HostID != "delegate" {
+		t.Fatalf("Expected login from host delegate, got %s", login.HostID)
+	}
+
+	f.On("CheckAccess", mock.Anything, user.AccessRequest{User: "alice", Action: user.ActionBackup}).Return(nil)
+	backup := &dao.BackupRequest{Username: "bob"}
+	if err := authz("ControlCenter.Backup", alice, backup); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if backup.Username != "alice" {
+		t.Fatalf("Expected backup by alice, got %s", backup.Username)
+	}
+}
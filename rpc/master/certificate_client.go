@@ -0,0 +1,37 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/certificate"
+)
+
+// GetCertificates returns the certificates of the virtual hosts and public
+// ports, without their private keys
+func (c *Client) GetCertificates() ([]certificate.Certificate, error) {
+	certs := []certificate.Certificate{}
+	err := c.call("GetCertificates", empty, &certs)
+	return certs, err
+}
+
+// AddCertificate adds or replaces the certificate of a hostname or port
+// address
+func (c *Client) AddCertificate(cert certificate.Certificate) error {
+	return c.call("AddCertificate", cert, nil)
+}
+
+// RemoveCertificate removes a certificate
+func (c *Client) RemoveCertificate(certificateID string) error {
+	return c.call("RemoveCertificate", certificateID, nil)
+}
@@ -0,0 +1,43 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/certificate"
+)
+
+// GetCertificates returns the certificates of the virtual hosts and public
+// ports, without their private keys
+func (s *Server) GetCertificates(_ struct{}, certs *[]certificate.Certificate) error {
+	result, err := s.f.GetCertificates(s.context())
+	if err != nil {
+		return err
+	}
+	*certs = make([]certificate.Certificate, len(result))
+	for i := range result {
+		(*certs)[i] = result[i].WithoutKey()
+	}
+	return nil
+}
+
+// AddCertificate adds or replaces the certificate of a hostname or port
+// address
+func (s *Server) AddCertificate(cert certificate.Certificate, _ *struct{}) error {
+	return s.f.AddCertificate(s.context(), &cert)
+}
+
+// RemoveCertificate removes a certificate
+func (s *Server) RemoveCertificate(certificateID string, _ *struct{}) error {
+	return s.f.RemoveCertificate(s.context(), certificateID)
+}
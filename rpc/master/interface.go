@@ -16,16 +16,23 @@ package master
 import (
 	"time"
 
+	"github.com/control-center/serviced/dfs"
 	"github.com/control-center/serviced/domain/addressassignment"
+	"github.com/control-center/serviced/domain/alert"
 	"github.com/control-center/serviced/domain/applicationendpoint"
+	"github.com/control-center/serviced/domain/auditlog"
+	"github.com/control-center/serviced/domain/certificate"
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/schedule"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/domain/session"
 	"github.com/control-center/serviced/domain/user"
 	"github.com/control-center/serviced/health"
 	"github.com/control-center/serviced/isvcs"
+	"github.com/control-center/serviced/scheduler/simulation"
 	"github.com/control-center/serviced/volume"
 )
 
@@ -102,6 +109,14 @@ type ClientInterface interface {
 	// RemoveVirtualIP removes a VirtualIP from a specific pool
 	RemoveVirtualIP(requestVirtualIP pool.VirtualIP) error
 
+	// SimulatePool returns where the scheduler would place instances in a
+	// pool after the simulated changes, without scheduling anything
+	SimulatePool(request SimulatePoolRequest) (*simulation.Plan, error)
+
+	// RebalancePool returns the instances that would be moved to rebalance a
+	// pool and, unless it is a dry run, asks the pool leader to move them
+	RebalancePool(request RebalancePoolRequest) (*simulation.Plan, error)
+
 	//--------------------------------------------------------------------------
 	// Service Management Functions
 
@@ -117,6 +132,9 @@ type ClientInterface interface {
 	// GetServiceDetailsByTenantID will return a list of ServiceDetails for the specified tenant ID
 	GetServiceDetailsByTenantID(tenantID string) ([]service.ServiceDetails, error)
 
+	// GetServiceList will return a service and all of its descendants
+	GetServiceList(serviceID string) ([]service.Service, error)
+
 	// GetServiceDetails will return a ServiceDetails for the specified service
 	GetServiceDetails(serviceID string) (*service.ServiceDetails, error)
 
@@ -163,6 +181,12 @@ type ClientInterface interface {
 	// Deploy an application template
 	DeployTemplate(request servicetemplate.ServiceTemplateDeploymentRequest) (tenantIDs []string, err error)
 
+	// Get the changes upgrading an application to a template would make
+	TemplateDiff(request TemplateUpgradeRequest) (*servicetemplate.TemplateDiff, error)
+
+	// Upgrade an application to a template
+	UpgradeTemplate(request TemplateUpgradeRequest) (*servicetemplate.TemplateDiff, error)
+
 	//--------------------------------------------------------------------------
 	// Volume Management Functions
 
@@ -206,6 +230,10 @@ type ClientInterface interface {
 
 	EnablePublicEndpointVHost(serviceid, endpointName, vhost string, isEnabled bool) error
 
+	SetPublicEndpointPortAccess(serviceid, endpointName, portAddr string, access *servicedefinition.AccessPolicy) error
+
+	SetPublicEndpointVHostAccess(serviceid, endpointName, vhost string, access *servicedefinition.AccessPolicy) error
+
 	GetAllPublicEndpoints() ([]service.PublicEndpoint, error)
 
 	//--------------------------------------------------------------------------
@@ -217,6 +245,105 @@ type ClientInterface interface {
 	// Validate the credentials of the specified user
 	ValidateCredentials(user user.User) (bool, error)
 
+	// GetUsers returns all stored users, without their passwords
+	GetUsers() ([]user.User, error)
+
+	// AddUser adds a new user
+	AddUser(user user.User) error
+
+	// RemoveUser removes a user
+	RemoveUser(userName string) error
+
+	// SetUserRoles replaces the roles granted to a user
+	SetUserRoles(userName string, roles user.RoleBindings) error
+
+	// CheckAccess returns an error if the user may not perform the requested
+	// action
+	CheckAccess(req user.AccessRequest) error
+
+	// LoginUser validates the credentials of a user and returns a token that
+	// identifies the user on calls from this host
+	LoginUser(name, password string) (string, error)
+
+	//--------------------------------------------------------------------------
+	// Alert Management Functions
+
+	// GetAlerts returns the active alerts, or every alert if all is set
+	GetAlerts(all bool) ([]alert.Alert, error)
+
+	// GetAlert returns an alert by its id
+	GetAlert(alertID string) (*alert.Alert, error)
+
+	// AcknowledgeAlert acknowledges an active alert on behalf of a user
+	AcknowledgeAlert(alertID, userName string) error
+
+	//--------------------------------------------------------------------------
+	// Schedule Management Functions
+
+	// GetSchedules returns the snapshot and backup schedules
+	GetSchedules() ([]schedule.Schedule, error)
+
+	// GetSchedule returns a schedule by its id
+	GetSchedule(scheduleID string) (*schedule.Schedule, error)
+
+	// AddSchedule adds a snapshot or backup schedule and returns its id
+	AddSchedule(s schedule.Schedule) (string, error)
+
+	// UpdateSchedule updates a schedule, keeping its status
+	UpdateSchedule(s schedule.Schedule) error
+
+	// RemoveSchedule removes a schedule
+	RemoveSchedule(scheduleID string) error
+
+	//--------------------------------------------------------------------------
+	// Certificate Management Functions
+
+	// GetCertificates returns the certificates of the virtual hosts and public
+	// ports, without their private keys
+	GetCertificates() ([]certificate.Certificate, error)
+
+	// AddCertificate adds or replaces the certificate of a hostname or port
+	// address
+	AddCertificate(cert certificate.Certificate) error
+
+	// RemoveCertificate removes a certificate
+	RemoveCertificate(certificateID string) error
+
+	//--------------------------------------------------------------------------
+	// Audit Trail Functions
+
+	// GetAuditEvents returns the recorded audit events selected by the
+	// filter, newest first
+	GetAuditEvents(filter auditlog.Filter) ([]auditlog.Event, error)
+
+	//--------------------------------------------------------------------------
+	// Shell Session Functions
+
+	// AddSession stores a recorded shell session and its recording, and
+	// returns the id of the session
+	AddSession(sess session.Session, recording []byte) (string, error)
+
+	// GetSessions returns the recorded shell sessions selected by the
+	// filter, most recently started first
+	GetSessions(filter session.Filter) ([]session.Session, error)
+
+	// GetSession returns a recorded shell session
+	GetSession(sessionID string) (*session.Session, error)
+
+	// GetSessionRecording returns the asciicast recording of a shell session
+	GetSessionRecording(sessionID string) ([]byte, error)
+
+	// SessionRecordingRequired returns whether shell sessions on a service
+	// must be recorded
+	SessionRecordingRequired(serviceID string) (bool, error)
+
+	//--------------------------------------------------------------------------
+	// Backup Management Functions
+
+	// VerifyBackup checks a backup file on the master against its recorded
+	// checksums
+	VerifyBackup(filename string) (*dfs.BackupVerification, error)
+
 	//--------------------------------------------------------------------------
 	// Healthcheck Management Functions
 
@@ -1,25 +1,46 @@
 package mocks
 
 import applicationendpoint "github.com/control-center/serviced/domain/applicationendpoint"
+import auditlog "github.com/control-center/serviced/domain/auditlog"
+import certificate "github.com/control-center/serviced/domain/certificate"
 import health "github.com/control-center/serviced/health"
 import host "github.com/control-center/serviced/domain/host"
 import isvcs "github.com/control-center/serviced/isvcs"
 import master "github.com/control-center/serviced/rpc/master"
 import mock "github.com/stretchr/testify/mock"
 import pool "github.com/control-center/serviced/domain/pool"
+import schedule "github.com/control-center/serviced/domain/schedule"
 import service "github.com/control-center/serviced/domain/service"
 import servicedefinition "github.com/control-center/serviced/domain/servicedefinition"
 import servicetemplate "github.com/control-center/serviced/domain/servicetemplate"
+import session "github.com/control-center/serviced/domain/session"
+import simulation "github.com/control-center/serviced/scheduler/simulation"
 import time "time"
 import user "github.com/control-center/serviced/domain/user"
 import volume "github.com/control-center/serviced/volume"
 import addressassignment "github.com/control-center/serviced/domain/addressassignment"
+import alert "github.com/control-center/serviced/domain/alert"
+import dfs "github.com/control-center/serviced/dfs"
 
 // ClientInterface is an autogenerated mock type for the ClientInterface type
 type ClientInterface struct {
 	mock.Mock
 }
 
+// AcknowledgeAlert provides a mock function with given fields: alertID, userName
+func (_m *ClientInterface) AcknowledgeAlert(alertID string, userName string) error {
+	ret := _m.Called(alertID, userName)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(alertID, userName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddHost provides a mock function with given fields: h
 func (_m *ClientInterface) AddHost(h host.Host) ([]byte, error) {
 	ret := _m.Called(h)
@@ -126,6 +147,27 @@ func (_m *ClientInterface) AddResourcePool(p pool.ResourcePool) error {
 	return r0
 }
 
+// AddSchedule provides a mock function with given fields: s
+func (_m *ClientInterface) AddSchedule(s schedule.Schedule) (string, error) {
+	ret := _m.Called(s)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(schedule.Schedule) string); ok {
+		r0 = rf(s)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(schedule.Schedule) error); ok {
+		r1 = rf(s)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // AddServiceTemplate provides a mock function with given fields: serviceTemplate
 func (_m *ClientInterface) AddServiceTemplate(serviceTemplate servicetemplate.ServiceTemplate) (string, error) {
 	ret := _m.Called(serviceTemplate)
@@ -147,6 +189,20 @@ func (_m *ClientInterface) AddServiceTemplate(serviceTemplate servicetemplate.Se
 	return r0, r1
 }
 
+// AddUser provides a mock function with given fields: _a0
+func (_m *ClientInterface) AddUser(_a0 user.User) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(user.User) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddVirtualIP provides a mock function with given fields: requestVirtualIP
 func (_m *ClientInterface) AddVirtualIP(requestVirtualIP pool.VirtualIP) error {
 	ret := _m.Called(requestVirtualIP)
@@ -189,6 +245,20 @@ func (_m *ClientInterface) AuthenticateHost(hostID string) (string, int64, error
 	return r0, r1, r2
 }
 
+// CheckAccess provides a mock function with given fields: req
+func (_m *ClientInterface) CheckAccess(req user.AccessRequest) error {
+	ret := _m.Called(req)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(user.AccessRequest) error); ok {
+		r0 = rf(req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ClearEmergency provides a mock function with given fields: serviceID
 func (_m *ClientInterface) ClearEmergency(serviceID string) (int, error) {
 	ret := _m.Called(serviceID)
@@ -289,6 +359,52 @@ func (_m *ClientInterface) DeployTemplate(request servicetemplate.ServiceTemplat
 	return r0, r1
 }
 
+// TemplateDiff provides a mock function with given fields: request
+func (_m *ClientInterface) TemplateDiff(request master.TemplateUpgradeRequest) (*servicetemplate.TemplateDiff, error) {
+	ret := _m.Called(request)
+
+	var r0 *servicetemplate.TemplateDiff
+	if rf, ok := ret.Get(0).(func(master.TemplateUpgradeRequest) *servicetemplate.TemplateDiff); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*servicetemplate.TemplateDiff)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(master.TemplateUpgradeRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpgradeTemplate provides a mock function with given fields: request
+func (_m *ClientInterface) UpgradeTemplate(request master.TemplateUpgradeRequest) (*servicetemplate.TemplateDiff, error) {
+	ret := _m.Called(request)
+
+	var r0 *servicetemplate.TemplateDiff
+	if rf, ok := ret.Get(0).(func(master.TemplateUpgradeRequest) *servicetemplate.TemplateDiff); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*servicetemplate.TemplateDiff)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(master.TemplateUpgradeRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DockerOverride provides a mock function with given fields: newImage, oldImage
 func (_m *ClientInterface) DockerOverride(newImage string, oldImage string) error {
 	ret := _m.Called(newImage, oldImage)
@@ -331,6 +447,34 @@ func (_m *ClientInterface) EnablePublicEndpointVHost(serviceid string, endpointN
 	return r0
 }
 
+// SetPublicEndpointPortAccess provides a mock function with given fields: serviceid, endpointName, portAddr, access
+func (_m *ClientInterface) SetPublicEndpointPortAccess(serviceid string, endpointName string, portAddr string, access *servicedefinition.AccessPolicy) error {
+	ret := _m.Called(serviceid, endpointName, portAddr, access)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, *servicedefinition.AccessPolicy) error); ok {
+		r0 = rf(serviceid, endpointName, portAddr, access)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPublicEndpointVHostAccess provides a mock function with given fields: serviceid, endpointName, vhost, access
+func (_m *ClientInterface) SetPublicEndpointVHostAccess(serviceid string, endpointName string, vhost string, access *servicedefinition.AccessPolicy) error {
+	ret := _m.Called(serviceid, endpointName, vhost, access)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, *servicedefinition.AccessPolicy) error); ok {
+		r0 = rf(serviceid, endpointName, vhost, access)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindHostsInPool provides a mock function with given fields: poolID
 func (_m *ClientInterface) FindHostsInPool(poolID string) ([]host.Host, error) {
 	ret := _m.Called(poolID)
@@ -377,6 +521,52 @@ func (_m *ClientInterface) GetActiveHostIDs() ([]string, error) {
 	return r0, r1
 }
 
+// GetAlert provides a mock function with given fields: alertID
+func (_m *ClientInterface) GetAlert(alertID string) (*alert.Alert, error) {
+	ret := _m.Called(alertID)
+
+	var r0 *alert.Alert
+	if rf, ok := ret.Get(0).(func(string) *alert.Alert); ok {
+		r0 = rf(alertID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*alert.Alert)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(alertID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAlerts provides a mock function with given fields: all
+func (_m *ClientInterface) GetAlerts(all bool) ([]alert.Alert, error) {
+	ret := _m.Called(all)
+
+	var r0 []alert.Alert
+	if rf, ok := ret.Get(0).(func(bool) []alert.Alert); ok {
+		r0 = rf(all)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]alert.Alert)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(bool) error); ok {
+		r1 = rf(all)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAllPublicEndpoints provides a mock function with given fields:
 func (_m *ClientInterface) GetAllPublicEndpoints() ([]service.PublicEndpoint, error) {
 	ret := _m.Called()
@@ -621,6 +811,52 @@ func (_m *ClientInterface) GetResourcePools() ([]pool.ResourcePool, error) {
 	return r0, r1
 }
 
+// GetSchedule provides a mock function with given fields: scheduleID
+func (_m *ClientInterface) GetSchedule(scheduleID string) (*schedule.Schedule, error) {
+	ret := _m.Called(scheduleID)
+
+	var r0 *schedule.Schedule
+	if rf, ok := ret.Get(0).(func(string) *schedule.Schedule); ok {
+		r0 = rf(scheduleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*schedule.Schedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(scheduleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSchedules provides a mock function with given fields:
+func (_m *ClientInterface) GetSchedules() ([]schedule.Schedule, error) {
+	ret := _m.Called()
+
+	var r0 []schedule.Schedule
+	if rf, ok := ret.Get(0).(func() []schedule.Schedule); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]schedule.Schedule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetServiceDetails provides a mock function with given fields: serviceID
 func (_m *ClientInterface) GetServiceDetails(serviceID string) (*service.ServiceDetails, error) {
 	ret := _m.Called(serviceID)
@@ -713,6 +949,29 @@ func (_m *ClientInterface) GetServiceInstances(serviceID string) ([]service.Inst
 	return r0, r1
 }
 
+// GetServiceList provides a mock function with given fields: serviceID
+func (_m *ClientInterface) GetServiceList(serviceID string) ([]service.Service, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 []service.Service
+	if rf, ok := ret.Get(0).(func(string) []service.Service); ok {
+		r0 = rf(serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.Service)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetServiceTemplates provides a mock function with given fields:
 func (_m *ClientInterface) GetServiceTemplates() (map[string]servicetemplate.ServiceTemplate, error) {
 	ret := _m.Called()
@@ -801,6 +1060,29 @@ func (_m *ClientInterface) GetTenantID(serviceID string) (string, error) {
 	return r0, r1
 }
 
+// GetUsers provides a mock function with given fields:
+func (_m *ClientInterface) GetUsers() ([]user.User, error) {
+	ret := _m.Called()
+
+	var r0 []user.User
+	if rf, ok := ret.Get(0).(func() []user.User); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]user.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetVolumeStatus provides a mock function with given fields:
 func (_m *ClientInterface) GetVolumeStatus() (*volume.Statuses, error) {
 	ret := _m.Called()
@@ -847,6 +1129,27 @@ func (_m *ClientInterface) HostsAuthenticated(hostIDs []string) (map[string]bool
 	return r0, r1
 }
 
+// LoginUser provides a mock function with given fields: name, password
+func (_m *ClientInterface) LoginUser(name string, password string) (string, error) {
+	ret := _m.Called(name, password)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(name, password)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(name, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // LocateServiceInstance provides a mock function with given fields: serviceID, instanceID
 func (_m *ClientInterface) LocateServiceInstance(serviceID string, instanceID int) (*service.LocationInstance, error) {
 	ret := _m.Called(serviceID, instanceID)
@@ -870,6 +1173,29 @@ func (_m *ClientInterface) LocateServiceInstance(serviceID string, instanceID in
 	return r0, r1
 }
 
+// RebalancePool provides a mock function with given fields: request
+func (_m *ClientInterface) RebalancePool(request master.RebalancePoolRequest) (*simulation.Plan, error) {
+	ret := _m.Called(request)
+
+	var r0 *simulation.Plan
+	if rf, ok := ret.Get(0).(func(master.RebalancePoolRequest) *simulation.Plan); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*simulation.Plan)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(master.RebalancePoolRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RemoveHost provides a mock function with given fields: hostID
 func (_m *ClientInterface) RemoveHost(hostID string) error {
 	ret := _m.Called(hostID)
@@ -926,6 +1252,205 @@ func (_m *ClientInterface) RemoveResourcePool(poolID string) error {
 	return r0
 }
 
+// RemoveSchedule provides a mock function with given fields: scheduleID
+func (_m *ClientInterface) RemoveSchedule(scheduleID string) error {
+	ret := _m.Called(scheduleID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(scheduleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetCertificates provides a mock function with given fields:
+func (_m *ClientInterface) GetCertificates() ([]certificate.Certificate, error) {
+	ret := _m.Called()
+
+	var r0 []certificate.Certificate
+	if rf, ok := ret.Get(0).(func() []certificate.Certificate); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]certificate.Certificate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddCertificate provides a mock function with given fields: cert
+func (_m *ClientInterface) AddCertificate(cert certificate.Certificate) error {
+	ret := _m.Called(cert)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(certificate.Certificate) error); ok {
+		r0 = rf(cert)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveCertificate provides a mock function with given fields: certificateID
+func (_m *ClientInterface) RemoveCertificate(certificateID string) error {
+	ret := _m.Called(certificateID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(certificateID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAuditEvents provides a mock function with given fields: filter
+func (_m *ClientInterface) GetAuditEvents(filter auditlog.Filter) ([]auditlog.Event, error) {
+	ret := _m.Called(filter)
+
+	var r0 []auditlog.Event
+	if rf, ok := ret.Get(0).(func(auditlog.Filter) []auditlog.Event); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]auditlog.Event)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(auditlog.Filter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddSession provides a mock function with given fields: sess, recording
+func (_m *ClientInterface) AddSession(sess session.Session, recording []byte) (string, error) {
+	ret := _m.Called(sess, recording)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(session.Session, []byte) string); ok {
+		r0 = rf(sess, recording)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(session.Session, []byte) error); ok {
+		r1 = rf(sess, recording)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSessions provides a mock function with given fields: filter
+func (_m *ClientInterface) GetSessions(filter session.Filter) ([]session.Session, error) {
+	ret := _m.Called(filter)
+
+	var r0 []session.Session
+	if rf, ok := ret.Get(0).(func(session.Filter) []session.Session); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]session.Session)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(session.Filter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSession provides a mock function with given fields: sessionID
+func (_m *ClientInterface) GetSession(sessionID string) (*session.Session, error) {
+	ret := _m.Called(sessionID)
+
+	var r0 *session.Session
+	if rf, ok := ret.Get(0).(func(string) *session.Session); ok {
+		r0 = rf(sessionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*session.Session)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSessionRecording provides a mock function with given fields: sessionID
+func (_m *ClientInterface) GetSessionRecording(sessionID string) ([]byte, error) {
+	ret := _m.Called(sessionID)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(string) []byte); ok {
+		r0 = rf(sessionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SessionRecordingRequired provides a mock function with given fields: serviceID
+func (_m *ClientInterface) SessionRecordingRequired(serviceID string) (bool, error) {
+	ret := _m.Called(serviceID)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(serviceID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RemoveServiceTemplate provides a mock function with given fields: serviceTemplateID
 func (_m *ClientInterface) RemoveServiceTemplate(serviceTemplateID string) error {
 	ret := _m.Called(serviceTemplateID)
@@ -940,6 +1465,20 @@ func (_m *ClientInterface) RemoveServiceTemplate(serviceTemplateID string) error
 	return r0
 }
 
+// RemoveUser provides a mock function with given fields: userName
+func (_m *ClientInterface) RemoveUser(userName string) error {
+	ret := _m.Called(userName)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(userName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RemoveVirtualIP provides a mock function with given fields: requestVirtualIP
 func (_m *ClientInterface) RemoveVirtualIP(requestVirtualIP pool.VirtualIP) error {
 	ret := _m.Called(requestVirtualIP)
@@ -1077,6 +1616,43 @@ func (_m *ClientInterface) ServiceUse(tenantID string, serviceID string, imageID
 	return r0, r1
 }
 
+// SetUserRoles provides a mock function with given fields: userName, roles
+func (_m *ClientInterface) SetUserRoles(userName string, roles user.RoleBindings) error {
+	ret := _m.Called(userName, roles)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, user.RoleBindings) error); ok {
+		r0 = rf(userName, roles)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SimulatePool provides a mock function with given fields: request
+func (_m *ClientInterface) SimulatePool(request master.SimulatePoolRequest) (*simulation.Plan, error) {
+	ret := _m.Called(request)
+
+	var r0 *simulation.Plan
+	if rf, ok := ret.Get(0).(func(master.SimulatePoolRequest) *simulation.Plan); ok {
+		r0 = rf(request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*simulation.Plan)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(master.SimulatePoolRequest) error); ok {
+		r1 = rf(request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // StopServiceInstance provides a mock function with given fields: serviceID, instanceID
 func (_m *ClientInterface) StopServiceInstance(serviceID string, instanceID int) error {
 	ret := _m.Called(serviceID, instanceID)
@@ -1133,6 +1709,20 @@ func (_m *ClientInterface) UpdateResourcePool(p pool.ResourcePool) error {
 	return r0
 }
 
+// UpdateSchedule provides a mock function with given fields: s
+func (_m *ClientInterface) UpdateSchedule(s schedule.Schedule) error {
+	ret := _m.Called(s)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(schedule.Schedule) error); ok {
+		r0 = rf(s)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpgradeRegistry provides a mock function with given fields: endpoint, override
 func (_m *ClientInterface) UpgradeRegistry(endpoint string, override bool) error {
 	ret := _m.Called(endpoint, override)
@@ -1168,6 +1758,29 @@ func (_m *ClientInterface) ValidateCredentials(_a0 user.User) (bool, error) {
 	return r0, r1
 }
 
+// VerifyBackup provides a mock function with given fields: filename
+func (_m *ClientInterface) VerifyBackup(filename string) (*dfs.BackupVerification, error) {
+	ret := _m.Called(filename)
+
+	var r0 *dfs.BackupVerification
+	if rf, ok := ret.Get(0).(func(string) *dfs.BackupVerification); ok {
+		r0 = rf(filename)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dfs.BackupVerification)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(filename)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // WaitService provides a mock function with given fields: serviceIDs, state, timeout, recursive
 func (_m *ClientInterface) WaitService(serviceIDs []string, state service.DesiredState, timeout time.Duration, recursive bool) error {
 	ret := _m.Called(serviceIDs, state, timeout, recursive)
@@ -15,6 +15,7 @@ package master
 
 import (
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/scheduler/simulation"
 )
 
 //GetResourcePool gets the pool for the given poolID or nil
@@ -68,3 +69,23 @@ func (c *Client) AddVirtualIP(requestVirtualIP pool.VirtualIP) error {
 func (c *Client) RemoveVirtualIP(requestVirtualIP pool.VirtualIP) error {
 	return c.call("RemoveVirtualIP", requestVirtualIP, nil)
 }
+
+// SimulatePool returns where the scheduler would place instances in a pool
+// after the simulated changes, without scheduling anything
+func (c *Client) SimulatePool(request SimulatePoolRequest) (*simulation.Plan, error) {
+	response := &simulation.Plan{}
+	if err := c.call("SimulatePool", request, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// RebalancePool returns the instances that would be moved to rebalance a
+// pool and, unless it is a dry run, asks the pool leader to move them
+func (c *Client) RebalancePool(request RebalancePoolRequest) (*simulation.Plan, error) {
+	response := &simulation.Plan{}
+	if err := c.call("RebalancePool", request, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
@@ -17,8 +17,23 @@ import (
 	"errors"
 
 	"github.com/control-center/serviced/domain/pool"
+	"github.com/control-center/serviced/domain/servicetemplate"
+	"github.com/control-center/serviced/scheduler/simulation"
 )
 
+// SimulatePoolRequest is the request object for SimulatePool
+type SimulatePoolRequest struct {
+	PoolID   string
+	Changes  simulation.Changes
+	Template *servicetemplate.ServiceTemplate // template to deploy, if any
+}
+
+// RebalancePoolRequest is the request object for RebalancePool
+type RebalancePoolRequest struct {
+	PoolID string
+	DryRun bool // only report the instances that would be moved
+}
+
 // GetResourcePools returns all ResourcePools
 func (s *Server) GetResourcePools(empty struct{}, poolsReply *[]pool.ResourcePool) error {
 	pools, err := s.f.GetResourcePools(s.context())
@@ -80,3 +95,24 @@ func (s *Server) AddVirtualIP(requestVirtualIP pool.VirtualIP, _ *struct{}) erro
 func (s *Server) RemoveVirtualIP(requestVirtualIP pool.VirtualIP, _ *struct{}) error {
 	return s.f.RemoveVirtualIP(s.context(), requestVirtualIP)
 }
+
+// SimulatePool returns where the scheduler would place instances in a pool
+func (s *Server) SimulatePool(request SimulatePoolRequest, reply *simulation.Plan) error {
+	plan, err := s.f.SimulatePool(s.context(), request.PoolID, request.Changes, request.Template)
+	if err != nil {
+		return err
+	}
+	*reply = *plan
+	return nil
+}
+
+// RebalancePool returns the instances that would be moved to rebalance a
+// pool and, unless it is a dry run, asks the pool leader to move them
+func (s *Server) RebalancePool(request RebalancePoolRequest, reply *simulation.Plan) error {
+	plan, err := s.f.RebalancePool(s.context(), request.PoolID, request.DryRun)
+	if err != nil {
+		return err
+	}
+	*reply = *plan
+	return nil
+}
@@ -92,6 +92,28 @@ func (c *Client) EnablePublicEndpointVHost(serviceid, endpointName, vhost string
 	return c.call("EnablePublicEndpointVHost", request, nil)
 }
 
+// Set the access policy of a port public endpoint for a service.
+func (c *Client) SetPublicEndpointPortAccess(serviceid, endpointName, portAddr string, access *servicedefinition.AccessPolicy) error {
+	request := &PublicEndpointRequest{
+		Serviceid:    serviceid,
+		EndpointName: endpointName,
+		Name:         portAddr,
+		Access:       access,
+	}
+	return c.call("SetPublicEndpointPortAccess", request, nil)
+}
+
+// Set the access policy of a vhost public endpoint for a service.
+func (c *Client) SetPublicEndpointVHostAccess(serviceid, endpointName, vhost string, access *servicedefinition.AccessPolicy) error {
+	request := &PublicEndpointRequest{
+		Serviceid:    serviceid,
+		EndpointName: endpointName,
+		Name:         vhost,
+		Access:       access,
+	}
+	return c.call("SetPublicEndpointVHostAccess", request, nil)
+}
+
 // GetAllPublicEndpoints
 func (c *Client) GetAllPublicEndpoints() ([]service.PublicEndpoint, error) {
 	var response []service.PublicEndpoint
@@ -27,6 +27,7 @@ type PublicEndpointRequest struct {
 	Protocol     string
 	IsEnabled    bool
 	Restart      bool
+	Access       *servicedefinition.AccessPolicy
 }
 
 // Adds a port public endpoint to a service.
@@ -71,6 +72,16 @@ func (s *Server) EnablePublicEndpointVHost(request *PublicEndpointRequest, _ *st
 	return s.f.EnablePublicEndpointVHost(s.context(), request.Serviceid, request.EndpointName, request.Name, request.IsEnabled)
 }
 
+// Set the access policy of a port public endpoint for a service.
+func (s *Server) SetPublicEndpointPortAccess(request *PublicEndpointRequest, _ *struct{}) error {
+	return s.f.SetPublicEndpointPortAccess(s.context(), request.Serviceid, request.EndpointName, request.Name, request.Access)
+}
+
+// Set the access policy of a vhost public endpoint for a service.
+func (s *Server) SetPublicEndpointVHostAccess(request *PublicEndpointRequest, _ *struct{}) error {
+	return s.f.SetPublicEndpointVHostAccess(s.context(), request.Serviceid, request.EndpointName, request.Name, request.Access)
+}
+
 // GetAllPublicEndpoints get all public endpoints
 func (s *Server) GetAllPublicEndpoints(empty struct{}, publicEndpoints *[]service.PublicEndpoint) error {
 	peps, err := s.f.GetAllPublicEndpoints(s.context())
@@ -0,0 +1,51 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/schedule"
+)
+
+// GetSchedules returns the snapshot and backup schedules
+func (c *Client) GetSchedules() ([]schedule.Schedule, error) {
+	schedules := []schedule.Schedule{}
+	err := c.call("GetSchedules", empty, &schedules)
+	return schedules, err
+}
+
+// GetSchedule returns a schedule by its id
+func (c *Client) GetSchedule(scheduleID string) (*schedule.Schedule, error) {
+	s := &schedule.Schedule{}
+	if err := c.call("GetSchedule", scheduleID, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// AddSchedule adds a snapshot or backup schedule and returns its id
+func (c *Client) AddSchedule(s schedule.Schedule) (string, error) {
+	var scheduleID string
+	err := c.call("AddSchedule", s, &scheduleID)
+	return scheduleID, err
+}
+
+// UpdateSchedule updates a schedule, keeping its status
+func (c *Client) UpdateSchedule(s schedule.Schedule) error {
+	return c.call("UpdateSchedule", s, nil)
+}
+
+// RemoveSchedule removes a schedule
+func (c *Client) RemoveSchedule(scheduleID string) error {
+	return c.call("RemoveSchedule", scheduleID, nil)
+}
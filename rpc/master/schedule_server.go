@@ -0,0 +1,57 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/schedule"
+)
+
+// GetSchedules returns the snapshot and backup schedules
+func (s *Server) GetSchedules(_ struct{}, schedules *[]schedule.Schedule) error {
+	result, err := s.f.GetSchedules(s.context())
+	if err != nil {
+		return err
+	}
+	*schedules = result
+	return nil
+}
+
+// GetSchedule returns a schedule by its id
+func (s *Server) GetSchedule(scheduleID string, sched *schedule.Schedule) error {
+	result, err := s.f.GetSchedule(s.context(), scheduleID)
+	if err != nil {
+		return err
+	}
+	*sched = *result
+	return nil
+}
+
+// AddSchedule adds a snapshot or backup schedule and returns its id
+func (s *Server) AddSchedule(sched schedule.Schedule, scheduleID *string) error {
+	if err := s.f.AddSchedule(s.context(), &sched); err != nil {
+		return err
+	}
+	*scheduleID = sched.ID
+	return nil
+}
+
+// UpdateSchedule updates a schedule, keeping its status
+func (s *Server) UpdateSchedule(sched schedule.Schedule, _ *struct{}) error {
+	return s.f.UpdateSchedule(s.context(), &sched)
+}
+
+// RemoveSchedule removes a schedule
+func (s *Server) RemoveSchedule(scheduleID string, _ *struct{}) error {
+	return s.f.RemoveSchedule(s.context(), scheduleID)
+}
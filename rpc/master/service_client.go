@@ -64,6 +64,13 @@ func (c *Client) GetServiceDetailsByTenantID(tenantID string) ([]service.Service
 	return svcs, err
 }
 
+// GetServiceList will return a service and all of its descendants
+func (c *Client) GetServiceList(serviceID string) ([]service.Service, error) {
+	svcs := []service.Service{}
+	err := c.call("GetServiceList", serviceID, &svcs)
+	return svcs, err
+}
+
 // GetServiceDetails will return a ServiceDetails for the specified service
 func (c *Client) GetServiceDetails(serviceID string) (*service.ServiceDetails, error) {
 	svc := &service.ServiceDetails{}
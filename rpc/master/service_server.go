@@ -102,6 +102,19 @@ func (s *Server) GetServiceDetailsByTenantID(tenantID string, response *[]servic
 	return nil
 }
 
+// GetServiceList will return a service and all of its descendants
+func (s *Server) GetServiceList(serviceID string, response *[]service.Service) error {
+	svcs, err := s.f.GetServiceList(s.context(), serviceID)
+	if err != nil {
+		return err
+	}
+	*response = make([]service.Service, len(svcs))
+	for i, svc := range svcs {
+		(*response)[i] = *svc
+	}
+	return nil
+}
+
 // Get a specific service
 func (s *Server) GetService(serviceID string, svc *service.Service) error {
 	sv, err := s.f.GetService(s.context(), serviceID)
@@ -54,3 +54,20 @@ func (c *Client) DeployTemplate(request servicetemplate.ServiceTemplateDeploymen
 
 }
 
+// TemplateDiff returns the changes upgrading an application to a template would make
+func (c *Client) TemplateDiff(request TemplateUpgradeRequest) (*servicetemplate.TemplateDiff, error) {
+	response := &servicetemplate.TemplateDiff{}
+	if err := c.call("TemplateDiff", request, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// UpgradeTemplate upgrades an application to a template
+func (c *Client) UpgradeTemplate(request TemplateUpgradeRequest) (*servicetemplate.TemplateDiff, error) {
+	response := &servicetemplate.TemplateDiff{}
+	if err := c.call("UpgradeTemplate", request, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
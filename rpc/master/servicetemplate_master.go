@@ -17,6 +17,14 @@ import (
 	"github.com/control-center/serviced/domain/servicetemplate"
 )
 
+// TemplateUpgradeRequest is the request object for TemplateDiff and
+// UpgradeTemplate
+type TemplateUpgradeRequest struct {
+	TenantID             string
+	TemplateID           string
+	SnapshotSpacePercent int
+}
+
 // Add a new service template
 func (s *Server) AddServiceTemplate(serviceTemplate servicetemplate.ServiceTemplate, response *string) error  {
 	reloadLogstashConfig := true
@@ -52,3 +60,23 @@ func (s *Server) DeployTemplate(request servicetemplate.ServiceTemplateDeploymen
 	*response = tenantIDs
 	return nil
 }
+
+// TemplateDiff returns the changes upgrading an application to a template would make
+func (s *Server) TemplateDiff(request TemplateUpgradeRequest, response *servicetemplate.TemplateDiff) error {
+	diff, err := s.f.TemplateDiff(s.context(), request.TenantID, request.TemplateID)
+	if err != nil {
+		return err
+	}
+	*response = *diff
+	return nil
+}
+
+// UpgradeTemplate upgrades an application to a template
+func (s *Server) UpgradeTemplate(request TemplateUpgradeRequest, response *servicetemplate.TemplateDiff) error {
+	diff, err := s.f.UpgradeTemplate(s.context(), request.TenantID, request.TemplateID, request.SnapshotSpacePercent)
+	if err != nil {
+		return err
+	}
+	*response = *diff
+	return nil
+}
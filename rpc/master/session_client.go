@@ -0,0 +1,58 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/session"
+)
+
+// AddSession stores a recorded shell session and its recording, and returns
+// the id of the session
+func (c *Client) AddSession(sess session.Session, recording []byte) (string, error) {
+	var sessionID string
+	err := c.call("AddSession", AddSessionRequest{Session: sess, Recording: recording}, &sessionID)
+	return sessionID, err
+}
+
+// GetSessions returns the recorded shell sessions selected by the filter,
+// most recently started first
+func (c *Client) GetSessions(filter session.Filter) ([]session.Session, error) {
+	sessions := []session.Session{}
+	err := c.call("GetSessions", filter, &sessions)
+	return sessions, err
+}
+
+// GetSession returns a recorded shell session
+func (c *Client) GetSession(sessionID string) (*session.Session, error) {
+	sess := &session.Session{}
+	if err := c.call("GetSession", sessionID, sess); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// GetSessionRecording returns the asciicast recording of a shell session
+func (c *Client) GetSessionRecording(sessionID string) ([]byte, error) {
+	var recording []byte
+	err := c.call("GetSessionRecording", sessionID, &recording)
+	return recording, err
+}
+
+// SessionRecordingRequired returns whether shell sessions on a service must
+// be recorded
+func (c *Client) SessionRecordingRequired(serviceID string) (bool, error) {
+	var required bool
+	err := c.call("SessionRecordingRequired", serviceID, &required)
+	return required, err
+}
@@ -0,0 +1,77 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package master
+
+import (
+	"github.com/control-center/serviced/domain/session"
+)
+
+// AddSessionRequest is a recorded shell session to store
+type AddSessionRequest struct {
+	Session   session.Session
+	Recording []byte
+}
+
+// AddSession stores a recorded shell session and its recording, and returns
+// the id of the session
+func (s *Server) AddSession(request AddSessionRequest, sessionID *string) error {
+	sess := request.Session
+	if err := s.f.AddSession(s.context(), &sess, request.Recording); err != nil {
+		return err
+	}
+	*sessionID = sess.ID
+	return nil
+}
+
+// GetSessions returns the recorded shell sessions selected by the filter,
+// most recently started first
+func (s *Server) GetSessions(filter session.Filter, sessions *[]session.Session) error {
+	result, err := s.f.GetSessions(s.context(), filter)
+	if err != nil {
+		return err
+	}
+	*sessions = result
+	return nil
+}
+
+// GetSession returns a recorded shell session
+func (s *Server) GetSession(sessionID string, sess *session.Session) error {
+	result, err := s.f.GetSession(s.context(), sessionID)
+	if err != nil {
+		return err
+	}
+	*sess = *result
+	return nil
+}
+
+// GetSessionRecording returns the asciicast recording of a shell session
+func (s *Server) GetSessionRecording(sessionID string, recording *[]byte) error {
+	result, err := s.f.GetSessionRecording(s.context(), sessionID)
+	if err != nil {
+		return err
+	}
+	*recording = result
+	return nil
+}
+
+// SessionRecordingRequired returns whether shell sessions on a service must
+// be recorded
+func (s *Server) SessionRecordingRequired(serviceID string, required *bool) error {
+	result, err := s.f.SessionRecordingRequired(s.context(), serviceID)
+	if err != nil {
+		return err
+	}
+	*required = result
+	return nil
+}
@@ -30,3 +30,41 @@ func (c *Client) ValidateCredentials(user user.User) (bool, error) {
 	err := c.call("ValidateCredentials", user, &result)
 	return result, err
 }
+
+// GetUsers returns all stored users, without their passwords
+func (c *Client) GetUsers() ([]user.User, error) {
+	users := []user.User{}
+	err := c.call("GetUsers", empty, &users)
+	return users, err
+}
+
+// AddUser adds a new user
+func (c *Client) AddUser(newUser user.User) error {
+	return c.call("AddUser", newUser, nil)
+}
+
+// RemoveUser removes a user
+func (c *Client) RemoveUser(userName string) error {
+	return c.call("RemoveUser", userName, nil)
+}
+
+// SetUserRoles replaces the roles granted to a user
+func (c *Client) SetUserRoles(userName string, roles user.RoleBindings) error {
+	req := SetUserRolesRequest{UserName: userName, Roles: roles}
+	return c.call("SetUserRoles", req, nil)
+}
+
+// CheckAccess returns an error if the user may not perform the requested
+// action
+func (c *Client) CheckAccess(req user.AccessRequest) error {
+	return c.call("CheckAccess", req, nil)
+}
+
+// LoginUser validates the credentials of a user and returns a token that
+// identifies the user on calls from this host
+func (c *Client) LoginUser(name, password string) (string, error) {
+	var token string
+	req := LoginUserRequest{Name: name, Password: password}
+	err := c.call("LoginUser", req, &token)
+	return token, err
+}
@@ -14,9 +14,15 @@
 package master
 
 import (
+	"errors"
+
+	"github.com/control-center/serviced/auth"
 	"github.com/control-center/serviced/domain/user"
 )
 
+// ErrBadCredentials is returned when a user cannot be logged in
+var ErrBadCredentials = errors.New("invalid user name or password")
+
 // Get the system user
 func (s *Server) GetSystemUser(unused struct{}, systemUser *user.User) error {
 	result, err := s.f.GetSystemUser(s.context())
@@ -36,3 +42,70 @@ func (s *Server) ValidateCredentials(someUser user.User, valid *bool) error {
 	*valid = result
 	return nil
 }
+
+// GetUsers returns all stored users, without their passwords
+func (s *Server) GetUsers(unused struct{}, users *[]user.User) error {
+	result, err := s.f.GetUsers(s.context())
+	if err != nil {
+		return err
+	}
+	for i := range result {
+		result[i].Password = ""
+	}
+	*users = result
+	return nil
+}
+
+// AddUser adds a new user
+func (s *Server) AddUser(newUser user.User, _ *struct{}) error {
+	if err := newUser.ValidEntity(); err != nil {
+		return err
+	}
+	return s.f.AddUser(s.context(), newUser)
+}
+
+// RemoveUser removes a user
+func (s *Server) RemoveUser(userName string, _ *struct{}) error {
+	return s.f.RemoveUser(s.context(), userName)
+}
+
+// SetUserRolesRequest is the request object for SetUserRoles
+type SetUserRolesRequest struct {
+	UserName string
+	Roles    user.RoleBindings
+}
+
+// SetUserRoles replaces the roles granted to a user
+func (s *Server) SetUserRoles(req SetUserRolesRequest, _ *struct{}) error {
+	return s.f.SetUserRoles(s.context(), req.UserName, req.Roles)
+}
+
+// CheckAccess returns an error if the user may not perform the requested
+// action
+func (s *Server) CheckAccess(req user.AccessRequest, _ *struct{}) error {
+	return s.f.CheckAccess(s.context(), req)
+}
+
+// LoginUserRequest is the request object for LoginUser
+type LoginUserRequest struct {
+	Name     string
+	Password string
+	HostID   string // set by the master to the host that sent the request
+}
+
+// LoginUser validates the credentials of a user and returns a token that
+// identifies the user on calls from the host that logged in
+func (s *Server) LoginUser(req LoginUserRequest, token *string) error {
+	valid, err := s.f.ValidateCredentials(s.context(), user.User{Name: req.Name, Password: req.Password})
+	if err != nil {
+		return err
+	} else if !valid {
+		return ErrBadCredentials
+	}
+	result, _, err := auth.CreateUserToken(req.Name, req.HostID, s.expiration)
+	if err != nil {
+		return err
+	}
+	*token = result
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"io"
 	"net/rpc"
 	"net/rpc/jsonrpc"
+	"strings"
 	"sync"
 
 	"github.com/control-center/serviced/auth"
@@ -36,22 +37,30 @@ var (
 	}
 	// RPC calls that do not require admin access:
 	NonAdminRequiredCalls = map[string]struct{}{
+		"Master.AddSession":                      struct{}{},
 		"Master.GetHost":                         struct{}{},
 		"Master.GetHosts":                        struct{}{},
 		"Master.GetEvaluatedService":             struct{}{},
 		"Master.GetSystemUser":                   struct{}{},
+		"Master.GetServicesHealth":               struct{}{},
 		"Master.ReportHealthStatus":              struct{}{},
 		"Master.ReportInstanceDead":              struct{}{},
+		"Master.SessionRecordingRequired":        struct{}{},
 		"Master.UpdateHost":                      struct{}{},
 		"ControlCenterAgent.GetEvaluatedService": struct{}{},
 		"ControlCenterAgent.GetHostID":           struct{}{},
 		"ControlCenterAgent.GetZkInfo":           struct{}{},
 		"ControlCenterAgent.GetISvcEndpoints":    struct{}{},
+		"ControlCenterAgent.GetServicesHealth":   struct{}{},
 		"ControlCenterAgent.ReportHealthStatus":  struct{}{},
 		"ControlCenterAgent.ReportInstanceDead":  struct{}{},
 		"ControlCenterAgent.SendLogMessage":      struct{}{},
 		"ControlCenterAgent.AddHostPrivate":      struct{}{},
 	}
+	// Authorizers that check the callers of RPC services, by service name:
+	authorizers = map[string]Authorizer{}
+	authzLock   sync.RWMutex
+
 	endian = binary.BigEndian
 
 	ErrNoAdmin = errors.New("Delegate does not have admin access")
@@ -78,6 +87,25 @@ func requiresAdmin(callName string) bool {
 	return !ok
 }
 
+// Authorizer checks whether the identity that sent an RPC request may make
+// the call.  It is given the decoded argument of the call, which is always
+// a pointer, and may fill in fields that only the server can vouch for.
+type Authorizer func(serviceMethod string, ident auth.Identity, args interface{}) error
+
+// RegisterAuthorizer sets the authorizer for every method of the named RPC
+// service.  It is consulted after the caller has been authenticated.
+func RegisterAuthorizer(name string, authz Authorizer) {
+	authzLock.Lock()
+	defer authzLock.Unlock()
+	authorizers[name] = authz
+}
+
+func getAuthorizer(serviceMethod string) Authorizer {
+	authzLock.RLock()
+	defer authzLock.RUnlock()
+	return authorizers[strings.SplitN(serviceMethod, ".", 2)[0]]
+}
+
 // We nead a ReadWriteCloser that we can pass to the underlying codec and use
 //  To buffer requests and responses from the actual connection
 type ByteBufferReadWriteCloser struct {
@@ -107,6 +135,8 @@ type AuthServerCodec struct {
 	parser       auth.RPCHeaderParser
 	wBuffMutex   sync.Mutex // Make sure we buffer one response at a time
 	lastError    error
+	lastMethod   string
+	lastIdent    auth.Identity
 }
 
 func NewDefaultAuthServerCodec(conn io.ReadWriteCloser) rpc.ServerCodec {
@@ -134,6 +164,8 @@ func (a *AuthServerCodec) ReadRequestHeader(r *rpc.Request) error {
 
 	// Reset state
 	a.lastError = nil
+	a.lastMethod = ""
+	a.lastIdent = nil
 	a.buff.ReadBuff.Reset()
 
 	ident, body, err := a.parser.ReadHeader(a.conn)
@@ -171,20 +203,33 @@ func (a *AuthServerCodec) ReadRequestHeader(r *rpc.Request) error {
 				a.lastError = ErrNoAdmin
 			}
 		}
-		//TODO: save the identity so we can inject it into the request body later
+		a.lastMethod = r.ServiceMethod
+		a.lastIdent = ident
 	}
 	return nil
 }
 
 // Decodes the request and populates the body object with the body of the request
-//  We don't change anything here, just let the underlying codec handle it.
+//  The underlying codec decodes the body, which is then passed along with the
+//  identity to the authorizer of the service, if any.
 //  This always gets called after ReadRequestHeader
 func (a *AuthServerCodec) ReadRequestBody(body interface{}) error {
 	if a.lastError != nil {
 		return a.lastError
 	}
-	// TODO: Use reflection and add the identity to the body if necessary
-	return a.wrappedcodec.ReadRequestBody(body)
+	if err := a.wrappedcodec.ReadRequestBody(body); err != nil {
+		return err
+	}
+	if body == nil || a.lastMethod == "" {
+		return nil
+	}
+	if authz := getAuthorizer(a.lastMethod); authz != nil {
+		if err := authz(a.lastMethod, a.lastIdent, body); err != nil {
+			log.WithError(err).WithField("ServiceMethod", a.lastMethod).Debug("Received unauthorized RPC request")
+			return err
+		}
+	}
+	return nil
 }
 
 //  Encodes the response before sending it back down to the client.
@@ -135,6 +135,34 @@ func (s *MySuite) TestReadRequestHeader(c *C) {
 	codectest.conn.AssertExpectations(c)
 }
 
+// The calls that delegates may make without admin access, before the suite
+// replaces them with its own
+var defaultNonAdminRequiredCalls = NonAdminRequiredCalls
+
+func (s *MySuite) TestReadRequestSessionCallsWithoutAdmin(c *C) {
+	suiteCalls := NonAdminRequiredCalls
+	NonAdminRequiredCalls = defaultNonAdminRequiredCalls
+	defer func() { NonAdminRequiredCalls = suiteCalls }()
+
+	ident := &authmocks.Identity{}
+	ident.On("HasAdminAccess").Return(false)
+	body := []byte("Body1")
+
+	// Delegates record shell sessions on behalf of their users
+	for _, method := range []string{"Master.SessionRecordingRequired", "Master.AddSession"} {
+		req := &rpc.Request{ServiceMethod: method}
+		codectest.wrappedServerCodec.On("ReadRequestHeader", req).Return(nil).Once()
+		codectest.headerParser.On("ReadHeader", codectest.conn).Return(ident, body, nil).Once()
+		err := codectest.authServerCodec.ReadRequestHeader(req)
+		c.Assert(err, IsNil)
+
+		args := &struct{}{}
+		codectest.wrappedServerCodec.On("ReadRequestBody", args).Return(nil).Once()
+		err = codectest.authServerCodec.ReadRequestBody(args)
+		c.Assert(err, IsNil, Commentf("method %s", method))
+	}
+}
+
 func (s *MySuite) TestReadRequestBody(c *C) {
 	body := 0
 	codectest.wrappedServerCodec.On("ReadRequestBody", body).Return(ErrTestCodec).Once()
@@ -145,6 +173,36 @@ func (s *MySuite) TestReadRequestBody(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (s *MySuite) TestReadRequestBodyAuthorizer(c *C) {
+	req := &rpc.Request{ServiceMethod: "RPCAuthzTest.Call"}
+	ident := &authmocks.Identity{}
+	ident.On("HasAdminAccess").Return(true)
+	body := []byte("Body1")
+	var (
+		gotMethod string
+		gotIdent  auth.Identity
+		gotArgs   interface{}
+	)
+	RegisterAuthorizer("RPCAuthzTest", func(method string, id auth.Identity, args interface{}) error {
+		gotMethod, gotIdent, gotArgs = method, id, args
+		return ErrTestCodec
+	})
+	defer RegisterAuthorizer("RPCAuthzTest", nil)
+
+	codectest.headerParser.On("ReadHeader", codectest.conn).Return(ident, body, nil).Once()
+	codectest.wrappedServerCodec.On("ReadRequestHeader", req).Return(nil).Once()
+	err := codectest.authServerCodec.ReadRequestHeader(req)
+	c.Assert(err, IsNil)
+
+	args := &struct{}{}
+	codectest.wrappedServerCodec.On("ReadRequestBody", args).Return(nil).Once()
+	err = codectest.authServerCodec.ReadRequestBody(args)
+	c.Assert(err, Equals, ErrTestCodec)
+	c.Assert(gotMethod, Equals, "RPCAuthzTest.Call")
+	c.Assert(gotIdent, Equals, ident)
+	c.Assert(gotArgs, Equals, args)
+}
+
 func (s *MySuite) TestWriteResponse(c *C) {
 	body := 0
 	resp := &rpc.Response{}
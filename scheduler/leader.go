@@ -15,6 +15,7 @@ package scheduler
 
 import (
 	"errors"
+	"sync"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/commons"
@@ -36,25 +37,46 @@ type leader struct {
 	poolID   string
 
 	hreg *zkservice.HostRegistryListener
+
+	mu      sync.Mutex
+	targets map[string]string // hosts chosen by the rebalancer, by service id
 }
 
 // Lead is executed by the "leader" of the control center cluster to handle its management responsibilities of:
 //    services
 //    snapshots
 //    virtual IPs
+//    rebalancing
 func Lead(shutdown <-chan interface{}, conn coordclient.Connection, cpClient dao.ControlPlane, facade *facade.Facade, poolID string) {
 
 	// creates a listener for the host registry
 	unassignmentHandler := zkservice.NewZKHostUnassignmentHandler(conn)
 	hreg := zkservice.NewHostRegistryListener(poolID, unassignmentHandler)
 
-	leader := leader{shutdown, conn, cpClient, facade, poolID, hreg}
+	leader := &leader{
+		shutdown: shutdown,
+		conn:     conn,
+		cpClient: cpClient,
+		facade:   facade,
+		poolID:   poolID,
+		hreg:     hreg,
+		targets:  make(map[string]string),
+	}
 
 	// creates a listener for services
-	serviceListener := zkservice.NewServiceListener(poolID, &leader)
+	serviceListener := zkservice.NewServiceListener(poolID, leader)
+
+	// moves instances to the hosts the strategies would choose for them
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		leader.runRebalancer()
+	}()
 
 	// starts all of the listeners
 	zzk.Start(shutdown, conn, serviceListener, hreg)
+	wg.Wait()
 }
 
 // SelectHost chooses a host from the pool for the specified service. If the
@@ -146,6 +168,17 @@ func (l *leader) SelectHost(sn *zkservice.ServiceNode) (string, error) {
 		return "", errors.New("assigned ip is not available")
 	}
 
+	// is the rebalancer moving an instance of the service?
+	if hostID, ok := l.getTarget(sn.ID); ok {
+		for _, h := range hosts {
+			if h.ID == hostID {
+				logger.WithField("hostid", hostID).Debug("Moving service instance to rebalance the pool")
+				return hostID, nil
+			}
+		}
+		logger.WithField("hostid", hostID).Warn("Host chosen to rebalance the pool is not available")
+	}
+
 	hp := sn.HostPolicy
 	strat, err := strategy.Get(string(hp))
 	if err != nil {
@@ -0,0 +1,274 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/health"
+	"github.com/control-center/serviced/scheduler/simulation"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+var (
+	// rebalanceInterval is how often pools that rebalance automatically are
+	// rebalanced
+	rebalanceInterval = 15 * time.Minute
+
+	// rebalancePollInterval is how often the rebalancer checks on an instance
+	// that it moved
+	rebalancePollInterval = 5 * time.Second
+
+	// rebalanceMoveTimeout is how long a moved instance has to start on its
+	// new host and pass its health checks
+	rebalanceMoveTimeout = 10 * time.Minute
+)
+
+// runRebalancer rebalances the pool when it is requested, and periodically
+// if the pool rebalances automatically
+func (l *leader) runRebalancer() {
+	logger := plog.WithField("poolid", l.poolID)
+
+	timer := time.NewTimer(rebalanceInterval)
+	defer timer.Stop()
+
+	done := make(chan struct{})
+	defer func() { close(done) }()
+
+	for {
+		var retry <-chan time.Time
+		node, ev, err := zkservice.WatchRebalance(l.conn, l.poolID, done)
+		if err != nil {
+			logger.WithError(err).Warn("Could not watch for rebalance requests")
+			retry = time.After(rebalancePollInterval)
+		} else if node.Pending() {
+			logger.Info("Rebalancing pool on request")
+			l.rebalance()
+			if err := zkservice.HandleRebalance(l.conn, l.poolID, node.Requested); err != nil {
+				logger.WithError(err).Warn("Could not record that the pool was rebalanced")
+			}
+		}
+
+		select {
+		case <-ev:
+		case <-retry:
+		case <-timer.C:
+			p, err := l.facade.GetResourcePool(datastore.Get(), l.poolID)
+			if err != nil {
+				logger.WithError(err).Warn("Could not look up resource pool")
+			} else if p != nil && p.Rebalance.Automatic {
+				logger.Debug("Rebalancing pool")
+				l.rebalance()
+			}
+			timer.Reset(rebalanceInterval)
+		case <-l.shutdown:
+			return
+		}
+
+		close(done)
+		done = make(chan struct{})
+	}
+}
+
+// rebalance moves instances, one at a time, to the hosts that the strategies
+// of their services would choose now.  It stops when no more instances can be
+// moved within the disruption budget of the pool, when a moved instance does
+// not become healthy in time, or when the leader shuts down.
+func (l *leader) rebalance() {
+	logger := plog.WithField("poolid", l.poolID)
+	ctx := datastore.Get()
+
+	p, err := l.facade.GetResourcePool(ctx, l.poolID)
+	if err != nil || p == nil {
+		logger.WithError(err).Warn("Could not look up resource pool to rebalance")
+		return
+	}
+	budget := p.Rebalance.GetDisruptionBudget()
+
+	// Every move changes the placement that the next moves are planned
+	// with, so plan again after each move.  The first plan bounds the number
+	// of moves, so that a pool that never settles is not churned forever.
+	maxMoves := -1
+	for moved := 0; maxMoves < 0 || moved < maxMoves; moved++ {
+		plan, err := l.facade.RebalancePool(ctx, l.poolID, true)
+		if err != nil {
+			logger.WithError(err).Warn("Could not plan the rebalance of the pool")
+			return
+		}
+		if maxMoves < 0 {
+			maxMoves = len(plan.Moves)
+		}
+		move, ok := l.nextMove(plan.Moves, budget)
+		if !ok {
+			logger.WithField("moved", moved).Info("Finished rebalancing pool")
+			return
+		}
+		if !l.move(move) {
+			return
+		}
+	}
+	logger.WithField("moved", maxMoves).Info("Finished rebalancing pool")
+}
+
+// nextMove returns the first move that keeps the instances of its service
+// within the disruption budget
+func (l *leader) nextMove(moves []simulation.Move, budget int) (simulation.Move, bool) {
+	ctx := datastore.Get()
+	for _, move := range moves {
+		logger := plog.WithFields(log.Fields{
+			"serviceid":  move.ServiceID,
+			"instanceid": move.InstanceID,
+		})
+		svc, err := l.facade.GetService(ctx, move.ServiceID)
+		if err != nil {
+			logger.WithError(err).Debug("Could not look up service to move")
+			continue
+		}
+		states, err := zkservice.GetServiceStates(l.conn, l.poolID, move.ServiceID)
+		if err != nil {
+			logger.WithError(err).Debug("Could not look up instances of service to move")
+			continue
+		}
+		hstats, err := l.facade.GetServiceHealth(ctx, move.ServiceID)
+		if err != nil {
+			logger.WithError(err).Debug("Could not look up health of service to move")
+			continue
+		}
+
+		// the instance that is moved is unavailable until it is healthy
+		if unavailableInstances(svc.Instances, states, hstats)+1 > budget {
+			logger.Debug("Not moving instance, which would exceed the disruption budget")
+			continue
+		}
+		return move, true
+	}
+	return simulation.Move{}, false
+}
+
+// move stops an instance so that the service listener starts it on the host
+// chosen by the rebalancer, and waits for it to pass its health checks there
+func (l *leader) move(move simulation.Move) bool {
+	logger := plog.WithFields(log.Fields{
+		"poolid":     l.poolID,
+		"serviceid":  move.ServiceID,
+		"instanceid": move.InstanceID,
+		"fromhostid": move.FromHostID,
+		"tohostid":   move.ToHostID,
+	})
+
+	l.setTarget(move.ServiceID, move.ToHostID)
+	defer l.clearTarget(move.ServiceID)
+
+	req := zkservice.StateRequest{
+		PoolID:     l.poolID,
+		HostID:     move.FromHostID,
+		ServiceID:  move.ServiceID,
+		InstanceID: move.InstanceID,
+	}
+	if err := zkservice.UpdateState(l.conn, req, func(s *zkservice.State) bool {
+		if s.DesiredState != service.SVCStop {
+			s.DesiredState = service.SVCStop
+			return true
+		}
+		return false
+	}); err != nil {
+		logger.WithError(err).Warn("Could not stop service instance to move it")
+		return false
+	}
+	logger.Info("Moving service instance to rebalance the pool")
+
+	ticker := time.NewTicker(rebalancePollInterval)
+	defer ticker.Stop()
+	timeout := time.NewTimer(rebalanceMoveTimeout)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-timeout.C:
+			logger.Warn("Moved service instance did not become healthy; stopped rebalancing the pool")
+			return false
+		case <-l.shutdown:
+			return false
+		}
+
+		states, err := zkservice.GetServiceStates(l.conn, l.poolID, move.ServiceID)
+		if err != nil {
+			logger.WithError(err).Debug("Could not look up instances of moved service")
+			continue
+		}
+		hstats, err := l.facade.GetServiceHealth(datastore.Get(), move.ServiceID)
+		if err != nil {
+			logger.WithError(err).Debug("Could not look up health of moved service")
+			continue
+		}
+		for _, s := range states {
+			if s.InstanceID == move.InstanceID && s.HostID != move.FromHostID && instanceAvailable(&s, hstats[s.InstanceID]) {
+				logger.WithField("hostid", s.HostID).Info("Moved service instance")
+				return true
+			}
+		}
+	}
+}
+
+// unavailableInstances returns the number of instances of a service that are
+// not running or not passing all of their health checks
+func unavailableInstances(instances int, states []zkservice.State, hstats map[int]map[string]health.HealthStatus) int {
+	available := make(map[int]bool)
+	for i := range states {
+		s := &states[i]
+		if s.InstanceID < instances && instanceAvailable(s, hstats[s.InstanceID]) {
+			available[s.InstanceID] = true
+		}
+	}
+	return instances - len(available)
+}
+
+// instanceAvailable returns true if an instance is running and passing all of
+// its health checks
+func instanceAvailable(s *zkservice.State, hstats map[string]health.HealthStatus) bool {
+	if s.DesiredState != service.SVCRun || s.Status != service.StateRunning {
+		return false
+	}
+	for _, hs := range hstats {
+		if hs.Status != health.OK {
+			return false
+		}
+	}
+	return true
+}
+
+// getTarget returns the host the rebalancer chose for an instance of a
+// service
+func (l *leader) getTarget(serviceID string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	hostID, ok := l.targets[serviceID]
+	return hostID, ok
+}
+
+func (l *leader) setTarget(serviceID, hostID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.targets[serviceID] = hostID
+}
+
+func (l *leader) clearTarget(serviceID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.targets, serviceID)
+}
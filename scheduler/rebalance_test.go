@@ -0,0 +1,73 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/health"
+	zkservice "github.com/control-center/serviced/zzk/service"
+)
+
+func runningState(instanceID int) zkservice.State {
+	return zkservice.State{
+		HostState:             zkservice.HostState{DesiredState: service.SVCRun},
+		CurrentStateContainer: zkservice.CurrentStateContainer{Status: service.StateRunning},
+		ServiceID:             "svc",
+		InstanceID:            instanceID,
+	}
+}
+
+func TestUnavailableInstances(t *testing.T) {
+	stopping := runningState(2)
+	stopping.DesiredState = service.SVCStop
+	starting := runningState(3)
+	starting.Status = service.StateStarting
+	surplus := runningState(5)
+
+	states := []zkservice.State{runningState(0), runningState(1), stopping, starting, surplus}
+	hstats := map[int]map[string]health.HealthStatus{
+		0: {"ready": {Status: health.OK}},
+		1: {"ready": {Status: health.Failed}},
+	}
+
+	// 0 is available; 1 fails a check; 2 is stopping; 3 is starting; 4 is
+	// not running; 5 is not counted
+	if n := unavailableInstances(5, states, hstats); n != 4 {
+		t.Fatalf("expected 4 unavailable instances, got %d", n)
+	}
+
+	hstats[1]["ready"] = health.HealthStatus{Status: health.OK}
+	if n := unavailableInstances(2, states, hstats); n != 0 {
+		t.Fatalf("expected no unavailable instances, got %d", n)
+	}
+}
+
+func TestLeaderTargets(t *testing.T) {
+	l := &leader{targets: make(map[string]string)}
+	if _, ok := l.getTarget("svc"); ok {
+		t.Fatal("expected no target")
+	}
+	l.setTarget("svc", "host")
+	if hostID, ok := l.getTarget("svc"); !ok || hostID != "host" {
+		t.Fatalf("expected target host, got %q", hostID)
+	}
+	l.clearTarget("svc")
+	if _, ok := l.getTarget("svc"); ok {
+		t.Fatal("expected the target to be cleared")
+	}
+}
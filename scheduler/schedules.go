@@ -0,0 +1,43 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"github.com/control-center/serviced/dao"
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/schedule"
+	"github.com/control-center/serviced/facade"
+)
+
+// scheduleClient runs the snapshot and backup schedules through the control
+// plane, which serializes them with the other operations on the dfs
+type scheduleClient struct {
+	dao.ControlPlane
+	facade *facade.Facade
+}
+
+// GetSchedules implements ttl.ScheduleInterface
+func (c *scheduleClient) GetSchedules() ([]schedule.Schedule, error) {
+	return c.facade.GetSchedules(datastore.Get())
+}
+
+// SetScheduleStatus implements ttl.ScheduleInterface
+func (c *scheduleClient) SetScheduleStatus(scheduleID string, status schedule.Status) error {
+	return c.facade.SetScheduleStatus(datastore.Get(), scheduleID, status)
+}
+
+// DeleteBackup implements ttl.ScheduleInterface
+func (c *scheduleClient) DeleteBackup(filename string) error {
+	return c.facade.DeleteBackup(datastore.Get(), filename)
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulation
+
+import (
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/utils"
+)
+
+// Changes are the hypothetical changes to a pool that a simulation applies
+// before placing instances
+type Changes struct {
+	AddHosts    []host.Host                  // Hosts that join the pool; ID is optional
+	RemoveHosts []string                     // Hosts that leave the pool; their instances are placed again
+	Services    []ServiceChange              // Changes to the services of the pool
+	HostPolicy  servicedefinition.HostPolicy // Replaces the host policy of every service, if set
+}
+
+// ServiceChange changes the settings of a service that affect scheduling.
+// Settings that are not set are left as they are.
+type ServiceChange struct {
+	ServiceID     string
+	Instances     *int
+	RAMCommitment *utils.EngNotation
+	CPUCommitment *uint64
+	HostPolicy    servicedefinition.HostPolicy
+	Placement     *servicedefinition.PlacementConstraints
+}
+
+// Apply applies the change to the service
+func (c ServiceChange) Apply(svc *service.Service) {
+	if c.Instances != nil {
+		svc.Instances = *c.Instances
+	}
+	if c.RAMCommitment != nil {
+		svc.RAMCommitment = *c.RAMCommitment
+	}
+	if c.CPUCommitment != nil {
+		svc.CPUCommitment = *c.CPUCommitment
+	}
+	if c.HostPolicy != servicedefinition.DEFAULT {
+		svc.HostPolicy = c.HostPolicy
+	}
+	if c.Placement != nil {
+		svc.Placement = *c.Placement
+	}
+}
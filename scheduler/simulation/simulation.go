@@ -0,0 +1,377 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package simulation places and moves service instances on hosts with the
+// scheduler strategies, without scheduling anything.
+package simulation
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/scheduler/strategy"
+)
+
+// Placement is an instance that the simulation placed on a host
+type Placement struct {
+	ServiceID      string
+	ServiceName    string
+	InstanceID     int
+	HostID         string
+	Oversubscribed bool // the host does not have the resources for the instance
+}
+
+// Unplaced is an instance that the simulation could not place
+type Unplaced struct {
+	ServiceID   string
+	ServiceName string
+	InstanceID  int
+	Reason      string
+}
+
+// Move is a running instance that would be moved to another host
+type Move struct {
+	ServiceID   string
+	ServiceName string
+	InstanceID  int
+	FromHostID  string
+	ToHostID    string
+}
+
+// HostCommitment is the load of a host at the end of the simulation
+type HostCommitment struct {
+	HostID        string
+	Name          string
+	Cores         int
+	Memory        uint64
+	CPUCommitment int    // cores committed to the instances on the host
+	RAMCommitment uint64 // bytes committed to the instances on the host
+	Instances     int
+}
+
+// Plan is the outcome of a simulation
+type Plan struct {
+	PoolID     string
+	Placements []Placement
+	Unplaced   []Unplaced
+	Moves      []Move
+	Hosts      []HostCommitment
+}
+
+// Simulation holds the hosts of a pool and the instances running on them
+type Simulation struct {
+	poolID     string
+	hosts      []*simHost
+	placements []Placement
+	unplaced   []Unplaced
+	moves      []Move
+}
+
+// New returns a simulation of the hosts of a pool, with nothing running
+func New(poolID string, hosts []host.Host) *Simulation {
+	s := &Simulation{poolID: poolID}
+	for _, h := range hosts {
+		s.hosts = append(s.hosts, &simHost{host: h})
+	}
+	sort.Sort(simHosts(s.hosts))
+	return s
+}
+
+// AddInstance records an instance that already runs on a host
+func (s *Simulation) AddInstance(hostID string, svc *service.Service, instanceID int) error {
+	for _, h := range s.hosts {
+		if h.host.ID == hostID {
+			h.add(&simService{svc: svc, instanceID: instanceID})
+			return nil
+		}
+	}
+	return fmt.Errorf("host %s is not in the simulation", hostID)
+}
+
+// Place selects a host for an instance with the strategy of the service and
+// adds the instance to the host.  Instances that cannot be placed are
+// reported in the plan.
+func (s *Simulation) Place(svc *service.Service, instanceID int) {
+	unplaced := Unplaced{ServiceID: svc.ID, ServiceName: svc.Name, InstanceID: instanceID}
+
+	strat, err := strategy.Get(string(svc.HostPolicy))
+	if err != nil {
+		unplaced.Reason = fmt.Sprintf("host policy %s: %s", svc.HostPolicy, err)
+		s.unplaced = append(s.unplaced, unplaced)
+		return
+	}
+	inst := &simService{svc: svc, instanceID: instanceID}
+	selected, err := s.selectHost(strat, inst)
+	if err != nil {
+		unplaced.Reason = err.Error()
+		s.unplaced = append(s.unplaced, unplaced)
+		return
+	} else if selected == nil {
+		unplaced.Reason = fmt.Sprintf("no host is available under the %s strategy", strat.Name())
+		s.unplaced = append(s.unplaced, unplaced)
+		return
+	}
+
+	cpu, ram := selected.committed()
+	placement := Placement{
+		ServiceID:   svc.ID,
+		ServiceName: svc.Name,
+		InstanceID:  instanceID,
+		HostID:      selected.host.ID,
+		Oversubscribed: cpu+int(svc.CPUCommitment) > selected.TotalCores() ||
+			ram+svc.RAMCommitment.Value > selected.TotalMemory(),
+	}
+	selected.add(inst)
+	s.placements = append(s.placements, placement)
+}
+
+// Rebalance moves the running instances, one at a time, to the host that the
+// strategy of their service would choose if they were not running.  An
+// instance is not moved if the strategy could just as well choose the host it
+// runs on, so that hosts which are equally good do not trade instances.
+// Instances with an address assignment and instances of services that
+// restart all of their instances on a change stay where they are.
+func (s *Simulation) Rebalance() {
+	for _, inst := range s.running() {
+		if !Movable(inst.svc) {
+			continue
+		}
+		strat, err := strategy.Get(string(inst.svc.HostPolicy))
+		if err != nil {
+			continue
+		}
+		from := inst.host
+		from.remove(inst)
+		to, err := s.selectHost(strat, inst)
+		if err != nil || to == nil || to == from || s.couldSelect(strat, inst, from) {
+			from.add(inst)
+			continue
+		}
+		to.add(inst)
+		s.moves = append(s.moves, Move{
+			ServiceID:   inst.svc.ID,
+			ServiceName: inst.svc.Name,
+			InstanceID:  inst.instanceID,
+			FromHostID:  from.host.ID,
+			ToHostID:    to.host.ID,
+		})
+	}
+}
+
+// Movable returns true if the instances of a service may be moved to another
+// host without disturbing the other instances of the service
+func Movable(svc *service.Service) bool {
+	for _, ep := range svc.Endpoints {
+		if ep.IsConfigurable() {
+			return false
+		}
+	}
+	options := servicedefinition.ChangeOptions(svc.ChangeOptions)
+	return !options.Contains(servicedefinition.RestartAllOnInstanceChanged) &&
+		!options.Contains(servicedefinition.RestartAllOnInstanceZeroDown)
+}
+
+// selectHost returns the host that the strategy chooses for an instance that
+// is not running
+func (s *Simulation) selectHost(strat strategy.Strategy, inst *simService) (*simHost, error) {
+	hosts := make([]strategy.Host, len(s.hosts))
+	for i, h := range s.hosts {
+		hosts[i] = h
+	}
+	selected, err := strat.SelectHost(inst, hosts)
+	if err != nil || selected == nil {
+		return nil, err
+	}
+	return selected.(*simHost), nil
+}
+
+// couldSelect returns true if the strategy chooses a host for an instance
+// when the host is offered either first or last, which covers the ways the
+// strategies break ties between hosts
+func (s *Simulation) couldSelect(strat strategy.Strategy, inst *simService, h *simHost) bool {
+	others := []strategy.Host{}
+	for _, other := range s.hosts {
+		if other != h {
+			others = append(others, other)
+		}
+	}
+	for _, hosts := range [][]strategy.Host{
+		append([]strategy.Host{h}, others...),
+		append(append([]strategy.Host{}, others...), h),
+	} {
+		if selected, err := strat.SelectHost(inst, hosts); err == nil && selected == h {
+			return true
+		}
+	}
+	return false
+}
+
+// running returns the instances running on the hosts, sorted by service and
+// instance
+func (s *Simulation) running() []*simService {
+	insts := []*simService{}
+	for _, h := range s.hosts {
+		for _, inst := range h.instances {
+			insts = append(insts, inst.(*simService))
+		}
+	}
+	sort.Sort(simServices(insts))
+	return insts
+}
+
+// Plan returns the instances placed so far, the instances that could not be
+// placed and the resulting load of each host
+func (s *Simulation) Plan() *Plan {
+	plan := &Plan{
+		PoolID:     s.poolID,
+		Placements: append([]Placement{}, s.placements...),
+		Unplaced:   append([]Unplaced{}, s.unplaced...),
+		Moves:      append([]Move{}, s.moves...),
+	}
+	for _, h := range s.hosts {
+		cpu, ram := h.committed()
+		plan.Hosts = append(plan.Hosts, HostCommitment{
+			HostID:        h.host.ID,
+			Name:          h.host.Name,
+			Cores:         h.TotalCores(),
+			Memory:        h.TotalMemory(),
+			CPUCommitment: cpu,
+			RAMCommitment: ram,
+			Instances:     len(h.instances),
+		})
+	}
+	sort.Sort(hostCommitments(plan.Hosts))
+	return plan
+}
+
+type hostCommitments []HostCommitment
+
+func (l hostCommitments) Len() int           { return len(l) }
+func (l hostCommitments) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+func (l hostCommitments) Less(i, j int) bool { return l[i].HostID < l[j].HostID }
+
+type simHosts []*simHost
+
+func (l simHosts) Len() int           { return len(l) }
+func (l simHosts) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+func (l simHosts) Less(i, j int) bool { return l[i].host.ID < l[j].host.ID }
+
+// simHost implements strategy.Host for a host in the simulation
+type simHost struct {
+	host      host.Host
+	instances []strategy.ServiceConfig
+}
+
+func (h *simHost) HostID() string {
+	return h.host.ID
+}
+
+func (h *simHost) TotalCores() int {
+	return h.host.Cores
+}
+
+func (h *simHost) TotalMemory() uint64 {
+	return h.host.TotalRAM()
+}
+
+func (h *simHost) RunningServices() []strategy.ServiceConfig {
+	return h.instances
+}
+
+func (h *simHost) Labels() map[string]string {
+	return h.host.Labels
+}
+
+// add runs an instance on the host
+func (h *simHost) add(inst *simService) {
+	inst.host = h
+	h.instances = append(h.instances, inst)
+}
+
+// remove stops an instance on the host
+func (h *simHost) remove(inst *simService) {
+	for i, running := range h.instances {
+		if running == inst {
+			h.instances = append(h.instances[:i], h.instances[i+1:]...)
+			break
+		}
+	}
+	inst.host = nil
+}
+
+// committed returns the cores and bytes of memory committed to the instances
+// on the host
+func (h *simHost) committed() (int, uint64) {
+	var (
+		cpu int
+		ram uint64
+	)
+	for _, inst := range h.instances {
+		cpu += inst.RequestedCorePercent()
+		ram += inst.RequestedMemoryBytes()
+	}
+	return cpu, ram
+}
+
+// simService implements strategy.ServiceConfig for an instance of a service
+// in the simulation, the same way the scheduler does for a service node
+type simService struct {
+	svc        *service.Service
+	instanceID int
+	host       *simHost // where the instance runs, if it is running
+}
+
+type simServices []*simService
+
+func (l simServices) Len() int      { return len(l) }
+func (l simServices) Swap(i, j int) { l[i], l[j] = l[j], l[i] }
+func (l simServices) Less(i, j int) bool {
+	if l[i].svc.Name != l[j].svc.Name {
+		return l[i].svc.Name < l[j].svc.Name
+	}
+	if l[i].svc.ID != l[j].svc.ID {
+		return l[i].svc.ID < l[j].svc.ID
+	}
+	return l[i].instanceID < l[j].instanceID
+}
+
+func (s *simService) GetServiceID() string {
+	return s.svc.ID
+}
+
+func (s *simService) GetServiceName() string {
+	return s.svc.Name
+}
+
+func (s *simService) GetDeploymentID() string {
+	return s.svc.DeploymentID
+}
+
+func (s *simService) RequestedCorePercent() int {
+	return int(s.svc.CPUCommitment)
+}
+
+func (s *simService) RequestedMemoryBytes() uint64 {
+	return s.svc.RAMCommitment.Value
+}
+
+func (s *simService) HostPolicy() servicedefinition.HostPolicy {
+	return s.svc.HostPolicy
+}
+
+func (s *simService) Placement() servicedefinition.PlacementConstraints {
+	return s.svc.Placement
+}
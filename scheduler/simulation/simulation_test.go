@@ -0,0 +1,184 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package simulation_test
+
+import (
+	"testing"
+
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	. "github.com/control-center/serviced/scheduler/simulation"
+	"github.com/control-center/serviced/utils"
+	. "gopkg.in/check.v1"
+)
+
+const gigabyte = 1024 * 1024 * 1024
+
+func TestSimulation(t *testing.T) { TestingT(t) }
+
+type SimulationSuite struct{}
+
+var _ = Suite(&SimulationSuite{})
+
+func newHost(id string, cores int, memgigs uint64, labels map[string]string) host.Host {
+	return host.Host{ID: id, Name: "name-" + id, Cores: cores, Memory: memgigs * gigabyte, Labels: labels}
+}
+
+func newService(id string, cores uint64, memgigs int64) *service.Service {
+	return &service.Service{
+		ID:            id,
+		Name:          "name-" + id,
+		Instances:     1,
+		DesiredState:  int(service.SVCRun),
+		CPUCommitment: cores,
+		RAMCommitment: utils.NewEngNotation(memgigs * gigabyte),
+	}
+}
+
+func (s *SimulationSuite) TestPlaceBalancesInstances(c *C) {
+	sim := New("default", []host.Host{newHost("a", 4, 8, nil), newHost("b", 4, 8, nil)})
+	running := newService("running", 1, 4)
+	c.Assert(sim.AddInstance("a", running, 0), IsNil)
+
+	svc := newService("new", 1, 2)
+	sim.Place(svc, 0)
+	sim.Place(svc, 1)
+
+	plan := sim.Plan()
+	c.Assert(plan.PoolID, Equals, "default")
+	c.Assert(plan.Unplaced, HasLen, 0)
+	c.Assert(plan.Placements, DeepEquals, []Placement{
+		{ServiceID: "new", ServiceName: "name-new", InstanceID: 0, HostID: "b"},
+		{ServiceID: "new", ServiceName: "name-new", InstanceID: 1, HostID: "b"},
+	})
+	c.Assert(plan.Hosts, DeepEquals, []HostCommitment{
+		{HostID: "a", Name: "name-a", Cores: 4, Memory: 8 * gigabyte, CPUCommitment: 1, RAMCommitment: 4 * gigabyte, Instances: 1},
+		{HostID: "b", Name: "name-b", Cores: 4, Memory: 8 * gigabyte, CPUCommitment: 2, RAMCommitment: 4 * gigabyte, Instances: 2},
+	})
+}
+
+func (s *SimulationSuite) TestPlaceOversubscribed(c *C) {
+	sim := New("default", []host.Host{newHost("a", 4, 2, nil)})
+
+	sim.Place(newService("big", 1, 4), 0)
+
+	plan := sim.Plan()
+	c.Assert(plan.Placements, HasLen, 1)
+	c.Assert(plan.Placements[0].Oversubscribed, Equals, true)
+	c.Assert(plan.Hosts[0].RAMCommitment, Equals, uint64(4*gigabyte))
+}
+
+func (s *SimulationSuite) TestPlaceUnsatisfiable(c *C) {
+	sim := New("default", []host.Host{newHost("a", 4, 8, nil), newHost("b", 4, 8, map[string]string{"ssd": "true"})})
+
+	separate := newService("separate", 1, 1)
+	separate.HostPolicy = servicedefinition.RequireSeparate
+	sim.Place(separate, 0)
+	sim.Place(separate, 1)
+	sim.Place(separate, 2)
+
+	ssd := newService("ssd", 1, 1)
+	ssd.Placement.HostLabels = map[string]string{"ssd": "true", "zone": "east"}
+	sim.Place(ssd, 0)
+
+	plan := sim.Plan()
+	c.Assert(plan.Placements, HasLen, 2)
+	c.Assert(plan.Unplaced, HasLen, 2)
+	c.Assert(plan.Unplaced[0].ServiceID, Equals, "separate")
+	c.Assert(plan.Unplaced[0].InstanceID, Equals, 2)
+	c.Assert(plan.Unplaced[0].Reason, Equals, "no host is available under the REQUIRE_SEPARATE strategy")
+	c.Assert(plan.Unplaced[1].ServiceID, Equals, "ssd")
+	c.Assert(plan.Unplaced[1].Reason, Matches, "no host satisfies the placement constraints of service ssd: .*")
+}
+
+func (s *SimulationSuite) TestAddInstanceUnknownHost(c *C) {
+	sim := New("default", []host.Host{newHost("a", 4, 8, nil)})
+	c.Assert(sim.AddInstance("b", newService("svc", 1, 1), 0), NotNil)
+}
+
+func (s *SimulationSuite) TestRebalance(c *C) {
+	sim := New("default", []host.Host{newHost("a", 4, 8, nil), newHost("b", 4, 8, nil), newHost("c", 4, 8, nil)})
+	web := newService("web", 1, 2)
+	web.Instances = 3
+	db := newService("db", 1, 2)
+	for i := 0; i < 3; i++ {
+		c.Assert(sim.AddInstance("a", web, i), IsNil)
+	}
+	c.Assert(sim.AddInstance("b", db, 0), IsNil)
+
+	sim.Rebalance()
+
+	plan := sim.Plan()
+	c.Assert(plan.Moves, DeepEquals, []Move{
+		{ServiceID: "web", ServiceName: "name-web", InstanceID: 0, FromHostID: "a", ToHostID: "c"},
+	})
+	for _, h := range plan.Hosts {
+		c.Assert(h.Instances, Equals, map[string]int{"a": 2, "b": 1, "c": 1}[h.HostID])
+	}
+
+	// a balanced pool stays as it is
+	sim.Rebalance()
+	c.Assert(sim.Plan().Moves, HasLen, 1)
+}
+
+func (s *SimulationSuite) TestRebalanceTies(c *C) {
+	sim := New("default", []host.Host{newHost("a", 4, 8, nil), newHost("b", 4, 8, nil)})
+	svc := newService("svc", 1, 2)
+	svc.Instances = 2
+	c.Assert(sim.AddInstance("b", svc, 0), IsNil)
+	c.Assert(sim.AddInstance("b", svc, 1), IsNil)
+
+	other := newService("other", 1, 2)
+	c.Assert(sim.AddInstance("a", other, 0), IsNil)
+
+	// every instance runs on a host that is as good as any other
+	sim.Rebalance()
+	c.Assert(sim.Plan().Moves, HasLen, 0)
+}
+
+func (s *SimulationSuite) TestRebalanceImmovable(c *C) {
+	sim := New("default", []host.Host{newHost("a", 4, 8, nil), newHost("b", 4, 8, nil)})
+	assigned := newService("assigned", 1, 2)
+	assigned.Instances = 2
+	assigned.Endpoints = []service.ServiceEndpoint{
+		{Name: "ep", Purpose: "export", AddressConfig: servicedefinition.AddressResourceConfig{Port: 80, Protocol: "tcp"}},
+	}
+	restart := newService("restart", 1, 2)
+	restart.Instances = 2
+	restart.ChangeOptions = []servicedefinition.ChangeOption{servicedefinition.RestartAllOnInstanceChanged}
+	for i := 0; i < 2; i++ {
+		c.Assert(sim.AddInstance("a", assigned, i), IsNil)
+		c.Assert(sim.AddInstance("a", restart, i), IsNil)
+	}
+
+	sim.Rebalance()
+	c.Assert(sim.Plan().Moves, HasLen, 0)
+	c.Assert(Movable(newService("svc", 1, 1)), Equals, true)
+	c.Assert(Movable(assigned), Equals, false)
+	c.Assert(Movable(restart), Equals, false)
+}
+
+func (s *SimulationSuite) TestServiceChangeApply(c *C) {
+	svc := newService("svc", 1, 1)
+	instances := 3
+	ram := utils.NewEngNotation(2 * gigabyte)
+	ServiceChange{ServiceID: "svc", Instances: &instances, RAMCommitment: &ram, HostPolicy: servicedefinition.PreferSeparate}.Apply(svc)
+	c.Assert(svc.Instances, Equals, 3)
+	c.Assert(svc.RAMCommitment.Value, Equals, uint64(2*gigabyte))
+	c.Assert(svc.CPUCommitment, Equals, uint64(1))
+	c.Assert(svc.HostPolicy, Equals, servicedefinition.HostPolicy(servicedefinition.PreferSeparate))
+}
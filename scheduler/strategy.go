@@ -102,10 +102,22 @@ func (h *StrategyHost) TotalMemory() uint64 {
 	return h.host.TotalRAM()
 }
 
+func (h *StrategyHost) Labels() map[string]string {
+	return h.host.Labels
+}
+
 func (s *StrategyService) GetServiceID() string {
 	return s.svc.ID
 }
 
+func (s *StrategyService) GetServiceName() string {
+	return s.svc.Name
+}
+
+func (s *StrategyService) GetDeploymentID() string {
+	return s.svc.DeploymentID
+}
+
 func (s *StrategyService) RequestedCorePercent() int {
 	return s.svc.CPUCommitment
 }
@@ -118,10 +130,22 @@ func (s *StrategyService) HostPolicy() servicedefinition.HostPolicy {
 	return s.svc.HostPolicy
 }
 
+func (s *StrategyService) Placement() servicedefinition.PlacementConstraints {
+	return s.svc.Placement
+}
+
 func (s *StrategyRunningService) GetServiceID() string {
 	return s.svc.ServiceID
 }
 
+func (s *StrategyRunningService) GetServiceName() string {
+	return s.svc.Name
+}
+
+func (s *StrategyRunningService) GetDeploymentID() string {
+	return s.svc.DeploymentID
+}
+
 func (s *StrategyRunningService) RequestedCorePercent() int {
 	return s.svc.CPUCommitment
 }
@@ -133,3 +157,7 @@ func (s *StrategyRunningService) RequestedMemoryBytes() uint64 {
 func (s *StrategyRunningService) HostPolicy() servicedefinition.HostPolicy {
 	return s.svc.HostPolicy
 }
+
+func (s *StrategyRunningService) Placement() servicedefinition.PlacementConstraints {
+	return s.svc.Placement
+}
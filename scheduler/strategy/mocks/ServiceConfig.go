@@ -15,6 +15,20 @@ func (m *ServiceConfig) GetServiceID() string {
 
 	return r0
 }
+func (m *ServiceConfig) GetServiceName() string {
+	ret := m.Called()
+
+	r0 := ret.Get(0).(string)
+
+	return r0
+}
+func (m *ServiceConfig) GetDeploymentID() string {
+	ret := m.Called()
+
+	r0 := ret.Get(0).(string)
+
+	return r0
+}
 func (m *ServiceConfig) RequestedCorePercent() int {
 	ret := m.Called()
 
@@ -36,3 +50,10 @@ func (m *ServiceConfig) HostPolicy() servicedefinition.HostPolicy {
 
 	return r0
 }
+func (m *ServiceConfig) Placement() servicedefinition.PlacementConstraints {
+	ret := m.Called()
+
+	r0 := ret.Get(0).(servicedefinition.PlacementConstraints)
+
+	return r0
+}
@@ -22,6 +22,10 @@ func (s *PackStrategy) Name() string {
 }
 
 func (s *PackStrategy) SelectHost(service ServiceConfig, hosts []Host) (Host, error) {
+	hosts, err := FilterHosts(service, hosts)
+	if err != nil {
+		return nil, err
+	}
 	under, over := ScoreHosts(service, hosts)
 
 	// Return the host with the least amount of free resources that can handle
@@ -0,0 +1,120 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strategy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PlacementError is returned when no host satisfies the placement
+// constraints of a service
+type PlacementError struct {
+	ServiceID string
+	Reasons   map[string]int // number of hosts rejected for each reason
+}
+
+func (err *PlacementError) Error() string {
+	reasons := make([]string, 0, len(err.Reasons))
+	for reason, count := range err.Reasons {
+		noun := "hosts"
+		if count == 1 {
+			noun = "host"
+		}
+		reasons = append(reasons, fmt.Sprintf("%d %s %s", count, noun, reason))
+	}
+	sort.Strings(reasons)
+	return fmt.Sprintf("no host satisfies the placement constraints of service %s: %s", err.ServiceID, strings.Join(reasons, "; "))
+}
+
+// FilterHosts returns the hosts that satisfy the placement constraints of
+// the service, as well as the anti-affinity of the services already running
+// on them.  If there are hosts, but none of them qualifies, the error
+// explains why each host was rejected.
+func FilterHosts(service ServiceConfig, hosts []Host) ([]Host, error) {
+	if len(hosts) == 0 {
+		return hosts, nil
+	}
+	eligible := []Host{}
+	reasons := make(map[string]int)
+	for _, host := range hosts {
+		if reason := checkPlacement(service, host); reason != "" {
+			reasons[reason]++
+			continue
+		}
+		eligible = append(eligible, host)
+	}
+	if len(eligible) == 0 {
+		return nil, &PlacementError{ServiceID: service.GetServiceID(), Reasons: reasons}
+	}
+	return eligible, nil
+}
+
+// checkPlacement returns the reason the service may not run on the host, or
+// an empty string if it may
+func checkPlacement(service ServiceConfig, host Host) string {
+	placement := service.Placement()
+
+	labels := host.Labels()
+	keys := make([]string, 0, len(placement.HostLabels))
+	for key := range placement.HostLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := placement.HostLabels[key]
+		if actual, ok := labels[key]; !ok || actual != value {
+			return fmt.Sprintf("lacking label %s=%s", key, value)
+		}
+	}
+
+	running := host.RunningServices()
+	for _, name := range placement.AntiAffinity {
+		for _, svc := range running {
+			if isService(name, service.GetDeploymentID(), svc) {
+				return fmt.Sprintf("running service %s", name)
+			}
+		}
+	}
+	for _, svc := range running {
+		for _, name := range svc.Placement().AntiAffinity {
+			if isService(name, svc.GetDeploymentID(), service) {
+				return fmt.Sprintf("running service %s, which must not run with this service", svc.GetServiceName())
+			}
+		}
+	}
+	for _, name := range placement.Affinity {
+		found := false
+		for _, svc := range running {
+			if isService(name, service.GetDeploymentID(), svc) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf("not running service %s", name)
+		}
+	}
+	return ""
+}
+
+// isService returns true if the service is named by id, or by name in the
+// application with the deployment id
+func isService(name, deploymentID string, service ServiceConfig) bool {
+	if service.GetServiceID() == name {
+		return true
+	}
+	return service.GetServiceName() == name && service.GetDeploymentID() == deploymentID
+}
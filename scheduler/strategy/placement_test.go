@@ -0,0 +1,126 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package strategy_test
+
+import (
+	"github.com/control-center/serviced/domain/servicedefinition"
+	"github.com/control-center/serviced/scheduler/strategy"
+	"github.com/control-center/serviced/scheduler/strategy/mocks"
+	"github.com/control-center/serviced/utils"
+	. "gopkg.in/check.v1"
+)
+
+func newLabeledHost(cores int, memgigs uint64, labels map[string]string, running ...strategy.ServiceConfig) *mocks.Host {
+	host := &mocks.Host{}
+	host.On("TotalCores").Return(cores)
+	host.On("TotalMemory").Return(memgigs * Gigabyte)
+	id, _ := utils.NewUUID36()
+	host.On("HostID").Return(id)
+	host.On("Labels").Return(labels)
+	host.On("RunningServices").Return(running)
+	return host
+}
+
+func newPlacedService(name, deploymentID string, placement servicedefinition.PlacementConstraints) *mocks.ServiceConfig {
+	id, _ := utils.NewUUID36()
+	svc := &mocks.ServiceConfig{}
+	svc.On("RequestedCorePercent").Return(100)
+	svc.On("RequestedMemoryBytes").Return(uint64(Gigabyte))
+	svc.On("GetServiceID").Return(id)
+	svc.On("GetServiceName").Return(name)
+	svc.On("GetDeploymentID").Return(deploymentID)
+	svc.On("Placement").Return(placement)
+	return svc
+}
+
+func (s *StrategySuite) TestFilterHostsByLabel(c *C) {
+	hostA := newLabeledHost(5, 5, nil)
+	hostB := newLabeledHost(5, 5, map[string]string{"ssd": "false"})
+	hostC := newLabeledHost(5, 5, map[string]string{"ssd": "true", "zone": "a"})
+
+	svc := newPlacedService("db", "dep1", servicedefinition.PlacementConstraints{
+		HostLabels: map[string]string{"ssd": "true"},
+	})
+
+	hosts, err := strategy.FilterHosts(svc, []strategy.Host{hostA, hostB, hostC})
+	c.Assert(err, IsNil)
+	c.Assert(hosts, DeepEquals, []strategy.Host{hostC})
+}
+
+func (s *StrategySuite) TestFilterHostsAntiAffinity(c *C) {
+	other := newPlacedService("cache", "dep1", servicedefinition.PlacementConstraints{})
+	otherTenant := newPlacedService("cache", "dep2", servicedefinition.PlacementConstraints{})
+	hostA := newLabeledHost(5, 5, nil, other)
+	hostB := newLabeledHost(5, 5, nil, otherTenant)
+
+	svc := newPlacedService("db", "dep1", servicedefinition.PlacementConstraints{
+		AntiAffinity: []string{"cache"},
+	})
+
+	hosts, err := strategy.FilterHosts(svc, []strategy.Host{hostA, hostB})
+	c.Assert(err, IsNil)
+	c.Assert(hosts, DeepEquals, []strategy.Host{hostB})
+}
+
+func (s *StrategySuite) TestFilterHostsAntiAffinityIsSymmetric(c *C) {
+	other := newPlacedService("cache", "dep1", servicedefinition.PlacementConstraints{
+		AntiAffinity: []string{"db"},
+	})
+	hostA := newLabeledHost(5, 5, nil, other)
+	hostB := newLabeledHost(5, 5, nil)
+
+	svc := newPlacedService("db", "dep1", servicedefinition.PlacementConstraints{})
+
+	hosts, err := strategy.FilterHosts(svc, []strategy.Host{hostA, hostB})
+	c.Assert(err, IsNil)
+	c.Assert(hosts, DeepEquals, []strategy.Host{hostB})
+}
+
+func (s *StrategySuite) TestFilterHostsAffinity(c *C) {
+	other := newPlacedService("cache", "dep1", servicedefinition.PlacementConstraints{})
+	hostA := newLabeledHost(5, 5, nil)
+	hostB := newLabeledHost(5, 5, nil, other)
+
+	svc := newPlacedService("web", "dep1", servicedefinition.PlacementConstraints{
+		Affinity: []string{other.GetServiceID()},
+	})
+
+	hosts, err := strategy.FilterHosts(svc, []strategy.Host{hostA, hostB})
+	c.Assert(err, IsNil)
+	c.Assert(hosts, DeepEquals, []strategy.Host{hostB})
+}
+
+func (s *StrategySuite) TestSelectHostUnsatisfiable(c *C) {
+	other := newPlacedService("cache", "dep1", servicedefinition.PlacementConstraints{})
+	hostA := newLabeledHost(5, 5, nil)
+	hostB := newLabeledHost(5, 5, map[string]string{"ssd": "true"}, other)
+	hostC := newLabeledHost(5, 5, map[string]string{"ssd": "true"})
+
+	svc := newPlacedService("db", "dep1", servicedefinition.PlacementConstraints{
+		HostLabels:   map[string]string{"ssd": "true"},
+		AntiAffinity: []string{"cache"},
+	})
+
+	strat := strategy.BalanceStrategy{}
+	host, err := strat.SelectHost(svc, []strategy.Host{hostA, hostB})
+	c.Assert(host, IsNil)
+	c.Assert(err, FitsTypeOf, &strategy.PlacementError{})
+	c.Assert(err, ErrorMatches, "no host satisfies the placement constraints of service .*: 1 host lacking label ssd=true; 1 host running service cache")
+
+	host, err = strat.SelectHost(svc, []strategy.Host{hostA, hostB, hostC})
+	c.Assert(err, IsNil)
+	c.Assert(host, Equals, hostC)
+}
@@ -22,6 +22,10 @@ func (s *PreferSeparateStrategy) Name() string {
 }
 
 func (s *PreferSeparateStrategy) SelectHost(service ServiceConfig, hosts []Host) (Host, error) {
+	hosts, err := FilterHosts(service, hosts)
+	if err != nil {
+		return nil, err
+	}
 	under, over := ScoreHosts(service, hosts)
 
 	if under != nil && len(under) > 0 {
@@ -22,6 +22,10 @@ func (s *RequireSeparateStrategy) Name() string {
 }
 
 func (s *RequireSeparateStrategy) SelectHost(service ServiceConfig, hosts []Host) (Host, error) {
+	hosts, err := FilterHosts(service, hosts)
+	if err != nil {
+		return nil, err
+	}
 	under, over := ScoreHosts(service, hosts)
 
 	if under != nil && len(under) > 0 {
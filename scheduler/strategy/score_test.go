@@ -16,6 +16,7 @@
 package strategy_test
 
 import (
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/scheduler/strategy"
 	"github.com/control-center/serviced/scheduler/strategy/mocks"
 	"github.com/control-center/serviced/utils"
@@ -32,6 +33,7 @@ func newHost(cores int, memgigs uint64) *mocks.Host {
 	host.On("TotalMemory").Return(memgigs * Gigabyte)
 	id, _ := utils.NewUUID36()
 	host.On("HostID").Return(id)
+	host.On("Labels").Return(map[string]string(nil))
 	return host
 }
 
@@ -41,6 +43,9 @@ func newService(cores int, memgigs uint64) *mocks.ServiceConfig {
 	svc.On("RequestedCorePercent").Return(cores * 100)
 	svc.On("RequestedMemoryBytes").Return(memgigs * Gigabyte)
 	svc.On("GetServiceID").Return(id)
+	svc.On("GetServiceName").Return("")
+	svc.On("GetDeploymentID").Return("")
+	svc.On("Placement").Return(servicedefinition.PlacementConstraints{})
 	return svc
 }
 
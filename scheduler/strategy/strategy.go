@@ -39,13 +39,17 @@ type Host interface {
 	TotalCores() int
 	TotalMemory() uint64
 	RunningServices() []ServiceConfig
+	Labels() map[string]string
 }
 
 type ServiceConfig interface {
 	GetServiceID() string
+	GetServiceName() string
+	GetDeploymentID() string
 	RequestedCorePercent() int
 	RequestedMemoryBytes() uint64
 	HostPolicy() servicedefinition.HostPolicy
+	Placement() servicedefinition.PlacementConstraints
 }
 
 type Strategy interface {
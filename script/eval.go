@@ -11,6 +11,9 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/control-center/serviced/commons"
+	"github.com/control-center/serviced/utils"
 )
 
 func evalEmpty(r *runner, n node) error {
@@ -106,7 +109,9 @@ func evalUSE(r *runner, n node) error {
 	if err != nil {
 		return err
 	}
-	logger.Info("Successfully pulled and tagged new image")
+	if r.plan == nil {
+		logger.Info("Successfully pulled and tagged new image")
+	}
 	return nil
 }
 
@@ -328,44 +333,74 @@ func evalSvcRun(r *runner, n node) error {
 }
 
 func evalSvcExec(r *runner, n node) error {
+	_, err := svcExec(r, n.args, r.execCommand, SVC_EXEC)
+	return err
+}
+
+// evalSvcExecCapture runs a command like SVC_EXEC and sets the variable to
+// its output
+// SVC_EXEC_CAPTURE <name> (COMMIT|NO_COMMIT) <svc> <command> [args]
+func evalSvcExecCapture(r *runner, n node) error {
+	capture := func(name string, args ...string) error {
+		output, err := r.execOutput(name, args...)
+		if err != nil {
+			return err
+		}
+		r.env[n.args[0]] = strings.TrimSpace(output)
+		return nil
+	}
+	if _, err := svcExec(r, n.args[1:], capture, SVC_EXEC_CAPTURE); err != nil {
+		return err
+	}
+	if r.plan != nil {
+		r.unknown[n.args[0]] = true
+	}
+	plog.WithField("variable", n.args[0]).Debug("Captured command output")
+	return nil
+}
+
+// svcExec runs a command in a new container of a service, committing the
+// container if args[0] is COMMIT.  The container name is returned.
+func svcExec(r *runner, args []string, execCommand execCmd, cmd string) (string, error) {
 	if r.svcFromPath == nil {
-		return fmt.Errorf("no service id lookup function for %s", SVC_RUN)
+		return "", fmt.Errorf("no service id lookup function for %s", cmd)
 	}
 
-	svcPath := n.args[1]
+	args = append([]string{}, args...)
+	svcPath := args[1]
 	tenantID, found := r.env["TENANT_ID"]
 	if !found {
-		return fmt.Errorf("no service tenant id specified for %s", SVC_RUN)
+		return "", fmt.Errorf("no service tenant id specified for %s", cmd)
 	}
 	svcID, err := r.svcFromPath(tenantID, svcPath)
 	if err != nil {
-		return err
+		return "", err
 	}
 	if svcID == "" {
-		return fmt.Errorf("no service id found for %s", svcPath)
+		return "", fmt.Errorf("no service id found for %s", svcPath)
 	}
 
-	n.args[1] = svcID
+	args[1] = svcID
 	// "HMS-YMD_svcID" will be the name of the container
 	containerName := time.Now().Format("150405-20060102") + "_" + svcID
 	logger := plog.WithFields(log.Fields{
 		"serviceid":     svcID,
 		"containername": containerName,
 	})
-	logger.Debugf("Running: serviced service shell %s", strings.Join(n.args[1:], " "))
-	args := []string{"service", "shell", "-s", containerName}
-	args = append(args, n.args[1:]...)
-	if err := r.execCommand("serviced", args...); err != nil {
-		return err
+	logger.Debugf("Running: serviced service shell %s", strings.Join(args[1:], " "))
+	shellArgs := []string{"service", "shell", "-s", containerName}
+	shellArgs = append(shellArgs, args[1:]...)
+	if err := execCommand("serviced", shellArgs...); err != nil {
+		return "", err
 	}
 
 	// Now commit the container (if 'COMMIT' was specified)
-	switch n.args[0] {
+	switch args[0] {
 	case "COMMIT":
 		logger.Debug("committing container")
 		var snapshotID string
 		if snapshotID, err = r.commitContainer(containerName); err != nil {
-			return err
+			return "", err
 		}
 		exitFunc := func(failed bool) {
 			if !failed {
@@ -378,9 +413,113 @@ func evalSvcExec(r *runner, n node) error {
 		}
 		r.addExitFunction(exitFunc)
 	}
+	return containerName, nil
+}
+
+// evalSet sets a variable
+// SET <name> <value>
+func evalSet(r *runner, n node) error {
+	r.env[n.args[0]] = strings.Join(n.args[1:], " ")
+	plog.WithField("variable", n.args[0]).Debug("Set variable")
 	return nil
 }
 
+// evalCondition evaluates the condition of an IF
+func (r *runner) evalCondition(args []string) (bool, error) {
+	negate := false
+	if args[0] == NOT {
+		negate = true
+		args = args[1:]
+	}
+
+	var result bool
+	var err error
+	switch len(args) {
+	case 2: // SVC_EXISTS <svc>
+		_, result, err = r.lookupService(args[1], SVC_EXISTS)
+	case 4: // SVC_IMAGE_VERSION <svc> <op> <version>
+		result, err = r.evalImageVersion(args[1], args[2], args[3])
+	default: // <value> <op> <value>
+		result = compare(args[0], args[1], args[2])
+	}
+	if err != nil {
+		return false, err
+	}
+	return result != negate, nil
+}
+
+// lookupService returns the id of the service at the path, and whether the
+// service exists
+func (r *runner) lookupService(svcPath, cmd string) (string, bool, error) {
+	if r.svcFromPath == nil {
+		return "", false, fmt.Errorf("no service id lookup function for %s", cmd)
+	}
+	tenantID, found := r.env["TENANT_ID"]
+	if !found {
+		return "", false, fmt.Errorf("no service tenant id specified for %s", cmd)
+	}
+	svcID, err := r.svcFromPath(tenantID, svcPath)
+	if _, ok := err.(ServiceNotFoundError); ok {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+	return svcID, svcID != "", nil
+}
+
+// evalImageVersion compares the tag of the service's image with the version
+func (r *runner) evalImageVersion(svcPath, op, version string) (bool, error) {
+	if r.svcImage == nil {
+		return false, fmt.Errorf("no service image lookup function for %s", SVC_IMAGE_VERSION)
+	}
+	svcID, found, err := r.lookupService(svcPath, SVC_IMAGE_VERSION)
+	if err != nil {
+		return false, err
+	} else if !found {
+		return false, fmt.Errorf("no service id found for %s", svcPath)
+	}
+	imageID, err := r.svcImage(svcID)
+	if err != nil {
+		return false, err
+	}
+	image, err := commons.ParseImageID(imageID)
+	if err != nil {
+		return false, err
+	}
+	tag := image.Tag
+	if tag == "" {
+		tag = "latest"
+	}
+	plog.WithFields(log.Fields{
+		"servicepath": svcPath,
+		"image":       imageID,
+	}).Debug("Comparing image version")
+	return compare(tag, op, version), nil
+}
+
+// compare compares two values; == and != compare strings and the other
+// comparisons compare versions
+func compare(a, op, b string) bool {
+	switch op {
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	}
+	c := utils.CompareVersions(a, b)
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
 func evalDependency(r *runner, n node) error {
 	plog.Debug("Dependency check for serviced not implemented, skipping...")
 	return nil
@@ -7,6 +7,7 @@ package script
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/control-center/serviced/commons"
@@ -29,6 +30,19 @@ var (
 	SVC_WAIT    = "SVC_WAIT"
 	DEPENDENCY  = "DEPENDENCY"
 
+	SET              = "SET"
+	SVC_EXEC_CAPTURE = "SVC_EXEC_CAPTURE"
+	IF               = "IF"
+	ELSE             = "ELSE"
+	END              = "END"
+	RETRY            = "RETRY"
+	ON_FAILURE       = "ON_FAILURE"
+
+	// conditions of an IF
+	NOT               = "NOT"
+	SVC_EXISTS        = "SVC_EXISTS"
+	SVC_IMAGE_VERSION = "SVC_IMAGE_VERSION"
+
 	EMPTY     = "EMPTY"
 	emptyNode = node{cmd: EMPTY}
 
@@ -39,9 +53,9 @@ func init() {
 	nodeFactories = map[string]lineParser{
 		"":          parseEmptyCommand,
 		"#":         parseEmptyCommand,
-		DESCRIPTION: atMost(1, parseArgCount(min(1), buildNode)),
-		VERSION:     atMost(1, parseArgCount(equals(1), buildNode)),
-		REQUIRE_SVC: atMost(1, parseArgCount(equals(0), buildNode)),
+		DESCRIPTION: topLevel(atMost(1, parseArgCount(min(1), buildNode))),
+		VERSION:     topLevel(atMost(1, parseArgCount(equals(1), buildNode))),
+		REQUIRE_SVC: topLevel(atMost(1, parseArgCount(equals(0), buildNode))),
 		SNAPSHOT:    require([]string{REQUIRE_SVC}, parseArgCount(max(1), buildNode)),
 		// set image for all services under top level tenant
 		// SVC_USE <new image>
@@ -57,17 +71,59 @@ func init() {
 		SVC_RESTART: require([]string{REQUIRE_SVC}, parseArgMatch(1, "^recurse$|^auto$", true, parseArgCount(bounds(1, 2), buildNode))),
 		SVC_STOP:    require([]string{REQUIRE_SVC}, parseArgMatch(1, "^recurse$|^auto$", true, parseArgCount(bounds(1, 2), buildNode))),
 		SVC_WAIT:    require([]string{REQUIRE_SVC}, parseWaitCmd(parseArgsUntil("^started$|^stopped$|^paused$", parseArgMatch(0, "^started$|^stopped$|^paused$", false, parseArgCount(max(3), buildNode))))),
-		DEPENDENCY:  validParents([]string{DESCRIPTION, VERSION}, atMost(1, parseArgCount(equals(1), buildNode))),
+		DEPENDENCY:  topLevel(validParents([]string{DESCRIPTION, VERSION}, atMost(1, parseArgCount(equals(1), buildNode)))),
+		// SET <name> <value>
+		SET: parseVarName(0, parseArgCount(min(2), buildNode)),
+		// eg., SVC_EXEC_CAPTURE VERSION NO_COMMIT Zenoss.core/Zope cat /opt/zenoss/VERSION
+		SVC_EXEC_CAPTURE: require([]string{REQUIRE_SVC}, parseVarName(0, parseArgMatch(1, "^(NO_)?COMMIT$", false, parseArgCount(min(4), buildNode)))),
+		// IF [NOT] SVC_EXISTS <svc>
+		// IF [NOT] SVC_IMAGE_VERSION <svc> <op> <version>
+		// IF [NOT] <value> <op> <value>
+		IF:   parseIfCmd(buildNode),
+		ELSE: parseArgCount(equals(0), buildNode),
+		END:  parseArgCount(equals(0), buildNode),
+		// RETRY <attempts> [<timeout>]
+		RETRY:      parseRetryCmd(parseArgCount(bounds(1, 2), buildNode)),
+		ON_FAILURE: topLevel(parseArgCount(equals(0), buildNode)),
 	}
 }
 
 // node is the struct created from parsing a line; cmd is the command on the line, args are the remainder of the line, line is
-// the original line and lineNum is the line number where the line occurred.
+// the original line and lineNum is the line number where the line occurred.  The nodes of an IF, RETRY or ON_FAILURE block
+// are in body; the nodes following the ELSE of an IF are in elseBody.
 type node struct {
-	cmd     string
-	args    []string
-	line    string
-	lineNum int
+	cmd      string
+	args     []string
+	line     string
+	lineNum  int
+	body     []node
+	elseBody []node
+}
+
+// isBlock returns true if the command opens a block that is closed by END
+func isBlock(cmd string) bool {
+	return cmd == IF || cmd == RETRY || cmd == ON_FAILURE
+}
+
+var (
+	varNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// ${NAME} is replaced by the value of the variable; $${NAME} is a literal
+	// ${NAME}.  Names that the script never sets are not variables, so shell
+	// references such as ${HOME} pass through unchanged.
+	varRefRegex = regexp.MustCompile(`\$(\$?)\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+	comparisons = map[string]struct{}{"==": {}, "!=": {}, "<": {}, "<=": {}, ">": {}, ">=": {}}
+)
+
+// varRefs returns the names of the variables referenced by the arg
+func varRefs(arg string) []string {
+	var names []string
+	for _, m := range varRefRegex.FindAllStringSubmatch(arg, -1) {
+		if m[1] == "" {
+			names = append(names, m[2])
+		}
+	}
+	return names
 }
 
 type lineParser func(*parseContext, string, []string) (node, error)
@@ -210,7 +266,7 @@ func parseArgCount(matcher match, parser lineParser) lineParser {
 func parseUseCmd(parser lineParser) lineParser {
 	return func(ctx *parseContext, cmd string, args []string) (node, error) {
 		n, err := parser(ctx, cmd, args)
-		if err == nil {
+		if err == nil && len(varRefs(strings.Join(args, " "))) == 0 {
 			_, err := commons.ParseImageID(args[0])
 			if err != nil {
 				return node{}, err
@@ -337,3 +393,84 @@ func atMost(n int, parser lineParser) lineParser {
 	}
 	return f
 }
+
+// parseVarName checks that the arg at argN is a valid variable name that
+// may be assigned by the script
+func parseVarName(argN int, parser lineParser) lineParser {
+	f := func(ctx *parseContext, cmd string, args []string) (node, error) {
+		n, err := parser(ctx, cmd, args)
+		if err == nil {
+			if !varNameRegex.MatchString(args[argN]) {
+				return node{}, fmt.Errorf("line %d: invalid variable name %s", ctx.lineNum, args[argN])
+			}
+			if args[argN] == "TENANT_ID" {
+				return node{}, fmt.Errorf("line %d: variable %s is reserved", ctx.lineNum, args[argN])
+			}
+		}
+		return n, err
+	}
+	return f
+}
+
+// parseIfCmd checks the condition of an IF
+// IF [NOT] (SVC_EXISTS <svc> | SVC_IMAGE_VERSION <svc> <op> <version> | <value> <op> <value>)
+func parseIfCmd(parser lineParser) lineParser {
+	return func(ctx *parseContext, cmd string, args []string) (node, error) {
+		n, err := parser(ctx, cmd, args)
+		if err != nil {
+			return n, err
+		}
+		cond := args
+		if len(cond) > 0 && cond[0] == NOT {
+			cond = cond[1:]
+		}
+		switch {
+		case len(cond) == 2 && cond[0] == SVC_EXISTS:
+		case len(cond) == 4 && cond[0] == SVC_IMAGE_VERSION:
+			if _, ok := comparisons[cond[2]]; !ok {
+				return node{}, fmt.Errorf("line %d: unknown comparison %s: %s", ctx.lineNum, cond[2], ctx.line)
+			}
+		case len(cond) == 3 && cond[0] != SVC_EXISTS && cond[0] != SVC_IMAGE_VERSION:
+			if _, ok := comparisons[cond[1]]; !ok {
+				return node{}, fmt.Errorf("line %d: unknown comparison %s: %s", ctx.lineNum, cond[1], ctx.line)
+			}
+			return n, nil
+		default:
+			return node{}, fmt.Errorf("line %d: invalid condition: %s", ctx.lineNum, ctx.line)
+		}
+		// conditions on services need the tenant
+		built := func(*parseContext, string, []string) (node, error) { return n, nil }
+		return require([]string{REQUIRE_SVC}, built)(ctx, cmd, args)
+	}
+}
+
+// parseRetryCmd checks that the attempts and timeout of a RETRY are
+// positive integers
+// RETRY <attempts> [<timeout>]
+func parseRetryCmd(parser lineParser) lineParser {
+	return func(ctx *parseContext, cmd string, args []string) (node, error) {
+		n, err := parser(ctx, cmd, args)
+		if err == nil {
+			if attempts, err := strconv.Atoi(args[0]); err != nil || attempts < 1 {
+				return node{}, fmt.Errorf("line %d: expected a positive number of attempts; got %s", ctx.lineNum, args[0])
+			}
+			if len(args) > 1 {
+				if timeout, err := strconv.Atoi(args[1]); err != nil || timeout < 1 {
+					return node{}, fmt.Errorf("line %d: expected a positive integer timeout; got %s", ctx.lineNum, args[1])
+				}
+			}
+		}
+		return n, err
+	}
+}
+
+// topLevel checks that the command is not inside a block
+func topLevel(parser lineParser) lineParser {
+	return func(ctx *parseContext, cmd string, args []string) (node, error) {
+		n, err := parser(ctx, cmd, args)
+		if err == nil && len(ctx.blocks) > 0 {
+			return node{}, fmt.Errorf("line %d: %s is not allowed inside %s", ctx.lineNum, cmd, ctx.blocks[len(ctx.blocks)-1].node.cmd)
+		}
+		return n, err
+	}
+}
@@ -20,10 +20,40 @@ type parseContext struct {
 	line    string
 	errors  []error
 	nodes   []node
+	blocks  []*openBlock    // blocks that have not been closed by END, innermost last
+	vars    map[string]bool // variables assigned so far
+	early   []varUse        // variables used before they were assigned
+}
+
+// varUse is a reference to a variable on a line of the script
+type varUse struct {
+	name    string
+	lineNum int
+}
+
+// openBlock is an IF, RETRY or ON_FAILURE whose END has not been parsed
+type openBlock struct {
+	node   *node
+	inElse bool
 }
 
 func newParseContext() *parseContext {
-	return &parseContext{errors: []error{}, nodes: []node{}}
+	return &parseContext{errors: []error{}, nodes: []node{}, vars: make(map[string]bool)}
+}
+
+// addNode adds the node to the innermost open block, or to the script if
+// no block is open
+func (pc *parseContext) addNode(n node) {
+	if len(pc.blocks) == 0 {
+		pc.nodes = append(pc.nodes, n)
+		return
+	}
+	b := pc.blocks[len(pc.blocks)-1]
+	if b.inElse {
+		b.node.elseBody = append(b.node.elseBody, n)
+	} else {
+		b.node.body = append(b.node.body, n)
+	}
 }
 
 func (pc *parseContext) addErrorf(format string, a ...interface{}) {
@@ -49,6 +79,16 @@ func parseDescriptor(r io.Reader) (*parseContext, error) {
 	if err := ForEachLine(r, parse); err != nil {
 		return nil, err
 	}
+	// a reference to a variable that the script never sets is left for the
+	// shell, so only variables that are set later are reported
+	for _, use := range ctx.early {
+		if ctx.vars[use.name] {
+			ctx.addErrorf("line %d: variable %s is used before it is set", use.lineNum, use.name)
+		}
+	}
+	for _, b := range ctx.blocks {
+		ctx.addErrorf("line %d: %s is not closed by %s", b.node.lineNum, b.node.cmd, END)
+	}
 	return ctx, nil
 }
 
@@ -106,8 +146,49 @@ func parseNode(ctx *parseContext) error {
 	if err != nil {
 		return err
 	}
-	if node.cmd != EMPTY {
-		ctx.nodes = append(ctx.nodes, node)
+	if node.cmd == EMPTY {
+		return nil
+	}
+
+	// variables must be assigned before they are used
+	for _, arg := range node.args {
+		for _, name := range varRefs(arg) {
+			if !ctx.vars[name] {
+				ctx.early = append(ctx.early, varUse{name: name, lineNum: ctx.lineNum})
+			}
+		}
+	}
+	switch node.cmd {
+	case REQUIRE_SVC:
+		ctx.vars["TENANT_ID"] = true
+	case SET, SVC_EXEC_CAPTURE:
+		ctx.vars[node.args[0]] = true
+	}
+
+	switch {
+	case node.cmd == ELSE:
+		if len(ctx.blocks) == 0 || ctx.blocks[len(ctx.blocks)-1].node.cmd != IF {
+			return fmt.Errorf("line %d: %s without %s", ctx.lineNum, ELSE, IF)
+		}
+		b := ctx.blocks[len(ctx.blocks)-1]
+		if b.inElse {
+			return fmt.Errorf("line %d: extra %s for %s on line %d", ctx.lineNum, ELSE, IF, b.node.lineNum)
+		}
+		b.inElse = true
+	case node.cmd == END:
+		if len(ctx.blocks) == 0 {
+			return fmt.Errorf("line %d: %s without %s, %s or %s", ctx.lineNum, END, IF, RETRY, ON_FAILURE)
+		}
+		b := ctx.blocks[len(ctx.blocks)-1]
+		ctx.blocks = ctx.blocks[:len(ctx.blocks)-1]
+		if b.node.cmd != IF && len(b.node.body) == 0 {
+			ctx.addErrorf("line %d: empty %s", b.node.lineNum, b.node.cmd)
+		}
+		ctx.addNode(*b.node)
+	case isBlock(node.cmd):
+		ctx.blocks = append(ctx.blocks, &openBlock{node: &node})
+	default:
+		ctx.addNode(node)
 	}
 	return nil
 }
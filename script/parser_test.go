@@ -201,3 +201,122 @@ func (vs *ScriptSuite) Test_parseBadLines(t *C) {
 		t.Assert(err, ErrorMatches, "invalid command line string")
 	}
 }
+
+func (vs *ScriptSuite) Test_parseControlFlow(t *C) {
+	testDescriptor := `
+REQUIRE_SVC
+SET IMAGE zenoss/core:5.1
+SVC_EXEC_CAPTURE VERSION NO_COMMIT Zenoss.core/Zope cat /VERSION
+IF NOT SVC_EXISTS Zenoss.core/redis
+	SVC_RUN Zenoss.core/Zope add-redis
+ELSE
+	IF ${VERSION} < 5.1
+		SVC_USE ${IMAGE}
+	END
+END
+ON_FAILURE
+	SVC_START Zenoss.core
+END
+RETRY 3 60
+	SVC_RESTART Zenoss.core/Zope
+	SVC_WAIT Zenoss.core/Zope started 30
+END
+`
+	ctx, err := parseDescriptor(strings.NewReader(testDescriptor))
+	t.Assert(err, IsNil)
+	t.Assert(ctx.errors, HasLen, 0)
+	t.Assert(ctx.nodes, HasLen, 6)
+
+	ifNode := ctx.nodes[3]
+	t.Assert(ifNode.cmd, Equals, IF)
+	t.Assert(ifNode.args, DeepEquals, []string{"NOT", "SVC_EXISTS", "Zenoss.core/redis"})
+	t.Assert(ifNode.body, HasLen, 1)
+	t.Assert(ifNode.body[0].cmd, Equals, SVC_RUN)
+	t.Assert(ifNode.elseBody, HasLen, 1)
+	t.Assert(ifNode.elseBody[0].cmd, Equals, IF)
+	t.Assert(ifNode.elseBody[0].body, HasLen, 1)
+	t.Assert(ifNode.elseBody[0].body[0].args, DeepEquals, []string{"${IMAGE}"})
+
+	t.Assert(ctx.nodes[4].cmd, Equals, ON_FAILURE)
+	t.Assert(ctx.nodes[4].body, HasLen, 1)
+	t.Assert(ctx.nodes[5].cmd, Equals, RETRY)
+	t.Assert(ctx.nodes[5].args, DeepEquals, []string{"3", "60"})
+	t.Assert(ctx.nodes[5].body, HasLen, 2)
+}
+
+func (vs *ScriptSuite) Test_parseShellVariables(t *C) {
+	testDescriptor := `
+DESCRIPTION Zenoss RM upgrade
+VERSION resmgr-5.0.1
+REQUIRE_SVC
+SNAPSHOT
+SVC_RUN Zenoss.resmgr/Zope upgrade --home=${ZENHOME}
+SVC_EXEC NO_COMMIT Zenoss.resmgr/Zope sh -c "cp ${ZENHOME}/etc/global.conf ${TMPDIR:-/tmp}"
+`
+	ctx, err := parseDescriptor(strings.NewReader(testDescriptor))
+	t.Assert(err, IsNil)
+	t.Assert(ctx.errors, HasLen, 0)
+	t.Assert(ctx.nodes, HasLen, 6)
+	t.Assert(ctx.nodes[4].args, DeepEquals, []string{"Zenoss.resmgr/Zope", "upgrade", "--home=${ZENHOME}"})
+}
+
+func (vs *ScriptSuite) Test_parseControlFlowErrors(t *C) {
+	testDescriptor := `
+REQUIRE_SVC
+IF 1 == 1
+	DESCRIPTION nested
+END
+`
+	_, err := parseDescriptor(strings.NewReader(testDescriptor))
+	t.Assert(err, ErrorMatches, "line 4: DESCRIPTION is not allowed inside IF")
+
+	testDescriptor = `
+REQUIRE_SVC
+SVC_RUN Zenoss.core/Zope ${UNSET}
+SET X $${X}
+IF 1 == 1
+	RETRY 2
+	END
+ON_FAILURE
+END
+`
+	ctx, err := parseDescriptor(strings.NewReader(testDescriptor))
+	t.Assert(err, ErrorMatches, "line 8: ON_FAILURE is not allowed inside IF")
+
+	testDescriptor = `
+REQUIRE_SVC
+SVC_RUN Zenoss.core/Zope ${LATER}
+SET X $${X}
+IF 1 == 1
+	RETRY 2
+	END
+	SET LATER 1
+`
+	ctx, err = parseDescriptor(strings.NewReader(testDescriptor))
+	t.Assert(err, IsNil)
+	t.Assert(ctx.errors, HasLen, 3)
+	t.Assert(ctx.errors[0], ErrorMatches, "line 6: empty RETRY")
+	t.Assert(ctx.errors[1], ErrorMatches, "line 3: variable LATER is used before it is set")
+	t.Assert(ctx.errors[2], ErrorMatches, "line 5: IF is not closed by END")
+
+	for line, expected := range map[string]string{
+		"ELSE":                  "line 1: ELSE without IF",
+		"END":                   "line 1: END without IF, RETRY or ON_FAILURE",
+		"RETRY 0":               "line 1: expected a positive number of attempts; got 0",
+		"RETRY 3 soon":          "line 1: expected a positive integer timeout; got soon",
+		"IF 1 ~ 2":              "line 1: unknown comparison ~: IF 1 ~ 2",
+		"IF SVC_EXISTS":         "line 1: invalid condition: IF SVC_EXISTS",
+		"IF NOT":                "line 1: invalid condition: IF NOT",
+		"SET 1X foo":            "line 1: invalid variable name 1X",
+		"SET TENANT_ID foo":     "line 1: variable TENANT_ID is reserved",
+		"IF 1 == 1\nELSE\nELSE": "line 3: extra ELSE for IF on line 1",
+	} {
+		_, err := parseDescriptor(strings.NewReader(line))
+		t.Assert(err, ErrorMatches, expected)
+	}
+
+	ctx, err = parseDescriptor(strings.NewReader("IF SVC_EXISTS Zenoss.core\nEND"))
+	t.Assert(err, IsNil)
+	t.Assert(ctx.errors, HasLen, 1)
+	t.Assert(ctx.errors[0], ErrorMatches, "line 1: IF depends on REQUIRE_SVC")
+}
@@ -0,0 +1,185 @@
+// Copyright 2019, The Serviced Authors. All rights reserved.
+// Use of this source code is governed by a
+// license that can be found in the LICENSE file.
+
+package script
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Action is one change a script would make
+type Action struct {
+	Line        int    // line of the script that makes the change
+	Depth       int    // nesting within IF, RETRY and ON_FAILURE blocks
+	Description string // what would be done
+}
+
+// Plan is the ordered list of changes a script would make, found by a dry
+// run
+type Plan struct {
+	TenantID string   // tenant the script runs against
+	Snapshot string   // latest existing snapshot of the tenant
+	Actions  []Action // changes, in the order they would be made
+	Problems []string // problems that would stop the script
+}
+
+// DryRunFromFile makes the plan for the script in the file
+func DryRunFromFile(fileName string, config *Config) (*Plan, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return DryRun(bufio.NewReader(f), config)
+}
+
+// DryRun resolves the tenant, services and images used by the script and
+// returns the changes it would make without making them.  The plan found
+// so far is returned with any error that stops the dry run.
+func DryRun(r io.Reader, config *Config) (*Plan, error) {
+	pctx, err := parseDescriptor(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(pctx.errors) > 0 {
+		return nil, &ParseError{Errors: pctx.errors}
+	}
+	run := newDryRunner(config, pctx)
+	err = run.Run(nil)
+	if tenantID, found := run.env["TENANT_ID"]; found {
+		run.plan.TenantID = tenantID
+		if run.latestSnapshot != nil {
+			snapshotID, serr := run.latestSnapshot(tenantID)
+			if serr != nil {
+				run.plan.Problems = append(run.plan.Problems, fmt.Sprintf("could not look up the snapshots of tenant %s: %s", tenantID, serr))
+			}
+			run.plan.Snapshot = snapshotID
+		}
+	}
+	return run.plan, err
+}
+
+// newDryRunner creates a runner that records the changes the script would
+// make.  Lookups still query the system.
+func newDryRunner(config *Config, pctx *parseContext) *runner {
+	r := newRunner(config, pctx)
+	r.plan = &Plan{}
+	r.unknown = make(map[string]bool)
+	paths := make(map[string]string)
+	if lookup := r.svcFromPath; lookup != nil {
+		r.svcFromPath = func(tenantID, path string) (string, error) {
+			serviceID, err := lookup(tenantID, path)
+			if err == nil && serviceID != "" {
+				paths[serviceID] = path
+			}
+			return serviceID, err
+		}
+	}
+	describe := func(serviceID string) string {
+		if path, found := paths[serviceID]; found {
+			return fmt.Sprintf("%s (%s)", path, serviceID)
+		}
+		return serviceID
+	}
+	r.execCommand = func(name string, args ...string) error {
+		r.addAction("run: %s %s", name, strings.Join(args, " "))
+		return nil
+	}
+	r.execOutput = func(name string, args ...string) (string, error) {
+		r.addAction("run: %s %s", name, strings.Join(args, " "))
+		return "", nil
+	}
+	r.snapshot = func(serviceID, description, tag string) (string, error) {
+		if tag != "" {
+			r.addAction("take a snapshot of tenant %s tagged %s", serviceID, tag)
+		} else {
+			r.addAction("take a snapshot of tenant %s", serviceID)
+		}
+		return "dry_run_snapshot", nil
+	}
+	r.restore = noOpRestore
+	r.commitContainer = func(containerID string) (string, error) {
+		r.addAction("commit container %s into the tenant's images", containerID)
+		return "dry_run_commit", nil
+	}
+	recordControl := func(verb string) ServiceControl {
+		return func(serviceID string, recursive bool) error {
+			if recursive {
+				r.addAction("%s service %s and its child services", verb, describe(serviceID))
+			} else {
+				r.addAction("%s service %s", verb, describe(serviceID))
+			}
+			return nil
+		}
+	}
+	r.svcStart = recordControl("start")
+	r.svcStop = recordControl("stop")
+	r.svcRestart = recordControl("restart")
+	r.svcWait = func(serviceIDs []string, state ServiceState, timeout uint32, recursive bool) error {
+		services := make([]string, len(serviceIDs))
+		for i, serviceID := range serviceIDs {
+			services[i] = describe(serviceID)
+		}
+		msg := fmt.Sprintf("wait for %s to be %s", strings.Join(services, ", "), state)
+		if recursive {
+			msg += ", including child services"
+		}
+		if timeout > 0 {
+			msg += fmt.Sprintf(", for at most %ds", timeout)
+		}
+		r.addAction("%s", msg)
+		return nil
+	}
+	use := r.svcUse
+	r.svcUse = func(tenantID, serviceID, imageID, registry string, replaceImgs []string, noOp bool) (string, error) {
+		msg := fmt.Sprintf("use image %s", imageID)
+		if len(replaceImgs) > 0 {
+			msg += fmt.Sprintf(" in place of %s", strings.Join(replaceImgs, ", "))
+		}
+		if serviceID != "" {
+			msg += fmt.Sprintf(" for service %s", describe(serviceID))
+		} else {
+			msg += fmt.Sprintf(" for the services of tenant %s", tenantID)
+		}
+		r.addAction("%s", msg)
+		if use != nil && len(varRefs(imageID)) == 0 {
+			if _, err := use(tenantID, serviceID, imageID, registry, replaceImgs, true); err != nil {
+				r.addProblem("image %s cannot be used: %s", imageID, err)
+			}
+		}
+		return "dry_run_image", nil
+	}
+	return r
+}
+
+// addAction adds a change made by the current step to the plan
+func (r *runner) addAction(format string, a ...interface{}) {
+	r.plan.Actions = append(r.plan.Actions, Action{
+		Line:        r.current.lineNum,
+		Depth:       r.depth,
+		Description: fmt.Sprintf(format, a...),
+	})
+}
+
+// addProblem adds a problem found by the current step to the plan
+func (r *runner) addProblem(format string, a ...interface{}) {
+	r.plan.Problems = append(r.plan.Problems, fmt.Sprintf("line %d: ", r.current.lineNum)+fmt.Sprintf(format, a...))
+}
+
+// usesUnknown returns true if the args use a variable whose value is not
+// known until the script runs
+func (r *runner) usesUnknown(args []string) bool {
+	for _, arg := range args {
+		for _, name := range varRefs(arg) {
+			if r.unknown[name] {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+// Copyright 2019, The Serviced Authors. All rights reserved.
+// Use of this source code is governed by a
+// license that can be found in the LICENSE file.
+
+// +build unit
+
+package script
+
+import (
+	"errors"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+func dryRunConfig() *Config {
+	return &Config{
+		ServiceID:    "TEST_SERVICE_ID_12345",
+		TenantLookup: func(service string) (string, error) { return "tenant", nil },
+		SvcIDFromPath: func(tenantID, path string) (string, error) {
+			return strings.ToLower(path[strings.LastIndex(path, "/")+1:]), nil
+		},
+		SvcImage: func(serviceID string) (string, error) { return "zenoss/core:5.0.9", nil },
+		LatestSnapshot: func(tenantID string) (string, error) {
+			return "tenant_20190101-000000", nil
+		},
+		// the dry run must not call these
+		Snapshot: func(string, string, string) (string, error) { return "", errors.New("snapshot called") },
+		SvcStart: func(string, bool) error { return errors.New("start called") },
+		SvcStop:  func(string, bool) error { return errors.New("stop called") },
+		SvcUse: func(tenantID, serviceID, imageID, registry string, replaceImgs []string, noOp bool) (string, error) {
+			if !noOp {
+				return "", errors.New("use called")
+			} else if imageID == "zenoss/missing:1" {
+				return "", errors.New("no such image")
+			}
+			return "", nil
+		},
+		SvcWait: func([]string, ServiceState, uint32, bool) error { return errors.New("wait called") },
+	}
+}
+
+func (vs *ScriptSuite) Test_DryRun(t *C) {
+	script := `
+REQUIRE_SVC
+SNAPSHOT pre-upgrade
+SVC_USE zenoss/core:5.1 zenoss/core
+SVC_STOP Zenoss.core auto
+SVC_WAIT Zenoss.core stopped 60 recursive
+SVC_EXEC_CAPTURE VERSION NO_COMMIT Zenoss.core/Zope cat /VERSION
+IF ${VERSION} < 5.1
+	SVC_RUN Zenoss.core/Zope upgrade
+END
+IF SVC_IMAGE_VERSION Zenoss.core/Zope < 5.1
+	SVC_EXEC COMMIT Zenoss.core/Zope migrate
+END
+RETRY 3 60
+	SVC_START Zenoss.core/Zope
+END
+`
+	plan, err := DryRun(strings.NewReader(script), dryRunConfig())
+	t.Assert(err, IsNil)
+	t.Assert(plan.TenantID, Equals, "tenant")
+	t.Assert(plan.Snapshot, Equals, "tenant_20190101-000000")
+	t.Assert(plan.Problems, HasLen, 0)
+
+	var lines []string
+	for _, action := range plan.Actions {
+		lines = append(lines, strings.Repeat("  ", action.Depth)+action.Description)
+	}
+	t.Assert(lines, HasLen, 12)
+	t.Assert(lines[:4], DeepEquals, []string{
+		"take a snapshot of tenant tenant tagged pre-upgrade",
+		"use image zenoss/core:5.1 in place of zenoss/core for the services of tenant tenant",
+		"stop service Zenoss.core (zenoss.core) and its child services",
+		"wait for Zenoss.core (zenoss.core) to be stopped, including child services, for at most 60s",
+	})
+	t.Assert(lines[4], Matches, "run: serviced service shell -s .*_zope zope cat /VERSION")
+	t.Assert(lines[5:7], DeepEquals, []string{
+		"if ${VERSION} < 5.1 (known when the script runs):",
+		"  run: serviced service run zope upgrade",
+	})
+	t.Assert(lines[7], Equals, "if SVC_IMAGE_VERSION Zenoss.core/Zope < 5.1: true")
+	t.Assert(lines[8], Matches, "  run: serviced service shell -s .*_zope zope migrate")
+	t.Assert(lines[9], Matches, "  commit container .*_zope into the tenant's images")
+	t.Assert(lines[10:], DeepEquals, []string{
+		"retry up to 3 times for at most 60s:",
+		"  start service Zenoss.core/Zope (zope)",
+	})
+	t.Assert(plan.Actions[0].Line, Equals, 3)
+}
+
+func (vs *ScriptSuite) Test_DryRunProblems(t *C) {
+	script := `
+REQUIRE_SVC
+SVC_USE zenoss/missing:1
+SVC_USE zenoss/core:5.1
+SVC_START Zenoss.core/Unknown
+SVC_RUN Zenoss.core/Zope never
+`
+	config := dryRunConfig()
+	config.SvcIDFromPath = func(tenantID, path string) (string, error) {
+		if strings.HasSuffix(path, "Unknown") {
+			return "", ServiceNotFoundError{Path: path}
+		}
+		return path, nil
+	}
+	config.LatestSnapshot = func(tenantID string) (string, error) { return "", nil }
+	plan, err := DryRun(strings.NewReader(script), config)
+	t.Assert(err, ErrorMatches, "did not find service Zenoss.core/Unknown")
+	t.Assert(plan.Actions, HasLen, 2)
+	t.Assert(plan.Problems, DeepEquals, []string{"line 3: image zenoss/missing:1 cannot be used: no such image"})
+	t.Assert(plan.Snapshot, Equals, "")
+
+	_, err = DryRun(strings.NewReader("SVC_USE zenoss/core:5.1"), config)
+	t.Assert(err, FitsTypeOf, &ParseError{})
+}
@@ -6,10 +6,12 @@ package script
 
 import (
 	"bufio"
-	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
 )
@@ -33,6 +35,9 @@ func init() {
 		SVC_RESTART: evalSvcRestart,
 		SVC_EXEC:    evalSvcExec,
 		SVC_WAIT:    evalSvcWait,
+
+		SET:              evalSet,
+		SVC_EXEC_CAPTURE: evalSvcExecCapture,
 	}
 }
 
@@ -50,6 +55,17 @@ type Config struct {
 	SvcRestart     ServiceControl    // function to restart a service
 	SvcWait        ServiceWait       // function to wait for a service to be in a desired state
 	SvcUse         ServiceUse
+	SvcImage       ServiceImage   // function to get the image of a service
+	LatestSnapshot SnapshotLookup // function to find the latest snapshot of a tenant, used by dry runs
+}
+
+// ParseError holds every problem found while parsing a script
+type ParseError struct {
+	Errors []error
+}
+
+func (err *ParseError) Error() string {
+	return "error parsing script"
 }
 
 type Runner interface {
@@ -72,7 +88,16 @@ type runner struct {
 	svcRestart      ServiceControl    // function to restart a service
 	svcWait         ServiceWait
 	execCommand     execCmd
+	execOutput      execOutputCmd // function to run a command and capture its output
 	svcUse          ServiceUse
+	svcImage        ServiceImage
+	failureBlocks   [][]node      // ON_FAILURE blocks reached, run in reverse order if the script fails
+	retryDelay      time.Duration // time to wait between the attempts of a RETRY
+	latestSnapshot  SnapshotLookup
+	plan            *Plan           // changes recorded by a dry run; nil when the script makes them
+	unknown         map[string]bool // variables whose values are not known in a dry run
+	current         node            // step being evaluated
+	depth           int             // nesting of the step being evaluated
 }
 
 func NewRunnerFromFile(fileName string, config *Config) (Runner, error) {
@@ -94,7 +119,7 @@ func NewRunner(r io.Reader, config *Config) (Runner, error) {
 			plog.WithError(e).Debug("Unable to build script runner due to one or more errors")
 		}
 
-		return nil, errors.New("error parsing script")
+		return nil, &ParseError{Errors: pctx.errors}
 	}
 	return newRunner(config, pctx), nil
 }
@@ -118,11 +143,16 @@ func newRunner(config *Config, pctx *parseContext) *runner {
 		svcWait:         config.SvcWait,
 		svcRestart:      config.SvcRestart,
 		execCommand:     defaultExec,
+		execOutput:      defaultExecOutput,
 		svcUse:          config.SvcUse,
+		svcImage:        config.SvcImage,
+		retryDelay:      5 * time.Second,
+		latestSnapshot:  config.LatestSnapshot,
 	}
 	if config.NoOp {
 		plog.Info("creatng no op runner")
 		r.execCommand = noOpExec
+		r.execOutput = noOpExecOutput
 		r.restore = noOpRestore
 		r.snapshot = noOpSnapshot
 		r.commitContainer = noOpCommit
@@ -148,26 +178,111 @@ func (r *runner) evalNodes(nodes []node, stop <-chan struct{}) error {
 
 	failed := true
 	defer func() {
+		if r.plan != nil {
+			// a dry run has nothing to clean up
+			return
+		}
+		if failed {
+			r.runFailureBlocks()
+		}
 		plog.Debug("Executing exit functions")
 		for _, ef := range r.exitFunctions {
 			ef(failed)
 		}
 	}()
 
+	if err := r.evalBlock(nodes, stop); err != nil {
+		return err
+	}
+	failed = false
+
+	return nil
+}
+
+// evalBlock evaluates the nodes in order, descending into IF and RETRY
+// blocks
+func (r *runner) evalBlock(nodes []node, stop <-chan struct{}) error {
 	for i, n := range nodes {
 		logger := plog.WithFields(log.Fields{
-			"step": i,
-			"line": n.line,
+			"step":    i,
+			"line":    n.line,
 			"command": n.cmd,
 		})
-		if f, found := cmdEval[n.cmd]; found {
-			logger.Info("executing step")
-			if err := f(r, n); err != nil {
+		args, err := r.expandArgs(n.args)
+		if err != nil {
+			logger.WithError(err).Error("Unable to execute step")
+			return fmt.Errorf("line %d: %s", n.lineNum, err)
+		}
+		unknown := r.plan != nil && r.usesUnknown(n.args)
+		n.args = args
+		r.current = n
+
+		switch {
+		case n.cmd == IF && unknown:
+			// the condition cannot be evaluated until the script runs
+			r.addAction("if %s (known when the script runs):", strings.Join(n.args, " "))
+			if err := r.evalNested(n.body, stop); err != nil {
+				return err
+			}
+			if len(n.elseBody) > 0 {
+				r.current = n
+				r.addAction("otherwise:")
+				if err := r.evalNested(n.elseBody, stop); err != nil {
+					return err
+				}
+			}
+		case n.cmd == IF:
+			ok, err := r.evalCondition(n.args)
+			if err != nil {
+				logger.WithError(err).Error("Unable to evaluate condition")
+				return err
+			}
+			logger.WithField("result", ok).Info("evaluated condition")
+			body := n.body
+			if !ok {
+				body = n.elseBody
+			}
+			if r.plan != nil {
+				r.addAction("if %s: %t", strings.Join(n.args, " "), ok)
+			}
+			if err := r.evalNested(body, stop); err != nil {
+				return err
+			}
+		case n.cmd == RETRY && r.plan != nil:
+			if len(n.args) > 1 {
+				r.addAction("retry up to %s times for at most %ss:", n.args[0], n.args[1])
+			} else {
+				r.addAction("retry up to %s times:", n.args[0])
+			}
+			if err := r.evalNested(n.body, stop); err != nil {
+				return err
+			}
+		case n.cmd == RETRY:
+			if err := r.evalRetry(n, stop); err != nil {
 				logger.WithError(err).Error("Unable to execute step")
 				return err
 			}
-		} else {
-			logger.Info("skipping step because of unknown function")
+		case n.cmd == ON_FAILURE && r.plan != nil:
+			r.addAction("if a later step fails:")
+			if err := r.evalNested(n.body, stop); err != nil {
+				return err
+			}
+		case n.cmd == ON_FAILURE:
+			logger.Info("registering failure steps")
+			r.failureBlocks = append(r.failureBlocks, n.body)
+		default:
+			if f, found := cmdEval[n.cmd]; found {
+				logger.Info("executing step")
+				if err := f(r, n); err != nil {
+					logger.WithError(err).Error("Unable to execute step")
+					return err
+				}
+				if n.cmd == SET && r.plan != nil {
+					r.unknown[n.args[0]] = unknown
+				}
+			} else {
+				logger.Info("skipping step because of unknown function")
+			}
 		}
 
 		select {
@@ -178,11 +293,100 @@ func (r *runner) evalNodes(nodes []node, stop <-chan struct{}) error {
 		}
 
 	}
-	failed = false
-
 	return nil
 }
 
+// evalNested evaluates the nodes of a block
+func (r *runner) evalNested(nodes []node, stop <-chan struct{}) error {
+	r.depth++
+	defer func() { r.depth-- }()
+	return r.evalBlock(nodes, stop)
+}
+
+// evalRetry evaluates the body of a RETRY until it succeeds, the attempts
+// are used up or the timeout has passed.  A running attempt is not
+// interrupted by the timeout.
+func (r *runner) evalRetry(n node, stop <-chan struct{}) error {
+	attempts, _ := strconv.Atoi(n.args[0])
+	var deadline time.Time
+	if len(n.args) > 1 {
+		timeout, _ := strconv.Atoi(n.args[1])
+		deadline = time.Now().Add(time.Duration(timeout) * time.Second)
+	}
+
+	logger := plog.WithField("line", n.line)
+	for attempt := 1; ; attempt++ {
+		err := r.evalBlock(n.body, stop)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-stop:
+			return err
+		default:
+		}
+		if attempt >= attempts {
+			return fmt.Errorf("line %d: gave up after %d attempts: %s", n.lineNum, attempt, err)
+		}
+		if !deadline.IsZero() && time.Now().Add(r.retryDelay).After(deadline) {
+			return fmt.Errorf("line %d: timed out after %d attempts: %s", n.lineNum, attempt, err)
+		}
+		logger.WithError(err).WithField("attempt", attempt).Warn("Step failed, retrying")
+		select {
+		case <-stop:
+			return err
+		case <-time.After(r.retryDelay):
+		}
+	}
+}
+
+// runFailureBlocks evaluates the ON_FAILURE blocks that were reached, the
+// most recent first.  A failing step is logged and does not prevent the
+// remaining steps from running.
+func (r *runner) runFailureBlocks() {
+	for i := len(r.failureBlocks) - 1; i >= 0; i-- {
+		plog.Info("Executing failure steps")
+		for _, n := range r.failureBlocks[i] {
+			// failure steps run even if the script was stopped
+			if err := r.evalBlock([]node{n}, nil); err != nil {
+				plog.WithError(err).WithField("line", n.line).Error("Unable to execute failure step")
+			}
+		}
+	}
+}
+
+// expandArgs returns a copy of the args with each ${NAME} replaced by the
+// value of the variable.  References to names that the script never sets are
+// left unchanged for the shell.
+func (r *runner) expandArgs(args []string) ([]string, error) {
+	expanded := make([]string, len(args))
+	for i, arg := range args {
+		var err error
+		expanded[i] = varRefRegex.ReplaceAllStringFunc(arg, func(ref string) string {
+			m := varRefRegex.FindStringSubmatch(ref)
+			if m[1] != "" {
+				return ref[1:]
+			}
+			if !r.parseCtx.vars[m[2]] {
+				return ref
+			}
+			if r.plan != nil && r.unknown[m[2]] {
+				// a dry run shows the variable in place of its value
+				return ref
+			}
+			value, found := r.env[m[2]]
+			if !found && err == nil {
+				err = fmt.Errorf("variable %s is not set", m[2])
+			}
+			return value
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return expanded, nil
+}
+
 func (r *runner) addExitFunction(ef func(bool)) {
 	r.exitFunctions = append(r.exitFunctions, ef)
 }
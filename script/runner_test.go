@@ -8,6 +8,8 @@ package script
 
 import (
 	"errors"
+	"strings"
+	"time"
 
 	. "gopkg.in/check.v1"
 )
@@ -48,3 +50,150 @@ func (vs *ScriptSuite) Test_Run(t *C) {
 	t.Assert(err, ErrorMatches, "test error id from path")
 
 }
+
+// newControlFlowRunner returns a runner for the script that records the
+// commands it executes instead of running them
+func newControlFlowRunner(t *C, script string, config *Config, executed *[]string) *runner {
+	config.ServiceID = "TEST_SERVICE_ID_12345"
+	config.TenantLookup = func(service string) (string, error) { return "tenant", nil }
+	if config.SvcIDFromPath == nil {
+		config.SvcIDFromPath = func(tenantID string, path string) (string, error) { return path, nil }
+	}
+	r, err := NewRunner(strings.NewReader(script), config)
+	t.Assert(err, IsNil)
+	run := r.(*runner)
+	run.retryDelay = 0
+	run.execCommand = func(name string, args ...string) error {
+		*executed = append(*executed, strings.Join(args, " "))
+		return nil
+	}
+	run.execOutput = func(name string, args ...string) (string, error) {
+		*executed = append(*executed, strings.Join(args, " "))
+		return "5.0.7\n", nil
+	}
+	return run
+}
+
+func (vs *ScriptSuite) Test_RunControlFlow(t *C) {
+	script := `
+REQUIRE_SVC
+SET PATCH patch-${TENANT_ID}
+SVC_EXEC_CAPTURE VERSION NO_COMMIT Zenoss.core/Zope cat /VERSION
+IF ${VERSION} < 5.1
+	SVC_RUN Zenoss.core/Zope upgrade ${VERSION} ${PATCH} $${HOME} ${ZENHOME}
+ELSE
+	SVC_RUN Zenoss.core/Zope skip
+END
+IF NOT SVC_EXISTS Zenoss.core/redis
+	SVC_RUN Zenoss.core/Zope add-redis
+END
+IF SVC_IMAGE_VERSION Zenoss.core/Zope >= 5.0.10
+	SVC_RUN Zenoss.core/Zope new-image
+ELSE
+	SVC_RUN Zenoss.core/Zope old-image
+END
+`
+	var executed []string
+	config := &Config{
+		SvcIDFromPath: func(tenantID string, path string) (string, error) {
+			if path == "Zenoss.core/redis" {
+				return "", ServiceNotFoundError{Path: path}
+			}
+			return path, nil
+		},
+		SvcImage: func(serviceID string) (string, error) { return "localhost:5000/zenoss/core:5.0.9", nil },
+	}
+	r := newControlFlowRunner(t, script, config, &executed)
+	err := r.Run(make(chan struct{}))
+	t.Assert(err, IsNil)
+	t.Assert(r.env["VERSION"], Equals, "5.0.7")
+	t.Assert(executed, HasLen, 4)
+	t.Assert(executed[0], Matches, "service shell -s .*_Zenoss.core/Zope Zenoss.core/Zope cat /VERSION")
+	t.Assert(executed[1:], DeepEquals, []string{
+		"service run Zenoss.core/Zope upgrade 5.0.7 patch-tenant ${HOME} ${ZENHOME}",
+		"service run Zenoss.core/Zope add-redis",
+		"service run Zenoss.core/Zope old-image",
+	})
+}
+
+func (vs *ScriptSuite) Test_RunRetry(t *C) {
+	script := `
+REQUIRE_SVC
+RETRY 3
+	SVC_RUN Zenoss.core/Zope upgrade
+	SVC_START Zenoss.core/Zope
+END
+`
+	var executed []string
+	starts := 0
+	config := &Config{
+		SvcStart: func(serviceID string, recursive bool) error {
+			if starts++; starts < 3 {
+				return errors.New("start failed")
+			}
+			return nil
+		},
+	}
+	r := newControlFlowRunner(t, script, config, &executed)
+	err := r.Run(make(chan struct{}))
+	t.Assert(err, IsNil)
+	t.Assert(starts, Equals, 3)
+	t.Assert(executed, HasLen, 3)
+
+	// the attempts are used up
+	starts = -10
+	executed = nil
+	r = newControlFlowRunner(t, script, config, &executed)
+	err = r.Run(make(chan struct{}))
+	t.Assert(err, ErrorMatches, "line 3: gave up after 3 attempts: start failed")
+	t.Assert(executed, HasLen, 3)
+
+	// the timeout passes before the next attempt
+	script = `
+REQUIRE_SVC
+RETRY 3 1
+	SVC_START Zenoss.core/Zope
+END
+`
+	starts = -10
+	r = newControlFlowRunner(t, script, config, &executed)
+	r.retryDelay = 2 * time.Second
+	err = r.Run(make(chan struct{}))
+	t.Assert(err, ErrorMatches, "line 3: timed out after 1 attempts: start failed")
+}
+
+func (vs *ScriptSuite) Test_RunOnFailure(t *C) {
+	script := `
+REQUIRE_SVC
+ON_FAILURE
+	SVC_RUN Zenoss.core/Zope undo-first
+END
+SVC_RUN Zenoss.core/Zope first
+ON_FAILURE
+	SVC_START Zenoss.core/Zope
+	SVC_RUN Zenoss.core/Zope undo-second
+END
+SVC_STOP Zenoss.core/Zope
+`
+	var executed []string
+	config := &Config{
+		SvcStart: func(serviceID string, recursive bool) error { return errors.New("start failed") },
+		SvcStop:  func(serviceID string, recursive bool) error { return errors.New("stop failed") },
+	}
+	r := newControlFlowRunner(t, script, config, &executed)
+	err := r.Run(make(chan struct{}))
+	t.Assert(err, ErrorMatches, "stop failed")
+	t.Assert(executed, DeepEquals, []string{
+		"service run Zenoss.core/Zope first",
+		"service run Zenoss.core/Zope undo-second",
+		"service run Zenoss.core/Zope undo-first",
+	})
+
+	// failure steps do not run when the script succeeds
+	config.SvcStop = func(serviceID string, recursive bool) error { return nil }
+	executed = nil
+	r = newControlFlowRunner(t, script, config, &executed)
+	err = r.Run(make(chan struct{}))
+	t.Assert(err, IsNil)
+	t.Assert(executed, DeepEquals, []string{"service run Zenoss.core/Zope first"})
+}
@@ -28,6 +28,22 @@ type SnapshotRestore func(snapshotID string, forceRestart bool) error
 // ServiceIDFromPath get a service id of a service given the tenant id the path to the services
 type ServiceIDFromPath func(tenantID string, path string) (string, error)
 
+// ServiceNotFoundError is returned by a ServiceIDFromPath when no service
+// has the path
+type ServiceNotFoundError struct {
+	Path string
+}
+
+func (err ServiceNotFoundError) Error() string {
+	return fmt.Sprintf("did not find service %s", err.Path)
+}
+
+// ServiceImage gets the image id of a service
+type ServiceImage func(serviceID string) (string, error)
+
+// SnapshotLookup finds the latest snapshot of a tenant, or "" if it has none
+type SnapshotLookup func(tenantID string) (string, error)
+
 // ServiceControl is a func used to control the state of a service
 type ServiceControl func(serviceID string, recursive bool) error
 
@@ -41,6 +57,8 @@ type ServiceWait func(serviceID []string, serviceState ServiceState, timeout uin
 
 type execCmd func(string, ...string) error
 
+type execOutputCmd func(string, ...string) (string, error)
+
 type findTenant func(string) (string, error)
 
 func ScriptStateToDesiredState(state ServiceState) (service.DesiredState, error) {
@@ -62,6 +80,13 @@ func defaultExec(name string, args ...string) error {
 	return cmd.Run()
 }
 
+func defaultExecOutput(name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	return string(output), err
+}
+
 func defaultTagImage(image *docker.Image, newTag string) (*docker.Image, error) {
 	return image.Tag(newTag, true)
 }
@@ -70,6 +95,10 @@ func noOpExec(name string, args ...string) error {
 	return nil
 }
 
+func noOpExecOutput(name string, args ...string) (string, error) {
+	return "", nil
+}
+
 func noOpServiceStart(serviceID string, recursive bool) error {
 	return nil
 }
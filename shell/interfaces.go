@@ -43,7 +43,9 @@ type ProcessConfig struct {
 	Envv        []string
 	Mount       []string
 	Command     string
-	LogToStderr bool // log the command output for stderr
+	LogToStderr bool   // log the command output for stderr
+	User        string // the user who started the process, for its recording
+	Record      bool   // record the session
 	LogStash    struct {
 		Enable        bool          //enable log stash
 		SettleTime    time.Duration //how long to wait for log stash to flush logs before exiting, ex. 1s
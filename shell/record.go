@@ -0,0 +1,129 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/control-center/serviced/domain/session"
+	"github.com/control-center/serviced/rpc/master"
+	"github.com/control-center/serviced/utils"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// SessionRecording records a shell session in memory until it is saved on
+// the master
+type SessionRecording struct {
+	*Recorder
+	Session session.Session
+	buffer  bytes.Buffer
+}
+
+// NewSessionRecording starts recording a session on a terminal of the given
+// size
+func NewSessionRecording(sess session.Session, width, height int) (*SessionRecording, error) {
+	r := &SessionRecording{Session: sess}
+	r.Session.Started = time.Now()
+	rec, err := NewRecorder(&r.buffer, Header{
+		Width:     width,
+		Height:    height,
+		Timestamp: r.Session.Started.Unix(),
+		Command:   sess.Command,
+		Title:     string(sess.Kind) + " " + sess.ServiceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.Recorder = rec
+	return r, nil
+}
+
+// Save saves the recording on the master, given the result of the session's
+// command, and returns the id of the session
+func (r *SessionRecording) Save(client master.ClientInterface, cmdErr error) (string, error) {
+	r.Session.Ended = time.Now()
+	if code, ok := utils.GetExitStatus(cmdErr); ok {
+		r.Session.ExitCode = code
+	} else {
+		r.Session.ExitCode = -1
+	}
+	if err := r.Err(); err != nil {
+		plog.WithError(err).WithField("serviceid", r.Session.ServiceID).Warn("Session recording is incomplete")
+	}
+	return client.AddSession(r.Session, r.buffer.Bytes())
+}
+
+// RecordedCmd is a command started with its terminal session recorded
+type RecordedCmd struct {
+	cmd  *exec.Cmd
+	done func()
+}
+
+// RunRecorded runs a command attached to the terminal of the current process
+// and records the session.
+func RunRecorded(cmd *exec.Cmd, rec *Recorder) error {
+	c, err := StartRecorded(cmd, rec)
+	if err != nil {
+		return err
+	}
+	return c.Wait()
+}
+
+// StartRecorded starts a command attached to the terminal of the current
+// process and records the session.  When stdin is a terminal the command is
+// given a pseudo-terminal of its own, so that what the operator types and
+// sees can be captured the way script(1) does; otherwise its input and
+// output are copied through the recorder.
+func StartRecorded(cmd *exec.Cmd, rec *Recorder) (*RecordedCmd, error) {
+	if terminal.IsTerminal(int(os.Stdin.Fd())) {
+		c, err := startTerminal(cmd, rec)
+		if err != errNoTerminal {
+			return c, err
+		}
+	}
+	return startPiped(cmd, rec)
+}
+
+// Wait waits for the command to exit and for its output to be recorded
+func (c *RecordedCmd) Wait() error {
+	err := c.cmd.Wait()
+	c.done()
+	return err
+}
+
+// startPiped starts a command whose standard streams are copied through the
+// recorder.
+func startPiped(cmd *exec.Cmd, rec *Recorder) (*RecordedCmd, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stdout = io.MultiWriter(os.Stdout, rec.Output())
+	cmd.Stderr = io.MultiWriter(os.Stderr, rec.Output())
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	// exec.Cmd would wait for stdin to be closed before returning from Wait,
+	// so it is copied here instead
+	go func() {
+		io.Copy(io.MultiWriter(stdin, rec.Input()), os.Stdin)
+		stdin.Close()
+	}()
+	return &RecordedCmd{cmd: cmd, done: func() {}}, nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux
+
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+var errNoTerminal = errors.New("could not allocate a pseudo-terminal")
+
+type winsize struct {
+	Rows, Cols, X, Y uint16
+}
+
+func ioctl(fd, request, arg uintptr) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, request, arg); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// openPTY allocates a pseudo-terminal and returns its master and slave ends
+func openPTY() (*os.File, *os.File, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	var unlock int32
+	if err := ioctl(master.Fd(), syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+	var n uint32
+	if err := ioctl(master.Fd(), syscall.TIOCGPTN, uintptr(unsafe.Pointer(&n))); err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+	slave, err := os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+	return master, slave, nil
+}
+
+// copySize copies the window size of the terminal on stdin to the
+// pseudo-terminal and returns it
+func copySize(master *os.File) (winsize, error) {
+	var ws winsize
+	if err := ioctl(os.Stdin.Fd(), syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&ws))); err != nil {
+		return ws, err
+	}
+	return ws, ioctl(master.Fd(), syscall.TIOCSWINSZ, uintptr(unsafe.Pointer(&ws)))
+}
+
+func startTerminal(cmd *exec.Cmd, rec *Recorder) (*RecordedCmd, error) {
+	master, slave, err := openPTY()
+	if err != nil {
+		plog.WithError(err).Debug("Unable to allocate a pseudo-terminal")
+		return nil, errNoTerminal
+	}
+	defer slave.Close()
+	copySize(master)
+
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	cmd.SysProcAttr.Ctty = 0
+	if err := cmd.Start(); err != nil {
+		master.Close()
+		return nil, err
+	}
+
+	state, err := terminal.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		plog.WithError(err).Warn("Unable to put the terminal into raw mode")
+	}
+
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	go func() {
+		for range winch {
+			if ws, err := copySize(master); err == nil {
+				rec.Resize(int(ws.Cols), int(ws.Rows))
+			}
+		}
+	}()
+
+	go io.Copy(io.MultiWriter(master, rec.Input()), os.Stdin)
+	output := make(chan struct{})
+	go func() {
+		// reading the master fails once every process on the slave is gone
+		io.Copy(io.MultiWriter(os.Stdout, rec.Output()), master)
+		close(output)
+	}()
+
+	done := func() {
+		<-output
+		signal.Stop(winch)
+		close(winch)
+		if state != nil {
+			terminal.Restore(int(os.Stdin.Fd()), state)
+		}
+		master.Close()
+	}
+	return &RecordedCmd{cmd: cmd, done: done}, nil
+}
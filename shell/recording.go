@@ -0,0 +1,194 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shell
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrInvalidRecording is returned when a recording is not in asciicast v2
+// format
+var ErrInvalidRecording = errors.New("not an asciicast v2 recording")
+
+const (
+	eventInput  = "i"
+	eventOutput = "o"
+	eventResize = "r"
+)
+
+// Header is the first line of an asciicast v2 recording
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Command   string            `json:"command,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Recorder writes the input and output of a session, with their timing, in
+// asciicast v2 format.  Errors writing the recording never interrupt the
+// session; the first one is returned by Err.
+type Recorder struct {
+	mu      sync.Mutex
+	w       io.Writer
+	start   time.Time
+	pending map[string][]byte
+	err     error
+}
+
+// NewRecorder writes the header of a recording and returns a Recorder for its
+// events
+func NewRecorder(w io.Writer, header Header) (*Recorder, error) {
+	start := time.Now()
+	header.Version = 2
+	if header.Timestamp == 0 {
+		header.Timestamp = start.Unix()
+	}
+	data, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fmt.Fprintf(w, "%s\n", data); err != nil {
+		return nil, err
+	}
+	return &Recorder{w: w, start: start, pending: make(map[string][]byte)}, nil
+}
+
+// Input returns a writer that records what is typed in the session
+func (r *Recorder) Input() io.Writer {
+	return recorderStream{r, eventInput}
+}
+
+// Output returns a writer that records what the session displays
+func (r *Recorder) Output() io.Writer {
+	return recorderStream{r, eventOutput}
+}
+
+// Resize records a change in the size of the terminal
+func (r *Recorder) Resize(width, height int) {
+	r.record(eventResize, []byte(fmt.Sprintf("%dx%d", width, height)))
+}
+
+// Err returns the first error writing the recording
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// record writes an event.  A multibyte character split across writes is held
+// back until it is complete, because events must be valid UTF-8.
+func (r *Recorder) record(code string, p []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	data := append(r.pending[code], p...)
+	r.pending[code] = nil
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				r.pending[code] = append([]byte{}, data[i:]...)
+				data = data[:i]
+			}
+			break
+		}
+	}
+	if len(data) == 0 {
+		return
+	}
+	elapsed := time.Since(r.start).Seconds()
+	event, err := json.Marshal([]interface{}{elapsed, code, string(data)})
+	if err != nil {
+		r.err = err
+		return
+	}
+	if _, err := fmt.Fprintf(r.w, "%s\n", event); err != nil {
+		r.err = err
+	}
+}
+
+type recorderStream struct {
+	r    *Recorder
+	code string
+}
+
+func (s recorderStream) Write(p []byte) (int, error) {
+	s.r.record(s.code, p)
+	return len(p), nil
+}
+
+// sleep waits between the events of a recording being played back; it is a
+// variable so that tests do not have to wait
+var sleep = time.Sleep
+
+// Play writes the output of a recording to w with its original timing.  The
+// timing is divided by speed, and pauses longer than maxIdle are shortened to
+// maxIdle unless it is 0.
+func Play(r io.Reader, w io.Writer, speed float64, maxIdle time.Duration) (*Header, error) {
+	if speed <= 0 {
+		speed = 1
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRecording
+	}
+	header := &Header{}
+	if err := json.Unmarshal(scanner.Bytes(), header); err != nil || header.Version != 2 {
+		return nil, ErrInvalidRecording
+	}
+
+	last := 0.0
+	for scanner.Scan() {
+		var event []interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || len(event) != 3 {
+			return header, ErrInvalidRecording
+		}
+		elapsed, ok := event[0].(float64)
+		code, ok2 := event[1].(string)
+		data, ok3 := event[2].(string)
+		if !ok || !ok2 || !ok3 {
+			return header, ErrInvalidRecording
+		}
+		if code != eventOutput {
+			continue
+		}
+		delay := time.Duration((elapsed - last) / speed * float64(time.Second))
+		if maxIdle > 0 && delay > maxIdle {
+			delay = maxIdle
+		}
+		if delay > 0 {
+			sleep(delay)
+		}
+		last = elapsed
+		if _, err := io.WriteString(w, data); err != nil {
+			return header, err
+		}
+	}
+	return header, scanner.Err()
+}
@@ -0,0 +1,121 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package shell
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	buf := &bytes.Buffer{}
+	rec, err := NewRecorder(buf, Header{Width: 80, Height: 24, Command: "/bin/bash"})
+	assert.Nil(t, err)
+
+	rec.Input().Write([]byte("ls\r"))
+	rec.Output().Write([]byte("caf\xc3"))
+	rec.Output().Write([]byte("\xa9\r\n"))
+	rec.Resize(100, 40)
+	assert.Nil(t, rec.Err())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 5)
+
+	header := Header{}
+	assert.Nil(t, json.Unmarshal([]byte(lines[0]), &header))
+	assert.Equal(t, 2, header.Version)
+	assert.Equal(t, 80, header.Width)
+	assert.Equal(t, "/bin/bash", header.Command)
+	assert.NotZero(t, header.Timestamp)
+
+	expected := [][2]string{{"i", "ls\r"}, {"o", "caf"}, {"o", "é\r\n"}, {"r", "100x40"}}
+	for i, line := range lines[1:] {
+		var event []interface{}
+		assert.Nil(t, json.Unmarshal([]byte(line), &event))
+		assert.Len(t, event, 3)
+		assert.Equal(t, expected[i][0], event[1])
+		assert.Equal(t, expected[i][1], event[2])
+	}
+}
+
+type failingWriter struct {
+	n int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.n == 0 {
+		return 0, assert.AnError
+	}
+	w.n--
+	return len(p), nil
+}
+
+func TestRecorder_WriteError(t *testing.T) {
+	rec, err := NewRecorder(&failingWriter{n: 1}, Header{})
+	assert.Nil(t, err)
+	n, err := rec.Output().Write([]byte("hello"))
+	assert.Equal(t, 5, n)
+	assert.Nil(t, err)
+	assert.Equal(t, assert.AnError, rec.Err())
+}
+
+func TestPlay(t *testing.T) {
+	var delays []time.Duration
+	sleep = func(d time.Duration) { delays = append(delays, d) }
+	defer func() { sleep = time.Sleep }()
+
+	recording := `{"version":2,"width":80,"height":24}
+[0.5,"o","$ "]
+[1.0,"i","ls\r"]
+[1.5,"o","ls\r\n"]
+[61.5,"o","done\r\n"]
+`
+	out := &bytes.Buffer{}
+	header, err := Play(strings.NewReader(recording), out, 2, 10*time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, 80, header.Width)
+	assert.Equal(t, "$ ls\r\ndone\r\n", out.String())
+	assert.Equal(t, []time.Duration{250 * time.Millisecond, 500 * time.Millisecond, 10 * time.Second}, delays)
+}
+
+func TestPlay_Invalid(t *testing.T) {
+	out := &bytes.Buffer{}
+	_, err := Play(strings.NewReader(""), out, 1, 0)
+	assert.Equal(t, ErrInvalidRecording, err)
+	_, err = Play(strings.NewReader(`{"version":1}`), out, 1, 0)
+	assert.Equal(t, ErrInvalidRecording, err)
+	_, err = Play(strings.NewReader("{\"version\":2}\n[\"x\"]\n"), out, 1, 0)
+	assert.Equal(t, ErrInvalidRecording, err)
+}
+
+func TestStartPiped(t *testing.T) {
+	buf := &bytes.Buffer{}
+	rec, err := NewRecorder(buf, Header{})
+	assert.Nil(t, err)
+
+	c, err := startPiped(exec.Command("sh", "-c", "echo hello; exit 3"), rec)
+	assert.Nil(t, err)
+	err = c.Wait()
+	assert.NotNil(t, err)
+	assert.Nil(t, rec.Err())
+	assert.Contains(t, buf.String(), `,"o","hello\n"]`)
+}
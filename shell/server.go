@@ -16,6 +16,7 @@ package shell
 import (
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -27,6 +28,7 @@ import (
 	"github.com/control-center/go-socket.io"
 
 	"github.com/control-center/serviced/domain/service"
+	"github.com/control-center/serviced/domain/session"
 	"github.com/control-center/serviced/logging"
 	worker "github.com/control-center/serviced/rpc/agent"
 	"github.com/control-center/serviced/rpc/master"
@@ -278,13 +280,27 @@ func (e *Executor) Exec(cfg *ProcessConfig) (p *ProcessInstance) {
 		return
 	}
 
-	cmd.Stdin = ShellReader{p.Stdin}
-	cmd.Stdout = ShellWriter{p.Stdout}
-	cmd.Stderr = ShellWriter{p.Stderr}
+	rec, err := e.startRecording(cfg)
+	if err != nil {
+		p.Result <- Result{0, err.Error(), ABNORMAL}
+		return
+	}
+	if rec != nil {
+		cmd.Stdin = io.TeeReader(ShellReader{p.Stdin}, rec.Input())
+		cmd.Stdout = io.MultiWriter(ShellWriter{p.Stdout}, rec.Output())
+		cmd.Stderr = io.MultiWriter(ShellWriter{p.Stderr}, rec.Output())
+	} else {
+		cmd.Stdin = ShellReader{p.Stdin}
+		cmd.Stdout = ShellWriter{p.Stdout}
+		cmd.Stderr = ShellWriter{p.Stderr}
+	}
 
 	go func() {
 		defer p.Close()
 		err := cmd.Run()
+		if rec != nil {
+			e.saveRecording(rec, err)
+		}
 		if exitcode, ok := utils.GetExitStatus(err); !ok {
 			p.Result <- Result{exitcode, err.Error(), ABNORMAL}
 		} else if exitcode == 0 {
@@ -297,6 +313,48 @@ func (e *Executor) Exec(cfg *ProcessConfig) (p *ProcessInstance) {
 	return
 }
 
+// startRecording returns a recording for the session if it was asked for or
+// the tenant of the service requires one, and nil otherwise
+func (e *Executor) startRecording(cfg *ProcessConfig) (*SessionRecording, error) {
+	masterClient, err := master.NewClient(e.masterAddress)
+	if err != nil {
+		return nil, err
+	}
+	defer masterClient.Close()
+	required, err := masterClient.SessionRecordingRequired(cfg.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.Record && !required {
+		return nil, nil
+	}
+	hostID, _ := utils.HostID()
+	return NewSessionRecording(session.Session{
+		Kind:       session.KindShell,
+		User:       cfg.User,
+		HostID:     hostID,
+		ServiceID:  cfg.ServiceID,
+		InstanceID: -1,
+		Command:    cfg.Command,
+	}, 80, 24)
+}
+
+// saveRecording saves the recording of a session on the master
+func (e *Executor) saveRecording(rec *SessionRecording, cmdErr error) {
+	logger := plog.WithField("serviceid", rec.Session.ServiceID)
+	masterClient, err := master.NewClient(e.masterAddress)
+	if err != nil {
+		logger.WithError(err).Error("Unable to save session recording")
+		return
+	}
+	defer masterClient.Close()
+	if sessionID, err := rec.Save(masterClient, cmdErr); err != nil {
+		logger.WithError(err).Error("Unable to save session recording")
+	} else {
+		logger.WithField("sessionid", sessionID).Info("Saved session recording")
+	}
+}
+
 func (e *Executor) onDisconnect(ns *socketio.NameSpace) {
 	inst := ns.Session.Values[PROCESSKEY].(*ProcessInstance)
 	inst.Disconnect()
@@ -0,0 +1,79 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package stats collects serviced metrics and posts them to the TSDB.
+package stats
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/control-center/serviced/utils"
+)
+
+// RejectionCountsFunc returns the number of clients each public endpoint has
+// rejected, by reason
+type RejectionCountsFunc func() map[string]map[string]int64
+
+// PublicEndpointStatsReporter collects and posts public endpoint stats to
+// the TSDB.
+type PublicEndpointStatsReporter struct {
+	statsReporter
+	hostID     string
+	rejections RejectionCountsFunc
+	counts     map[string]map[string]int64
+}
+
+// NewPublicEndpointStatsReporter creates a new PublicEndpointStatsReporter
+// and kicks off the reporting goroutine.
+func NewPublicEndpointStatsReporter(destination string, interval time.Duration, rejections RejectionCountsFunc) (*PublicEndpointStatsReporter, error) {
+	hostID, err := utils.HostID()
+	if err != nil {
+		plog.WithError(err).Debug("Could not determine host ID")
+		return nil, err
+	}
+
+	sr := PublicEndpointStatsReporter{
+		statsReporter: statsReporter{
+			destination:  destination,
+			closeChannel: make(chan struct{}),
+		},
+		hostID:     hostID,
+		rejections: rejections,
+	}
+
+	sr.statsReporter.updateStatsFunc = sr.updateStats
+	sr.statsReporter.gatherStatsFunc = sr.gatherStats
+	go sr.report(interval)
+	return &sr, nil
+}
+
+// Fills out the metric consumer format.
+func (sr *PublicEndpointStatsReporter) gatherStats(t time.Time) []Sample {
+	stats := []Sample{}
+	for endpoint, counts := range sr.counts {
+		for reason, count := range counts {
+			tagmap := map[string]string{
+				"controlplane_host_id": sr.hostID,
+				"publicendpoint":       endpoint,
+				"reason":               reason,
+			}
+			stats = append(stats, Sample{"publicendpoint.rejected", strconv.FormatInt(count, 10), t.Unix(), tagmap})
+		}
+	}
+	return stats
+}
+
+func (sr *PublicEndpointStatsReporter) updateStats() {
+	sr.counts = sr.rejections()
+}
@@ -43,6 +43,12 @@ func ExecDockerExec(containerID string, bashcmd []string) error {
 	return syscall.Exec(command[0], command[0:], os.Environ())
 }
 
+// DockerExecCommand returns the docker exec command that attaches to a
+// container and runs the command
+func DockerExecCommand(containerID string, bashcmd []string) ([]string, error) {
+	return generateDockerExecCommand(containerID, bashcmd, false)
+}
+
 // RunDockerExec runs the command using docker exec
 func RunDockerExec(containerID string, bashcmd []string) ([]byte, error) {
 	oldStdin := os.Stdin
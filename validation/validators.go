@@ -16,10 +16,16 @@ package validation
 import (
 	"fmt"
 	"net"
+	"regexp"
 	"strconv"
 	"strings"
 )
 
+var (
+	labelKeyRegex   = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]*$`)
+	labelValueRegex = regexp.MustCompile(`^[A-Za-z0-9._/-]*$`)
+)
+
 //NotEmpty check to see if the value is not an empty string or a string with just whitespace characters, returns an
 // error if empty. FieldName is used to create a meaningful error
 func NotEmpty(fieldName string, value string) error {
@@ -151,3 +157,15 @@ func ValidVirtualIP(bindInterface string) error {
 	}
 	return nil
 }
+
+// ValidLabel checks that a label can be written as KEY=VALUE.  Keys must not
+// be empty; values may be.
+func ValidLabel(key, value string) error {
+	if !labelKeyRegex.MatchString(key) {
+		return NewViolation(fmt.Sprintf("invalid label key: %q", key))
+	}
+	if !labelValueRegex.MatchString(value) {
+		return NewViolation(fmt.Sprintf("invalid value for label %s: %q", key, value))
+	}
+	return nil
+}
@@ -177,3 +177,14 @@ func (vs *ValidationSuite) Test_ExcludeString(c *C) {
 	err = ExcludeChars("field", "", "")
 	c.Assert(err, IsNil)
 }
+
+func (vs *ValidationSuite) Test_ValidLabel(c *C) {
+	c.Assert(ValidLabel("ssd", "true"), IsNil)
+	c.Assert(ValidLabel("zone", ""), IsNil)
+	c.Assert(ValidLabel("example.com/rack", "r-12"), IsNil)
+	c.Assert(ValidLabel("", "true"), NotNil)
+	c.Assert(ValidLabel("-ssd", "true"), NotNil)
+	c.Assert(ValidLabel("ssd=true", ""), NotNil)
+	c.Assert(ValidLabel("ssd", "a b"), NotNil)
+	c.Assert(ValidLabel("ssd", "a,b"), NotNil)
+}
@@ -41,6 +41,7 @@ var (
 	ErrBtrfsInvalidLabel      = errors.New("invalid label")
 	ErrBtrfsListingSnapshots  = errors.New("couldn't list snapshots")
 	ErrBtrfsNotSupported      = errors.New("operation not supported on btrfs driver")
+	ErrBtrfsIncrementalExport = errors.New("incremental backups are not supported on btrfs driver")
 )
 
 func init() {
@@ -596,6 +597,12 @@ func (v *BtrfsVolume) Rollback(label string) error {
 
 // Export implements volume.Volume.Export
 func (v *BtrfsVolume) Export(label, parent string, writer io.Writer, excludes []string) error {
+	// btrfs send -p writes a send stream rather than the tar with whiteouts
+	// that incremental backups are made of
+	if strings.TrimSpace(parent) != "" {
+		glog.Errorf("%s: cannot export the changes since snapshot %s", volume.DriverTypeBtrFS, parent)
+		return ErrBtrfsIncrementalExport
+	}
 	if len(excludes) > 0 {
 		glog.Warning("btrfs backups do not support excluding directories")
 	}
@@ -608,7 +615,7 @@ func (v *BtrfsVolume) Export(label, parent string, writer io.Writer, excludes []
 		return volume.ErrSnapshotDoesNotExist
 	}
 	// TODO: add to tarfile and include metadata
-	if err := runBtrfsSend(writer, v.sudoer, parent, v.snapshotPath(label)); err != nil {
+	if err := runBtrfsSend(writer, v.sudoer, v.snapshotPath(label)); err != nil {
 		glog.Errorf("Could not export snapshot %s: %s", label, err)
 		return err
 	}
@@ -657,11 +664,8 @@ func (v *BtrfsVolume) snapshotExists(label string) (exists bool, err error) {
 }
 
 // runBtrfsSend writes a btrfs snapshot to a write handle
-func runBtrfsSend(writer io.Writer, sudoer bool, parentpath, path string) error {
+func runBtrfsSend(writer io.Writer, sudoer bool, path string) error {
 	cmdArgs := []string{"btrfs", "send", path}
-	if parentpath = strings.TrimSpace(parentpath); parentpath != "" {
-		cmdArgs = append(cmdArgs, "-p", parentpath)
-	}
 	if sudoer {
 		cmdArgs = append([]string{"sudo", "-n"}, cmdArgs...)
 	}
@@ -18,6 +18,7 @@ package btrfs
 import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
 	"math"
 	"testing"
 )
@@ -236,3 +237,9 @@ func TestParseDF(t *testing.T) {
 		assert.Equal(t, result, tc.out, fmt.Sprintf("%s: %s", tc.label, tc.outmsg))
 	}
 }
+
+func TestExportIncremental(t *testing.T) {
+	v := &BtrfsVolume{}
+	err := v.Export("snapshot", "parent", ioutil.Discard, nil)
+	assert.Equal(t, ErrBtrfsIncrementalExport, err)
+}
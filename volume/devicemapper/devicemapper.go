@@ -1271,32 +1271,24 @@ func (v *DeviceMapperVolume) Export(label, parent string, writer io.Writer, excl
 		return volume.ErrSnapshotDoesNotExist
 	}
 	label = v.rawSnapshotLabel(label)
-	mountpoint, err := ioutil.TempDir("", "serviced-export-volume-")
-	if err != nil {
-		return err
-	}
-	defer os.RemoveAll(mountpoint)
-	deviceHash, err := v.Metadata.LookupSnapshotDevice(label)
+	mountpoint, unmount, err := v.mountSnapshot(label)
 	if err != nil {
 		return err
 	}
-	glog.V(2).Infof("Mounting temporary export device %s", deviceHash)
-	if err := v.driver.DeviceSet.MountDevice(deviceHash, mountpoint, label); err != nil {
-		return err
-	}
-	defer func(d *DeviceMapperDriver, deviceHash, mountpoint string) {
-		// We use the provided UnmountDevice func here, rather than our own
-		// unmount(), because we DO care about Docker's internal bookkeeping
-		// here. Without this, DeviceSet.DeleteDevice will fail.
-		if err := d.DeviceSet.UnmountDevice(deviceHash, mountpoint); err != nil {
-			glog.V(2).Infof("Error unmounting %s (device: %s): %s", mountpoint, deviceHash, err)
+	defer unmount()
+
+	// Mount the parent snapshot to find the changes since it was taken
+	var parentMountpoint string
+	if parent = strings.TrimSpace(parent); parent != "" {
+		if !v.snapshotExists(parent) {
+			return volume.ErrSnapshotDoesNotExist
 		}
-		d.DeviceSet.Lock()
-		if err := d.DeactivateDevice(deviceHash); err != nil {
-			glog.V(2).Infof("Error deactivating device %s: %s", deviceHash, err)
+		var unmountParent func()
+		if parentMountpoint, unmountParent, err = v.mountSnapshot(v.rawSnapshotLabel(parent)); err != nil {
+			return err
 		}
-		d.DeviceSet.Unlock()
-	}(v.driver, deviceHash, mountpoint)
+		defer unmountParent()
+	}
 
 	tarOut := tar.NewWriter(writer)
 
@@ -1339,13 +1331,51 @@ func (v *DeviceMapperVolume) Export(label, parent string, writer io.Writer, excl
 	if err := exportDirectoryAsTar(mdpath, fmt.Sprintf("%s-metadata", label), tarOut, []string{}); err != nil {
 		return err
 	}
-	if err := exportDirectoryAsTar(mountpoint, fmt.Sprintf("%s-volume", label), tarOut, excludes); err != nil {
+	if parentMountpoint != "" {
+		if err := volume.ExportDirectoryChanges(tarOut, mountpoint, parentMountpoint, fmt.Sprintf("%s-volume", label), excludes); err != nil {
+			return err
+		}
+	} else if err := exportDirectoryAsTar(mountpoint, fmt.Sprintf("%s-volume", label), tarOut, excludes); err != nil {
 		return err
 	}
 
 	return tarOut.Close()
 }
 
+// mountSnapshot mounts the device of a snapshot on a temporary mountpoint
+// and returns the mountpoint and a function that unmounts it.
+func (v *DeviceMapperVolume) mountSnapshot(label string) (string, func(), error) {
+	mountpoint, err := ioutil.TempDir("", "serviced-export-volume-")
+	if err != nil {
+		return "", nil, err
+	}
+	deviceHash, err := v.Metadata.LookupSnapshotDevice(label)
+	if err != nil {
+		os.RemoveAll(mountpoint)
+		return "", nil, err
+	}
+	glog.V(2).Infof("Mounting temporary export device %s", deviceHash)
+	if err := v.driver.DeviceSet.MountDevice(deviceHash, mountpoint, label); err != nil {
+		os.RemoveAll(mountpoint)
+		return "", nil, err
+	}
+	d := v.driver
+	return mountpoint, func() {
+		// We use the provided UnmountDevice func here, rather than our own
+		// unmount(), because we DO care about Docker's internal bookkeeping
+		// here. Without this, DeviceSet.DeleteDevice will fail.
+		if err := d.DeviceSet.UnmountDevice(deviceHash, mountpoint); err != nil {
+			glog.V(2).Infof("Error unmounting %s (device: %s): %s", mountpoint, deviceHash, err)
+		}
+		d.DeviceSet.Lock()
+		if err := d.DeactivateDevice(deviceHash); err != nil {
+			glog.V(2).Infof("Error deactivating device %s: %s", deviceHash, err)
+		}
+		d.DeviceSet.Unlock()
+		os.RemoveAll(mountpoint)
+	}, nil
+}
+
 func (d *DeviceMapperDriver) Status() (volume.Status, error) {
 	glog.V(2).Info("devicemapper.Status()")
 	dockerStatus := d.DeviceSet.Status()
@@ -15,9 +15,12 @@ package volume
 
 import (
 	"archive/tar"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"syscall"
 
 	"github.com/zenoss/glog"
@@ -120,6 +123,126 @@ func ExportFile(tarfile *tar.Writer, path, name string) error {
 	return nil
 }
 
+// WhiteoutPrefix starts the base name of an entry of an incremental export
+// that marks a path removed since the parent snapshot, the way docker marks
+// removed files in an image layer.
+const WhiteoutPrefix = ".wh."
+
+// ExportDirectoryChanges recursively writes the changes to a directory since
+// the parent directory into a tar Writer.  Directories are always written,
+// while files and symlinks are only written if they are new or their type,
+// size, mode, owner, modification time or link target changed.  Paths of the
+// parent that no longer exist are written as empty entries named with
+// WhiteoutPrefix.  Paths relative to the directory that match one of the
+// excludes are left out.
+func ExportDirectoryChanges(tarfile *tar.Writer, path, parent, name string, excludes []string) error {
+	excluded := make(map[string]struct{})
+	for _, exclude := range excludes {
+		exclude = strings.Trim(filepath.Clean(exclude), "/")
+		excluded[exclude] = struct{}{}
+		excluded[filepath.Join(filepath.Dir(exclude), fmt.Sprintf(".%s.serviced.initialized", filepath.Base(exclude)))] = struct{}{}
+	}
+	return exportChanges(tarfile, path, parent, name, "", excluded)
+}
+
+func exportChanges(tarfile *tar.Writer, path, parent, name, relpath string, excluded map[string]struct{}) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		glog.Errorf("Could not open %s: %s", path, err)
+		return err
+	}
+	defer dir.Close()
+	fstat, err := dir.Stat()
+	if err != nil {
+		glog.Errorf("Could not stat %s: %s", path, err)
+		return err
+	}
+	header, err := getHeader(name, "", fstat)
+	if err != nil {
+		return err
+	}
+	if err := tarfile.WriteHeader(header); err != nil {
+		glog.Errorf("Could not write header for directory %s: %s", path, err)
+		return err
+	}
+	files, err := dir.Readdir(0)
+	if err != nil {
+		glog.Errorf("Could not list directory for %s: %s", path, err)
+		return err
+	}
+	found := make(map[string]struct{})
+	for _, finfo := range files {
+		child := filepath.Join(relpath, finfo.Name())
+		if _, ok := excluded[child]; ok {
+			continue
+		}
+		found[finfo.Name()] = struct{}{}
+		fullpath, parentpath, entry := filepath.Join(path, finfo.Name()), filepath.Join(parent, finfo.Name()), filepath.Join(name, finfo.Name())
+		if finfo.IsDir() {
+			if err := exportChanges(tarfile, fullpath, parentpath, entry, child, excluded); err != nil {
+				return err
+			}
+		} else if !unchangedFile(fullpath, finfo, parentpath) {
+			if err := ExportFile(tarfile, fullpath, entry); err != nil {
+				return err
+			}
+		}
+	}
+
+	// write the whiteouts for the paths that were removed
+	if pstat, err := os.Lstat(parent); err != nil || !pstat.IsDir() {
+		return nil
+	}
+	pfiles, err := ioutil.ReadDir(parent)
+	if err != nil {
+		glog.Errorf("Could not list parent directory %s: %s", parent, err)
+		return err
+	}
+	for _, finfo := range pfiles {
+		if _, ok := found[finfo.Name()]; ok {
+			continue
+		} else if _, ok := excluded[filepath.Join(relpath, finfo.Name())]; ok {
+			continue
+		}
+		whiteout := &tar.Header{
+			Name:     filepath.Join(name, WhiteoutPrefix+finfo.Name()),
+			Typeflag: tar.TypeReg,
+			Mode:     0644,
+			ModTime:  fstat.ModTime(),
+		}
+		if err := tarfile.WriteHeader(whiteout); err != nil {
+			glog.Errorf("Could not write whiteout for %s: %s", filepath.Join(parent, finfo.Name()), err)
+			return err
+		}
+	}
+	return nil
+}
+
+// unchangedFile returns true if the file at path has the same stamp as the
+// file at the parent path
+func unchangedFile(path string, fstat os.FileInfo, parentpath string) bool {
+	pstat, err := os.Lstat(parentpath)
+	if err != nil {
+		return false
+	}
+	if fstat.Mode() != pstat.Mode() || fstat.Size() != pstat.Size() || !fstat.ModTime().Equal(pstat.ModTime()) {
+		return false
+	}
+	sys, psys := fstat.Sys().(*syscall.Stat_t), pstat.Sys().(*syscall.Stat_t)
+	if sys.Uid != psys.Uid || sys.Gid != psys.Gid {
+		return false
+	}
+	if isSymLink(fstat) {
+		link, err := os.Readlink(path)
+		if err != nil {
+			return false
+		}
+		plink, err := os.Readlink(parentpath)
+		return err == nil && link == plink
+	}
+	return true
+}
+
 // ImportArchive reads from a tar Reader and writes the contents into a path
 // preserving file permissions and ownership.
 func ImportArchive(tarfile *tar.Reader, path string) error {
@@ -679,6 +679,16 @@ func (v *RsyncVolume) Export(label, parent string, writer io.Writer, excludes []
 	}
 	// write volume
 	volpath := v.snapshotPath(label)
+	if parent = strings.TrimSpace(parent); parent != "" {
+		// only write the changes since the parent snapshot
+		parentpath := v.snapshotPath(parent)
+		if exists, err := volume.IsDir(parentpath); err != nil {
+			return err
+		} else if !exists {
+			return volume.ErrSnapshotDoesNotExist
+		}
+		return volume.ExportDirectoryChanges(tarfile, volpath, parentpath, fmt.Sprintf("%s-volume", label), nil)
+	}
 	if err := volume.ExportDirectory(tarfile, volpath, fmt.Sprintf("%s-volume", label)); err != nil {
 		return err
 	}
@@ -16,6 +16,9 @@
 package volume_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -85,3 +88,48 @@ func (s *UtilsSuite) TestFileInfoSlice(c *C) {
 
 	c.Assert(labels, DeepEquals, expected)
 }
+
+func (s *UtilsSuite) TestExportDirectoryChanges(c *C) {
+	parent, current := c.MkDir(), c.MkDir()
+	mtime := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	write := func(root, name, data string) {
+		filename := filepath.Join(root, name)
+		c.Assert(os.MkdirAll(filepath.Dir(filename), 0755), IsNil)
+		c.Assert(ioutil.WriteFile(filename, []byte(data), 0644), IsNil)
+		c.Assert(os.Chtimes(filename, mtime, mtime), IsNil)
+	}
+	for _, root := range []string{parent, current} {
+		write(root, "same", "same")
+		write(root, "dir/same", "same")
+		write(root, "excluded/file", "data")
+	}
+	write(parent, "changed", "old")
+	write(current, "changed", "newer")
+	write(parent, "removed", "removed")
+	write(parent, "removeddir/file", "removed")
+	write(current, "added", "added")
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	c.Assert(ExportDirectoryChanges(tw, current, parent, "vol", []string{"excluded"}), IsNil)
+	c.Assert(tw.Close(), IsNil)
+
+	names := make(map[string]bool)
+	tr := tar.NewReader(buf)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, IsNil)
+		names[hdr.Name] = true
+	}
+	c.Assert(names, DeepEquals, map[string]bool{
+		"vol":                true,
+		"vol/added":          true,
+		"vol/changed":        true,
+		"vol/dir":            true,
+		"vol/.wh.removed":    true,
+		"vol/.wh.removeddir": true,
+	})
+}
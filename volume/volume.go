@@ -155,7 +155,8 @@ type Volume interface {
 	UntagSnapshot(tagName string) (string, error)
 	// GetSnapshotWithTag returns info about the snapshot with the given tag, or nil if there isn't one
 	GetSnapshotWithTag(tagName string) (*SnapshotInfo, error)
-	// Export exports the snapshot stored as <label> to <filename>
+	// Export exports the snapshot stored as <label> to <filename>.  If
+	// <parent> is set, only the changes since that snapshot are exported.
 	Export(label, parent string, writer io.Writer, excludes []string) error
 	// Import imports the exported snapshot at <filename> as <label>
 	Import(label string, reader io.Reader) error
@@ -0,0 +1,242 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/domain/servicedefinition"
+)
+
+var (
+	// ErrAccessDenied is returned when a client's address is not allowed
+	// by the access policy of a public endpoint
+	ErrAccessDenied = errors.New("client address is not allowed")
+
+	// ErrRateLimited is returned when a client has exceeded the rate limit
+	// of a public endpoint
+	ErrRateLimited = errors.New("client rate limit exceeded")
+)
+
+// rateLimitWindow is the period over which an endpoint's rate limit applies
+const rateLimitWindow = time.Minute
+
+// AccessFilter enforces the access policy of a public endpoint
+type AccessFilter struct {
+	name    string
+	mu      *sync.RWMutex
+	allow   []*net.IPNet
+	deny    []*net.IPNet
+	limiter *rateLimiter
+}
+
+// NewAccessFilter creates a filter that allows every client until a policy
+// is set
+func NewAccessFilter(name string) *AccessFilter {
+	return &AccessFilter{name: name, mu: &sync.RWMutex{}}
+}
+
+// Set replaces the policy of the filter.  A nil policy allows every client.
+func (f *AccessFilter) Set(policy *servicedefinition.AccessPolicy) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.allow, f.deny, f.limiter = nil, nil, nil
+	if policy.IsZero() {
+		return
+	}
+	parse := func(cidrs []string) []*net.IPNet {
+		networks := []*net.IPNet{}
+		for _, cidr := range cidrs {
+			network, err := servicedefinition.ParseCIDR(cidr)
+			if err != nil {
+				plog.WithError(err).WithField("endpoint", f.name).Warn("Ignoring invalid network in access policy")
+				continue
+			}
+			networks = append(networks, network)
+		}
+		return networks
+	}
+	f.allow = parse(policy.Allow)
+	f.deny = parse(policy.Deny)
+	if policy.RateLimit > 0 {
+		f.limiter = newRateLimiter(policy.RateLimit, rateLimitWindow)
+	}
+}
+
+// Check returns nil if the client at the address may use the endpoint.
+// Rejected clients are counted.
+func (f *AccessFilter) Check(remoteAddr string) error {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if err := f.check(ip); err != nil {
+		rejections.add(f.name, err)
+		plog.WithFields(log.Fields{
+			"endpoint": f.name,
+			"client":   host,
+		}).WithError(err).Debug("Rejected client of public endpoint")
+		return err
+	}
+	return nil
+}
+
+func (f *AccessFilter) check(ip net.IP) error {
+	if ip == nil {
+		if len(f.allow) > 0 || len(f.deny) > 0 || f.limiter != nil {
+			return ErrAccessDenied
+		}
+		return nil
+	}
+	for _, network := range f.deny {
+		if network.Contains(ip) {
+			return ErrAccessDenied
+		}
+	}
+	if len(f.allow) > 0 {
+		allowed := false
+		for _, network := range f.allow {
+			if network.Contains(ip) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return ErrAccessDenied
+		}
+	}
+	if f.limiter != nil && !f.limiter.allow(ip.String()) {
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// AllowRequest returns true if the client of the request may use the
+// endpoint, otherwise it writes the rejection to the response
+func (f *AccessFilter) AllowRequest(w http.ResponseWriter, r *http.Request) bool {
+	switch f.Check(r.RemoteAddr) {
+	case nil:
+		return true
+	case ErrRateLimited:
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
+	default:
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}
+	return false
+}
+
+// rateLimiter limits each client to a number of events per window with a
+// token bucket, so that a client may use its whole allowance at once
+type rateLimiter struct {
+	mu      sync.Mutex
+	limit   float64
+	window  time.Duration
+	clients map[string]*tokenBucket
+	pruned  time.Time
+	now     func() time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:   float64(limit),
+		window:  window,
+		clients: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// allow takes a token from the client's bucket and returns false if there
+// are none left
+func (l *rateLimiter) allow(client string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+
+	// forget the clients whose buckets have refilled
+	if now.Sub(l.pruned) > l.window {
+		for key, b := range l.clients {
+			if now.Sub(b.last) > l.window {
+				delete(l.clients, key)
+			}
+		}
+		l.pruned = now
+	}
+
+	b, ok := l.clients[client]
+	if !ok {
+		b = &tokenBucket{tokens: l.limit, last: now}
+		l.clients[client] = b
+	} else {
+		b.tokens += now.Sub(b.last).Seconds() * l.limit / l.window.Seconds()
+		if b.tokens > l.limit {
+			b.tokens = l.limit
+		}
+		b.last = now
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// rejectionCounter counts the clients rejected by each public endpoint
+type rejectionCounter struct {
+	mu     sync.Mutex
+	counts map[string]map[string]int64
+}
+
+var rejections = &rejectionCounter{counts: make(map[string]map[string]int64)}
+
+func (c *rejectionCounter) add(endpoint string, err error) {
+	reason := "denied"
+	if err == ErrRateLimited {
+		reason = "ratelimited"
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts[endpoint] == nil {
+		c.counts[endpoint] = make(map[string]int64)
+	}
+	c.counts[endpoint][reason]++
+}
+
+// GetAccessRejections returns the number of requests and connections each
+// public endpoint has rejected since startup, by reason ("denied" or
+// "ratelimited")
+func GetAccessRejections() map[string]map[string]int64 {
+	rejections.mu.Lock()
+	defer rejections.mu.Unlock()
+	result := make(map[string]map[string]int64)
+	for endpoint, counts := range rejections.counts {
+		result[endpoint] = make(map[string]int64)
+		for reason, count := range counts {
+			result[endpoint][reason] = count
+		}
+	}
+	return result
+}
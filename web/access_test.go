@@ -0,0 +1,115 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestWebSuite) TestAccessFilterAllowsEveryClientWithoutPolicy(c *C) {
+	filter := NewAccessFilter("test-open")
+	c.Check(filter.Check("10.1.2.3:5000"), IsNil)
+	filter.Set(nil)
+	c.Check(filter.Check("10.1.2.3:5000"), IsNil)
+	c.Check(filter.Check("not-an-address"), IsNil)
+}
+
+func (s *TestWebSuite) TestAccessFilterAllowAndDenyLists(c *C) {
+	filter := NewAccessFilter("test-lists")
+	filter.Set(&servicedefinition.AccessPolicy{
+		Allow: []string{"10.0.0.0/8", "192.168.1.1"},
+		Deny:  []string{"10.1.0.0/16"},
+	})
+
+	c.Check(filter.Check("10.2.3.4:5000"), IsNil)
+	c.Check(filter.Check("192.168.1.1:5000"), IsNil)
+	c.Check(filter.Check("10.1.3.4:5000"), Equals, ErrAccessDenied)
+	c.Check(filter.Check("192.168.1.2:5000"), Equals, ErrAccessDenied)
+	c.Check(filter.Check("not-an-address"), Equals, ErrAccessDenied)
+
+	rejected := GetAccessRejections()["test-lists"]
+	c.Check(rejected["denied"], Equals, int64(3))
+	c.Check(rejected["ratelimited"], Equals, int64(0))
+
+	// clearing the policy allows everyone again
+	filter.Set(&servicedefinition.AccessPolicy{})
+	c.Check(filter.Check("192.168.1.2:5000"), IsNil)
+}
+
+func (s *TestWebSuite) TestRateLimiter(c *C) {
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(2, time.Minute)
+	limiter.now = func() time.Time { return now }
+
+	c.Check(limiter.allow("a"), Equals, true)
+	c.Check(limiter.allow("a"), Equals, true)
+	c.Check(limiter.allow("a"), Equals, false)
+
+	// other clients have their own allowance
+	c.Check(limiter.allow("b"), Equals, true)
+
+	// half the window refills one token
+	now = now.Add(30 * time.Second)
+	c.Check(limiter.allow("a"), Equals, true)
+	c.Check(limiter.allow("a"), Equals, false)
+
+	// idle clients are forgotten
+	now = now.Add(2 * time.Minute)
+	c.Check(limiter.allow("c"), Equals, true)
+	c.Check(limiter.clients, HasLen, 1)
+}
+
+func (s *TestWebSuite) TestAccessFilterAllowRequest(c *C) {
+	filter := NewAccessFilter("test-request")
+	filter.Set(&servicedefinition.AccessPolicy{Deny: []string{"10.0.0.1"}, RateLimit: 1})
+
+	check := func(remoteAddr string) int {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+		r.RemoteAddr = remoteAddr
+		if filter.AllowRequest(w, r) {
+			return http.StatusOK
+		}
+		return w.Code
+	}
+
+	c.Check(check("10.0.0.1:4000"), Equals, http.StatusForbidden)
+	c.Check(check("10.0.0.2:4000"), Equals, http.StatusOK)
+	c.Check(check("10.0.0.2:4001"), Equals, http.StatusTooManyRequests)
+	c.Check(GetAccessRejections()["test-request"]["ratelimited"], Equals, int64(1))
+}
+
+func (s *TestWebSuite) TestVHostHandlerRejectsDeniedClients(c *C) {
+	h := NewVHostHandler("test-vhost")
+	h.Enable()
+	h.SetAccess(&servicedefinition.AccessPolicy{Allow: []string{"10.0.0.0/8"}})
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "192.168.1.1:4000"
+	c.Check(h.Handle(false, s.recorder, r), Equals, true)
+	c.Check(s.recorder.Code, Equals, http.StatusForbidden)
+
+	// allowed clients get through to the (missing) exports
+	s.recorder = httptest.NewRecorder()
+	r.RemoteAddr = "10.0.0.1:4000"
+	c.Check(h.Handle(false, s.recorder, r), Equals, true)
+	c.Check(s.recorder.Code, Equals, http.StatusNotFound)
+}
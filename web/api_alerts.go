@@ -0,0 +1,88 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/alert"
+	userdomain "github.com/control-center/serviced/domain/user"
+	"github.com/zenoss/go-json-rest"
+)
+
+// getAlerts returns the active alerts, or every alert if the "all" query
+// parameter is true
+func getAlerts(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	all := r.URL.Query().Get("all") == "true"
+
+	alerts, err := ctx.getFacade().GetAlerts(ctx.getDatastoreContext(), all)
+	if err != nil {
+		restServerError(w, err)
+		return
+	}
+
+	if !ctx.viewsAll() {
+		visible := []alert.Alert{}
+		for _, a := range alerts {
+			if ctx.viewsTenant(a.TenantID) {
+				visible = append(visible, a)
+			}
+		}
+		alerts = visible
+	}
+
+	w.WriteJson(alerts)
+}
+
+// putAlertAck acknowledges an active alert on behalf of the requesting user
+func putAlertAck(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	alertID, err := url.QueryUnescape(r.PathParam("alertId"))
+	if err != nil {
+		writeJSON(w, err, http.StatusBadRequest)
+		return
+	} else if len(alertID) == 0 {
+		writeJSON(w, "alertId must be specified", http.StatusBadRequest)
+		return
+	}
+
+	facade := ctx.getFacade()
+	dataCtx := ctx.getDatastoreContext()
+
+	a, err := facade.GetAlert(dataCtx, alertID)
+	if datastore.IsErrNoSuchEntity(err) {
+		writeJSON(w, fmt.Sprintf("Alert %v Not Found", alertID), http.StatusNotFound)
+		return
+	} else if err != nil {
+		restServerError(w, err)
+		return
+	}
+
+	if err := ctx.authorize(userdomain.AccessRequest{Action: userdomain.ActionControlService, ServiceID: a.ServiceID}); err != nil {
+		restForbidden(w)
+		return
+	}
+
+	if err := facade.AcknowledgeAlert(dataCtx, alertID, ctx.username); err == alert.ErrAlertResolved {
+		writeJSON(w, err.Error(), http.StatusConflict)
+		return
+	} else if err != nil {
+		restServerError(w, err)
+		return
+	}
+
+	restSuccess(w)
+}
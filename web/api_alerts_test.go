@@ -0,0 +1,89 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"net/http"
+
+	"github.com/control-center/serviced/domain/alert"
+	userdomain "github.com/control-center/serviced/domain/user"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestWebSuite) TestGetAlertsShouldReturnActiveAlerts(c *C) {
+	request := s.buildRequest("GET", "/alerts", "")
+	s.mockFacade.
+		On("GetAlerts", s.ctx.getDatastoreContext(), false).
+		Return([]alert.Alert{{ID: "alert1", State: alert.StateOpen}}, nil)
+
+	getAlerts(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	var alerts []alert.Alert
+	s.getResult(c, &alerts)
+	c.Assert(alerts, HasLen, 1)
+	c.Assert(alerts[0].ID, Equals, "alert1")
+}
+
+func (s *TestWebSuite) TestPutAlertAckShouldAcknowledge(c *C) {
+	request := s.buildRequest("PUT", "/alerts/alert1/ack", "")
+	request.PathParams["alertId"] = "alert1"
+	s.ctx.username = "jdoe"
+	s.mockFacade.
+		On("GetAlert", s.ctx.getDatastoreContext(), "alert1").
+		Return(&alert.Alert{ID: "alert1", ServiceID: "svc1"}, nil)
+	s.mockFacade.
+		On("AcknowledgeAlert", s.ctx.getDatastoreContext(), "alert1", "jdoe").
+		Return(nil)
+
+	putAlertAck(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	s.mockFacade.AssertCalled(c, "AcknowledgeAlert", s.ctx.getDatastoreContext(), "alert1", "jdoe")
+}
+
+func (s *TestWebSuite) TestPutAlertAckShouldRejectResolved(c *C) {
+	request := s.buildRequest("PUT", "/alerts/alert1/ack", "")
+	request.PathParams["alertId"] = "alert1"
+	s.mockFacade.
+		On("GetAlert", s.ctx.getDatastoreContext(), "alert1").
+		Return(&alert.Alert{ID: "alert1", ServiceID: "svc1"}, nil)
+	s.mockFacade.
+		On("AcknowledgeAlert", s.ctx.getDatastoreContext(), "alert1", "").
+		Return(alert.ErrAlertResolved)
+
+	putAlertAck(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusConflict)
+}
+
+func (s *TestWebSuite) TestPutAlertAckShouldRequireAccess(c *C) {
+	request := s.buildRequest("PUT", "/alerts/alert1/ack", "")
+	request.PathParams["alertId"] = "alert1"
+	s.ctx.roles = userdomain.RoleBindings{{Role: userdomain.RoleOperator, TenantID: "otherTenant"}}
+	s.mockFacade.
+		On("GetAccessScope", mock.Anything, mock.AnythingOfType("user.AccessRequest")).
+		Return(userdomain.Scope{TenantID: "tenant1"}, nil)
+	s.mockFacade.
+		On("GetAlert", s.ctx.getDatastoreContext(), "alert1").
+		Return(&alert.Alert{ID: "alert1", ServiceID: "svc1"}, nil)
+
+	putAlertAck(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusForbidden)
+	s.mockFacade.AssertNotCalled(c, "AcknowledgeAlert", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,79 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/control-center/serviced/domain/auditlog"
+	"github.com/zenoss/go-json-rest"
+)
+
+// getAuditEvents returns the recorded audit events, newest first.  The user,
+// type, id and action query parameters select events by exact match, since
+// and until (RFC 3339) bound their time, success selects successful (true) or
+// failed (false) actions, and limit caps the number of events returned.
+func getAuditEvents(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	filter, err := parseAuditFilter(r.URL.Query())
+	if err != nil {
+		writeJSON(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	events, err := ctx.getFacade().GetAuditEvents(ctx.getDatastoreContext(), filter)
+	if err != nil {
+		restServerError(w, err)
+		return
+	}
+
+	w.WriteJson(events)
+}
+
+// parseAuditFilter builds an audit filter from the query parameters
+func parseAuditFilter(query url.Values) (auditlog.Filter, error) {
+	filter := auditlog.Filter{
+		User:     query.Get("user"),
+		Type:     query.Get("type"),
+		EntityID: query.Get("id"),
+		Action:   query.Get("action"),
+	}
+	for name, t := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if value := query.Get(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s: %s", name, err)
+			}
+			*t = parsed
+		}
+	}
+	if value := query.Get("success"); value != "" {
+		success, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid success: %s", err)
+		}
+		filter.Success = &success
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return filter, fmt.Errorf("invalid limit %q", value)
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/control-center/serviced/domain/auditlog"
+	"github.com/zenoss/go-json-rest"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestWebSuite) TestGetAuditEventsShouldApplyFilter(c *C) {
+	request := s.buildRequest("GET", "/audit?user=jdoe&type=service&id=svc1&action=stop&since=2019-03-05T10:00:00Z&until=2019-03-06T10:00:00Z&success=false&limit=5", "")
+	failed := false
+	filter := auditlog.Filter{
+		User:     "jdoe",
+		Type:     "service",
+		EntityID: "svc1",
+		Action:   "stop",
+		Since:    time.Date(2019, 3, 5, 10, 0, 0, 0, time.UTC),
+		Until:    time.Date(2019, 3, 6, 10, 0, 0, 0, time.UTC),
+		Success:  &failed,
+		Limit:    5,
+	}
+	s.mockFacade.
+		On("GetAuditEvents", s.ctx.getDatastoreContext(), filter).
+		Return([]auditlog.Event{{ID: "e1"}}, nil)
+
+	getAuditEvents(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	var events []auditlog.Event
+	s.getResult(c, &events)
+	c.Assert(events, HasLen, 1)
+	c.Assert(events[0].ID, Equals, "e1")
+}
+
+func (s *TestWebSuite) TestGetAuditEventsShouldRejectBadParameters(c *C) {
+	for _, query := range []string{"since=yesterday", "success=maybe", "limit=-1"} {
+		s.recorder = httptest.NewRecorder()
+		s.writer = rest.NewResponseWriter(s.recorder, false)
+		request := s.buildRequest("GET", "/audit?"+query, "")
+
+		getAuditEvents(&(s.writer), &request, s.ctx)
+
+		c.Check(s.recorder.Code, Equals, http.StatusBadRequest, Commentf("query %s", query))
+	}
+	s.mockFacade.AssertNotCalled(c, "GetAuditEvents")
+}
+
+func (s *TestWebSuite) TestGetAuditEventsShouldReturnServerError(c *C) {
+	request := s.buildRequest("GET", "/audit", "")
+	s.mockFacade.
+		On("GetAuditEvents", s.ctx.getDatastoreContext(), auditlog.Filter{}).
+		Return(nil, errors.New("datastore failure"))
+
+	getAuditEvents(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusInternalServerError)
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/control-center/serviced/domain/certificate"
+	"github.com/zenoss/go-json-rest"
+)
+
+// getCertificates returns the certificates of the virtual hosts and public
+// ports, without their private keys
+func getCertificates(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	certs, err := ctx.getFacade().GetCertificates(ctx.getDatastoreContext())
+	if err != nil {
+		restServerError(w, err)
+		return
+	}
+
+	result := make([]certificate.Certificate, len(certs))
+	for i := range certs {
+		result[i] = certs[i].WithoutKey()
+	}
+	w.WriteJson(result)
+}
+
+// postCertificate adds or replaces the certificate of a hostname or port
+// address and returns it without its private key
+func postCertificate(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	var cert certificate.Certificate
+	if err := r.DecodeJsonPayload(&cert); err != nil {
+		restBadRequest(w, err)
+		return
+	}
+
+	if err := ctx.getFacade().AddCertificate(ctx.getDatastoreContext(), &cert); err != nil {
+		restServerError(w, err)
+		return
+	}
+
+	result := cert.WithoutKey()
+	writeJSON(w, &result, http.StatusOK)
+}
+
+// deleteCertificate removes a certificate
+func deleteCertificate(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	certificateID, err := url.QueryUnescape(r.PathParam("certificateId"))
+	if err != nil {
+		writeJSON(w, err, http.StatusBadRequest)
+		return
+	} else if len(certificateID) == 0 {
+		writeJSON(w, "certificateId must be specified", http.StatusBadRequest)
+		return
+	}
+
+	if err := ctx.getFacade().RemoveCertificate(ctx.getDatastoreContext(), certificateID); err != nil {
+		restServerError(w, err)
+		return
+	}
+
+	restSuccess(w)
+}
@@ -0,0 +1,85 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/control-center/serviced/domain/certificate"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestWebSuite) TestGetCertificatesShouldHideKeys(c *C) {
+	request := s.buildRequest("GET", "/certificates", "")
+	s.mockFacade.
+		On("GetCertificates", s.ctx.getDatastoreContext()).
+		Return([]certificate.Certificate{{ID: "app", CertPEM: "CERT", KeyPEM: "KEY"}}, nil)
+
+	getCertificates(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	var certs []certificate.Certificate
+	s.getResult(c, &certs)
+	c.Assert(certs, HasLen, 1)
+	c.Assert(certs[0].ID, Equals, "app")
+	c.Assert(certs[0].CertPEM, Equals, "CERT")
+	c.Assert(certs[0].KeyPEM, Equals, "")
+}
+
+func (s *TestWebSuite) TestPostCertificateShouldAddCertificate(c *C) {
+	request := s.buildRequest("POST", "/certificates", `{"ID":"app","CertPEM":"CERT","KeyPEM":"KEY"}`)
+	s.mockFacade.
+		On("AddCertificate", s.ctx.getDatastoreContext(), mock.AnythingOfType("*certificate.Certificate")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			args.Get(1).(*certificate.Certificate).Subject = "app.example.com"
+		})
+
+	postCertificate(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	var added certificate.Certificate
+	s.getResult(c, &added)
+	c.Assert(added.ID, Equals, "app")
+	c.Assert(added.Subject, Equals, "app.example.com")
+	c.Assert(added.KeyPEM, Equals, "")
+}
+
+func (s *TestWebSuite) TestPostCertificateShouldReturnError(c *C) {
+	request := s.buildRequest("POST", "/certificates", `{"ID":"app","CertPEM":"CERT","KeyPEM":"KEY"}`)
+	s.mockFacade.
+		On("AddCertificate", s.ctx.getDatastoreContext(), mock.AnythingOfType("*certificate.Certificate")).
+		Return(errors.New("invalid certificate"))
+
+	postCertificate(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusInternalServerError)
+}
+
+func (s *TestWebSuite) TestDeleteCertificateShouldRemoveCertificate(c *C) {
+	request := s.buildRequest("DELETE", "/certificates/%3A9443", "")
+	request.PathParams["certificateId"] = "%3A9443"
+	s.mockFacade.
+		On("RemoveCertificate", s.ctx.getDatastoreContext(), ":9443").
+		Return(nil)
+
+	deleteCertificate(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	s.mockFacade.AssertCalled(c, "RemoveCertificate", s.ctx.getDatastoreContext(), ":9443")
+}
@@ -18,6 +18,8 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/utils"
 	"github.com/zenoss/go-json-rest"
 )
 
@@ -32,7 +34,7 @@ func getHosts(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 		return
 	}
 
-	w.WriteJson(hosts)
+	w.WriteJson(filterReadHosts(ctx, hosts))
 }
 
 // getHostsForPool returns the list of hosts for a pool.
@@ -67,8 +69,9 @@ func getHostStatuses(w *rest.ResponseWriter, r *rest.Request, ctx *requestContex
 	values := r.URL.Query()
 
 	var hostIDs []string
-	if _, ok := values["hostId"]; ok {
-		hostIDs = values["hostId"]
+	requested, ok := values["hostId"]
+	if ok && ctx.viewsAll() {
+		hostIDs = requested
 	} else {
 		hosts, err := facade.GetReadHosts(dataCtx)
 		if err != nil {
@@ -76,9 +79,11 @@ func getHostStatuses(w *rest.ResponseWriter, r *rest.Request, ctx *requestContex
 			return
 		}
 
-		hostIDs = make([]string, len(hosts))
-		for i, host := range hosts {
-			hostIDs[i] = host.ID
+		hostIDs = []string{}
+		for _, host := range filterReadHosts(ctx, hosts) {
+			if !ok || utils.StringInSlice(host.ID, requested) {
+				hostIDs = append(hostIDs, host.ID)
+			}
 		}
 	}
 
@@ -103,3 +108,17 @@ func getHostStatuses(w *rest.ResponseWriter, r *rest.Request, ctx *requestContex
 
 	w.WriteJson(statuses)
 }
+
+// filterReadHosts returns the hosts that the principal may view
+func filterReadHosts(ctx *requestContext, hosts []host.ReadHost) []host.ReadHost {
+	if ctx.viewsAll() {
+		return hosts
+	}
+	result := []host.ReadHost{}
+	for _, h := range hosts {
+		if ctx.viewsPool(h.PoolID) {
+			result = append(result, h)
+		}
+	}
+	return result
+}
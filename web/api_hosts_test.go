@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/control-center/serviced/domain/host"
+	userdomain "github.com/control-center/serviced/domain/user"
 	. "gopkg.in/check.v1"
 )
 
@@ -76,6 +77,26 @@ func (s *TestWebSuite) TestGetHostsShouldReturnStatusOK(c *C) {
 	c.Assert(s.recorder.Code, Equals, http.StatusOK)
 }
 
+func (s *TestWebSuite) TestGetHostsShouldFilterByPool(c *C) {
+	request := s.buildRequest("GET", "http://www.example.com/hosts", "")
+	s.ctx.roles = userdomain.RoleBindings{{Role: userdomain.RoleViewer, PoolID: "otherPool"}}
+	otherHost := apiHostsTestData.secondHost
+	otherHost.PoolID = "otherPool"
+
+	s.mockFacade.
+		On("GetReadHosts", s.ctx.getDatastoreContext()).
+		Return([]host.ReadHost{apiHostsTestData.firstHost, otherHost}, nil)
+
+	getHosts(&(s.writer), &request, s.ctx)
+
+	response := []host.ReadHost{}
+	s.getResult(c, &response)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	c.Assert(response, HasLen, 1)
+	c.Assert(response[0].ID, Equals, otherHost.ID)
+}
+
 func (s *TestWebSuite) TestGetHostsForPoolShouldReturnBadRequestForInvalidPoolId(c *C) {
 	request := s.buildRequest("GET", "http://www.example.com/pools/inv%ZZlid/hosts", "")
 	request.PathParams["poolId"] = "inv%ZZlid"
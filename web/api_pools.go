@@ -14,6 +14,7 @@
 package web
 
 import (
+	"github.com/control-center/serviced/domain/pool"
 	"github.com/zenoss/go-json-rest"
 )
 
@@ -28,5 +29,15 @@ func getPools(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 		return
 	}
 
+	if !ctx.viewsAll() {
+		visible := []pool.ReadPool{}
+		for _, p := range pools {
+			if ctx.viewsPool(p.ID) {
+				visible = append(visible, p)
+			}
+		}
+		pools = visible
+	}
+
 	w.WriteJson(pools)
 }
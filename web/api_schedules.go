@@ -0,0 +1,152 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/schedule"
+	"github.com/zenoss/go-json-rest"
+)
+
+// getSchedules returns every snapshot and backup schedule
+func getSchedules(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	schedules, err := ctx.getFacade().GetSchedules(ctx.getDatastoreContext())
+	if err != nil {
+		restServerError(w, err)
+		return
+	}
+
+	if !ctx.viewsAll() {
+		visible := []schedule.Schedule{}
+		for _, s := range schedules {
+			if ctx.viewsTenant(s.TenantID) {
+				visible = append(visible, s)
+			}
+		}
+		schedules = visible
+	}
+
+	w.WriteJson(schedules)
+}
+
+// getSchedule returns a schedule with its status and last outcome
+func getSchedule(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	s, ok := lookupSchedule(w, r, ctx)
+	if !ok {
+		return
+	}
+
+	w.WriteJson(s)
+}
+
+// postSchedule adds a schedule and returns it with its new id
+func postSchedule(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	var s schedule.Schedule
+	if err := r.DecodeJsonPayload(&s); err != nil {
+		restBadRequest(w, err)
+		return
+	}
+
+	if err := ctx.authorize(s.AccessRequest()); err != nil {
+		restForbidden(w)
+		return
+	}
+
+	if err := ctx.getFacade().AddSchedule(ctx.getDatastoreContext(), &s); err != nil {
+		restServerError(w, err)
+		return
+	}
+
+	writeJSON(w, &s, http.StatusOK)
+}
+
+// putSchedule replaces the settings of a schedule
+func putSchedule(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	current, ok := lookupSchedule(w, r, ctx)
+	if !ok {
+		return
+	}
+
+	var s schedule.Schedule
+	if err := r.DecodeJsonPayload(&s); err != nil {
+		restBadRequest(w, err)
+		return
+	}
+	s.ID = current.ID
+
+	// the principal must be able to manage the schedule as it is and as it
+	// will be
+	if err := ctx.authorize(current.AccessRequest()); err != nil {
+		restForbidden(w)
+		return
+	} else if err := ctx.authorize(s.AccessRequest()); err != nil {
+		restForbidden(w)
+		return
+	}
+
+	if err := ctx.getFacade().UpdateSchedule(ctx.getDatastoreContext(), &s); err != nil {
+		restServerError(w, err)
+		return
+	}
+
+	restSuccess(w)
+}
+
+// deleteSchedule removes a schedule.  The snapshots and backups it took are
+// kept.
+func deleteSchedule(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	s, ok := lookupSchedule(w, r, ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.authorize(s.AccessRequest()); err != nil {
+		restForbidden(w)
+		return
+	}
+
+	if err := ctx.getFacade().RemoveSchedule(ctx.getDatastoreContext(), s.ID); err != nil {
+		restServerError(w, err)
+		return
+	}
+
+	restSuccess(w)
+}
+
+// lookupSchedule returns the schedule named by the route, or writes the
+// error response and returns false
+func lookupSchedule(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) (*schedule.Schedule, bool) {
+	scheduleID, err := url.QueryUnescape(r.PathParam("scheduleId"))
+	if err != nil {
+		writeJSON(w, err, http.StatusBadRequest)
+		return nil, false
+	} else if len(scheduleID) == 0 {
+		writeJSON(w, "scheduleId must be specified", http.StatusBadRequest)
+		return nil, false
+	}
+
+	s, err := ctx.getFacade().GetSchedule(ctx.getDatastoreContext(), scheduleID)
+	if datastore.IsErrNoSuchEntity(err) {
+		writeJSON(w, fmt.Sprintf("Schedule %v Not Found", scheduleID), http.StatusNotFound)
+		return nil, false
+	} else if err != nil {
+		restServerError(w, err)
+		return nil, false
+	}
+	return s, true
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"net/http"
+
+	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/schedule"
+	userdomain "github.com/control-center/serviced/domain/user"
+	"github.com/stretchr/testify/mock"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestWebSuite) TestGetSchedulesShouldReturnSchedules(c *C) {
+	request := s.buildRequest("GET", "/schedules", "")
+	s.mockFacade.
+		On("GetSchedules", s.ctx.getDatastoreContext()).
+		Return([]schedule.Schedule{{ID: "sched1", Kind: schedule.KindBackup, Cron: "@daily"}}, nil)
+
+	getSchedules(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	var schedules []schedule.Schedule
+	s.getResult(c, &schedules)
+	c.Assert(schedules, HasLen, 1)
+	c.Assert(schedules[0].ID, Equals, "sched1")
+}
+
+func (s *TestWebSuite) TestGetScheduleShouldReturnNotFound(c *C) {
+	request := s.buildRequest("GET", "/schedules/sched1", "")
+	request.PathParams["scheduleId"] = "sched1"
+	s.mockFacade.
+		On("GetSchedule", s.ctx.getDatastoreContext(), "sched1").
+		Return(nil, datastore.ErrNoSuchEntity{Key: datastore.NewKey(schedule.GetType(), "sched1")})
+
+	getSchedule(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusNotFound)
+}
+
+func (s *TestWebSuite) TestPostScheduleShouldAddSchedule(c *C) {
+	request := s.buildRequest("POST", "/schedules", `{"Kind":"snapshot","TenantID":"tenant1","Cron":"0 2 * * *","Retention":{"Daily":7}}`)
+	s.mockFacade.
+		On("AddSchedule", s.ctx.getDatastoreContext(), mock.AnythingOfType("*schedule.Schedule")).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			args.Get(1).(*schedule.Schedule).ID = "sched1"
+		})
+
+	postSchedule(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	var added schedule.Schedule
+	s.getResult(c, &added)
+	c.Assert(added.ID, Equals, "sched1")
+	c.Assert(added.TenantID, Equals, "tenant1")
+	c.Assert(added.Retention.Daily, Equals, 7)
+}
+
+func (s *TestWebSuite) TestPostScheduleShouldRequireAccess(c *C) {
+	request := s.buildRequest("POST", "/schedules", `{"Kind":"backup","Cron":"@daily"}`)
+	s.ctx.roles = userdomain.RoleBindings{{Role: userdomain.RoleTenantAdmin, TenantID: "tenant1"}}
+
+	postSchedule(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusForbidden)
+	s.mockFacade.AssertNotCalled(c, "AddSchedule", mock.Anything, mock.Anything)
+}
+
+func (s *TestWebSuite) TestPutScheduleShouldKeepID(c *C) {
+	request := s.buildRequest("PUT", "/schedules/sched1", `{"ID":"other","Kind":"backup","Cron":"@weekly"}`)
+	request.PathParams["scheduleId"] = "sched1"
+	s.mockFacade.
+		On("GetSchedule", s.ctx.getDatastoreContext(), "sched1").
+		Return(&schedule.Schedule{ID: "sched1", Kind: schedule.KindBackup, Cron: "@daily"}, nil)
+	s.mockFacade.
+		On("UpdateSchedule", s.ctx.getDatastoreContext(), mock.AnythingOfType("*schedule.Schedule")).
+		Return(nil)
+
+	putSchedule(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	updated := s.mockFacade.Calls[1].Arguments.Get(1).(*schedule.Schedule)
+	c.Assert(updated.ID, Equals, "sched1")
+	c.Assert(updated.Cron, Equals, "@weekly")
+}
+
+func (s *TestWebSuite) TestDeleteScheduleShouldRemoveSchedule(c *C) {
+	request := s.buildRequest("DELETE", "/schedules/sched1", "")
+	request.PathParams["scheduleId"] = "sched1"
+	s.mockFacade.
+		On("GetSchedule", s.ctx.getDatastoreContext(), "sched1").
+		Return(&schedule.Schedule{ID: "sched1", Kind: schedule.KindBackup, Cron: "@daily"}, nil)
+	s.mockFacade.
+		On("RemoveSchedule", s.ctx.getDatastoreContext(), "sched1").
+		Return(nil)
+
+	deleteSchedule(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	s.mockFacade.AssertCalled(c, "RemoveSchedule", s.ctx.getDatastoreContext(), "sched1")
+}
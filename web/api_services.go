@@ -41,7 +41,7 @@ func getAllServiceDetails(w *rest.ResponseWriter, r *rest.Request, c *requestCon
 		return
 	}
 
-	w.WriteJson(details)
+	w.WriteJson(filterServiceDetails(c, details))
 }
 
 func getServiceDetails(w *rest.ResponseWriter, r *rest.Request, c *requestContext) {
@@ -245,3 +245,17 @@ func buildQuery(r *rest.Request) (service.Query, error) {
 
 	return query, nil
 }
+
+// filterServiceDetails returns the services that the principal may view
+func filterServiceDetails(ctx *requestContext, details []service.ServiceDetails) []service.ServiceDetails {
+	if ctx.viewsAll() {
+		return details
+	}
+	result := []service.ServiceDetails{}
+	for _, d := range details {
+		if ctx.viewsService(d.ID) {
+			result = append(result, d)
+		}
+	}
+	return result
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/control-center/serviced/datastore"
 	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/domain/service"
+	userdomain "github.com/control-center/serviced/domain/user"
 	"github.com/stretchr/testify/mock"
 	. "gopkg.in/check.v1"
 )
@@ -225,6 +226,34 @@ func (s *TestWebSuite) TestRestGetAllServiceDetailsShouldReturnStatusOK(c *C) {
 	c.Assert(s.recorder.Code, Equals, http.StatusOK)
 }
 
+func (s *TestWebSuite) TestRestGetAllServiceDetailsShouldFilterByTenant(c *C) {
+	s.ctx.roles = userdomain.RoleBindings{{Role: userdomain.RoleViewer, TenantID: "tenant"}}
+	s.mockFacade.On("QueryServiceDetails",
+		mock.Anything,
+		mock.AnythingOfType("service.Query")).
+		Return(allServices, nil)
+	for _, id := range []string{"firstService", "tenant"} {
+		s.mockFacade.
+			On("GetAccessScope", mock.Anything, userdomain.AccessRequest{Action: userdomain.ActionView, ServiceID: id}).
+			Return(userdomain.Scope{TenantID: "tenant", PoolID: "pool"}, nil)
+	}
+	s.mockFacade.
+		On("GetAccessScope", mock.Anything, userdomain.AccessRequest{Action: userdomain.ActionView, ServiceID: "secondService"}).
+		Return(userdomain.Scope{TenantID: "otherTenant", PoolID: "pool"}, nil)
+
+	request := s.buildRequest("GET", "http://www.example.com/services", "")
+
+	getAllServiceDetails(&(s.writer), &request, s.ctx)
+
+	response := []service.ServiceDetails{}
+	s.getResult(c, &response)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	c.Assert(response, HasLen, 2)
+	c.Assert(response[0].ID, Equals, "firstService")
+	c.Assert(response[1].ID, Equals, "tenant")
+}
+
 func (s *TestWebSuite) TestRestQueryServiceDetailsShouldQueryForAll(c *C) {
 	s.mockFacade.On("QueryServiceDetails",
 		mock.Anything,
@@ -0,0 +1,203 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/datastore"
+	userdomain "github.com/control-center/serviced/domain/user"
+	"github.com/zenoss/go-json-rest"
+)
+
+// getRoles returns the roles held by the principal of an authenticated
+// request.  REST and auth0 tokens are only accepted when they carry admin
+// access, so their bearers are cluster administrators; everybody else gets
+// the roles recorded on their session at login.
+func getRoles(r *rest.Request) userdomain.RoleBindings {
+	if token, err := auth.ExtractRestToken(r.Request); err == nil && token != "" && token != "null" {
+		if isOIDCToken(token) {
+			roles, _ := loginWithOIDCTokenOK(r, token)
+			return roles
+		}
+		return userdomain.ClusterAdmin
+	}
+	if auth.Auth0IsConfigured() {
+		if _, err := r.Request.Cookie(auth0TokenCookie); err == nil {
+			return userdomain.ClusterAdmin
+		}
+	}
+	cookie, err := r.Request.Cookie(sessionCookie)
+	if err != nil {
+		return nil
+	}
+	value, err := url.QueryUnescape(strings.Replace(cookie.Value, "+", url.QueryEscape("+"), -1))
+	if err != nil {
+		return nil
+	}
+	sessionsLock.RLock()
+	defer sessionsLock.RUnlock()
+	session, err := findsessionT(value)
+	if err != nil {
+		return nil
+	}
+	return session.Roles
+}
+
+// newAccessRequest builds an access request for the entity identified by
+// the path parameters of the route
+func newAccessRequest(r *rest.Request, action userdomain.Action) userdomain.AccessRequest {
+	param := func(name string) string {
+		value, _ := url.QueryUnescape(r.PathParam(name))
+		return value
+	}
+	return userdomain.AccessRequest{
+		Action:    action,
+		ServiceID: param("serviceId"),
+		HostID:    param("hostId"),
+		PoolID:    param("poolId"),
+	}
+}
+
+// authorize returns userdomain.ErrAccessDenied if the roles do not permit the
+// request.  Requests that name no entity are cluster-wide, except for reads,
+// which are allowed to anybody holding a role; handlers that list entities
+// must leave out those the principal may not view.
+func (sc *ServiceConfig) authorize(roles userdomain.RoleBindings, req userdomain.AccessRequest) error {
+	scope := userdomain.Scope{}
+	if roles.Allows(req.Action, scope) {
+		// a cluster-wide role covers every entity
+		return nil
+	}
+	if req.ServiceID != "" || req.HostID != "" || req.PoolID != "" {
+		var err error
+		if scope, err = sc.facade.GetAccessScope(datastore.Get(), req); err != nil {
+			plog.WithError(err).WithField("action", req.Action).Debug("Could not resolve access scope")
+			return userdomain.ErrAccessDenied
+		}
+	} else if req.Action == userdomain.ActionView {
+		scope = userdomain.AnyScope
+	}
+	return roles.Authorize(req.Action, scope)
+}
+
+// checkAccess verifies that the request is authenticated and that its
+// principal may perform the action on the entity named by the route.  With
+// anywhere set, the principal only needs to hold the action on some scope;
+// the handler is then responsible for checking each entity it touches.
+func (sc *ServiceConfig) checkAccess(w *rest.ResponseWriter, r *rest.Request, action userdomain.Action, anywhere bool) (userdomain.RoleBindings, bool) {
+	if !loginOK(w, r) {
+		restUnauthorized(w)
+		return nil, false
+	}
+	roles := getRoles(r)
+	req := newAccessRequest(r, action)
+	var err error
+	if anywhere {
+		err = roles.Authorize(action, userdomain.AnyScope)
+	} else {
+		err = sc.authorize(roles, req)
+	}
+	if err != nil {
+		plog.WithField("url", r.URL.String()).WithField("action", action).Debug("Request is not authorized")
+		restForbidden(w)
+		return nil, false
+	}
+	return roles, true
+}
+
+// checkAuthFor authenticates the request and authorizes the action against
+// the service, host or pool named by the route
+func (sc *ServiceConfig) checkAuthFor(action userdomain.Action, realfunc ctxhandlerFunc) handlerFunc {
+	return sc.newAuthorizedRequestHandler(action, false, realfunc)
+}
+
+// checkAuthAnywhere authenticates the request and verifies that the principal
+// may perform the action somewhere; the handler must call
+// requestContext.authorize for each entity in the request body.
+func (sc *ServiceConfig) checkAuthAnywhere(action userdomain.Action, realfunc ctxhandlerFunc) handlerFunc {
+	return sc.newAuthorizedRequestHandler(action, true, realfunc)
+}
+
+func (sc *ServiceConfig) newAuthorizedRequestHandler(action userdomain.Action, anywhere bool, realfunc ctxhandlerFunc) handlerFunc {
+	return func(w *rest.ResponseWriter, r *rest.Request) {
+		roles, ok := sc.checkAccess(w, r, action, anywhere)
+		if !ok {
+			return
+		}
+		reqCtx := newRequestContextFromRequest(sc, r)
+		reqCtx.roles = roles
+		defer reqCtx.end()
+		realfunc(w, r, reqCtx)
+	}
+}
+
+// authorizedClientFor is authorizedClient with authorization of the action
+// against the service, host or pool named by the route
+func (sc *ServiceConfig) authorizedClientFor(action userdomain.Action, realfunc handlerClientFunc) handlerFunc {
+	return func(w *rest.ResponseWriter, r *rest.Request) {
+		if _, ok := sc.checkAccess(w, r, action, false); !ok {
+			return
+		}
+		client, err := sc.getClient()
+		if err != nil {
+			plog.WithError(err).Error("Unable to acquire client")
+			restServerError(w, err)
+			return
+		}
+		defer client.Close()
+		realfunc(w, r, client)
+	}
+}
+
+// authorize returns userdomain.ErrAccessDenied if the principal of the
+// request may not perform the action described by req
+func (ctx *requestContext) authorize(req userdomain.AccessRequest) error {
+	return ctx.sc.authorize(ctx.roles, req)
+}
+
+// viewsAll returns true if the principal may view every entity in the
+// cluster, in which case list responses need not be filtered
+func (ctx *requestContext) viewsAll() bool {
+	return ctx.roles.Allows(userdomain.ActionView, userdomain.Scope{})
+}
+
+// viewsService returns true if the principal may view the service
+func (ctx *requestContext) viewsService(serviceID string) bool {
+	return ctx.authorize(userdomain.AccessRequest{Action: userdomain.ActionView, ServiceID: serviceID}) == nil
+}
+
+// viewsPool returns true if the principal may view the pool and its hosts
+func (ctx *requestContext) viewsPool(poolID string) bool {
+	return ctx.roles.Allows(userdomain.ActionView, userdomain.Scope{PoolID: poolID})
+}
+
+// viewsTenant returns true if the principal may view everything in the
+// tenant.  An empty tenant is the whole cluster.
+func (ctx *requestContext) viewsTenant(tenantID string) bool {
+	return ctx.roles.Allows(userdomain.ActionView, userdomain.Scope{TenantID: tenantID})
+}
+
+// authorizeServices returns userdomain.ErrAccessDenied if the principal of the
+// request may not perform the action on every one of the services
+func (ctx *requestContext) authorizeServices(action userdomain.Action, serviceIDs []string) error {
+	for _, serviceID := range serviceIDs {
+		if err := ctx.authorize(userdomain.AccessRequest{Action: action, ServiceID: serviceID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"crypto/tls"
+	"sync"
+	"time"
+
+	"github.com/control-center/serviced/domain/certificate"
+)
+
+// certificateRefresh is how often the certificates are reloaded from the
+// datastore
+const certificateRefresh = 30 * time.Second
+
+// CertificateCache selects the certificate to serve on a TLS handshake by the
+// server name the client asked for (SNI) and the port address it connected
+// to.  Clients that match none of the certificates get the default
+// certificate of the master.
+type CertificateCache struct {
+	defaultCert *tls.Certificate
+	load        func() ([]certificate.Certificate, error)
+	mu          sync.RWMutex
+	certs       []certificate.Certificate
+	parsed      map[string]*tls.Certificate
+}
+
+// NewCertificateCache creates a certificate cache that serves the default
+// certificate until it loads the certificates from the datastore
+func NewCertificateCache(defaultCert *tls.Certificate, load func() ([]certificate.Certificate, error)) *CertificateCache {
+	return &CertificateCache{
+		defaultCert: defaultCert,
+		load:        load,
+		parsed:      make(map[string]*tls.Certificate),
+	}
+}
+
+// LoadDefaultCertificate loads the certificate of the master from its cert
+// and key files, or from the built-in certificate if they are not set
+func LoadDefaultCertificate(certFile, keyFile string) (*tls.Certificate, error) {
+	certFile, keyFile = GetCertFiles(certFile, keyFile)
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// Refresh reloads the certificates.  Certificates that cannot be parsed are
+// skipped, so that their clients get the default certificate.
+func (c *CertificateCache) Refresh() error {
+	certs, err := c.load()
+	if err != nil {
+		return err
+	}
+	valid := make([]certificate.Certificate, 0, len(certs))
+	parsed := make(map[string]*tls.Certificate, len(certs))
+	for _, cert := range certs {
+		tlsCert, err := cert.TLSCertificate()
+		if err != nil {
+			plog.WithError(err).WithField("certificate", cert.ID).Warn("Could not parse certificate")
+			continue
+		}
+		valid = append(valid, cert)
+		parsed[cert.ID] = tlsCert
+	}
+
+	c.mu.Lock()
+	c.certs, c.parsed = valid, parsed
+	c.mu.Unlock()
+	return nil
+}
+
+// Run refreshes the certificates until shutdown
+func (c *CertificateCache) Run(shutdown <-chan interface{}) {
+	ticker := time.NewTicker(certificateRefresh)
+	defer ticker.Stop()
+	for {
+		if err := c.Refresh(); err != nil {
+			plog.WithError(err).Warn("Could not load certificates")
+		}
+		select {
+		case <-ticker.C:
+		case <-shutdown:
+			return
+		}
+	}
+}
+
+// Get returns the certificate for a server name at a port address
+func (c *CertificateCache) Get(serverName, portAddr string) *tls.Certificate {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if cert := certificate.Find(c.certs, serverName, portAddr); cert != nil {
+		return c.parsed[cert.ID]
+	}
+	return c.defaultCert
+}
+
+// GetCertificate returns the tls.Config callback of a server listening at a
+// port address
+func (c *CertificateCache) GetCertificate(portAddr string) func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return c.Get(hello.ServerName, portAddr), nil
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/control-center/serviced/domain/certificate"
+	. "gopkg.in/check.v1"
+)
+
+// testCertificate returns a self-signed certificate for a name
+func testCertificate(c *C, id string) certificate.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, IsNil)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: id},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	c.Assert(err, IsNil)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	c.Assert(err, IsNil)
+	return certificate.Certificate{
+		ID:      id,
+		CertPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		KeyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}
+}
+
+// subject returns the common name of a served certificate
+func subject(c *C, cert *tls.Certificate) string {
+	c.Assert(cert, NotNil)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	c.Assert(err, IsNil)
+	return leaf.Subject.CommonName
+}
+
+func (s *TestWebSuite) TestCertificateCacheSelectsBySNI(c *C) {
+	defaultCert, err := LoadDefaultCertificate("", "")
+	c.Assert(err, IsNil)
+	certs := []certificate.Certificate{
+		testCertificate(c, "app"),
+		testCertificate(c, "*.example.com"),
+		testCertificate(c, ":9443"),
+		{ID: "broken", CertPEM: "CERT", KeyPEM: "KEY"},
+	}
+	cache := NewCertificateCache(defaultCert, func() ([]certificate.Certificate, error) {
+		return certs, nil
+	})
+
+	// the default certificate is served until the certificates are loaded
+	c.Assert(cache.Get("app.cc.example.org", ":443"), Equals, defaultCert)
+
+	c.Assert(cache.Refresh(), IsNil)
+	getCertificate := cache.GetCertificate(":443")
+	cert, err := getCertificate(&tls.ClientHelloInfo{ServerName: "app.cc.example.org"})
+	c.Assert(err, IsNil)
+	c.Assert(subject(c, cert), Equals, "app")
+	c.Assert(subject(c, cache.Get("www.example.com", ":443")), Equals, "*.example.com")
+	c.Assert(subject(c, cache.Get("", "0.0.0.0:9443")), Equals, ":9443")
+	c.Assert(cache.Get("www.example.org", ":443"), Equals, defaultCert)
+	c.Assert(cache.Get("broken", ":443"), Equals, defaultCert)
+
+	// the last certificates are kept if they cannot be reloaded
+	cache.load = func() ([]certificate.Certificate, error) { return nil, errors.New("unavailable") }
+	c.Assert(cache.Refresh(), NotNil)
+	c.Assert(subject(c, cache.Get("app", ":443")), Equals, "app")
+}
@@ -26,9 +26,12 @@ import (
 	"strings"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/auth"
 	"github.com/control-center/serviced/config"
 	daoclient "github.com/control-center/serviced/dao/client"
 	"github.com/control-center/serviced/datastore"
+	"github.com/control-center/serviced/domain/certificate"
+	userdomain "github.com/control-center/serviced/domain/user"
 	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/node"
 	"github.com/control-center/serviced/rpc/master"
@@ -58,6 +61,7 @@ type ServiceConfig struct {
 	uiConfig    UIConfig
 	facade      facade.FacadeInterface
 	vhostmgr    *VHostManager
+	certs       *CertificateCache
 }
 
 // Auth0Config contains configuration values pertaining to Auth0
@@ -139,6 +143,16 @@ func (sc *ServiceConfig) Serve(shutdown <-chan (interface{})) {
 	logger := plog.WithField("bindport", sc.bindPort)
 	logger.Debug("Starting vhost synching")
 
+	// load the certificates of the virtual hosts and public ports
+	defaultCert, err := LoadDefaultCertificate(sc.certPEMFile, sc.keyPEMFile)
+	if err != nil {
+		logger.WithError(err).Error("Could not load the certificate of the master")
+	}
+	sc.certs = NewCertificateCache(defaultCert, func() ([]certificate.Certificate, error) {
+		return sc.facade.GetCertificates(datastore.Get())
+	})
+	go sc.certs.Run(shutdown)
+
 	// start public port listener
 	sc.startPublicPortListener(shutdown)
 
@@ -215,9 +229,6 @@ func (sc *ServiceConfig) Serve(shutdown <-chan (interface{})) {
 	defaultHostAlias = sc.hostaliases[0]
 	uiConfig = sc.uiConfig
 
-	// FIXME: bubble up these errors to the caller
-	certFile, keyFile := GetCertFiles(sc.certPEMFile, sc.keyPEMFile)
-
 	go func() {
 		redirect := func(w http.ResponseWriter, req *http.Request) {
 			// bindPort has already been validated, so the Split/access below won't break.
@@ -236,10 +247,11 @@ func (sc *ServiceConfig) Serve(shutdown <-chan (interface{})) {
 			MinVersion:               utils.MinTLS("http"),
 			PreferServerCipherSuites: true,
 			CipherSuites:             utils.CipherSuites("http"),
+			GetCertificate:           sc.certs.GetCertificate(sc.bindPort),
 		}
 		server := &http.Server{Addr: sc.bindPort, TLSConfig: config, Handler: http.HandlerFunc(httphandler)}
 		logger.WithField("ciphersuite", utils.CipherSuitesByName(config)).Info("Creating HTTP server")
-		err := server.ListenAndServeTLS(certFile, keyFile)
+		err := server.ListenAndServeTLS("", "")
 		if err != nil {
 			logger.WithError(err).Error("Could not setup HTTPS webserver")
 		}
@@ -265,9 +277,15 @@ func routeToInternalServiceProxy(path string, target string, requiresAuth bool,
 	// Wrap the normal http.Handler in a rest.handlerFunc
 	handlerFunc := func(w *rest.ResponseWriter, r *rest.Request) {
 		// All proxied requests should be authenticated first
-		if requiresAuth && !loginOK(w, r) {
-			restUnauthorized(w)
-			return
+		if requiresAuth {
+			if !loginOK(w, r) {
+				restUnauthorized(w)
+				return
+			}
+			if err := getRoles(r).Authorize(userdomain.ActionView, userdomain.AnyScope); err != nil {
+				restForbidden(w)
+				return
+			}
 		}
 		proxy := node.NewReverseProxy(path, targetURL)
 		proxy.ServeHTTP(w.ResponseWriter, r.Request)
@@ -295,20 +313,7 @@ func (sc *ServiceConfig) unAuthorizedClient(realfunc handlerClientFunc) handlerF
 }
 
 func (sc *ServiceConfig) authorizedClient(realfunc handlerClientFunc) handlerFunc {
-	return func(w *rest.ResponseWriter, r *rest.Request) {
-		if !loginOK(w, r) {
-			restUnauthorized(w)
-			return
-		}
-		client, err := sc.getClient()
-		if err != nil {
-			plog.WithError(err).Error("Unable to acquire client")
-			restServerError(w, err)
-			return
-		}
-		defer client.Close()
-		realfunc(w, r, client)
-	}
+	return sc.authorizedClientFor(userdomain.ActionView, realfunc)
 }
 
 func (sc *ServiceConfig) isCollectingStats() handlerFunc {
@@ -354,14 +359,7 @@ func (sc *ServiceConfig) newRequestHandler(check checkFunc, realfunc ctxhandlerF
 }
 
 func (sc *ServiceConfig) checkAuth(realfunc ctxhandlerFunc) handlerFunc {
-	check := func(w *rest.ResponseWriter, r *rest.Request) bool {
-		if !loginOK(w, r) {
-			restUnauthorized(w)
-			return false
-		}
-		return true
-	}
-	return sc.newRequestHandler(check, realfunc)
+	return sc.checkAuthFor(userdomain.ActionView, realfunc)
 }
 
 func (sc *ServiceConfig) noAuth(realfunc ctxhandlerFunc) handlerFunc {
@@ -376,6 +374,7 @@ type requestContext struct {
 	master   master.ClientInterface
 	dataCtx  datastore.Context
 	username string
+	roles    userdomain.RoleBindings
 }
 
 func newRequestContext(sc *ServiceConfig) *requestContext {
@@ -435,7 +434,7 @@ type getRoutes func(sc *ServiceConfig) []rest.Route
 // changes in state
 func (sc *ServiceConfig) startPublicPortListener(shutdown <-chan interface{}) {
 	// set up the public port manager
-	pubmgr := NewPublicPortManager("", sc.certPEMFile, sc.keyPEMFile, func(portAddress string, err error) {
+	pubmgr := NewPublicPortManager("", sc.certs, func(portAddress string, err error) {
 		logger := plog.WithField("portaddress", portAddress).WithError(err)
 
 		// connect to zookeeper
@@ -528,4 +527,7 @@ func restGetAuth0Config(w *rest.ResponseWriter, r *rest.Request, ctx *requestCon
 	w.Write([]byte("var Auth0Config = "))
 	w.WriteJson(auth0Config)
 	w.Write([]byte(";\n"))
+	w.Write([]byte("var OIDCConfig = "))
+	w.WriteJson(OIDCConfig{Enabled: auth.OIDCIsConfigured()})
+	w.Write([]byte(";\n"))
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/control-center/serviced/domain/host"
 	"github.com/control-center/serviced/domain/service"
 	"github.com/control-center/serviced/domain/servicedefinition"
+	userdomain "github.com/control-center/serviced/domain/user"
 	"github.com/control-center/serviced/rpc/agent"
 	"github.com/control-center/serviced/utils"
 	"github.com/zenoss/glog"
@@ -46,6 +47,9 @@ func restGetHosts(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext)
 	glog.V(2).Infof("Returning %d hosts", len(hosts))
 	response := make(map[string]*host.Host)
 	for i, host := range hosts {
+		if !ctx.viewsPool(host.PoolID) {
+			continue
+		}
 		response[host.ID] = &hosts[i]
 		if err := buildHostMonitoringProfile(&hosts[i]); err != nil {
 			restServerError(w, err)
@@ -209,6 +213,10 @@ func restGetServiceConfigFile(w *rest.ResponseWriter, r *rest.Request, ctx *requ
 		return
 	}
 
+	if !authorizeServiceConfigFile(w, ctx, fileID, userdomain.ActionView) {
+		return
+	}
+
 	facade := ctx.getFacade()
 	dataCtx := ctx.getDatastoreContext()
 	file, err := facade.GetServiceConfig(dataCtx, fileID)
@@ -260,6 +268,10 @@ func restUpdateServiceConfigFile(w *rest.ResponseWriter, r *rest.Request, ctx *r
 		return
 	}
 
+	if !authorizeServiceConfigFile(w, ctx, fileID, userdomain.ActionEditService) {
+		return
+	}
+
 	var file servicedefinition.ConfigFile
 	if err := r.DecodeJsonPayload(&file); err != nil {
 		glog.V(1).Infof("Could not decode service config payload: %v", err)
@@ -289,6 +301,10 @@ func restDeleteServiceConfigFile(w *rest.ResponseWriter, r *rest.Request, ctx *r
 		return
 	}
 
+	if !authorizeServiceConfigFile(w, ctx, fileID, userdomain.ActionEditService) {
+		return
+	}
+
 	facade := ctx.getFacade()
 	dataCtx := ctx.getDatastoreContext()
 	if err := facade.DeleteServiceConfig(dataCtx, fileID); err != nil {
@@ -301,6 +317,23 @@ func restDeleteServiceConfigFile(w *rest.ResponseWriter, r *rest.Request, ctx *r
 	return
 }
 
+// authorizeServiceConfigFile writes an error and returns false if the
+// principal may not perform the action on the service that owns the config
+// file
+func authorizeServiceConfigFile(w *rest.ResponseWriter, ctx *requestContext, fileID string, action userdomain.Action) bool {
+	serviceID, err := ctx.getFacade().GetServiceConfigServiceID(ctx.getDatastoreContext(), fileID)
+	if err != nil {
+		glog.Errorf("Could not get service config file: %s", err)
+		restServerError(w, err)
+		return false
+	}
+	if err := ctx.authorize(userdomain.AccessRequest{Action: action, ServiceID: serviceID}); err != nil {
+		restForbidden(w)
+		return false
+	}
+	return true
+}
+
 func restGetServicePublicEndpoints(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	serviceID, err := url.QueryUnescape(r.PathParam("serviceId"))
 	if err != nil {
@@ -428,9 +461,17 @@ func restGetAggregateServices(w *rest.ResponseWriter, r *rest.Request, ctx *requ
 			restServerError(w, err)
 			return
 		}
-		for _, d := range details {
+		for _, d := range filterServiceDetails(ctx, details) {
 			serviceIDs = append(serviceIDs, d.ID)
 		}
+	} else if !ctx.viewsAll() {
+		visible := []string{}
+		for _, serviceID := range serviceIDs {
+			if ctx.viewsService(serviceID) {
+				visible = append(visible, serviceID)
+			}
+		}
+		serviceIDs = visible
 	}
 
 	aggServices, err := facade.GetAggregateServices(dataCtx, time.Now().Add(-tsince), serviceIDs)
@@ -453,6 +494,21 @@ func restGetActiveHostIDs(w *rest.ResponseWriter, r *rest.Request, ctx *requestC
 		return
 	}
 
+	if !ctx.viewsAll() {
+		hosts, err := facade.GetReadHosts(dataCtx)
+		if err != nil {
+			restServerError(w, err)
+			return
+		}
+		visible := []string{}
+		for _, h := range filterReadHosts(ctx, hosts) {
+			if utils.StringInSlice(h.ID, hostids) {
+				visible = append(visible, h.ID)
+			}
+		}
+		hostids = visible
+	}
+
 	w.WriteJson(&hostids)
 
 }
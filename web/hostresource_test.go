@@ -20,6 +20,8 @@ import (
 	"net/http"
 
 	"github.com/control-center/serviced/domain/host"
+	"github.com/control-center/serviced/domain/servicedefinition"
+	userdomain "github.com/control-center/serviced/domain/user"
 	"github.com/stretchr/testify/mock"
 	. "gopkg.in/check.v1"
 )
@@ -303,3 +305,65 @@ func (s *TestWebSuite) TestBuildHostMonitoringProfile(c *C) {
 	c.Assert(len(host.MonitoringProfile.GraphConfigs), Equals, 6)
 	// FIXME: validate the expected content of the metric and graph configs
 }
+
+func (s *TestWebSuite) mockServiceConfigFileOwner(fileID, serviceID, tenantID string) {
+	s.ctx.roles = userdomain.RoleBindings{{Role: userdomain.RoleTenantAdmin, TenantID: "tenant1"}}
+	s.mockFacade.
+		On("GetServiceConfigServiceID", s.ctx.getDatastoreContext(), fileID).
+		Return(serviceID, nil)
+	s.mockFacade.
+		On("GetAccessScope", mock.Anything, mock.MatchedBy(func(req userdomain.AccessRequest) bool { return req.ServiceID == serviceID })).
+		Return(userdomain.Scope{TenantID: tenantID}, nil)
+}
+
+func (s *TestWebSuite) TestRestGetServiceConfigFile(c *C) {
+	s.mockServiceConfigFileOwner("file1", "svc1", "tenant1")
+	s.mockFacade.
+		On("GetServiceConfig", s.ctx.getDatastoreContext(), "file1").
+		Return(&servicedefinition.ConfigFile{Filename: "/etc/app.conf"}, nil)
+	request := s.buildRequest("GET", "/serviceconfigs/file1", "")
+	request.PathParams["fileId"] = "file1"
+
+	restGetServiceConfigFile(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	actual := servicedefinition.ConfigFile{}
+	s.getResult(c, &actual)
+	c.Assert(actual.Filename, Equals, "/etc/app.conf")
+}
+
+func (s *TestWebSuite) TestRestGetServiceConfigFileOfOtherTenant(c *C) {
+	s.mockServiceConfigFileOwner("file2", "svc2", "tenant2")
+	request := s.buildRequest("GET", "/serviceconfigs/file2", "")
+	request.PathParams["fileId"] = "file2"
+
+	restGetServiceConfigFile(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusForbidden)
+	s.mockFacade.AssertNotCalled(c, "GetServiceConfig", mock.Anything, mock.Anything)
+}
+
+func (s *TestWebSuite) TestRestUpdateServiceConfigFileOfOtherTenant(c *C) {
+	s.mockServiceConfigFileOwner("file2", "svc2", "tenant2")
+	request := s.buildRequest("PUT", "/serviceconfigs/file2", `{"Filename":"/etc/app.conf"}`)
+	request.PathParams["fileId"] = "file2"
+
+	restUpdateServiceConfigFile(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusForbidden)
+	s.mockFacade.AssertNotCalled(c, "UpdateServiceConfig", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *TestWebSuite) TestRestDeleteServiceConfigFile(c *C) {
+	s.mockServiceConfigFileOwner("file1", "svc1", "tenant1")
+	s.mockFacade.
+		On("DeleteServiceConfig", s.ctx.getDatastoreContext(), "file1").
+		Return(nil)
+	request := s.buildRequest("DELETE", "/serviceconfigs/file1", "")
+	request.PathParams["fileId"] = "file1"
+
+	restDeleteServiceConfigFile(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	s.mockFacade.AssertCalled(c, "DeleteServiceConfig", s.ctx.getDatastoreContext(), "file1")
+}
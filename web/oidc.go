@@ -0,0 +1,180 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/config"
+	userdomain "github.com/control-center/serviced/domain/user"
+	"github.com/control-center/serviced/utils"
+	"github.com/zenoss/go-json-rest"
+)
+
+// oidcStateCookie holds the state and nonce of a login in progress
+const oidcStateCookie = "ZOIDCState"
+
+// oidcLoginFailedPath is where the browser is sent when a login fails
+const oidcLoginFailedPath = "/#/login?sso_error=1"
+
+// OIDCConfig tells the UI whether to offer OpenID Connect login
+type OIDCConfig struct {
+	Enabled bool
+}
+
+// oidcRoles returns the roles granted to members of the groups by the
+// configured admin groups and group role mappings
+func oidcRoles(groups []string) userdomain.RoleBindings {
+	opts := config.GetOptions()
+	roles := userdomain.RoleBindings{}
+	for _, group := range opts.OIDCAdminGroups {
+		if utils.StringInSlice(strings.TrimSpace(group), groups) {
+			roles.Add(userdomain.RoleBinding{Role: userdomain.RoleClusterAdmin})
+		}
+	}
+	for _, mapping := range opts.OIDCGroupRoles {
+		parts := strings.SplitN(mapping, "=", 2)
+		if len(parts) != 2 {
+			plog.WithField("mapping", mapping).Warn("Ignoring OIDC group role mapping; expected GROUP=ROLE")
+			continue
+		}
+		if !utils.StringInSlice(strings.TrimSpace(parts[0]), groups) {
+			continue
+		}
+		binding, err := userdomain.ParseRoleBinding(parts[1])
+		if err != nil {
+			plog.WithError(err).WithField("mapping", mapping).Warn("Ignoring OIDC group role mapping")
+			continue
+		}
+		roles.Add(binding)
+	}
+	return roles
+}
+
+// oidcRedirectURL returns the callback url registered with the provider
+func oidcRedirectURL(r *http.Request) string {
+	if redirectURL := config.GetOptions().OIDCRedirectURL; redirectURL != "" {
+		return redirectURL
+	}
+	return fmt.Sprintf("https://%s/auth/oidc/callback", r.Host)
+}
+
+// isOIDCToken returns true if the bearer token was issued by the
+// configured OIDC provider
+func isOIDCToken(token string) bool {
+	return token != "" && token != "null" && auth.OIDCIsConfigured() && auth.GetOIDCProvider().IssuedToken(token)
+}
+
+// loginWithOIDCTokenOK verifies an OIDC bearer token and returns the roles
+// granted to its groups
+func loginWithOIDCTokenOK(r *rest.Request, token string) (userdomain.RoleBindings, bool) {
+	identity, err := auth.GetOIDCProvider().VerifyToken(token)
+	if err != nil {
+		plog.WithError(err).WithField("url", r.URL.String()).Debug("Unable to verify OIDC token")
+		return nil, false
+	}
+	roles := oidcRoles(identity.Groups)
+	if len(roles) == 0 {
+		plog.WithField("url", r.URL.String()).WithField("user", identity.User).Debug("Could not login with OIDC token. No roles granted to the user's groups.")
+		return nil, false
+	}
+	return roles, true
+}
+
+// restOIDCLogin sends the browser to the provider's login page
+func restOIDCLogin(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	if !auth.OIDCIsConfigured() {
+		writeJSON(w, &simpleResponse{"OpenID Connect login is not configured", loginLink()}, http.StatusNotFound)
+		return
+	}
+	state, err := randomURLStr()
+	if err != nil {
+		restServerError(w, err)
+		return
+	}
+	nonce, err := randomURLStr()
+	if err != nil {
+		restServerError(w, err)
+		return
+	}
+	loginURL, err := auth.GetOIDCProvider().AuthCodeURL(state, nonce, oidcRedirectURL(r.Request))
+	if err != nil {
+		restServerError(w, err)
+		return
+	}
+	http.SetCookie(
+		w.ResponseWriter,
+		&http.Cookie{
+			Name:     oidcStateCookie,
+			Value:    state + "." + nonce,
+			Path:     "/",
+			MaxAge:   600,
+			Secure:   true,
+			HttpOnly: true,
+		})
+	http.Redirect(w.ResponseWriter, r.Request, loginURL, http.StatusFound)
+}
+
+// restOIDCCallback completes a login when the provider sends the browser
+// back with an authorization code
+func restOIDCCallback(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	fail := func(msg string, err error) {
+		plog.WithError(err).Warn(msg)
+		writeBlankCookie(w, r, oidcStateCookie)
+		http.Redirect(w.ResponseWriter, r.Request, oidcLoginFailedPath, http.StatusFound)
+	}
+	if !auth.OIDCIsConfigured() {
+		writeJSON(w, &simpleResponse{"OpenID Connect login is not configured", loginLink()}, http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	if e := query.Get("error"); e != "" {
+		fail("OIDC provider rejected the login", fmt.Errorf("%s: %s", e, query.Get("error_description")))
+		return
+	}
+	cookie, err := r.Request.Cookie(oidcStateCookie)
+	if err != nil {
+		fail("OIDC login has no state cookie", err)
+		return
+	}
+	parts := strings.SplitN(cookie.Value, ".", 2)
+	if len(parts) != 2 || subtle.ConstantTimeCompare([]byte(parts[0]), []byte(query.Get("state"))) != 1 {
+		fail("OIDC login state does not match", nil)
+		return
+	}
+
+	identity, err := auth.GetOIDCProvider().Exchange(query.Get("code"), oidcRedirectURL(r.Request), parts[1])
+	if err != nil {
+		fail("Could not complete OIDC login", err)
+		return
+	}
+	roles := oidcRoles(identity.Groups)
+	if len(roles) == 0 {
+		fail("OIDC login denied; no roles are granted to the user's groups", fmt.Errorf("user %s, groups %v", identity.User, identity.Groups))
+		return
+	}
+
+	writeBlankCookie(w, r, oidcStateCookie)
+	if err := startSession(w, identity.User, roles); err != nil {
+		fail("Could not create a session for the OIDC login", err)
+		return
+	}
+	plog.WithField("user", identity.User).Info("Logged in with OIDC")
+	http.Redirect(w.ResponseWriter, r.Request, "/", http.StatusFound)
+}
@@ -0,0 +1,74 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"net/http"
+
+	"github.com/control-center/serviced/config"
+	userdomain "github.com/control-center/serviced/domain/user"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestWebSuite) withOIDCOptions(c *C, f func()) {
+	saved := config.GetOptions()
+	defer config.LoadOptions(saved)
+	opts := saved
+	opts.OIDCIssuer = "https://idp.example.com"
+	opts.OIDCClientID = "serviced"
+	opts.OIDCAdminGroups = []string{"admins"}
+	opts.OIDCGroupRoles = []string{"ops=operator", "acme=tenant-admin@tenant:acme", "bad", "worse=nobody"}
+	config.LoadOptions(opts)
+	f()
+}
+
+func (s *TestWebSuite) TestOIDCRoles(c *C) {
+	s.withOIDCOptions(c, func() {
+		c.Assert(oidcRoles([]string{"admins", "ops"}), DeepEquals, userdomain.RoleBindings{
+			{Role: userdomain.RoleClusterAdmin},
+			{Role: userdomain.RoleOperator},
+		})
+		c.Assert(oidcRoles([]string{"acme", "worse"}), DeepEquals, userdomain.RoleBindings{
+			{Role: userdomain.RoleTenantAdmin, TenantID: "acme"},
+		})
+		c.Assert(oidcRoles([]string{"bad", "users"}), HasLen, 0)
+	})
+}
+
+func (s *TestWebSuite) TestOIDCLoginNotConfigured(c *C) {
+	request := s.buildRequest("GET", "http://www.example.com/auth/oidc/login", "")
+	restOIDCLogin(&s.writer, &request, s.ctx)
+	c.Assert(s.recorder.Code, Equals, http.StatusNotFound)
+}
+
+func (s *TestWebSuite) TestOIDCCallbackStateMismatch(c *C) {
+	s.withOIDCOptions(c, func() {
+		request := s.buildRequest("GET", "http://www.example.com/auth/oidc/callback?code=abc&state=forged", "")
+		request.AddCookie(&http.Cookie{Name: oidcStateCookie, Value: "state.nonce"})
+		restOIDCCallback(&s.writer, &request, s.ctx)
+		c.Assert(s.recorder.Code, Equals, http.StatusFound)
+		c.Assert(s.recorder.Header().Get("Location"), Equals, oidcLoginFailedPath)
+	})
+}
+
+func (s *TestWebSuite) TestOIDCCallbackProviderError(c *C) {
+	s.withOIDCOptions(c, func() {
+		request := s.buildRequest("GET", "http://www.example.com/auth/oidc/callback?error=access_denied", "")
+		restOIDCCallback(&s.writer, &request, s.ctx)
+		c.Assert(s.recorder.Code, Equals, http.StatusFound)
+		c.Assert(s.recorder.Header().Get("Location"), Equals, oidcLoginFailedPath)
+	})
+}
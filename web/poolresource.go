@@ -30,6 +30,9 @@ func restGetPools(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext)
 
 	poolsMap := make(map[string]*pool.ResourcePool)
 	for i, pool := range pools {
+		if !ctx.viewsPool(pool.ID) {
+			continue
+		}
 		hostIDs, err := getPoolHostIds(pool.ID, facade, dataCtx)
 		if err != nil {
 			restServerError(w, err)
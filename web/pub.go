@@ -20,6 +20,7 @@ import (
 	"sync"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/utils"
 	"github.com/control-center/serviced/zzk/registry"
 )
@@ -29,19 +30,17 @@ var ErrPortServerRunning = errors.New("port server is already running")
 // PublicPortManager manages all the port servers for a particular host id
 type PublicPortManager struct {
 	hostID    string
-	certFile  string
-	keyFile   string
+	certs     *CertificateCache
 	onFailure func(portNumber string, err error)
 	mu        *sync.RWMutex
 	ports     map[string]*PublicPortHandler
 }
 
 // NewPublicPortManager creates a new public port manager for a host id
-func NewPublicPortManager(hostID, certFile, keyFile string, onFailure func(portAddr string, err error)) *PublicPortManager {
+func NewPublicPortManager(hostID string, certs *CertificateCache, onFailure func(portAddr string, err error)) *PublicPortManager {
 	return &PublicPortManager{
 		hostID:    hostID,
-		certFile:  certFile,
-		keyFile:   keyFile,
+		certs:     certs,
 		onFailure: onFailure,
 		mu:        &sync.RWMutex{},
 		ports:     make(map[string]*PublicPortHandler),
@@ -61,7 +60,7 @@ func (m *PublicPortManager) Enable(portAddr, protocol string, useTLS bool) {
 	}
 
 	// start the port server
-	if err := h.Serve(protocol, useTLS, m.certFile, m.keyFile); err != nil {
+	if err := h.Serve(protocol, useTLS, m.certs); err != nil {
 		m.onFailure(portAddr, err)
 	}
 }
@@ -92,10 +91,24 @@ func (m *PublicPortManager) Set(portAddr string, data []registry.ExportDetails)
 	}
 }
 
+// SetAccess updates the access policy for a particular port handler
+func (m *PublicPortManager) SetAccess(portAddr string, access *servicedefinition.AccessPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h, ok := m.ports[portAddr]
+	if !ok {
+		h = NewPublicPortHandler(portAddr)
+		m.ports[portAddr] = h
+	}
+	h.SetAccess(access)
+}
+
 // PublicPortHandler manages the port server at a specific port address
 type PublicPortHandler struct {
 	portAddr string
 	exports  Exports
+	access   *AccessFilter
 	cancel   chan struct{}
 	wg       *sync.WaitGroup
 }
@@ -108,13 +121,15 @@ func NewPublicPortHandler(portAddr string, data ...registry.ExportDetails) *Publ
 	return &PublicPortHandler{
 		portAddr: portAddr,
 		exports:  NewRoundRobinExports(data), // round-robin is the default
+		access:   NewAccessFilter(portAddr),
 		cancel:   cancel,
 		wg:       &sync.WaitGroup{},
 	}
 }
 
-// Serve starts the port server at address
-func (h *PublicPortHandler) Serve(protocol string, useTLS bool, certFile, keyFile string) error {
+// Serve starts the port server at address.  TLS connections get the
+// certificate of the server name they ask for or of the port address.
+func (h *PublicPortHandler) Serve(protocol string, useTLS bool, certs *CertificateCache) error {
 	logger := plog.WithFields(log.Fields{
 		"portaddress": h.portAddr,
 		"protocol":    protocol,
@@ -133,14 +148,6 @@ func (h *PublicPortHandler) Serve(protocol string, useTLS bool, certFile, keyFil
 	var tlsConfig *tls.Config
 	if useTLS {
 
-		// get the certificate
-		certFile, keyFile = GetCertFiles(certFile, keyFile)
-		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
-		if err != nil {
-			logger.WithError(err).Debug("Could not set up certificate")
-			return err
-		}
-
 		// cipher suites and tls min version change may not be needed with
 		// golang 1.5:
 		// https://github.com/golang/go/issues/10094
@@ -149,10 +156,10 @@ func (h *PublicPortHandler) Serve(protocol string, useTLS bool, certFile, keyFil
 			MinVersion:               utils.MinTLS("http"),
 			PreferServerCipherSuites: true,
 			CipherSuites:             utils.CipherSuites("http"),
-			Certificates:             []tls.Certificate{cert},
+			GetCertificate:           certs.GetCertificate(h.portAddr),
 		}
 
-		logger.Debug("Set up tls certificate")
+		logger.Debug("Set up tls certificate selection")
 	}
 
 	// start listening on the port with a non-tls connection
@@ -168,9 +175,9 @@ func (h *PublicPortHandler) Serve(protocol string, useTLS bool, certFile, keyFil
 		defer logger.Debug("Port server exited")
 
 		if protocol == "http" || protocol == "https" {
-			ServeHTTP(h.cancel, h.portAddr, protocol, listener, tlsConfig, h.exports)
+			ServeHTTP(h.cancel, h.portAddr, protocol, listener, tlsConfig, h.exports, h.access)
 		} else {
-			ServeTCP(h.cancel, listener, tlsConfig, h.exports)
+			ServeTCP(h.cancel, listener, tlsConfig, h.exports, h.access)
 		}
 		h.wg.Done()
 	}()
@@ -192,3 +199,8 @@ func (h *PublicPortHandler) Stop() {
 func (h *PublicPortHandler) SetExports(data []registry.ExportDetails) {
 	h.exports.Set(data)
 }
+
+// SetAccess updates the access policy for the port handler
+func (h *PublicPortHandler) SetAccess(access *servicedefinition.AccessPolicy) {
+	h.access.Set(access)
+}
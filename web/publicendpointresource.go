@@ -17,6 +17,7 @@
 package web
 
 import (
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/zenoss/glog"
 	"github.com/zenoss/go-json-rest"
 
@@ -154,6 +155,37 @@ func restVirtualHostEnable(w *rest.ResponseWriter, r *rest.Request, ctx *request
 	restSuccess(w)
 }
 
+// restVirtualHostAccess sets the client access policy of a virtual host
+// endpoint.  An empty policy allows every client.
+func restVirtualHostAccess(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	glog.V(1).Infof("Set VHOST access with %s %#v", r.URL.Path, r)
+
+	serviceid, application, vhostname, err := getVHostContext(r)
+	if err != nil {
+		restServerError(w, err)
+		return
+	}
+
+	var access servicedefinition.AccessPolicy
+	err = r.DecodeJsonPayload(&access)
+	if err != nil {
+		restBadRequest(w, err)
+		return
+	}
+
+	facade := ctx.getFacade()
+	dataCtx := ctx.getDatastoreContext()
+
+	err = facade.SetPublicEndpointVHostAccess(dataCtx, serviceid, application, vhostname, &access)
+	if err != nil {
+		glog.Errorf("Error setting access for vhost %s on service (%s): %v", vhostname, serviceid, err)
+		restServerError(w, err)
+		return
+	}
+
+	restSuccess(w)
+}
+
 // Returns the service, application, and portnumber from the request
 func getPortContext(r *rest.Request) (string, string, string, error) {
 	glog.V(1).Infof("in getPortContext()")
@@ -287,6 +319,39 @@ func restPortEnable(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext
 	restSuccess(w)
 }
 
+// restPortAccess sets the client access policy of a port endpoint.  An empty
+// policy allows every client.
+func restPortAccess(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
+	glog.V(1).Infof("Set PORT access with %s %#v", r.URL.Path, r)
+
+	serviceid, application, port, err := getPortContext(r)
+	if err != nil {
+		err := fmt.Errorf("Error setting port access for service (%s): %v", serviceid, err)
+		glog.Error(err)
+		restServerError(w, err)
+		return
+	}
+
+	var access servicedefinition.AccessPolicy
+	err = r.DecodeJsonPayload(&access)
+	if err != nil {
+		restBadRequest(w, err)
+		return
+	}
+
+	facade := ctx.getFacade()
+	dataCtx := ctx.getDatastoreContext()
+
+	err = facade.SetPublicEndpointPortAccess(dataCtx, serviceid, application, port, &access)
+	if err != nil {
+		glog.Errorf("Error setting access for service (%s) port %s: %v", serviceid, port, err)
+		restServerError(w, err)
+		return
+	}
+
+	restSuccess(w)
+}
+
 // Get all virtual hosts
 type virtualHost struct {
 	Name            string
@@ -0,0 +1,70 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/control-center/serviced/domain/servicedefinition"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestWebSuite) TestRestPortAccess(c *C) {
+	request := s.buildRequest("PUT", "/services/svc1/endpoint/zproxy/access/ports/:22222", `{"Allow":["10.0.0.0/8"],"RateLimit":60}`)
+	request.PathParams["serviceId"] = "svc1"
+	request.PathParams["application"] = "zproxy"
+	request.PathParams["portname"] = ":22222"
+	expected := &servicedefinition.AccessPolicy{Allow: []string{"10.0.0.0/8"}, RateLimit: 60}
+	s.mockFacade.
+		On("SetPublicEndpointPortAccess", s.ctx.getDatastoreContext(), "svc1", "zproxy", ":22222", expected).
+		Return(nil)
+
+	restPortAccess(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	s.mockFacade.AssertExpectations(c)
+}
+
+func (s *TestWebSuite) TestRestPortAccessFails(c *C) {
+	request := s.buildRequest("PUT", "/services/svc1/endpoint/zproxy/access/ports/:22222", `{"Deny":["bogus"]}`)
+	request.PathParams["serviceId"] = "svc1"
+	request.PathParams["application"] = "zproxy"
+	request.PathParams["portname"] = ":22222"
+	expectedError := errors.New("invalid network")
+	s.mockFacade.
+		On("SetPublicEndpointPortAccess", s.ctx.getDatastoreContext(), "svc1", "zproxy", ":22222", &servicedefinition.AccessPolicy{Deny: []string{"bogus"}}).
+		Return(expectedError)
+
+	restPortAccess(&(s.writer), &request, s.ctx)
+
+	s.assertServerError(c, expectedError)
+}
+
+func (s *TestWebSuite) TestRestVirtualHostAccess(c *C) {
+	request := s.buildRequest("PUT", "/services/svc1/endpoint/zproxy/access/vhosts/zenoss", `{}`)
+	request.PathParams["serviceId"] = "svc1"
+	request.PathParams["application"] = "zproxy"
+	request.PathParams["name"] = "zenoss"
+	s.mockFacade.
+		On("SetPublicEndpointVHostAccess", s.ctx.getDatastoreContext(), "svc1", "zproxy", "zenoss", &servicedefinition.AccessPolicy{}).
+		Return(nil)
+
+	restVirtualHostAccess(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusOK)
+	s.mockFacade.AssertExpectations(c)
+}
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -24,11 +25,13 @@ import (
 	"github.com/Sirupsen/logrus"
 	"github.com/zenoss/go-json-rest"
 
+	"github.com/control-center/serviced/commons/statistics"
 	"github.com/control-center/serviced/dao"
 	daoclient "github.com/control-center/serviced/dao/client"
 	"github.com/control-center/serviced/domain"
 	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/service"
+	userdomain "github.com/control-center/serviced/domain/user"
 	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/isvcs"
 	"github.com/control-center/serviced/servicedversion"
@@ -97,7 +100,9 @@ func getServices(ctx *requestContext, tenantID string, since time.Duration) ([]s
 func getISVCS() []service.Service {
 	services := []service.Service{}
 	services = append(services, isvcs.InternalServicesISVC)
-	services = append(services, isvcs.ElasticsearchServicedISVC)
+	if config.GetOptions().DatastoreDriver != config.DatastoreLocal {
+		services = append(services, isvcs.ElasticsearchServicedISVC)
+	}
 	services = append(services, isvcs.ElasticsearchLogStashISVC)
 	services = append(services, isvcs.ZookeeperISVC)
 	services = append(services, isvcs.LogstashISVC)
@@ -113,7 +118,9 @@ func getISVCS() []service.Service {
 func getIRS() []dao.RunningService {
 	services := []dao.RunningService{}
 	services = append(services, isvcs.InternalServicesIRS)
-	services = append(services, isvcs.ElasticsearchServicedIRS)
+	if config.GetOptions().DatastoreDriver != config.DatastoreLocal {
+		services = append(services, isvcs.ElasticsearchServicedIRS)
+	}
 	services = append(services, isvcs.ElasticsearchLogStashIRS)
 	services = append(services, isvcs.GetZooKeeperRunningInstances()...)
 	services = append(services, isvcs.LogstashIRS)
@@ -172,6 +179,7 @@ func restGetAllServices(w *rest.ResponseWriter, r *rest.Request, ctx *requestCon
 				result[ii].MonitoringProfile.GraphConfigs = append(result[ii].MonitoringProfile.GraphConfigs, getInternalGraphConfigs(result[ii].ID)...)
 			}
 		}
+		result = filterServices(ctx, result)
 		w.WriteJson(&result)
 		return
 	}
@@ -189,6 +197,7 @@ func restGetAllServices(w *rest.ResponseWriter, r *rest.Request, ctx *requestCon
 				result[ii].MonitoringProfile.GraphConfigs = append(result[ii].MonitoringProfile.GraphConfigs, getInternalGraphConfigs(result[ii].ID)...)
 			}
 		}
+		result = filterServices(ctx, result)
 		w.WriteJson(&result)
 		return
 	}
@@ -233,9 +242,24 @@ func restGetAllServices(w *rest.ResponseWriter, r *rest.Request, ctx *requestCon
 			result[ii].MonitoringProfile.GraphConfigs = append(result[ii].MonitoringProfile.GraphConfigs, getInternalGraphConfigs(result[ii].ID)...)
 		}
 	}
+	result = filterServices(ctx, result)
 	w.WriteJson(&result)
 }
 
+// filterServices returns the services that the principal may view
+func filterServices(ctx *requestContext, svcs []service.Service) []service.Service {
+	if ctx.viewsAll() {
+		return svcs
+	}
+	result := []service.Service{}
+	for _, svc := range svcs {
+		if ctx.viewsService(svc.ID) {
+			result = append(result, svc)
+		}
+	}
+	return result
+}
+
 func restGetRunningForHost(w *rest.ResponseWriter, r *rest.Request, client *daoclient.ControlClient) {
 	hostID, err := url.QueryUnescape(r.PathParam("hostId"))
 	if err != nil {
@@ -343,7 +367,7 @@ func restGetTopServices(w *rest.ResponseWriter, r *rest.Request, ctx *requestCon
 		restServerError(w, err)
 		return
 	}
-	for _, tenant := range allTenants {
+	for _, tenant := range filterServiceDetails(ctx, allTenants) {
 		service, err := ctx.getFacade().GetService(ctx.getDatastoreContext(), tenant.ID)
 		if err != nil {
 			plog.WithField("tenantid", tenant.ID).WithError(err).Error("Could not get service")
@@ -352,7 +376,9 @@ func restGetTopServices(w *rest.ResponseWriter, r *rest.Request, ctx *requestCon
 		}
 		topServices = append(topServices, *service)
 	}
-	topServices = append(topServices, isvcs.InternalServicesISVC)
+	if ctx.viewsAll() {
+		topServices = append(topServices, isvcs.InternalServicesISVC)
+	}
 	plog.WithField("numservices", len(topServices)).Debug("Got top services")
 	w.WriteJson(&topServices)
 }
@@ -614,6 +640,11 @@ func restRestartServices(w *rest.ResponseWriter, r *rest.Request, ctx *requestCo
 	}
 
 	logger := plog.WithField("serviceids", serviceRequest.ServiceIDs)
+	if err := ctx.authorizeServices(userdomain.ActionControlService, serviceRequest.ServiceIDs); err != nil {
+		logger.Debug("Request is not authorized")
+		restForbidden(w)
+		return
+	}
 	serviceFacade := ctx.getFacade()
 	dataCtx := ctx.getDatastoreContext()
 	_, err = serviceFacade.RestartService(dataCtx, serviceRequest)
@@ -683,6 +714,11 @@ func restStartServices(w *rest.ResponseWriter, r *rest.Request, ctx *requestCont
 	}
 
 	logger := plog.WithField("serviceids", serviceRequest.ServiceIDs)
+	if err := ctx.authorizeServices(userdomain.ActionControlService, serviceRequest.ServiceIDs); err != nil {
+		logger.Debug("Request is not authorized")
+		restForbidden(w)
+		return
+	}
 	serviceFacade := ctx.getFacade()
 	dataCtx := ctx.getDatastoreContext()
 	_, err = serviceFacade.StartService(dataCtx, serviceRequest)
@@ -743,6 +779,11 @@ func restStopServices(w *rest.ResponseWriter, r *rest.Request, ctx *requestConte
 	}
 
 	logger := plog.WithField("serviceids", serviceRequest.ServiceIDs)
+	if err := ctx.authorizeServices(userdomain.ActionControlService, serviceRequest.ServiceIDs); err != nil {
+		logger.Debug("Request is not authorized")
+		restForbidden(w)
+		return
+	}
 
 	serviceFacade := ctx.getFacade()
 	dataCtx := ctx.getDatastoreContext()
@@ -848,7 +889,15 @@ func restGetServicedVersion(w *rest.ResponseWriter, r *rest.Request) {
 	w.WriteJson(servicedversion.GetVersion())
 }
 
-func restGetStorage(w *rest.ResponseWriter, r *rest.Request, client *daoclient.ControlClient) {
+// storageForecast is the predicted available storage on the thin pool
+// devices or a tenant volume at the end of the lookahead period
+type storageForecast struct {
+	Name      string
+	Lookahead int // seconds
+	statistics.Forecast
+}
+
+func restGetStorage(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	volumeStatuses := volume.GetStatus()
 	if volumeStatuses == nil || len(volumeStatuses.GetAllStatuses()) == 0 {
 		err := fmt.Errorf("Unexpected error getting volume status")
@@ -861,6 +910,7 @@ func restGetStorage(w *rest.ResponseWriter, r *rest.Request, client *daoclient.C
 		Name              string
 		Status            volume.Status
 		MonitoringProfile domain.MonitorProfile
+		Forecasts         []storageForecast `json:",omitempty"`
 	}
 
 	// REST collections should return arrays, not maps
@@ -885,12 +935,35 @@ func restGetStorage(w *rest.ResponseWriter, r *rest.Request, client *daoclient.C
 		}
 
 		volumeInfo.MonitoringProfile = *profile
+
+		// Storage availability is only predicted for the thin pool
+		if _, ok := volumeStatuses.DeviceMapperStatusMap[volumeName]; ok {
+			volumeInfo.Forecasts = getStorageForecasts(ctx)
+		}
 		storageInfo = append(storageInfo, volumeInfo)
 	}
 
 	w.WriteJson(storageInfo)
 }
 
+// getStorageForecasts returns the predicted available storage for the
+// configured lookahead period, sorted by name.  The storage page still works
+// without them, so errors are only logged.
+func getStorageForecasts(ctx *requestContext) []storageForecast {
+	lookahead := config.GetOptions().StorageLookaheadPeriod
+	forecasts, err := ctx.getFacade().PredictStorageAvailability(ctx.getDatastoreContext(), time.Duration(lookahead)*time.Second)
+	if err != nil {
+		plog.WithError(err).Warn("Unable to predict storage availability")
+		return nil
+	}
+	result := make([]storageForecast, 0, len(forecasts))
+	for name, forecast := range forecasts {
+		result = append(result, storageForecast{Name: name, Lookahead: lookahead, Forecast: forecast})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
 func restGetUIConfig(w *rest.ResponseWriter, r *rest.Request, client *daoclient.ControlClient) {
 	w.WriteJson(uiConfig)
 }
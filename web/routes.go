@@ -13,7 +13,10 @@
 
 package web
 
-import "github.com/zenoss/go-json-rest"
+import (
+	userdomain "github.com/control-center/serviced/domain/user"
+	"github.com/zenoss/go-json-rest"
+)
 
 //getRoutes returns all registered rest routes
 func (sc *ServiceConfig) getRoutes() []rest.Route {
@@ -25,8 +28,8 @@ func (sc *ServiceConfig) getRoutes() []rest.Route {
 
 		// Backups
 		rest.Route{"GET", "/backup/check", gz(sc.authorizedClient(RestBackupCheck))},
-		rest.Route{"GET", "/backup/create", gz(sc.authorizedClient(RestBackupCreate))},
-		rest.Route{"GET", "/backup/restore", gz(sc.authorizedClient(RestBackupRestore))},
+		rest.Route{"GET", "/backup/create", gz(sc.authorizedClientFor(userdomain.ActionBackup, RestBackupCreate))},
+		rest.Route{"GET", "/backup/restore", gz(sc.authorizedClientFor(userdomain.ActionRestore, RestBackupRestore))},
 		rest.Route{"GET", "/backup/list", gz(sc.authorizedClient(RestBackupFileList))},
 		rest.Route{"GET", "/backup/status", gz(sc.authorizedClient(RestBackupStatus))},
 		rest.Route{"GET", "/backup/restore/status", gz(sc.authorizedClient(RestRestoreStatus))},
@@ -36,24 +39,24 @@ func (sc *ServiceConfig) getRoutes() []rest.Route {
 		rest.Route{"GET", "/hosts/running", gz(sc.checkAuth(restGetActiveHostIDs))},
 		rest.Route{"GET", "/hosts/defaultHostAlias", gz(sc.checkAuth(restGetDefaultHostAlias))},
 		rest.Route{"GET", "/hosts/:hostId", gz(sc.checkAuth(restGetHost))},
-		rest.Route{"POST", "/hosts/add", gz(sc.checkAuth(restAddHost))},
-		rest.Route{"DELETE", "/hosts/:hostId", gz(sc.checkAuth(restRemoveHost))},
-		rest.Route{"PUT", "/hosts/:hostId", gz(sc.checkAuth(restUpdateHost))},
+		rest.Route{"POST", "/hosts/add", gz(sc.checkAuthFor(userdomain.ActionEditHost, restAddHost))},
+		rest.Route{"DELETE", "/hosts/:hostId", gz(sc.checkAuthFor(userdomain.ActionEditHost, restRemoveHost))},
+		rest.Route{"PUT", "/hosts/:hostId", gz(sc.checkAuthFor(userdomain.ActionEditHost, restUpdateHost))},
 		rest.Route{"GET", "/hosts/:hostId/running", gz(sc.authorizedClient(restGetRunningForHost))},
-		rest.Route{"DELETE", "/hosts/:hostId/:serviceStateId", gz(sc.authorizedClient(restKillRunning))},
-		rest.Route{"POST", "/hosts/:hostId/key", gz(sc.checkAuth(restResetHostKey))},
+		rest.Route{"DELETE", "/hosts/:hostId/:serviceStateId", gz(sc.authorizedClientFor(userdomain.ActionControlService, restKillRunning))},
+		rest.Route{"POST", "/hosts/:hostId/key", gz(sc.checkAuthFor(userdomain.ActionEditHost, restResetHostKey))},
 
 		// Pools
 		rest.Route{"GET", "/pools/:poolId", gz(sc.checkAuth(restGetPool))},
-		rest.Route{"DELETE", "/pools/:poolId", gz(sc.checkAuth(restRemovePool))},
-		rest.Route{"PUT", "/pools/:poolId", gz(sc.checkAuth(restUpdatePool))},
-		rest.Route{"POST", "/pools/add", gz(sc.checkAuth(restAddPool))},
+		rest.Route{"DELETE", "/pools/:poolId", gz(sc.checkAuthFor(userdomain.ActionEditPool, restRemovePool))},
+		rest.Route{"PUT", "/pools/:poolId", gz(sc.checkAuthFor(userdomain.ActionEditPool, restUpdatePool))},
+		rest.Route{"POST", "/pools/add", gz(sc.checkAuthFor(userdomain.ActionEditPool, restAddPool))},
 		rest.Route{"GET", "/pools", gz(sc.checkAuth(restGetPools))},
 		rest.Route{"GET", "/pools/:poolId/hosts", gz(sc.checkAuth(restGetHostsForResourcePool))},
 
 		// Pools (VirtualIP)
-		rest.Route{"PUT", "/pools/:poolId/virtualip", gz(sc.checkAuth(restAddPoolVirtualIP))},
-		rest.Route{"DELETE", "/pools/:poolId/virtualip/*ip", gz(sc.checkAuth(restRemovePoolVirtualIP))},
+		rest.Route{"PUT", "/pools/:poolId/virtualip", gz(sc.checkAuthFor(userdomain.ActionEditPool, restAddPoolVirtualIP))},
+		rest.Route{"DELETE", "/pools/:poolId/virtualip/*ip", gz(sc.checkAuthFor(userdomain.ActionEditPool, restRemovePoolVirtualIP))},
 
 		// Pools (IPs)
 		rest.Route{"GET", "/pools/:poolId/ips", gz(sc.checkAuth(restGetPoolIps))},
@@ -65,38 +68,40 @@ func (sc *ServiceConfig) getRoutes() []rest.Route {
 		rest.Route{"GET", "/services/:serviceId/running", gz(sc.authorizedClient(restGetRunningForService))},
 		rest.Route{"GET", "/services/:serviceId/:serviceStateId/logs", gz(sc.authorizedClient(restGetServiceStateLogs))},
 		rest.Route{"GET", "/services/:serviceId/:serviceStateId/logs/download", gz(sc.authorizedClient(downloadServiceStateLogs))},
-		rest.Route{"POST", "/services/add", gz(sc.authorizedClient(restAddService))},
-		rest.Route{"POST", "/services/deploy", gz(sc.authorizedClient(restDeployService))},
-		rest.Route{"PUT", "/services/restartServices", gz(sc.checkAuth(restRestartServices))},
-		rest.Route{"PUT", "/services/startServices", gz(sc.checkAuth(restStartServices))},
-		rest.Route{"PUT", "/services/stopServices", gz(sc.checkAuth(restStopServices))},
-		rest.Route{"DELETE", "/services/:serviceId", gz(sc.checkAuth(restRemoveService))},
+		rest.Route{"POST", "/services/add", gz(sc.authorizedClientFor(userdomain.ActionEditService, restAddService))},
+		rest.Route{"POST", "/services/deploy", gz(sc.authorizedClientFor(userdomain.ActionEditService, restDeployService))},
+		rest.Route{"PUT", "/services/restartServices", gz(sc.checkAuthAnywhere(userdomain.ActionControlService, restRestartServices))},
+		rest.Route{"PUT", "/services/startServices", gz(sc.checkAuthAnywhere(userdomain.ActionControlService, restStartServices))},
+		rest.Route{"PUT", "/services/stopServices", gz(sc.checkAuthAnywhere(userdomain.ActionControlService, restStopServices))},
+		rest.Route{"DELETE", "/services/:serviceId", gz(sc.checkAuthFor(userdomain.ActionEditService, restRemoveService))},
 		rest.Route{"GET", "/services/:serviceId/logs", gz(sc.authorizedClient(restGetServiceLogs))},
-		rest.Route{"PUT", "/services/:serviceId", gz(sc.authorizedClient(restUpdateService))},
-		rest.Route{"GET", "/services/:serviceId/snapshot", gz(sc.authorizedClient(restSnapshotService))},
-		rest.Route{"PUT", "/services/:serviceId/restartService", gz(sc.checkAuth(restRestartService))},
-		rest.Route{"PUT", "/services/:serviceId/startService", gz(sc.checkAuth(restStartService))},
-		rest.Route{"PUT", "/services/:serviceId/stopService", gz(sc.checkAuth(restStopService))},
-		rest.Route{"POST", "/services/:serviceId/migrate", sc.authorizedClient(restPostServicesForMigration)},
+		rest.Route{"PUT", "/services/:serviceId", gz(sc.authorizedClientFor(userdomain.ActionEditService, restUpdateService))},
+		rest.Route{"GET", "/services/:serviceId/snapshot", gz(sc.authorizedClientFor(userdomain.ActionSnapshot, restSnapshotService))},
+		rest.Route{"PUT", "/services/:serviceId/restartService", gz(sc.checkAuthFor(userdomain.ActionControlService, restRestartService))},
+		rest.Route{"PUT", "/services/:serviceId/startService", gz(sc.checkAuthFor(userdomain.ActionControlService, restStartService))},
+		rest.Route{"PUT", "/services/:serviceId/stopService", gz(sc.checkAuthFor(userdomain.ActionControlService, restStopService))},
+		rest.Route{"POST", "/services/:serviceId/migrate", sc.authorizedClientFor(userdomain.ActionEditService, restPostServicesForMigration)},
 
 		// Services (Virtual Host)
-		rest.Route{"PUT", "/services/:serviceId/endpoint/:application/vhosts/*name", gz(sc.checkAuth(restAddVirtualHost))},
-		rest.Route{"DELETE", "/services/:serviceId/endpoint/:application/vhosts/*name", gz(sc.checkAuth(restRemoveVirtualHost))},
-		rest.Route{"POST", "/services/:serviceId/endpoint/:application/vhosts/*name", gz(sc.checkAuth(restVirtualHostEnable))},
+		rest.Route{"PUT", "/services/:serviceId/endpoint/:application/vhosts/*name", gz(sc.checkAuthFor(userdomain.ActionEditService, restAddVirtualHost))},
+		rest.Route{"DELETE", "/services/:serviceId/endpoint/:application/vhosts/*name", gz(sc.checkAuthFor(userdomain.ActionEditService, restRemoveVirtualHost))},
+		rest.Route{"POST", "/services/:serviceId/endpoint/:application/vhosts/*name", gz(sc.checkAuthFor(userdomain.ActionEditService, restVirtualHostEnable))},
+		rest.Route{"PUT", "/services/:serviceId/endpoint/:application/access/vhosts/*name", gz(sc.checkAuthFor(userdomain.ActionEditService, restVirtualHostAccess))},
 		// Services (Endpoint Ports)
-		rest.Route{"PUT", "/services/:serviceId/endpoint/:application/ports/*portname", gz(sc.checkAuth(restAddPort))},
-		rest.Route{"DELETE", "/services/:serviceId/endpoint/:application/ports/*portname", gz(sc.checkAuth(restRemovePort))},
-		rest.Route{"POST", "/services/:serviceId/endpoint/:application/ports/*portname", gz(sc.checkAuth(restPortEnable))},
+		rest.Route{"PUT", "/services/:serviceId/endpoint/:application/ports/*portname", gz(sc.checkAuthFor(userdomain.ActionEditService, restAddPort))},
+		rest.Route{"DELETE", "/services/:serviceId/endpoint/:application/ports/*portname", gz(sc.checkAuthFor(userdomain.ActionEditService, restRemovePort))},
+		rest.Route{"POST", "/services/:serviceId/endpoint/:application/ports/*portname", gz(sc.checkAuthFor(userdomain.ActionEditService, restPortEnable))},
+		rest.Route{"PUT", "/services/:serviceId/endpoint/:application/access/ports/*portname", gz(sc.checkAuthFor(userdomain.ActionEditService, restPortAccess))},
 
 		// Services (IP)
-		rest.Route{"PUT", "/services/:serviceId/ip", gz(sc.checkAuth(restServiceAutomaticAssignIP))},
-		rest.Route{"PUT", "/services/:serviceId/ip/*ip", gz(sc.checkAuth(restServiceManualAssignIP))},
+		rest.Route{"PUT", "/services/:serviceId/ip", gz(sc.checkAuthFor(userdomain.ActionEditService, restServiceAutomaticAssignIP))},
+		rest.Route{"PUT", "/services/:serviceId/ip/*ip", gz(sc.checkAuthFor(userdomain.ActionEditService, restServiceManualAssignIP))},
 
 		// Service templates (App templates)
 		rest.Route{"GET", "/templates", gz(sc.checkAuth(restGetAppTemplates))},
-		rest.Route{"POST", "/templates/add", gz(sc.checkAuth(restAddAppTemplate))},
-		rest.Route{"DELETE", "/templates/:templateId", gz(sc.checkAuth(restRemoveAppTemplate))},
-		rest.Route{"POST", "/templates/deploy", gz(sc.checkAuth(restDeployAppTemplate))},
+		rest.Route{"POST", "/templates/add", gz(sc.checkAuthFor(userdomain.ActionEditTemplate, restAddAppTemplate))},
+		rest.Route{"DELETE", "/templates/:templateId", gz(sc.checkAuthFor(userdomain.ActionEditTemplate, restRemoveAppTemplate))},
+		rest.Route{"POST", "/templates/deploy", gz(sc.checkAuthAnywhere(userdomain.ActionEditService, restDeployAppTemplate))},
 		rest.Route{"POST", "/templates/deploy/status", gz(sc.checkAuth(restDeployAppTemplateStatus))},
 		rest.Route{"GET", "/templates/deploy/active", gz(sc.checkAuth(restDeployAppTemplateActive))},
 
@@ -112,6 +117,8 @@ func (sc *ServiceConfig) getRoutes() []rest.Route {
 		// Generic static data
 		rest.Route{"GET", "/favicon.ico", gz(favIcon)},
 		rest.Route{"GET", "/static/globals.js", sc.noAuth(restGetAuth0Config)},
+		rest.Route{"GET", "/auth/oidc/login", sc.noAuth(restOIDCLogin)},
+		rest.Route{"GET", "/auth/oidc/callback", sc.noAuth(restOIDCCallback)},
 		rest.Route{"GET", "/static/*resource", gz(staticData)},
 		rest.Route{"GET", "/licenses.html", gz(licenses)},
 
@@ -119,7 +126,7 @@ func (sc *ServiceConfig) getRoutes() []rest.Route {
 		rest.Route{"GET", "/dockerIsLoggedIn", gz(sc.authorizedClient(restDockerIsLoggedIn))},
 		rest.Route{"GET", "/stats", gz(sc.isCollectingStats())},
 		rest.Route{"GET", "/version", gz(restGetServicedVersion)},
-		rest.Route{"GET", "/storage", gz(sc.authorizedClient(restGetStorage))},
+		rest.Route{"GET", "/storage", gz(sc.checkAuth(restGetStorage))},
 
 		// V2 API
 		rest.Route{"GET", "/api/v2/pools", gz(sc.checkAuth(getPools))},
@@ -132,7 +139,7 @@ func (sc *ServiceConfig) getRoutes() []rest.Route {
 		rest.Route{"GET", "/api/v2/internalservicestatuses", gz(sc.checkAuth(getInternalServiceStatuses))},
 		rest.Route{"GET", "/api/v2/services", gz(sc.checkAuth(getAllServiceDetails))},
 		rest.Route{"GET", "/api/v2/services/:serviceId", gz(sc.checkAuth(getServiceDetails))},
-		rest.Route{"PUT", "/api/v2/services/:serviceId", gz(sc.checkAuth(putServiceDetails))},
+		rest.Route{"PUT", "/api/v2/services/:serviceId", gz(sc.checkAuthFor(userdomain.ActionEditService, putServiceDetails))},
 		rest.Route{"GET", "/api/v2/services/:serviceId/services", gz(sc.checkAuth(getChildServiceDetails))},
 		rest.Route{"GET", "/api/v2/services/:serviceId/instances", gz(sc.checkAuth(restGetServiceInstances))},
 		rest.Route{"GET", "/api/v2/services/:serviceId/monitoringprofile", gz(sc.checkAuth(restGetServiceMonitoringProfile))},
@@ -141,15 +148,26 @@ func (sc *ServiceConfig) getRoutes() []rest.Route {
 		rest.Route{"GET", "/api/v2/services/:serviceId/exportendpoints", gz(sc.checkAuth(restGetServiceExportedEndpoints))},
 		rest.Route{"GET", "/api/v2/services/:serviceId/descendantstates", gz(sc.checkAuth(restCountDescendantStates))},
 		rest.Route{"GET", "/api/v2/services/:serviceId/context", gz(sc.checkAuth(getServiceContext))},
-		rest.Route{"PUT", "/api/v2/services/:serviceId/context", gz(sc.checkAuth(putServiceContext))},
+		rest.Route{"PUT", "/api/v2/services/:serviceId/context", gz(sc.checkAuthFor(userdomain.ActionEditService, putServiceContext))},
 		rest.Route{"GET", "/api/v2/statuses", gz(sc.checkAuth(restGetAggregateServices))},
 		rest.Route{"GET", "/api/v2/hoststatuses", gz(sc.checkAuth(getHostStatuses))},
+		rest.Route{"GET", "/api/v2/alerts", gz(sc.checkAuth(getAlerts))},
+		rest.Route{"PUT", "/api/v2/alerts/:alertId/ack", gz(sc.checkAuthAnywhere(userdomain.ActionControlService, putAlertAck))},
+		rest.Route{"GET", "/api/v2/schedules", gz(sc.checkAuth(getSchedules))},
+		rest.Route{"POST", "/api/v2/schedules", gz(sc.checkAuth(postSchedule))},
+		rest.Route{"GET", "/api/v2/schedules/:scheduleId", gz(sc.checkAuth(getSchedule))},
+		rest.Route{"PUT", "/api/v2/schedules/:scheduleId", gz(sc.checkAuth(putSchedule))},
+		rest.Route{"DELETE", "/api/v2/schedules/:scheduleId", gz(sc.checkAuth(deleteSchedule))},
+		rest.Route{"GET", "/api/v2/certificates", gz(sc.checkAuth(getCertificates))},
+		rest.Route{"POST", "/api/v2/certificates", gz(sc.checkAuthFor(userdomain.ActionManageCertificates, postCertificate))},
+		rest.Route{"DELETE", "/api/v2/certificates/:certificateId", gz(sc.checkAuthFor(userdomain.ActionManageCertificates, deleteCertificate))},
+		rest.Route{"GET", "/api/v2/audit", gz(sc.checkAuthFor(userdomain.ActionViewAudit, getAuditEvents))},
 
 		rest.Route{"GET", "/api/v2/services/:serviceId/serviceconfigs", gz(sc.checkAuth(restGetServiceConfigFiles))},
-		rest.Route{"POST", "/api/v2/services/:serviceId/serviceconfigs", gz(sc.checkAuth(restAddServiceConfigFile))},
+		rest.Route{"POST", "/api/v2/services/:serviceId/serviceconfigs", gz(sc.checkAuthFor(userdomain.ActionEditService, restAddServiceConfigFile))},
 		rest.Route{"GET", "/api/v2/serviceconfigs/:fileId", gz(sc.checkAuth(restGetServiceConfigFile))},
-		rest.Route{"PUT", "/api/v2/serviceconfigs/:fileId", gz(sc.checkAuth(restUpdateServiceConfigFile))},
-		rest.Route{"DELETE", "/api/v2/serviceconfigs/:fileId", gz(sc.checkAuth(restDeleteServiceConfigFile))},
+		rest.Route{"PUT", "/api/v2/serviceconfigs/:fileId", gz(sc.checkAuthAnywhere(userdomain.ActionEditService, restUpdateServiceConfigFile))},
+		rest.Route{"DELETE", "/api/v2/serviceconfigs/:fileId", gz(sc.checkAuthAnywhere(userdomain.ActionEditService, restDeleteServiceConfigFile))},
 	}
 
 	// Hardcoding these target URLs for now.
@@ -39,7 +39,7 @@ func RouteOriginalURL(r *http.Request) {
 }
 
 // ServeTCP sets up a tcp based server connection given a set of exports.
-func ServeTCP(cancel <-chan struct{}, listener net.Listener, tlsConfig *tls.Config, exports Exports) {
+func ServeTCP(cancel <-chan struct{}, listener net.Listener, tlsConfig *tls.Config, exports Exports, access *AccessFilter) {
 	stopChan := make(chan bool)
 	wg := &sync.WaitGroup{}
 
@@ -51,6 +51,13 @@ func ServeTCP(cancel <-chan struct{}, listener net.Listener, tlsConfig *tls.Conf
 				return
 			}
 
+			// drop connections from clients that are not allowed by the
+			// access policy
+			if access.Check(local.RemoteAddr().String()) != nil {
+				local.Close()
+				continue
+			}
+
 			if tlsConfig != nil {
 				local = tls.Server(local, tlsConfig)
 			}
@@ -99,7 +106,7 @@ func ServeTCP(cancel <-chan struct{}, listener net.Listener, tlsConfig *tls.Conf
 }
 
 // ServeHTTP sets up an http server for handling a collection of endpoints
-func ServeHTTP(cancel <-chan struct{}, address, protocol string, listener net.Listener, tlsConfig *tls.Config, exports Exports) {
+func ServeHTTP(cancel <-chan struct{}, address, protocol string, listener net.Listener, tlsConfig *tls.Config, exports Exports, access *AccessFilter) {
 	logger := plog.WithFields(log.Fields{
 		"portaddress": address,
 		"protocol":    protocol,
@@ -137,6 +144,11 @@ func ServeHTTP(cancel <-chan struct{}, address, protocol string, listener net.Li
 
 		logger.WithField("handlerrequest", r).Debug("Handler handling (port) request")
 
+		// reject clients that are not allowed by the access policy
+		if !access.AllowRequest(w, r) {
+			return
+		}
+
 		export := exports.Next()
 		if export == nil {
 			http.Error(w, "endpoint not available", http.StatusNotFound)
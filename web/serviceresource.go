@@ -82,6 +82,14 @@ func restGetServicesHealth(w *rest.ResponseWriter, r *rest.Request, ctx *request
 		return
 	}
 
+	if !ctx.viewsAll() {
+		for serviceID := range healthStatuses {
+			if !ctx.viewsService(serviceID) {
+				delete(healthStatuses, serviceID)
+			}
+		}
+	}
+
 	w.WriteJson(struct {
 		Timestamp int64
 		Statuses  map[string]map[int]map[string]health.HealthStatus
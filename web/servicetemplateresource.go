@@ -25,6 +25,7 @@ import (
 
 	"github.com/control-center/serviced/domain/addressassignment"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	userdomain "github.com/control-center/serviced/domain/user"
 )
 
 func restGetAppTemplates(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
@@ -88,6 +89,11 @@ func restDeployAppTemplate(w *rest.ResponseWriter, r *rest.Request, ctx *request
 		restBadRequest(w, err)
 		return
 	}
+	if err := ctx.authorize(userdomain.AccessRequest{Action: userdomain.ActionEditService, PoolID: payload.PoolID}); err != nil {
+		glog.V(1).Infof("Not authorized to deploy to pool %s", payload.PoolID)
+		restForbidden(w)
+		return
+	}
 	tenantIDs, err := ctx.getFacade().DeployTemplate(ctx.getDatastoreContext(), payload.PoolID, payload.TemplateID, payload.DeploymentID)
 	if err != nil {
 		glog.Error("Could not deploy template: ", err)
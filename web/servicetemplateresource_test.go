@@ -26,6 +26,7 @@ import (
 
 	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/domain/servicetemplate"
+	userdomain "github.com/control-center/serviced/domain/user"
 	"github.com/stretchr/testify/mock"
 	"github.com/zenoss/go-json-rest"
 	. "gopkg.in/check.v1"
@@ -203,6 +204,28 @@ func (s *TestWebSuite) TestRestDeployAppTemplateFails(c *C) {
 	s.assertServerError(c, expectedError)
 }
 
+func (s *TestWebSuite) TestRestDeployAppTemplateForbidden(c *C) {
+	payload := servicetemplate.ServiceTemplateDeploymentRequest{
+		PoolID:       "somePoolID",
+		TemplateID:   "someTemplateID",
+		DeploymentID: "someDeploymentID",
+	}
+	jsonPayload, err := json.Marshal(&payload)
+	if err != nil {
+		c.Fatalf("Failed to marshall JSON: %s", err)
+	}
+	request := s.buildRequest("POST", "/templates/deploy", string(jsonPayload))
+	s.ctx.roles = userdomain.RoleBindings{{Role: userdomain.RoleTenantAdmin, PoolID: "otherPoolID"}}
+	s.mockFacade.
+		On("GetAccessScope", mock.Anything, mock.AnythingOfType("user.AccessRequest")).
+		Return(userdomain.Scope{PoolID: payload.PoolID}, nil)
+
+	restDeployAppTemplate(&(s.writer), &request, s.ctx)
+
+	c.Assert(s.recorder.Code, Equals, http.StatusForbidden)
+	s.mockFacade.AssertNotCalled(c, "DeployTemplate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func (s *TestWebSuite) TestRestDeployAppTemplateFailsForBadJSON(c *C) {
 	request := s.buildRequest("POST", "/templates/deploy", "{this is not valid json}")
 
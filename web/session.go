@@ -15,7 +15,9 @@ package web
 
 import (
 	"github.com/control-center/serviced/auth"
+	"github.com/control-center/serviced/datastore"
 	userdomain "github.com/control-center/serviced/domain/user"
+	"github.com/control-center/serviced/facade"
 	"github.com/control-center/serviced/rpc/master"
 	"github.com/control-center/serviced/utils"
 	"github.com/zenoss/glog"
@@ -41,6 +43,7 @@ var adminGroup = "sudo"
 type sessionT struct {
 	ID       string
 	User     string
+	Roles    userdomain.RoleBindings
 	creation time.Time
 	access   time.Time
 }
@@ -182,6 +185,10 @@ func loginOK(w *rest.ResponseWriter, r *rest.Request) bool {
 		plog.WithError(tErr).WithField("url", r.URL.String()).Debug(msg)
 		return false
 	}
+	if isOIDCToken(token) {
+		_, ok := loginWithOIDCTokenOK(r, token)
+		return ok
+	}
 	if auth.Auth0IsConfigured() {
 		if auth0LoginOK(w, r, token) {
 			return true
@@ -290,40 +297,49 @@ func restLoginWithBasicAuth(w *rest.ResponseWriter, r *rest.Request, ctx *reques
 		return
 	}
 
-	if validateLogin(&creds, client) {
-		sessionsLock.Lock()
-		defer sessionsLock.Unlock()
-
-		session, err := createsessionT(creds.Username)
-		if err != nil {
+	if roles, ok := validateLogin(&creds, client, ctx.getFacade()); ok {
+		if err := startSession(w, creds.Username, roles); err != nil {
 			writeJSON(w, &simpleResponse{"sessionT could not be created", loginLink()}, http.StatusInternalServerError)
 			return
 		}
-		sessions[session.ID] = session
-
-		glog.V(1).Info("Created authenticated session: ", session.ID)
-		http.SetCookie(
-			w.ResponseWriter,
-			&http.Cookie{
-				Name:   sessionCookie,
-				Value:  session.ID,
-				Path:   "/",
-				MaxAge: 0,
-			})
-		http.SetCookie(
-			w.ResponseWriter,
-			&http.Cookie{
-				Name:   usernameCookie,
-				Value:  creds.Username,
-				Path:   "/",
-				MaxAge: 0,
-			})
 		w.WriteJson(&simpleResponse{"Accepted", homeLink()})
 	} else {
 		writeJSON(w, &simpleResponse{"Login failed", loginLink()}, http.StatusUnauthorized)
 	}
 }
 
+// startSession creates an authenticated session for the user and sets the
+// session cookies
+func startSession(w *rest.ResponseWriter, username string, roles userdomain.RoleBindings) error {
+	sessionsLock.Lock()
+	defer sessionsLock.Unlock()
+
+	session, err := createsessionT(username, roles)
+	if err != nil {
+		return err
+	}
+	sessions[session.ID] = session
+
+	glog.V(1).Info("Created authenticated session: ", session.ID)
+	http.SetCookie(
+		w.ResponseWriter,
+		&http.Cookie{
+			Name:   sessionCookie,
+			Value:  session.ID,
+			Path:   "/",
+			MaxAge: 0,
+		})
+	http.SetCookie(
+		w.ResponseWriter,
+		&http.Cookie{
+			Name:   usernameCookie,
+			Value:  username,
+			Path:   "/",
+			MaxAge: 0,
+		})
+	return nil
+}
+
 /*
  * Perform login, return JSON
  */
@@ -335,7 +351,12 @@ func restLogin(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 		plog.WithError(tErr).Warning(msg)
 		writeJSON(w, &simpleResponse{msg, loginLink()}, http.StatusUnauthorized)
 	} else if token != "" {
-		if _, ok := loginWithAuth0TokenOK(r, token); ok {
+		if isOIDCToken(token) {
+			if _, ok := loginWithOIDCTokenOK(r, token); ok {
+				w.WriteJson(&simpleResponse{"Accepted", homeLink()})
+				return
+			}
+		} else if _, ok := loginWithAuth0TokenOK(r, token); ok {
 			w.WriteJson(&simpleResponse{"Accepted", homeLink()})
 			return
 		} else if loginWithTokenOK(r, token) {
@@ -348,16 +369,23 @@ func restLogin(w *rest.ResponseWriter, r *rest.Request, ctx *requestContext) {
 	}
 }
 
-func validateLogin(creds *login, client master.ClientInterface) bool {
+// validateLogin checks the credentials against the users stored by control
+// center and then against PAM, returning the roles granted to the user.
+// Members of the admin group are cluster administrators.
+func validateLogin(creds *login, client master.ClientInterface, f facade.FacadeInterface) (userdomain.RoleBindings, bool) {
 	glog.V(1).Info("validateLogin()")
-	systemUser, err := client.GetSystemUser()
-	if err == nil && creds.Username == systemUser.Name {
-		validated := cpValidateLogin(creds, client)
-		if validated {
-			return validated
+	if cpValidateLogin(creds, client) {
+		roles, err := f.GetUserRoles(datastore.Get(), creds.Username)
+		if err != nil {
+			glog.Errorf("Unable to look up roles for user %s: %s", creds.Username, err)
+			return nil, false
 		}
+		return roles, true
 	}
-	return pamValidateLogin(creds, adminGroup)
+	if pamValidateLogin(creds, adminGroup) {
+		return userdomain.ClusterAdmin, true
+	}
+	return nil, false
 }
 
 func cpValidateLogin(creds *login, client master.ClientInterface) bool {
@@ -371,17 +399,18 @@ func cpValidateLogin(creds *login, client master.ClientInterface) bool {
 	var result bool
 	result, err := client.ValidateCredentials(user)
 	if err != nil {
-		glog.Errorf("Unable to validate credentials %s", err)
+		// users that are not stored by control center are validated by PAM
+		glog.V(1).Infof("Unable to validate credentials %s", err)
 	}
 	return result
 }
 
-func createsessionT(user string) (*sessionT, error) {
+func createsessionT(user string, roles userdomain.RoleBindings) (*sessionT, error) {
 	sid, err := randomsessionTId()
 	if err != nil {
 		return nil, err
 	}
-	return &sessionT{sid, user, time.Now(), time.Now()}, nil
+	return &sessionT{sid, user, roles, time.Now(), time.Now()}, nil
 }
 
 func findsessionT(sid string) (*sessionT, error) {
@@ -416,6 +445,15 @@ func randomStr() (string, error) {
 	return base64.StdEncoding.EncodeToString(sid), nil
 }
 
+// randomURLStr returns a random string that is safe to use in urls
+func randomURLStr() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
 func deleteSessionT(sid string) {
 	sessionsLock.Lock()
 	defer sessionsLock.Unlock()
@@ -199,6 +199,15 @@ func restGetConciseServiceStatus(w *rest.ResponseWriter, r *rest.Request, ctx *r
 			glog.V(2).Infof("Error retrieving service statuses: (%s)", err)
 			return nil, err
 		}
+		if !ctx.viewsAll() {
+			visible := []*ConciseServiceStatus{}
+			for _, stat := range statuses {
+				if ctx.viewsService(stat.ServiceID) {
+					visible = append(visible, stat)
+				}
+			}
+			statuses = visible
+		}
 		bytes, err := json.Marshal(statuses)
 		if err != nil {
 			glog.V(2).Infof("Error serializing service statuses: (%s)", err)
@@ -207,7 +216,14 @@ func restGetConciseServiceStatus(w *rest.ResponseWriter, r *rest.Request, ctx *r
 		return bytes, nil
 	}
 	w.Header().Set("content-type", "application/json")
-	bytes, err := getCached(f)
+	var bytes []byte
+	var err error
+	if ctx.viewsAll() {
+		bytes, err = getCached(f)
+	} else {
+		// the cache holds every status, so build a filtered list instead
+		bytes, err = f()
+	}
 	if err != nil {
 		glog.Errorf("Error retrieving service statuses: %s", err)
 		restServerError(w, err)
@@ -0,0 +1,57 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit
+
+package web
+
+import (
+	"errors"
+	"time"
+
+	"github.com/control-center/serviced/commons/statistics"
+	"github.com/control-center/serviced/config"
+	. "gopkg.in/check.v1"
+)
+
+func (s *TestWebSuite) TestGetStorageForecastsShouldSortByName(c *C) {
+	saved := config.GetOptions()
+	defer config.LoadOptions(saved)
+	opts := saved
+	opts.StorageLookaheadPeriod = 360
+	config.LoadOptions(opts)
+
+	s.mockFacade.
+		On("PredictStorageAvailability", s.ctx.getDatastoreContext(), 6*time.Minute).
+		Return(map[string]statistics.Forecast{
+			"thinpool-metadata": {Model: statistics.LeastSquaresModel, Value: 200, Lower: 150, Upper: 250},
+			"thinpool-data":     {Model: statistics.HoltWintersModel, Value: 2000, Lower: 1500, Upper: 2500},
+		}, nil)
+
+	forecasts := getStorageForecasts(s.ctx)
+
+	c.Assert(forecasts, HasLen, 2)
+	c.Assert(forecasts[0].Name, Equals, "thinpool-data")
+	c.Assert(forecasts[0].Lookahead, Equals, 360)
+	c.Assert(forecasts[0].Model, Equals, statistics.HoltWintersModel)
+	c.Assert(forecasts[0].Lower, Equals, 1500.0)
+	c.Assert(forecasts[1].Name, Equals, "thinpool-metadata")
+}
+
+func (s *TestWebSuite) TestGetStorageForecastsShouldIgnoreErrors(c *C) {
+	s.mockFacade.
+		On("PredictStorageAvailability", s.ctx.getDatastoreContext(), time.Duration(config.GetOptions().StorageLookaheadPeriod)*time.Second).
+		Return(nil, errors.New("metrics unavailable"))
+
+	c.Assert(getStorageForecasts(s.ctx), IsNil)
+}
@@ -21,6 +21,11 @@
                     <i class='healthIcon glyphicon'></i>
                     <div class='healthTooltipDetailName'>Authenticated</div>
                 </div>
+                ${m.getCertificateWarnings().map(w => `
+                <div class='healthTooltipDetailRow unknown'>
+                    <i class='healthIcon glyphicon'></i>
+                    <div class='healthTooltipDetailName'>${w}</div>
+                </div>`).join("")}
             `;
         };
 
@@ -55,6 +60,13 @@
                 return active ? "failed" : "not_running";
             }
 
+            // certificates of the pool's public endpoints that
+            // have expired or expire soon
+            getCertificateWarnings() {
+                let status = this.host && this.getHostStatus(this.host.id);
+                return (status && status.CertificateWarnings) || [];
+            }
+
             getHostActiveStatusClass() {
                 let { active } = this._getHostStatus();
                 return active ? "passed" : "not_running";
@@ -112,7 +124,8 @@
                 // single value to determine if we need to
                 // update the view
                 var ACTIVE = 1 << 1,
-                    AUTHED = 1 << 2;
+                    AUTHED = 1 << 2,
+                    CERTS = 1 << 3;
 
                 scope.$watch(function(){
                     let {active, authed} = scope.vm._getHostStatus(),
@@ -131,6 +144,9 @@
                     if(authed){
                         val = val ^ AUTHED;
                     }
+                    if(scope.vm.getCertificateWarnings().length){
+                        val = val ^ CERTS;
+                    }
 
                     return val;
                 }, function(newVal, oldVal){
@@ -16,6 +16,11 @@
             authService.auth0login();
         }
 
+        $scope.useOIDC = utils.useOIDC();
+        if ($location.search().sso_error) {
+            $notification.create("", $translate.instant("login_sso_fail"), $("#loginNotifications")).error();
+        }
+
         enableLoginButton();
 
         $scope.$emit("ready");
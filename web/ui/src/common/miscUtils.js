@@ -94,6 +94,10 @@
 
         var utils = {
 
+            useOIDC: function() {
+                return !!(window.OIDCConfig && window.OIDCConfig.Enabled);
+            },
+
             useAuth0: function() {
                 if (window.Auth0Config.Auth0Scope && window.Auth0Config.Auth0Audience && window.Auth0Config.Auth0Domain && window.Auth0Config.Auth0ClientID) {
                     return true;
@@ -64,6 +64,14 @@ func restUnauthorized(w *rest.ResponseWriter) {
 	return
 }
 
+/*
+ * Inform the client that the user's roles do not permit the request.
+ */
+func restForbidden(w *rest.ResponseWriter) {
+	writeJSON(w, &simpleResponse{"Access denied", homeLink()}, http.StatusForbidden)
+	return
+}
+
 /*
  * Provide a generic response for an oopsie.
  */
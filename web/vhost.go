@@ -18,6 +18,7 @@ import (
 	"sync"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/zzk/registry"
 	"strings"
 )
@@ -45,7 +46,7 @@ func (m *VHostManager) Enable(name string) {
 
 	h, ok := m.vhosts[name]
 	if !ok {
-		h = NewVHostHandler()
+		h = NewVHostHandler(name)
 		m.vhosts[name] = h
 	}
 	h.Enable()
@@ -71,11 +72,24 @@ func (m *VHostManager) Set(name string, data []registry.ExportDetails) {
 	if ok {
 		h.SetExports(data)
 	} else {
-		h = NewVHostHandler(data...)
+		h = NewVHostHandler(name, data...)
 		m.vhosts[name] = h
 	}
 }
 
+// SetAccess updates the access policy of the vhost
+func (m *VHostManager) SetAccess(name string, access *servicedefinition.AccessPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h, ok := m.vhosts[name]
+	if !ok {
+		h = NewVHostHandler(name)
+		m.vhosts[name] = h
+	}
+	h.SetAccess(access)
+}
+
 // Handle manages a vhost request and returns true if the vhost is enabled
 func (m *VHostManager) Handle(httphost string, w http.ResponseWriter, r *http.Request) bool {
 	m.mu.RLock()
@@ -99,14 +113,16 @@ func (m *VHostManager) handle(name string, w http.ResponseWriter, r *http.Reques
 // VHostHandler manages a vhost endpoint
 type VHostHandler struct {
 	exports Exports
+	access  *AccessFilter
 	mu      *sync.RWMutex
 	enabled bool
 }
 
 // NewVHostHandler instantiates a new vhost handler
-func NewVHostHandler(data ...registry.ExportDetails) *VHostHandler {
+func NewVHostHandler(name string, data ...registry.ExportDetails) *VHostHandler {
 	return &VHostHandler{
 		exports: NewRoundRobinExports(data), // default to round-robin
+		access:  NewAccessFilter(name),
 		mu:      &sync.RWMutex{},
 		enabled: false,
 	}
@@ -131,6 +147,11 @@ func (h *VHostHandler) SetExports(data []registry.ExportDetails) {
 	h.exports.Set(data)
 }
 
+// SetAccess updates the access policy for a vhost endpoint
+func (h *VHostHandler) SetAccess(access *servicedefinition.AccessPolicy) {
+	h.access.Set(access)
+}
+
 // Handle is the vhost handler, returns true if the vhost is enabled
 func (h *VHostHandler) Handle(useTLS bool, w http.ResponseWriter, r *http.Request) bool {
 	h.mu.RLock()
@@ -141,6 +162,11 @@ func (h *VHostHandler) Handle(useTLS bool, w http.ResponseWriter, r *http.Reques
 		return false
 	}
 
+	// reject clients that are not allowed by the access policy
+	if !h.access.AllowRequest(w, r) {
+		return true
+	}
+
 	// get the next available export
 	export := h.exports.Next()
 	if export == nil {
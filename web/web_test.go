@@ -26,6 +26,7 @@ import (
 
 	"github.com/control-center/serviced/datastore"
 	datastoreMocks "github.com/control-center/serviced/datastore/mocks"
+	userdomain "github.com/control-center/serviced/domain/user"
 	facadeMocks "github.com/control-center/serviced/facade/mocks"
 	"github.com/zenoss/go-json-rest"
 	. "gopkg.in/check.v1"
@@ -52,6 +53,7 @@ func (s *TestWebSuite) SetUpTest(c *C) {
 	s.mockFacade = &facadeMocks.FacadeInterface{}
 	config := ServiceConfig{facade: s.mockFacade}
 	s.ctx = newRequestContext(&config)
+	s.ctx.roles = userdomain.ClusterAdmin
 
 	s.recorder = httptest.NewRecorder()
 	s.writer = rest.NewResponseWriter(s.recorder, false)
@@ -11,30 +11,19 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// +build integration,!quick
+// +build integration,!quick unit
 
 package docker
 
 import (
 	"fmt"
 	"sync"
-	"testing"
 	"time"
 
 	"github.com/control-center/serviced/zzk"
 	. "gopkg.in/check.v1"
 )
 
-type ZZKTest struct {
-	zzk.ZZKTestSuite
-}
-
-var _ = Suite(&ZZKTest{})
-
-func Test(t *testing.T) {
-	TestingT(t)
-}
-
 type ActionResult struct {
 	Duration time.Duration
 	Result   []byte
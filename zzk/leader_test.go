@@ -11,7 +11,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// +build integration,!quick
+// +build integration,!quick unit
 
 package zzk_test
 
@@ -11,7 +11,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// +build integration,!quick
+// +build integration,!quick unit
 
 package zzk_test
 
@@ -19,12 +19,18 @@ import (
 	"path"
 	"time"
 
+	"github.com/control-center/serviced/coordinator/client"
 	. "github.com/control-center/serviced/zzk"
 	"github.com/control-center/serviced/zzk/mocks"
 	"github.com/stretchr/testify/mock"
 	. "gopkg.in/check.v1"
 )
 
+// isConnection matches a connection from any coordinator driver
+func isConnection(conn client.Connection) bool {
+	return conn != nil
+}
+
 func (t *ZZKTest) TestManage(c *C) {
 	shutdown := make(chan interface{})
 	pathname := "/managetest"
@@ -33,7 +39,7 @@ func (t *ZZKTest) TestManage(c *C) {
 
 	var shutdownRecv <-chan interface{} = shutdown
 	s := &mocks.Listener2{}
-	s.On("Listen", shutdownRecv, mock.AnythingOfType("*zookeeper.Connection")).Return().WaitUntil(exit).Twice()
+	s.On("Listen", shutdownRecv, mock.MatchedBy(isConnection)).Return().WaitUntil(exit).Twice()
 	s.On("Exited").Return().Once()
 
 	done := make(chan struct{})
@@ -77,7 +83,7 @@ func (t *ZZKTest) TestListen2(c *C) {
 	pathname := "/listentest"
 
 	s := &mocks.Spawner{}
-	s.On("SetConn", mock.AnythingOfType("*zookeeper.Connection")).Return().Once()
+	s.On("SetConn", conn).Return().Once()
 
 	s.On("Path").Return(pathname)
 
@@ -0,0 +1,63 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build unit,!integration
+
+package zzk
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/coordinator/client/memory"
+	. "gopkg.in/check.v1"
+)
+
+// NOTE: this constant can be adjusted to satisfy race conditions
+const ZKTestTimeout = 5 * time.Second
+
+// ZZKMemoryTestSuite sets up the local client on the in-memory coordinator
+// driver, so zzk tests can run without a zookeeper container.
+type ZZKMemoryTestSuite struct {
+	dsn string
+}
+
+func (t *ZZKMemoryTestSuite) SetUpSuite(c *C) {
+	t.dsn = fmt.Sprintf("zzk-%d", time.Now().UnixNano())
+	zclient, err := client.New("memory", t.dsn, "", nil)
+	if err != nil {
+		c.Fatalf("Could not create in-memory client: %s", err)
+	}
+	InitializeLocalClient(zclient)
+}
+
+func (t *ZZKMemoryTestSuite) TearDownSuite(c *C) {
+	ShutdownConnections()
+	memory.Reset(t.dsn)
+}
+
+func (t *ZZKMemoryTestSuite) SetUpTest(c *C) {
+	// delete the contents of the tree for every test
+	conn, err := GetLocalConnection("/")
+	if err != nil {
+		c.Fatalf("Could not get connection: %s", err)
+	}
+
+	children, err := conn.Children("/")
+	for _, child := range children {
+		if err := conn.Delete("/" + child); err != nil {
+			c.Logf("Could not delete %s: %s", child, err)
+		}
+	}
+}
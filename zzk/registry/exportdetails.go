@@ -29,6 +29,7 @@ type ExportDetails struct {
 	PrivateIP  string
 	HostIP     string
 	MuxPort    uint16
+	ServiceID  string
 	InstanceID int
 	version    interface{}
 }
@@ -1,5 +1,6 @@
 package mocks
 
+import "github.com/control-center/serviced/domain/servicedefinition"
 import "github.com/control-center/serviced/zzk/registry"
 import "github.com/stretchr/testify/mock"
 
@@ -16,3 +17,6 @@ func (_m *PublicPortHandler) Disable(port string) {
 func (_m *PublicPortHandler) Set(port string, exports []registry.ExportDetails) {
 	_m.Called(port, exports)
 }
+func (_m *PublicPortHandler) SetAccess(port string, access *servicedefinition.AccessPolicy) {
+	_m.Called(port, access)
+}
@@ -1,5 +1,6 @@
 package mocks
 
+import "github.com/control-center/serviced/domain/servicedefinition"
 import "github.com/control-center/serviced/zzk/registry"
 import "github.com/stretchr/testify/mock"
 
@@ -16,3 +17,6 @@ func (_m *VHostHandler) Disable(name string) {
 func (_m *VHostHandler) Set(name string, exports []registry.ExportDetails) {
 	_m.Called(name, exports)
 }
+func (_m *VHostHandler) SetAccess(name string, access *servicedefinition.AccessPolicy) {
+	_m.Called(name, access)
+}
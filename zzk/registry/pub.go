@@ -15,9 +15,11 @@ package registry
 
 import (
 	"path"
+	"reflect"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/servicedefinition"
 )
 
 // PublicPort describes a public endpoint
@@ -27,6 +29,7 @@ type PublicPort struct {
 	ServiceID   string // TODO: search by tenant and application
 	Protocol    string
 	UseTLS      bool
+	Access      *servicedefinition.AccessPolicy
 	version     interface{}
 }
 
@@ -45,6 +48,7 @@ type PublicPortHandler interface {
 	Enable(port string, protocol string, useTLS bool)
 	Disable(port string)
 	Set(port string, exports []ExportDetails)
+	SetAccess(port string, access *servicedefinition.AccessPolicy)
 }
 
 // PublicPortListener listens to ports for a provided ip
@@ -101,6 +105,7 @@ func (l *PublicPortListener) Spawn(shutdown <-chan interface{}, portAddr string)
 	exportMap := make(map[string]ExportDetails)
 
 	isEnabled := false
+	var access *servicedefinition.AccessPolicy
 	defer func() {
 		if isEnabled {
 			l.handler.Disable(portAddr)
@@ -190,6 +195,14 @@ func (l *PublicPortListener) Spawn(shutdown <-chan interface{}, portAddr string)
 			exLogger.Debug("Set new endpoints for export")
 		}
 
+		// the policy is always set before the port is enabled, so that a
+		// policy left over from a deleted port is not applied
+		if !isEnabled || !reflect.DeepEqual(access, dat.Access) {
+			l.handler.SetAccess(portAddr, dat.Access)
+			access = dat.Access
+			logger.Debug("Set access policy")
+		}
+
 		if !isEnabled {
 			l.handler.Enable(portAddr, dat.Protocol, dat.UseTLS)
 			logger.Debug("Enabled port")
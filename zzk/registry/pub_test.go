@@ -11,13 +11,14 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// +build integration,!quick
+// +build integration,!quick unit
 
 package registry_test
 
 import (
 	"time"
 
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/zzk"
 	. "github.com/control-center/serviced/zzk/registry"
 	"github.com/control-center/serviced/zzk/registry/mocks"
@@ -35,12 +36,15 @@ func (t *ZZKTest) TestPublicPortListener(c *C) {
 	listener := NewPublicPortListener("master", handler)
 	listener.SetConnection(conn)
 
+	access := &servicedefinition.AccessPolicy{Allow: []string{"10.0.0.0/8"}}
+	handler.On("SetAccess", "10.187.22.151:2181", access).Return().Once()
 	handler.On("Enable", "10.187.22.151:2181", "proto", true).Return().Once()
 	publicPort := &PublicPort{
 		TenantID:    "tenantid",
 		Application: "app",
 		Protocol:    "proto",
 		UseTLS:      true,
+		Access:      access,
 	}
 	err = conn.Create("/net/pub/master/10.187.22.151:2181", publicPort)
 	c.Assert(err, IsNil)
@@ -87,6 +91,23 @@ func (t *ZZKTest) TestPublicPortListener(c *C) {
 	case <-timer.C:
 	}
 
+	// access policy changed
+	newAccess := &servicedefinition.AccessPolicy{Deny: []string{"10.1.0.0/16"}, RateLimit: 60}
+	handler.On("SetAccess", "10.187.22.151:2181", newAccess).Return().Once()
+
+	err = conn.Get("/net/pub/master/10.187.22.151:2181", publicPort)
+	c.Assert(err, IsNil)
+	publicPort.Access = newAccess
+	err = conn.Set("/net/pub/master/10.187.22.151:2181", publicPort)
+	c.Assert(err, IsNil)
+
+	timer.Reset(time.Second)
+	select {
+	case <-done:
+		c.Fatalf("Listener exited unexpectedly")
+	case <-timer.C:
+	}
+
 	// shutdown
 	handler.On("Disable", "10.187.22.151:2181").Return().Once()
 
@@ -15,9 +15,11 @@ package registry
 
 import (
 	"path"
+	"reflect"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/control-center/serviced/coordinator/client"
+	"github.com/control-center/serviced/domain/servicedefinition"
 )
 
 // VHost describes a vhost endpoint
@@ -25,6 +27,7 @@ type VHost struct {
 	TenantID    string
 	ServiceID   string
 	Application string
+	Access      *servicedefinition.AccessPolicy
 	version     interface{}
 }
 
@@ -43,6 +46,7 @@ type VHostHandler interface {
 	Enable(name string)
 	Disable(name string)
 	Set(name string, exports []ExportDetails)
+	SetAccess(name string, access *servicedefinition.AccessPolicy)
 }
 
 // VHostListener listens for vhosts on a host
@@ -97,6 +101,7 @@ func (l *VHostListener) Spawn(shutdown <-chan interface{}, subdomain string) {
 
 	// keep track of the on/off state of the export
 	isEnabled := false
+	var access *servicedefinition.AccessPolicy
 	defer func() {
 		if isEnabled {
 			l.handler.Disable(subdomain)
@@ -188,6 +193,14 @@ func (l *VHostListener) Spawn(shutdown <-chan interface{}, subdomain string) {
 			l.handler.Set(subdomain, exports)
 		}
 
+		// the policy is always set before the vhost is enabled, so that a
+		// policy left over from a deleted vhost is not applied
+		if !isEnabled || !reflect.DeepEqual(access, dat.Access) {
+			l.handler.SetAccess(subdomain, dat.Access)
+			access = dat.Access
+			logger.Debug("Set access policy")
+		}
+
 		// do something if the state of the vhost has changed
 		if !isEnabled {
 			l.handler.Enable(subdomain)
@@ -11,13 +11,14 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// +build integration,!quick
+// +build integration,!quick unit
 
 package registry_test
 
 import (
 	"time"
 
+	"github.com/control-center/serviced/domain/servicedefinition"
 	"github.com/control-center/serviced/zzk"
 	. "github.com/control-center/serviced/zzk/registry"
 	"github.com/control-center/serviced/zzk/registry/mocks"
@@ -35,6 +36,7 @@ func (t *ZZKTest) TestVHostListener(c *C) {
 	listener := NewVHostListener("master", handler)
 	listener.SetConnection(conn)
 
+	handler.On("SetAccess", "myhost", (*servicedefinition.AccessPolicy)(nil)).Return().Once()
 	handler.On("Enable", "myhost").Return().Once()
 	vhost := &VHost{
 		TenantID:    "tenantid",
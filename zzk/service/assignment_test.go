@@ -11,7 +11,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// +build integration,!quick
+// +build integration,!quick unit
 
 package service_test
 
@@ -31,7 +31,7 @@ import (
 var _ = Suite(&ZKAssignmentHandlerTestSuite{})
 
 type ZKAssignmentHandlerTestSuite struct {
-	zzk.ZZKTestSuite
+	zzkTestSuite
 
 	// Dependencies
 	registeredHostHandler mocks.RegisteredHostHandler
@@ -45,7 +45,7 @@ type ZKAssignmentHandlerTestSuite struct {
 }
 
 func (s *ZKAssignmentHandlerTestSuite) SetUpTest(c *C) {
-	s.ZZKTestSuite.SetUpTest(c)
+	s.zzkTestSuite.SetUpTest(c)
 
 	s.testHost = h.Host{ID: "testHost", PoolID: "poolid"}
 
@@ -11,7 +11,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// +build integration,!quick
+// +build integration,!quick unit
 
 package service_test
 
@@ -35,14 +35,14 @@ const (
 var _ = Suite(&HostIPListenerSuite{})
 
 type HostIPListenerSuite struct {
-	zzk.ZZKTestSuite
+	zzkTestSuite
 	conn     client.Connection
 	listener *HostIPListener
 	handler  *mocks.HostIPHandler
 }
 
 func (t *HostIPListenerSuite) SetUpTest(c *C) {
-	t.ZZKTestSuite.SetUpTest(c)
+	t.zzkTestSuite.SetUpTest(c)
 
 	// initialize the zookeeper connection
 	conn, err := zzk.GetLocalConnection("/")
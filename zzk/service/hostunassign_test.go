@@ -11,7 +11,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// +build integration,!quick
+// +build integration,!quick unit
 
 package service_test
 
@@ -31,7 +31,7 @@ import (
 var _ = Suite(&ZKHostUnassignHandlerTestSuite{})
 
 type ZKHostUnassignHandlerTestSuite struct {
-	zzk.ZZKTestSuite
+	zzkTestSuite
 
 	// Dependencies
 	registeredHostHandler mocks.RegisteredHostHandler
@@ -42,7 +42,7 @@ type ZKHostUnassignHandlerTestSuite struct {
 }
 
 func (s *ZKHostUnassignHandlerTestSuite) SetUpTest(c *C) {
-	s.ZZKTestSuite.SetUpTest(c)
+	s.zzkTestSuite.SetUpTest(c)
 
 	s.testHost = h.Host{ID: "testHost", PoolID: "poolid"}
 
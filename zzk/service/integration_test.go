@@ -11,17 +11,16 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// +build integration,!quick
+// +build integration,!quick unit
 
 package service_test
 
 import (
-	"github.com/control-center/serviced/zzk"
 	. "gopkg.in/check.v1"
 )
 
 var _ = Suite(&ZZKTest{})
 
 type ZZKTest struct {
-	zzk.ZZKTestSuite
+	zzkTestSuite
 }
@@ -63,6 +63,12 @@ func (p *ZKPath) Locked() *ZKPath {
 	return p.concat("locked")
 }
 
+// Rebalance appends the node name for rebalance requests to the zookeeper
+// path.
+func (p *ZKPath) Rebalance() *ZKPath {
+	return p.concat("rebalance")
+}
+
 // ID appends the given id to the zookeeper path.  If the string is empty,
 // the method will add nothing to the path.  If this behavior is not desired,
 // then checks for a empty string should be done before this method is called.
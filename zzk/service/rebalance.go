@@ -0,0 +1,104 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"time"
+
+	"github.com/control-center/serviced/coordinator/client"
+)
+
+// RebalanceNode is the storage object for requests to rebalance a pool
+type RebalanceNode struct {
+	Requested time.Time // when a rebalance was last requested
+	Handled   time.Time // the request time of the last rebalance that ran
+	version   interface{}
+}
+
+// Version implements client.Node
+func (r *RebalanceNode) Version() interface{} {
+	return r.version
+}
+
+// SetVersion implements client.Node
+func (r *RebalanceNode) SetVersion(version interface{}) {
+	r.version = version
+}
+
+// Pending returns true if a rebalance was requested and has not run yet
+func (r *RebalanceNode) Pending() bool {
+	return r.Requested.After(r.Handled)
+}
+
+// RequestRebalance asks the leader of a pool to rebalance the pool
+func RequestRebalance(conn client.Connection, poolID string) error {
+	return updateRebalance(conn, poolID, func(node *RebalanceNode) {
+		node.Requested = time.Now().UTC()
+	})
+}
+
+// HandleRebalance records that the leader of a pool ran the rebalance that
+// was requested at a given time
+func HandleRebalance(conn client.Connection, poolID string, requested time.Time) error {
+	return updateRebalance(conn, poolID, func(node *RebalanceNode) {
+		node.Handled = requested
+	})
+}
+
+func updateRebalance(conn client.Connection, poolID string, mutate func(*RebalanceNode)) error {
+	pth := Base().Pools().ID(poolID).Rebalance().Path()
+	logger := plog.WithField("zkpath", pth)
+
+	node := &RebalanceNode{}
+	if err := conn.Get(pth, node); err == client.ErrNoNode {
+		mutate(node)
+		if err := conn.Create(pth, node); err != nil {
+			logger.WithError(err).Debug("Could not create rebalance node")
+			return err
+		}
+		return nil
+	} else if err != nil && err != client.ErrEmptyNode {
+		logger.WithError(err).Debug("Could not look up rebalance node")
+		return err
+	}
+	mutate(node)
+	if err := conn.Set(pth, node); err != nil {
+		logger.WithError(err).Debug("Could not update rebalance node")
+		return err
+	}
+	return nil
+}
+
+// WatchRebalance returns the rebalance requests of a pool and an event that
+// fires when they change.
+func WatchRebalance(conn client.Connection, poolID string, done <-chan struct{}) (*RebalanceNode, <-chan client.Event, error) {
+	pth := Base().Pools().ID(poolID).Rebalance().Path()
+	for {
+		ok, ev, err := conn.ExistsW(pth, done)
+		if err != nil {
+			return nil, nil, err
+		} else if !ok {
+			return &RebalanceNode{}, ev, nil
+		}
+		node := &RebalanceNode{}
+		ev, err = conn.GetW(pth, node, done)
+		if err == client.ErrNoNode {
+			// the node was deleted, so watch it again
+			continue
+		} else if err != nil && err != client.ErrEmptyNode {
+			return nil, nil, err
+		}
+		return node, ev, nil
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2019 The Serviced Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build integration,!quick
+
+package service_test
+
+import (
+	"github.com/control-center/serviced/zzk"
+	. "github.com/control-center/serviced/zzk/service"
+	. "gopkg.in/check.v1"
+)
+
+func (t *ZZKTest) TestRebalanceRequests(c *C) {
+	conn, err := zzk.GetLocalConnection("/")
+	c.Assert(err, IsNil)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	// nothing requested
+	node, ev, err := WatchRebalance(conn, "poolid", done)
+	c.Assert(err, IsNil)
+	c.Assert(node.Pending(), Equals, false)
+
+	// request a rebalance
+	err = RequestRebalance(conn, "poolid")
+	c.Assert(err, IsNil)
+	<-ev
+
+	node, ev, err = WatchRebalance(conn, "poolid", done)
+	c.Assert(err, IsNil)
+	c.Assert(node.Pending(), Equals, true)
+
+	// handle the request
+	err = HandleRebalance(conn, "poolid", node.Requested)
+	c.Assert(err, IsNil)
+	<-ev
+
+	node, _, err = WatchRebalance(conn, "poolid", done)
+	c.Assert(err, IsNil)
+	c.Assert(node.Pending(), Equals, false)
+}